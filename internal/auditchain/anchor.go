@@ -0,0 +1,85 @@
+package auditchain
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// AnchorsFile records chain heads in the town git repo.
+const AnchorsFile = "audit-anchors.jsonl"
+
+// Anchor is a chain head as it was at a point in time. Once committed, a
+// rewritten chain no longer matches its anchors.
+type Anchor struct {
+	Timestamp time.Time `json:"ts"`
+	Log       string    `json:"log"`
+	Seq       int64     `json:"seq"`
+	Hash      string    `json:"hash"`
+}
+
+// AnchorsPath returns the path of a town's anchors file.
+func AnchorsPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirMayor, AnchorsFile)
+}
+
+// ReadAnchors returns a town's anchors, oldest first.
+func ReadAnchors(townRoot string) ([]Anchor, error) {
+	f, err := os.Open(AnchorsPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var anchors []Anchor
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var a Anchor
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil || a.Log == "" {
+			continue
+		}
+		anchors = append(anchors, a)
+	}
+	return anchors, scanner.Err()
+}
+
+// LastAnchor returns the most recent anchor of a log.
+func LastAnchor(anchors []Anchor, log string) (Anchor, bool) {
+	for i := len(anchors) - 1; i >= 0; i-- {
+		if anchors[i].Log == log {
+			return anchors[i], true
+		}
+	}
+	return Anchor{}, false
+}
+
+// AppendAnchors adds anchors to a town's anchors file.
+func AppendAnchors(townRoot string, anchors []Anchor) error {
+	path := AnchorsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: anchors are committed to git
+	if err != nil {
+		return fmt.Errorf("opening anchors: %w", err)
+	}
+	defer f.Close()
+	for _, a := range anchors {
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("writing anchors: %w", err)
+		}
+	}
+	return nil
+}
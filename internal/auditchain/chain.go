@@ -0,0 +1,251 @@
+// Package auditchain makes Gas Town's append-only logs tamper-evident.
+//
+// Each line appended through events.Log or townlog.Logger carries a link:
+// its sequence number, a SHA-256 hash over the previous line's hash, the
+// sequence number and the line's content, and (when the town has an audit
+// key) an Ed25519 signature of that hash. Editing, dropping or reordering
+// lines breaks the chain, which Verify reports. Anchors of each chain's head,
+// committed to the town git repo, catch a chain rewritten from scratch.
+package auditchain
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// PublicKeyFile is the town's public audit key, committed with the town so
+// anyone can verify signatures. The private key never lives in the town,
+// where agents could read or replace it: it is kept in the user's config
+// directory (see KeyPath).
+const PublicKeyFile = "audit-key.pub"
+
+// Link is a line's place in its chain.
+type Link struct {
+	Seq  int64  `json:"seq"`
+	Hash string `json:"hash"`
+	Sig  string `json:"sig,omitempty"`
+}
+
+// Digest returns the hash linking body, as line seq, to the previous line's
+// hash (empty for the first line).
+func Digest(prev string, seq int64, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n", prev, seq)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Next returns the link for body following head (the zero Link starts a
+// chain). A nil key leaves the link unsigned.
+func Next(head Link, body []byte, key ed25519.PrivateKey) Link {
+	l := Link{Seq: head.Seq + 1}
+	l.Hash = Digest(head.Hash, l.Seq, body)
+	if key != nil {
+		l.Sig = base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(l.Hash)))
+	}
+	return l
+}
+
+// Format encodes links into the lines of a particular log.
+type Format interface {
+	// Seal returns the line to write for body with its link.
+	Seal(body []byte, l Link) []byte
+	// Open splits a written line into its body and link. ok is false for
+	// lines written without a link.
+	Open(line []byte) (body []byte, l Link, ok bool)
+}
+
+// Append writes body to the town's log at path as the next line of its
+// chain, creating the log with perm. A lock file in the town's runtime
+// directory serializes appends across processes.
+func Append(townRoot, path string, perm os.FileMode, f Format, body []byte, key ed25519.PrivateKey) error {
+	lockDir := filepath.Join(townRoot, constants.DirRuntime)
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	lock := flock.New(filepath.Join(lockDir, "audit-"+strings.TrimPrefix(filepath.Base(path), ".")+".lock"))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking %s: %w", filepath.Base(path), err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	// Hash exactly what Open will hand back to Verify
+	body = bytes.TrimSpace(body)
+	head, err := Head(path, f)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perm) //nolint:gosec // G302: caller chooses the log's permissions
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+
+	line := append(f.Seal(body, Next(head, body, key)), '\n')
+	if _, err := file.Write(line); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// headWindow is how much of the end of a log Head reads first.
+const headWindow = 64 * 1024
+
+// Head returns the link of the last chained line in the log at path, or the
+// zero Link if there is none (or no log).
+func Head(path string, f Format) (Link, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Link{}, nil
+		}
+		return Link{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return Link{}, err
+	}
+
+	// Usually the last line is chained, so the tail is enough
+	offset := max(0, info.Size()-headWindow)
+	for {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return Link{}, err
+		}
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return Link{}, err
+		}
+		lines := bytes.Split(data, []byte("\n"))
+		if offset > 0 {
+			lines = lines[1:] // Partial line
+		}
+		for i := len(lines) - 1; i >= 0; i-- {
+			if _, l, ok := f.Open(lines[i]); ok {
+				return l, nil
+			}
+		}
+		if offset == 0 {
+			return Link{}, nil
+		}
+		offset = 0
+	}
+}
+
+// KeyPath returns where the private key for a public audit key is kept:
+// <user config dir>/gastown/audit-keys/<fingerprint>, outside every town.
+func KeyPath(pub ed25519.PublicKey) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("finding user config dir: %w", err)
+	}
+	sum := sha256.Sum256(pub)
+	return filepath.Join(dir, "gastown", "audit-keys", hex.EncodeToString(sum[:8])), nil
+}
+
+// PublicKeyPath returns the path of a town's public audit key.
+func PublicKeyPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirMayor, PublicKeyFile)
+}
+
+// LoadKey returns the private key for a town's public audit key, or nil if
+// the town has no key or this user doesn't hold its private half.
+func LoadKey(townRoot string) (ed25519.PrivateKey, error) {
+	pub, err := LoadPublicKey(townRoot)
+	if err != nil || pub == nil {
+		return nil, err
+	}
+	path, err := KeyPath(pub)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid audit key %s", path)
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+	if !pub.Equal(key.Public()) {
+		return nil, fmt.Errorf("audit key %s doesn't match %s", path, PublicKeyPath(townRoot))
+	}
+	return key, nil
+}
+
+// LoadPublicKey returns a town's public audit key, or nil if it has none.
+func LoadPublicKey(townRoot string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(PublicKeyPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	pub, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid audit public key %s", PublicKeyPath(townRoot))
+	}
+	return ed25519.PublicKey(pub), nil
+}
+
+// ErrKeyExists is returned by GenerateKey when the town already has a key.
+var ErrKeyExists = errors.New("town already has an audit key")
+
+// GenerateKey creates a town's audit key pair. The private key is written to
+// KeyPath, readable only by its owner; the public key to PublicKeyPath.
+func GenerateKey(townRoot string) (ed25519.PublicKey, error) {
+	if _, err := os.Stat(PublicKeyPath(townRoot)); err == nil {
+		return nil, ErrKeyExists
+	}
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	path, err := KeyPath(pub)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(priv.Seed())+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(PublicKeyPath(townRoot)), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(PublicKeyPath(townRoot), []byte(base64.StdEncoding.EncodeToString(pub)+"\n"), 0644); err != nil { //nolint:gosec // G306: public key
+		return nil, err
+	}
+	return pub, nil
+}
+
+// verifySig reports whether sig is key's signature of hash.
+func verifySig(key ed25519.PublicKey, hash, sig string) bool {
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, []byte(hash), raw)
+}
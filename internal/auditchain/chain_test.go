@@ -0,0 +1,223 @@
+package auditchain
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeLog appends bodies to a fresh log and returns its path.
+func writeLog(t *testing.T, f Format, bodies []string, townRoot string) string {
+	t.Helper()
+	key, err := LoadKey(townRoot)
+	if err != nil {
+		t.Fatalf("LoadKey: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "log")
+	for _, b := range bodies {
+		if err := Append(townRoot, path, 0644, f, []byte(b), key); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	return path
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+func verifyLines(lines []string, f Format) *Report {
+	return Verify("test", strings.NewReader(strings.Join(lines, "\n")+"\n"), f, nil)
+}
+
+func problemKinds(rep *Report) string {
+	var kinds []string
+	for _, p := range rep.Problems {
+		kinds = append(kinds, p.Kind)
+	}
+	return strings.Join(kinds, ",")
+}
+
+var jsonBodies = []string{
+	`{"ts":"2026-10-18T10:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-1","seq":7}}`,
+	`{"ts":"2026-10-18T10:01:00Z","type":"done","actor":"gastown/polecats/toast"}`,
+	`{}`,
+	`{"ts":"2026-10-18T10:02:00Z","type":"nudge","actor":"deacon"}`,
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	path := writeLog(t, JSON, jsonBodies, t.TempDir())
+	lines := readLines(t, path)
+	for i, line := range lines {
+		body, l, ok := JSON.Open([]byte(line))
+		if !ok || string(body) != jsonBodies[i] || l.Seq != int64(i+1) {
+			t.Errorf("line %d opened as %q seq %d (ok=%v), want %q seq %d", i+1, body, l.Seq, ok, jsonBodies[i], i+1)
+		}
+	}
+	if head, _ := Head(path, JSON); head.Seq != 4 {
+		t.Errorf("Head seq = %d, want 4", head.Seq)
+	}
+	if rep := verifyLines(lines, JSON); !rep.OK() || rep.Entries != 4 {
+		t.Errorf("clean log: entries %d, problems %+v", rep.Entries, rep.Problems)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	lines := readLines(t, writeLog(t, JSON, jsonBodies, t.TempDir()))
+
+	tests := []struct {
+		name   string
+		tamper func([]string) []string
+		want   string
+	}{
+		{"edit", func(l []string) []string {
+			l[1] = strings.Replace(l[1], "toast", "nux", 1)
+			return l
+		}, ProblemEdited},
+		{"delete", func(l []string) []string {
+			return append(l[:1], l[2:]...)
+		}, ProblemGap},
+		{"reorder", func(l []string) []string {
+			l[1], l[2] = l[2], l[1]
+			return l
+		}, ProblemGap + "," + ProblemReordered},
+		{"insert", func(l []string) []string {
+			return append(l[:2], append([]string{`{"type":"forged"}`}, l[2:]...)...)
+		}, ProblemUnchained},
+	}
+	for _, tt := range tests {
+		tampered := tt.tamper(append([]string(nil), lines...))
+		if got := problemKinds(verifyLines(tampered, JSON)); got != tt.want {
+			t.Errorf("%s: problems = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyLegacyLines(t *testing.T) {
+	lines := readLines(t, writeLog(t, JSON, jsonBodies[:2], t.TempDir()))
+	legacy := []string{`{"type":"old"}`, `{"type":"older"}`}
+	rep := verifyLines(append(legacy, lines...), JSON)
+	if !rep.OK() || rep.Legacy != 2 || rep.Entries != 2 {
+		t.Errorf("legacy prefix: legacy %d, entries %d, problems %+v", rep.Legacy, rep.Entries, rep.Problems)
+	}
+}
+
+func TestTextFormat(t *testing.T) {
+	bodies := []string{
+		"2026-10-18 10:00:00 [spawn] gastown/polecats/toast spawned for gt-1",
+		"2026-10-18 10:05:00 [done] gastown/polecats/toast completed gt-1",
+	}
+	lines := readLines(t, writeLog(t, Text, bodies, t.TempDir()))
+	if !strings.HasPrefix(lines[0], bodies[0]+" #chain seq=1 hash=") {
+		t.Errorf("text line = %q", lines[0])
+	}
+	if got := StripText(lines[1]); got != bodies[1] {
+		t.Errorf("StripText = %q, want %q", got, bodies[1])
+	}
+	if rep := verifyLines(lines, Text); !rep.OK() {
+		t.Errorf("clean text log: %+v", rep.Problems)
+	}
+	lines[0] = strings.Replace(lines[0], "gt-1", "gt-2", 1)
+	if got := problemKinds(verifyLines(lines, Text)); got != ProblemEdited {
+		t.Errorf("edited text log: problems = %q", got)
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	townRoot := t.TempDir()
+	pub, err := GenerateKey(townRoot)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	// The private key is kept outside the town, readable only by its owner
+	keyPath, err := KeyPath(pub)
+	if err != nil {
+		t.Fatalf("KeyPath: %v", err)
+	}
+	if !strings.HasPrefix(keyPath, configDir) {
+		t.Errorf("private key at %s, want under the user config dir %s", keyPath, configDir)
+	}
+	if info, err := os.Stat(keyPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("private key mode = %v, %v; want 0600", info, err)
+	}
+	if key, err := LoadKey(townRoot); err != nil || !pub.Equal(key.Public()) {
+		t.Errorf("LoadKey = %v; want the generated key", err)
+	}
+	if _, err := GenerateKey(townRoot); err != ErrKeyExists {
+		t.Errorf("second GenerateKey = %v, want ErrKeyExists", err)
+	}
+	if loaded, _ := LoadPublicKey(townRoot); !bytes.Equal(loaded, pub) {
+		t.Error("LoadPublicKey doesn't match the generated key")
+	}
+
+	lines := readLines(t, writeLog(t, JSON, jsonBodies[:2], townRoot))
+	rep := Verify("test", strings.NewReader(strings.Join(lines, "\n")), JSON, pub)
+	if !rep.OK() || rep.Signed != 2 {
+		t.Errorf("signed log: signed %d, problems %+v", rep.Signed, rep.Problems)
+	}
+
+	// A line chained without the key links up but isn't signed
+	forged := readLines(t, writeLog(t, JSON, jsonBodies[:3], t.TempDir()))
+	rep = Verify("test", strings.NewReader(strings.Join(append(lines, forged[2]), "\n")), JSON, pub)
+	if got := problemKinds(rep); got != ProblemUnsigned {
+		t.Errorf("unsigned append: problems = %q", got)
+	}
+
+	other := t.TempDir()
+	otherPub, _ := GenerateKey(other)
+	rep = Verify("test", strings.NewReader(strings.Join(lines, "\n")), JSON, otherPub)
+	if got := problemKinds(rep); got != ProblemBadSig+","+ProblemBadSig {
+		t.Errorf("wrong key: problems = %q", got)
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	townRoot := t.TempDir()
+	lines := readLines(t, writeLog(t, JSON, jsonBodies, t.TempDir()))
+	_, head, _ := JSON.Open([]byte(lines[2]))
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	if err := AppendAnchors(townRoot, []Anchor{
+		{Timestamp: now, Log: "test", Seq: head.Seq, Hash: head.Hash},
+		{Timestamp: now, Log: "other", Seq: 99, Hash: "x"},
+	}); err != nil {
+		t.Fatalf("AppendAnchors: %v", err)
+	}
+	anchors, err := ReadAnchors(townRoot)
+	if err != nil || len(anchors) != 2 {
+		t.Fatalf("ReadAnchors = %+v, %v", anchors, err)
+	}
+	if last, ok := LastAnchor(anchors, "test"); !ok || last.Seq != 3 {
+		t.Errorf("LastAnchor = %+v, %v", last, ok)
+	}
+
+	rep := verifyLines(lines, JSON)
+	rep.CheckAnchors(anchors)
+	if !rep.OK() || rep.Anchors != 1 {
+		t.Errorf("anchored log: anchors %d, problems %+v", rep.Anchors, rep.Problems)
+	}
+
+	// Truncating past the anchor, or rewriting the whole chain, is caught
+	rep = verifyLines(lines[:2], JSON)
+	rep.CheckAnchors(anchors)
+	if got := problemKinds(rep); got != ProblemTruncated {
+		t.Errorf("truncated log: problems = %q", got)
+	}
+	rewritten := append([]string{jsonBodies[1], jsonBodies[0]}, jsonBodies[2:]...)
+	rep = verifyLines(readLines(t, writeLog(t, JSON, rewritten, t.TempDir())), JSON)
+	rep.CheckAnchors(anchors)
+	if got := problemKinds(rep); got != ProblemRewritten {
+		t.Errorf("rewritten log: problems = %q", got)
+	}
+}
@@ -0,0 +1,107 @@
+package auditchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// JSON chains JSONL logs such as .events.jsonl. The link is appended as
+// trailing "seq", "hash" and "sig" fields of each object; the body is the
+// object without them.
+var JSON Format = jsonFormat{}
+
+type jsonFormat struct{}
+
+func (jsonFormat) Seal(body []byte, l Link) []byte {
+	body = bytes.TrimSpace(body)
+	if len(body) < 2 || body[len(body)-1] != '}' {
+		return body
+	}
+	suffix, _ := json.Marshal(l)
+	out := make([]byte, 0, len(body)+len(suffix))
+	out = append(out, body[:len(body)-1]...)
+	if len(body) > 2 {
+		out = append(out, ',')
+	}
+	out = append(out, suffix[1:]...) // suffix without its opening brace
+	return out
+}
+
+// jsonLinkMarker starts the link fields of a chained JSON line. They are
+// always last, so the last occurrence is the link even if the payload has
+// a "seq" field of its own.
+var jsonLinkMarker = []byte(`"seq":`)
+
+func (jsonFormat) Open(line []byte) ([]byte, Link, bool) {
+	line = bytes.TrimSpace(line)
+	i := bytes.LastIndex(line, jsonLinkMarker)
+	if i < 1 || line[len(line)-1] != '}' {
+		return nil, Link{}, false
+	}
+	var l Link
+	if err := json.Unmarshal(append([]byte("{"), line[i:]...), &l); err != nil || l.Hash == "" {
+		return nil, Link{}, false
+	}
+	// Drop the separating comma (absent when the body was {})
+	end := i
+	if line[end-1] == ',' {
+		end--
+	}
+	body := make([]byte, 0, end+1)
+	body = append(body, line[:end]...)
+	body = append(body, '}')
+	return body, l, true
+}
+
+// Text chains human-readable logs such as town.log. The link is appended
+// to each line as " #chain seq=N hash=H sig=S".
+var Text Format = textFormat{}
+
+type textFormat struct{}
+
+const textLinkMarker = " #chain "
+
+func (textFormat) Seal(body []byte, l Link) []byte {
+	var sb strings.Builder
+	sb.Write(bytes.TrimRight(body, "\r\n"))
+	sb.WriteString(textLinkMarker)
+	sb.WriteString("seq=" + strconv.FormatInt(l.Seq, 10) + " hash=" + l.Hash)
+	if l.Sig != "" {
+		sb.WriteString(" sig=" + l.Sig)
+	}
+	return []byte(sb.String())
+}
+
+func (textFormat) Open(line []byte) ([]byte, Link, bool) {
+	line = bytes.TrimRight(line, "\r\n")
+	i := bytes.LastIndex(line, []byte(textLinkMarker))
+	if i < 0 {
+		return nil, Link{}, false
+	}
+	var l Link
+	for _, field := range strings.Fields(string(line[i+len(textLinkMarker):])) {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "seq":
+			l.Seq, _ = strconv.ParseInt(value, 10, 64)
+		case "hash":
+			l.Hash = value
+		case "sig":
+			l.Sig = value
+		}
+	}
+	if l.Seq == 0 || l.Hash == "" {
+		return nil, Link{}, false
+	}
+	return line[:i], l, true
+}
+
+// StripText removes the link from a text log line, if it has one.
+func StripText(line string) string {
+	if i := strings.LastIndex(line, textLinkMarker); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
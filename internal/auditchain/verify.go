@@ -0,0 +1,143 @@
+package auditchain
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+)
+
+// Problem kinds reported by Verify.
+const (
+	ProblemEdited     = "edited"     // line content doesn't match its hash
+	ProblemGap        = "gap"        // lines are missing before this one
+	ProblemReordered  = "reordered"  // line is out of sequence
+	ProblemUnchained  = "unchained"  // line without a link after the chain started
+	ProblemBadSig     = "bad-sig"    // signature doesn't verify
+	ProblemUnsigned   = "unsigned"   // unsigned line after signing started
+	ProblemTruncated  = "truncated"  // an anchored line is gone
+	ProblemRewritten  = "rewritten"  // an anchored line has a different hash
+	ProblemUnreadable = "unreadable" // the log couldn't be read
+)
+
+// Problem is a break in a chain.
+type Problem struct {
+	Line   int    `json:"line,omitempty"` // 1-based line number in the log
+	Seq    int64  `json:"seq,omitempty"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// Report is the result of verifying one log.
+type Report struct {
+	Log      string    `json:"log"`
+	Entries  int       `json:"entries"` // chained lines
+	Legacy   int       `json:"legacy"`  // unchained lines before the chain started
+	Signed   int       `json:"signed"`
+	Head     Link      `json:"head"`
+	Anchors  int       `json:"anchors"` // anchors checked
+	Problems []Problem `json:"problems,omitempty"`
+
+	hashes map[int64]string
+}
+
+// OK reports whether the log verified cleanly.
+func (r *Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *Report) add(line int, seq int64, kind, format string, args ...interface{}) {
+	r.Problems = append(r.Problems, Problem{Line: line, Seq: seq, Kind: kind, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Verify walks a log's chain. Lines before the first chained line are
+// counted as legacy. A nil key skips signature checks.
+//
+// After a break, verification resumes from the line's recorded link, so each
+// tampered line is reported once rather than poisoning the rest of the log.
+func Verify(name string, r io.Reader, f Format, key ed25519.PublicKey) *Report {
+	rep := &Report{Log: name, hashes: make(map[int64]string)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var prev Link
+	started, signing := false, false
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		raw := scanner.Bytes()
+		if len(raw) == 0 {
+			continue
+		}
+		body, l, ok := f.Open(raw)
+		if !ok {
+			if started {
+				rep.add(lineNo, 0, ProblemUnchained, "line has no chain link")
+			} else {
+				rep.Legacy++
+			}
+			continue
+		}
+		rep.Entries++
+		rep.hashes[l.Seq] = l.Hash
+
+		if started && l.Seq <= prev.Seq {
+			// Keep verifying from the latest line so the lines after a moved
+			// one still link up
+			rep.add(lineNo, l.Seq, ProblemReordered, "seq %d follows seq %d", l.Seq, prev.Seq)
+			continue
+		}
+		switch {
+		case !started && l.Seq != 1:
+			// The chain's first line is gone (or legacy lines were trimmed
+			// along with it); take its link on trust
+			rep.add(lineNo, l.Seq, ProblemGap, "chain starts at seq %d, not 1", l.Seq)
+		case started && l.Seq > prev.Seq+1:
+			rep.add(lineNo, l.Seq, ProblemGap, "%d line(s) missing after seq %d", l.Seq-prev.Seq-1, prev.Seq)
+		default:
+			if got := Digest(prev.Hash, l.Seq, body); got != l.Hash {
+				rep.add(lineNo, l.Seq, ProblemEdited, "hash mismatch (content changed since it was logged)")
+			}
+		}
+
+		if l.Sig != "" {
+			rep.Signed++
+			signing = true
+			if key != nil && !verifySig(key, l.Hash, l.Sig) {
+				rep.add(lineNo, l.Seq, ProblemBadSig, "signature does not match the town key")
+			}
+		} else if signing && key != nil {
+			rep.add(lineNo, l.Seq, ProblemUnsigned, "unsigned line after signing started")
+		}
+
+		started = true
+		prev = l
+	}
+	if err := scanner.Err(); err != nil {
+		rep.add(lineNo, 0, ProblemUnreadable, "%v", err)
+	}
+	rep.Head = prev
+	return rep
+}
+
+// CheckAnchors compares a verified log with the anchors recorded for it.
+// Anchors for other logs are ignored.
+func (r *Report) CheckAnchors(anchors []Anchor) {
+	for _, a := range anchors {
+		if a.Log != r.Log {
+			continue
+		}
+		r.Anchors++
+		hash, ok := r.hashes[a.Seq]
+		switch {
+		case !ok && a.Seq > r.Head.Seq:
+			r.add(0, a.Seq, ProblemTruncated, "anchored at seq %d on %s, log now ends at seq %d",
+				a.Seq, a.Timestamp.Format("2006-01-02 15:04"), r.Head.Seq)
+		case !ok:
+			r.add(0, a.Seq, ProblemTruncated, "anchored seq %d (%s) is missing", a.Seq, a.Timestamp.Format("2006-01-02 15:04"))
+		case hash != a.Hash:
+			r.add(0, a.Seq, ProblemRewritten, "seq %d no longer matches its anchor from %s",
+				a.Seq, a.Timestamp.Format("2006-01-02 15:04"))
+		}
+	}
+}
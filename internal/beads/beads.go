@@ -47,6 +47,9 @@ type Issue struct {
 	RoleBead   string `json:"role_bead,omitempty"`   // Role definition bead (shared)
 	AgentState string `json:"agent_state,omitempty"` // Agent lifecycle state (spawning, working, done, stuck)
 
+	Pinned bool `json:"pinned,omitempty"`
+	Wisp   bool `json:"wisp,omitempty"` // Ephemeral: stored in the database, filtered from JSONL export
+
 	// Counts from list output
 	DependencyCount int `json:"dependency_count,omitempty"`
 	DependentCount  int `json:"dependent_count,omitempty"`
@@ -72,6 +75,7 @@ type ListOptions struct {
 	Status     string // "open", "closed", "all"
 	Type       string // Deprecated: use Label instead. "task", "bug", "feature", "epic"
 	Label      string // Label filter (e.g., "gt:agent", "gt:merge-request")
+	IssueType  string // Raw issue_type filter (e.g., "message", "convoy")
 	Priority   int    // 0-4, -1 for no filter
 	Parent     string // filter by parent ID
 	Assignee   string // filter by assignee (e.g., "gastown/Toast")
@@ -126,6 +130,16 @@ func NewWithBeadsDir(workDir, beadsDir string) *Beads {
 	return &Beads{workDir: workDir, beadsDir: beadsDir}
 }
 
+// native returns the in-process snapshot of this wrapper's database, if
+// native reads are available. See Native.
+func (b *Beads) native() (*Snapshot, bool) {
+	beadsDir := b.beadsDir
+	if beadsDir == "" {
+		beadsDir = ResolveBeadsDir(b.workDir)
+	}
+	return Native(beadsDir)
+}
+
 // run executes a bd command and returns stdout.
 func (b *Beads) run(args ...string) ([]byte, error) {
 	// Use --no-daemon for faster read operations (avoids daemon IPC overhead)
@@ -198,6 +212,10 @@ func (b *Beads) wrapError(err error, stderr string, args []string) error {
 
 // List returns issues matching the given options.
 func (b *Beads) List(opts ListOptions) ([]*Issue, error) {
+	if snap, ok := b.native(); ok {
+		return snap.List(opts), nil
+	}
+
 	args := []string{"list", "--json"}
 
 	if opts.Status != "" {
@@ -210,6 +228,9 @@ func (b *Beads) List(opts ListOptions) ([]*Issue, error) {
 		// Deprecated: convert type to label for backward compatibility
 		args = append(args, "--label=gt:"+opts.Type)
 	}
+	if opts.IssueType != "" {
+		args = append(args, "--type="+opts.IssueType)
+	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
 	}
@@ -311,6 +332,16 @@ func (b *Beads) ReadyWithType(issueType string) ([]*Issue, error) {
 
 // Show returns detailed information about an issue.
 func (b *Beads) Show(id string) (*Issue, error) {
+	if snap, ok := b.native(); ok {
+		if issue, ok := snap.Get(id); ok {
+			return issue, nil
+		}
+		if snap.Owns(id) {
+			return nil, ErrNotFound
+		}
+		// Possibly another rig's bead: let bd route it.
+	}
+
 	out, err := b.run("show", id, "--json")
 	if err != nil {
 		return nil, err
@@ -336,12 +367,28 @@ func (b *Beads) ShowMultiple(ids []string) (map[string]*Issue, error) {
 		return make(map[string]*Issue), nil
 	}
 
+	result := make(map[string]*Issue, len(ids))
+	if snap, ok := b.native(); ok {
+		var remote []string
+		for _, id := range ids {
+			if issue, ok := snap.Get(id); ok {
+				result[id] = issue
+			} else if !snap.Owns(id) {
+				remote = append(remote, id)
+			}
+		}
+		if len(remote) == 0 {
+			return result, nil
+		}
+		ids = remote
+	}
+
 	// bd show supports multiple IDs
 	args := append([]string{"show", "--json"}, ids...)
 	out, err := b.run(args...)
 	if err != nil {
-		// If bd fails, return empty map (some IDs might not exist)
-		return make(map[string]*Issue), nil
+		// If bd fails, return what we have (some IDs might not exist)
+		return result, nil
 	}
 
 	var issues []*Issue
@@ -349,7 +396,6 @@ func (b *Beads) ShowMultiple(ids []string) (map[string]*Issue, error) {
 		return nil, fmt.Errorf("parsing bd show output: %w", err)
 	}
 
-	result := make(map[string]*Issue, len(issues))
 	for _, issue := range issues {
 		result[issue.ID] = issue
 	}
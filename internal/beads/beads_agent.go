@@ -495,6 +495,15 @@ func (b *Beads) GetAgentBead(id string) (*Issue, *AgentFields, error) {
 // ListAgentBeads returns all agent beads in a single query.
 // Returns a map of agent bead ID to Issue.
 func (b *Beads) ListAgentBeads() (map[string]*Issue, error) {
+	if snap, ok := b.native(); ok {
+		issues := snap.List(ListOptions{Label: "gt:agent", Priority: -1})
+		result := make(map[string]*Issue, len(issues))
+		for _, issue := range issues {
+			result[issue.ID] = issue
+		}
+		return result, nil
+	}
+
 	out, err := b.run("list", "--label=gt:agent", "--json")
 	if err != nil {
 		return nil, err
@@ -0,0 +1,270 @@
+// Package beads provides change set bead management for cross-rig merges.
+package beads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LabelChangeset marks a change set bead.
+const LabelChangeset = "gt:changeset"
+
+// LabelChangesetHeld marks an MR whose tests passed and that is waiting for
+// the rest of its change set. The refinery skips held MRs.
+const LabelChangesetHeld = "changeset:held"
+
+// Change set states.
+const (
+	ChangesetOpen   = "open"   // Members still being tested
+	ChangesetReady  = "ready"  // Every member passed; waiting to land
+	ChangesetLanded = "landed" // Every member merged
+	ChangesetFailed = "failed" // Landing failed and was rolled back; retryable
+)
+
+// Change set member states.
+const (
+	MemberPending    = "pending"     // Waiting for its rig's refinery
+	MemberPassed     = "passed"      // Tests passed; held for the coordinated merge
+	MemberFailed     = "failed"      // Tests, merge or push failed
+	MemberMerged     = "merged"      // Merged and pushed
+	MemberRolledBack = "rolled_back" // Merged, then undone because another member failed; still held
+)
+
+// ChangesetMember is one MR in a change set.
+type ChangesetMember struct {
+	MR          string `json:"mr"`
+	Rig         string `json:"rig"`
+	Branch      string `json:"branch,omitempty"`
+	Target      string `json:"target,omitempty"`
+	State       string `json:"state"`
+	Tested      string `json:"tested,omitempty"`       // Branch SHA the tests passed on
+	MergeCommit string `json:"merge_commit,omitempty"` // Set once merged
+	Reverted    string `json:"reverted,omitempty"`     // Commit that rolled back a pushed merge
+	Error       string `json:"error,omitempty"`
+}
+
+// ChangesetFields holds structured fields for change set beads.
+// These are stored as "key: value" lines in the description, with one
+// "member:" line per MR.
+type ChangesetFields struct {
+	State     string
+	CreatedBy string
+	LandedAt  string
+	Reason    string // Why the change set failed
+	Members   []ChangesetMember
+}
+
+// Member returns the member for an MR, or nil.
+func (f *ChangesetFields) Member(mrID string) *ChangesetMember {
+	for i := range f.Members {
+		if f.Members[i].MR == mrID {
+			return &f.Members[i]
+		}
+	}
+	return nil
+}
+
+// AllPassed reports whether every member's tests have passed, so the
+// change set can land. Rolled-back members passed before and are still held.
+func (f *ChangesetFields) AllPassed() bool {
+	if len(f.Members) == 0 {
+		return false
+	}
+	for _, m := range f.Members {
+		if m.State != MemberPassed && m.State != MemberRolledBack {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatChangesetDescription creates a description string from change set fields.
+func FormatChangesetDescription(title string, fields *ChangesetFields) string {
+	if fields == nil {
+		return title
+	}
+
+	lines := []string{title, ""}
+	lines = append(lines, "state: "+fields.State)
+	lines = append(lines, "created_by: "+orNull(fields.CreatedBy))
+	lines = append(lines, "landed_at: "+orNull(fields.LandedAt))
+	lines = append(lines, "reason: "+orNull(fields.Reason))
+	for _, m := range fields.Members {
+		lines = append(lines, "member: "+formatMember(m))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func orNull(s string) string {
+	if s == "" {
+		return "null"
+	}
+	return s
+}
+
+// formatMember renders a member as space-separated key=value pairs. The
+// error goes last because it may contain spaces.
+func formatMember(m ChangesetMember) string {
+	parts := []string{m.MR, "rig=" + m.Rig}
+	for _, kv := range [][2]string{
+		{"branch", m.Branch},
+		{"target", m.Target},
+		{"state", m.State},
+		{"tested", m.Tested},
+		{"merged", m.MergeCommit},
+		{"reverted", m.Reverted},
+	} {
+		if kv[1] != "" {
+			parts = append(parts, kv[0]+"="+kv[1])
+		}
+	}
+	if m.Error != "" {
+		parts = append(parts, "error="+strings.ReplaceAll(m.Error, "\n", " "))
+	}
+	return strings.Join(parts, " ")
+}
+
+func parseMember(value string) (ChangesetMember, bool) {
+	var m ChangesetMember
+	if i := strings.Index(value, " error="); i >= 0 {
+		m.Error = strings.TrimSpace(value[i+len(" error="):])
+		value = value[:i]
+	}
+	tokens := strings.Fields(value)
+	if len(tokens) == 0 {
+		return m, false
+	}
+	m.MR = tokens[0]
+	for _, tok := range tokens[1:] {
+		key, val, ok := strings.Cut(tok, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "rig":
+			m.Rig = val
+		case "branch":
+			m.Branch = val
+		case "target":
+			m.Target = val
+		case "state":
+			m.State = val
+		case "tested":
+			m.Tested = val
+		case "merged":
+			m.MergeCommit = val
+		case "reverted":
+			m.Reverted = val
+		}
+	}
+	if m.State == "" {
+		m.State = MemberPending
+	}
+	return m, true
+}
+
+// ParseChangesetFields extracts change set fields from a description written
+// by FormatChangesetDescription.
+func ParseChangesetFields(description string) *ChangesetFields {
+	fields := &ChangesetFields{}
+
+	for i, line := range strings.Split(description, "\n") {
+		if i == 0 {
+			continue // The title, which may contain a colon
+		}
+		line = strings.TrimSpace(line)
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "null" {
+			value = ""
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "state":
+			fields.State = value
+		case "created_by":
+			fields.CreatedBy = value
+		case "landed_at":
+			fields.LandedAt = value
+		case "reason":
+			fields.Reason = value
+		case "member":
+			if m, ok := parseMember(value); ok {
+				fields.Members = append(fields.Members, m)
+			}
+		}
+	}
+
+	if fields.State == "" {
+		fields.State = ChangesetOpen
+	}
+	return fields
+}
+
+// CreateChangesetBead creates a change set bead linking MRs across rigs.
+// The created_by field is populated from BD_ACTOR env var for provenance tracking.
+func (b *Beads) CreateChangesetBead(title string, fields *ChangesetFields) (*Issue, error) {
+	description := FormatChangesetDescription(title, fields)
+
+	args := []string{"create", "--json",
+		"--title=" + title,
+		"--description=" + description,
+		"--type=task",
+		"--labels=" + LabelChangeset,
+	}
+
+	// Default actor from BD_ACTOR env var for provenance tracking
+	if actor := os.Getenv("BD_ACTOR"); actor != "" {
+		args = append(args, "--actor="+actor)
+	}
+
+	out, err := b.run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(out, &issue); err != nil {
+		return nil, fmt.Errorf("parsing bd create output: %w", err)
+	}
+
+	return &issue, nil
+}
+
+// GetChangesetBead retrieves a change set bead by ID.
+// Returns nil if not found.
+func (b *Beads) GetChangesetBead(id string) (*Issue, *ChangesetFields, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	if !HasLabel(issue, LabelChangeset) {
+		return nil, nil, fmt.Errorf("issue %s is not a change set bead (missing %s label)", id, LabelChangeset)
+	}
+
+	return issue, ParseChangesetFields(issue.Description), nil
+}
+
+// UpdateChangesetFields rewrites a change set bead's fields. A landed change
+// set is closed.
+func (b *Beads) UpdateChangesetFields(issue *Issue, fields *ChangesetFields) error {
+	description := FormatChangesetDescription(issue.Title, fields)
+	if err := b.Update(issue.ID, UpdateOptions{Description: &description}); err != nil {
+		return err
+	}
+
+	if fields.State == ChangesetLanded {
+		return b.CloseWithReason("landed", issue.ID)
+	}
+	return nil
+}
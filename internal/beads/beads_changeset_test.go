@@ -0,0 +1,46 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangesetFieldsRoundTrip(t *testing.T) {
+	fields := &ChangesetFields{
+		State:     ChangesetFailed,
+		CreatedBy: "mayor",
+		Reason:    "client push rejected",
+		Members: []ChangesetMember{
+			{MR: "gt-mr1", Rig: "api", Branch: "polecat/nux/gt-1", Target: "main", State: MemberRolledBack, Tested: "abc123", MergeCommit: "def456", Reverted: "987fed"},
+			{MR: "cl-mr2", Rig: "client", Branch: "polecat/max/cl-2", Target: "main", State: MemberFailed, Error: "push failed: remote: state: protected\nbranch"},
+			{MR: "gt-mr3", Rig: "docs", State: MemberPending},
+		},
+	}
+
+	// A colon in the title must not be read as a field
+	desc := FormatChangesetDescription("Reason: API v2 plus client", fields)
+	got := ParseChangesetFields(desc)
+
+	fields.Members[1].Error = "push failed: remote: state: protected branch"
+	if !reflect.DeepEqual(got, fields) {
+		t.Errorf("round trip:\n got %+v\nwant %+v\ndescription:\n%s", got, fields, desc)
+	}
+}
+
+func TestChangesetAllPassed(t *testing.T) {
+	fields := &ChangesetFields{}
+	if fields.AllPassed() {
+		t.Error("empty change set reported as passed")
+	}
+	fields.Members = []ChangesetMember{{MR: "a", State: MemberPassed}, {MR: "b", State: MemberPending}}
+	if fields.AllPassed() {
+		t.Error("change set with a pending member reported as passed")
+	}
+	fields.Member("b").State = MemberRolledBack
+	if !fields.AllPassed() {
+		t.Error("passed and rolled-back members should be ready to land")
+	}
+	if fields.Member("c") != nil {
+		t.Error("Member found a non-member")
+	}
+}
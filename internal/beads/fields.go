@@ -187,6 +187,14 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Cross-rig change set (see ChangesetFields)
+	ChangesetID string // Change set this MR lands with; held until all members pass
+
+	// Pull request merge mode: an MR whose checks are still pending is
+	// parked and polled again on the refinery's next patrol
+	PullRequest string // URL of the MR's pull request on the forge
+	ChecksSince string // When the MR was parked on pending checks (RFC 3339)
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -261,6 +269,15 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "changeset_id", "changeset-id", "changesetid", "changeset":
+			fields.ChangesetID = value
+			hasFields = true
+		case "pull_request", "pull-request", "pullrequest":
+			fields.PullRequest = value
+			hasFields = true
+		case "checks_since", "checks-since", "checkssince":
+			fields.ChecksSince = value
+			hasFields = true
 		}
 	}
 
@@ -325,6 +342,15 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.ChangesetID != "" {
+		lines = append(lines, "changeset_id: "+fields.ChangesetID)
+	}
+	if fields.PullRequest != "" {
+		lines = append(lines, "pull_request: "+fields.PullRequest)
+	}
+	if fields.ChecksSince != "" {
+		lines = append(lines, "checks_since: "+fields.ChecksSince)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -371,6 +397,16 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"changeset_id":       true,
+		"changeset-id":       true,
+		"changesetid":        true,
+		"changeset":          true,
+		"pull_request":       true,
+		"pull-request":       true,
+		"pullrequest":        true,
+		"checks_since":       true,
+		"checks-since":       true,
+		"checkssince":        true,
 	}
 
 	// Collect non-MR lines from existing description
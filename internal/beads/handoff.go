@@ -3,6 +3,7 @@ package beads
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -80,6 +81,24 @@ func (b *Beads) UpdateHandoffContent(role, content string) error {
 	return b.Update(issue.ID, UpdateOptions{Description: &content})
 }
 
+// AppendHandoffContent adds content to the end of the handoff bead's
+// description, keeping what is already there.
+func (b *Beads) AppendHandoffContent(role, content string) (*Issue, error) {
+	issue, err := b.GetOrCreateHandoffBead(role)
+	if err != nil {
+		return nil, err
+	}
+
+	description := content
+	if existing := strings.TrimRight(issue.Description, "\n"); existing != "" {
+		description = existing + "\n\n" + content
+	}
+	if err := b.Update(issue.ID, UpdateOptions{Description: &description}); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
 // ClearHandoffContent clears the handoff bead's description.
 func (b *Beads) ClearHandoffContent(role string) error {
 	issue, err := b.FindHandoffBead(role)
@@ -39,7 +39,8 @@ var tierLineRegex = regexp.MustCompile(`(?i)^Tier:\s*(haiku|sonnet|opus)\s*$`)
 
 // waitsForLineRegex matches "WaitsFor: condition1, condition2, ..." lines.
 // Common conditions: "all-children" (fanout gate for dynamically bonded children)
-var waitsForLineRegex = regexp.MustCompile(`(?i)^WaitsFor:\s*(.+)$`)
+// Also matches the "waits_for:" line written to instantiated step beads.
+var waitsForLineRegex = regexp.MustCompile(`(?i)^Waits_?For:\s*(.+)$`)
 
 // typeLineRegex matches "Type: task|wait|..." lines.
 // Common types: "task" (default), "wait" (await-signal with backoff)
@@ -219,6 +220,19 @@ func parseBackoffConfig(configStr string) *BackoffConfig {
 	return cfg
 }
 
+// ParseStepTier returns the tier hint recorded in a step bead's description
+// ("tier: opus" from instantiation, or a "Tier:" line in a template step),
+// lowercased. Returns "" if the step has no tier.
+func ParseStepTier(description string) string {
+	tier := ""
+	for _, line := range strings.Split(description, "\n") {
+		if matches := tierLineRegex.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			tier = strings.ToLower(matches[1])
+		}
+	}
+	return tier
+}
+
 // ExpandTemplateVars replaces {{variable}} placeholders in text using the provided context map.
 // Unknown variables are left as-is.
 func ExpandTemplateVars(text string, ctx map[string]string) string {
@@ -385,6 +399,9 @@ func (b *Beads) instantiateFromMarkdown(mol *Issue, parent *Issue, opts Instanti
 			}
 		}
 	}
+	if err := validateWaitsFor(steps); err != nil {
+		return nil, err
+	}
 
 	// Create child issues for each step
 	var createdIssues []*Issue
@@ -406,6 +423,9 @@ func (b *Beads) instantiateFromMarkdown(mol *Issue, parent *Issue, opts Instanti
 		if step.Tier != "" {
 			description += fmt.Sprintf("\ntier: %s", step.Tier)
 		}
+		if len(step.WaitsFor) > 0 {
+			description += fmt.Sprintf("\nwaits_for: %s", strings.Join(step.WaitsFor, ", "))
+		}
 
 		// Create the child issue
 		childOpts := CreateOptions{
@@ -505,7 +525,7 @@ func ValidateMolecule(mol *Issue) error {
 		return err
 	}
 
-	return nil
+	return validateWaitsFor(steps)
 }
 
 // detectCycles checks for circular dependencies in the step graph using DFS.
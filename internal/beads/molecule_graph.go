@@ -0,0 +1,115 @@
+package beads
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+// MoleculeGraph returns the dependency graph of a molecule's steps (the
+// children of rootID). Nodes are colored by status and labeled with their
+// assignee; open steps with unmet dependencies or WaitsFor conditions are
+// shown as blocked.
+func (b *Beads) MoleculeGraph(rootID string) (*graph.Graph, error) {
+	root, err := b.Show(rootID)
+	if err != nil {
+		return nil, fmt.Errorf("getting root issue: %w", err)
+	}
+	children, err := b.List(ListOptions{Parent: rootID, Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing children: %w", err)
+	}
+	if len(children) == 0 {
+		return nil, fmt.Errorf("no steps found for %s (not a molecule root?)", rootID)
+	}
+
+	// List output lacks dependency details; fetch them with show.
+	ids := make([]string, len(children))
+	for i, child := range children {
+		ids[i] = child.ID
+	}
+	details, err := b.ShowMultiple(ids)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]*Issue, len(children))
+	for i, child := range children {
+		steps[i] = child
+		if d, ok := details[child.ID]; ok {
+			steps[i] = d
+		}
+	}
+
+	waiting := make(map[string]bool)
+	for _, step := range steps {
+		if step.Status != "open" {
+			continue
+		}
+		results, err := b.EvaluateWaits(step)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating waits for %s: %w", step.ID, err)
+		}
+		waiting[step.ID] = len(UnmetWaits(results)) > 0
+	}
+
+	return moleculeGraph(root, steps, waiting), nil
+}
+
+// moleculeGraph builds the graph of a molecule from its steps. waiting
+// marks open steps held back by WaitsFor conditions.
+func moleculeGraph(root *Issue, steps []*Issue, waiting map[string]bool) *graph.Graph {
+	g := graph.New(root.ID + " " + root.Title)
+
+	statuses := make(map[string]string, len(steps))
+	for _, step := range steps {
+		statuses[step.ID] = step.Status
+	}
+
+	for _, step := range steps {
+		deps := stepDependencies(step, statuses)
+		status := graphStatus(step.Status)
+		if status == graph.StatusOpen && waiting[step.ID] {
+			status = graph.StatusBlocked
+		}
+		for _, dep := range deps {
+			if status == graph.StatusOpen && dep.Status != "closed" {
+				status = graph.StatusBlocked
+			}
+			g.AddEdge(dep.ID, step.ID)
+		}
+		g.AddNode(graph.Node{ID: step.ID, Title: step.Title, Status: status, Assignee: step.Assignee})
+	}
+	return g
+}
+
+// stepDependencies returns the blocking dependencies of a step, from show
+// output if present, otherwise from DependsOn with statuses looked up among
+// the molecule's steps.
+func stepDependencies(step *Issue, statuses map[string]string) []IssueDep {
+	var deps []IssueDep
+	for _, dep := range step.Dependencies {
+		if dep.DependencyType != "parent-child" {
+			deps = append(deps, dep)
+		}
+	}
+	if len(step.Dependencies) > 0 {
+		return deps
+	}
+	for _, id := range step.DependsOn {
+		deps = append(deps, IssueDep{ID: id, Status: statuses[id]})
+	}
+	return deps
+}
+
+// graphStatus maps a bead status to a graph node status.
+func graphStatus(status string) graph.Status {
+	switch status {
+	case "closed", "tombstone":
+		return graph.StatusClosed
+	case "in_progress", StatusHooked, StatusPinned:
+		return graph.StatusInProgress
+	case "blocked":
+		return graph.StatusBlocked
+	}
+	return graph.StatusOpen
+}
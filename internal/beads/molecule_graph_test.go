@@ -0,0 +1,74 @@
+package beads
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+func TestMoleculeGraph(t *testing.T) {
+	root := &Issue{ID: "gt-mol", Title: "Polecat work"}
+	steps := []*Issue{
+		{ID: "gt-mol.1", Title: "Load context", Status: "closed"},
+		{
+			ID: "gt-mol.2", Title: "Implement", Status: "in_progress", Assignee: "gastown/polecats/nux",
+			Dependencies: []IssueDep{
+				{ID: "gt-mol", Status: "open", DependencyType: "parent-child"},
+				{ID: "gt-mol.1", Status: "closed", DependencyType: "blocks"},
+			},
+		},
+		{ID: "gt-mol.3", Title: "Test", Status: "open", DependsOn: []string{"gt-mol.2"}},
+		{ID: "gt-mol.4", Title: "Docs", Status: "open", DependsOn: []string{"gt-mol.1"}},
+		{ID: "gt-mol.5", Title: "Gather", Status: "open"},
+	}
+	waiting := map[string]bool{"gt-mol.5": true}
+
+	g := moleculeGraph(root, steps, waiting)
+
+	want := map[string]graph.Status{
+		"gt-mol.1": graph.StatusClosed,
+		"gt-mol.2": graph.StatusInProgress,
+		"gt-mol.3": graph.StatusBlocked, // needs in-progress step
+		"gt-mol.4": graph.StatusOpen,    // ready
+		"gt-mol.5": graph.StatusBlocked, // unmet WaitsFor
+	}
+	for _, n := range g.Nodes {
+		if n.Status != want[n.ID] {
+			t.Errorf("%s status = %q, want %q", n.ID, n.Status, want[n.ID])
+		}
+	}
+	if g.Nodes[1].Assignee != "gastown/polecats/nux" {
+		t.Errorf("assignee = %q", g.Nodes[1].Assignee)
+	}
+
+	// The parent-child link to the root is not an edge.
+	wantEdges := []graph.Edge{
+		{From: "gt-mol.1", To: "gt-mol.2"},
+		{From: "gt-mol.2", To: "gt-mol.3"},
+		{From: "gt-mol.1", To: "gt-mol.4"},
+	}
+	if len(g.Edges) != len(wantEdges) {
+		t.Fatalf("edges = %v, want %v", g.Edges, wantEdges)
+	}
+	for i, e := range wantEdges {
+		if g.Edges[i] != e {
+			t.Errorf("edge %d = %v, want %v", i, g.Edges[i], e)
+		}
+	}
+}
+
+func TestGraphStatus(t *testing.T) {
+	for status, want := range map[string]graph.Status{
+		"open":        graph.StatusOpen,
+		"in_progress": graph.StatusInProgress,
+		StatusHooked:  graph.StatusInProgress,
+		StatusPinned:  graph.StatusInProgress,
+		"blocked":     graph.StatusBlocked,
+		"closed":      graph.StatusClosed,
+		"tombstone":   graph.StatusClosed,
+	} {
+		if got := graphStatus(status); got != want {
+			t.Errorf("graphStatus(%q) = %q, want %q", status, got, want)
+		}
+	}
+}
@@ -787,3 +787,23 @@ Needs: step1`
 		t.Errorf("step[1].Type = %q, want task", steps[1].Type)
 	}
 }
+
+func TestParseStepTier(t *testing.T) {
+	tests := []struct {
+		name string
+		desc string
+		want string
+	}{
+		{"none", "Do the thing.\n\ninstantiated_from: gt-mol\nstep: build", ""},
+		{"instantiated", "Do the thing.\n\ninstantiated_from: gt-mol\nstep: build\ntier: opus", "opus"},
+		{"template", "Tier: Haiku\nDo the thing.", "haiku"},
+		{"not a tier", "Tier: platinum", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseStepTier(tt.desc); got != tt.want {
+				t.Errorf("ParseStepTier() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
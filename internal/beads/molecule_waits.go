@@ -0,0 +1,228 @@
+package beads
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Wait condition kinds for a step's WaitsFor list.
+//
+// Children are the beads bonded as children of the waiting step or of the
+// steps it needs, which is where fan-out steps attach dynamic work.
+const (
+	WaitAllChildren = "all-children"  // at least one child, and every child closed
+	WaitAnyChildren = "any-children"  // at least one child closed
+	WaitNOfChildren = "n-of-children" // "N-of-children": at least N children closed
+	WaitBeadClosed  = "bead-closed"   // "bead:<id>-closed": a specific bead closed
+)
+
+// WaitingLabel marks an open step that blocked an agent on WaitsFor, so
+// the waiters can be woken when its conditions clear.
+const WaitingLabel = "gt:waiting"
+
+// WaiterLabelPrefix prefixes the labels recording which agents to wake.
+const WaiterLabelPrefix = "waiter:"
+
+// WaitCondition is a parsed WaitsFor entry.
+type WaitCondition struct {
+	Raw    string
+	Kind   string
+	N      int    // for WaitNOfChildren
+	BeadID string // for WaitBeadClosed
+}
+
+// ParseWaitCondition parses a WaitsFor entry. Returns an error if the
+// condition isn't recognized.
+func ParseWaitCondition(s string) (WaitCondition, error) {
+	s = strings.TrimSpace(s)
+	cond := WaitCondition{Raw: s}
+	lower := strings.ToLower(s)
+
+	switch {
+	case lower == WaitAllChildren:
+		cond.Kind = WaitAllChildren
+	case lower == WaitAnyChildren:
+		cond.Kind = WaitAnyChildren
+	case strings.HasSuffix(lower, "-of-children"):
+		if n, err := strconv.Atoi(strings.TrimSuffix(lower, "-of-children")); err == nil && n > 0 {
+			cond.Kind = WaitNOfChildren
+			cond.N = n
+		}
+	case strings.HasPrefix(lower, "bead:") && strings.HasSuffix(lower, "-closed"):
+		id := s[len("bead:") : len(s)-len("-closed")]
+		if id != "" {
+			cond.Kind = WaitBeadClosed
+			cond.BeadID = id
+		}
+	}
+	if cond.Kind == "" {
+		return cond, fmt.Errorf("unknown WaitsFor condition %q", s)
+	}
+	return cond, nil
+}
+
+// validateWaitsFor checks that every WaitsFor condition of the steps is
+// recognized.
+func validateWaitsFor(steps []MoleculeStep) error {
+	for _, step := range steps {
+		for _, raw := range step.WaitsFor {
+			if _, err := ParseWaitCondition(raw); err != nil {
+				return fmt.Errorf("step %q: %w", step.Ref, err)
+			}
+		}
+	}
+	return nil
+}
+
+// needsChildren reports whether evaluating the condition requires the
+// step's children.
+func (c WaitCondition) needsChildren() bool {
+	return c.Kind == WaitAllChildren || c.Kind == WaitAnyChildren || c.Kind == WaitNOfChildren
+}
+
+// ParseStepWaitsFor returns the WaitsFor conditions recorded in a step
+// bead's description ("waits_for:" from instantiation, or a "WaitsFor:"
+// line in a template step).
+func ParseStepWaitsFor(description string) []string {
+	var conds []string
+	for _, line := range strings.Split(description, "\n") {
+		matches := waitsForLineRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		for _, cond := range strings.Split(matches[1], ",") {
+			if cond = strings.TrimSpace(cond); cond != "" {
+				conds = append(conds, cond)
+			}
+		}
+	}
+	return conds
+}
+
+// WaitResult is the evaluation of one WaitsFor condition.
+type WaitResult struct {
+	Condition string `json:"condition"`
+	Met       bool   `json:"met"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// EvaluateWaits evaluates a step's WaitsFor conditions against the live
+// bead graph. Returns nil if the step has none. Unknown conditions are
+// rejected when the molecule is validated; one that reaches a step anyway
+// is reported as unmet.
+func (b *Beads) EvaluateWaits(step *Issue) ([]WaitResult, error) {
+	raw := ParseStepWaitsFor(step.Description)
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	conds := make([]WaitCondition, len(raw))
+	parseErrs := make([]error, len(raw))
+	needChildren := false
+	for i, r := range raw {
+		conds[i], parseErrs[i] = ParseWaitCondition(r)
+		needChildren = needChildren || conds[i].needsChildren()
+	}
+
+	var total, closed int
+	if needChildren {
+		children, err := b.stepChildren(step.ID)
+		if err != nil {
+			return nil, err
+		}
+		total = len(children)
+		for _, child := range children {
+			if child.Status == "closed" {
+				closed++
+			}
+		}
+	}
+	childDetail := fmt.Sprintf("%d/%d children closed", closed, total)
+
+	results := make([]WaitResult, 0, len(conds))
+	for i, cond := range conds {
+		result := WaitResult{Condition: cond.Raw}
+		if parseErrs[i] != nil {
+			result.Detail = parseErrs[i].Error()
+			results = append(results, result)
+			continue
+		}
+		switch cond.Kind {
+		case WaitAllChildren:
+			result.Met = total > 0 && closed == total
+			result.Detail = childDetail
+		case WaitAnyChildren:
+			result.Met = closed > 0
+			result.Detail = childDetail
+		case WaitNOfChildren:
+			result.Met = closed >= cond.N
+			result.Detail = childDetail
+		case WaitBeadClosed:
+			bead, err := b.Show(cond.BeadID)
+			switch {
+			case err == nil:
+				result.Met = bead.Status == "closed"
+				result.Detail = cond.BeadID + " is " + bead.Status
+			case err == ErrNotFound:
+				result.Detail = cond.BeadID + " not found"
+			default:
+				return nil, err
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// stepChildren returns the children of a step and of the steps it needs.
+func (b *Beads) stepChildren(stepID string) ([]*Issue, error) {
+	parents := []string{stepID}
+	step, err := b.Show(stepID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range step.Dependencies {
+		if dep.DependencyType != "parent-child" {
+			parents = append(parents, dep.ID)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var children []*Issue
+	for _, parent := range parents {
+		issues, err := b.List(ListOptions{Parent: parent, Status: "all", Priority: -1})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if !seen[issue.ID] {
+				seen[issue.ID] = true
+				children = append(children, issue)
+			}
+		}
+	}
+	return children, nil
+}
+
+// UnmetWaits returns the results that are not met.
+func UnmetWaits(results []WaitResult) []WaitResult {
+	var unmet []WaitResult
+	for _, r := range results {
+		if !r.Met {
+			unmet = append(unmet, r)
+		}
+	}
+	return unmet
+}
+
+// WaitersFromLabels returns the agents recorded as waiting on a step.
+func WaitersFromLabels(labels []string) []string {
+	var waiters []string
+	for _, label := range labels {
+		if strings.HasPrefix(label, WaiterLabelPrefix) {
+			waiters = append(waiters, strings.TrimPrefix(label, WaiterLabelPrefix))
+		}
+	}
+	return waiters
+}
@@ -0,0 +1,81 @@
+package beads
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseWaitCondition(t *testing.T) {
+	tests := []struct {
+		in     string
+		kind   string
+		n      int
+		beadID string
+	}{
+		{"all-children", WaitAllChildren, 0, ""},
+		{" Any-Children ", WaitAnyChildren, 0, ""},
+		{"3-of-children", WaitNOfChildren, 3, ""},
+		{"bead:gt-abc.2-closed", WaitBeadClosed, 0, "gt-abc.2"},
+	}
+	for _, tt := range tests {
+		got, err := ParseWaitCondition(tt.in)
+		if err != nil || got.Kind != tt.kind || got.N != tt.n || got.BeadID != tt.beadID {
+			t.Errorf("ParseWaitCondition(%q) = %+v, %v, want kind=%s n=%d bead=%q", tt.in, got, err, tt.kind, tt.n, tt.beadID)
+		}
+	}
+
+	for _, in := range []string{"0-of-children", "x-of-children", "bead:-closed", "sunrise"} {
+		if _, err := ParseWaitCondition(in); err == nil {
+			t.Errorf("ParseWaitCondition(%q) = nil error, want unknown condition", in)
+		}
+	}
+}
+
+func TestValidateMoleculeUnknownWaitsFor(t *testing.T) {
+	mol := &Issue{
+		ID:          "mol-gather",
+		Type:        "molecule",
+		Description: "## Step: fanout\nSpawn workers.\n\n## Step: gather\nCollect.\nNeeds: fanout\nWaitsFor: sunrise",
+	}
+	if err := ValidateMolecule(mol); err == nil {
+		t.Error("ValidateMolecule() = nil, want error for unknown WaitsFor condition")
+	}
+
+	mol.Description = strings.Replace(mol.Description, "sunrise", "all-children", 1)
+	if err := ValidateMolecule(mol); err != nil {
+		t.Errorf("ValidateMolecule() = %v, want nil", err)
+	}
+}
+
+func TestParseStepWaitsFor(t *testing.T) {
+	desc := "Gather results.\n\ninstantiated_from: mol-x\nstep: gather\nwaits_for: all-children, bead:gt-1-closed"
+	got := ParseStepWaitsFor(desc)
+	want := []string{"all-children", "bead:gt-1-closed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStepWaitsFor = %v, want %v", got, want)
+	}
+
+	if got := ParseStepWaitsFor("WaitsFor: any-children"); !reflect.DeepEqual(got, []string{"any-children"}) {
+		t.Errorf("ParseStepWaitsFor(template line) = %v", got)
+	}
+	if got := ParseStepWaitsFor("no conditions here"); got != nil {
+		t.Errorf("ParseStepWaitsFor(none) = %v, want nil", got)
+	}
+}
+
+func TestUnmetWaitsAndWaiters(t *testing.T) {
+	results := []WaitResult{
+		{Condition: "all-children", Met: true},
+		{Condition: "bead:gt-1-closed", Met: false, Detail: "gt-1 is open"},
+	}
+	unmet := UnmetWaits(results)
+	if len(unmet) != 1 || unmet[0].Condition != "bead:gt-1-closed" {
+		t.Errorf("UnmetWaits = %+v", unmet)
+	}
+
+	labels := []string{"gt:waiting", "waiter:gastown/polecats/nux", "other"}
+	if got := WaitersFromLabels(labels); !reflect.DeepEqual(got, []string{"gastown/polecats/nux"}) {
+		t.Errorf("WaitersFromLabels = %v", got)
+	}
+}
@@ -0,0 +1,386 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NativeEnv disables native reads when set to "0" or "false", forcing every
+// query through bd.
+const NativeEnv = "GT_BEADS_NATIVE"
+
+// DatabaseFile is the SQLite database bd keeps in a .beads directory.
+const DatabaseFile = "beads.db"
+
+// Snapshot is a read-only, in-memory view of a beads database, loaded
+// directly from the SQLite database instead of forking bd for every query.
+// Writes still go through bd; the snapshot is reloaded whenever the
+// database or its WAL changes on disk.
+//
+// Like bd --allow-stale, a snapshot reflects the database as it is and
+// does not import a newer issues.jsonl first.
+type Snapshot struct {
+	prefix  string
+	issues  []*Issue // list order: priority, then newest first
+	byID    map[string]*Issue
+	deps    map[string][]nativeDep // issue_id -> its dependencies
+	rdeps   map[string][]nativeDep // depends_on_id -> its dependents
+	created map[string]time.Time
+}
+
+type nativeDep struct {
+	from, to, kind string
+}
+
+// nativeStamp identifies a version of the database files on disk.
+type nativeStamp struct {
+	dbSize, walSize   int64
+	dbMtime, walMtime int64
+}
+
+type nativeEntry struct {
+	stamp nativeStamp
+	snap  *Snapshot
+}
+
+var (
+	nativeMu    sync.Mutex
+	nativeCache = make(map[string]*nativeEntry)
+)
+
+// Native returns a snapshot of the beads database in beadsDir. It returns
+// false if native reads are disabled, there is no database, or it can't be
+// read (a schema the reader doesn't know, or bd holding a write lock past
+// the busy timeout); callers should then fall back to bd.
+//
+// Snapshots are cached per process and invalidated by the size and mtime of
+// the database and its WAL, so repeated queries in one gt command read the
+// database once.
+func Native(beadsDir string) (*Snapshot, bool) {
+	return native(beadsDir, true)
+}
+
+// NativeCached is Native without the cold load: it returns the snapshot only
+// if this process already holds an up-to-date one. Loading a snapshot reads
+// the whole database, so a command issuing a single query is faster forking
+// bd; it reads natively only once something else has paid for the load.
+func NativeCached(beadsDir string) (*Snapshot, bool) {
+	return native(beadsDir, false)
+}
+
+func native(beadsDir string, load bool) (*Snapshot, bool) {
+	switch strings.ToLower(os.Getenv(NativeEnv)) {
+	case "0", "false", "off":
+		return nil, false
+	}
+
+	dbPath := filepath.Join(beadsDir, DatabaseFile)
+	// Stat before reading: a write that lands during the read changes the
+	// stamp, so the next call reloads rather than keeping a stale snapshot.
+	stamp, ok := statNative(dbPath)
+	if !ok {
+		return nil, false
+	}
+
+	nativeMu.Lock()
+	defer nativeMu.Unlock()
+	if e, ok := nativeCache[dbPath]; ok && e.stamp == stamp {
+		return e.snap, true
+	}
+	if !load {
+		return nil, false
+	}
+
+	snap, err := readSnapshot(dbPath)
+	if err != nil {
+		delete(nativeCache, dbPath)
+		return nil, false
+	}
+	nativeCache[dbPath] = &nativeEntry{stamp: stamp, snap: snap}
+	return snap, true
+}
+
+// readSnapshot loads a snapshot in one read transaction.
+func readSnapshot(dbPath string) (*Snapshot, error) {
+	f, closeFn, err := openSQLite(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+	return loadSnapshot(f)
+}
+
+func statNative(dbPath string) (nativeStamp, bool) {
+	db, err := os.Stat(dbPath)
+	if err != nil || db.IsDir() {
+		return nativeStamp{}, false
+	}
+	stamp := nativeStamp{dbSize: db.Size(), dbMtime: db.ModTime().UnixNano()}
+	if wal, err := os.Stat(dbPath + "-wal"); err == nil {
+		stamp.walSize = wal.Size()
+		stamp.walMtime = wal.ModTime().UnixNano()
+	}
+	return stamp, true
+}
+
+// loadSnapshot reads the issues, labels, dependencies and config tables.
+func loadSnapshot(f *sqliteTx) (*Snapshot, error) {
+	s := &Snapshot{
+		byID:    make(map[string]*Issue),
+		deps:    make(map[string][]nativeDep),
+		rdeps:   make(map[string][]nativeDep),
+		created: make(map[string]time.Time),
+	}
+
+	err := f.rows("issues", issueColumns, func(row *sqliteRow) {
+		issue, created := issueFromRow(row)
+		if issue.ID == "" || issue.Status == "tombstone" {
+			return
+		}
+		s.issues = append(s.issues, issue)
+		s.byID[issue.ID] = issue
+		s.created[issue.ID] = created
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.rows("labels", []string{"issue_id", "label"}, func(row *sqliteRow) {
+		if issue, ok := s.byID[sqlString(row.get("issue_id"))]; ok {
+			issue.Labels = append(issue.Labels, sqlString(row.get("label")))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.rows("dependencies", []string{"issue_id", "depends_on_id", "type"}, func(row *sqliteRow) {
+		d := nativeDep{
+			from: sqlString(row.get("issue_id")),
+			to:   sqlString(row.get("depends_on_id")),
+			kind: sqlString(row.get("type")),
+		}
+		s.deps[d.from] = append(s.deps[d.from], d)
+		s.rdeps[d.to] = append(s.rdeps[d.to], d)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The prefix lets Show answer "not found" authoritatively for IDs that
+	// belong to this database. Older databases may not have a config table.
+	_ = f.rows("config", []string{"key", "value"}, func(row *sqliteRow) {
+		if sqlString(row.get("key")) == "issue_prefix" {
+			s.prefix = sqlString(row.get("value"))
+		}
+	})
+
+	for _, issue := range s.issues {
+		sort.Strings(issue.Labels)
+		for _, d := range s.deps[issue.ID] {
+			if d.kind == "parent-child" {
+				issue.Parent = d.to
+			}
+		}
+		issue.DependencyCount = len(s.deps[issue.ID])
+		issue.DependentCount = len(s.rdeps[issue.ID])
+	}
+
+	// Same order as bd list: priority, then newest first.
+	sort.SliceStable(s.issues, func(i, j int) bool {
+		a, b := s.issues[i], s.issues[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return s.created[a.ID].After(s.created[b.ID])
+	})
+	return s, nil
+}
+
+// issueColumns are the issues columns issueFromRow reads. Older databases
+// use "wisp" where newer ones use "ephemeral".
+var issueColumns = []string{
+	"id", "title", "description", "status", "priority", "issue_type",
+	"created_at", "created_by", "updated_at", "closed_at", "assignee",
+	"hook_bead", "role_bead", "agent_state", "pinned", "ephemeral", "wisp",
+}
+
+// issueFromRow converts an issues row, also returning the parsed creation
+// time for sorting.
+func issueFromRow(row *sqliteRow) (*Issue, time.Time) {
+	wisp := row.get("ephemeral")
+	if wisp == nil {
+		wisp = row.get("wisp")
+	}
+	createdRaw := sqlString(row.get("created_at"))
+	created := parseBeadsTime(createdRaw)
+	return &Issue{
+		ID:          sqlString(row.get("id")),
+		Title:       sqlString(row.get("title")),
+		Description: sqlString(row.get("description")),
+		Status:      sqlString(row.get("status")),
+		Priority:    sqlInt(row.get("priority")),
+		Type:        sqlString(row.get("issue_type")),
+		CreatedAt:   formatParsedTime(created, createdRaw),
+		CreatedBy:   sqlString(row.get("created_by")),
+		UpdatedAt:   formatBeadsTime(sqlString(row.get("updated_at"))),
+		ClosedAt:    formatBeadsTime(sqlString(row.get("closed_at"))),
+		Assignee:    sqlString(row.get("assignee")),
+		HookBead:    sqlString(row.get("hook_bead")),
+		RoleBead:    sqlString(row.get("role_bead")),
+		AgentState:  sqlString(row.get("agent_state")),
+		Pinned:      sqlInt(row.get("pinned")) != 0,
+		Wisp:        sqlInt(wisp) != 0,
+	}, created
+}
+
+// parseBeadsTime parses the timestamp formats found in beads databases:
+// Go's database/sql encoding ("2006-01-02 15:04:05.999-07:00"), RFC 3339,
+// and SQLite's CURRENT_TIMESTAMP (UTC, no zone). The layout is picked from
+// the value's shape since snapshots parse thousands of these.
+func parseBeadsTime(s string) time.Time {
+	if len(s) < 19 {
+		return time.Time{}
+	}
+	layout := "2006-01-02 15:04:05.999999999"
+	if s[10] == 'T' {
+		layout = "2006-01-02T15:04:05.999999999"
+	}
+	if strings.ContainsAny(s[19:], "Z+-") {
+		layout += "Z07:00"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// formatBeadsTime normalizes a stored timestamp to RFC 3339, matching bd's
+// JSON output. Unparseable values are passed through unchanged.
+func formatBeadsTime(s string) string {
+	if s == "" {
+		return ""
+	}
+	return formatParsedTime(parseBeadsTime(s), s)
+}
+
+func formatParsedTime(t time.Time, raw string) string {
+	if t.IsZero() {
+		return raw
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// cloneIssue copies an issue so callers can't mutate the shared snapshot.
+func cloneIssue(issue *Issue) *Issue {
+	c := *issue
+	c.Labels = append([]string(nil), issue.Labels...)
+	return &c
+}
+
+// Prefix returns the database's issue prefix (e.g. "gt"), if recorded.
+func (s *Snapshot) Prefix() string {
+	return s.prefix
+}
+
+// Owns reports whether id belongs to this database by prefix, so a miss is
+// authoritative rather than a cross-database reference bd would route.
+func (s *Snapshot) Owns(id string) bool {
+	return s.prefix != "" && strings.HasPrefix(id, s.prefix+"-")
+}
+
+// List returns issues matching opts with the same filter semantics as
+// bd list: an empty Status excludes closed issues and "all" includes them.
+func (s *Snapshot) List(opts ListOptions) []*Issue {
+	label := opts.Label
+	if label == "" && opts.Type != "" {
+		label = "gt:" + opts.Type
+	}
+	var statuses []string
+	if opts.Status != "" && opts.Status != "all" {
+		statuses = strings.Split(opts.Status, ",")
+	}
+
+	var result []*Issue
+	for _, issue := range s.issues {
+		switch {
+		case statuses == nil && opts.Status == "" && issue.Status == "closed":
+			continue
+		case statuses != nil && !containsString(statuses, issue.Status):
+			continue
+		case label != "" && !containsString(issue.Labels, label):
+			continue
+		case opts.IssueType != "" && issue.Type != opts.IssueType:
+			continue
+		case opts.Priority >= 0 && issue.Priority != opts.Priority:
+			continue
+		case opts.Parent != "" && issue.Parent != opts.Parent:
+			continue
+		case opts.Assignee != "" && issue.Assignee != opts.Assignee:
+			continue
+		case opts.NoAssignee && issue.Assignee != "":
+			continue
+		}
+		result = append(result, cloneIssue(issue))
+	}
+	return result
+}
+
+// Get returns an issue with its dependencies and dependents filled in, as
+// bd show does.
+func (s *Snapshot) Get(id string) (*Issue, bool) {
+	issue, ok := s.byID[id]
+	if !ok {
+		return nil, false
+	}
+	c := cloneIssue(issue)
+	for _, d := range s.deps[id] {
+		if target, ok := s.byID[d.to]; ok {
+			c.Dependencies = append(c.Dependencies, issueDep(target, d.kind))
+		}
+	}
+	for _, d := range s.rdeps[id] {
+		if source, ok := s.byID[d.from]; ok {
+			c.Dependents = append(c.Dependents, issueDep(source, d.kind))
+		}
+	}
+	return c, true
+}
+
+// DependsOn returns the raw depends_on_id of id's dependencies of the given
+// type (all types if depType is empty), including external references such
+// as "external:rig:id" that don't resolve in this database.
+func (s *Snapshot) DependsOn(id, depType string) []string {
+	var ids []string
+	for _, d := range s.deps[id] {
+		if depType == "" || d.kind == depType {
+			ids = append(ids, d.to)
+		}
+	}
+	return ids
+}
+
+func issueDep(issue *Issue, kind string) IssueDep {
+	return IssueDep{
+		ID:             issue.ID,
+		Title:          issue.Title,
+		Status:         issue.Status,
+		Priority:       issue.Priority,
+		Type:           issue.Type,
+		DependencyType: kind,
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,482 @@
+package beads
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// nativeSchema mirrors the parts of bd's schema the native reader uses,
+// plus a few columns it must skip over.
+const nativeSchema = `
+CREATE TABLE issues (
+    id TEXT PRIMARY KEY,
+    content_hash TEXT,
+    title TEXT NOT NULL CHECK(length(title) <= 500),
+    description TEXT NOT NULL DEFAULT '',
+    design TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    priority INTEGER NOT NULL DEFAULT 2 CHECK(priority >= 0 AND priority <= 4),
+    issue_type TEXT NOT NULL DEFAULT 'task',
+    assignee TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT DEFAULT '',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at DATETIME,
+    CHECK ((status = 'closed') = (closed_at IS NOT NULL) OR status = 'tombstone')
+);
+CREATE TABLE labels (
+    issue_id TEXT NOT NULL,
+    label TEXT NOT NULL,
+    PRIMARY KEY (issue_id, label)
+);
+CREATE TABLE dependencies (
+    issue_id TEXT NOT NULL,
+    depends_on_id TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'blocks',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (issue_id, depends_on_id)
+);
+CREATE TABLE config (key TEXT PRIMARY KEY, value TEXT NOT NULL);
+INSERT INTO config VALUES ('issue_prefix', 'gt');
+ALTER TABLE issues ADD COLUMN ephemeral INTEGER DEFAULT 0;
+ALTER TABLE issues ADD COLUMN pinned INTEGER DEFAULT 0;
+ALTER TABLE issues ADD COLUMN hook_bead TEXT DEFAULT '';
+ALTER TABLE issues ADD COLUMN agent_state TEXT DEFAULT '';
+`
+
+// nativeFixture creates a .beads directory whose database is built by the
+// sqlite3 CLI. With keepWAL, committed pages stay in beads.db-wal the way
+// they do while bd holds the database open.
+func nativeFixture(tb testing.TB, keepWAL bool, sql string) string {
+	tb.Helper()
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		tb.Skip("sqlite3 not installed")
+	}
+	beadsDir := filepath.Join(tb.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		tb.Fatal(err)
+	}
+	execSQLite(tb, beadsDir, keepWAL, nativeSchema+sql)
+	if _, err := os.Stat(filepath.Join(beadsDir, DatabaseFile+"-wal")); keepWAL && err != nil {
+		tb.Fatalf("expected a WAL file: %v", err)
+	}
+	return beadsDir
+}
+
+func execSQLite(tb testing.TB, beadsDir string, keepWAL bool, sql string) {
+	tb.Helper()
+	script := sql
+	if keepWAL {
+		script = "PRAGMA journal_mode=WAL;\n.dbconfig no_ckpt_on_close on\n" + sql
+	}
+	cmd := exec.Command("sqlite3", filepath.Join(beadsDir, DatabaseFile))
+	cmd.Stdin = strings.NewReader(script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		tb.Fatalf("sqlite3: %v\n%s", err, out)
+	}
+}
+
+const nativeIssues = `
+INSERT INTO issues (id, title, description, status, priority, issue_type, assignee, created_at, updated_at, closed_at, ephemeral, hook_bead, agent_state) VALUES
+ ('gt-convoy', 'Ship it', '', 'open', 1, 'convoy', NULL, '2025-01-01 10:00:00', '2025-01-01 10:00:00', NULL, 0, '', ''),
+ ('gt-a', 'First task', 'body', 'open', 2, 'task', 'gastown/polecats/Toast', '2025-01-02 10:00:00.5+00:00', '2025-01-03T10:00:00Z', NULL, 0, '', ''),
+ ('gt-b', 'Second task', '', 'in_progress', 2, 'task', NULL, '2025-01-03 10:00:00', '2025-01-03 10:00:00', NULL, 0, '', ''),
+ ('gt-c', 'Done task', '', 'closed', 1, 'task', NULL, '2025-01-01 09:00:00', '2025-01-04 10:00:00', '2025-01-04 10:00:00', 0, '', ''),
+ ('gt-dead', 'Deleted', '', 'tombstone', 2, 'task', NULL, '2025-01-01 09:00:00', '2025-01-01 09:00:00', NULL, 0, '', ''),
+ ('gt-gastown-polecat-Toast', 'Toast', '', 'open', 2, 'agent', NULL, '2025-01-01 09:00:00', '2025-01-01 09:00:00', NULL, 0, 'gt-a', 'working'),
+ ('gt-msg', 'POLECAT_DONE Toast', 'done', 'open', 1, 'message', 'gastown/witness', '2025-01-05 09:00:00', '2025-01-05 09:00:00', NULL, 1, '', '');
+INSERT INTO labels VALUES ('gt-a', 'urgent'), ('gt-a', 'backend'), ('gt-gastown-polecat-Toast', 'gt:agent'),
+ ('gt-msg', 'from:gastown/polecats/Toast'), ('gt-msg', 'cc:mayor/');
+INSERT INTO dependencies (issue_id, depends_on_id, type) VALUES
+ ('gt-a', 'gt-convoy', 'parent-child'),
+ ('gt-b', 'gt-a', 'blocks'),
+ ('gt-convoy', 'gt-a', 'tracks'),
+ ('gt-convoy', 'external:beads:bd-xyz', 'tracks');
+`
+
+func ids(issues []*Issue) string {
+	var out []string
+	for _, issue := range issues {
+		out = append(out, issue.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestNativeList(t *testing.T) {
+	for _, keepWAL := range []bool{false, true} {
+		t.Run(fmt.Sprintf("wal=%v", keepWAL), func(t *testing.T) {
+			snap, ok := Native(nativeFixture(t, keepWAL, nativeIssues))
+			if !ok {
+				t.Fatal("Native returned false")
+			}
+
+			tests := []struct {
+				name string
+				opts ListOptions
+				want string
+			}{
+				{"default excludes closed", ListOptions{Priority: -1}, "gt-msg,gt-convoy,gt-b,gt-a,gt-gastown-polecat-Toast"},
+				{"all", ListOptions{Status: "all", Priority: -1}, "gt-msg,gt-convoy,gt-c,gt-b,gt-a,gt-gastown-polecat-Toast"},
+				{"status", ListOptions{Status: "in_progress", Priority: -1}, "gt-b"},
+				{"status list", ListOptions{Status: "open,closed", Priority: -1}, "gt-msg,gt-convoy,gt-c,gt-a,gt-gastown-polecat-Toast"},
+				{"priority", ListOptions{Status: "all", Priority: 1}, "gt-msg,gt-convoy,gt-c"},
+				{"label", ListOptions{Label: "gt:agent", Priority: -1}, "gt-gastown-polecat-Toast"},
+				{"deprecated type", ListOptions{Type: "agent", Priority: -1}, "gt-gastown-polecat-Toast"},
+				{"issue type", ListOptions{IssueType: "convoy", Status: "open", Priority: -1}, "gt-convoy"},
+				{"parent", ListOptions{Parent: "gt-convoy", Priority: -1}, "gt-a"},
+				{"assignee", ListOptions{Assignee: "gastown/polecats/Toast", Priority: -1}, "gt-a"},
+				{"no assignee", ListOptions{Status: "open", IssueType: "task", NoAssignee: true, Priority: -1}, ""},
+			}
+			for _, tt := range tests {
+				if got := ids(snap.List(tt.opts)); got != tt.want {
+					t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNativeIssueFields(t *testing.T) {
+	snap, ok := Native(nativeFixture(t, true, nativeIssues))
+	if !ok {
+		t.Fatal("Native returned false")
+	}
+
+	a, ok := snap.Get("gt-a")
+	if !ok {
+		t.Fatal("gt-a not found")
+	}
+	if a.Title != "First task" || a.Description != "body" || a.Type != "task" || a.Priority != 2 {
+		t.Errorf("unexpected issue: %+v", a)
+	}
+	if a.CreatedAt != "2025-01-02T10:00:00.5Z" || a.UpdatedAt != "2025-01-03T10:00:00Z" {
+		t.Errorf("timestamps = %q, %q", a.CreatedAt, a.UpdatedAt)
+	}
+	if strings.Join(a.Labels, ",") != "backend,urgent" {
+		t.Errorf("Labels = %v", a.Labels)
+	}
+	if a.Parent != "gt-convoy" || a.DependencyCount != 1 || a.DependentCount != 2 {
+		t.Errorf("Parent=%q deps=%d dependents=%d", a.Parent, a.DependencyCount, a.DependentCount)
+	}
+	if len(a.Dependencies) != 1 || a.Dependencies[0].ID != "gt-convoy" || a.Dependencies[0].DependencyType != "parent-child" {
+		t.Errorf("Dependencies = %+v", a.Dependencies)
+	}
+	if len(a.Dependents) != 2 {
+		t.Errorf("Dependents = %+v", a.Dependents)
+	}
+
+	agent, _ := snap.Get("gt-gastown-polecat-Toast")
+	if agent.HookBead != "gt-a" || agent.AgentState != "working" {
+		t.Errorf("agent slots = %q, %q", agent.HookBead, agent.AgentState)
+	}
+	msg, _ := snap.Get("gt-msg")
+	if !msg.Wisp {
+		t.Error("ephemeral message should have Wisp set")
+	}
+
+	if _, ok := snap.Get("gt-dead"); ok {
+		t.Error("tombstones should be invisible")
+	}
+	if got := snap.DependsOn("gt-convoy", "tracks"); strings.Join(got, ",") != "gt-a,external:beads:bd-xyz" {
+		t.Errorf("DependsOn = %v", got)
+	}
+
+	// Mutating a returned issue must not leak into the shared snapshot.
+	a.Labels[0] = "changed"
+	if again, _ := snap.Get("gt-a"); again.Labels[0] != "backend" {
+		t.Error("snapshot was mutated through a returned issue")
+	}
+}
+
+func TestNativeBeadsAPI(t *testing.T) {
+	beadsDir := nativeFixture(t, true, nativeIssues)
+	b := NewWithBeadsDir(t.TempDir(), beadsDir)
+
+	issue, err := b.Show("gt-b")
+	if err != nil || issue.Status != "in_progress" {
+		t.Fatalf("Show = %+v, %v", issue, err)
+	}
+	// Owned prefix: authoritative miss, no bd fallback needed.
+	if _, err := b.Show("gt-missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Show(missing) err = %v, want ErrNotFound", err)
+	}
+
+	agents, err := b.ListAgentBeads()
+	if err != nil || len(agents) != 1 || agents["gt-gastown-polecat-Toast"] == nil {
+		t.Errorf("ListAgentBeads = %v, %v", agents, err)
+	}
+
+	many, err := b.ShowMultiple([]string{"gt-a", "gt-c", "gt-missing"})
+	if err != nil || len(many) != 2 {
+		t.Errorf("ShowMultiple = %v, %v", many, err)
+	}
+}
+
+func TestNativeInvalidation(t *testing.T) {
+	beadsDir := nativeFixture(t, true, nativeIssues)
+	first, ok := Native(beadsDir)
+	if !ok {
+		t.Fatal("Native returned false")
+	}
+	if again, _ := Native(beadsDir); again != first {
+		t.Error("unchanged database should reuse the cached snapshot")
+	}
+
+	execSQLite(t, beadsDir, true, `INSERT INTO issues (id, title, status) VALUES ('gt-new', 'New', 'open');`)
+	second, ok := Native(beadsDir)
+	if !ok {
+		t.Fatal("Native returned false after write")
+	}
+	if _, ok := second.Get("gt-new"); !ok {
+		t.Error("snapshot not reloaded after write")
+	}
+
+	// Checkpointing moves pages from the WAL into the main file.
+	execSQLite(t, beadsDir, false, `PRAGMA wal_checkpoint(TRUNCATE);`)
+	third, ok := Native(beadsDir)
+	if !ok {
+		t.Fatal("Native returned false after checkpoint")
+	}
+	if _, ok := third.Get("gt-new"); !ok {
+		t.Error("issue lost after checkpoint")
+	}
+}
+
+func TestNativeCached(t *testing.T) {
+	resetNativeCache()
+	beadsDir := nativeFixture(t, true, nativeIssues)
+	if _, ok := NativeCached(beadsDir); ok {
+		t.Fatal("NativeCached loaded a snapshot on a cold cache")
+	}
+	snap, ok := Native(beadsDir)
+	if !ok {
+		t.Fatal("Native returned false")
+	}
+	if cached, ok := NativeCached(beadsDir); !ok || cached != snap {
+		t.Error("NativeCached should return the loaded snapshot")
+	}
+
+	execSQLite(t, beadsDir, true, `INSERT INTO issues (id, title, status) VALUES ('gt-new', 'New', 'open');`)
+	if _, ok := NativeCached(beadsDir); ok {
+		t.Error("NativeCached returned a stale snapshot after a write")
+	}
+}
+
+func TestNativeLargeDatabase(t *testing.T) {
+	// Enough rows to span many pages, and a description long enough to
+	// spill onto overflow pages.
+	var sb strings.Builder
+	long := strings.Repeat("lorem ipsum ", 2000)
+	sb.WriteString("BEGIN;\n")
+	for i := 0; i < 2000; i++ {
+		desc := ""
+		if i == 1234 {
+			desc = long
+		}
+		fmt.Fprintf(&sb, "INSERT INTO issues (id, title, description, status, priority) VALUES ('gt-%d', 'Issue %d', '%s', 'open', %d);\n", i, i, desc, i%5)
+	}
+	sb.WriteString("COMMIT;\n")
+
+	snap, ok := Native(nativeFixture(t, true, sb.String()))
+	if !ok {
+		t.Fatal("Native returned false")
+	}
+	if got := len(snap.List(ListOptions{Priority: -1})); got != 2000 {
+		t.Errorf("List returned %d issues, want 2000", got)
+	}
+	issue, ok := snap.Get("gt-1234")
+	if !ok || issue.Description != long {
+		t.Errorf("long description not read back (len %d)", len(issue.Description))
+	}
+}
+
+func TestNativeDisabled(t *testing.T) {
+	beadsDir := nativeFixture(t, false, nativeIssues)
+	t.Setenv(NativeEnv, "0")
+	if _, ok := Native(beadsDir); ok {
+		t.Error("Native should be disabled by GT_BEADS_NATIVE=0")
+	}
+}
+
+func TestNativeNoDatabase(t *testing.T) {
+	if _, ok := Native(t.TempDir()); ok {
+		t.Error("Native should return false without a database")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, DatabaseFile), []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Native(dir); ok {
+		t.Error("Native should return false for a corrupt database")
+	}
+}
+
+func TestNativeUnknownSchema(t *testing.T) {
+	// A bd schema change the reader doesn't know must fall back to bd
+	// rather than return wrong results.
+	beadsDir := nativeFixture(t, false, nativeIssues+`ALTER TABLE issues RENAME COLUMN issue_type TO kind;`)
+	if _, ok := Native(beadsDir); ok {
+		t.Error("Native should return false when a required column is missing")
+	}
+}
+
+func TestNativeReadsCommittedOnly(t *testing.T) {
+	beadsDir := nativeFixture(t, true, nativeIssues)
+
+	// A writer in the middle of a transaction, as bd is during an update.
+	db, err := sql.Open("sqlite", filepath.Join(beadsDir, DatabaseFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.Exec(`INSERT INTO issues (id, title, status) VALUES ('gt-pending', 'Pending', 'open')`); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, ok := Native(beadsDir)
+	if !ok {
+		t.Fatal("Native returned false during a concurrent write")
+	}
+	if _, ok := snap.Get("gt-pending"); ok {
+		t.Error("snapshot includes an uncommitted row")
+	}
+	if _, ok := snap.Get("gt-a"); !ok {
+		t.Error("snapshot is missing committed rows")
+	}
+}
+
+// benchFixture builds a town-sized database: 30 agents with hooked work,
+// a few thousand issues, and an inbox per agent.
+func benchFixture(b *testing.B) string {
+	var sb strings.Builder
+	sb.WriteString("BEGIN;\n")
+	for i := 0; i < 3000; i++ {
+		fmt.Fprintf(&sb, "INSERT INTO issues (id, title, description, status, priority) VALUES ('gt-w%d', 'Work %d', '%s', 'open', %d);\n",
+			i, i, strings.Repeat("x", 400), i%5)
+	}
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&sb, "INSERT INTO issues (id, title, status, issue_type, hook_bead) VALUES ('gt-gastown-polecat-p%d', 'p%d', 'open', 'agent', 'gt-w%d');\n", i, i, i)
+		fmt.Fprintf(&sb, "INSERT INTO labels VALUES ('gt-gastown-polecat-p%d', 'gt:agent');\n", i)
+		for j := 0; j < 20; j++ {
+			fmt.Fprintf(&sb, "INSERT INTO issues (id, title, status, issue_type, assignee) VALUES ('gt-m%d-%d', 'Hello', 'open', 'message', 'gastown/polecats/p%d');\n", i, j, i)
+		}
+	}
+	sb.WriteString("COMMIT;\n")
+	return nativeFixture(b, true, sb.String())
+}
+
+// statusQueries approximates what gt status asks of beads: every agent
+// bead, then each agent's hooked work.
+func statusQueries(b *testing.B, bd *Beads) {
+	agents, err := bd.ListAgentBeads()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, agent := range agents {
+		if _, err := bd.Show(agent.HookBead); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// forkQuery runs one sqlite3 process for a query: the per-query process
+// cost the native reader removes, and a lower bound on forking bd.
+func forkQuery(b *testing.B, beadsDir, query string) {
+	cmd := exec.Command("sqlite3", "-json", filepath.Join(beadsDir, DatabaseFile), query)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		b.Fatalf("sqlite3: %v\n%s", err, out)
+	}
+}
+
+func resetNativeCache() {
+	nativeMu.Lock()
+	nativeCache = make(map[string]*nativeEntry)
+	nativeMu.Unlock()
+}
+
+func BenchmarkStatusQueries(b *testing.B) {
+	beadsDir := benchFixture(b)
+	bd := NewWithBeadsDir(b.TempDir(), beadsDir)
+
+	b.Run("native", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			statusQueries(b, bd)
+		}
+	})
+	b.Run("native-cold", func(b *testing.B) {
+		// Every gt invocation is a new process that reads the database once.
+		for i := 0; i < b.N; i++ {
+			resetNativeCache()
+			statusQueries(b, bd)
+		}
+	})
+	b.Run("fork-per-query", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			forkQuery(b, beadsDir, `SELECT * FROM issues WHERE issue_type = 'agent'`)
+			for a := 0; a < 30; a++ {
+				forkQuery(b, beadsDir, fmt.Sprintf(`SELECT * FROM issues WHERE id = 'gt-w%d'`, a))
+			}
+		}
+	})
+	b.Run("bd", func(b *testing.B) {
+		if _, err := exec.LookPath("bd"); err != nil {
+			b.Skip("bd not installed")
+		}
+		b.Setenv(NativeEnv, "0")
+		for i := 0; i < b.N; i++ {
+			statusQueries(b, bd)
+		}
+	})
+}
+
+// BenchmarkInboxQuery measures the query behind gt mail inbox.
+func BenchmarkInboxQuery(b *testing.B) {
+	beadsDir := benchFixture(b)
+	bd := NewWithBeadsDir(b.TempDir(), beadsDir)
+	opts := ListOptions{IssueType: "message", Assignee: "gastown/polecats/p7", Status: "open", Priority: -1}
+
+	b.Run("native", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if msgs, err := bd.List(opts); err != nil || len(msgs) != 20 {
+				b.Fatalf("List = %d, %v", len(msgs), err)
+			}
+		}
+	})
+	b.Run("native-cold", func(b *testing.B) {
+		// Every gt invocation is a new process that reads the database once.
+		for i := 0; i < b.N; i++ {
+			resetNativeCache()
+			if _, err := bd.List(opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("fork-per-query", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			forkQuery(b, beadsDir, `SELECT * FROM issues WHERE issue_type = 'message' AND assignee = 'gastown/polecats/p7'`)
+		}
+	})
+	b.Run("bd", func(b *testing.B) {
+		if _, err := exec.LookPath("bd"); err != nil {
+			b.Skip("bd not installed")
+		}
+		b.Setenv(NativeEnv, "0")
+		for i := 0; i < b.N; i++ {
+			if _, err := bd.List(opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
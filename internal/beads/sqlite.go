@@ -0,0 +1,208 @@
+package beads
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure-Go SQLite driver, registered as "sqlite"
+)
+
+// The native reader opens the beads database read-only through SQLite
+// itself, so reads honor bd's locks and see only committed transactions.
+// Before reading, it checks that the tables and columns it relies on exist;
+// any other schema is an error so callers can fall back to bd rather than
+// return wrong results.
+
+var errSQLiteUnsupported = errors.New("unsupported beads database schema")
+
+// sqliteBusyTimeout bounds how long a read waits for a bd write to finish
+// before giving up and falling back to bd.
+const sqliteBusyTimeout = 2 * time.Second
+
+// requiredColumns lists, per table, the columns the native reader needs.
+// Optional columns (ephemeral/wisp, pinned, hook_bead, ...) read as NULL
+// when absent.
+var requiredColumns = map[string][]string{
+	"issues": {"id", "title", "description", "status", "priority", "issue_type",
+		"assignee", "created_at", "updated_at", "closed_at"},
+	"labels":       {"issue_id", "label"},
+	"dependencies": {"issue_id", "depends_on_id", "type"},
+}
+
+// sqliteTx is a read transaction: every query in it sees the same
+// committed state of the database.
+type sqliteTx struct {
+	tx     *sql.Tx
+	tables map[string]map[string]bool // table -> column names, filled by checkSchema
+}
+
+// openSQLite opens a read-only connection to a beads database and begins a
+// read transaction. Call close when done.
+func openSQLite(path string) (*sqliteTx, func(), error) {
+	dsn := (&url.URL{Scheme: "file", OmitHost: true, Path: path}).String() +
+		fmt.Sprintf("?mode=ro&_pragma=busy_timeout(%d)&_pragma=query_only(1)", sqliteBusyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	tx, err := db.Begin()
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, err
+	}
+	closeFn := func() {
+		_ = tx.Rollback()
+		_ = db.Close()
+	}
+
+	f := &sqliteTx{tx: tx, tables: make(map[string]map[string]bool)}
+	if err := f.checkSchema(); err != nil {
+		closeFn()
+		return nil, nil, err
+	}
+	return f, closeFn, nil
+}
+
+// checkSchema verifies the required tables and columns exist.
+func (f *sqliteTx) checkSchema() error {
+	for table, required := range requiredColumns {
+		columns, err := f.columns(table)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			return fmt.Errorf("%w: no table %s", errSQLiteUnsupported, table)
+		}
+		f.tables[table] = columns
+		for _, col := range required {
+			if !columns[col] {
+				return fmt.Errorf("%w: %s has no column %s", errSQLiteUnsupported, table, col)
+			}
+		}
+	}
+	return nil
+}
+
+// columns returns the column names of a table, or none if it doesn't exist.
+func (f *sqliteTx) columns(table string) (map[string]bool, error) {
+	rows, err := f.tx.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// sqliteRow is one table row with values addressable by column name.
+type sqliteRow struct {
+	index map[string]int
+	vals  []any
+}
+
+func (r *sqliteRow) get(col string) any {
+	if i, ok := r.index[col]; ok {
+		return r.vals[i]
+	}
+	return nil
+}
+
+// rows scans the given columns of a table and calls fn with each row.
+// Columns the table doesn't have read as NULL. Timestamps (*_at columns)
+// are returned as stored text. The row is reused between calls.
+func (f *sqliteTx) rows(name string, columns []string, fn func(row *sqliteRow)) error {
+	present, ok := f.tables[name]
+	if !ok {
+		var err error
+		if present, err = f.columns(name); err != nil {
+			return err
+		}
+		f.tables[name] = present
+	}
+
+	row := &sqliteRow{index: make(map[string]int, len(columns))}
+	var selected []string
+	for _, col := range columns {
+		if present[col] {
+			row.index[col] = len(selected)
+			expr := fmt.Sprintf(`"%s"`, col)
+			if strings.HasSuffix(col, "_at") {
+				// CAST drops the DATETIME declared type, so the driver
+				// returns the stored text instead of parsing a time.Time.
+				expr = fmt.Sprintf(`CAST(%s AS TEXT)`, expr)
+			}
+			selected = append(selected, expr)
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("%w: no table %s", errSQLiteUnsupported, name)
+	}
+	// Table and column names come from the fixed sets loadSnapshot reads.
+	query := fmt.Sprintf(`SELECT %s FROM "%s"`, strings.Join(selected, ", "), name)
+	rows, err := f.tx.Query(query) //nolint:gosec // G201: identifiers are constants
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	row.vals = make([]any, len(selected))
+	ptrs := make([]any, len(selected))
+	for i := range ptrs {
+		ptrs[i] = &row.vals[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		fn(row)
+	}
+	return rows.Err()
+}
+
+// sqlString converts a column value to a string ("" for NULL).
+func sqlString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case int64:
+		return fmt.Sprintf("%d", x)
+	case float64:
+		return fmt.Sprintf("%g", x)
+	}
+	return ""
+}
+
+// sqlInt converts a column value to an int (0 for NULL or non-numeric text).
+func sqlInt(v any) int {
+	switch x := v.(type) {
+	case int64:
+		return int(x)
+	case float64:
+		return int(x)
+	case bool:
+		if x {
+			return 1
+		}
+	case string:
+		var n int
+		_, _ = fmt.Sscanf(strings.TrimSpace(x), "%d", &n)
+		return n
+	}
+	return 0
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/ptyd"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -43,7 +44,7 @@ type Boot struct {
 	townRoot  string
 	bootDir   string // ~/gt/deacon/dogs/boot/
 	deaconDir string // ~/gt/deacon/
-	tmux      *tmux.Tmux
+	backend   tmux.SessionBackend
 	degraded  bool
 }
 
@@ -53,7 +54,7 @@ func New(townRoot string) *Boot {
 		townRoot:  townRoot,
 		bootDir:   filepath.Join(townRoot, "deacon", "dogs", "boot"),
 		deaconDir: filepath.Join(townRoot, "deacon"),
-		tmux:      tmux.NewTmux(),
+		backend:   ptyd.NewBackend(),
 		degraded:  os.Getenv("GT_DEGRADED") == "true",
 	}
 }
@@ -81,7 +82,7 @@ func (b *Boot) IsRunning() bool {
 
 // IsSessionAlive checks if the Boot tmux session exists.
 func (b *Boot) IsSessionAlive() bool {
-	has, err := b.tmux.HasSession(SessionName)
+	has, err := b.backend.HasSession(SessionName)
 	return err == nil && has
 }
 
@@ -162,7 +163,7 @@ func (b *Boot) Spawn(agentOverride string) error {
 func (b *Boot) spawnTmux(agentOverride string) error {
 	// Kill any stale session first
 	if b.IsSessionAlive() {
-		_ = b.tmux.KillSession(SessionName)
+		_ = b.backend.KillSession(SessionName)
 	}
 
 	// Ensure boot directory exists (it should have CLAUDE.md with Boot context)
@@ -185,7 +186,7 @@ func (b *Boot) spawnTmux(agentOverride string) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := b.tmux.NewSessionWithCommand(SessionName, b.bootDir, startCmd); err != nil {
+	if err := b.backend.NewSessionWithCommand(SessionName, b.bootDir, startCmd); err != nil {
 		return fmt.Errorf("creating boot session: %w", err)
 	}
 
@@ -195,7 +196,7 @@ func (b *Boot) spawnTmux(agentOverride string) error {
 		TownRoot: b.townRoot,
 	})
 	for k, v := range envVars {
-		_ = b.tmux.SetEnvironment(SessionName, k, v)
+		_ = b.backend.SetEnvironment(SessionName, k, v)
 	}
 
 	return nil
@@ -236,7 +237,7 @@ func (b *Boot) DeaconDir() string {
 	return b.deaconDir
 }
 
-// Tmux returns the tmux manager.
-func (b *Boot) Tmux() *tmux.Tmux {
-	return b.tmux
+// Backend returns the session backend (tmux unless GT_SESSION_BACKEND=pty).
+func (b *Boot) Backend() tmux.SessionBackend {
+	return b.backend
 }
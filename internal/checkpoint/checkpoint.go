@@ -38,6 +38,13 @@ type Checkpoint struct {
 	// Branch is the current git branch.
 	Branch string `json:"branch,omitempty"`
 
+	// SnapshotRef is the private ref holding a snapshot of uncommitted work
+	// (see Snapshot). Empty if the tree was clean or snapshotting was skipped.
+	SnapshotRef string `json:"snapshot_ref,omitempty"`
+
+	// SnapshotCommit is the commit SHA the snapshot ref pointed to.
+	SnapshotCommit string `json:"snapshot_commit,omitempty"`
+
 	// HookedBead is the bead ID on the agent's hook.
 	HookedBead string `json:"hooked_bead,omitempty"`
 
@@ -156,6 +163,20 @@ func Capture(polecatDir string) (*Checkpoint, error) {
 	return cp, nil
 }
 
+// WithSnapshot records uncommitted work as a git snapshot (see Snapshot) and
+// links it from the checkpoint. A clean tree leaves the checkpoint unchanged.
+func (cp *Checkpoint) WithSnapshot(workDir, polecat string) (*Checkpoint, error) {
+	snap, err := Snapshot(workDir, polecat)
+	if err != nil {
+		return cp, err
+	}
+	if snap != nil {
+		cp.SnapshotRef = snap.Ref
+		cp.SnapshotCommit = snap.Commit
+	}
+	return cp, nil
+}
+
 // WithMolecule adds molecule context to a checkpoint.
 func (cp *Checkpoint) WithMolecule(moleculeID, stepID, stepTitle string) *Checkpoint {
 	cp.MoleculeID = moleculeID
@@ -210,6 +231,10 @@ func (cp *Checkpoint) Summary() string {
 		parts = append(parts, fmt.Sprintf("branch: %s", cp.Branch))
 	}
 
+	if cp.SnapshotRef != "" {
+		parts = append(parts, "snapshot saved")
+	}
+
 	if len(parts) == 0 {
 		return "no significant state"
 	}
@@ -0,0 +1,252 @@
+package checkpoint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnapshotRefPrefix is the private ref namespace holding WIP snapshots.
+// Refs under refs/ (other than refs/worktree/) are shared by every worktree
+// of a repository, so snapshots survive the polecat worktree being removed.
+const SnapshotRefPrefix = "refs/gastown/checkpoints"
+
+// Default retention for snapshot pruning.
+const (
+	DefaultSnapshotKeep   = 10
+	DefaultSnapshotMaxAge = 7 * 24 * time.Hour
+)
+
+// ErrNoSnapshot is returned when a polecat has no snapshots to restore.
+var ErrNoSnapshot = errors.New("no checkpoint snapshot found")
+
+// SnapshotInfo describes a WIP snapshot stored under SnapshotRefPrefix.
+type SnapshotInfo struct {
+	// Ref is the full ref name (refs/gastown/checkpoints/<polecat>/<ts>).
+	Ref string `json:"ref"`
+
+	// Commit is the snapshot (working tree) commit SHA.
+	Commit string `json:"commit"`
+
+	// Polecat is the worker the snapshot belongs to.
+	Polecat string `json:"polecat"`
+
+	// Created is when the snapshot was taken.
+	Created time.Time `json:"created"`
+}
+
+// SnapshotRefName returns the ref for a polecat snapshot taken at ts.
+// Unix milliseconds keep refs unique and lexically sortable.
+func SnapshotRefName(polecat string, ts time.Time) string {
+	return fmt.Sprintf("%s/%s/%d", SnapshotRefPrefix, polecat, ts.UnixMilli())
+}
+
+// gitOutput runs git in dir with optional extra environment and returns
+// trimmed stdout.
+func gitOutput(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Snapshot records the index and working tree (including untracked, non-ignored
+// files) of workDir as a commit under a private ref, without touching the branch,
+// the index or the stash. The commit has the same shape as a stash entry
+// (worktree commit with parents HEAD and an index commit), so it can be
+// reapplied with git's stash machinery.
+//
+// Returns nil, nil if the working tree is clean.
+func Snapshot(workDir, polecat string) (*SnapshotInfo, error) {
+	status, err := gitOutput(workDir, nil, "status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		return nil, nil
+	}
+
+	head, err := gitOutput(workDir, nil, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	// Index tree: what's currently staged.
+	indexTree, err := gitOutput(workDir, nil, "write-tree")
+	if err != nil {
+		return nil, fmt.Errorf("writing index tree: %w", err)
+	}
+
+	// Worktree tree: stage everything into a throwaway copy of the index so
+	// the real index is left untouched.
+	indexPath, err := gitOutput(workDir, nil, "rev-parse", "--path-format=absolute", "--git-path", "index")
+	if err != nil {
+		return nil, err
+	}
+	tmpIndex, err := os.CreateTemp("", "gt-checkpoint-index-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp index: %w", err)
+	}
+	tmpPath := tmpIndex.Name()
+	_ = tmpIndex.Close()
+	defer os.Remove(tmpPath)
+
+	if data, err := os.ReadFile(indexPath); err == nil { //nolint:gosec // G304: path comes from git
+		if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+			return nil, fmt.Errorf("copying index: %w", err)
+		}
+	} else {
+		_ = os.Remove(tmpPath)
+	}
+
+	env := []string{"GIT_INDEX_FILE=" + tmpPath}
+	if _, err := gitOutput(workDir, env, "add", "-A"); err != nil {
+		return nil, fmt.Errorf("staging working tree: %w", err)
+	}
+	worktreeTree, err := gitOutput(workDir, env, "write-tree")
+	if err != nil {
+		return nil, fmt.Errorf("writing worktree tree: %w", err)
+	}
+
+	now := time.Now()
+	branch, _ := gitOutput(workDir, nil, "rev-parse", "--abbrev-ref", "HEAD")
+	identity := []string{
+		"GIT_AUTHOR_NAME=gastown", "GIT_AUTHOR_EMAIL=checkpoint@gastown.local",
+		"GIT_COMMITTER_NAME=gastown", "GIT_COMMITTER_EMAIL=checkpoint@gastown.local",
+	}
+
+	indexCommit, err := gitOutput(workDir, identity, "commit-tree", indexTree, "-p", head,
+		"-m", fmt.Sprintf("index on %s: checkpoint %s", branch, polecat))
+	if err != nil {
+		return nil, fmt.Errorf("committing index: %w", err)
+	}
+	snapCommit, err := gitOutput(workDir, identity, "commit-tree", worktreeTree, "-p", head, "-p", indexCommit,
+		"-m", fmt.Sprintf("WIP on %s: gastown checkpoint %s %s", branch, polecat, now.Format(time.RFC3339)))
+	if err != nil {
+		return nil, fmt.Errorf("committing worktree: %w", err)
+	}
+
+	ref := SnapshotRefName(polecat, now)
+	if _, err := gitOutput(workDir, nil, "update-ref", ref, snapCommit); err != nil {
+		return nil, fmt.Errorf("updating %s: %w", ref, err)
+	}
+
+	return &SnapshotInfo{Ref: ref, Commit: snapCommit, Polecat: polecat, Created: now}, nil
+}
+
+// ListSnapshots returns a polecat's snapshots in repoDir, newest first.
+// If polecat is empty, snapshots for all polecats are returned.
+func ListSnapshots(repoDir, polecat string) ([]SnapshotInfo, error) {
+	pattern := SnapshotRefPrefix + "/"
+	if polecat != "" {
+		pattern += polecat + "/"
+	}
+	out, err := gitOutput(repoDir, nil, "for-each-ref", "--format=%(refname) %(objectname)", pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var snaps []SnapshotInfo
+	for _, line := range strings.Split(out, "\n") {
+		ref, commit, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		rest := strings.TrimPrefix(ref, SnapshotRefPrefix+"/")
+		name, ts := path.Split(rest)
+		millis, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			continue
+		}
+		snaps = append(snaps, SnapshotInfo{
+			Ref:     ref,
+			Commit:  commit,
+			Polecat: strings.TrimSuffix(name, "/"),
+			Created: time.UnixMilli(millis),
+		})
+	}
+
+	sort.Slice(snaps, func(i, j int) bool {
+		return snaps[i].Created.After(snaps[j].Created)
+	})
+	return snaps, nil
+}
+
+// LatestSnapshot returns a polecat's most recent snapshot.
+func LatestSnapshot(repoDir, polecat string) (*SnapshotInfo, error) {
+	snaps, err := ListSnapshots(repoDir, polecat)
+	if err != nil {
+		return nil, err
+	}
+	if len(snaps) == 0 {
+		return nil, fmt.Errorf("%w for %s", ErrNoSnapshot, polecat)
+	}
+	return &snaps[0], nil
+}
+
+// Restore reapplies a snapshot (ref or commit) onto the worktree at
+// workDir. The snapshot is three-way merged against the commit it was taken
+// from, so it applies to a fresh worktree on a newer base as long as the
+// changes don't conflict. Staged state is restored when it applies cleanly.
+func Restore(workDir, snapshot string) error {
+	commit, err := gitOutput(workDir, nil, "rev-parse", "--verify", snapshot+"^{commit}")
+	if err != nil {
+		return fmt.Errorf("resolving snapshot %s: %w", snapshot, err)
+	}
+
+	if _, err := gitOutput(workDir, nil, "stash", "apply", "--index", commit); err == nil {
+		return nil
+	}
+	if _, err := gitOutput(workDir, nil, "stash", "apply", commit); err != nil {
+		return fmt.Errorf("applying snapshot %s: %w", snapshot, err)
+	}
+	return nil
+}
+
+// PruneSnapshots deletes a polecat's snapshots beyond the newest keep that
+// are also older than maxAge. A keep or maxAge of zero disables that limit.
+// If polecat is empty, every polecat's snapshots are pruned independently.
+// Returns the number of snapshots deleted.
+func PruneSnapshots(repoDir, polecat string, keep int, maxAge time.Duration) (int, error) {
+	snaps, err := ListSnapshots(repoDir, polecat)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[string]int)
+	cutoff := time.Now().Add(-maxAge)
+	pruned := 0
+	for _, s := range snaps {
+		seen[s.Polecat]++
+		if keep > 0 && seen[s.Polecat] <= keep {
+			continue
+		}
+		if maxAge > 0 && s.Created.After(cutoff) {
+			continue
+		}
+		if _, err := gitOutput(repoDir, nil, "update-ref", "-d", s.Ref); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
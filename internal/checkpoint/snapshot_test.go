@@ -0,0 +1,159 @@
+package checkpoint
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@test.com",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// initSnapshotRepo creates a repo with one commit and returns its path.
+func initSnapshotRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "tracked.txt"), []byte("base\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "tracked.txt")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func TestSnapshotCleanTree(t *testing.T) {
+	dir := initSnapshotRepo(t)
+
+	snap, err := Snapshot(dir, "Toast")
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if snap != nil {
+		t.Errorf("Snapshot of clean tree = %+v, want nil", snap)
+	}
+}
+
+func TestSnapshotPreservesWorkWithoutTouchingTree(t *testing.T) {
+	dir := initSnapshotRepo(t)
+	head := runGit(t, dir, "rev-parse", "HEAD")
+
+	// Staged edit, unstaged edit, and an untracked file
+	if err := os.WriteFile(filepath.Join(dir, "staged.txt"), []byte("staged\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "staged.txt")
+	if err := os.WriteFile(filepath.Join(dir, "tracked.txt"), []byte("base\nwip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("untracked\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	statusBefore := runGit(t, dir, "status", "--porcelain")
+
+	snap, err := Snapshot(dir, "Toast")
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if snap == nil || !strings.HasPrefix(snap.Ref, SnapshotRefPrefix+"/Toast/") {
+		t.Fatalf("Snapshot = %+v, want ref under %s/Toast/", snap, SnapshotRefPrefix)
+	}
+
+	// Branch, index, worktree and stash are untouched
+	if got := runGit(t, dir, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD moved: %s -> %s", head, got)
+	}
+	if got := runGit(t, dir, "status", "--porcelain"); got != statusBefore {
+		t.Errorf("status changed:\nbefore:\n%s\nafter:\n%s", statusBefore, got)
+	}
+	if got := runGit(t, dir, "stash", "list"); got != "" {
+		t.Errorf("stash list = %q, want empty", got)
+	}
+
+	// Snapshot contents include all three kinds of change
+	files := runGit(t, dir, "ls-tree", "-r", "--name-only", snap.Ref)
+	for _, want := range []string{"tracked.txt", "staged.txt", "new.txt"} {
+		if !strings.Contains(files, want) {
+			t.Errorf("snapshot tree missing %s: %s", want, files)
+		}
+	}
+
+	// Restore into a fresh worktree of the same repo
+	fresh := filepath.Join(t.TempDir(), "fresh")
+	runGit(t, dir, "worktree", "add", "-q", "-b", "fresh", fresh, "main")
+	if err := Restore(fresh, snap.Ref); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	for name, want := range map[string]string{
+		"tracked.txt": "base\nwip\n",
+		"staged.txt":  "staged\n",
+		"new.txt":     "untracked\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(fresh, name))
+		if err != nil {
+			t.Errorf("restored %s: %v", name, err)
+			continue
+		}
+		if string(data) != want {
+			t.Errorf("restored %s = %q, want %q", name, data, want)
+		}
+	}
+}
+
+func TestListAndPruneSnapshots(t *testing.T) {
+	dir := initSnapshotRepo(t)
+	commit := runGit(t, dir, "rev-parse", "HEAD")
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	for i := 0; i < 4; i++ {
+		runGit(t, dir, "update-ref", SnapshotRefName("Toast", old.Add(time.Duration(i)*time.Hour)), commit)
+	}
+	runGit(t, dir, "update-ref", SnapshotRefName("Toast", time.Now()), commit)
+	runGit(t, dir, "update-ref", SnapshotRefName("Cheedo", old), commit)
+
+	snaps, err := ListSnapshots(dir, "Toast")
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	if len(snaps) != 5 {
+		t.Fatalf("ListSnapshots = %d, want 5", len(snaps))
+	}
+	if !snaps[0].Created.After(snaps[1].Created) {
+		t.Error("snapshots not sorted newest first")
+	}
+
+	// Keep 2 per polecat, and only prune what's older than a week
+	pruned, err := PruneSnapshots(dir, "", 2, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("PruneSnapshots: %v", err)
+	}
+	if pruned != 3 {
+		t.Errorf("pruned = %d, want 3", pruned)
+	}
+	if snaps, _ := ListSnapshots(dir, "Toast"); len(snaps) != 2 {
+		t.Errorf("Toast snapshots after prune = %d, want 2", len(snaps))
+	}
+	if snaps, _ := ListSnapshots(dir, "Cheedo"); len(snaps) != 1 {
+		t.Errorf("Cheedo snapshots after prune = %d, want 1 (within keep)", len(snaps))
+	}
+
+	if _, err := LatestSnapshot(dir, "Nux"); !errors.Is(err, ErrNoSnapshot) {
+		t.Errorf("LatestSnapshot(Nux) err = %v, want ErrNoSnapshot", err)
+	}
+}
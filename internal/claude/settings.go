@@ -22,6 +22,11 @@ const (
 	// Interactive roles (mayor, crew) wait for user input, so UserPromptSubmit
 	// handles mail injection.
 	Interactive RoleType = "interactive"
+
+	// ReadOnly is for sessions that should answer questions without changing
+	// anything, like seance forks of a predecessor: edits are denied, only
+	// read-only commands are pre-approved and hooks are disabled.
+	ReadOnly RoleType = "readonly"
 )
 
 // RoleTypeFor returns the RoleType for a given role name.
@@ -63,6 +68,8 @@ func EnsureSettingsAt(workDir string, roleType RoleType, settingsDir, settingsFi
 	switch roleType {
 	case Autonomous:
 		templateName = "config/settings-autonomous.json"
+	case ReadOnly:
+		templateName = "config/settings-readonly.json"
 	default:
 		templateName = "config/settings-interactive.json"
 	}
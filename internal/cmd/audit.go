@@ -46,7 +46,12 @@ Examples:
   gt audit --actor=mayor                  # Show mayor's activity
   gt audit --since=24h                    # Show all activity in last 24h
   gt audit --actor=joe --since=1h         # Combined filters
-  gt audit --json                         # Output as JSON`,
+  gt audit --json                         # Output as JSON
+
+The events and town logs are hash-chained to make tampering evident:
+  gt audit verify                         # Detect edits, gaps, reordering
+  gt audit anchor                         # Commit chain heads to town git
+  gt audit keygen                         # Sign new entries with a town key`,
 	RunE: runAudit,
 }
 
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditchain"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/swarm"
+	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	auditVerifyJSON     bool
+	auditAnchorNoCommit bool
+)
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the audit logs' hash chains",
+	Long: `Verify that .events.jsonl and logs/town.log haven't been tampered with.
+
+Every line appended through gt is hash-chained to the line before it, and
+signed when the town has an audit key (gt audit keygen). verify walks each
+chain and reports:
+
+  edited      a line's content no longer matches its hash
+  gap         lines are missing
+  reordered   a line is out of sequence
+  unchained   a line was added without going through gt
+  bad-sig     a signature doesn't match the town's public key
+  unsigned    an unsigned line appears after signing started
+  truncated   a line recorded by an anchor is gone
+  rewritten   a line no longer matches the anchor committed for it
+
+Lines written before chaining was introduced are counted as legacy.
+Exits non-zero if any problem is found.`,
+	Args: cobra.NoArgs,
+	RunE: runAuditVerify,
+}
+
+var auditAnchorCmd = &cobra.Command{
+	Use:   "anchor",
+	Short: "Record the audit chain heads in the town git repo",
+	Long: `Append the current head of each audit chain to mayor/audit-anchors.jsonl
+and commit it to the town git repo. A chain rewritten after an anchor was
+committed no longer matches it, which gt audit verify reports.
+
+Chains that haven't moved since their last anchor are skipped. The daemon
+anchors hourly.`,
+	Args: cobra.NoArgs,
+	RunE: runAuditAnchor,
+}
+
+var auditKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Create the town's audit signing key",
+	Long: `Create an Ed25519 key pair for signing audit log entries.
+
+The private key is written outside the town, to
+<user config dir>/gastown/audit-keys/ (owner-only), so agents working in the
+town can't read or replace it. The public key goes to mayor/audit-key.pub,
+which should be committed so anyone can verify signatures with gt audit
+verify. Entries logged from then on are signed.`,
+	Args: cobra.NoArgs,
+	RunE: runAuditKeygen,
+}
+
+func init() {
+	auditVerifyCmd.Flags().BoolVar(&auditVerifyJSON, "json", false, "Output as JSON")
+	auditAnchorCmd.Flags().BoolVar(&auditAnchorNoCommit, "no-commit", false, "Record anchors without committing them")
+
+	auditCmd.AddCommand(auditVerifyCmd)
+	auditCmd.AddCommand(auditAnchorCmd)
+	auditCmd.AddCommand(auditKeygenCmd)
+}
+
+// auditLog is a hash-chained log in the town.
+type auditLog struct {
+	Name   string
+	Path   string
+	Format auditchain.Format
+}
+
+// auditLogs returns the town's chained logs.
+func auditLogs(townRoot string) []auditLog {
+	return []auditLog{
+		{Name: "events", Path: filepath.Join(townRoot, events.EventsFile), Format: auditchain.JSON},
+		{Name: "townlog", Path: townlog.LogPath(townRoot), Format: auditchain.Text},
+	}
+}
+
+// verifyAuditLogs verifies each chained log against the town key and anchors.
+func verifyAuditLogs(townRoot string) ([]*auditchain.Report, error) {
+	pub, err := auditchain.LoadPublicKey(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	anchors, err := auditchain.ReadAnchors(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading anchors: %w", err)
+	}
+
+	var reports []*auditchain.Report
+	for _, log := range auditLogs(townRoot) {
+		var r io.Reader = strings.NewReader("")
+		if f, err := os.Open(log.Path); err == nil { //nolint:gosec // G304: path is constructed internally
+			defer f.Close()
+			r = f
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+		rep := auditchain.Verify(log.Name, r, log.Format, pub)
+		rep.CheckAnchors(anchors)
+		reports = append(reports, rep)
+	}
+	return reports, nil
+}
+
+func runAuditVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	reports, err := verifyAuditLogs(townRoot)
+	if err != nil {
+		return err
+	}
+
+	ok := true
+	for _, rep := range reports {
+		ok = ok && rep.OK()
+	}
+
+	if auditVerifyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		for _, rep := range reports {
+			printAuditReport(rep)
+		}
+	}
+
+	if !ok {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// printAuditReport prints one log's verification result.
+func printAuditReport(rep *auditchain.Report) {
+	mark := style.Success.Render("✓")
+	if !rep.OK() {
+		mark = style.Error.Render("✗")
+	}
+	summary := fmt.Sprintf("%d chained", rep.Entries)
+	if rep.Signed > 0 {
+		summary += fmt.Sprintf(", %d signed", rep.Signed)
+	}
+	if rep.Legacy > 0 {
+		summary += fmt.Sprintf(", %d legacy", rep.Legacy)
+	}
+	if rep.Anchors > 0 {
+		summary += fmt.Sprintf(", %d anchor(s)", rep.Anchors)
+	}
+	fmt.Printf("%s %s %s\n", mark, style.Bold.Render(rep.Log), style.Dim.Render("("+summary+")"))
+	if rep.Head.Seq > 0 {
+		fmt.Printf("  head: seq %d %s\n", rep.Head.Seq, style.Dim.Render(swarm.ShortSHA(rep.Head.Hash)))
+	}
+	for _, p := range rep.Problems {
+		where := ""
+		if p.Line > 0 {
+			where = fmt.Sprintf("line %d: ", p.Line)
+		}
+		fmt.Printf("  %s %s%s\n", style.Error.Render(p.Kind), where, p.Detail)
+	}
+}
+
+// anchorAuditLogs records the head of each chain that has moved since its
+// last anchor. Returns the anchors written.
+func anchorAuditLogs(townRoot string, now time.Time) ([]auditchain.Anchor, error) {
+	existing, err := auditchain.ReadAnchors(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading anchors: %w", err)
+	}
+
+	var anchors []auditchain.Anchor
+	for _, log := range auditLogs(townRoot) {
+		head, err := auditchain.Head(log.Path, log.Format)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s head: %w", log.Name, err)
+		}
+		if head.Seq == 0 {
+			continue
+		}
+		if last, ok := auditchain.LastAnchor(existing, log.Name); ok && last.Seq == head.Seq && last.Hash == head.Hash {
+			continue
+		}
+		anchors = append(anchors, auditchain.Anchor{Timestamp: now.UTC(), Log: log.Name, Seq: head.Seq, Hash: head.Hash})
+	}
+	if len(anchors) == 0 {
+		return nil, nil
+	}
+	return anchors, auditchain.AppendAnchors(townRoot, anchors)
+}
+
+// errNotGitRepo means the town has no git repo to commit anchors to.
+var errNotGitRepo = errors.New("town is not a git repository (run gt git-init)")
+
+// commitAuditAnchors commits the anchors file to the town git repo.
+func commitAuditAnchors(townRoot string) error {
+	if _, err := os.Stat(filepath.Join(townRoot, ".git")); err != nil {
+		return errNotGitRepo
+	}
+	rel, err := filepath.Rel(townRoot, auditchain.AnchorsPath(townRoot))
+	if err != nil {
+		return err
+	}
+	for _, args := range [][]string{
+		{"add", "--", rel},
+		{"commit", "-m", "audit: anchor chain heads", "--", rel},
+	} {
+		gitCmd := exec.Command("git", args...)
+		gitCmd.Dir = townRoot
+		var stderr bytes.Buffer
+		gitCmd.Stderr = &stderr
+		if err := gitCmd.Run(); err != nil {
+			return fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+	}
+	return nil
+}
+
+func runAuditAnchor(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	anchors, err := anchorAuditLogs(townRoot, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(anchors) == 0 {
+		fmt.Printf("%s Audit chains unchanged since their last anchor\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, a := range anchors {
+		fmt.Printf("%s Anchored %s at seq %d %s\n", style.Bold.Render("✓"), a.Log, a.Seq, style.Dim.Render(swarm.ShortSHA(a.Hash)))
+	}
+
+	if auditAnchorNoCommit {
+		return nil
+	}
+	if err := commitAuditAnchors(townRoot); err != nil {
+		style.PrintWarning("anchors recorded but not committed: %v", err)
+	}
+	return nil
+}
+
+func runAuditKeygen(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	pub, err := auditchain.GenerateKey(townRoot)
+	if err != nil {
+		if errors.Is(err, auditchain.ErrKeyExists) {
+			return fmt.Errorf("%w (remove %s to rotate it)", err, auditchain.PublicKeyPath(townRoot))
+		}
+		return fmt.Errorf("generating audit key: %w", err)
+	}
+
+	fmt.Printf("%s Created audit signing key\n", style.Bold.Render("✓"))
+	if keyPath, err := auditchain.KeyPath(pub); err == nil {
+		fmt.Printf("  Private key: %s\n", keyPath)
+	}
+	fmt.Printf("  Public key:  %s\n", auditchain.PublicKeyPath(townRoot))
+	fmt.Printf("  %s\n", style.Dim.Render("Commit the public key so others can verify signatures."))
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -27,8 +28,12 @@ Checkpoint data includes:
 - Modified files list
 - Git branch and last commit
 - Timestamp
+- A git snapshot of uncommitted work (index and working tree)
 
-Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.`,
+Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.
+Snapshots are commits under refs/gastown/checkpoints/<polecat>/<ts> in the
+shared repo, so they survive the worktree being nuked or corrupted. They
+don't touch the branch, index or stash.`,
 }
 
 var checkpointWriteCmd = &cobra.Command{
@@ -41,7 +46,9 @@ This is typically called:
 - Periodically during long work sessions
 - Before handoff to another session
 
-The checkpoint captures git state, molecule progress, and hooked work.`,
+The checkpoint captures git state, molecule progress, and hooked work.
+Uncommitted changes are also saved as a git snapshot (skip with --no-snapshot),
+and snapshots beyond the retention limit are pruned.`,
 	RunE: runCheckpointWrite,
 }
 
@@ -59,16 +66,61 @@ var checkpointClearCmd = &cobra.Command{
 	RunE:  runCheckpointClear,
 }
 
+var checkpointListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List git snapshots of uncommitted work",
+	Long: `List checkpoint snapshots for the current worker, newest first.
+
+Use --polecat to list another worker's snapshots, or --all for every worker
+in this rig's repo.`,
+	RunE: runCheckpointList,
+}
+
+var checkpointRestoreCmd = &cobra.Command{
+	Use:   "restore [snapshot-ref]",
+	Short: "Reapply a snapshot of uncommitted work",
+	Long: `Reapply a checkpoint snapshot into a worktree.
+
+With no argument, restores the newest snapshot for the current worker (or
+--polecat). The snapshot is three-way merged against the commit it was taken
+from, so it applies to a fresh worktree on a newer base unless the changes
+conflict.
+
+Examples:
+  gt checkpoint restore                          # Latest snapshot into cwd
+  gt checkpoint restore --polecat Toast          # Toast's latest snapshot
+  gt checkpoint restore refs/gastown/checkpoints/Toast/1767225600000 --into ../fresh`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCheckpointRestore,
+}
+
+var checkpointPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old checkpoint snapshots",
+	Long: `Delete snapshots beyond the newest --keep per worker that are also
+older than --older-than. Applies to every worker in the repo.`,
+	RunE: runCheckpointPrune,
+}
+
 var (
-	checkpointNotes    string
-	checkpointMolecule string
-	checkpointStep     string
+	checkpointNotes      string
+	checkpointMolecule   string
+	checkpointStep       string
+	checkpointNoSnapshot bool
+	checkpointPolecat    string
+	checkpointAll        bool
+	checkpointInto       string
+	checkpointKeep       int
+	checkpointOlderThan  time.Duration
 )
 
 func init() {
 	checkpointCmd.AddCommand(checkpointWriteCmd)
 	checkpointCmd.AddCommand(checkpointReadCmd)
 	checkpointCmd.AddCommand(checkpointClearCmd)
+	checkpointCmd.AddCommand(checkpointListCmd)
+	checkpointCmd.AddCommand(checkpointRestoreCmd)
+	checkpointCmd.AddCommand(checkpointPruneCmd)
 
 	checkpointWriteCmd.Flags().StringVar(&checkpointNotes, "notes", "",
 		"Add notes to the checkpoint")
@@ -76,6 +128,23 @@ func init() {
 		"Override molecule ID (auto-detected if not specified)")
 	checkpointWriteCmd.Flags().StringVar(&checkpointStep, "step", "",
 		"Override step ID (auto-detected if not specified)")
+	checkpointWriteCmd.Flags().BoolVar(&checkpointNoSnapshot, "no-snapshot", false,
+		"Don't save a git snapshot of uncommitted work")
+
+	checkpointListCmd.Flags().StringVar(&checkpointPolecat, "polecat", "",
+		"Worker whose snapshots to list (default: current worker)")
+	checkpointListCmd.Flags().BoolVar(&checkpointAll, "all", false,
+		"List snapshots for every worker")
+
+	checkpointRestoreCmd.Flags().StringVar(&checkpointPolecat, "polecat", "",
+		"Worker whose latest snapshot to restore (default: current worker)")
+	checkpointRestoreCmd.Flags().StringVar(&checkpointInto, "into", "",
+		"Worktree to restore into (default: current directory)")
+
+	checkpointPruneCmd.Flags().IntVar(&checkpointKeep, "keep", checkpoint.DefaultSnapshotKeep,
+		"Snapshots to keep per worker regardless of age")
+	checkpointPruneCmd.Flags().DurationVar(&checkpointOlderThan, "older-than", checkpoint.DefaultSnapshotMaxAge,
+		"Only prune snapshots older than this")
 
 	rootCmd.AddCommand(checkpointCmd)
 }
@@ -140,6 +209,15 @@ func runCheckpointWrite(cmd *cobra.Command, args []string) error {
 		cp.WithHookedBead(hookedBead)
 	}
 
+	// Snapshot uncommitted work (non-fatal: the checkpoint is still useful without it)
+	if !checkpointNoSnapshot && roleInfo.Polecat != "" {
+		if _, err := cp.WithSnapshot(cwd, roleInfo.Polecat); err != nil {
+			fmt.Printf("%s Could not snapshot uncommitted work: %v\n", style.Dim.Render("⚠"), err)
+		} else if cp.SnapshotRef != "" {
+			_, _ = checkpoint.PruneSnapshots(cwd, roleInfo.Polecat, checkpoint.DefaultSnapshotKeep, checkpoint.DefaultSnapshotMaxAge)
+		}
+	}
+
 	// Write checkpoint
 	if err := checkpoint.Write(cwd, cp); err != nil {
 		return fmt.Errorf("writing checkpoint: %w", err)
@@ -147,6 +225,9 @@ func runCheckpointWrite(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Checkpoint written\n", style.Bold.Render("✓"))
 	fmt.Printf("  %s\n", cp.Summary())
+	if cp.SnapshotRef != "" {
+		fmt.Printf("  Snapshot: %s\n", cp.SnapshotRef)
+	}
 
 	return nil
 }
@@ -194,6 +275,9 @@ func runCheckpointRead(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  - %s\n", f)
 		}
 	}
+	if cp.SnapshotRef != "" {
+		fmt.Printf("Snapshot: %s\n", cp.SnapshotRef)
+	}
 	if cp.Notes != "" {
 		fmt.Printf("Notes: %s\n", cp.Notes)
 	}
@@ -218,6 +302,107 @@ func runCheckpointClear(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkpointWorker resolves the worker whose snapshots a command acts on:
+// --polecat if given, otherwise the current polecat or crew worker.
+func checkpointWorker(cwd string) (string, error) {
+	if checkpointPolecat != "" {
+		return checkpointPolecat, nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return "", fmt.Errorf("not in a Gas Town workspace (use --polecat)")
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return "", fmt.Errorf("detecting role: %w", err)
+	}
+	if roleInfo.Polecat == "" {
+		return "", fmt.Errorf("not in a polecat or crew worktree (use --polecat)")
+	}
+	return roleInfo.Polecat, nil
+}
+
+func runCheckpointList(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	worker := ""
+	if !checkpointAll {
+		if worker, err = checkpointWorker(cwd); err != nil {
+			return err
+		}
+	}
+
+	snaps, err := checkpoint.ListSnapshots(cwd, worker)
+	if err != nil {
+		return fmt.Errorf("listing snapshots: %w", err)
+	}
+	if len(snaps) == 0 {
+		fmt.Printf("%s No snapshots\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	for _, s := range snaps {
+		fmt.Printf("%s  %s  %s\n",
+			s.Created.Format("2006-01-02 15:04:05"),
+			s.Commit[:min(12, len(s.Commit))],
+			s.Ref)
+	}
+	return nil
+}
+
+func runCheckpointRestore(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	target := cwd
+	if checkpointInto != "" {
+		target = checkpointInto
+	}
+
+	var ref string
+	if len(args) > 0 {
+		ref = args[0]
+	} else {
+		worker, err := checkpointWorker(cwd)
+		if err != nil {
+			return err
+		}
+		snap, err := checkpoint.LatestSnapshot(target, worker)
+		if err != nil {
+			return err
+		}
+		ref = snap.Ref
+	}
+
+	if err := checkpoint.Restore(target, ref); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Restored %s\n", style.Bold.Render("✓"), ref)
+	fmt.Printf("  Into: %s\n", target)
+	return nil
+}
+
+func runCheckpointPrune(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	pruned, err := checkpoint.PruneSnapshots(cwd, "", checkpointKeep, checkpointOlderThan)
+	if err != nil {
+		return fmt.Errorf("pruning snapshots: %w", err)
+	}
+
+	fmt.Printf("%s Pruned %d snapshot(s)\n", style.Bold.Render("✓"), pruned)
+	return nil
+}
+
 // detectMoleculeContext tries to detect the current molecule and step from beads.
 func detectMoleculeContext(workDir string, ctx RoleInfo) (moleculeID, stepID, stepTitle string) {
 	b := beads.New(workDir)
@@ -17,6 +17,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/forecast"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -73,6 +74,7 @@ var (
 	convoyStrandedJSON bool
 	convoyCloseReason  string
 	convoyCloseNotify  string
+	convoyAfter        []string
 )
 
 var convoyCmd = &cobra.Command{
@@ -112,7 +114,12 @@ COMMANDS:
   add       Add issues to an existing convoy (reopens if closed)
   close     Close a convoy (manually, regardless of tracked issue status)
   status    Show convoy progress, tracked issues, and active workers
-  list      List convoys (the dashboard view)`,
+  list      List convoys (the dashboard view)
+  after     Order a convoy after other convoys (staged rollouts)
+  release   Dispatch queued work whose predecessor convoys have landed
+  deadline  Set or clear the date a convoy must land by
+  slipping  List convoys forecast to miss their deadline
+  notes     Generate release notes for a convoy`,
 }
 
 var convoyCreateCmd = &cobra.Command{
@@ -132,7 +139,9 @@ Examples:
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
   gt convoy create "Release prep" gt-abc --notify ops/      # notify ops/
   gt convoy create "Feature rollout" gt-a gt-b --owner mayor/ --notify ops/
-  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release`,
+  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
+  gt convoy create "Frontend" gt-ui --after hq-cv-back  # starts once hq-cv-back lands
+  gt convoy create "Release 2.1" gt-a gt-b --deadline 2026-11-01`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runConvoyCreate,
 }
@@ -184,6 +193,9 @@ var convoyCheckCmd = &cobra.Command{
 This handles cross-rig convoy completion: convoys in town beads tracking issues
 in rig beads won't auto-close via bd close alone. This command bridges that gap.
 
+Each convoy it closes gets release notes (see gt convoy notes), which are saved
+to the convoy bead and included in the completion notification.
+
 Can be run manually or by deacon patrol to ensure convoys close promptly.`,
 	RunE: runConvoyCheck,
 }
@@ -235,6 +247,8 @@ func init() {
 	convoyCreateCmd.Flags().StringVar(&convoyOwner, "owner", "", "Owner who requested convoy (gets completion notification)")
 	convoyCreateCmd.Flags().StringVar(&convoyNotify, "notify", "", "Additional address to notify on completion (default: mayor/ if flag used without value)")
 	convoyCreateCmd.Flags().Lookup("notify").NoOptDefVal = "mayor/"
+	convoyCreateCmd.Flags().StringSliceVar(&convoyAfter, "after", nil, "Convoy(s) that must land before this convoy's work starts")
+	convoyCreateCmd.Flags().StringVar(&convoyDeadline, "deadline", "", "When the convoy must land (RFC 3339, YYYY-MM-DD, or duration like 3d)")
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
@@ -308,6 +322,13 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
 	}
+	var deadline time.Time
+	if convoyDeadline != "" {
+		if deadline, err = forecast.ParseDeadline(convoyDeadline, time.Now()); err != nil {
+			return err
+		}
+		description = forecast.SetDeadline(description, deadline)
+	}
 
 	// Generate convoy ID with cv- prefix
 	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
@@ -337,6 +358,15 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 
 	// Notify address is stored in description (line 166-168) and read from there
 
+	// Add 'after' ordering on predecessor convoys (blocks dependency)
+	for _, pred := range convoyAfter {
+		depCmd := exec.Command("bd", "dep", "add", convoyID, pred, "--type=blocks")
+		depCmd.Dir = townBeads
+		if err := depCmd.Run(); err != nil {
+			style.PrintWarning("couldn't order after %s: %v", pred, err)
+		}
+	}
+
 	// Add 'tracks' relations for each tracked issue
 	trackedCount := 0
 	for _, issueID := range trackedIssues {
@@ -368,6 +398,12 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		fmt.Printf("  Molecule: %s\n", convoyMolecule)
 	}
+	if len(convoyAfter) > 0 {
+		fmt.Printf("  After:    %s\n", strings.Join(convoyAfter, ", "))
+	}
+	if !deadline.IsZero() {
+		fmt.Printf("  Deadline: %s\n", deadline.Local().Format("Mon Jan 2 15:04"))
+	}
 
 	fmt.Printf("\n  %s\n", style.Dim.Render("Convoy auto-closes when all tracked issues complete"))
 
@@ -472,6 +508,8 @@ func runConvoyCheck(cmd *cobra.Command, args []string) error {
 		for _, c := range closed {
 			fmt.Printf("  🚚 %s: %s\n", c.ID, c.Title)
 		}
+		// Closed convoys may free work queued behind them
+		releaseConvoyQueue(filepath.Dir(townBeads))
 	}
 
 	return nil
@@ -549,9 +587,11 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 		sendCloseNotification(convoyCloseNotify, convoyID, convoy.Title, reason)
 	} else {
 		// Check if convoy has a notify address in description
-		notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
+		notifyConvoyCompletion(townBeads, convoyID, convoy.Title, "")
 	}
 
+	releaseConvoyQueue(filepath.Dir(townBeads))
+
 	return nil
 }
 
@@ -652,6 +692,11 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 
 	// Check each convoy for stranded state
 	for _, convoy := range convoys {
+		// Work waiting on predecessor convoys isn't stranded
+		if pending, err := pendingPredecessors(townBeads, convoy.ID); err == nil && len(pending) > 0 {
+			continue
+		}
+
 		tracked := getTrackedIssues(townBeads, convoy.ID)
 		if len(tracked) == 0 {
 			continue
@@ -795,8 +840,10 @@ func checkAndCloseCompletedConvoys(townBeads string) ([]struct{ ID, Title string
 
 			closed = append(closed, struct{ ID, Title string }{convoy.ID, convoy.Title})
 
-			// Check if convoy has notify address and send notification
-			notifyConvoyCompletion(townBeads, convoy.ID, convoy.Title)
+			// Record release notes on the bead, then include them in the
+			// notification to owner and notify addresses
+			notes := publishConvoyNotes(townBeads, convoy.ID)
+			notifyConvoyCompletion(townBeads, convoy.ID, convoy.Title, notes)
 		}
 	}
 
@@ -804,7 +851,8 @@ func checkAndCloseCompletedConvoys(townBeads string) ([]struct{ ID, Title string
 }
 
 // notifyConvoyCompletion sends notifications to owner and any notify addresses.
-func notifyConvoyCompletion(townBeads, convoyID, title string) {
+// Release notes, if any, are appended to the message.
+func notifyConvoyCompletion(townBeads, convoyID, title, notes string) {
 	// Get convoy description to find owner and notify addresses
 	showArgs := []string{"show", convoyID, "--json"}
 	showCmd := exec.Command("bd", showArgs...)
@@ -827,6 +875,11 @@ func notifyConvoyCompletion(townBeads, convoyID, title string) {
 	desc := convoys[0].Description
 	notified := make(map[string]bool) // Track who we've notified to avoid duplicates
 
+	body := fmt.Sprintf("Convoy %s has completed.\n\nAll tracked issues are now closed.", convoyID)
+	if notes != "" {
+		body += "\n\n" + notes
+	}
+
 	for _, line := range strings.Split(desc, "\n") {
 		var addr string
 		if strings.HasPrefix(line, "Owner: ") {
@@ -839,7 +892,7 @@ func notifyConvoyCompletion(townBeads, convoyID, title string) {
 			// Send notification via gt mail
 			mailArgs := []string{"mail", "send", addr,
 				"-s", fmt.Sprintf("🚚 Convoy landed: %s", title),
-				"-m", fmt.Sprintf("Convoy %s has completed.\n\nAll tracked issues are now closed.", convoyID)}
+				"-m", body}
 			mailCmd := exec.Command("gt", mailArgs...)
 			_ = mailCmd.Run() // Best effort, ignore errors
 			notified[addr] = true
@@ -912,6 +965,17 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 
 	tracked := getTrackedIssues(townBeads, convoyID)
 
+	// Upstream chain (convoys this one runs after) and work queued behind it
+	upstream, _ := getConvoyUpstream(townBeads, convoyID)
+	queued := queuedForConvoy(filepath.Dir(townBeads), convoyID)
+
+	// Forecast when the remaining work lands, against any deadline
+	now := time.Now()
+	eta := convoyForecast(loadForecastModel(townBeads), tracked)
+	deadline := forecast.DeadlineFromDescription(convoy.Description)
+	remaining := remainingIssues(tracked)
+	deadlineStatus := forecast.Schedule(eta, deadline, remaining, now)
+
 	// Count completed
 	completed := 0
 	for _, t := range tracked {
@@ -928,6 +992,11 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked   []trackedIssueInfo `json:"tracked"`
 			Completed int                `json:"completed"`
 			Total     int                `json:"total"`
+			After     []convoyRef        `json:"after,omitempty"`
+			Queued    []queuedSling      `json:"queued,omitempty"`
+			Forecast  *forecast.Forecast `json:"forecast,omitempty"`
+			Deadline  *time.Time         `json:"deadline,omitempty"`
+			Schedule  string             `json:"schedule,omitempty"` // on track, at risk, slipping
 		}
 		out := jsonStatus{
 			ID:        convoy.ID,
@@ -936,6 +1005,13 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked:   tracked,
 			Completed: completed,
 			Total:     len(tracked),
+			After:     upstream,
+			Queued:    queued,
+			Forecast:  eta,
+			Schedule:  deadlineStatus,
+		}
+		if !deadline.IsZero() {
+			out.Deadline = &deadline
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -950,6 +1026,28 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	if convoy.ClosedAt != "" {
 		fmt.Printf("  Closed:    %s\n", convoy.ClosedAt)
 	}
+	if eta != nil {
+		fmt.Printf("  ETA:       %s %s\n", eta.ETA.Local().Format("Mon Jan 2 15:04"), style.Dim.Render(eta.FormatETA(now)))
+	}
+	if !deadline.IsZero() {
+		line := fmt.Sprintf("  Deadline:  %s", deadline.Local().Format("Mon Jan 2 15:04"))
+		if deadlineStatus != "" {
+			line += " " + formatDeadlineStatus(deadlineStatus)
+		}
+		fmt.Println(line)
+	}
+
+	if len(upstream) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Runs After:"))
+		printConvoyUpstream(upstream, "    ")
+	}
+	if len(queued) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Queued:"))
+		for _, q := range queued {
+			fmt.Printf("    ⏸ %s → %s %s\n", q.Issue, q.Target,
+				style.Dim.Render("(queued "+formatWorkerAge(time.Since(q.QueuedAt))+" ago)"))
+		}
+	}
 
 	if len(tracked) > 0 {
 		fmt.Printf("\n  %s\n", style.Bold.Render("Tracked Issues:"))
@@ -1053,15 +1151,11 @@ func runConvoyList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("listing convoys: %w", err)
 	}
 
-	var convoys []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		CreatedAt string `json:"created_at"`
-	}
+	var convoys []convoyListEntry
 	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
 		return fmt.Errorf("parsing convoy list: %w", err)
 	}
+	addConvoyListForecasts(townBeads, stdout.Bytes(), convoys)
 
 	if convoyListJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -1081,22 +1175,64 @@ func runConvoyList(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s\n\n", style.Bold.Render("Convoys"))
+	now := time.Now()
 	for i, c := range convoys {
 		status := formatConvoyStatus(c.Status)
-		fmt.Printf("  %d. 🚚 %s: %s %s\n", i+1, c.ID, c.Title, status)
+		line := fmt.Sprintf("  %d. 🚚 %s: %s %s", i+1, c.ID, c.Title, status)
+		if c.ETA != nil {
+			line += " " + style.Dim.Render("ETA "+c.ETA.FormatShort(now))
+		}
+		if c.Schedule != "" {
+			line += " " + formatDeadlineStatus(c.Schedule)
+		}
+		fmt.Println(line)
 	}
 	fmt.Printf("\nUse 'gt convoy status <id>' or 'gt convoy status <n>' for detailed view.\n")
 
 	return nil
 }
 
+// convoyListEntry is a convoy in gt convoy list, with its forecast when open.
+type convoyListEntry struct {
+	ID        string             `json:"id"`
+	Title     string             `json:"title"`
+	Status    string             `json:"status"`
+	CreatedAt string             `json:"created_at"`
+	ETA       *forecast.Forecast `json:"eta,omitempty"`
+	Schedule  string             `json:"schedule,omitempty"` // on track, at risk, slipping
+}
+
+// addConvoyListForecasts forecasts the open convoys in a list. listJSON is
+// the raw bd list output, which carries the deadlines in descriptions.
+func addConvoyListForecasts(townBeads string, listJSON []byte, convoys []convoyListEntry) {
+	var descs []struct {
+		Description string `json:"description"`
+	}
+	_ = json.Unmarshal(listJSON, &descs)
+
+	var model *forecast.Model
+	for i := range convoys {
+		c := &convoys[i]
+		if c.Status == "closed" {
+			continue
+		}
+		if model == nil {
+			if model = loadForecastModel(townBeads); model == nil {
+				return
+			}
+		}
+		tracked := getTrackedIssues(townBeads, c.ID)
+		c.ETA = convoyForecast(model, tracked)
+		if i < len(descs) {
+			remaining := remainingIssues(tracked)
+			deadline := forecast.DeadlineFromDescription(descs[i].Description)
+			c.Schedule = forecast.Schedule(c.ETA, deadline, remaining, model.Now)
+		}
+	}
+}
+
 // printConvoyTree displays convoys with their child issues in a tree format.
-func printConvoyTree(townBeads string, convoys []struct {
-	ID        string `json:"id"`
-	Title     string `json:"title"`
-	Status    string `json:"status"`
-	CreatedAt string `json:"created_at"`
-}) error {
+func printConvoyTree(townBeads string, convoys []convoyListEntry) error {
 	for _, c := range convoys {
 		// Get tracked issues for this convoy
 		tracked := getTrackedIssues(townBeads, c.ID)
@@ -0,0 +1,504 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Convoy ordering: "convoy B runs after convoy A" is stored as a blocks
+// dependency from B on A in town beads. Issues tracked by B can't be slung
+// until A has landed (closed); gt sling --queue parks them in the convoy
+// queue, and gt convoy release dispatches them once their predecessors land.
+
+var convoyAfterRemove bool
+
+var convoyAfterCmd = &cobra.Command{
+	Use:   "after <convoy-id> <predecessor-id> [predecessor-id...]",
+	Short: "Order a convoy after other convoys",
+	Long: `Make a convoy wait for other convoys to land before its work starts.
+
+While any predecessor is open, gt sling refuses to dispatch issues tracked
+by the convoy (or queues them with --queue). When the last predecessor
+closes, the daemon's convoy watcher dispatches the queued work.
+
+Examples:
+  gt convoy after hq-cv-front hq-cv-back          # frontend waits for backend
+  gt convoy after hq-cv-front hq-cv-back --remove # drop the ordering`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runConvoyAfter,
+}
+
+var convoyReleaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Dispatch queued work whose predecessor convoys have landed",
+	Long: `Dispatch work queued with gt sling --queue once every convoy it waits
+on has landed. Work that is still waiting stays queued.
+
+The daemon's convoy watcher runs this whenever a convoy closes; gt convoy
+check and gt convoy close run it too.`,
+	Args: cobra.NoArgs,
+	RunE: runConvoyRelease,
+}
+
+func init() {
+	convoyAfterCmd.Flags().BoolVar(&convoyAfterRemove, "remove", false, "Remove the ordering instead of adding it")
+
+	convoyCmd.AddCommand(convoyAfterCmd)
+	convoyCmd.AddCommand(convoyReleaseCmd)
+}
+
+// convoyRef is a convoy in an after chain. After holds the convoy's own
+// predecessors when the chain is expanded.
+type convoyRef struct {
+	ID     string      `json:"id"`
+	Title  string      `json:"title"`
+	Status string      `json:"status"`
+	After  []convoyRef `json:"after,omitempty"`
+}
+
+// Landed reports whether the convoy has closed.
+func (c convoyRef) Landed() bool {
+	return c.Status == "closed" || c.Status == "tombstone"
+}
+
+// queryTownDB runs a query against the town beads database and decodes the
+// rows into v. A query with no rows leaves v untouched.
+func queryTownDB(townBeads, query string, v interface{}) error {
+	queryCmd := exec.Command("sqlite3", "-json", filepath.Join(townBeads, "beads.db"), query)
+	var stdout, stderr bytes.Buffer
+	queryCmd.Stdout = &stdout
+	queryCmd.Stderr = &stderr
+	if err := queryCmd.Run(); err != nil {
+		return fmt.Errorf("querying town beads: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil
+	}
+	return json.Unmarshal(stdout.Bytes(), v)
+}
+
+// sqlQuote quotes s as an SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// getConvoyPredecessors returns the convoys a convoy runs after.
+func getConvoyPredecessors(townBeads, convoyID string) ([]convoyRef, error) {
+	var preds []convoyRef
+	err := queryTownDB(townBeads, fmt.Sprintf(`
+		SELECT i.id, i.title, i.status
+		FROM dependencies d
+		JOIN issues i ON i.id = d.depends_on_id
+		WHERE d.issue_id = %s AND d.type = 'blocks' AND i.issue_type = 'convoy'
+		ORDER BY i.id
+	`, sqlQuote(convoyID)), &preds)
+	return preds, err
+}
+
+// getConvoyUpstream returns a convoy's predecessors with their own
+// predecessors expanded, all the way up the chain.
+func getConvoyUpstream(townBeads, convoyID string) ([]convoyRef, error) {
+	return expandUpstream(townBeads, convoyID, map[string]bool{convoyID: true})
+}
+
+func expandUpstream(townBeads, convoyID string, seen map[string]bool) ([]convoyRef, error) {
+	preds, err := getConvoyPredecessors(townBeads, convoyID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range preds {
+		if seen[preds[i].ID] {
+			continue // cycle guard; gt convoy after refuses cycles
+		}
+		seen[preds[i].ID] = true
+		if preds[i].After, err = expandUpstream(townBeads, preds[i].ID, seen); err != nil {
+			return nil, err
+		}
+	}
+	return preds, nil
+}
+
+// pendingPredecessors returns the predecessors of a convoy that haven't
+// landed yet.
+func pendingPredecessors(townBeads, convoyID string) ([]convoyRef, error) {
+	preds, err := getConvoyPredecessors(townBeads, convoyID)
+	if err != nil {
+		return nil, err
+	}
+	var pending []convoyRef
+	for _, p := range preds {
+		if !p.Landed() {
+			pending = append(pending, p)
+		}
+	}
+	return pending, nil
+}
+
+// getConvoysTracking returns the open convoys that track an issue.
+func getConvoysTracking(townBeads, issueID string) ([]string, error) {
+	var rows []struct {
+		ID string `json:"issue_id"`
+	}
+	err := queryTownDB(townBeads, fmt.Sprintf(`
+		SELECT DISTINCT d.issue_id
+		FROM dependencies d
+		JOIN issues i ON i.id = d.issue_id
+		WHERE d.type = 'tracks' AND i.issue_type = 'convoy' AND i.status != 'closed'
+		AND (d.depends_on_id = %s OR d.depends_on_id LIKE %s)
+		ORDER BY d.issue_id
+	`, sqlQuote(issueID), sqlQuote("%:"+issueID)), &rows)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	return ids, nil
+}
+
+// slingGate returns the convoy holding an issue back and the predecessors
+// it is waiting on, or "" when the issue may be dispatched.
+func slingGate(townBeads, issueID string) (string, []convoyRef, error) {
+	convoys, err := getConvoysTracking(townBeads, issueID)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, c := range convoys {
+		pending, err := pendingPredecessors(townBeads, c)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(pending) > 0 {
+			return c, pending, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// formatConvoyRefs renders convoys as "id (title)" for messages.
+func formatConvoyRefs(refs []convoyRef) string {
+	parts := make([]string, 0, len(refs))
+	for _, r := range refs {
+		parts = append(parts, fmt.Sprintf("%s (%s)", r.ID, r.Title))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// queuedSling is a sling held back until its convoy's predecessors land.
+// It keeps the sling's flags so the release dispatches the same sling.
+type queuedSling struct {
+	Issue    string    `json:"issue"`
+	Target   string    `json:"target"`
+	Convoy   string    `json:"convoy"` // convoy that was gated when queued
+	QueuedAt time.Time `json:"queued_at"`
+
+	// Formula is slung --on Issue. --var is formula-only, never queued.
+	Formula  string `json:"formula,omitempty"`
+	Args     string `json:"args,omitempty"`
+	Subject  string `json:"subject,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Agent    string `json:"agent,omitempty"`
+	Account  string `json:"account,omitempty"`
+	Create   bool   `json:"create,omitempty"`
+	Force    bool   `json:"force,omitempty"`
+	NoConvoy bool   `json:"no_convoy,omitempty"`
+}
+
+// slingArgv returns the gt arguments that dispatch the queued sling.
+func (q queuedSling) slingArgv() []string {
+	argv := []string{"sling", q.Issue, q.Target}
+	if q.Formula != "" {
+		argv = []string{"sling", q.Formula, "--on", q.Issue, q.Target}
+	}
+	for _, f := range []struct{ flag, value string }{
+		{"--args", q.Args},
+		{"--subject", q.Subject},
+		{"--message", q.Message},
+		{"--agent", q.Agent},
+		{"--account", q.Account},
+	} {
+		if f.value != "" {
+			argv = append(argv, f.flag, f.value)
+		}
+	}
+	if q.Create {
+		argv = append(argv, "--create")
+	}
+	if q.Force {
+		argv = append(argv, "--force")
+	}
+	if q.NoConvoy {
+		argv = append(argv, "--no-convoy")
+	}
+	return argv
+}
+
+// convoyQueuePath returns the town's queue of held-back slings.
+func convoyQueuePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "convoy-queue.json")
+}
+
+// lockConvoyQueue serializes queue updates between gt sling and releases.
+func lockConvoyQueue(townRoot string) (*flock.Flock, error) {
+	dir := filepath.Join(townRoot, constants.DirRuntime)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime dir: %w", err)
+	}
+	lock := flock.New(filepath.Join(dir, "convoy-queue.lock"))
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking convoy queue: %w", err)
+	}
+	return lock, nil
+}
+
+func loadConvoyQueue(townRoot string) ([]queuedSling, error) {
+	data, err := os.ReadFile(convoyQueuePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var queue []queuedSling
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("parsing convoy queue: %w", err)
+	}
+	return queue, nil
+}
+
+func saveConvoyQueue(townRoot string, queue []queuedSling) error {
+	if len(queue) == 0 {
+		if err := os.Remove(convoyQueuePath(townRoot)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return util.AtomicWriteJSON(convoyQueuePath(townRoot), queue)
+}
+
+// queueSling adds a held-back sling to the queue, replacing any earlier
+// entry for the same issue.
+func queueSling(townRoot string, q queuedSling) error {
+	lock, err := lockConvoyQueue(townRoot)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	queue, err := loadConvoyQueue(townRoot)
+	if err != nil {
+		return err
+	}
+	kept := queue[:0]
+	for _, e := range queue {
+		if e.Issue != q.Issue {
+			kept = append(kept, e)
+		}
+	}
+	return saveConvoyQueue(townRoot, append(kept, q))
+}
+
+// releaseQueuedSlings dispatches each queued sling whose issue is no longer
+// gated. Entries that are still gated, or whose dispatch fails, stay queued.
+func releaseQueuedSlings(townRoot string, dispatch func(queuedSling) error) ([]queuedSling, error) {
+	lock, err := lockConvoyQueue(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	queue, err := loadConvoyQueue(townRoot)
+	if err != nil || len(queue) == 0 {
+		return nil, err
+	}
+
+	townBeads := filepath.Join(townRoot, ".beads")
+	var released, kept []queuedSling
+	for _, q := range queue {
+		gate, _, err := slingGate(townBeads, q.Issue)
+		if err != nil || gate != "" {
+			kept = append(kept, q)
+			continue
+		}
+		if err := dispatch(q); err != nil {
+			style.PrintWarning("couldn't dispatch %s to %s: %v", q.Issue, q.Target, err)
+			kept = append(kept, q)
+			continue
+		}
+		released = append(released, q)
+	}
+	if len(released) == 0 {
+		return nil, nil
+	}
+	return released, saveConvoyQueue(townRoot, kept)
+}
+
+// dispatchQueuedSling runs gt sling for a released entry.
+func dispatchQueuedSling(townRoot string) func(queuedSling) error {
+	return func(q queuedSling) error {
+		gtPath, err := os.Executable()
+		if err != nil {
+			gtPath = "gt"
+		}
+		slingCmd := exec.Command(gtPath, q.slingArgv()...) //nolint:gosec // G204: args come from the town's own queue
+		slingCmd.Dir = townRoot
+		var out bytes.Buffer
+		slingCmd.Stdout = &out
+		slingCmd.Stderr = &out
+		if err := slingCmd.Run(); err != nil {
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			return fmt.Errorf("%w: %s", err, lines[len(lines)-1])
+		}
+		return nil
+	}
+}
+
+// releaseConvoyQueue dispatches released work and reports it. Used after a
+// convoy closes.
+func releaseConvoyQueue(townRoot string) {
+	released, err := releaseQueuedSlings(townRoot, dispatchQueuedSling(townRoot))
+	if err != nil {
+		style.PrintWarning("couldn't release queued work: %v", err)
+		return
+	}
+	for _, q := range released {
+		fmt.Printf("%s Dispatched queued %s to %s\n", style.Bold.Render("▶"), q.Issue, q.Target)
+	}
+}
+
+func runConvoyAfter(cmd *cobra.Command, args []string) error {
+	convoyID := args[0]
+	predecessors := args[1:]
+
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range append([]string{convoyID}, predecessors...) {
+		var rows []struct {
+			Type string `json:"issue_type"`
+		}
+		if err := queryTownDB(townBeads, fmt.Sprintf(`SELECT issue_type FROM issues WHERE id = %s`, sqlQuote(id)), &rows); err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return fmt.Errorf("convoy '%s' not found", id)
+		}
+		if rows[0].Type != "convoy" {
+			return fmt.Errorf("'%s' is not a convoy (type: %s)", id, rows[0].Type)
+		}
+	}
+
+	for _, pred := range predecessors {
+		if convoyAfterRemove {
+			depCmd := exec.Command("bd", "dep", "remove", convoyID, pred)
+			depCmd.Dir = townBeads
+			if out, err := depCmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("removing ordering on %s: %w (%s)", pred, err, strings.TrimSpace(string(out)))
+			}
+			fmt.Printf("%s %s no longer waits for %s\n", style.Bold.Render("✓"), convoyID, pred)
+			continue
+		}
+
+		if err := checkConvoyCycle(townBeads, convoyID, pred); err != nil {
+			return err
+		}
+		depCmd := exec.Command("bd", "dep", "add", convoyID, pred, "--type=blocks")
+		depCmd.Dir = townBeads
+		if out, err := depCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("ordering after %s: %w (%s)", pred, err, strings.TrimSpace(string(out)))
+		}
+		fmt.Printf("%s %s runs after %s\n", style.Bold.Render("✓"), convoyID, pred)
+	}
+
+	if convoyAfterRemove {
+		// Removing the last pending predecessor can free queued work
+		releaseConvoyQueue(filepath.Dir(townBeads))
+	}
+	return nil
+}
+
+// checkConvoyCycle refuses an ordering that would make a convoy wait on
+// itself.
+func checkConvoyCycle(townBeads, convoyID, pred string) error {
+	if pred == convoyID {
+		return fmt.Errorf("convoy %s can't run after itself", convoyID)
+	}
+	upstream, err := getConvoyUpstream(townBeads, pred)
+	if err != nil {
+		return err
+	}
+	var walk func([]convoyRef) bool
+	walk = func(refs []convoyRef) bool {
+		for _, r := range refs {
+			if r.ID == convoyID || walk(r.After) {
+				return true
+			}
+		}
+		return false
+	}
+	if walk(upstream) {
+		return fmt.Errorf("%s already runs after %s; ordering it after %s would be a cycle", pred, convoyID, pred)
+	}
+	return nil
+}
+
+func runConvoyRelease(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	townRoot := filepath.Dir(townBeads)
+
+	released, err := releaseQueuedSlings(townRoot, dispatchQueuedSling(townRoot))
+	if err != nil {
+		return err
+	}
+	if len(released) == 0 {
+		fmt.Println("No queued work ready to dispatch.")
+		return nil
+	}
+	fmt.Printf("%s Dispatched %d queued issue(s):\n", style.Bold.Render("✓"), len(released))
+	for _, q := range released {
+		fmt.Printf("  ▶ %s → %s %s\n", q.Issue, q.Target, style.Dim.Render("(after "+q.Convoy+")"))
+	}
+	return nil
+}
+
+// printConvoyUpstream prints a convoy's after chain as an indented tree.
+func printConvoyUpstream(refs []convoyRef, indent string) {
+	for _, r := range refs {
+		status := "○"
+		if r.Landed() {
+			status = "✓"
+		}
+		fmt.Printf("%s%s %s: %s %s\n", indent, status, r.ID, r.Title, style.Dim.Render("["+r.Status+"]"))
+		printConvoyUpstream(r.After, indent+"  ")
+	}
+}
+
+// queuedForConvoy returns the queued slings held back by a convoy.
+func queuedForConvoy(townRoot, convoyID string) []queuedSling {
+	queue, _ := loadConvoyQueue(townRoot)
+	var out []queuedSling
+	for _, q := range queue {
+		if q.Convoy == convoyID {
+			out = append(out, q)
+		}
+	}
+	return out
+}
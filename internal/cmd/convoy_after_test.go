@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setupConvoyTown creates a town whose beads DB has just the tables the
+// convoy ordering queries read.
+func setupConvoyTown(t *testing.T, statements string) string {
+	t.Helper()
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	townRoot := t.TempDir()
+	townBeads := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(townBeads, 0755); err != nil {
+		t.Fatal(err)
+	}
+	schema := `
+		CREATE TABLE issues (id TEXT PRIMARY KEY, title TEXT, status TEXT, issue_type TEXT);
+		CREATE TABLE dependencies (issue_id TEXT, depends_on_id TEXT, type TEXT);
+	`
+	cmd := exec.Command("sqlite3", filepath.Join(townBeads, "beads.db"), schema+statements)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("creating beads.db: %v\n%s", err, out)
+	}
+	return townRoot
+}
+
+// Backend lands first, then frontend; docs waits on frontend.
+const rolloutTown = `
+	INSERT INTO issues VALUES ('hq-cv-back', 'Backend', 'open', 'convoy');
+	INSERT INTO issues VALUES ('hq-cv-front', 'Frontend', 'open', 'convoy');
+	INSERT INTO issues VALUES ('hq-cv-docs', 'Docs', 'open', 'convoy');
+	INSERT INTO issues VALUES ('hq-task', 'Not a convoy', 'open', 'task');
+	INSERT INTO dependencies VALUES ('hq-cv-front', 'hq-cv-back', 'blocks');
+	INSERT INTO dependencies VALUES ('hq-cv-docs', 'hq-cv-front', 'blocks');
+	INSERT INTO dependencies VALUES ('hq-cv-docs', 'hq-task', 'blocks');
+	INSERT INTO dependencies VALUES ('hq-cv-back', 'external:gt-api:gt-api-1', 'tracks');
+	INSERT INTO dependencies VALUES ('hq-cv-front', 'external:gt-ui:gt-ui-1', 'tracks');
+	INSERT INTO dependencies VALUES ('hq-cv-docs', 'hq-doc-1', 'tracks');
+`
+
+func closeConvoy(t *testing.T, townRoot, id string) {
+	t.Helper()
+	db := filepath.Join(townRoot, ".beads", "beads.db")
+	if out, err := exec.Command("sqlite3", db, "UPDATE issues SET status = 'closed' WHERE id = '"+id+"'").CombinedOutput(); err != nil {
+		t.Fatalf("closing %s: %v\n%s", id, err, out)
+	}
+}
+
+func TestConvoyUpstream(t *testing.T) {
+	townRoot := setupConvoyTown(t, rolloutTown)
+	townBeads := filepath.Join(townRoot, ".beads")
+
+	upstream, err := getConvoyUpstream(townBeads, "hq-cv-docs")
+	if err != nil {
+		t.Fatalf("getConvoyUpstream: %v", err)
+	}
+	if len(upstream) != 1 || upstream[0].ID != "hq-cv-front" {
+		t.Fatalf("upstream = %+v, want only hq-cv-front (tasks are not convoys)", upstream)
+	}
+	if after := upstream[0].After; len(after) != 1 || after[0].ID != "hq-cv-back" || after[0].Title != "Backend" {
+		t.Errorf("hq-cv-front upstream = %+v, want hq-cv-back", after)
+	}
+
+	if upstream, err := getConvoyUpstream(townBeads, "hq-cv-back"); err != nil || len(upstream) != 0 {
+		t.Errorf("hq-cv-back upstream = %+v, %v; want none", upstream, err)
+	}
+}
+
+func TestSlingGate(t *testing.T) {
+	townRoot := setupConvoyTown(t, rolloutTown)
+	townBeads := filepath.Join(townRoot, ".beads")
+
+	if convoy, _, err := slingGate(townBeads, "gt-api-1"); err != nil || convoy != "" {
+		t.Errorf("backend issue gated by %q (%v), want free", convoy, err)
+	}
+
+	convoy, pending, err := slingGate(townBeads, "gt-ui-1")
+	if err != nil {
+		t.Fatalf("slingGate: %v", err)
+	}
+	if convoy != "hq-cv-front" || len(pending) != 1 || pending[0].ID != "hq-cv-back" {
+		t.Errorf("slingGate(gt-ui-1) = %q, %+v; want hq-cv-front waiting on hq-cv-back", convoy, pending)
+	}
+
+	closeConvoy(t, townRoot, "hq-cv-back")
+	if convoy, _, _ := slingGate(townBeads, "gt-ui-1"); convoy != "" {
+		t.Errorf("frontend issue still gated by %s after backend landed", convoy)
+	}
+	if convoy, _, _ := slingGate(townBeads, "hq-doc-1"); convoy != "hq-cv-docs" {
+		t.Errorf("docs issue gated by %q, want hq-cv-docs", convoy)
+	}
+}
+
+func TestCheckConvoyCycle(t *testing.T) {
+	townRoot := setupConvoyTown(t, rolloutTown)
+	townBeads := filepath.Join(townRoot, ".beads")
+
+	if err := checkConvoyCycle(townBeads, "hq-cv-back", "hq-cv-docs"); err == nil {
+		t.Error("ordering backend after docs should be a cycle")
+	}
+	if err := checkConvoyCycle(townBeads, "hq-cv-back", "hq-cv-back"); err == nil {
+		t.Error("a convoy can't run after itself")
+	}
+	if err := checkConvoyCycle(townBeads, "hq-cv-docs", "hq-cv-back"); err != nil {
+		t.Errorf("docs after backend: %v", err)
+	}
+}
+
+func TestReleaseQueuedSlings(t *testing.T) {
+	townRoot := setupConvoyTown(t, rolloutTown)
+
+	for _, q := range []queuedSling{
+		{Issue: "gt-ui-1", Target: "gastown", Convoy: "hq-cv-front", QueuedAt: time.Now()},
+		{Issue: "hq-doc-1", Target: "gastown", Convoy: "hq-cv-docs", QueuedAt: time.Now()},
+		{Issue: "gt-ui-1", Target: "frontend", Convoy: "hq-cv-front", QueuedAt: time.Now()},
+	} {
+		if err := queueSling(townRoot, q); err != nil {
+			t.Fatalf("queueSling: %v", err)
+		}
+	}
+	if queue, _ := loadConvoyQueue(townRoot); len(queue) != 2 {
+		t.Fatalf("queue = %+v, want re-queued issue replaced", queue)
+	}
+
+	var dispatched []string
+	dispatch := func(q queuedSling) error {
+		dispatched = append(dispatched, q.Issue+"→"+q.Target)
+		return nil
+	}
+
+	if released, err := releaseQueuedSlings(townRoot, dispatch); err != nil || len(released) != 0 {
+		t.Fatalf("release before backend landed = %+v, %v", released, err)
+	}
+
+	closeConvoy(t, townRoot, "hq-cv-back")
+	released, err := releaseQueuedSlings(townRoot, dispatch)
+	if err != nil {
+		t.Fatalf("releaseQueuedSlings: %v", err)
+	}
+	if len(released) != 1 || len(dispatched) != 1 || dispatched[0] != "gt-ui-1→frontend" {
+		t.Errorf("released %+v, dispatched %v; want gt-ui-1 to frontend", released, dispatched)
+	}
+	if got := queuedForConvoy(townRoot, "hq-cv-docs"); len(got) != 1 {
+		t.Errorf("docs work should still be queued: %+v", got)
+	}
+
+	// A failed dispatch stays queued
+	closeConvoy(t, townRoot, "hq-cv-front")
+	failing := func(queuedSling) error { return errors.New("no capacity") }
+	if released, _ := releaseQueuedSlings(townRoot, failing); len(released) != 0 {
+		t.Errorf("failed dispatch reported released: %+v", released)
+	}
+	if released, _ := releaseQueuedSlings(townRoot, dispatch); len(released) != 1 {
+		t.Errorf("retry released %+v, want docs work", released)
+	}
+	if _, err := os.Stat(convoyQueuePath(townRoot)); !os.IsNotExist(err) {
+		t.Error("empty queue file should be removed")
+	}
+}
+
+func TestQueuedSlingKeepsFlags(t *testing.T) {
+	townRoot := setupConvoyTown(t, rolloutTown)
+
+	saved := []string{slingArgs, slingSubject, slingMessage, slingAgent, slingAccount}
+	savedBools := []bool{slingQueue, slingCreate, slingForce, slingDryRun}
+	t.Cleanup(func() {
+		slingArgs, slingSubject, slingMessage, slingAgent, slingAccount = saved[0], saved[1], saved[2], saved[3], saved[4]
+		slingQueue, slingCreate, slingForce, slingDryRun = savedBools[0], savedBools[1], savedBools[2], savedBools[3]
+	})
+	slingArgs, slingSubject, slingMessage = "patch release", "Ship it", "after the backend"
+	slingAgent, slingAccount = "codex", "work"
+	slingQueue, slingCreate, slingForce, slingDryRun = true, true, true, false
+
+	stop, err := checkSlingGate(townRoot, "gt-ui-1", "", []string{"frontend"})
+	if err != nil || !stop {
+		t.Fatalf("checkSlingGate = %v, %v; want queued", stop, err)
+	}
+	queue, err := loadConvoyQueue(townRoot)
+	if err != nil || len(queue) != 1 {
+		t.Fatalf("queue = %+v, %v", queue, err)
+	}
+
+	want := []string{"sling", "gt-ui-1", "frontend",
+		"--args", "patch release", "--subject", "Ship it", "--message", "after the backend",
+		"--agent", "codex", "--account", "work", "--create", "--force"}
+	if got := queue[0].slingArgv(); !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed argv = %q\nwant %q", got, want)
+	}
+
+	plain := queuedSling{Issue: "gt-ui-1", Target: "frontend"}
+	if got := plain.slingArgv(); !reflect.DeepEqual(got, []string{"sling", "gt-ui-1", "frontend"}) {
+		t.Errorf("argv without flags = %q", got)
+	}
+}
+
+func TestQueuedFormulaSlingRoundTrip(t *testing.T) {
+	townRoot := setupConvoyTown(t, rolloutTown)
+
+	savedArgs, savedQueue, savedNoConvoy, savedDryRun := slingArgs, slingQueue, slingNoConvoy, slingDryRun
+	t.Cleanup(func() {
+		slingArgs, slingQueue, slingNoConvoy, slingDryRun = savedArgs, savedQueue, savedNoConvoy, savedDryRun
+	})
+	slingArgs = "focus on auth"
+	slingQueue, slingNoConvoy, slingDryRun = true, true, false
+
+	// gt sling mol-review --on gt-ui-1 gastown --queue --no-convoy -a ...
+	stop, err := checkSlingGate(townRoot, "gt-ui-1", "mol-review", []string{"gastown"})
+	if err != nil || !stop {
+		t.Fatalf("checkSlingGate = %v, %v; want queued", stop, err)
+	}
+	queue, err := loadConvoyQueue(townRoot)
+	if err != nil || len(queue) != 1 {
+		t.Fatalf("queue = %+v, %v", queue, err)
+	}
+
+	want := []string{"sling", "mol-review", "--on", "gt-ui-1", "gastown",
+		"--args", "focus on auth", "--no-convoy"}
+	if got := queue[0].slingArgv(); !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed argv = %q\nwant %q", got, want)
+	}
+}
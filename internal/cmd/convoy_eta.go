@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/forecast"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+var (
+	convoyDeadline       string
+	convoySlippingJSON   bool
+	convoySlippingNotify bool
+)
+
+var convoyDeadlineCmd = &cobra.Command{
+	Use:   "deadline <convoy-id> <when|none>",
+	Short: "Set or clear a convoy's deadline",
+	Long: `Declare when a convoy must land. gt convoy status and list compare the
+forecast ETA with it, and the daemon alerts the convoy's owner when the
+forecast slips past it.
+
+<when> is an RFC 3339 time, a date (end of that day), or a duration from
+now. "none" clears the deadline.
+
+Examples:
+  gt convoy deadline hq-cv-abc 2026-11-01
+  gt convoy deadline hq-cv-abc 3d
+  gt convoy deadline hq-cv-abc none`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConvoyDeadline,
+}
+
+var convoySlippingCmd = &cobra.Command{
+	Use:   "slipping",
+	Short: "List convoys forecast to miss their deadline",
+	Long: `Forecast every open convoy with a deadline and list those at risk (the
+slow end of the forecast is past the deadline) or slipping (the expected
+ETA is past it, or the deadline has passed with work remaining).
+
+With --notify, the owner and notify addresses of each slipping convoy (or
+mayor/ if it has none) are mailed once per deadline. The daemon runs this
+on every heartbeat.`,
+	Args: cobra.NoArgs,
+	RunE: runConvoySlipping,
+}
+
+func init() {
+	convoySlippingCmd.Flags().BoolVar(&convoySlippingJSON, "json", false, "Output as JSON")
+	convoySlippingCmd.Flags().BoolVar(&convoySlippingNotify, "notify", false, "Mail owners of slipping convoys (once per deadline)")
+
+	convoyCmd.AddCommand(convoyDeadlineCmd)
+	convoyCmd.AddCommand(convoySlippingCmd)
+}
+
+// loadForecastModel loads the town's throughput history. Forecasts are
+// best-effort, so a failure just means no ETAs.
+func loadForecastModel(townBeads string) *forecast.Model {
+	model, err := forecast.Load(filepath.Dir(townBeads))
+	if err != nil {
+		return nil
+	}
+	return model
+}
+
+// convoyForecast forecasts a convoy from its tracked issues. Returns nil
+// when there is no model, nothing left to land, or no history.
+func convoyForecast(model *forecast.Model, tracked []trackedIssueInfo) *forecast.Forecast {
+	if model == nil {
+		return nil
+	}
+	items := make([]forecast.Item, 0, len(tracked))
+	for _, t := range tracked {
+		items = append(items, forecast.Item{ID: t.ID, Status: t.Status})
+	}
+	return model.Forecast(items)
+}
+
+// remainingIssues counts the tracked issues that haven't landed.
+func remainingIssues(tracked []trackedIssueInfo) int {
+	n := 0
+	for _, t := range tracked {
+		if t.Status != "closed" && t.Status != "tombstone" {
+			n++
+		}
+	}
+	return n
+}
+
+// formatDeadlineStatus styles a deadline status for terminal output.
+func formatDeadlineStatus(status string) string {
+	switch status {
+	case forecast.Slipping:
+		return style.Error.Render("⚠ " + status)
+	case forecast.AtRisk:
+		return style.Warning.Render(status)
+	case forecast.OnTrack:
+		return style.Success.Render(status)
+	}
+	return status
+}
+
+// convoyDescription fetches a convoy's description.
+func convoyDescription(townBeads, convoyID string) (string, error) {
+	showCmd := exec.Command("bd", "show", convoyID, "--json")
+	showCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	showCmd.Stdout = &stdout
+	if err := showCmd.Run(); err != nil {
+		return "", fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	var convoys []struct {
+		Type        string `json:"issue_type"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil || len(convoys) == 0 {
+		return "", fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	if convoys[0].Type != "convoy" {
+		return "", fmt.Errorf("'%s' is not a convoy (type: %s)", convoyID, convoys[0].Type)
+	}
+	return convoys[0].Description, nil
+}
+
+func runConvoyDeadline(cmd *cobra.Command, args []string) error {
+	convoyID := args[0]
+
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+
+	var deadline time.Time
+	if args[1] != "none" {
+		if deadline, err = forecast.ParseDeadline(args[1], time.Now()); err != nil {
+			return err
+		}
+	}
+
+	description, err := convoyDescription(townBeads, convoyID)
+	if err != nil {
+		return err
+	}
+	updateCmd := exec.Command("bd", "update", convoyID, "--description="+forecast.SetDeadline(description, deadline))
+	updateCmd.Dir = townBeads
+	if out, err := updateCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("updating convoy: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+
+	if deadline.IsZero() {
+		fmt.Printf("%s Cleared deadline of convoy %s\n", style.Bold.Render("✓"), convoyID)
+	} else {
+		fmt.Printf("%s Convoy %s must land by %s\n", style.Bold.Render("✓"), convoyID, deadline.Local().Format("Mon Jan 2 15:04"))
+	}
+	return nil
+}
+
+// slippingConvoy is a convoy forecast to miss its deadline.
+type slippingConvoy struct {
+	ID        string             `json:"id"`
+	Title     string             `json:"title"`
+	Deadline  time.Time          `json:"deadline"`
+	Status    string             `json:"status"` // forecast.AtRisk or forecast.Slipping
+	Remaining int                `json:"remaining"`
+	Forecast  *forecast.Forecast `json:"forecast,omitempty"`
+	notify    []string
+}
+
+// findSlippingConvoys forecasts every open convoy with a deadline.
+func findSlippingConvoys(townBeads string) ([]slippingConvoy, error) {
+	listCmd := exec.Command("bd", "list", "--type=convoy", "--status=open", "--json")
+	listCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	listCmd.Stdout = &stdout
+	if err := listCmd.Run(); err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
+	}
+	var convoys []struct {
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
+		return nil, fmt.Errorf("parsing convoy list: %w", err)
+	}
+
+	var model *forecast.Model
+	now := time.Now()
+	var slipping []slippingConvoy
+	for _, c := range convoys {
+		deadline := forecast.DeadlineFromDescription(c.Description)
+		if deadline.IsZero() {
+			continue
+		}
+		if model == nil {
+			if model = loadForecastModel(townBeads); model != nil {
+				now = model.Now
+			}
+		}
+		tracked := getTrackedIssues(townBeads, c.ID)
+		remaining := remainingIssues(tracked)
+		f := convoyForecast(model, tracked)
+		status := forecast.Schedule(f, deadline, remaining, now)
+		if status != forecast.AtRisk && status != forecast.Slipping {
+			continue
+		}
+		slipping = append(slipping, slippingConvoy{
+			ID:        c.ID,
+			Title:     c.Title,
+			Deadline:  deadline,
+			Status:    status,
+			Remaining: remaining,
+			Forecast:  f,
+			notify:    convoySubscribers(c.Description),
+		})
+	}
+	return slipping, nil
+}
+
+// convoySubscribers returns the owner and notify addresses in a convoy's
+// description.
+func convoySubscribers(desc string) []string {
+	var addrs []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(desc, "\n") {
+		var addr string
+		if v, ok := strings.CutPrefix(line, "Owner: "); ok {
+			addr = v
+		} else if v, ok := strings.CutPrefix(line, "Notify: "); ok {
+			addr = v
+		}
+		if addr = strings.TrimSpace(addr); addr != "" && !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func runConvoySlipping(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+
+	slipping, err := findSlippingConvoys(townBeads)
+	if err != nil {
+		return err
+	}
+
+	if convoySlippingNotify {
+		notifySlippingConvoys(filepath.Dir(townBeads), slipping)
+	}
+
+	if convoySlippingJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(slipping)
+	}
+
+	if len(slipping) == 0 {
+		fmt.Println("No convoys at risk of missing their deadline.")
+		return nil
+	}
+
+	now := time.Now()
+	fmt.Printf("%s %d convoy(s) at risk of missing their deadline:\n\n", style.Warning.Render("⚠"), len(slipping))
+	for _, s := range slipping {
+		fmt.Printf("  🚚 %s: %s %s\n", s.ID, s.Title, formatDeadlineStatus(s.Status))
+		fmt.Printf("     Deadline: %s\n", s.Deadline.Local().Format("Mon Jan 2 15:04"))
+		if s.Forecast != nil {
+			fmt.Printf("     ETA:      %s\n", s.Forecast.FormatETA(now))
+		}
+		fmt.Printf("     Remaining: %d issue(s)\n\n", s.Remaining)
+	}
+	return nil
+}
+
+// slipAlertsPath records which convoy deadlines have been alerted on.
+func slipAlertsPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "convoy-slips.json")
+}
+
+// notifySlippingConvoys mails the subscribers of each slipping convoy, once
+// per convoy deadline.
+func notifySlippingConvoys(townRoot string, slipping []slippingConvoy) {
+	alerted := make(map[string]time.Time)
+	if data, err := os.ReadFile(slipAlertsPath(townRoot)); err == nil { //nolint:gosec // G304: path is constructed internally
+		_ = json.Unmarshal(data, &alerted)
+	}
+
+	now := time.Now()
+	changed := false
+	for _, s := range slipping {
+		if s.Status != forecast.Slipping || alerted[s.ID].Equal(s.Deadline) {
+			continue
+		}
+		subject := fmt.Sprintf("⚠ Convoy slipping: %s", s.Title)
+		body := fmt.Sprintf("Convoy %s is forecast to miss its deadline.\n\nDeadline:  %s\nRemaining: %d issue(s)\n",
+			s.ID, s.Deadline.Local().Format(time.RFC1123), s.Remaining)
+		if s.Forecast != nil {
+			body += fmt.Sprintf("ETA:       %s (%s)\n", s.Forecast.ETA.Local().Format(time.RFC1123), s.Forecast.FormatETA(now))
+		}
+		body += fmt.Sprintf("\nSee: gt convoy status %s", s.ID)
+
+		recipients := s.notify
+		if len(recipients) == 0 {
+			recipients = []string{"mayor/"}
+		}
+		for _, addr := range recipients {
+			mailCmd := exec.Command("gt", "mail", "send", addr, "-s", subject, "-m", body)
+			mailCmd.Dir = townRoot
+			if err := mailCmd.Run(); err != nil {
+				style.PrintWarning("couldn't alert %s: %v", addr, err)
+			}
+		}
+		alerted[s.ID] = s.Deadline
+		changed = true
+	}
+
+	if changed {
+		if err := os.MkdirAll(filepath.Dir(slipAlertsPath(townRoot)), 0755); err == nil {
+			_ = util.AtomicWriteJSON(slipAlertsPath(townRoot), alerted)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvoySubscribers(t *testing.T) {
+	desc := "Convoy tracking 3 issues\nOwner: mayor/\nNotify: gastown/crew/max\nNotify: mayor/\nDeadline: 2026-11-01T09:00:00Z"
+	want := []string{"mayor/", "gastown/crew/max"}
+	if got := convoySubscribers(desc); !reflect.DeepEqual(got, want) {
+		t.Errorf("convoySubscribers = %v, want %v", got, want)
+	}
+	if got := convoySubscribers("Convoy tracking 1 issues"); len(got) != 0 {
+		t.Errorf("convoySubscribers without owner = %v, want none", got)
+	}
+}
+
+func TestRemainingIssues(t *testing.T) {
+	tracked := []trackedIssueInfo{
+		{ID: "gt-a", Status: "closed"},
+		{ID: "gt-b", Status: "in_progress"},
+		{ID: "gt-c", Status: "open"},
+		{ID: "gt-d", Status: "tombstone"},
+	}
+	if got := remainingIssues(tracked); got != 2 {
+		t.Errorf("remainingIssues = %d, want 2", got)
+	}
+}
@@ -0,0 +1,514 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/protocol"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/swarm"
+)
+
+var (
+	convoyNotesJSON   bool
+	convoyNotesSave   bool
+	convoyNotesOutput string
+)
+
+var convoyNotesCmd = &cobra.Command{
+	Use:   "notes <convoy-id>",
+	Short: "Generate release notes for a convoy",
+	Long: `Generate release notes for a convoy: its tracked issues grouped by type,
+the merge requests that landed them (with merge commits), contributing
+agents, session cost, and issues closed as won't-fix.
+
+Release notes are generated automatically when gt convoy check closes a
+landed convoy: they are saved to the convoy bead's notes, included in the
+completion mail, and written to <rig>/<release_notes_dir>/<convoy-id>.md
+(and .json) for each rig that merged work, if the rig sets the
+release_notes_dir config.
+
+Examples:
+  gt convoy notes hq-cv-abc               # Markdown to stdout
+  gt convoy notes hq-cv-abc --json        # JSON to stdout
+  gt convoy notes hq-cv-abc --save        # Also store on the convoy bead and rigs
+  gt convoy notes hq-cv-abc -o NOTES.md   # Write to a file (.json for JSON)`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyNotes,
+}
+
+func init() {
+	convoyNotesCmd.Flags().BoolVar(&convoyNotesJSON, "json", false, "Output as JSON")
+	convoyNotesCmd.Flags().BoolVar(&convoyNotesSave, "save", false, "Store on the convoy bead and in rigs with release_notes_dir")
+	convoyNotesCmd.Flags().StringVarP(&convoyNotesOutput, "output", "o", "", "Write to a file instead of stdout (JSON if it ends in .json)")
+
+	convoyCmd.AddCommand(convoyNotesCmd)
+}
+
+// convoyNotes is the release-notes document for a landed convoy.
+type convoyNotes struct {
+	ConvoyID     string                   `json:"convoy_id"`
+	Title        string                   `json:"title"`
+	Status       string                   `json:"status"`
+	CreatedAt    string                   `json:"created_at,omitempty"`
+	LandedAt     string                   `json:"landed_at,omitempty"`
+	Groups       []notesGroup             `json:"groups"`
+	Merges       []protocol.MergedPayload `json:"merges"`
+	WontFix      []notesIssue             `json:"wont_fix,omitempty"`
+	Contributors []string                 `json:"contributors"`
+	CostUSD      float64                  `json:"cost_usd"`
+	Sessions     int                      `json:"sessions"`
+}
+
+// notesGroup is the issues of one type.
+type notesGroup struct {
+	Type   string       `json:"type"`
+	Issues []notesIssue `json:"issues"`
+}
+
+// notesIssue is a tracked issue in the release notes.
+type notesIssue struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	CloseReason string `json:"close_reason,omitempty"`
+}
+
+// notesTypeOrder is the order issue types appear in, with their headings.
+// Other types follow alphabetically.
+var notesTypeOrder = []struct{ Type, Heading string }{
+	{"feature", "Features"},
+	{"bug", "Bug Fixes"},
+	{"task", "Tasks"},
+	{"chore", "Chores"},
+	{"epic", "Epics"},
+}
+
+// isWontFix reports whether a close reason means the work was dropped.
+func isWontFix(reason string) bool {
+	r := strings.ToLower(reason)
+	for _, marker := range []string{"won't fix", "wont fix", "wontfix", "won't do", "not planned", "will not fix"} {
+		if strings.Contains(r, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// notesConvoy is the convoy bead as bd show reports it.
+type notesConvoy struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Type      string `json:"issue_type"`
+	CreatedAt string `json:"created_at"`
+	ClosedAt  string `json:"closed_at"`
+}
+
+// buildConvoyNotes assembles release notes from a convoy's tracked issues,
+// their close reasons, the merges that landed them, and session costs.
+func buildConvoyNotes(convoy notesConvoy, tracked []trackedIssueInfo, reasons map[string]string,
+	merges []protocol.MergedPayload, costs []CostEntry) *convoyNotes {
+	notes := &convoyNotes{
+		ConvoyID:     convoy.ID,
+		Title:        convoy.Title,
+		Status:       convoy.Status,
+		CreatedAt:    convoy.CreatedAt,
+		LandedAt:     convoy.ClosedAt,
+		Groups:       []notesGroup{},
+		Merges:       []protocol.MergedPayload{},
+		Contributors: []string{},
+	}
+
+	byType := make(map[string][]notesIssue)
+	trackedIDs := make(map[string]bool)
+	contributors := make(map[string]bool)
+	for _, t := range tracked {
+		trackedIDs[t.ID] = true
+		issue := notesIssue{ID: t.ID, Title: t.Title, Status: t.Status, CloseReason: reasons[t.ID]}
+		if t.Status == "closed" && isWontFix(issue.CloseReason) {
+			notes.WontFix = append(notes.WontFix, issue)
+			continue
+		}
+		issueType := t.IssueType
+		if issueType == "" {
+			issueType = "task"
+		}
+		byType[issueType] = append(byType[issueType], issue)
+		if t.Assignee != "" {
+			contributors[t.Assignee] = true
+		}
+	}
+
+	var types []string
+	for _, o := range notesTypeOrder {
+		if _, ok := byType[o.Type]; ok {
+			types = append(types, o.Type)
+		}
+	}
+	var others []string
+	for issueType := range byType {
+		if notesHeading(issueType) == "" {
+			others = append(others, issueType)
+		}
+	}
+	sort.Strings(others)
+	for _, issueType := range append(types, others...) {
+		issues := byType[issueType]
+		sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+		notes.Groups = append(notes.Groups, notesGroup{Type: issueType, Issues: issues})
+	}
+	sort.Slice(notes.WontFix, func(i, j int) bool { return notes.WontFix[i].ID < notes.WontFix[j].ID })
+
+	for _, m := range merges {
+		if !trackedIDs[m.Issue] {
+			continue
+		}
+		notes.Merges = append(notes.Merges, m)
+		if m.Polecat != "" {
+			contributors[m.Polecat] = true
+		}
+	}
+	sort.Slice(notes.Merges, func(i, j int) bool { return notes.Merges[i].MergedAt.Before(notes.Merges[j].MergedAt) })
+
+	for _, c := range costs {
+		if !trackedIDs[c.WorkItem] {
+			continue
+		}
+		notes.CostUSD += c.CostUSD
+		notes.Sessions++
+	}
+
+	for addr := range contributors {
+		notes.Contributors = append(notes.Contributors, addr)
+	}
+	sort.Strings(notes.Contributors)
+	return notes
+}
+
+// notesHeading returns the section heading of a well-known issue type.
+func notesHeading(issueType string) string {
+	for _, o := range notesTypeOrder {
+		if o.Type == issueType {
+			return o.Heading
+		}
+	}
+	return ""
+}
+
+// Markdown renders the release notes.
+func (n *convoyNotes) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Release Notes: %s\n\n", n.Title)
+
+	issues := 0
+	for _, g := range n.Groups {
+		issues += len(g.Issues)
+	}
+	summary := fmt.Sprintf("Convoy %s", n.ConvoyID)
+	if landed, err := time.Parse(time.RFC3339, n.LandedAt); err == nil {
+		summary += " landed " + landed.Local().Format("Mon Jan 2 2006 15:04")
+	} else {
+		summary += " (" + n.Status + ")"
+	}
+	fmt.Fprintf(&sb, "%s: %d issue(s), %d merge(s).\n", summary, issues, len(n.Merges))
+
+	for _, g := range n.Groups {
+		heading := notesHeading(g.Type)
+		if heading == "" {
+			heading = strings.ToUpper(g.Type[:1]) + g.Type[1:]
+		}
+		fmt.Fprintf(&sb, "\n## %s\n\n", heading)
+		for _, issue := range g.Issues {
+			fmt.Fprintf(&sb, "- %s: %s", issue.ID, issue.Title)
+			if issue.Status != "closed" {
+				fmt.Fprintf(&sb, " (%s)", issue.Status)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(n.Merges) > 0 {
+		sb.WriteString("\n## Merged\n\n")
+		for _, m := range n.Merges {
+			fmt.Fprintf(&sb, "- %s: `%s` → %s", m.Issue, m.Branch, m.TargetBranch)
+			if m.MergeCommit != "" {
+				fmt.Fprintf(&sb, " @ %s", swarm.ShortSHA(m.MergeCommit))
+			}
+			if m.Rig != "" {
+				fmt.Fprintf(&sb, " (%s)", m.Rig)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(n.WontFix) > 0 {
+		sb.WriteString("\n## Won't Fix\n\n")
+		for _, issue := range n.WontFix {
+			fmt.Fprintf(&sb, "- %s: %s — %s\n", issue.ID, issue.Title, issue.CloseReason)
+		}
+	}
+
+	if len(n.Contributors) > 0 {
+		sb.WriteString("\n## Contributors\n\n")
+		for _, addr := range n.Contributors {
+			fmt.Fprintf(&sb, "- %s\n", addr)
+		}
+	}
+
+	if n.Sessions > 0 {
+		fmt.Fprintf(&sb, "\n## Cost\n\n$%.2f across %d session(s)\n", n.CostUSD, n.Sessions)
+	}
+	return sb.String()
+}
+
+// collectConvoyNotes gathers everything the release notes for a convoy
+// need. Merge and cost lookups are best-effort.
+func collectConvoyNotes(townBeads, convoyID string) (*convoyNotes, error) {
+	showCmd := exec.Command("bd", "show", convoyID, "--json")
+	showCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	showCmd.Stdout = &stdout
+	if err := showCmd.Run(); err != nil {
+		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	var convoys []notesConvoy
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil || len(convoys) == 0 {
+		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	convoy := convoys[0]
+	if convoy.Type != "convoy" {
+		return nil, fmt.Errorf("'%s' is not a convoy (type: %s)", convoyID, convoy.Type)
+	}
+
+	tracked := getTrackedIssues(townBeads, convoyID)
+	ids := make([]string, 0, len(tracked))
+	for _, t := range tracked {
+		ids = append(ids, t.ID)
+	}
+
+	townRoot := filepath.Dir(townBeads)
+	merges := findConvoyMerges(townRoot, ids)
+
+	// Digests older than the convoy can't hold its sessions
+	days := 1
+	if created, err := time.Parse(time.RFC3339, convoy.CreatedAt); err == nil {
+		days += int(time.Since(created).Hours() / 24)
+	}
+	costs := querySessionEvents()
+	if digested, err := queryDigestBeads(days); err == nil {
+		costs = append(costs, digested...)
+	}
+
+	return buildConvoyNotes(convoy, tracked, getCloseReasons(townRoot, ids), merges, costs), nil
+}
+
+// getCloseReasons returns the close reason of each closed issue. bd runs
+// from the town root so routes resolve rig issues.
+func getCloseReasons(townRoot string, issueIDs []string) map[string]string {
+	reasons := make(map[string]string)
+	if len(issueIDs) == 0 {
+		return reasons
+	}
+	args := append([]string{"--no-daemon", "show"}, issueIDs...)
+	args = append(args, "--json")
+	showCmd := exec.Command("bd", args...)
+	showCmd.Dir = townRoot
+	var stdout bytes.Buffer
+	showCmd.Stdout = &stdout
+	if err := showCmd.Run(); err != nil {
+		return reasons
+	}
+	var issues []struct {
+		ID          string `json:"id"`
+		CloseReason string `json:"close_reason"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
+		return reasons
+	}
+	for _, issue := range issues {
+		if issue.CloseReason != "" {
+			reasons[issue.ID] = issue.CloseReason
+		}
+	}
+	return reasons
+}
+
+// findConvoyMerges returns the merged merge requests, across all rigs, whose
+// source issue is one of issueIDs.
+func findConvoyMerges(townRoot string, issueIDs []string) []protocol.MergedPayload {
+	wanted := make(map[string]bool, len(issueIDs))
+	for _, id := range issueIDs {
+		wanted[id] = true
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		return nil
+	}
+	var merges []protocol.MergedPayload
+	for rigName := range rigsConfig.Rigs {
+		mrs, err := beads.New(filepath.Join(townRoot, rigName)).List(beads.ListOptions{
+			Type: "merge-request", Status: "closed", Priority: -1,
+		})
+		if err != nil {
+			continue
+		}
+		for _, mr := range mrs {
+			fields := beads.ParseMRFields(mr)
+			if fields == nil || !wanted[fields.SourceIssue] {
+				continue
+			}
+			if fields.CloseReason != "" && fields.CloseReason != "merged" {
+				continue
+			}
+			mergedAt, _ := time.Parse(time.RFC3339, mr.ClosedAt)
+			merges = append(merges, protocol.MergedPayload{
+				Branch:       fields.Branch,
+				Issue:        fields.SourceIssue,
+				Polecat:      fields.Worker,
+				Rig:          rigName,
+				MergedAt:     mergedAt,
+				MergeCommit:  fields.MergeCommit,
+				TargetBranch: fields.Target,
+			})
+		}
+	}
+	return merges
+}
+
+// notesRigs returns the rigs that merged work in the convoy.
+func (n *convoyNotes) notesRigs() []string {
+	seen := make(map[string]bool)
+	var rigs []string
+	for _, m := range n.Merges {
+		if m.Rig != "" && !seen[m.Rig] {
+			seen[m.Rig] = true
+			rigs = append(rigs, m.Rig)
+		}
+	}
+	sort.Strings(rigs)
+	return rigs
+}
+
+// saveConvoyNotes stores release notes on the convoy bead (Markdown followed
+// by the JSON document) and in each contributing rig that sets
+// release_notes_dir. Returns the rig files written.
+func saveConvoyNotes(townBeads string, notes *convoyNotes) ([]string, error) {
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	markdown := notes.Markdown()
+
+	beadNotes := markdown + "\n```json\n" + string(data) + "\n```\n"
+	updateCmd := exec.Command("bd", "update", notes.ConvoyID, "--notes="+beadNotes)
+	updateCmd.Dir = townBeads
+	if out, err := updateCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("saving notes to %s: %w (%s)", notes.ConvoyID, err, strings.TrimSpace(string(out)))
+	}
+
+	townRoot := filepath.Dir(townBeads)
+	var written []string
+	for _, rigName := range notes.notesRigs() {
+		r := &rig.Rig{Name: rigName, Path: filepath.Join(townRoot, rigName)}
+		dir := r.GetStringConfig("release_notes_dir")
+		if dir == "" {
+			continue
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(r.Path, dir)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return written, fmt.Errorf("creating %s: %w", dir, err)
+		}
+		base := filepath.Join(dir, notes.ConvoyID)
+		if err := os.WriteFile(base+".md", []byte(markdown), 0644); err != nil { //nolint:gosec // G306: release notes are not sensitive
+			return written, err
+		}
+		if err := os.WriteFile(base+".json", append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: release notes are not sensitive
+			return written, err
+		}
+		written = append(written, base+".md")
+	}
+	return written, nil
+}
+
+// publishConvoyNotes generates and saves the release notes of a convoy that
+// just landed. Returns the Markdown, or "" if the notes couldn't be built.
+func publishConvoyNotes(townBeads, convoyID string) string {
+	notes, err := collectConvoyNotes(townBeads, convoyID)
+	if err != nil {
+		style.PrintWarning("couldn't generate release notes for %s: %v", convoyID, err)
+		return ""
+	}
+	if _, err := saveConvoyNotes(townBeads, notes); err != nil {
+		style.PrintWarning("couldn't save release notes for %s: %v", convoyID, err)
+	}
+	return notes.Markdown()
+}
+
+func runConvoyNotes(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+
+	convoyID := args[0]
+	if n, err := strconv.Atoi(convoyID); err == nil && n > 0 {
+		if convoyID, err = resolveConvoyNumber(townBeads, n); err != nil {
+			return err
+		}
+	}
+
+	notes, err := collectConvoyNotes(townBeads, convoyID)
+	if err != nil {
+		return err
+	}
+
+	if convoyNotesSave {
+		written, err := saveConvoyNotes(townBeads, notes)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%s Saved release notes to %s\n", style.Bold.Render("✓"), convoyID)
+		for _, path := range written {
+			fmt.Fprintf(os.Stderr, "  %s\n", path)
+		}
+	}
+
+	asJSON := convoyNotesJSON || strings.HasSuffix(convoyNotesOutput, ".json")
+	var out []byte
+	if asJSON {
+		data, err := json.MarshalIndent(notes, "", "  ")
+		if err != nil {
+			return err
+		}
+		out = append(data, '\n')
+	} else {
+		out = []byte(notes.Markdown())
+	}
+
+	if convoyNotesOutput != "" {
+		if err := os.WriteFile(convoyNotesOutput, out, 0644); err != nil { //nolint:gosec // G306: release notes are not sensitive
+			return fmt.Errorf("writing %s: %w", convoyNotesOutput, err)
+		}
+		fmt.Printf("%s Wrote release notes to %s\n", style.Bold.Render("✓"), convoyNotesOutput)
+		return nil
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/protocol"
+)
+
+func TestBuildConvoyNotes(t *testing.T) {
+	convoy := notesConvoy{ID: "hq-cv-abc", Title: "Auth rework", Status: "closed", ClosedAt: "2026-10-18T12:00:00Z"}
+	tracked := []trackedIssueInfo{
+		{ID: "gt-2", Title: "Fix token refresh", Status: "closed", IssueType: "bug", Assignee: "gastown/polecats/nux"},
+		{ID: "gt-1", Title: "Add SSO", Status: "closed", IssueType: "feature"},
+		{ID: "gt-3", Title: "Legacy login", Status: "closed", IssueType: "feature"},
+		{ID: "gt-4", Title: "Update runbook", Status: "closed", IssueType: "docs"},
+	}
+	reasons := map[string]string{"gt-1": "Merged", "gt-3": "Won't fix: superseded by SSO"}
+	merged := time.Date(2026, 10, 18, 11, 0, 0, 0, time.UTC)
+	merges := []protocol.MergedPayload{
+		{Branch: "polecat/toast/gt-1", Issue: "gt-1", Polecat: "gastown/polecats/toast", Rig: "gastown",
+			MergedAt: merged, MergeCommit: "0123456789abcdef", TargetBranch: "main"},
+		{Branch: "polecat/nux/gt-2", Issue: "gt-2", Polecat: "gastown/polecats/nux", Rig: "gastown",
+			MergedAt: merged.Add(-time.Hour), TargetBranch: "main"},
+		{Branch: "polecat/ace/gt-9", Issue: "gt-9", Polecat: "gastown/polecats/ace", Rig: "gastown", MergedAt: merged},
+	}
+	costs := []CostEntry{
+		{WorkItem: "gt-1", CostUSD: 1.25},
+		{WorkItem: "gt-2", CostUSD: 0.75},
+		{WorkItem: "gt-9", CostUSD: 9.00},
+	}
+
+	notes := buildConvoyNotes(convoy, tracked, reasons, merges, costs)
+
+	var types []string
+	for _, g := range notes.Groups {
+		types = append(types, g.Type)
+	}
+	if got := strings.Join(types, ","); got != "feature,bug,docs" {
+		t.Errorf("group order = %s, want feature,bug,docs", got)
+	}
+	if len(notes.Groups[0].Issues) != 1 || notes.Groups[0].Issues[0].ID != "gt-1" {
+		t.Errorf("features = %+v, want only gt-1 (gt-3 was won't-fix)", notes.Groups[0].Issues)
+	}
+	if len(notes.WontFix) != 1 || notes.WontFix[0].ID != "gt-3" {
+		t.Errorf("wont_fix = %+v, want gt-3", notes.WontFix)
+	}
+	if len(notes.Merges) != 2 || notes.Merges[0].Issue != "gt-2" {
+		t.Errorf("merges = %+v, want gt-2 then gt-1 (untracked gt-9 dropped)", notes.Merges)
+	}
+	if got := strings.Join(notes.Contributors, ","); got != "gastown/polecats/nux,gastown/polecats/toast" {
+		t.Errorf("contributors = %s", got)
+	}
+	if notes.CostUSD != 2.00 || notes.Sessions != 2 {
+		t.Errorf("cost = $%.2f over %d sessions, want $2.00 over 2", notes.CostUSD, notes.Sessions)
+	}
+	if rigs := notes.notesRigs(); len(rigs) != 1 || rigs[0] != "gastown" {
+		t.Errorf("notesRigs = %v, want [gastown]", rigs)
+	}
+
+	md := notes.Markdown()
+	for _, want := range []string{
+		"# Release Notes: Auth rework",
+		"## Features\n\n- gt-1: Add SSO\n",
+		"## Bug Fixes\n\n- gt-2: Fix token refresh\n",
+		"## Docs\n",
+		"- gt-1: `polecat/toast/gt-1` → main @ 01234567 (gastown)",
+		"## Won't Fix\n\n- gt-3: Legacy login — Won't fix: superseded by SSO\n",
+		"$2.00 across 2 session(s)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestIsWontFix(t *testing.T) {
+	for reason, want := range map[string]bool{
+		"Won't fix":                 true,
+		"wontfix - out of scope":    true,
+		"Not planned":               true,
+		"Merged in abc123":          false,
+		"All tracked issues closed": false,
+		"":                          false,
+	} {
+		if got := isWontFix(reason); got != want {
+			t.Errorf("isWontFix(%q) = %v, want %v", reason, got, want)
+		}
+	}
+}
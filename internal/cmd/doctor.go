@@ -2,10 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -14,8 +21,22 @@ var (
 	doctorVerbose         bool
 	doctorRig             string
 	doctorRestartSessions bool
+	doctorJSON            bool
+	doctorPlan            bool
+	doctorOnly            []string
+	doctorSkip            []string
+	doctorEscalate        bool
+	doctorSaveBaseline    bool
+	doctorChanges         bool
+	doctorSuppress        string
+	doctorSuppressReason  string
+	doctorSuppressUntil   string
 )
 
+// doctorEscalationSource prefixes the source of escalations filed by
+// --escalate, so each failing check is escalated at most once while open.
+const doctorEscalationSource = "doctor:"
+
 var doctorCmd = &cobra.Command{
 	Use:     "doctor",
 	GroupID: GroupDiag,
@@ -81,7 +102,37 @@ Patrol checks:
   - patrol-roles-have-prompts Verify role prompts exist
 
 Use --fix to attempt automatic fixes for issues that support it.
-Use --rig to check a specific rig instead of the entire workspace.`,
+Use --plan to see what --fix would change without changing anything.
+Use --rig to check a specific rig instead of the entire workspace.
+
+Selecting checks:
+  --only and --skip take check names or categories (e.g. cleanup,
+  "Core", orphan-sessions), comma-separated or repeated.
+
+Machine-readable output:
+  --json prints the full report (or plan, with --plan) as JSON, with
+  each check's name, category, status, details, fix hint and whether
+  it is fixable.
+
+Unattended runs:
+  --escalate files a high-severity escalation for each check in error
+  that doesn't already have an open escalation. The deacon patrol runs
+  'gt doctor --escalate' so new errors reach a human.
+
+Baselines and drift:
+  --save-baseline records the current results. Later runs show what
+  changed since: new failures, resolved checks, and checks that keep
+  flipping between runs (flapping). A baseline saved with --only or
+  --skip covers just the checks that ran; others are never reported as
+  new. --changes prints only the drift and fails only on new errors.
+  Every run is recorded in the per-machine doctor history used for flap
+  detection.
+
+Suppressions:
+  Accept a known failure until a date, stored in settings/config.json:
+    gt doctor --suppress orphan-sessions --reason "crew debugging" --until 2026-11-30
+  Suppressed checks still run but don't count as warnings or errors.
+  Expired suppressions are reported so they can be renewed or removed.`,
 	RunE: runDoctor,
 }
 
@@ -90,10 +141,27 @@ func init() {
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output as JSON")
+	doctorCmd.Flags().BoolVar(&doctorPlan, "plan", false, "Show what --fix would change without changing anything")
+	doctorCmd.Flags().StringSliceVar(&doctorOnly, "only", nil, "Run only these checks or categories")
+	doctorCmd.Flags().StringSliceVar(&doctorSkip, "skip", nil, "Skip these checks or categories")
+	doctorCmd.Flags().BoolVar(&doctorEscalate, "escalate", false, "File escalations for checks in error that aren't already escalated")
+	doctorCmd.Flags().BoolVar(&doctorSaveBaseline, "save-baseline", false, "Save results as the baseline for later drift reports")
+	doctorCmd.Flags().BoolVar(&doctorChanges, "changes", false, "Show only changes since the baseline")
+	doctorCmd.Flags().StringVar(&doctorSuppress, "suppress", "", "Suppress a failing check (requires --reason and --until)")
+	doctorCmd.Flags().StringVar(&doctorSuppressReason, "reason", "", "Reason for --suppress")
+	doctorCmd.Flags().StringVar(&doctorSuppressUntil, "until", "", "Last day (YYYY-MM-DD) --suppress applies")
 	rootCmd.AddCommand(doctorCmd)
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	if doctorPlan && doctorFix {
+		return fmt.Errorf("--plan and --fix are mutually exclusive")
+	}
+	if doctorSaveBaseline && doctorChanges {
+		return fmt.Errorf("--save-baseline and --changes are mutually exclusive")
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -187,6 +255,23 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		d.RegisterAll(doctor.RigChecks()...)
 	}
 
+	if doctorSuppress != "" {
+		return suppressDoctorCheck(townRoot, d, doctorSuppress, doctorSuppressReason, doctorSuppressUntil)
+	}
+
+	if err := d.Select(doctorOnly, doctorSkip); err != nil {
+		return err
+	}
+
+	if doctorPlan {
+		plan := d.Plan(ctx)
+		if doctorJSON {
+			return plan.WriteJSON(os.Stdout)
+		}
+		plan.Print(os.Stdout)
+		return nil
+	}
+
 	// Run checks
 	var report *doctor.Report
 	if doctorFix {
@@ -195,13 +280,192 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		report = d.Run(ctx)
 	}
 
+	// Apply suppressions from town settings
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	expired := report.ApplySuppressions(settings.DoctorSuppressions, time.Now())
+
+	if err := doctor.RecordRun(townRoot, report); err != nil {
+		style.PrintWarning("could not record doctor run: %v", err)
+	}
+
+	// Compare against the baseline, or replace it
+	var drift *doctor.Drift
+	if doctorSaveBaseline {
+		baseline := doctor.NewBaseline(report)
+		baseline.Partial = len(doctorOnly) > 0 || len(doctorSkip) > 0
+		if err := doctor.SaveBaseline(townRoot, baseline); err != nil {
+			return fmt.Errorf("saving baseline: %w", err)
+		}
+	} else {
+		baseline, err := doctor.LoadBaseline(townRoot)
+		if err != nil {
+			return err
+		}
+		if baseline != nil {
+			history, err := state.DoctorHistory(townRoot)
+			if err != nil {
+				style.PrintWarning("could not read doctor history: %v", err)
+			}
+			drift = baseline.Compare(report, history)
+			report.Drift = drift
+		} else if doctorChanges {
+			return fmt.Errorf("no baseline saved: run 'gt doctor --save-baseline' first")
+		}
+	}
+
 	// Print report
-	report.Print(os.Stdout, doctorVerbose)
+	if doctorJSON {
+		if err := report.WriteJSON(os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		if !doctorChanges {
+			report.Print(os.Stdout, doctorVerbose)
+		}
+		if drift != nil {
+			fmt.Println()
+			drift.Print(os.Stdout)
+		}
+		if doctorSaveBaseline {
+			fmt.Printf("\n%s Baseline saved to %s\n", style.Bold.Render("✓"), doctor.BaselinePath(townRoot))
+		}
+		for _, s := range expired {
+			style.PrintWarning("suppression for %s expired %s (%s); renew it or remove it from %s",
+				s.Check, s.Expires, s.Reason, config.TownSettingsPath(townRoot))
+		}
+	}
+
+	if doctorEscalate {
+		// Keep stdout parseable in JSON mode.
+		out := os.Stdout
+		if doctorJSON {
+			out = os.Stderr
+		}
+		if err := escalateDoctorErrors(townRoot, report, out); err != nil {
+			return err
+		}
+	}
 
 	// Exit with error code if there are errors
+	if doctorChanges {
+		newErrors := 0
+		for _, check := range drift.New {
+			if check.Status == doctor.StatusError {
+				newErrors++
+			}
+		}
+		if newErrors > 0 {
+			return fmt.Errorf("doctor found %d new error(s) since baseline", newErrors)
+		}
+		return nil
+	}
 	if report.HasErrors() {
 		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
 	}
 
 	return nil
 }
+
+// escalateDoctorErrors files an escalation for each check in error that
+// doesn't already have an open escalation from doctor.
+func escalateDoctorErrors(townRoot string, report *doctor.Report, out io.Writer) error {
+	var failing []*doctor.CheckResult
+	for _, check := range report.Checks {
+		if check.Status == doctor.StatusError && check.Suppressed == "" {
+			failing = append(failing, check)
+		}
+	}
+	if len(failing) == 0 {
+		return nil
+	}
+
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	open, err := bd.ListEscalations()
+	if err != nil {
+		return fmt.Errorf("listing escalations: %w", err)
+	}
+	escalated := make(map[string]bool)
+	for _, issue := range open {
+		escalated[beads.ParseEscalationFields(issue.Description).Source] = true
+	}
+
+	escalationConfig, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading escalation config: %w", err)
+	}
+	agentID := detectSender()
+	if agentID == "" {
+		agentID = "gt doctor"
+	}
+
+	for _, check := range failing {
+		source := doctorEscalationSource + check.Name
+		if escalated[source] {
+			continue
+		}
+		reason := check.Message
+		if len(check.Details) > 0 {
+			reason += "\n" + strings.Join(check.Details, "\n")
+		}
+		if check.FixHint != "" {
+			reason += "\nFix: " + check.FixHint
+		}
+		issue, _, _, err := createEscalation(townRoot, escalationConfig, escalationRequest{
+			Severity:    config.SeverityHigh,
+			Description: fmt.Sprintf("gt doctor: %s: %s", check.Name, check.Message),
+			Reason:      reason,
+			Source:      source,
+			From:        agentID,
+		})
+		if err != nil {
+			style.PrintWarning("could not escalate %s: %v", check.Name, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "%s Escalated %s: %s\n", style.Bold.Render("✓"), check.Name, issue.ID)
+	}
+	return nil
+}
+
+// suppressDoctorCheck adds or replaces a suppression in town settings.
+func suppressDoctorCheck(townRoot string, d *doctor.Doctor, check, reason, until string) error {
+	known := false
+	for _, c := range d.Checks() {
+		if c.Name() == check {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown check %q", check)
+	}
+	if reason == "" || until == "" {
+		return fmt.Errorf("--suppress requires --reason and --until")
+	}
+	suppression := config.DoctorSuppression{Check: check, Reason: reason, Expires: until}
+	if _, err := suppression.ExpiresAt(); err != nil {
+		return err
+	}
+
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	kept := settings.DoctorSuppressions[:0]
+	for _, s := range settings.DoctorSuppressions {
+		if s.Check != check {
+			kept = append(kept, s)
+		}
+	}
+	settings.DoctorSuppressions = append(kept, suppression)
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Suppressed %s until %s\n", style.Bold.Render("✓"), check, until)
+	fmt.Printf("  Reason: %s\n", reason)
+	return nil
+}
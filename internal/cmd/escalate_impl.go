@@ -66,39 +66,91 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Create escalation bead
-	bd := beads.New(beads.ResolveBeadsDir(townRoot))
-	fields := &beads.EscalationFields{
+	issue, actions, targets, err := createEscalation(townRoot, escalationConfig, escalationRequest{
 		Severity:    severity,
+		Description: description,
 		Reason:      escalateReason,
 		Source:      escalateSource,
-		EscalatedBy: agentID,
-		EscalatedAt: time.Now().Format(time.RFC3339),
 		RelatedBead: escalateRelatedBead,
+		From:        agentID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Output
+	if escalateJSON {
+		result := map[string]interface{}{
+			"id":       issue.ID,
+			"severity": severity,
+			"actions":  actions,
+			"targets":  targets,
+		}
+		if escalateSource != "" {
+			result["source"] = escalateSource
+		}
+		out, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		emoji := severityEmoji(severity)
+		fmt.Printf("%s Escalation created: %s\n", emoji, issue.ID)
+		fmt.Printf("  Severity: %s\n", severity)
+		if escalateSource != "" {
+			fmt.Printf("  Source: %s\n", escalateSource)
+		}
+		fmt.Printf("  Routed to: %s\n", strings.Join(targets, ", "))
+	}
+
+	return nil
+}
+
+// escalationRequest describes an escalation to file.
+type escalationRequest struct {
+	Severity    string
+	Description string
+	Reason      string
+	Source      string
+	RelatedBead string
+	From        string
+}
+
+// createEscalation creates the escalation bead, routes it by severity (mail
+// and external actions) and logs it to the activity feed. It returns the
+// bead with the routing actions and mail targets used.
+func createEscalation(townRoot string, escalationConfig *config.EscalationConfig, req escalationRequest) (*beads.Issue, []string, []string, error) {
+	// Create escalation bead
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	fields := &beads.EscalationFields{
+		Severity:    req.Severity,
+		Reason:      req.Reason,
+		Source:      req.Source,
+		EscalatedBy: req.From,
+		EscalatedAt: time.Now().Format(time.RFC3339),
+		RelatedBead: req.RelatedBead,
 	}
 
-	issue, err := bd.CreateEscalationBead(description, fields)
+	issue, err := bd.CreateEscalationBead(req.Description, fields)
 	if err != nil {
-		return fmt.Errorf("creating escalation bead: %w", err)
+		return nil, nil, nil, fmt.Errorf("creating escalation bead: %w", err)
 	}
 
 	// Get routing actions for this severity
-	actions := escalationConfig.GetRouteForSeverity(severity)
+	actions := escalationConfig.GetRouteForSeverity(req.Severity)
 	targets := extractMailTargetsFromActions(actions)
 
 	// Send mail to each target (actions with "mail:" prefix)
 	router := mail.NewRouter(townRoot)
 	for _, target := range targets {
 		msg := &mail.Message{
-			From:    agentID,
+			From:    req.From,
 			To:      target,
-			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
-			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(req.Severity), req.Description),
+			Body:    formatEscalationMailBody(issue.ID, req.Severity, req.Reason, req.From, req.RelatedBead),
 			Type:    mail.TypeTask,
 		}
 
 		// Set priority based on severity
-		switch severity {
+		switch req.Severity {
 		case config.SeverityCritical:
 			msg.Priority = mail.PriorityUrgent
 		case config.SeverityHigh:
@@ -115,41 +167,18 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	executeExternalActions(actions, escalationConfig, issue.ID, req.Severity, req.Description)
 
 	// Log to activity feed
-	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
-	payload["severity"] = severity
+	payload := events.EscalationPayload(issue.ID, req.From, strings.Join(targets, ","), req.Description)
+	payload["severity"] = req.Severity
 	payload["actions"] = strings.Join(actions, ",")
-	if escalateSource != "" {
-		payload["source"] = escalateSource
-	}
-	_ = events.LogFeed(events.TypeEscalationSent, agentID, payload)
-
-	// Output
-	if escalateJSON {
-		result := map[string]interface{}{
-			"id":       issue.ID,
-			"severity": severity,
-			"actions":  actions,
-			"targets":  targets,
-		}
-		if escalateSource != "" {
-			result["source"] = escalateSource
-		}
-		out, _ := json.MarshalIndent(result, "", "  ")
-		fmt.Println(string(out))
-	} else {
-		emoji := severityEmoji(severity)
-		fmt.Printf("%s Escalation created: %s\n", emoji, issue.ID)
-		fmt.Printf("  Severity: %s\n", severity)
-		if escalateSource != "" {
-			fmt.Printf("  Source: %s\n", escalateSource)
-		}
-		fmt.Printf("  Routed to: %s\n", strings.Join(targets, ", "))
+	if req.Source != "" {
+		payload["source"] = req.Source
 	}
+	_ = events.LogFeed(events.TypeEscalationSent, req.From, payload)
 
-	return nil
+	return issue, actions, targets, nil
 }
 
 func runEscalateList(cmd *cobra.Command, args []string) error {
@@ -4,15 +4,19 @@ import (
 	"bufio"
 	"crypto/rand"
 	"encoding/base32"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"golang.org/x/text/cases"
@@ -21,12 +25,16 @@ import (
 
 // Formula command flags
 var (
-	formulaListJSON   bool
-	formulaShowJSON   bool
-	formulaRunPR      int
-	formulaRunRig     string
-	formulaRunDryRun  bool
-	formulaCreateType string
+	formulaListJSON    bool
+	formulaShowJSON    bool
+	formulaShowExpand  bool
+	formulaShowResolve bool
+	formulaShowVars    []string
+	formulaRunPR       int
+	formulaRunRig      string
+	formulaRunDryRun   bool
+	formulaRunVars     []string
+	formulaCreateType  string
 )
 
 var formulaCmd = &cobra.Command{
@@ -86,9 +94,19 @@ Shows:
   - Steps with dependencies
   - Composition rules (extends, aspects)
 
+With --resolve, applies composition (extends, compose.include,
+compose.expand and compose.aspects) and prints the resulting steps in
+dependency order, each with the formula file it came from.
+
+With --expand, also evaluates the formula's when conditions and foreach
+loops against its var defaults and any --var overrides, and prints the
+concrete steps that pouring would create.
+
 Examples:
   gt formula show shiny
-  gt formula show rule-of-five --json`,
+  gt formula show rule-of-five --json
+  gt formula show shiny-secure --resolve
+  gt formula show release --expand --var platforms=linux,darwin`,
 	Args: cobra.ExactArgs(1),
 	RunE: runFormulaShow,
 }
@@ -112,13 +130,19 @@ Options:
   --pr=N      Run formula on GitHub PR #N
   --rig=NAME  Target specific rig (default: current or gastown)
   --dry-run   Show what would happen without executing
+  --var=K=V   Set a formula input (repeatable)
+
+Inputs are checked against their declared types before anything runs,
+and every invalid input is reported at once. On a terminal, missing
+required inputs are prompted for.
 
 Examples:
   gt formula run shiny                    # Run formula in current rig
   gt formula run                          # Run default formula from rig config
   gt formula run shiny --pr=123           # Run on PR #123
   gt formula run security-audit --rig=beads  # Run in specific rig
-  gt formula run release --dry-run        # Preview execution`,
+  gt formula run release --dry-run        # Preview execution
+  gt formula run code-review --var files=main.go`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFormulaRun,
 }
@@ -150,11 +174,15 @@ func init() {
 
 	// Show flags
 	formulaShowCmd.Flags().BoolVar(&formulaShowJSON, "json", false, "Output as JSON")
+	formulaShowCmd.Flags().BoolVar(&formulaShowResolve, "resolve", false, "Print the steps after resolving extends/compose, with their source formula")
+	formulaShowCmd.Flags().BoolVar(&formulaShowExpand, "expand", false, "Print the concrete steps after composition and when/foreach expansion")
+	formulaShowCmd.Flags().StringArrayVar(&formulaShowVars, "var", nil, "Formula variable (key=value) for --expand, can be repeated")
 
 	// Run flags
 	formulaRunCmd.Flags().IntVar(&formulaRunPR, "pr", 0, "GitHub PR number to run formula on")
 	formulaRunCmd.Flags().StringVar(&formulaRunRig, "rig", "", "Target rig (default: current or gastown)")
 	formulaRunCmd.Flags().BoolVar(&formulaRunDryRun, "dry-run", false, "Preview execution without running")
+	formulaRunCmd.Flags().StringArrayVar(&formulaRunVars, "var", nil, "Formula input (key=value), can be repeated")
 
 	// Create flags
 	formulaCreateCmd.Flags().StringVar(&formulaCreateType, "type", "task", "Formula type: task, workflow, or patrol")
@@ -181,9 +209,15 @@ func runFormulaList(cmd *cobra.Command, args []string) error {
 	return bdCmd.Run()
 }
 
-// runFormulaShow delegates to bd formula show
+// runFormulaShow delegates to bd formula show, or prints the expanded plan
+// with --expand.
 func runFormulaShow(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
+	if formulaShowExpand || formulaShowResolve {
+		return showResolvedFormula(formulaName)
+	} else if len(formulaShowVars) > 0 {
+		return fmt.Errorf("--var requires --expand")
+	}
 	bdArgs := []string{"formula", "show", formulaName}
 	if formulaShowJSON {
 		bdArgs = append(bdArgs, "--json")
@@ -192,7 +226,99 @@ func runFormulaShow(cmd *cobra.Command, args []string) error {
 	bdCmd := exec.Command("bd", bdArgs...)
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	if err := bdCmd.Run(); err != nil {
+		return err
+	}
+
+	// bd doesn't know var types; list them with their enum choices.
+	if !formulaShowJSON {
+		if f, err := loadFormula(formulaName); err == nil {
+			printFormulaVarSpecs(f)
+		}
+	}
+	return nil
+}
+
+// loadFormula finds and parses a formula by name, for composition.
+func loadFormula(name string) (*formula.Formula, error) {
+	formulaPath, err := findFormulaFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return formula.ParseFile(formulaPath)
+}
+
+// showResolvedFormula prints the steps of a workflow formula after
+// resolving composition (extends, include, expand, aspects), and with
+// --expand after applying when conditions and foreach loops. Each step
+// shows the formula file it came from.
+func showResolvedFormula(formulaName string) error {
+	vars, err := parseFormulaVars(formulaShowVars)
+	if err != nil {
+		return err
+	}
+
+	f, err := loadFormula(formulaName)
+	if err != nil {
+		return fmt.Errorf("loading formula: %w", err)
+	}
+	if f.Type != formula.TypeWorkflow {
+		return fmt.Errorf("--resolve and --expand only apply to workflow formulas (%s is %s)", formulaName, f.Type)
+	}
+
+	resolved, err := f.Resolve(loadFormula)
+	if err != nil {
+		return fmt.Errorf("resolving formula: %w", err)
+	}
+	if formulaShowExpand {
+		if resolved, err = resolved.Expand(vars); err != nil {
+			return fmt.Errorf("expanding formula: %w", err)
+		}
+	}
+	order, err := resolved.TopologicalSort()
+	if err != nil {
+		return err
+	}
+
+	if formulaShowJSON {
+		steps := make([]formula.Step, 0, len(order))
+		for _, id := range order {
+			steps = append(steps, *resolved.GetStep(id))
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(steps)
+	}
+
+	fmt.Printf("%s %s: %d step(s)\n\n", style.Bold.Render("📋"), formulaName, len(resolved.Steps))
+	for i, id := range order {
+		step := resolved.GetStep(id)
+		fmt.Printf("  %d. %s %s %s\n", i+1, style.Bold.Render(step.ID), step.Title,
+			style.Dim.Render("("+formulaSourceName(step.Source)+")"))
+		if len(step.Needs) > 0 {
+			fmt.Printf("     %s\n", style.Dim.Render("needs: "+strings.Join(step.Needs, ", ")))
+		}
+	}
+	printFormulaVarSpecs(resolved)
+	return nil
+}
+
+// parseFormulaVars parses repeated --var key=value flags.
+func parseFormulaVars(flags []string) (map[string]string, error) {
+	vars := make(map[string]string, len(flags))
+	for _, v := range flags {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (expected key=value)", v)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// formulaSourceName shortens a step's source path to the formula file name.
+func formulaSourceName(source string) string {
+	return strings.TrimSuffix(filepath.Base(source), ".formula.toml")
 }
 
 // runFormulaRun executes a formula by spawning a convoy of polecats.
@@ -257,9 +383,15 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("parsing formula: %w", err)
 	}
 
+	// Validate inputs against their declared types
+	vars, err := resolveFormulaRunVars(formulaPath)
+	if err != nil {
+		return err
+	}
+
 	// Handle dry-run mode
 	if formulaRunDryRun {
-		return dryRunFormula(f, formulaName, targetRig)
+		return dryRunFormula(f, formulaName, targetRig, vars)
 	}
 
 	// Currently only convoy formulas are supported for execution
@@ -276,11 +408,41 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 	}
 
 	// Execute convoy formula
-	return executeConvoyFormula(f, formulaName, targetRig)
+	return executeConvoyFormula(f, formulaName, targetRig, vars)
+}
+
+// resolveFormulaRunVars validates --var values, plus --pr for formulas
+// with a pr input, against the formula's declared vars and inputs.
+func resolveFormulaRunVars(formulaPath string) (map[string]string, error) {
+	vars, err := parseFormulaVars(formulaRunVars)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(formulaPath, ".toml") {
+		return vars, nil
+	}
+	f, err := formula.ParseFile(formulaPath)
+	if err != nil {
+		return nil, fmt.Errorf("parsing formula: %w", err)
+	}
+	if _, ok := f.Inputs["pr"]; ok && formulaRunPR > 0 && vars["pr"] == "" {
+		vars["pr"] = strconv.Itoa(formulaRunPR)
+	}
+	return resolveFormulaVars(f, vars)
+}
+
+// formatFormulaVars renders vars as sorted "key=value" pairs.
+func formatFormulaVars(vars map[string]string) []string {
+	pairs := make([]string, 0, len(vars))
+	for name, v := range vars {
+		pairs = append(pairs, name+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
 }
 
 // dryRunFormula shows what would happen without executing
-func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
+func dryRunFormula(f *formulaData, formulaName, targetRig string, vars map[string]string) error {
 	fmt.Printf("%s Would execute formula:\n", style.Dim.Render("[dry-run]"))
 	fmt.Printf("  Formula: %s\n", style.Bold.Render(formulaName))
 	fmt.Printf("  Type:    %s\n", f.Type)
@@ -288,6 +450,9 @@ func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
 	if formulaRunPR > 0 {
 		fmt.Printf("  PR:      #%d\n", formulaRunPR)
 	}
+	if len(vars) > 0 {
+		fmt.Printf("  Inputs:  %s\n", strings.Join(formatFormulaVars(vars), ", "))
+	}
 
 	if f.Type == "convoy" && len(f.Legs) > 0 {
 		fmt.Printf("\n  Legs (%d parallel):\n", len(f.Legs))
@@ -304,7 +469,7 @@ func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
 }
 
 // executeConvoyFormula spawns a convoy of polecats to execute a convoy formula
-func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
+func executeConvoyFormula(f *formulaData, formulaName, targetRig string, vars map[string]string) error {
 	fmt.Printf("%s Executing convoy formula: %s\n\n",
 		style.Bold.Render("🚚"), formulaName)
 
@@ -328,6 +493,9 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 	if formulaRunPR > 0 {
 		description += fmt.Sprintf("\nPR: #%d", formulaRunPR)
 	}
+	if len(vars) > 0 {
+		description += "\nInputs: " + strings.Join(formatFormulaVars(vars), ", ")
+	}
 
 	createArgs := []string{
 		"create",
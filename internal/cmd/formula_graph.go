@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+// Graph command flags
+var (
+	formulaGraphFormat string
+	formulaGraphJSON   bool
+	formulaGraphExpand bool
+	formulaGraphVars   []string
+)
+
+var formulaGraphCmd = &cobra.Command{
+	Use:   "graph <name>",
+	Short: "Render a formula's step graph as Mermaid or DOT",
+	Long: `Render the dependency graph of a formula as a Mermaid flowchart or
+Graphviz DOT, for pasting into PRs and design docs.
+
+Workflow formulas are resolved first (extends, compose.include,
+compose.expand and compose.aspects). With --expand, when conditions and
+foreach loops are applied too, so the graph shows the concrete steps
+pouring would create. Convoy formulas show their legs feeding synthesis.
+
+Examples:
+  gt formula graph shiny                       # Mermaid (default)
+  gt formula graph shiny --format dot | dot -Tsvg > shiny.svg
+  gt formula graph release --expand --var platforms=linux,darwin
+  gt formula graph code-review --json          # nodes and edges`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaGraph,
+}
+
+func init() {
+	formulaGraphCmd.Flags().StringVar(&formulaGraphFormat, "format", "mermaid", "Output format: mermaid or dot")
+	formulaGraphCmd.Flags().BoolVar(&formulaGraphJSON, "json", false, "Output nodes and edges as JSON")
+	formulaGraphCmd.Flags().BoolVar(&formulaGraphExpand, "expand", false, "Apply when conditions and foreach loops")
+	formulaGraphCmd.Flags().StringArrayVar(&formulaGraphVars, "var", nil, "Formula variable (key=value) for --expand, can be repeated")
+
+	formulaCmd.AddCommand(formulaGraphCmd)
+}
+
+func runFormulaGraph(cmd *cobra.Command, args []string) error {
+	formulaName := args[0]
+	if len(formulaGraphVars) > 0 && !formulaGraphExpand {
+		return fmt.Errorf("--var requires --expand")
+	}
+	vars, err := parseFormulaVars(formulaGraphVars)
+	if err != nil {
+		return err
+	}
+
+	f, err := loadFormula(formulaName)
+	if err != nil {
+		return fmt.Errorf("loading formula: %w", err)
+	}
+	if f.Type == formula.TypeWorkflow {
+		if f, err = f.Resolve(loadFormula); err != nil {
+			return fmt.Errorf("resolving formula: %w", err)
+		}
+		if formulaGraphExpand {
+			if f, err = f.Expand(vars); err != nil {
+				return fmt.Errorf("expanding formula: %w", err)
+			}
+		}
+	} else if formulaGraphExpand {
+		return fmt.Errorf("--expand only applies to workflow formulas (%s is %s)", formulaName, f.Type)
+	}
+
+	return printGraph(f.Graph(), formulaGraphFormat, formulaGraphJSON)
+}
+
+// printGraph writes a graph to stdout as JSON or in the named format.
+func printGraph(g *graph.Graph, format string, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g)
+	}
+	f, err := graph.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	out, err := g.Render(f)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
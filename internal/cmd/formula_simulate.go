@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Simulate command flags
+var (
+	formulaSimulateVars     []string
+	formulaSimulateScript   string
+	formulaSimulateAgents   int
+	formulaSimulateInMemory bool
+	formulaSimulateJSON     bool
+)
+
+var formulaSimulateCmd = &cobra.Command{
+	Use:   "simulate <name>",
+	Short: "Dry-run a workflow formula with scripted mock agents",
+	Long: `Simulate a workflow formula without spending real agent sessions.
+
+The formula is resolved and expanded with --var, then poured into an
+isolated throwaway beads database with bd cook and bd mol wisp, as gt
+sling pours it. Mock agents claim ready steps and
+finish them as a script says, and the report shows:
+  - the execution order, with simulated start and end times
+  - the peak number of steps running in parallel
+  - the critical path and its length
+  - steps that failed, got stuck, or could never become ready
+
+The script is YAML mapping step IDs (or globs) to an outcome and duration:
+
+  agents: 2              # concurrent agents (default: unlimited)
+  default:
+    duration: 5m
+  steps:
+    implement:
+      duration: 30m
+    "test-*":
+      duration: 10m
+    review: {outcome: fail}
+
+Outcomes are done (default), fail (dependents never start) and stuck (the
+agent never finishes). Without --script every step takes 1m.
+
+Exits non-zero if any step failed, got stuck or never ran.
+
+Examples:
+  gt formula simulate shiny --var feature=auth
+  gt formula simulate release --script release.sim.yaml --agents 1
+  gt formula simulate shiny-secure --var feature=x --in-memory --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaSimulate,
+}
+
+func init() {
+	formulaSimulateCmd.Flags().StringArrayVar(&formulaSimulateVars, "var", nil, "Formula variable (key=value), can be repeated")
+	formulaSimulateCmd.Flags().StringVar(&formulaSimulateScript, "script", "", "Mock agent script (YAML)")
+	formulaSimulateCmd.Flags().IntVar(&formulaSimulateAgents, "agents", -1, "Number of concurrent agents, overriding the script (0 = unlimited)")
+	formulaSimulateCmd.Flags().BoolVar(&formulaSimulateInMemory, "in-memory", false, "Track the run in memory instead of a throwaway beads database")
+	formulaSimulateCmd.Flags().BoolVar(&formulaSimulateJSON, "json", false, "Output as JSON")
+
+	formulaCmd.AddCommand(formulaSimulateCmd)
+}
+
+func runFormulaSimulate(cmd *cobra.Command, args []string) error {
+	formulaName := args[0]
+	vars, err := parseFormulaVars(formulaSimulateVars)
+	if err != nil {
+		return err
+	}
+
+	script := formula.NewSimScript()
+	if formulaSimulateScript != "" {
+		if script, err = formula.ParseSimScriptFile(formulaSimulateScript); err != nil {
+			return err
+		}
+	}
+	if formulaSimulateAgents >= 0 {
+		script.Agents = formulaSimulateAgents
+	}
+
+	f, err := loadFormula(formulaName)
+	if err != nil {
+		return fmt.Errorf("loading formula: %w", err)
+	}
+	if f, err = f.Resolve(loadFormula); err != nil {
+		return fmt.Errorf("resolving formula: %w", err)
+	}
+
+	var tracker formula.SimTracker
+	if !formulaSimulateInMemory {
+		dir, err := os.MkdirTemp("", "gt-simulate-*")
+		if err != nil {
+			return fmt.Errorf("creating simulation workspace: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+		if tracker, err = newBeadsSimTracker(dir); err != nil {
+			return err
+		}
+	}
+
+	result, err := f.Simulate(vars, script, tracker)
+	if err != nil {
+		return fmt.Errorf("simulating %s: %w", formulaName, err)
+	}
+
+	if formulaSimulateJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printSimResult(result)
+	}
+	if !result.Complete() {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// printSimResult prints a simulation report.
+func printSimResult(r *formula.SimResult) {
+	agents := "unlimited agents"
+	if r.Agents > 0 {
+		agents = fmt.Sprintf("%d agent(s)", r.Agents)
+	}
+	fmt.Printf("%s Simulated %s with %s\n\n", style.Bold.Render("🧪"), r.Formula, agents)
+
+	width := 0
+	for _, s := range r.Steps {
+		width = max(width, len(s.ID))
+	}
+	fmt.Printf("  %s\n", style.Bold.Render("Execution order:"))
+	for _, s := range r.Steps {
+		end := formatDuration(s.End)
+		if s.Outcome == formula.OutcomeStuck {
+			end = "never"
+		}
+		line := fmt.Sprintf("    %8s → %-8s %-*s  %s", formatDuration(s.Start), end, width, s.ID, style.Dim.Render(s.Agent))
+		if s.Outcome != formula.OutcomeDone {
+			line += " " + style.Warning.Render(string(s.Outcome))
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	fmt.Printf("  Makespan:      %s\n", formatDuration(r.Makespan))
+	fmt.Printf("  Max parallel:  %d\n", r.MaxParallel)
+	fmt.Printf("  Critical path: %s %s\n", formatDuration(r.CriticalPathLength),
+		style.Dim.Render("("+strings.Join(r.CriticalPath, " → ")+")"))
+
+	if len(r.Failed) > 0 {
+		fmt.Printf("\n  %s Failed: %s\n", style.ErrorPrefix, strings.Join(r.Failed, ", "))
+	}
+	if len(r.Stuck) > 0 {
+		fmt.Printf("\n  %s Stuck: %s\n", style.WarningPrefix, strings.Join(r.Stuck, ", "))
+	}
+	if len(r.NeverReady) > 0 {
+		fmt.Printf("\n  %s Never ready:\n", style.WarningPrefix)
+		for _, b := range r.NeverReady {
+			reason := "no free agent"
+			if len(b.BlockedBy) > 0 {
+				reason = "blocked by " + strings.Join(b.BlockedBy, ", ")
+			}
+			fmt.Printf("    %s %s\n", b.ID, style.Dim.Render("("+reason+")"))
+		}
+	}
+	if r.Complete() {
+		fmt.Printf("\n%s All %d steps completed\n", style.SuccessPrefix, len(r.Steps))
+	}
+}
+
+// beadsSimTracker tracks a simulation in a throwaway beads database, so
+// readiness comes from real bead dependencies rather than the formula. The
+// formula is poured the way gt sling pours it: bd cook, then bd mol wisp.
+type beadsSimTracker struct {
+	b     *beads.Beads
+	dir   string
+	env   []string
+	root  string
+	beads map[string]string // step ID -> bead ID
+	steps map[string]string // bead ID -> step ID
+}
+
+// newBeadsSimTracker initializes a beads database in dir.
+func newBeadsSimTracker(dir string) (*beadsSimTracker, error) {
+	beadsDir := filepath.Join(dir, ".beads")
+	env := make([]string, 0, len(os.Environ())+1)
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, "BEADS_DIR=") {
+			env = append(env, e)
+		}
+	}
+	env = append(env, "BEADS_DIR="+beadsDir)
+
+	t := &beadsSimTracker{
+		b:     beads.NewWithBeadsDir(dir, beadsDir),
+		dir:   dir,
+		env:   env,
+		beads: make(map[string]string),
+		steps: make(map[string]string),
+	}
+	if _, err := t.bd("init", "--prefix", "sim"); err != nil {
+		return nil, fmt.Errorf("initializing simulation beads (use --in-memory to skip): %w", err)
+	}
+	return t, nil
+}
+
+// bd runs a bd command against the simulation database.
+func (t *beadsSimTracker) bd(args ...string) ([]byte, error) {
+	cmd := exec.Command("bd", args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = t.dir
+	cmd.Env = t.env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("bd %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// simStepPrefix tags each poured step bead with its formula step ID.
+const simStepPrefix = "sim-step: "
+
+func (t *beadsSimTracker) Pour(f *formula.Formula) error {
+	// The formula is already expanded, so vars are substituted. Each step
+	// is tagged so its bead can be found after bd assigns IDs.
+	poured := *f
+	poured.Vars, poured.Inputs = nil, nil
+	poured.Steps = make([]formula.Step, len(f.Steps))
+	for i, step := range f.Steps {
+		step.Description = strings.TrimSpace(simStepPrefix + step.ID + "\n\n" + step.Description)
+		poured.Steps[i] = step
+	}
+
+	formulasDir := filepath.Join(t.dir, ".beads", "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := poured.EncodeConcrete(&buf, f.Name); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(formulasDir, f.Name+".formula.toml"), buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	if _, err := t.bd("--no-daemon", "cook", f.Name); err != nil {
+		return fmt.Errorf("cooking formula: %w", err)
+	}
+	out, err := t.bd("--no-daemon", "mol", "wisp", f.Name, "--json")
+	if err != nil {
+		return fmt.Errorf("creating wisp: %w", err)
+	}
+	if t.root, err = parseWispIDFromJSON(out); err != nil {
+		return err
+	}
+
+	children, err := t.b.List(beads.ListOptions{Parent: t.root, Status: "all", Priority: -1})
+	if err != nil {
+		return err
+	}
+	for _, issue := range children {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			if stepID, ok := strings.CutPrefix(line, simStepPrefix); ok {
+				t.beads[stepID] = issue.ID
+				t.steps[issue.ID] = stepID
+				break
+			}
+		}
+	}
+	for _, step := range f.Steps {
+		if _, ok := t.beads[step.ID]; !ok {
+			return fmt.Errorf("wisp %s has no bead for step %s", t.root, step.ID)
+		}
+	}
+	return nil
+}
+
+func (t *beadsSimTracker) Start(stepID, agent string) error {
+	status := "in_progress"
+	return t.b.Update(t.beads[stepID], beads.UpdateOptions{Status: &status, Assignee: &agent})
+}
+
+func (t *beadsSimTracker) Finish(stepID string, outcome formula.Outcome) error {
+	if outcome == formula.OutcomeDone {
+		return t.b.Close(t.beads[stepID])
+	}
+	// A failed step stays unclosed, so its dependents stay blocked.
+	status := "blocked"
+	return t.b.Update(t.beads[stepID], beads.UpdateOptions{Status: &status})
+}
+
+func (t *beadsSimTracker) Ready() ([]string, error) {
+	open, err := t.b.List(beads.ListOptions{Parent: t.root, Status: "open", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(open))
+	for i, issue := range open {
+		ids[i] = issue.ID
+	}
+	details, err := t.b.ShowMultiple(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var ready []string
+	for _, id := range ids {
+		issue, ok := details[id]
+		if !ok {
+			continue
+		}
+		met := true
+		for _, dep := range issue.Dependencies {
+			if dep.DependencyType != "parent-child" && dep.Status != "closed" {
+				met = false
+				break
+			}
+		}
+		if met {
+			ready = append(ready, t.steps[id])
+		}
+	}
+	return ready, nil
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestBeadsSimTrackerPoursThroughBd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("bd stub is a shell script")
+	}
+	dir := t.TempDir()
+	binDir := t.TempDir()
+	logPath := filepath.Join(binDir, "bd.log")
+
+	// The stub cooks only formulas written to the simulation's formulas
+	// directory, and lists the wisp's steps with their tagged descriptions.
+	bdScript := `#!/bin/sh
+echo "$*" >> "${BD_LOG}"
+while [ "${1#--}" != "$1" ]; do shift; done
+case "$1" in
+  init) exit 0 ;;
+  cook) test -f "${BEADS_DIR}/formulas/$2.formula.toml" || exit 1 ;;
+  mol) echo '{"new_epic_id":"sim-wisp-1"}' ;;
+  list)
+    cat <<'JSON'
+[{"id":"sim-wisp-2","title":"Build","description":"sim-step: build"},
+ {"id":"sim-wisp-3","title":"Test","description":"sim-step: test\n\nRun the tests"}]
+JSON
+    ;;
+esac
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(bdScript), 0755); err != nil {
+		t.Fatalf("write bd stub: %v", err)
+	}
+	t.Setenv("BD_LOG", logPath)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(beads.NativeEnv, "0")
+
+	f, err := formula.Parse([]byte(`
+formula = "sim-demo"
+type = "workflow"
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[steps]]
+id = "test"
+title = "Test"
+description = "Run the tests"
+needs = ["build"]
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tracker, err := newBeadsSimTracker(dir)
+	if err != nil {
+		t.Fatalf("newBeadsSimTracker: %v", err)
+	}
+	if err := tracker.Pour(f); err != nil {
+		t.Fatalf("Pour: %v", err)
+	}
+
+	if tracker.root != "sim-wisp-1" {
+		t.Errorf("root = %q, want sim-wisp-1", tracker.root)
+	}
+	if tracker.beads["build"] != "sim-wisp-2" || tracker.beads["test"] != "sim-wisp-3" {
+		t.Errorf("step beads = %v", tracker.beads)
+	}
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read bd log: %v", err)
+	}
+	for _, want := range []string{"--no-daemon cook sim-demo", "--no-daemon mol wisp sim-demo --json"} {
+		if !strings.Contains(string(log), want) {
+			t.Errorf("bd log missing %q:\n%s", want, log)
+		}
+	}
+}
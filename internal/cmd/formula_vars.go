@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"golang.org/x/term"
+)
+
+// townVarChecker checks bead-id and rig formula vars against the town.
+type townVarChecker struct{}
+
+func (townVarChecker) CheckBead(id string) error {
+	return verifyBeadExists(id)
+}
+
+func (townVarChecker) CheckRig(name string) error {
+	if _, ok := IsRigName(name); !ok {
+		return fmt.Errorf("rig %q not found", name)
+	}
+	return nil
+}
+
+// resolveFormulaVars validates --var values for a formula. On a terminal,
+// missing required vars are prompted for first. Every problem is reported
+// in one error. Returns the values to pass on, including prompted ones.
+func resolveFormulaVars(f *formula.Formula, vars map[string]string) (map[string]string, error) {
+	if missing := f.MissingVars(vars); len(missing) > 0 && term.IsTerminal(int(os.Stdin.Fd())) {
+		promptFormulaVars(f, missing, vars, os.Stdin, os.Stdout)
+	}
+	if err := f.ValidateVars(vars, townVarChecker{}); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// promptFormulaVars asks for each missing var, showing its type,
+// description and enum choices. Empty answers are left missing so that
+// validation reports them.
+func promptFormulaVars(f *formula.Formula, missing []string, vars map[string]string, in io.Reader, out io.Writer) {
+	specs := make(map[string]formula.VarSpec)
+	for _, spec := range f.VarSpecs() {
+		specs[spec.Name] = spec
+	}
+
+	reader := bufio.NewReader(in)
+	for _, name := range missing {
+		// An earlier answer may have satisfied a required_unless group.
+		if !slices.Contains(f.MissingVars(vars), name) {
+			continue
+		}
+		spec := specs[name]
+		fmt.Fprintf(out, "%s %s", style.Bold.Render(name), style.Dim.Render("("+string(spec.Type)+")"))
+		if spec.Description != "" {
+			fmt.Fprintf(out, " %s", spec.Description)
+		}
+		if len(spec.Enum) > 0 {
+			fmt.Fprintf(out, " [%s]", spec.Choices())
+		}
+		fmt.Fprint(out, ": ")
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && answer == "" {
+			return // EOF: leave the rest missing
+		}
+		if answer = strings.TrimSpace(answer); answer != "" {
+			vars[name] = answer
+		}
+	}
+}
+
+// printFormulaVarSpecs lists a formula's typed vars and inputs with enum
+// choices, defaults and requirements. Prints nothing for a formula
+// without typed vars.
+func printFormulaVarSpecs(f *formula.Formula) {
+	specs := f.VarSpecs()
+	typed := false
+	width := 0
+	for _, spec := range specs {
+		typed = typed || spec.Type != formula.VarString
+		width = max(width, len(spec.Name))
+	}
+	if !typed {
+		return
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Vars:"))
+	for _, spec := range specs {
+		var notes []string
+		if len(spec.Enum) > 0 {
+			notes = append(notes, spec.Choices())
+		}
+		if spec.Default != "" {
+			notes = append(notes, "default: "+spec.Default)
+		}
+		switch {
+		case len(spec.RequiredUnless) > 0:
+			unless := append([]string(nil), spec.RequiredUnless...)
+			sort.Strings(unless)
+			notes = append(notes, "required unless "+strings.Join(unless, " or "))
+		case spec.Required:
+			notes = append(notes, "required")
+		}
+		line := fmt.Sprintf("  %-*s  %-8s", width, spec.Name, spec.Type)
+		if len(notes) > 0 {
+			line += "  " + style.Dim.Render(strings.Join(notes, "; "))
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+}
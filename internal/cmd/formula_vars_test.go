@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestPromptFormulaVars(t *testing.T) {
+	f, err := formula.Parse([]byte(`
+formula = "review"
+type = "workflow"
+
+[vars.scope]
+description = "What to review"
+enum = ["all", "diff"]
+required = true
+
+[inputs.pr]
+type = "int"
+required_unless = ["branch"]
+
+[inputs.branch]
+
+[[steps]]
+id = "review"
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	vars := map[string]string{}
+	var out bytes.Buffer
+	promptFormulaVars(f, f.MissingVars(vars), vars, strings.NewReader("42\ndiff\n"), &out)
+
+	if vars["pr"] != "42" || vars["scope"] != "diff" {
+		t.Errorf("vars = %v, want pr=42 scope=diff", vars)
+	}
+	if !strings.Contains(out.String(), "What to review [all | diff]") {
+		t.Errorf("prompt should show description and choices:\n%s", out.String())
+	}
+	if err := f.ValidateVars(vars, nil); err != nil {
+		t.Errorf("prompted vars should validate: %v", err)
+	}
+}
+
+func TestPromptFormulaVars_EOF(t *testing.T) {
+	f, err := formula.Parse([]byte(`
+formula = "f"
+type = "workflow"
+
+[vars.a]
+required = true
+
+[vars.b]
+required = true
+
+[[steps]]
+id = "s"
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	vars := map[string]string{}
+	promptFormulaVars(f, f.MissingVars(vars), vars, strings.NewReader("x"), &bytes.Buffer{})
+
+	if vars["a"] != "x" {
+		t.Errorf("a = %q, want x (answer without newline)", vars["a"])
+	}
+	if got := f.MissingVars(vars); len(got) != 1 || got[0] != "b" {
+		t.Errorf("MissingVars = %v, want [b]", got)
+	}
+}
+
+func TestRunSlingFormula_ValidatesVarsBeforeTarget(t *testing.T) {
+	townRoot := t.TempDir()
+	formulasDir := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor", "rig"), 0755); err != nil {
+		t.Fatalf("mkdir mayor/rig: %v", err)
+	}
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatalf("mkdir formulas: %v", err)
+	}
+	typed := `
+formula = "typed"
+type = "workflow"
+
+[vars.count]
+type = "int"
+
+[[steps]]
+id = "s"
+`
+	if err := os.WriteFile(filepath.Join(formulasDir, "typed.formula.toml"), []byte(typed), 0644); err != nil {
+		t.Fatalf("write formula: %v", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(townRoot); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	prevVars := slingVars
+	t.Cleanup(func() { slingVars = prevVars })
+	slingVars = []string{"count=many"}
+
+	// The target doesn't exist; a var error means validation ran first,
+	// before anything could be dispatched or spawned for it.
+	err = runSlingFormula([]string{"typed", "no-such-target"})
+	if err == nil || !strings.Contains(err.Error(), "count") {
+		t.Fatalf("runSlingFormula error = %v, want a var validation error for count", err)
+	}
+}
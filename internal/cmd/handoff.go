@@ -38,6 +38,7 @@ Examples:
   gt handoff gt-abc -s "Fix it"       # Hook with context, then restart
   gt handoff -s "Context" -m "Notes"  # Hand off with custom message
   gt handoff -c                       # Collect state into handoff message
+  gt handoff -c --decision "Use sqlite" --question "Keep the v1 shim?"
   gt handoff crew                     # Hand off crew session
   gt handoff mayor                    # Hand off mayor session
 
@@ -45,6 +46,13 @@ The --collect (-c) flag gathers current state (hooked work, inbox, ready beads,
 in-progress items) and includes it in the handoff mail. This provides context
 for the next session without manual summarization.
 
+Every handoff of your own session also writes a structured record (JSON
+plus a Markdown rendering) to <town>/.runtime/handoffs/<agent>/: hooked
+bead, molecule step and progress, git branch and commit, files touched
+since the previous handoff, and any --question/--decision given. The next
+'gt prime' shows what changed since the previous handoff; browse the chain
+with 'gt handoff history'.
+
 Any molecule on the hook will be auto-continued by the new session.
 The SessionStart hook runs 'gt prime' to restore context.`,
 	RunE: runHandoff,
@@ -56,6 +64,9 @@ var (
 	handoffSubject string
 	handoffMessage string
 	handoffCollect bool
+
+	handoffQuestions []string
+	handoffDecisions []string
 )
 
 func init() {
@@ -64,6 +75,8 @@ func init() {
 	handoffCmd.Flags().StringVarP(&handoffSubject, "subject", "s", "", "Subject for handoff mail (optional)")
 	handoffCmd.Flags().StringVarP(&handoffMessage, "message", "m", "", "Message body for handoff mail (optional)")
 	handoffCmd.Flags().BoolVarP(&handoffCollect, "collect", "c", false, "Auto-collect state (status, inbox, beads) into handoff message")
+	handoffCmd.Flags().StringArrayVar(&handoffQuestions, "question", nil, "Open question for the next session (repeatable)")
+	handoffCmd.Flags().StringArrayVar(&handoffDecisions, "decision", nil, "Decision made this session (repeatable)")
 	rootCmd.AddCommand(handoffCmd)
 }
 
@@ -93,6 +106,13 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 			handoffSubject = "Session handoff with context"
 		}
 	}
+	if notes := formatHandoffNotes(handoffDecisions, handoffQuestions); notes != "" {
+		if handoffMessage == "" {
+			handoffMessage = notes
+		} else {
+			handoffMessage = handoffMessage + "\n\n" + notes
+		}
+	}
 
 	t := tmux.NewTmux()
 
@@ -151,7 +171,8 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), currentSession)
 
 	// Log handoff event (both townlog and events feed)
-	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+	townRoot, _ := workspace.FindFromCwd()
+	if townRoot != "" {
 		agent := sessionToGTRole(currentSession)
 		if agent == "" {
 			agent = currentSession
@@ -163,6 +184,9 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 
 	// Dry run mode - show what would happen (BEFORE any side effects)
 	if handoffDryRun {
+		if townRoot != "" {
+			fmt.Println("Would write structured handoff record")
+		}
 		if handoffSubject != "" || handoffMessage != "" {
 			fmt.Printf("Would send handoff mail: subject=%q (auto-hooked)\n", handoffSubject)
 		}
@@ -171,6 +195,15 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Record structured state for the next session (non-fatal)
+	if townRoot != "" {
+		if rec, err := writeHandoffRecord(townRoot, currentSession); err != nil {
+			style.PrintWarning("could not write handoff record: %v", err)
+		} else {
+			fmt.Printf("%s Recorded handoff %s\n", style.Bold.Render("📝"), rec.ID)
+		}
+	}
+
 	// If subject/message provided, send handoff mail to self first
 	// The mail is auto-hooked so the next session picks it up
 	if handoffSubject != "" || handoffMessage != "" {
@@ -349,6 +382,13 @@ var claudeEnvVars = []string{
 // This needs to be the actual command to execute (e.g., claude), not a session attach command.
 // The command includes a cd to the correct working directory for the role.
 func buildRestartCommand(sessionName string) (string, error) {
+	return buildRestartCommandWithAgent(sessionName, "")
+}
+
+// buildRestartCommandWithAgent is buildRestartCommand with an agent override
+// (e.g. the agent mapped to a molecule step's tier). An empty agent uses the
+// default resolution.
+func buildRestartCommandWithAgent(sessionName, agent string) (string, error) {
 	// Detect town root from current directory
 	townRoot := detectTownRootFromCwd()
 	if townRoot == "" {
@@ -384,6 +424,16 @@ func buildRestartCommand(sessionName string) (string, error) {
 	// 4. run claude with the startup beacon (triggers immediate context loading)
 	// Use exec to ensure clean process replacement.
 	runtimeCmd := config.GetRuntimeCommandWithPrompt("", beacon)
+	if agent != "" {
+		rigPath := ""
+		if identity.Rig != "" {
+			rigPath = filepath.Join(townRoot, identity.Rig)
+		}
+		runtimeCmd, err = config.GetRuntimeCommandWithPromptAndAgentOverride(rigPath, beacon, agent)
+		if err != nil {
+			return "", err
+		}
+	}
 
 	// Build environment exports - role vars first, then Claude vars
 	var exports []string
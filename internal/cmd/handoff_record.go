@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/handoff"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	handoffHistoryJSON  bool
+	handoffHistoryFull  bool
+	handoffHistoryLimit int
+)
+
+var handoffHistoryCmd = &cobra.Command{
+	Use:   "history [role]",
+	Short: "Browse the chain of structured handoff records",
+	Long: `List an agent's handoff records, newest first, with what changed
+between each handoff and the one before it.
+
+The role defaults to the current agent. It can be a town role (mayor,
+deacon), a rig role shortcut resolved from the current rig (witness,
+refinery, crew), or a full address (gastown/witness, gastown/crew/max).
+
+Examples:
+  gt handoff history
+  gt handoff history mayor --full
+  gt handoff history gastown/crew/max -n 5 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHandoffHistory,
+}
+
+func init() {
+	handoffHistoryCmd.Flags().BoolVar(&handoffHistoryJSON, "json", false, "Output records as JSON")
+	handoffHistoryCmd.Flags().BoolVar(&handoffHistoryFull, "full", false, "Show each record in full")
+	handoffHistoryCmd.Flags().IntVarP(&handoffHistoryLimit, "limit", "n", 10, "Maximum records to show (0 for all)")
+	handoffCmd.AddCommand(handoffHistoryCmd)
+}
+
+// writeHandoffRecord captures the current agent's state and appends it to
+// the agent's handoff chain.
+func writeHandoffRecord(townRoot, sessionName string) (*handoff.Record, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("detecting role: %w", err)
+	}
+	agent := getAgentIdentity(RoleContext{Role: roleInfo.Role, Rig: roleInfo.Rig, Polecat: roleInfo.Polecat})
+	if agent == "" {
+		return nil, fmt.Errorf("cannot determine agent identity (role: %s)", roleInfo.Role)
+	}
+
+	rec := &handoff.Record{
+		Agent:         agent,
+		Session:       sessionName,
+		Subject:       handoffSubject,
+		Message:       handoffMessage,
+		HookedBead:    detectHookedBead(cwd, roleInfo),
+		OpenQuestions: handoffQuestions,
+		Decisions:     handoffDecisions,
+	}
+	if moleculeID, stepID, stepTitle := detectMoleculeContext(cwd, roleInfo); moleculeID != "" {
+		rec.Molecule = handoffMolecule(cwd, moleculeID, stepID, stepTitle)
+	}
+
+	cp, err := checkpoint.Capture(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("capturing git state: %w", err)
+	}
+	rec.WithCheckpoint(cp)
+
+	// Files touched are relative to where the previous handoff left off
+	base := ""
+	if prev, err := handoff.Latest(townRoot, agent); err == nil && prev != nil && prev.Git != nil {
+		base = prev.Git.LastCommit
+	}
+	rec.FilesTouched = handoff.TouchedFiles(cwd, base, cp.ModifiedFiles)
+
+	if err := handoff.Save(townRoot, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// handoffMolecule records the molecule position, including which of the
+// instance's steps are closed.
+func handoffMolecule(workDir, moleculeID, stepID, stepTitle string) *handoff.Molecule {
+	m := &handoff.Molecule{ID: moleculeID, Step: stepID, StepTitle: stepTitle}
+	b := beads.New(workDir)
+	step, err := b.Show(stepID)
+	if err != nil || step.Parent == "" {
+		return m
+	}
+	m.Root = step.Parent
+	children, err := b.List(beads.ListOptions{
+		Parent:   m.Root,
+		Status:   "all",
+		Priority: -1,
+	})
+	if err != nil {
+		return m
+	}
+	m.StepsTotal = len(children)
+	for _, child := range children {
+		if child.Status == "closed" {
+			m.StepsDone = append(m.StepsDone, child.ID)
+		}
+	}
+	return m
+}
+
+// formatHandoffNotes renders decisions and open questions for the handoff mail.
+func formatHandoffNotes(decisions, questions []string) string {
+	var parts []string
+	if len(decisions) > 0 {
+		parts = append(parts, "## Decisions\n- "+strings.Join(decisions, "\n- "))
+	}
+	if len(questions) > 0 {
+		parts = append(parts, "## Open Questions\n- "+strings.Join(questions, "\n- "))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func runHandoffHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	role := ""
+	if len(args) > 0 {
+		role = args[0]
+	}
+	agent, err := resolveHandoffAgent(townRoot, role)
+	if err != nil {
+		return err
+	}
+
+	records, err := handoff.History(townRoot, agent, handoffHistoryLimit)
+	if err != nil {
+		return err
+	}
+
+	if handoffHistoryJSON {
+		if records == nil {
+			records = []*handoff.Record{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("%s No handoff records for %s\n", style.Dim.Render("○"), agent)
+		return nil
+	}
+
+	for i, r := range records {
+		var prev *handoff.Record
+		if i+1 < len(records) && records[i+1].ID == r.Previous {
+			prev = records[i+1]
+		} else if r.Previous != "" {
+			prev, _ = handoff.Load(townRoot, agent, r.Previous)
+		}
+		delta := handoff.Diff(prev, r)
+
+		if handoffHistoryFull {
+			if i > 0 {
+				fmt.Println("---")
+			}
+			fmt.Print(r.Markdown())
+			fmt.Printf("\nSince previous: %s\n\n", delta.Summary())
+			continue
+		}
+
+		subject := r.Subject
+		if subject == "" {
+			subject = style.Dim.Render("(no subject)")
+		}
+		fmt.Printf("%s  %s  %s\n",
+			r.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			style.Bold.Render(r.ID),
+			subject)
+		fmt.Printf("    %s\n", style.Dim.Render(delta.Summary()))
+	}
+	return nil
+}
+
+// resolveHandoffAgent turns a role argument into the agent address records
+// are stored under. An empty role means the current agent.
+func resolveHandoffAgent(townRoot, role string) (string, error) {
+	role = strings.Trim(role, "/")
+	if strings.Contains(role, "/") {
+		return role, nil
+	}
+	switch strings.ToLower(role) {
+	case "mayor", "deacon", "boot":
+		return strings.ToLower(role), nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting current directory: %w", err)
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return "", fmt.Errorf("detecting role: %w", err)
+	}
+	ctx := RoleContext{Role: roleInfo.Role, Rig: roleInfo.Rig, Polecat: roleInfo.Polecat}
+	switch strings.ToLower(role) {
+	case "":
+	case "witness":
+		ctx.Role = RoleWitness
+	case "refinery":
+		ctx.Role = RoleRefinery
+	case "crew":
+		if ctx.Role != RoleCrew {
+			return "", fmt.Errorf("not in a crew workspace - use <rig>/crew/<name>")
+		}
+	default:
+		return "", fmt.Errorf("unknown role %q - use mayor, deacon, witness, refinery, crew or a full address", role)
+	}
+	if (ctx.Role == RoleWitness || ctx.Role == RoleRefinery) && ctx.Rig == "" {
+		return "", fmt.Errorf("cannot determine rig - use <rig>/%s", role)
+	}
+	agent := getAgentIdentity(ctx)
+	if agent == "" {
+		return "", fmt.Errorf("cannot determine agent identity - specify a role")
+	}
+	return agent, nil
+}
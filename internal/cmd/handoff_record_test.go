@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/handoff"
+)
+
+func TestFormatHandoffNotes(t *testing.T) {
+	if got := formatHandoffNotes(nil, nil); got != "" {
+		t.Errorf("formatHandoffNotes(nil, nil) = %q", got)
+	}
+	got := formatHandoffNotes([]string{"Use sqlite"}, []string{"Keep v1?", "Who deploys?"})
+	want := "## Decisions\n- Use sqlite\n\n## Open Questions\n- Keep v1?\n- Who deploys?"
+	if got != want {
+		t.Errorf("formatHandoffNotes = %q, want %q", got, want)
+	}
+}
+
+func TestResolveHandoffAgentAddresses(t *testing.T) {
+	for role, want := range map[string]string{
+		"mayor":             "mayor",
+		"mayor/":            "mayor",
+		"Deacon":            "deacon",
+		"gastown/witness":   "gastown/witness",
+		"gastown/crew/max/": "gastown/crew/max",
+	} {
+		got, err := resolveHandoffAgent(t.TempDir(), role)
+		if err != nil || got != want {
+			t.Errorf("resolveHandoffAgent(%q) = %q, %v; want %q", role, got, err, want)
+		}
+	}
+}
+
+func TestOutputHandoffContentShowsDelta(t *testing.T) {
+	town := t.TempDir()
+	ctx := RoleContext{Role: RoleMayor, TownRoot: town, WorkDir: town}
+
+	if out := captureStdout(t, func() { outputHandoffContent(ctx) }); out != "" {
+		t.Errorf("expected no output without handoff content, got %q", out)
+	}
+
+	prev := &handoff.Record{
+		Agent:     "mayor",
+		CreatedAt: time.Now().Add(-2 * time.Hour),
+		Molecule:  &handoff.Molecule{ID: "mol-x", Root: "hq-1", StepsDone: []string{"hq-1.1"}},
+	}
+	cur := &handoff.Record{
+		Agent:        "mayor",
+		CreatedAt:    time.Now().Add(-time.Minute),
+		Molecule:     &handoff.Molecule{ID: "mol-x", Root: "hq-1", StepsDone: []string{"hq-1.1", "hq-1.2", "hq-1.3"}},
+		FilesTouched: []string{"a.go", "b.go", "c.go"},
+	}
+	for _, r := range []*handoff.Record{prev, cur} {
+		if err := handoff.Save(town, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := captureStdout(t, func() { outputHandoffContent(ctx) })
+	for _, want := range []string{
+		"Handoff from Previous Session",
+		"Since last session:** 2 steps done, 3 files changed",
+		"Steps done: hq-1.2, hq-1.3",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+
+	staleTown := t.TempDir()
+	if err := handoff.Save(staleTown, &handoff.Record{Agent: "mayor", CreatedAt: time.Now().Add(-48 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if out := captureStdout(t, func() { outputHandoffContent(RoleContext{Role: RoleMayor, TownRoot: staleTown}) }); out != "" {
+		t.Errorf("stale record should not be shown, got %q", out)
+	}
+}
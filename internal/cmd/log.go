@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditchain"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -172,10 +175,32 @@ func followLog(logPath string) error {
 	fmt.Printf("%s Following %s (Ctrl+C to stop)\n\n", style.Dim.Render("○"), logPath)
 
 	tailCmd := exec.Command("tail", "-f", logPath)
-	tailCmd.Stdout = os.Stdout
 	tailCmd.Stderr = os.Stderr
+	out, err := tailCmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := tailCmd.Start(); err != nil {
+		return err
+	}
+	if err := copyLogLines(os.Stdout, out); err != nil {
+		_ = tailCmd.Process.Kill()
+		_ = tailCmd.Wait()
+		return err
+	}
+	return tailCmd.Wait()
+}
 
-	return tailCmd.Run()
+// copyLogLines copies town log lines from r to w without their audit chain
+// links, as gt log shows them.
+func copyLogLines(w io.Writer, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if _, err := fmt.Fprintln(w, auditchain.StripText(scanner.Text())); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
 }
 
 // printEvent prints a single event with styling.
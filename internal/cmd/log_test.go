@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/auditchain"
+)
+
+func TestCopyLogLinesStripsChainLinks(t *testing.T) {
+	bodies := []string{
+		"2026-10-18 12:00:00 [spawn] gastown/polecats/toast spawned for gt-1",
+		"2026-10-18 12:05:00 [done] gastown/polecats/toast completed gt-1",
+	}
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, "town.log")
+	for _, b := range bodies {
+		if err := auditchain.Append(townRoot, path, 0644, auditchain.Text, []byte(b), nil); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := copyLogLines(&out, bytes.NewReader(raw)); err != nil {
+		t.Fatalf("copyLogLines: %v", err)
+	}
+	if got, want := out.String(), strings.Join(bodies, "\n")+"\n"; got != want {
+		t.Errorf("followed output = %q, want %q", got, want)
+	}
+}
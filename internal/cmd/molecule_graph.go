@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+var moleculeGraphFormat string
+
+var moleculeGraphCmd = &cobra.Command{
+	Use:   "graph <root-issue-id>",
+	Short: "Render a live molecule's step graph as Mermaid or DOT",
+	Long: `Render the steps of an instantiated molecule as a Mermaid flowchart or
+Graphviz DOT, for pasting into PRs and design docs.
+
+Each node shows the step bead, its title and its assignee, colored by
+status:
+  open          ready to start (white)
+  in progress   claimed or hooked (amber)
+  closed        done (green)
+  blocked       open, but waiting on dependencies or WaitsFor (red)
+
+Examples:
+  gt mol graph gt-abc
+  gt mol graph gt-abc --format dot | dot -Tpng > mol.png
+  gt mol graph gt-abc --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeGraph,
+}
+
+func init() {
+	moleculeGraphCmd.Flags().StringVar(&moleculeGraphFormat, "format", "mermaid", "Output format: mermaid or dot")
+	moleculeGraphCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output nodes and edges as JSON")
+
+	moleculeCmd.AddCommand(moleculeGraphCmd)
+}
+
+func runMoleculeGraph(cmd *cobra.Command, args []string) error {
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("not in a beads workspace: %w", err)
+	}
+
+	g, err := beads.New(workDir).MoleculeGraph(args[0])
+	if err != nil {
+		return err
+	}
+	return printGraph(g, moleculeGraphFormat, moleculeJSON)
+}
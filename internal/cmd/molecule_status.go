@@ -99,16 +99,17 @@ func buildAgentBeadID(identity string, role Role, townRoot string) string {
 
 // MoleculeProgressInfo contains progress information for a molecule instance.
 type MoleculeProgressInfo struct {
-	RootID       string   `json:"root_id"`
-	RootTitle    string   `json:"root_title"`
-	MoleculeID   string   `json:"molecule_id,omitempty"`
-	TotalSteps   int      `json:"total_steps"`
-	DoneSteps    int      `json:"done_steps"`
-	InProgress   int      `json:"in_progress_steps"`
-	ReadySteps   []string `json:"ready_steps"`
-	BlockedSteps []string `json:"blocked_steps"`
-	Percent      int      `json:"percent_complete"`
-	Complete     bool     `json:"complete"`
+	RootID       string     `json:"root_id"`
+	RootTitle    string     `json:"root_title"`
+	MoleculeID   string     `json:"molecule_id,omitempty"`
+	TotalSteps   int        `json:"total_steps"`
+	DoneSteps    int        `json:"done_steps"`
+	InProgress   int        `json:"in_progress_steps"`
+	ReadySteps   []string   `json:"ready_steps"`
+	BlockedSteps []string   `json:"blocked_steps"`
+	WaitingSteps []StepWait `json:"waiting_steps,omitempty"` // blocked only by WaitsFor
+	Percent      int        `json:"percent_complete"`
+	Complete     bool       `json:"complete"`
 }
 
 // MoleculeStatusInfo contains status information for an agent's work.
@@ -212,6 +213,15 @@ func runMoleculeProgress(cmd *cobra.Command, args []string) error {
 			}
 
 			if len(child.DependsOn) == 0 || allDepsClosed {
+				unmet, err := unmetStepWaits(b, child)
+				if err != nil {
+					return err
+				}
+				if len(unmet) > 0 {
+					progress.BlockedSteps = append(progress.BlockedSteps, child.ID)
+					progress.WaitingSteps = append(progress.WaitingSteps, StepWait{StepID: child.ID, Unmet: unmet})
+					continue
+				}
 				progress.ReadySteps = append(progress.ReadySteps, child.ID)
 			} else {
 				progress.BlockedSteps = append(progress.BlockedSteps, child.ID)
@@ -255,6 +265,7 @@ func runMoleculeProgress(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 	fmt.Printf("  Blocked:     %d\n", len(progress.BlockedSteps))
+	printStepWaits("    ", progress.WaitingSteps)
 
 	if progress.Complete {
 		fmt.Printf("\n  %s\n", style.Bold.Render("✓ Molecule complete!"))
@@ -537,6 +548,15 @@ func getMoleculeProgressInfo(b *beads.Beads, moleculeRootID string) (*MoleculePr
 			}
 
 			if len(child.DependsOn) == 0 || allDepsClosed {
+				unmet, err := unmetStepWaits(b, child)
+				if err != nil {
+					return nil, err
+				}
+				if len(unmet) > 0 {
+					progress.BlockedSteps = append(progress.BlockedSteps, child.ID)
+					progress.WaitingSteps = append(progress.WaitingSteps, StepWait{StepID: child.ID, Unmet: unmet})
+					continue
+				}
 				progress.ReadySteps = append(progress.ReadySteps, child.ID)
 			} else {
 				progress.BlockedSteps = append(progress.BlockedSteps, child.ID)
@@ -571,6 +591,10 @@ func determineNextAction(status MoleculeStatusInfo) string {
 		return fmt.Sprintf("Start next ready step: bd update %s --status=in_progress", status.Progress.ReadySteps[0])
 	}
 
+	if len(status.Progress.WaitingSteps) > 0 {
+		return "Waiting on WaitsFor conditions - wake mail is sent when they clear"
+	}
+
 	if len(status.Progress.BlockedSteps) > 0 {
 		return "All remaining steps are blocked - waiting on dependencies"
 	}
@@ -662,6 +686,7 @@ func outputMoleculeStatus(status MoleculeStatusInfo) error {
 		}
 		fmt.Println()
 		fmt.Printf("  Blocked:     %d\n", len(status.Progress.BlockedSteps))
+		printStepWaits("    ", status.Progress.WaitingSteps)
 
 		if status.Progress.Complete {
 			fmt.Printf("\n%s\n", style.Bold.Render("✓ Molecule complete!"))
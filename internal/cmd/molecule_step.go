@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/ptyd"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -24,11 +28,19 @@ This command handles the step-to-step transition for polecats:
 
 1. Closes the completed step (bd close <step-id>)
 2. Extracts the molecule ID from the step
-3. Finds the next ready step (dependency-aware)
+3. Finds the next ready step (dependency-aware, and honoring WaitsFor
+   conditions such as all-children or bead:<id>-closed)
 4. If next step exists:
    - Updates the hook to point to the next step
-   - Respawns the pane for a fresh session
-5. If molecule complete:
+   - Respawns the pane for a fresh session, on the agent mapped to the
+     step's Tier (tier_agents in town or rig settings), if any.
+     Changing tiers writes a checkpoint first so the new agent resumes
+     with the hook and molecule context.
+5. If only WaitsFor conditions block the remaining steps:
+   - Shows the unmet conditions
+   - Registers this agent as a waiter; 'gt mol wake' sends wake mail
+     when the conditions clear
+6. If molecule complete:
    - Clears the hook
    - Sends POLECAT_DONE to witness
    - Exits the session
@@ -53,13 +65,14 @@ func init() {
 
 // StepDoneResult is the result of a step done operation.
 type StepDoneResult struct {
-	StepID       string `json:"step_id"`
-	MoleculeID   string `json:"molecule_id"`
-	StepClosed   bool   `json:"step_closed"`
-	NextStepID   string `json:"next_step_id,omitempty"`
-	NextStepTitle string `json:"next_step_title,omitempty"`
-	Complete     bool   `json:"complete"`
-	Action       string `json:"action"` // "continue", "done", "no_more_ready"
+	StepID        string     `json:"step_id"`
+	MoleculeID    string     `json:"molecule_id"`
+	StepClosed    bool       `json:"step_closed"`
+	NextStepID    string     `json:"next_step_id,omitempty"`
+	NextStepTitle string     `json:"next_step_title,omitempty"`
+	Complete      bool       `json:"complete"`
+	Action        string     `json:"action"`            // "continue", "done", "no_more_ready"
+	Waiting       []StepWait `json:"waiting,omitempty"` // steps held back by WaitsFor
 }
 
 func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
@@ -117,7 +130,7 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	}
 
 	// Step 4: Find the next ready step
-	nextStep, allComplete, err := findNextReadyStep(b, moleculeID)
+	nextStep, allComplete, waiting, err := findNextReadyStep(b, moleculeID)
 	if err != nil {
 		return fmt.Errorf("finding next step: %w", err)
 	}
@@ -130,8 +143,21 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		result.NextStepTitle = nextStep.Title
 		result.Action = "continue"
 	} else {
-		// There are more steps but none are ready (blocked on dependencies)
+		// There are more steps but none are ready (blocked on dependencies
+		// or WaitsFor conditions)
 		result.Action = "no_more_ready"
+		result.Waiting = waiting
+	}
+
+	// Register as a waiter so 'gt mol wake' notifies us when the conditions
+	// clear, and wake anyone whose conditions this step just satisfied.
+	if !moleculeStepDryRun {
+		if err := registerStepWaiters(b, waiting, detectSender()); err != nil {
+			style.PrintWarning("could not register waiter: %v", err)
+		}
+		if _, err := wakeStepWaiters(b, townRoot, false); err != nil {
+			style.PrintWarning("could not wake waiting agents: %v", err)
+		}
 	}
 
 	// JSON output
@@ -144,12 +170,19 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	// Step 5: Handle next action
 	switch result.Action {
 	case "continue":
-		return handleStepContinue(cwd, townRoot, workDir, nextStep, moleculeStepDryRun)
+		return handleStepContinue(cwd, townRoot, step, nextStep, moleculeStepDryRun)
 
 	case "done":
 		return handleMoleculeComplete(cwd, townRoot, moleculeID, moleculeStepDryRun)
 
 	case "no_more_ready":
+		if len(waiting) > 0 {
+			fmt.Printf("\n%s Remaining steps are waiting on WaitsFor conditions:\n",
+				style.Dim.Render("ℹ"))
+			printStepWaits("  ", waiting)
+			fmt.Printf("You will get wake mail when they clear (gt mol wake)\n")
+			return nil
+		}
 		fmt.Printf("\n%s All remaining steps are blocked - waiting on dependencies\n",
 			style.Dim.Render("ℹ"))
 		fmt.Printf("Run 'gt mol progress %s' to see blocked steps\n", moleculeID)
@@ -187,10 +220,12 @@ func extractMoleculeIDFromStep(stepID string) string {
 }
 
 // findNextReadyStep finds the next ready step in a molecule.
-// Returns (nextStep, allComplete, error).
-// If all steps are complete, returns (nil, true, nil).
-// If no steps are ready but some are blocked/in_progress, returns (nil, false, nil).
-func findNextReadyStep(b *beads.Beads, moleculeID string) (*beads.Issue, bool, error) {
+// Returns (nextStep, allComplete, waiting, error).
+// If all steps are complete, returns (nil, true, nil, nil).
+// If no steps are ready but some are blocked/in_progress, returns (nil, false,
+// waiting, nil), where waiting lists steps held back only by unmet WaitsFor
+// conditions.
+func findNextReadyStep(b *beads.Beads, moleculeID string) (*beads.Issue, bool, []StepWait, error) {
 	// Get all children of the molecule
 	children, err := b.List(beads.ListOptions{
 		Parent:   moleculeID,
@@ -198,11 +233,11 @@ func findNextReadyStep(b *beads.Beads, moleculeID string) (*beads.Issue, bool, e
 		Priority: -1,
 	})
 	if err != nil {
-		return nil, false, fmt.Errorf("listing molecule steps: %w", err)
+		return nil, false, nil, fmt.Errorf("listing molecule steps: %w", err)
 	}
 
 	if len(children) == 0 {
-		return nil, true, nil // No steps = complete
+		return nil, true, nil, nil // No steps = complete
 	}
 
 	// Build set of closed step IDs and collect open steps
@@ -227,10 +262,12 @@ func findNextReadyStep(b *beads.Beads, moleculeID string) (*beads.Issue, bool, e
 
 	// Check if all complete
 	if !hasNonClosedSteps {
-		return nil, true, nil
+		return nil, true, nil, nil
 	}
 
-	// Find ready steps (open steps with all dependencies closed)
+	// Find ready steps (open steps with all dependencies closed and all
+	// WaitsFor conditions met)
+	var waiting []StepWait
 	for _, step := range openSteps {
 		allDepsClosed := true
 		for _, depID := range step.DependsOn {
@@ -241,16 +278,24 @@ func findNextReadyStep(b *beads.Beads, moleculeID string) (*beads.Issue, bool, e
 		}
 
 		if len(step.DependsOn) == 0 || allDepsClosed {
-			return step, false, nil
+			unmet, err := unmetStepWaits(b, step)
+			if err != nil {
+				return nil, false, nil, err
+			}
+			if len(unmet) > 0 {
+				waiting = append(waiting, StepWait{StepID: step.ID, Unmet: unmet})
+				continue
+			}
+			return step, false, nil, nil
 		}
 	}
 
-	// No ready steps (all blocked or in_progress)
-	return nil, false, nil
+	// No ready steps (all blocked, waiting or in_progress)
+	return nil, false, waiting, nil
 }
 
-// handleStepContinue handles continuing to the next step.
-func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun bool) error { // workDir unused but kept for signature consistency
+// handleStepContinue handles continuing from doneStep to the next step.
+func handleStepContinue(cwd, townRoot string, doneStep, nextStep *beads.Issue, dryRun bool) error {
 	fmt.Printf("\n%s Next step: %s\n", style.Bold.Render("→"), nextStep.ID)
 	fmt.Printf("  %s\n", nextStep.Title)
 
@@ -260,6 +305,25 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 		return fmt.Errorf("detecting role: %w", err)
 	}
 
+	// Route the next step to the agent mapped to its tier. A change of
+	// agent is checkpointed; both sides are validated, so an unusable
+	// mapping counts as the default agent.
+	rigPath := ""
+	if roleInfo.Rig != "" {
+		rigPath = filepath.Join(townRoot, roleInfo.Rig)
+	}
+	doneTier := beads.ParseStepTier(doneStep.Description)
+	nextTier := beads.ParseStepTier(nextStep.Description)
+	tierAgent := config.ResolveTierAgent(nextTier, townRoot, rigPath)
+	tierSwitch := tierAgent != config.EffectiveTierAgent(doneTier, townRoot, rigPath)
+	if tierSwitch {
+		agentName := tierAgent
+		if agentName == "" {
+			agentName = "default agent"
+		}
+		fmt.Printf("  Tier: %s → %s (%s)\n", tierLabel(doneTier), tierLabel(nextTier), agentName)
+	}
+
 	roleCtx := RoleContext{
 		Role:     roleInfo.Role,
 		Rig:      roleInfo.Rig,
@@ -280,6 +344,9 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 
 	if dryRun {
 		fmt.Printf("\n[dry-run] Would pin next step: %s\n", nextStep.ID)
+		if tierSwitch {
+			fmt.Printf("[dry-run] Would write tier handoff checkpoint\n")
+		}
 		fmt.Printf("[dry-run] Would respawn pane\n")
 		return nil
 	}
@@ -294,11 +361,21 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 
 	fmt.Printf("%s Next step pinned: %s\n", style.Bold.Render("📌"), nextStep.ID)
 
+	if tierSwitch {
+		if err := writeTierHandoffCheckpoint(cwd, roleInfo, nextStep, doneTier, nextTier); err != nil {
+			// Non-fatal: the hook still carries the work
+			style.PrintWarning("could not write tier handoff checkpoint: %v", err)
+		}
+	}
+
 	// Respawn the pane
 	if !tmux.IsInsideTmux() {
 		// Not in tmux - just print next action
 		fmt.Printf("\n%s Not in tmux - start new session with 'gt prime'\n",
 			style.Dim.Render("ℹ"))
+		if tierAgent != "" {
+			fmt.Printf("  Use agent %s for tier %s\n", tierAgent, nextTier)
+		}
 		return nil
 	}
 
@@ -313,14 +390,21 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 		return fmt.Errorf("getting session name: %w", err)
 	}
 
-	restartCmd, err := buildRestartCommand(currentSession)
+	restartCmd, err := buildRestartCommandWithAgent(currentSession, tierAgent)
 	if err != nil {
 		return fmt.Errorf("building restart command: %w", err)
 	}
 
 	fmt.Printf("\n%s Respawning for next step...\n", style.Bold.Render("🔄"))
 
-	t := tmux.NewTmux()
+	// Respawning a pane in place is tmux-only; other backends restart the
+	// agent by starting a new session.
+	t, ok := tmux.AsTmux(ptyd.NewBackend())
+	if !ok {
+		fmt.Printf("%s Session backend can't respawn in place - start a new session with 'gt prime'\n",
+			style.Dim.Render("ℹ"))
+		return nil
+	}
 
 	// Clear history before respawn
 	if err := t.ClearHistory(pane); err != nil {
@@ -331,6 +415,38 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 	return t.RespawnPane(pane, restartCmd)
 }
 
+// tierLabel formats a step tier for display.
+func tierLabel(tier string) string {
+	if tier == "" {
+		return "none"
+	}
+	return tier
+}
+
+// writeTierHandoffCheckpoint records the hook and molecule position before
+// the session is respawned on a different agent, so the successor resumes
+// the next step even though it can't see the predecessor's transcript.
+func writeTierHandoffCheckpoint(cwd string, roleInfo RoleInfo, nextStep *beads.Issue, fromTier, toTier string) error {
+	// Only polecats and crew workers use checkpoints
+	if roleInfo.Role != RolePolecat && roleInfo.Role != RoleCrew {
+		return nil
+	}
+
+	cp, err := checkpoint.Capture(cwd)
+	if err != nil {
+		return err
+	}
+	cp.WithMolecule(extractMoleculeIDFromStep(nextStep.ID), nextStep.ID, nextStep.Title)
+	cp.WithHookedBead(nextStep.ID)
+	cp.WithNotes(fmt.Sprintf("Tier handoff: %s -> %s", tierLabel(fromTier), tierLabel(toTier)))
+	if roleInfo.Polecat != "" {
+		if _, err := cp.WithSnapshot(cwd, roleInfo.Polecat); err != nil {
+			style.PrintWarning("could not snapshot uncommitted work: %v", err)
+		}
+	}
+	return checkpoint.Write(cwd, cp)
+}
+
 // handleMoleculeComplete handles when a molecule is complete.
 func handleMoleculeComplete(cwd, townRoot, moleculeID string, dryRun bool) error {
 	fmt.Printf("\n%s Molecule complete!\n", style.Bold.Render("🎉"))
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var moleculeWakeCmd = &cobra.Command{
+	Use:   "wake",
+	Short: "Wake agents whose WaitsFor conditions have cleared",
+	Long: `Re-evaluate steps that blocked an agent on WaitsFor conditions and send
+wake mail to the waiting agents once every condition is met.
+
+A step's WaitsFor line gates it on the live bead graph:
+  all-children          the step (or the steps it needs) has children,
+                        and every one is closed
+  any-children          at least one child is closed
+  N-of-children         at least N children are closed
+  bead:<id>-closed      a specific bead is closed
+
+Any other condition is rejected when the molecule is validated.
+
+When 'gt mol step done' finds no ready step because of an unmet condition,
+it labels the step gt:waiting and records the agent as a waiter. This
+command is run after every 'gt mol step done' and by the Deacon patrol.
+
+Examples:
+  gt mol wake              # Notify waiters whose conditions cleared
+  gt mol wake --dry-run    # Show who would be woken`,
+	Args: cobra.NoArgs,
+	RunE: runMoleculeWake,
+}
+
+var moleculeWakeDryRun bool
+
+func init() {
+	moleculeWakeCmd.Flags().BoolVarP(&moleculeWakeDryRun, "dry-run", "n", false, "Show what would be done")
+	moleculeWakeCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+
+	moleculeCmd.AddCommand(moleculeWakeCmd)
+}
+
+// StepWait describes a step held back by unmet WaitsFor conditions.
+type StepWait struct {
+	StepID string             `json:"step_id"`
+	Unmet  []beads.WaitResult `json:"unmet"`
+}
+
+// MoleculeWakeResult is the result of a wake pass.
+type MoleculeWakeResult struct {
+	Checked  int      `json:"checked"`
+	Cleared  []string `json:"cleared"`
+	Notified []string `json:"notified"`
+	Failed   []string `json:"failed,omitempty"`
+}
+
+// unmetStepWaits evaluates a step's WaitsFor conditions and returns the
+// ones not yet met. Returns nil if the step can start.
+func unmetStepWaits(b *beads.Beads, step *beads.Issue) ([]beads.WaitResult, error) {
+	results, err := b.EvaluateWaits(step)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating waits for %s: %w", step.ID, err)
+	}
+	return beads.UnmetWaits(results), nil
+}
+
+// formatUnmetWaits renders unmet conditions as "cond (detail); ...".
+func formatUnmetWaits(unmet []beads.WaitResult) string {
+	parts := make([]string, 0, len(unmet))
+	for _, w := range unmet {
+		if w.Detail != "" {
+			parts = append(parts, fmt.Sprintf("%s (%s)", w.Condition, w.Detail))
+		} else {
+			parts = append(parts, w.Condition)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// printStepWaits prints the steps held back by WaitsFor conditions.
+func printStepWaits(indent string, waiting []StepWait) {
+	for _, w := range waiting {
+		fmt.Printf("%s%s waits for: %s\n", indent, w.StepID, formatUnmetWaits(w.Unmet))
+	}
+}
+
+// registerStepWaiters marks waiting steps so that 'gt mol wake' notifies
+// the agent once their conditions clear.
+func registerStepWaiters(b *beads.Beads, waiting []StepWait, agent string) error {
+	if agent == "" {
+		return nil
+	}
+	for _, w := range waiting {
+		if err := b.Update(w.StepID, beads.UpdateOptions{
+			AddLabels: []string{beads.WaitingLabel, beads.WaiterLabelPrefix + agent},
+		}); err != nil {
+			return fmt.Errorf("registering waiter on %s: %w", w.StepID, err)
+		}
+	}
+	return nil
+}
+
+func runMoleculeWake(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding workspace: %w", err)
+	}
+	if townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("not in a beads workspace: %w", err)
+	}
+
+	result, err := wakeStepWaiters(beads.New(workDir), townRoot, moleculeWakeDryRun)
+	if err != nil {
+		return err
+	}
+
+	if moleculeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if len(result.Cleared) == 0 {
+		fmt.Printf("%s No waiting steps cleared (%d checked)\n", style.Dim.Render("○"), result.Checked)
+		return nil
+	}
+	prefix := ""
+	if moleculeWakeDryRun {
+		prefix = "[dry-run] Would wake: "
+	}
+	fmt.Printf("%s %sWaitsFor cleared on %s\n", style.Bold.Render("⏰"), prefix, strings.Join(result.Cleared, ", "))
+	if len(result.Notified) > 0 {
+		fmt.Printf("  Notified: %v\n", result.Notified)
+	}
+	if len(result.Failed) > 0 {
+		fmt.Printf("  Failed: %v\n", result.Failed)
+	}
+	return nil
+}
+
+// wakeStepWaiters evaluates open gt:waiting steps, sends wake mail to the
+// waiters of those whose conditions are all met, and clears their labels.
+func wakeStepWaiters(b *beads.Beads, townRoot string, dryRun bool) (*MoleculeWakeResult, error) {
+	steps, err := b.List(beads.ListOptions{
+		Status:   "open",
+		Label:    beads.WaitingLabel,
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing waiting steps: %w", err)
+	}
+
+	result := &MoleculeWakeResult{
+		Checked:  len(steps),
+		Cleared:  []string{},
+		Notified: []string{},
+	}
+	router := mail.NewRouter(townRoot)
+
+	for _, step := range steps {
+		unmet, err := unmetStepWaits(b, step)
+		if err != nil {
+			return nil, err
+		}
+		if len(unmet) > 0 {
+			continue
+		}
+		result.Cleared = append(result.Cleared, step.ID)
+
+		waiters := beads.WaitersFromLabels(step.Labels)
+		if dryRun {
+			result.Notified = append(result.Notified, waiters...)
+			continue
+		}
+
+		// Keep the labels of waiters we failed to reach so the next pass
+		// retries them.
+		var remove []string
+		failed := false
+		for _, waiter := range waiters {
+			msg := &mail.Message{
+				From:     "deacon/",
+				To:       waiter,
+				Subject:  fmt.Sprintf("⏰ WAIT CLEARED: %s", step.ID),
+				Body:     fmt.Sprintf("The WaitsFor conditions on step %s (%s) are met.\n\nRun 'gt mol status' and start the step.", step.ID, step.Title),
+				Type:     mail.TypeNotification,
+				Priority: mail.PriorityHigh,
+				Wisp:     true,
+			}
+			if err := router.Send(msg); err != nil {
+				result.Failed = append(result.Failed, waiter)
+				failed = true
+			} else {
+				result.Notified = append(result.Notified, waiter)
+				remove = append(remove, beads.WaiterLabelPrefix+waiter)
+			}
+		}
+		if !failed {
+			remove = append(remove, beads.WaitingLabel)
+		}
+		if len(remove) == 0 {
+			continue
+		}
+		if err := b.Update(step.ID, beads.UpdateOptions{RemoveLabels: remove}); err != nil {
+			return nil, fmt.Errorf("clearing wait labels on %s: %w", step.ID, err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,699 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/swarm"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// MQ changeset command flags
+var (
+	mqChangesetTitle      string
+	mqChangesetTested     string
+	mqChangesetLand       bool
+	mqChangesetFailReason string
+	mqChangesetStatusJSON bool
+)
+
+var mqChangesetCmd = &cobra.Command{
+	Use:   "changeset",
+	Short: "Land merge requests across rigs together",
+	RunE:  requireSubcommand,
+	Long: `Manage change sets: merge requests in different rigs that must land together.
+
+A change that spans rigs (an API change and its client) is submitted as one
+MR per rig. Linking them in a change set makes each rig's refinery hold its
+MR after tests pass instead of merging it. Once every member has passed,
+'gt mq changeset land' merges all of them in one coordinated step:
+
+  1. Merge each member into its target in its rig's landing worktree
+     (<rig>/refinery/changeset, separate from the refinery's checkout)
+  2. If any merge fails, reset every worktree - nothing is pushed
+  3. Push each rig's target
+  4. If a push fails, push a rollback commit in rigs already pushed, reset
+     the rest
+
+Failures are recorded per member; a failed landing can be retried once the
+failing MR passes again. Retrying reverts the earlier rollback commit, so
+members that were pushed and rolled back land in full.
+
+Change set updates and landings take a town-wide lock, so refineries
+passing members at the same time don't lose each other's updates.
+
+Commands:
+  create  Link MRs from different rigs into a change set
+  add     Add MRs to a change set
+  pass    Record that an MR's tests passed (refinery)
+  fail    Record that an MR's tests failed (refinery)
+  land    Merge every member together
+  status  Show each member of a change set`,
+}
+
+var mqChangesetCreateCmd = &cobra.Command{
+	Use:   "create <mr-id> <mr-id>...",
+	Short: "Link MRs from different rigs into a change set",
+	Long: `Create a change set bead linking merge requests across rigs.
+
+Each MR's rig is found from its ID prefix. A change set holds at most one
+MR per rig, and an MR belongs to at most one change set.
+
+Examples:
+  gt mq changeset create gt-mr-abc bd-mr-def
+  gt mq changeset create gt-mr-abc bd-mr-def --title "Auth API v2"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMqChangesetCreate,
+}
+
+var mqChangesetAddCmd = &cobra.Command{
+	Use:   "add <changeset-id> <mr-id>...",
+	Short: "Add MRs to a change set",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runMqChangesetAdd,
+}
+
+var mqChangesetPassCmd = &cobra.Command{
+	Use:   "pass <mr-id>",
+	Short: "Record that an MR's tests passed and hold it",
+	Long: `Record that a change set member passed its tests.
+
+Run by the refinery instead of merging an MR that has a changeset_id.
+The MR is held (labelled changeset:held) until the whole change set lands.
+The tested commit defaults to the branch head in the refinery clone.
+
+With --land, the change set lands right away if this was the last member
+to pass.
+
+Examples:
+  gt mq changeset pass gt-mr-abc
+  gt mq changeset pass gt-mr-abc --land`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMqChangesetPass,
+}
+
+var mqChangesetFailCmd = &cobra.Command{
+	Use:   "fail <mr-id>",
+	Short: "Record that an MR's tests failed",
+	Long: `Record that a change set member failed its tests.
+
+The other members stay held. Once the MR is fixed and passes, the change
+set can land.
+
+Example:
+  gt mq changeset fail gt-mr-abc --reason "TestAuthV2 fails"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMqChangesetFail,
+}
+
+var mqChangesetLandCmd = &cobra.Command{
+	Use:   "land <changeset-id>",
+	Short: "Merge every member of a change set together",
+	Long: `Merge every member of a change set in a coordinated step.
+
+All members must have passed their tests. Rigs must use local merge mode.
+On failure, merges are rolled back in every rig and the failure is recorded
+on the change set; see 'gt mq changeset status'.
+
+Example:
+  gt mq changeset land hq-abc`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMqChangesetLand,
+}
+
+var mqChangesetStatusCmd = &cobra.Command{
+	Use:   "status <changeset-id>",
+	Short: "Show each member of a change set",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMqChangesetStatus,
+}
+
+func init() {
+	mqChangesetCreateCmd.Flags().StringVar(&mqChangesetTitle, "title", "", "Change set title (default: lists the MRs)")
+	mqChangesetPassCmd.Flags().StringVar(&mqChangesetTested, "tested", "", "Commit the tests passed on (default: branch head)")
+	mqChangesetPassCmd.Flags().BoolVar(&mqChangesetLand, "land", false, "Land the change set if every member has now passed")
+	mqChangesetFailCmd.Flags().StringVarP(&mqChangesetFailReason, "reason", "r", "", "Why the tests failed (required)")
+	_ = mqChangesetFailCmd.MarkFlagRequired("reason") // cobra flags: error only at runtime if missing
+	mqChangesetStatusCmd.Flags().BoolVar(&mqChangesetStatusJSON, "json", false, "Output as JSON")
+
+	mqChangesetCmd.AddCommand(mqChangesetCreateCmd)
+	mqChangesetCmd.AddCommand(mqChangesetAddCmd)
+	mqChangesetCmd.AddCommand(mqChangesetPassCmd)
+	mqChangesetCmd.AddCommand(mqChangesetFailCmd)
+	mqChangesetCmd.AddCommand(mqChangesetLandCmd)
+	mqChangesetCmd.AddCommand(mqChangesetStatusCmd)
+	mqCmd.AddCommand(mqChangesetCmd)
+}
+
+// ChangesetStatusOutput is the JSON output structure for change set status.
+type ChangesetStatusOutput struct {
+	ID       string                  `json:"id"`
+	Title    string                  `json:"title"`
+	State    string                  `json:"state"`
+	Reason   string                  `json:"reason,omitempty"`
+	LandedAt string                  `json:"landed_at,omitempty"`
+	Members  []beads.ChangesetMember `json:"members"`
+}
+
+// changesetMR is a merge request bead together with the rig that holds it.
+type changesetMR struct {
+	rig    *rig.Rig
+	bd     *beads.Beads
+	issue  *beads.Issue
+	fields *beads.MRFields
+}
+
+// rigNameForBead returns the rig whose beads hold a bead, found from the
+// bead ID's prefix in the town routes.
+func rigNameForBead(townRoot, beadID string) (string, error) {
+	prefix := beads.ExtractPrefix(beadID)
+	rigPath := beads.GetRigPathForPrefix(townRoot, prefix)
+	if rigPath == "" || rigPath == townRoot {
+		return "", fmt.Errorf("no rig found for %s (prefix %q is not routed to a rig)", beadID, prefix)
+	}
+	rel, err := filepath.Rel(townRoot, rigPath)
+	if err != nil {
+		return "", err
+	}
+	return strings.Split(rel, string(filepath.Separator))[0], nil
+}
+
+// loadChangesetMR fetches a merge request bead from its rig.
+func loadChangesetMR(townRoot, mrID string) (*changesetMR, error) {
+	rigName, err := rigNameForBead(townRoot, mrID)
+	if err != nil {
+		return nil, err
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return nil, err
+	}
+
+	bd := beads.New(r.Path)
+	issue, err := bd.Show(mrID)
+	if err != nil {
+		if errors.Is(err, beads.ErrNotFound) {
+			return nil, fmt.Errorf("merge request '%s' not found in rig '%s'", mrID, rigName)
+		}
+		return nil, fmt.Errorf("fetching merge request: %w", err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.Branch == "" {
+		return nil, fmt.Errorf("'%s' is not a merge request", mrID)
+	}
+	return &changesetMR{rig: r, bd: bd, issue: issue, fields: fields}, nil
+}
+
+// setChangeset records the change set on the MR bead.
+func (mr *changesetMR) setChangeset(id string) error {
+	mr.fields.ChangesetID = id
+	desc := beads.SetMRFields(mr.issue, mr.fields)
+	return mr.bd.Update(mr.issue.ID, beads.UpdateOptions{Description: &desc})
+}
+
+// member returns the change set member for this MR.
+func (mr *changesetMR) member() beads.ChangesetMember {
+	target := mr.fields.Target
+	if target == "" {
+		target = mr.rig.DefaultBranch()
+	}
+	return beads.ChangesetMember{
+		MR:     mr.issue.ID,
+		Rig:    mr.rig.Name,
+		Branch: mr.fields.Branch,
+		Target: target,
+		State:  beads.MemberPending,
+	}
+}
+
+// changesetBeads returns the town beads, where change sets live.
+func changesetBeads() (string, *beads.Beads, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return townRoot, beads.New(beads.ResolveBeadsDir(townRoot)), nil
+}
+
+// lockChangesets serializes change set updates and landings in a town, so
+// concurrent refineries passing members don't overwrite each other's
+// updates and two landings never share a rig's landing worktree.
+func lockChangesets(townRoot string) (*flock.Flock, error) {
+	dir := filepath.Join(townRoot, constants.DirRuntime)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime dir: %w", err)
+	}
+	lock := flock.New(filepath.Join(dir, "changeset.lock"))
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking change sets: %w", err)
+	}
+	return lock, nil
+}
+
+// getChangeset fetches a change set bead, failing if it doesn't exist.
+func getChangeset(bd *beads.Beads, id string) (*beads.Issue, *beads.ChangesetFields, error) {
+	issue, fields, err := bd.GetChangesetBead(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if issue == nil {
+		return nil, nil, fmt.Errorf("change set '%s' not found", id)
+	}
+	return issue, fields, nil
+}
+
+// addChangesetMembers loads MRs and checks they can join a change set that
+// already has the given members.
+func addChangesetMembers(townRoot string, existing []beads.ChangesetMember, mrIDs []string) ([]*changesetMR, []beads.ChangesetMember, error) {
+	rigs := make(map[string]string, len(existing))
+	for _, m := range existing {
+		rigs[m.Rig] = m.MR
+	}
+
+	var mrs []*changesetMR
+	members := existing
+	for _, id := range mrIDs {
+		mr, err := loadChangesetMR(townRoot, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if mr.issue.Status == "closed" {
+			return nil, nil, fmt.Errorf("merge request '%s' is closed", id)
+		}
+		if mr.fields.ChangesetID != "" {
+			return nil, nil, fmt.Errorf("merge request '%s' is already in change set %s", id, mr.fields.ChangesetID)
+		}
+		if other, ok := rigs[mr.rig.Name]; ok {
+			return nil, nil, fmt.Errorf("%s and %s are both in rig '%s'; a change set takes one MR per rig", other, id, mr.rig.Name)
+		}
+		rigs[mr.rig.Name] = id
+		mrs = append(mrs, mr)
+		members = append(members, mr.member())
+	}
+	return mrs, members, nil
+}
+
+func runMqChangesetCreate(cmd *cobra.Command, args []string) error {
+	townRoot, bd, err := changesetBeads()
+	if err != nil {
+		return err
+	}
+	lock, err := lockChangesets(townRoot)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	mrs, members, err := addChangesetMembers(townRoot, nil, args)
+	if err != nil {
+		return err
+	}
+
+	title := mqChangesetTitle
+	if title == "" {
+		title = "Change set: " + strings.Join(args, ", ")
+	}
+	issue, err := bd.CreateChangesetBead(title, &beads.ChangesetFields{
+		State:     beads.ChangesetOpen,
+		CreatedBy: detectSender(),
+		Members:   members,
+	})
+	if err != nil {
+		return fmt.Errorf("creating change set bead: %w", err)
+	}
+
+	for _, mr := range mrs {
+		if err := mr.setChangeset(issue.ID); err != nil {
+			return fmt.Errorf("linking %s to change set: %w", mr.issue.ID, err)
+		}
+	}
+
+	fmt.Printf("%s Created change set %s\n", style.Bold.Render("✓"), issue.ID)
+	for _, m := range members {
+		fmt.Printf("  %-12s  %-12s  %s → %s\n", m.MR, m.Rig, m.Branch, m.Target)
+	}
+	fmt.Printf("\n  %s\n", style.Dim.Render("Refineries hold these MRs until all pass, then: gt mq changeset land "+issue.ID))
+	return nil
+}
+
+func runMqChangesetAdd(cmd *cobra.Command, args []string) error {
+	townRoot, bd, err := changesetBeads()
+	if err != nil {
+		return err
+	}
+	lock, err := lockChangesets(townRoot)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	issue, fields, err := getChangeset(bd, args[0])
+	if err != nil {
+		return err
+	}
+	if fields.State == beads.ChangesetLanded {
+		return fmt.Errorf("change set %s has already landed", issue.ID)
+	}
+
+	mrs, members, err := addChangesetMembers(townRoot, fields.Members, args[1:])
+	if err != nil {
+		return err
+	}
+	fields.Members = members
+	fields.State = beads.ChangesetOpen
+	if err := bd.UpdateChangesetFields(issue, fields); err != nil {
+		return fmt.Errorf("updating change set: %w", err)
+	}
+	for _, mr := range mrs {
+		if err := mr.setChangeset(issue.ID); err != nil {
+			return fmt.Errorf("linking %s to change set: %w", mr.issue.ID, err)
+		}
+		fmt.Printf("%s Added %s (%s) to %s\n", style.Bold.Render("✓"), mr.issue.ID, mr.rig.Name, issue.ID)
+	}
+	return nil
+}
+
+// changesetForMR loads an MR and the change set it belongs to.
+func changesetForMR(townRoot string, bd *beads.Beads, mrID string) (*changesetMR, *beads.Issue, *beads.ChangesetFields, *beads.ChangesetMember, error) {
+	mr, err := loadChangesetMR(townRoot, mrID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if mr.fields.ChangesetID == "" {
+		return nil, nil, nil, nil, fmt.Errorf("merge request '%s' is not part of a change set", mrID)
+	}
+	issue, fields, err := getChangeset(bd, mr.fields.ChangesetID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	member := fields.Member(mrID)
+	if member == nil {
+		return nil, nil, nil, nil, fmt.Errorf("change set %s does not list %s", issue.ID, mrID)
+	}
+	if fields.State == beads.ChangesetLanded {
+		return nil, nil, nil, nil, fmt.Errorf("change set %s has already landed", issue.ID)
+	}
+	return mr, issue, fields, member, nil
+}
+
+func runMqChangesetPass(cmd *cobra.Command, args []string) error {
+	townRoot, bd, err := changesetBeads()
+	if err != nil {
+		return err
+	}
+	lock, err := lockChangesets(townRoot)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+	mr, issue, fields, member, err := changesetForMR(townRoot, bd, args[0])
+	if err != nil {
+		return err
+	}
+
+	tested := mqChangesetTested
+	if tested == "" {
+		g := git.NewGit(refinery.WorkDir(mr.rig))
+		ref := member.Branch
+		if exists, _ := g.BranchExists(ref); !exists {
+			ref = "origin/" + ref
+		}
+		if tested, err = g.Rev(ref); err != nil {
+			return fmt.Errorf("resolving %s in the refinery clone (use --tested): %w", member.Branch, err)
+		}
+	}
+
+	member.State = beads.MemberPassed
+	member.Tested = tested
+	member.Error = ""
+	if fields.AllPassed() {
+		fields.State = beads.ChangesetReady
+	}
+	if err := bd.UpdateChangesetFields(issue, fields); err != nil {
+		return fmt.Errorf("updating change set: %w", err)
+	}
+	if err := mr.bd.Update(mr.issue.ID, beads.UpdateOptions{AddLabels: []string{beads.LabelChangesetHeld}}); err != nil {
+		return fmt.Errorf("holding %s: %w", mr.issue.ID, err)
+	}
+
+	fmt.Printf("%s %s passed at %s; held for change set %s\n", style.Bold.Render("✓"), mr.issue.ID, swarm.ShortSHA(tested), issue.ID)
+	if fields.State != beads.ChangesetReady {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("Waiting on %d of %d members", countWaiting(fields), len(fields.Members))))
+		return nil
+	}
+	if !mqChangesetLand {
+		fmt.Printf("  All %d members passed. Land with: gt mq changeset land %s\n", len(fields.Members), issue.ID)
+		return nil
+	}
+	fmt.Println()
+	return landChangeset(bd, issue, fields)
+}
+
+func countWaiting(fields *beads.ChangesetFields) int {
+	n := 0
+	for _, m := range fields.Members {
+		if m.State != beads.MemberPassed && m.State != beads.MemberRolledBack {
+			n++
+		}
+	}
+	return n
+}
+
+func runMqChangesetFail(cmd *cobra.Command, args []string) error {
+	townRoot, bd, err := changesetBeads()
+	if err != nil {
+		return err
+	}
+	lock, err := lockChangesets(townRoot)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+	mr, issue, fields, member, err := changesetForMR(townRoot, bd, args[0])
+	if err != nil {
+		return err
+	}
+
+	member.State = beads.MemberFailed
+	member.Error = mqChangesetFailReason
+	if fields.State == beads.ChangesetReady {
+		fields.State = beads.ChangesetOpen
+	}
+	if err := bd.UpdateChangesetFields(issue, fields); err != nil {
+		return fmt.Errorf("updating change set: %w", err)
+	}
+	if beads.HasLabel(mr.issue, beads.LabelChangesetHeld) {
+		if err := mr.bd.Update(mr.issue.ID, beads.UpdateOptions{RemoveLabels: []string{beads.LabelChangesetHeld}}); err != nil {
+			return fmt.Errorf("releasing %s: %w", mr.issue.ID, err)
+		}
+	}
+
+	fmt.Printf("%s %s failed; change set %s waits for a fix\n", style.Bold.Render("✗"), mr.issue.ID, issue.ID)
+	fmt.Printf("  Reason: %s\n", mqChangesetFailReason)
+	return nil
+}
+
+func runMqChangesetLand(cmd *cobra.Command, args []string) error {
+	townRoot, bd, err := changesetBeads()
+	if err != nil {
+		return err
+	}
+	lock, err := lockChangesets(townRoot)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+	issue, fields, err := getChangeset(bd, args[0])
+	if err != nil {
+		return err
+	}
+	if fields.State == beads.ChangesetLanded {
+		return fmt.Errorf("change set %s has already landed", issue.ID)
+	}
+	if !fields.AllPassed() {
+		return fmt.Errorf("change set %s is not ready: %d of %d members have not passed (see gt mq changeset status %s)",
+			issue.ID, countWaiting(fields), len(fields.Members), issue.ID)
+	}
+	return landChangeset(bd, issue, fields)
+}
+
+// landChangeset merges every member, then closes the merged MRs or records
+// the failure on the change set. Callers hold the change set lock.
+func landChangeset(bd *beads.Beads, issue *beads.Issue, fields *beads.ChangesetFields) error {
+	engineers := make(map[string]*refinery.Engineer, len(fields.Members))
+	rigPaths := make(map[string]string, len(fields.Members))
+	landings := make([]*refinery.ChangesetLanding, 0, len(fields.Members))
+	for i := range fields.Members {
+		m := &fields.Members[i]
+		_, r, err := getRig(m.Rig)
+		if err != nil {
+			return err
+		}
+		eng := refinery.NewEngineer(r)
+		if err := eng.LoadConfig(); err != nil {
+			return fmt.Errorf("loading %s merge queue config: %w", m.Rig, err)
+		}
+		if eng.Config().Mode == config.MergeModePullRequest {
+			return fmt.Errorf("rig '%s' merges through pull requests; change sets need local merge mode", m.Rig)
+		}
+		workDir, err := refinery.ChangesetWorkDir(r)
+		if err != nil {
+			return err
+		}
+		engineers[m.MR] = eng
+		rigPaths[m.MR] = r.Path
+		landings = append(landings, &refinery.ChangesetLanding{Member: m, WorkDir: workDir})
+	}
+
+	fmt.Printf("Landing change set %s (%d rigs)\n", issue.ID, len(landings))
+	landErr := refinery.LandChangeset(issue.ID, landings, os.Stdout)
+
+	if landErr == nil {
+		fields.State = beads.ChangesetLanded
+		fields.LandedAt = time.Now().UTC().Format(time.RFC3339)
+		fields.Reason = ""
+	} else {
+		fields.State = beads.ChangesetFailed
+		fields.Reason = landErr.Error()
+	}
+
+	// Close merged MRs; release failed ones so their refinery picks them up
+	// again once fixed. Rolled-back MRs stay held.
+	for _, m := range fields.Members {
+		mrBeads := beads.New(rigPaths[m.MR])
+		mrIssue, err := mrBeads.Show(m.MR)
+		if err != nil {
+			style.PrintWarning("could not fetch %s: %v", m.MR, err)
+			continue
+		}
+		info := mrInfoFromBead(mrIssue)
+		switch m.State {
+		case beads.MemberMerged:
+			engineers[m.MR].HandleMRInfoSuccess(info, refinery.ProcessResult{Success: true, MergeCommit: m.MergeCommit})
+		case beads.MemberFailed:
+			if err := mrBeads.Update(m.MR, beads.UpdateOptions{RemoveLabels: []string{beads.LabelChangesetHeld}}); err != nil {
+				style.PrintWarning("could not release %s: %v", m.MR, err)
+			}
+			engineers[m.MR].HandleMRInfoFailure(info, refinery.ProcessResult{Error: m.Error})
+		}
+	}
+
+	if err := bd.UpdateChangesetFields(issue, fields); err != nil {
+		style.PrintWarning("could not update change set %s: %v", issue.ID, err)
+	}
+
+	fmt.Println()
+	if landErr != nil {
+		printChangesetMembers(fields.Members)
+		return fmt.Errorf("change set %s did not land: %w", issue.ID, landErr)
+	}
+	fmt.Printf("%s Landed change set %s\n", style.Bold.Render("✓"), issue.ID)
+	printChangesetMembers(fields.Members)
+	return nil
+}
+
+func runMqChangesetStatus(cmd *cobra.Command, args []string) error {
+	_, bd, err := changesetBeads()
+	if err != nil {
+		return err
+	}
+	issue, fields, err := getChangeset(bd, args[0])
+	if err != nil {
+		return err
+	}
+
+	output := ChangesetStatusOutput{
+		ID:       issue.ID,
+		Title:    issue.Title,
+		State:    fields.State,
+		Reason:   fields.Reason,
+		LandedAt: fields.LandedAt,
+		Members:  fields.Members,
+	}
+	if output.Members == nil {
+		output.Members = []beads.ChangesetMember{}
+	}
+
+	if mqChangesetStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(output)
+	}
+
+	fmt.Printf("Change set: %s\n", style.Bold.Render(issue.ID))
+	fmt.Printf("Title: %s\n", issue.Title)
+	fmt.Printf("State: %s\n", formatChangesetState(fields.State))
+	if fields.LandedAt != "" {
+		fmt.Printf("Landed: %s\n", fields.LandedAt)
+	}
+	if fields.Reason != "" {
+		fmt.Printf("Reason: %s\n", fields.Reason)
+	}
+	fmt.Printf("\nMembers (%d):\n", len(fields.Members))
+	printChangesetMembers(fields.Members)
+	return nil
+}
+
+// printChangesetMembers prints one line per member, with any error below it.
+func printChangesetMembers(members []beads.ChangesetMember) {
+	if len(members) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+		return
+	}
+	for _, m := range members {
+		detail := ""
+		switch {
+		case m.MergeCommit != "":
+			detail = "merge " + swarm.ShortSHA(m.MergeCommit)
+		case m.Tested != "":
+			detail = "tested " + swarm.ShortSHA(m.Tested)
+		}
+		fmt.Printf("  %s %-12s  %-12s  %-11s  %s → %s  %s\n",
+			changesetMemberIcon(m.State), m.MR, m.Rig, m.State, m.Branch, m.Target, style.Dim.Render(detail))
+		if m.Error != "" {
+			fmt.Printf("      %s\n", style.Dim.Render(m.Error))
+		}
+	}
+}
+
+func formatChangesetState(state string) string {
+	switch state {
+	case beads.ChangesetReady:
+		return style.Success.Render(state)
+	case beads.ChangesetLanded:
+		return style.Success.Render(state) + " ✓"
+	case beads.ChangesetFailed:
+		return style.Error.Render(state)
+	default:
+		return state
+	}
+}
+
+func changesetMemberIcon(state string) string {
+	switch state {
+	case beads.MemberPassed:
+		return "●"
+	case beads.MemberMerged:
+		return "✓"
+	case beads.MemberFailed:
+		return "✗"
+	case beads.MemberRolledBack:
+		return "↩"
+	default:
+		return "○"
+	}
+}
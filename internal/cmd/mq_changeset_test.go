@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestRigNameForBead(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	routes := `{"prefix":"gt-","path":"gastown/mayor/rig"}
+{"prefix":"bd-","path":"beads"}
+{"prefix":"hq-","path":"."}
+`
+	if err := os.WriteFile(filepath.Join(town, ".beads", beads.RoutesFileName), []byte(routes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]string{"gt-mr-abc": "gastown", "bd-xyz": "beads"} {
+		if got, err := rigNameForBead(town, id); err != nil || got != want {
+			t.Errorf("rigNameForBead(%s) = %q, %v; want %q", id, got, err, want)
+		}
+	}
+	for _, id := range []string{"hq-cs1", "zz-unknown", "noprefix"} {
+		if _, err := rigNameForBead(town, id); err == nil {
+			t.Errorf("rigNameForBead(%s) should fail", id)
+		}
+	}
+}
+
+func TestPrintChangesetMembers(t *testing.T) {
+	out := captureStdout(t, func() {
+		printChangesetMembers([]beads.ChangesetMember{
+			{MR: "gt-mr1", Rig: "gastown", Branch: "polecat/nux", Target: "main", State: beads.MemberRolledBack, Tested: "abc123def456", MergeCommit: "0123456789ab"},
+			{MR: "bd-mr2", Rig: "beads", Branch: "polecat/max", Target: "main", State: beads.MemberFailed, Error: "push failed: protected"},
+			{MR: "wy-mr3", Rig: "wyvern", Branch: "polecat/ace", Target: "main", State: beads.MemberPassed, Tested: "fedcba987654"},
+		})
+	})
+
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), out)
+	}
+	for i, want := range [][]string{
+		{"↩", "gt-mr1", "gastown", "rolled_back", "polecat/nux → main", "merge 01234567"},
+		{"✗", "bd-mr2", "beads", "failed"},
+		{"push failed: protected"},
+		{"●", "wy-mr3", "passed", "tested fedcba98"},
+	} {
+		for _, w := range want {
+			if !strings.Contains(lines[i], w) {
+				t.Errorf("line %d = %q, missing %q", i, lines[i], w)
+			}
+		}
+	}
+}
+
+func TestLockChangesets(t *testing.T) {
+	town := t.TempDir()
+	lock, err := lockChangesets(town)
+	if err != nil {
+		t.Fatalf("lockChangesets: %v", err)
+	}
+
+	// A second holder (another refinery's gt process) has to wait
+	other := flock.New(filepath.Join(town, ".runtime", "changeset.lock"))
+	if ok, err := other.TryLock(); err != nil || ok {
+		t.Fatalf("TryLock while held = %v, %v; want false", ok, err)
+	}
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := other.TryLock(); err != nil || !ok {
+		t.Fatalf("TryLock after unlock = %v, %v; want true", ok, err)
+	}
+	_ = other.Unlock()
+}
@@ -163,6 +163,8 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		if issue.Status == "open" {
 			if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
 				displayStatus = "blocked"
+			} else if beads.HasLabel(issue, beads.LabelChangesetHeld) {
+				displayStatus = "held"
 			} else {
 				displayStatus = "ready"
 			}
@@ -177,6 +179,8 @@ func runMQList(cmd *cobra.Command, args []string) error {
 			styledStatus = style.Warning.Render("active")
 		case "blocked":
 			styledStatus = style.Dim.Render("blocked")
+		case "held":
+			styledStatus = style.Dim.Render("held")
 		case "closed":
 			styledStatus = style.Dim.Render("closed")
 		}
@@ -239,6 +243,14 @@ func runMQList(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %s %s\n", style.Dim.Render(displayID+":"),
 				style.Dim.Render(fmt.Sprintf("waiting on %s", issue.BlockedBy[0])))
 		}
+		if displayStatus == "open" && beads.HasLabel(issue, beads.LabelChangesetHeld) && item.fields != nil {
+			displayID := issue.ID
+			if len(displayID) > 12 {
+				displayID = displayID[:12]
+			}
+			fmt.Printf("  %s %s\n", style.Dim.Render(displayID+":"),
+				style.Dim.Render(fmt.Sprintf("held for change set %s", item.fields.ChangesetID)))
+		}
 	}
 
 	return nil
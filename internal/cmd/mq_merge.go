@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var mqMergeCmd = &cobra.Command{
+	Use:   "merge <mr-id>",
+	Short: "Merge an MR through its pull request (pull_request mode)",
+	Long: `Merge a merge request through the forge, for rigs whose merge_queue
+mode is "pull_request".
+
+Run by the refinery patrol in place of a local merge and push. Pushes the
+MR's branch, opens (or reuses) a pull request and checks the forge's
+required checks once:
+
+  passed   Merge the pull request, close the MR and its source issue
+  pending  Park the MR and exit; the next patrol runs this again
+  none     Park like pending until CI registers checks or check_grace passes
+  failed   Notify the witness and leave the MR for rework (exit 1)
+
+A parked MR records its pull request and when it was parked (pull_request
+and checks_since). If the checks are still pending after the forge's
+check_timeout, the MR fails.
+
+Example:
+  gt mq merge gt-mr-abc`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMqMerge,
+}
+
+func init() {
+	mqCmd.AddCommand(mqMergeCmd)
+}
+
+func runMqMerge(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	mr, err := loadChangesetMR(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if mr.issue.Status == "closed" {
+		return fmt.Errorf("merge request '%s' is closed", mr.issue.ID)
+	}
+	if mr.fields.ChangesetID != "" {
+		return fmt.Errorf("merge request '%s' is in change set %s; change sets need local merge mode", mr.issue.ID, mr.fields.ChangesetID)
+	}
+
+	eng := refinery.NewEngineer(mr.rig)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if eng.Config().Mode != config.MergeModePullRequest {
+		return fmt.Errorf("rig '%s' merges locally; gt mq merge is for merge_queue mode %q", mr.rig.Name, config.MergeModePullRequest)
+	}
+
+	info := mrInfoFromBead(mr.issue)
+	if info.Target == "" {
+		info.Target = mr.rig.DefaultBranch()
+	}
+	result := eng.ProcessMRInfo(context.Background(), info)
+	switch {
+	case result.Success:
+		eng.HandleMRInfoSuccess(info, result)
+		fmt.Printf("%s Merged %s\n", style.Bold.Render("✓"), info.ID)
+		return nil
+	case result.Parked:
+		if err := eng.ParkMR(info, result); err != nil {
+			return fmt.Errorf("parking %s: %w", info.ID, err)
+		}
+		fmt.Printf("%s Parked %s: %s\n", style.Bold.Render("⏸"), info.ID, result.Error)
+		fmt.Printf("  %s\n", style.Dim.Render("Checks are still running; the next patrol re-polls with: gt mq merge "+info.ID))
+		return nil
+	default:
+		eng.HandleMRInfoFailure(info, result)
+		return fmt.Errorf("merging %s: %s", info.ID, result.Error)
+	}
+}
+
+// mrInfoFromBead converts an MR bead for the refinery's merge and
+// completion handlers.
+func mrInfoFromBead(issue *beads.Issue) *refinery.MRInfo {
+	info := &refinery.MRInfo{ID: issue.ID, Title: issue.Title, Priority: issue.Priority}
+	if f := beads.ParseMRFields(issue); f != nil {
+		info.Branch = f.Branch
+		info.Target = f.Target
+		info.SourceIssue = f.SourceIssue
+		info.Worker = f.Worker
+		info.Rig = f.Rig
+		info.AgentBead = f.AgentBead
+		info.RetryCount = f.RetryCount
+		info.ChangesetID = f.ChangesetID
+		info.PullRequest = f.PullRequest
+		info.ChecksSince = refinery.ParseChecksSince(f.ChecksSince)
+	}
+	return info
+}
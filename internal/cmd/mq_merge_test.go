@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestMRInfoFromBead(t *testing.T) {
+	issue := &beads.Issue{
+		ID:          "gt-mr1",
+		Title:       "Merge: gt-1",
+		Priority:    1,
+		Description: "branch: polecat/nux/gt-1\ntarget: main\nsource_issue: gt-1\nworker: nux\nchangeset_id: hq-cs1\npull_request: https://github.com/acme/widgets/pull/7\nchecks_since: 2026-01-02T03:04:05Z",
+	}
+	info := mrInfoFromBead(issue)
+	if info.Branch != "polecat/nux/gt-1" || info.SourceIssue != "gt-1" || info.Worker != "nux" || info.ChangesetID != "hq-cs1" || info.Priority != 1 {
+		t.Errorf("mrInfoFromBead = %+v", info)
+	}
+	if info.PullRequest != "https://github.com/acme/widgets/pull/7" || info.ChecksSince == nil || info.ChecksSince.Year() != 2026 {
+		t.Errorf("mrInfoFromBead parked fields = %q, %v", info.PullRequest, info.ChecksSince)
+	}
+}
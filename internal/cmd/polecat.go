@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/limits"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
@@ -606,18 +607,26 @@ func runPolecatSync(cmd *cobra.Command, args []string) error {
 
 // PolecatStatus represents detailed polecat status for JSON output.
 type PolecatStatus struct {
-	Rig            string        `json:"rig"`
-	Name           string        `json:"name"`
-	State          polecat.State `json:"state"`
-	Issue          string        `json:"issue,omitempty"`
-	ClonePath      string        `json:"clone_path"`
-	Branch         string        `json:"branch"`
-	SessionRunning bool          `json:"session_running"`
-	SessionID      string        `json:"session_id,omitempty"`
-	Attached       bool          `json:"attached,omitempty"`
-	Windows        int           `json:"windows,omitempty"`
-	CreatedAt      string        `json:"created_at,omitempty"`
-	LastActivity   string        `json:"last_activity,omitempty"`
+	Rig            string            `json:"rig"`
+	Name           string            `json:"name"`
+	State          polecat.State     `json:"state"`
+	Issue          string            `json:"issue,omitempty"`
+	ClonePath      string            `json:"clone_path"`
+	Branch         string            `json:"branch"`
+	SessionRunning bool              `json:"session_running"`
+	SessionID      string            `json:"session_id,omitempty"`
+	Attached       bool              `json:"attached,omitempty"`
+	Windows        int               `json:"windows,omitempty"`
+	CreatedAt      string            `json:"created_at,omitempty"`
+	LastActivity   string            `json:"last_activity,omitempty"`
+	Resources      *PolecatResources `json:"resources,omitempty"`
+}
+
+// PolecatResources is a polecat session's resource limits and usage.
+type PolecatResources struct {
+	Mechanism limits.Mechanism `json:"mechanism"`
+	Limits    limits.Limits    `json:"limits"`
+	Usage     limits.Usage     `json:"usage"`
 }
 
 func runPolecatStatus(cmd *cobra.Command, args []string) error {
@@ -649,6 +658,16 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Resource usage, for sessions started under limits
+	var resources *PolecatResources
+	if sessInfo.Running {
+		if ls := limits.Load(r.Path, "polecats/"+polecatName); ls != nil {
+			if usage, err := ls.Usage(); err == nil {
+				resources = &PolecatResources{Mechanism: ls.Mechanism, Limits: ls.Limits, Usage: usage}
+			}
+		}
+	}
+
 	// JSON output
 	if polecatStatusJSON {
 		status := PolecatStatus{
@@ -662,6 +681,7 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 			SessionID:      sessInfo.SessionID,
 			Attached:       sessInfo.Attached,
 			Windows:        sessInfo.Windows,
+			Resources:      resources,
 		}
 		if !sessInfo.Created.IsZero() {
 			status.CreatedAt = sessInfo.Created.Format("2006-01-02 15:04:05")
@@ -735,9 +755,41 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Status:        %s\n", style.Dim.Render("not running"))
 	}
 
+	if resources != nil {
+		printPolecatResources(resources)
+	}
+
 	return nil
 }
 
+// printPolecatResources prints a session's resource usage against its limits.
+func printPolecatResources(res *PolecatResources) {
+	l, u := res.Limits, res.Usage
+	withMax := func(usage, max string, set bool) string {
+		if !set {
+			return usage
+		}
+		return usage + style.Dim.Render(" / "+max)
+	}
+
+	fmt.Println()
+	fmt.Printf("%s %s\n", style.Bold.Render("Resources"), style.Dim.Render("("+string(res.Mechanism)+")"))
+	fmt.Printf("  Memory:        %s\n", withMax(limits.FormatBytes(u.MemoryBytes), limits.FormatBytes(l.MemoryMax), l.MemoryMax > 0))
+	fmt.Printf("  Processes:     %s\n", withMax(fmt.Sprintf("%d", u.Pids), fmt.Sprintf("%d", l.PidsMax), l.PidsMax > 0))
+	cpu := formatDuration(u.CPUTime)
+	if l.CPUWeight > 0 {
+		cpu += style.Dim.Render(fmt.Sprintf(" (weight %d)", l.CPUWeight))
+	}
+	fmt.Printf("  CPU time:      %s\n", cpu)
+	fmt.Printf("  Wall clock:    %s\n", withMax(formatDuration(u.Elapsed), formatDuration(l.WallClock), l.WallClock > 0))
+	if u.OOMKills > 0 {
+		fmt.Printf("  %s %d process(es) killed at the memory limit\n", style.WarningPrefix, u.OOMKills)
+	}
+	if u.PidsMaxHits > 0 {
+		fmt.Printf("  %s %d fork(s) refused at the process limit\n", style.WarningPrefix, u.PidsMaxHits)
+	}
+}
+
 // formatActivityTime returns a human-readable relative time string.
 func formatActivityTime(t time.Time) string {
 	d := time.Since(t)
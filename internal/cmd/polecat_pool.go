@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var polecatPoolJSON bool
+
+var polecatPoolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Manage the warm pool of pre-provisioned polecat worktrees",
+	RunE:  requireSubcommand,
+	Long: `Manage a rig's warm pool of idle, pre-provisioned polecat worktrees.
+
+With a warm pool, gt sling claims a worktree whose setup hooks and overlay
+have already run instead of creating one, so heavy setup (dependency
+installs) happens ahead of time. The daemon refills the pool and resets
+idle worktrees to the latest default branch on every heartbeat; gt sling
+also starts a refill in the background after claiming.
+
+Enable it in <rig>/settings/config.json:
+
+  "warm_pool": {"size": 2}`,
+}
+
+var polecatPoolStatusCmd = &cobra.Command{
+	Use:   "status <rig>",
+	Short: "Show the warm pool",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPolecatPoolStatus,
+}
+
+var polecatPoolRefillCmd = &cobra.Command{
+	Use:   "refill <rig>",
+	Short: "Refresh warm worktrees and provision up to the pool size",
+	Long: `Reset idle warm worktrees to the latest default branch and rerun their
+setup hooks, then provision new ones until the pool reaches its configured
+size. Ready worktrees
+beyond the size are removed.
+
+Only one refill runs per rig at a time; a second one exits immediately.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPoolRefill,
+}
+
+var polecatPoolDrainCmd = &cobra.Command{
+	Use:   "drain <rig>",
+	Short: "Remove all warm worktrees",
+	Long: `Remove every warm worktree in the rig. The daemon refills the pool on
+its next heartbeat unless warm_pool.size is set to 0.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPoolDrain,
+}
+
+func init() {
+	polecatPoolStatusCmd.Flags().BoolVar(&polecatPoolJSON, "json", false, "Output as JSON")
+
+	polecatPoolCmd.AddCommand(polecatPoolStatusCmd)
+	polecatPoolCmd.AddCommand(polecatPoolRefillCmd)
+	polecatPoolCmd.AddCommand(polecatPoolDrainCmd)
+	polecatCmd.AddCommand(polecatPoolCmd)
+}
+
+func runPolecatPoolStatus(cmd *cobra.Command, args []string) error {
+	mgr, r, err := getPolecatManager(args[0])
+	if err != nil {
+		return err
+	}
+	slots, err := mgr.ListWarm()
+	if err != nil {
+		return err
+	}
+	size := mgr.WarmPoolSize()
+
+	if polecatPoolJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Rig   string             `json:"rig"`
+			Size  int                `json:"size"`
+			Slots []polecat.WarmSlot `json:"slots"`
+		}{r.Name, size, slots})
+	}
+
+	ready := 0
+	for _, s := range slots {
+		if s.Ready {
+			ready++
+		}
+	}
+	fmt.Printf("%s Warm pool for %s: %d/%d ready\n", style.Bold.Render("🔥"), r.Name, ready, size)
+	if size == 0 && len(slots) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Disabled. Set warm_pool.size in settings/config.json to enable."))
+		return nil
+	}
+	for _, s := range slots {
+		state := "ready"
+		if !s.Ready {
+			state = "provisioning"
+		}
+		commit := s.Commit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		fmt.Printf("  %-22s %-12s %s %s\n", s.Name, state, commit,
+			style.Dim.Render(formatDuration(time.Since(s.CreatedAt))+" old"))
+	}
+	return nil
+}
+
+func runPolecatPoolRefill(cmd *cobra.Command, args []string) error {
+	mgr, r, err := getPolecatManager(args[0])
+	if err != nil {
+		return err
+	}
+
+	if refreshed, err := mgr.RefreshWarmPool(); err != nil {
+		fmt.Printf("%s Could not refresh warm worktrees: %v\n", style.WarningPrefix, err)
+	} else if refreshed > 0 {
+		fmt.Printf("%s Refreshed %d warm worktree(s) to the latest default branch\n", style.SuccessPrefix, refreshed)
+	}
+
+	added, err := mgr.RefillWarmPool()
+	if added > 0 {
+		fmt.Printf("%s Provisioned %d warm worktree(s) for %s\n", style.SuccessPrefix, added, r.Name)
+	}
+	return err
+}
+
+func runPolecatPoolDrain(cmd *cobra.Command, args []string) error {
+	mgr, r, err := getPolecatManager(args[0])
+	if err != nil {
+		return err
+	}
+	removed, err := mgr.DrainWarmPool()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed %d warm worktree(s) from %s\n", style.SuccessPrefix, removed, r.Name)
+	return nil
+}
+
+// refillWarmPoolInBackground starts gt polecat pool refill for the rig as a
+// detached process, so a sling that just claimed a warm worktree doesn't
+// wait for its replacement to be provisioned.
+func refillWarmPoolInBackground(rigName string) {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return
+	}
+	cmd := exec.Command(gtPath, "polecat", "pool", "refill", rigName)
+	// Detach from parent I/O; the refill runs after sling exits
+	cmd.Stdin = nil
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	_ = cmd.Process.Release()
+}
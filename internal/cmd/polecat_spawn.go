@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -11,9 +12,9 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/ptyd"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -66,7 +67,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 
 	// Get polecat manager (with tmux for session-aware allocation)
 	polecatGit := git.NewGit(r.Path)
-	t := tmux.NewTmux()
+	t := ptyd.NewBackend()
 	polecatMgr := polecat.NewManager(r, polecatGit, t)
 
 	// Allocate a new polecat name
@@ -96,14 +97,28 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 			}
 		}
 		fmt.Printf("Repairing stale polecat %s with fresh worktree...\n", polecatName)
+		// The old work belongs to a different issue: it is kept as a snapshot
+		// (gt checkpoint restore) but not carried into this sling's worktree.
 		if _, err = polecatMgr.RepairWorktreeWithOptions(polecatName, opts.Force, addOpts); err != nil {
 			return nil, fmt.Errorf("repairing stale polecat: %w", err)
 		}
 	} else if err == polecat.ErrPolecatNotFound {
-		// Create new polecat
-		fmt.Printf("Creating polecat %s...\n", polecatName)
-		if _, err = polecatMgr.AddWithOptions(polecatName, addOpts); err != nil {
-			return nil, fmt.Errorf("creating polecat: %w", err)
+		// Prefer a pre-provisioned worktree from the rig's warm pool
+		_, err = polecatMgr.ClaimWarm(polecatName, addOpts)
+		if err == nil {
+			fmt.Printf("Claimed warm worktree for polecat %s\n", polecatName)
+		} else {
+			if !errors.Is(err, polecat.ErrNoWarmPolecat) {
+				fmt.Printf("Warning: could not claim warm worktree: %v\n", err)
+			}
+			// Create new polecat
+			fmt.Printf("Creating polecat %s...\n", polecatName)
+			if _, err = polecatMgr.AddWithOptions(polecatName, addOpts); err != nil {
+				return nil, fmt.Errorf("creating polecat: %w", err)
+			}
+		}
+		if polecatMgr.WarmPoolSize() > 0 {
+			refillWarmPoolInBackground(rigName)
 		}
 	} else {
 		return nil, fmt.Errorf("getting polecat: %w", err)
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/handoff"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -226,7 +228,8 @@ func outputUnknownContext(ctx RoleContext) {
 	fmt.Printf("Town root: %s\n", style.Dim.Render(ctx.TownRoot))
 }
 
-// outputHandoffContent reads and displays the pinned handoff bead for the role.
+// outputHandoffContent reads and displays the pinned handoff bead for the role,
+// followed by what changed since the previous structured handoff.
 func outputHandoffContent(ctx RoleContext) {
 	if ctx.Role == RoleUnknown {
 		return
@@ -235,13 +238,15 @@ func outputHandoffContent(ctx RoleContext) {
 	// Get role key for handoff bead lookup
 	roleKey := string(ctx.Role)
 
+	// Silently skip if beads lookup fails (might not be a beads repo)
+	description := ""
 	bd := beads.New(ctx.TownRoot)
-	issue, err := bd.FindHandoffBead(roleKey)
-	if err != nil {
-		// Silently skip if beads lookup fails (might not be a beads repo)
-		return
+	if issue, err := bd.FindHandoffBead(roleKey); err == nil && issue != nil {
+		description = issue.Description
 	}
-	if issue == nil || issue.Description == "" {
+
+	rec, prev := recentHandoffRecords(ctx)
+	if description == "" && rec == nil {
 		// No handoff content
 		return
 	}
@@ -249,9 +254,55 @@ func outputHandoffContent(ctx RoleContext) {
 	// Display handoff content
 	fmt.Println()
 	fmt.Printf("%s\n\n", style.Bold.Render("## 🤝 Handoff from Previous Session"))
-	fmt.Println(issue.Description)
+	if description != "" {
+		fmt.Println(description)
+		fmt.Println()
+	}
+	if rec != nil {
+		outputHandoffDelta(handoff.Diff(prev, rec))
+	}
+	if description != "" {
+		fmt.Println(style.Dim.Render("(Clear with: gt rig reset --handoff)"))
+	}
+}
+
+// recentHandoffRecords returns the agent's latest handoff record, if written
+// within the last day, and the record before it.
+func recentHandoffRecords(ctx RoleContext) (rec, prev *handoff.Record) {
+	agent := getAgentIdentity(ctx)
+	if agent == "" {
+		return nil, nil
+	}
+	rec, err := handoff.Latest(ctx.TownRoot, agent)
+	if err != nil || rec == nil || rec.IsStale(24*time.Hour) {
+		return nil, nil
+	}
+	if rec.Previous != "" {
+		prev, _ = handoff.Load(ctx.TownRoot, agent, rec.Previous)
+	}
+	return rec, prev
+}
+
+// outputHandoffDelta shows the progress the previous session recorded.
+func outputHandoffDelta(d handoff.Delta) {
+	fmt.Printf("**Since last session:** %s\n", d.Summary())
+	if len(d.StepsDone) > 0 {
+		fmt.Printf("  Steps done: %s\n", strings.Join(d.StepsDone, ", "))
+	}
+	if len(d.FilesChanged) > 0 {
+		files := d.FilesChanged
+		more := ""
+		if len(files) > 10 {
+			more = fmt.Sprintf(" (+%d more)", len(files)-10)
+			files = files[:10]
+		}
+		fmt.Printf("  Files changed: %s%s\n", strings.Join(files, ", "), more)
+	}
+	if len(d.ResolvedQuestions) > 0 {
+		fmt.Printf("  Resolved: %s\n", strings.Join(d.ResolvedQuestions, "; "))
+	}
+	fmt.Println(style.Dim.Render("(History: gt handoff history)"))
 	fmt.Println()
-	fmt.Println(style.Dim.Render("(Clear with: gt rig reset --handoff)"))
 }
 
 // outputStartupDirective outputs role-specific instructions for the agent.
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/ptyd"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var ptydCmd = &cobra.Command{
+	Use:     "ptyd",
+	GroupID: GroupServices,
+	Short:   "Manage the headless PTY session supervisor",
+	RunE:    requireSubcommand,
+	Long: `Manage the headless PTY session supervisor.
+
+ptyd is a pure-Go replacement for tmux. It runs agent sessions in
+pseudo-terminals, keeps their scrollback in ring buffers, and serves
+create/kill/send/capture/attach over a Unix socket.
+
+Use it in CI and containers where tmux isn't installed:
+
+  gt ptyd start
+  export GT_SESSION_BACKEND=pty
+  gt sling <bead> <rig>
+
+The socket path defaults to a per-user file in the temp directory and
+can be overridden with GT_PTYD_SOCKET.`,
+}
+
+var ptydStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the supervisor in the background",
+	RunE:  runPtydStart,
+}
+
+var ptydStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the supervisor and kill all its sessions",
+	RunE:  runPtydStop,
+}
+
+var ptydStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show supervisor status and sessions",
+	RunE:  runPtydStatus,
+}
+
+var ptydAttachCmd = &cobra.Command{
+	Use:   "attach <session>",
+	Short: "Attach to a supervised session (Ctrl-] to detach)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPtydAttach,
+}
+
+var ptydRunCmd = &cobra.Command{
+	Use:    "run",
+	Short:  "Run supervisor in foreground (internal)",
+	Hidden: true,
+	RunE:   runPtydRun,
+}
+
+var ptydScrollback int
+
+func init() {
+	ptydCmd.AddCommand(ptydStartCmd)
+	ptydCmd.AddCommand(ptydStopCmd)
+	ptydCmd.AddCommand(ptydStatusCmd)
+	ptydCmd.AddCommand(ptydAttachCmd)
+	ptydCmd.AddCommand(ptydRunCmd)
+
+	ptydRunCmd.Flags().IntVar(&ptydScrollback, "scrollback", ptyd.DefaultScrollback, "Scrollback bytes kept per session")
+
+	rootCmd.AddCommand(ptydCmd)
+}
+
+func runPtydStart(cmd *cobra.Command, args []string) error {
+	socket := ptyd.DefaultSocketPath()
+	client := ptyd.NewClient(socket)
+	if client.Ping() == nil {
+		return fmt.Errorf("ptyd already running on %s", socket)
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+
+	runCmd := exec.Command(gtPath, "ptyd", "run")
+	runCmd.Stdin = nil
+	runCmd.Stdout = nil
+	runCmd.Stderr = nil
+	if err := runCmd.Start(); err != nil {
+		return fmt.Errorf("starting ptyd: %w", err)
+	}
+
+	// Wait for the socket to come up
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if client.Ping() == nil {
+			fmt.Printf("%s ptyd started (PID %d)\n", style.Bold.Render("✓"), runCmd.Process.Pid)
+			fmt.Printf("  Socket: %s\n", socket)
+			fmt.Printf("  Use with: %s\n", style.Dim.Render("export "+ptyd.BackendEnv+"="+ptyd.BackendPTY))
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("ptyd failed to start on %s", socket)
+}
+
+func runPtydStop(cmd *cobra.Command, args []string) error {
+	client := ptyd.NewClient(ptyd.DefaultSocketPath())
+	if err := client.Shutdown(); err != nil {
+		return err
+	}
+	fmt.Printf("%s ptyd stopped\n", style.Bold.Render("✓"))
+	return nil
+}
+
+func runPtydStatus(cmd *cobra.Command, args []string) error {
+	socket := ptyd.DefaultSocketPath()
+	client := ptyd.NewClient(socket)
+	if client.Ping() != nil {
+		fmt.Printf("%s ptyd is %s\n", style.Dim.Render("○"), "not running")
+		fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt ptyd start"))
+		return nil
+	}
+
+	sessions, err := client.ListSessions()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s ptyd is %s\n", style.Bold.Render("●"), style.Bold.Render("running"))
+	fmt.Printf("  Socket: %s\n", socket)
+	fmt.Printf("  Sessions: %d\n", len(sessions))
+	for _, name := range sessions {
+		pid, _ := client.GetPanePID(name)
+		fmt.Printf("    %s %s\n", name, style.Dim.Render("(pid "+pid+")"))
+	}
+	return nil
+}
+
+func runPtydAttach(cmd *cobra.Command, args []string) error {
+	return ptyd.NewClient(ptyd.DefaultSocketPath()).AttachSession(args[0])
+}
+
+func runPtydRun(cmd *cobra.Command, args []string) error {
+	server := ptyd.NewServer(ptyd.DefaultSocketPath(), ptyd.NewSupervisor(ptydScrollback))
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		_ = server.Close()
+	}()
+
+	return server.Serve()
+}
@@ -60,9 +60,14 @@ The command also:
   - Creates ~/gt/plugins/ (town-level) if it doesn't exist
   - Creates <rig>/plugins/ (rig-level)
 
+With --template, the rig also gets the template's settings, overlay files,
+setup hooks, plugins and formulas (see 'gt rig template'). The argument is
+a template name in <town>/templates/rigs/ or a template directory.
+
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
-  gt rig add my-project git@github.com:user/repo.git --prefix mp`,
+  gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add api git@github.com:user/api.git --template go-service`,
 	Args: cobra.ExactArgs(2),
 	RunE: runRigAdd,
 }
@@ -256,6 +261,7 @@ var (
 	rigAddPrefix       string
 	rigAddLocalRepo    string
 	rigAddBranch       string
+	rigAddTemplate     string
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -286,6 +292,7 @@ func init() {
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
 	rigAddCmd.Flags().StringVar(&rigAddBranch, "branch", "", "Default branch name (default: auto-detected from remote)")
+	rigAddCmd.Flags().StringVar(&rigAddTemplate, "template", "", "Rig template name or directory")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -331,6 +338,23 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Resolve and check the template before creating anything
+	var tmpl *rig.Template
+	if rigAddTemplate != "" {
+		if tmpl, err = rig.ResolveTemplate(townRoot, rigAddTemplate); err != nil {
+			return err
+		}
+		// Validate with the prefix AddRig will use; a source repo's tracked
+		// beads may still override it, and Apply re-validates then.
+		prefix := rigAddPrefix
+		if prefix == "" {
+			prefix = rig.DeriveBeadsPrefix(name)
+		}
+		if err := tmpl.Validate(rig.TemplateVars{Name: name, Prefix: prefix}); err != nil {
+			return err
+		}
+	}
+
 	// Create rig manager
 	g := git.NewGit(townRoot)
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
@@ -340,6 +364,9 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	if rigAddLocalRepo != "" {
 		fmt.Printf("  Local repo: %s\n", rigAddLocalRepo)
 	}
+	if tmpl != nil {
+		fmt.Printf("  Template: %s\n", tmpl.Name)
+	}
 
 	startTime := time.Now()
 
@@ -404,6 +431,13 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if tmpl != nil {
+		if err := applyRigTemplate(tmpl, newRig); err != nil {
+			// The rig itself is usable; 'gt rig template apply' can retry
+			fmt.Printf("  %s Could not apply template %s: %v\n", style.Warning.Render("!"), tmpl.Name, err)
+		}
+	}
+
 	elapsed := time.Since(startTime)
 
 	// Read default branch from rig config
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigTemplateSaveName        string
+	rigTemplateSaveDescription string
+	rigTemplateSaveLiteral     bool
+)
+
+var rigTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage rig templates",
+	RunE:  requireSubcommand,
+	Long: `Manage rig templates for 'gt rig add --template'.
+
+A template bundles what is otherwise hand-copied into every new rig:
+
+  template.json     Name and description
+  settings/         -> <rig>/settings/ (config.json: merge queue, namepool,
+                       role agents, crew startup, ...)
+  overlay/          -> <rig>/.runtime/overlay/
+  setup-hooks/      -> <rig>/.runtime/setup-hooks/
+  plugins/          -> <rig>/plugins/
+  formulas/         -> <rig>/.beads/formulas/
+
+Named templates live in <town>/templates/rigs/<name>/. In every text file,
+{{rig.name}} and {{rig.prefix}} are replaced with the new rig's name and
+beads prefix.`,
+}
+
+var rigTemplateSaveCmd = &cobra.Command{
+	Use:   "save <rig>",
+	Short: "Capture an existing rig as a template",
+	Long: `Save a rig's settings, overlay, setup hooks, plugins and formulas as a
+named template in <town>/templates/rigs/<name>/.
+
+Whole-word occurrences of the rig name and bead IDs with the rig's prefix
+are replaced by {{rig.name}} and {{rig.prefix}}; use --literal to keep
+them. Review the saved files before sharing: overlay files often hold
+secrets.
+
+Examples:
+  gt rig template save gastown
+  gt rig template save gastown --name go-service -d "Go service with CI hooks"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigTemplateSave,
+}
+
+var rigTemplateApplyCmd = &cobra.Command{
+	Use:   "apply <template> <rig>",
+	Short: "Apply a template to an existing rig",
+	Long: `Copy a template's settings, overlay, setup hooks, plugins and formulas
+into an existing rig, overwriting files with the same names. Existing
+polecat worktrees keep their overlay until they are recreated.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRigTemplateApply,
+}
+
+var rigTemplateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the town's rig templates",
+	RunE:  runRigTemplateList,
+}
+
+func init() {
+	rigTemplateSaveCmd.Flags().StringVar(&rigTemplateSaveName, "name", "", "Template name (default: rig name)")
+	rigTemplateSaveCmd.Flags().StringVarP(&rigTemplateSaveDescription, "description", "d", "", "Template description")
+	rigTemplateSaveCmd.Flags().BoolVar(&rigTemplateSaveLiteral, "literal", false, "Don't replace the rig name and prefix with variables")
+
+	rigTemplateCmd.AddCommand(rigTemplateSaveCmd)
+	rigTemplateCmd.AddCommand(rigTemplateApplyCmd)
+	rigTemplateCmd.AddCommand(rigTemplateListCmd)
+	rigCmd.AddCommand(rigTemplateCmd)
+}
+
+func runRigTemplateSave(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	name := rigTemplateSaveName
+	if name == "" {
+		name = r.Name
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid template name %q", name)
+	}
+
+	dest := filepath.Join(rig.TemplatesDir(townRoot), name)
+	t, err := rig.SaveTemplate(r.Path, dest, rig.Template{
+		Name:        name,
+		Description: rigTemplateSaveDescription,
+		CreatedFrom: r.Name,
+		CreatedAt:   time.Now().UTC(),
+	}, rigTemplateVars(r), !rigTemplateSaveLiteral)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Saved rig %s as template %s\n", style.Bold.Render("✓"), r.Name, style.Bold.Render(t.Name))
+	fmt.Printf("  %s\n", style.Dim.Render(t.Dir))
+	fmt.Printf("\nUse it with: gt rig add <name> <git-url> --template %s\n", t.Name)
+	return nil
+}
+
+func runRigTemplateApply(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[1])
+	if err != nil {
+		return err
+	}
+	t, err := rig.ResolveTemplate(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if err := applyRigTemplate(t, r); err != nil {
+		return err
+	}
+	fmt.Printf("%s Rig %s updated\n", style.Bold.Render("✓"), r.Name)
+	return nil
+}
+
+func runRigTemplateList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	templates, err := rig.ListTemplates(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		fmt.Printf("No rig templates in %s\n", rig.TemplatesDir(townRoot))
+		fmt.Println("Create one with: gt rig template save <rig>")
+		return nil
+	}
+	for _, t := range templates {
+		line := style.Bold.Render(t.Name)
+		if t.Description != "" {
+			line += "  " + t.Description
+		}
+		if t.CreatedFrom != "" {
+			line += "  " + style.Dim.Render("(from "+t.CreatedFrom+")")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// applyRigTemplate applies a resolved template to a rig and
+// reports what it wrote.
+func applyRigTemplate(t *rig.Template, r *rig.Rig) error {
+	written, err := t.Apply(r.Path, rigTemplateVars(r))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  Applied template %s (%d files)\n", style.Bold.Render(t.Name), len(written))
+	return nil
+}
+
+// rigTemplateVars returns the template variables for r.
+func rigTemplateVars(r *rig.Rig) rig.TemplateVars {
+	vars := rig.TemplateVars{Name: r.Name}
+	if r.Config != nil {
+		vars.Prefix = r.Config.Prefix
+	}
+	return vars
+}
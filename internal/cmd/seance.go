@@ -2,17 +2,24 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/seance"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -24,6 +31,10 @@ var (
 	seanceTalk   string
 	seancePrompt string
 	seanceJSON   bool
+
+	seanceNoSandbox   bool
+	seanceAllowWrites bool
+	seanceNoSave      bool
 )
 
 var seanceCmd = &cobra.Command{
@@ -53,6 +64,17 @@ THE SEANCE (talk to predecessor):
 The --talk flag spawns: claude --fork-session --resume <id>
 This loads the predecessor's full context without modifying their session.
 
+The fork runs read-only: in a disposable snapshot of the current worktree
+(HEAD plus uncommitted changes, files read-only), with a Claude settings
+profile that denies edits and disables hooks. Use --no-sandbox to run in
+the current directory (still with the read-only profile), or --allow-writes
+for the predecessor's full tool permissions.
+
+The Q&A is appended to your role's handoff bead, so the answers survive
+your own next handoff (skip with --no-save). With -p and --json the answer
+is printed as JSON for scripts:
+  gt seance --talk <id> -p "Which branch has the fix?" --json | jq -r .answer
+
 Sessions are discovered from:
   1. Events emitted by SessionStart hooks (~/gt/.events.jsonl)
   2. The [GAS TOWN] beacon makes sessions searchable in /resume`,
@@ -65,7 +87,10 @@ func init() {
 	seanceCmd.Flags().IntVarP(&seanceRecent, "recent", "n", 20, "Number of recent sessions to show")
 	seanceCmd.Flags().StringVarP(&seanceTalk, "talk", "t", "", "Session ID to commune with")
 	seanceCmd.Flags().StringVarP(&seancePrompt, "prompt", "p", "", "One-shot prompt (with --talk)")
-	seanceCmd.Flags().BoolVar(&seanceJSON, "json", false, "Output as JSON")
+	seanceCmd.Flags().BoolVar(&seanceJSON, "json", false, "Output as JSON (session list, or the answer to -p)")
+	seanceCmd.Flags().BoolVar(&seanceNoSandbox, "no-sandbox", false, "Run in the current directory instead of a snapshot worktree (still read-only)")
+	seanceCmd.Flags().BoolVar(&seanceAllowWrites, "allow-writes", false, "Resume with full tool permissions in the current directory")
+	seanceCmd.Flags().BoolVar(&seanceNoSave, "no-save", false, "Don't save the Q&A transcript to your handoff bead")
 
 	rootCmd.AddCommand(seanceCmd)
 }
@@ -188,30 +213,93 @@ func runSeanceList() error {
 }
 
 func runSeanceTalk(sessionID, prompt string) error {
-	// Expand short IDs if needed (user might provide partial)
-	// For now, require full ID or let claude --resume handle it
+	if seanceJSON && prompt == "" {
+		return fmt.Errorf("--json with --talk requires a one-shot prompt (-p)")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	townRoot, _ := workspace.FindFromCwd()
 
-	fmt.Printf("%s Summoning session %s...\n\n", style.Bold.Render("🔮"), sessionID)
+	// In JSON mode stdout carries only the answer
+	var status io.Writer = os.Stdout
+	if seanceJSON {
+		status = os.Stderr
+	}
+	fmt.Fprintf(status, "%s Summoning session %s...\n\n", style.Bold.Render("🔮"), sessionID)
 
 	// Build the command
 	args := []string{"--fork-session", "--resume", sessionID}
+	dir := cwd
+	var sandbox *seance.Sandbox
+	if !seanceAllowWrites {
+		settings, err := seanceSettings(townRoot)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--settings", settings)
+
+		if !seanceNoSandbox {
+			sandbox, err = openSeanceSandbox(townRoot, cwd, sessionID)
+			if err != nil {
+				fmt.Fprintf(status, "%s No sandbox (%v); running here with a read-only profile\n",
+					style.Dim.Render("⚠"), err)
+			} else {
+				defer closeSeanceSandbox(sandbox)
+				dir = sandbox.Dir
+				fmt.Fprintf(status, "%s\n", style.Dim.Render("Sandbox: read-only snapshot at "+sandbox.Commit[:min(12, len(sandbox.Commit))]))
+			}
+		}
+	}
+	original, _ := seance.FindSession(sessionID)
+	before := seance.Transcripts(dir)
 
 	if prompt != "" {
 		// One-shot mode with --print
 		args = append(args, "--print", prompt)
+		if seanceJSON {
+			args = append(args, "--output-format", "json")
+		}
 
+		var out bytes.Buffer
 		cmd := exec.Command("claude", args...)
-		cmd.Stdout = os.Stdout
+		cmd.Dir = dir
+		cmd.Stdout = &out
+		if !seanceJSON {
+			cmd.Stdout = io.MultiWriter(os.Stdout, &out)
+		}
 		cmd.Stderr = os.Stderr
 
-		if err := cmd.Run(); err != nil {
+		if err := runSeanceChild(cmd); err != nil {
 			return fmt.Errorf("seance failed: %w", err)
 		}
+
+		result := seance.ParseResult(out.Bytes())
+		answer := seanceAnswer{
+			SessionID: sessionID,
+			Prompt:    prompt,
+			Result:    result,
+			ReadOnly:  !seanceAllowWrites,
+			Sandboxed: sandbox != nil,
+		}
+		if !result.IsError {
+			answer.HandoffBead = saveSeanceTranscript(status, townRoot, cwd, sessionID, []seance.Exchange{
+				{Role: "user", Text: prompt},
+				{Role: "assistant", Text: result.Answer},
+			})
+		}
+		if seanceJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(answer)
+		}
 		return nil
 	}
 
 	// Interactive mode - just launch claude
 	cmd := exec.Command("claude", args...)
+	cmd.Dir = dir
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -219,19 +307,139 @@ func runSeanceTalk(sessionID, prompt string) error {
 	fmt.Printf("%s\n", style.Dim.Render("You are now talking to your predecessor. Ask them anything."))
 	fmt.Printf("%s\n\n", style.Dim.Render("Exit with /exit or Ctrl+C"))
 
-	if err := cmd.Run(); err != nil {
+	runErr := runSeanceChild(cmd)
+	if original != "" {
+		if fork := newestTranscript(dir, before); fork != "" {
+			if exchanges, err := seance.NewExchanges(original, fork); err == nil {
+				saveSeanceTranscript(status, townRoot, cwd, sessionID, exchanges)
+			}
+		}
+	}
+
+	if runErr != nil {
 		// Exit errors are normal when user exits
-		if exitErr, ok := err.(*exec.ExitError); ok {
+		if exitErr, ok := runErr.(*exec.ExitError); ok {
 			if exitErr.ExitCode() == 0 || exitErr.ExitCode() == 130 {
 				return nil // Normal exit or Ctrl+C
 			}
 		}
-		return fmt.Errorf("seance ended: %w", err)
+		return fmt.Errorf("seance ended: %w", runErr)
 	}
 
 	return nil
 }
 
+// runSeanceChild runs claude in the foreground. Ctrl+C reaches claude
+// through the terminal; gt catches it meanwhile so it survives to save the
+// transcript and remove the sandbox. (Notify rather than Ignore: an ignored
+// signal would stay ignored in the child.)
+func runSeanceChild(cmd *exec.Cmd) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+	return cmd.Run()
+}
+
+// seanceAnswer is the --json output of a one-shot seance.
+type seanceAnswer struct {
+	SessionID string `json:"session_id"`
+	Prompt    string `json:"prompt"`
+	seance.Result
+	ReadOnly    bool   `json:"read_only"`
+	Sandboxed   bool   `json:"sandboxed"`
+	HandoffBead string `json:"handoff_bead,omitempty"`
+}
+
+// seanceSettings returns the read-only Claude settings profile for seances,
+// creating it if needed.
+func seanceSettings(townRoot string) (string, error) {
+	base, dir := townRoot, filepath.Join(constants.DirRuntime, "seance")
+	if townRoot == "" {
+		base, dir = os.TempDir(), "gt-seance"
+	}
+	if err := claude.EnsureSettingsAt(base, claude.ReadOnly, dir, "settings.json"); err != nil {
+		return "", fmt.Errorf("creating read-only seance profile: %w", err)
+	}
+	return filepath.Join(base, dir, "settings.json"), nil
+}
+
+// openSeanceSandbox snapshots the current worktree into a disposable
+// read-only worktree and makes the predecessor's session resumable there.
+func openSeanceSandbox(townRoot, cwd, sessionID string) (*seance.Sandbox, error) {
+	transcript, err := seance.FindSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	parent := filepath.Join(os.TempDir(), "gt-seance")
+	if townRoot != "" {
+		parent = filepath.Join(townRoot, constants.DirRuntime, "seance")
+	}
+	id := sessionID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	dir := filepath.Join(parent, fmt.Sprintf("%s-%d", id, time.Now().UnixMilli()))
+
+	// Clear out sandboxes from seances that were killed before cleaning up
+	for _, stale := range seance.PruneSandboxes(parent) {
+		_ = os.RemoveAll(seance.ProjectDir(stale))
+	}
+
+	sandbox, err := seance.NewSandbox(cwd, dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := seance.CopySession(transcript, sandbox.Dir); err != nil {
+		closeSeanceSandbox(sandbox)
+		return nil, err
+	}
+	return sandbox, nil
+}
+
+// closeSeanceSandbox removes a sandbox and the transcripts Claude kept for it.
+func closeSeanceSandbox(sandbox *seance.Sandbox) {
+	if err := sandbox.Remove(); err != nil {
+		style.PrintWarning("could not remove seance sandbox %s: %v", sandbox.Dir, err)
+	}
+	_ = os.RemoveAll(seance.ProjectDir(sandbox.Dir))
+}
+
+// newestTranscript returns the most recent transcript in dir's Claude
+// project that isn't in before: the forked session.
+func newestTranscript(dir string, before map[string]bool) string {
+	newest := ""
+	var newestTime time.Time
+	for path := range seance.Transcripts(dir) {
+		if before[path] {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(newestTime) {
+			newest, newestTime = path, info.ModTime()
+		}
+	}
+	return newest
+}
+
+// saveSeanceTranscript appends the Q&A to the caller's handoff bead so it
+// survives the next handoff. Returns the bead ID, or "" if nothing was saved.
+func saveSeanceTranscript(status io.Writer, townRoot, cwd, sessionID string, exchanges []seance.Exchange) string {
+	if seanceNoSave || townRoot == "" || len(exchanges) == 0 {
+		return ""
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil || roleInfo.Role == RoleUnknown {
+		return ""
+	}
+	issue, err := beads.New(townRoot).AppendHandoffContent(string(roleInfo.Role),
+		seance.FormatTranscript(sessionID, time.Now(), exchanges))
+	if err != nil {
+		fmt.Fprintf(status, "%s Could not save seance transcript: %v\n", style.Dim.Render("⚠"), err)
+		return ""
+	}
+	fmt.Fprintf(status, "%s Saved seance transcript to %s\n", style.Bold.Render("📝"), issue.ID)
+	return issue.ID
+}
+
 // discoverSessions reads session_start events from our event stream.
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
 	eventsPath := filepath.Join(townRoot, events.EventsFile)
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/seance"
+)
+
+func TestSeanceTalkOneShotJSON(t *testing.T) {
+	// A git worktree outside any town, with a predecessor transcript
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	t.Setenv("TMPDIR", t.TempDir()) // sandbox and profile location outside a town
+	transcript := filepath.Join(seance.ProjectDir(repo), "sess-1.jsonl")
+	if err := os.MkdirAll(filepath.Dir(transcript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(transcript, []byte(`{"type":"user","uuid":"u1","message":{"content":"hi"}}`+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fake claude that records where and how it ran
+	binDir := t.TempDir()
+	record := filepath.Join(t.TempDir(), "claude-run")
+	writeScript(t, binDir, "claude", `#!/bin/sh
+{ pwd; echo "$@"; } > `+record+`
+echo '{"type":"result","result":"It is on fix/auth","session_id":"fork-1","is_error":false}'
+`)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	oldJSON, oldSave := seanceJSON, seanceNoSave
+	t.Cleanup(func() { seanceJSON, seanceNoSave = oldJSON, oldSave })
+	seanceJSON, seanceNoSave = true, true
+
+	var runErr error
+	out := captureStdout(t, func() { runErr = runSeanceTalk("sess-1", "Which branch?") })
+	if runErr != nil {
+		t.Fatalf("runSeanceTalk: %v", runErr)
+	}
+
+	var answer struct {
+		SessionID     string `json:"session_id"`
+		Prompt        string `json:"prompt"`
+		Answer        string `json:"answer"`
+		ForkSessionID string `json:"fork_session_id"`
+		ReadOnly      bool   `json:"read_only"`
+		Sandboxed     bool   `json:"sandboxed"`
+	}
+	if err := json.Unmarshal([]byte(out), &answer); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, out)
+	}
+	if answer.Answer != "It is on fix/auth" || answer.ForkSessionID != "fork-1" || !answer.ReadOnly || !answer.Sandboxed {
+		t.Errorf("answer = %+v", answer)
+	}
+
+	data, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitN(strings.TrimSpace(string(data)), "\n", 2)
+	ranIn, args := lines[0], lines[1]
+	if resolved, _ := filepath.EvalSymlinks(repo); ranIn == repo || ranIn == resolved {
+		t.Error("seance ran in the predecessor's worktree, not a sandbox")
+	}
+	if _, err := os.Stat(ranIn); !os.IsNotExist(err) {
+		t.Errorf("sandbox %s was not removed", ranIn)
+	}
+	for _, want := range []string{"--fork-session --resume sess-1", "--settings ", "--print Which branch?", "--output-format json"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("claude args %q lack %q", args, want)
+		}
+	}
+}
+
+func TestSeanceTalkJSONNeedsPrompt(t *testing.T) {
+	old := seanceJSON
+	t.Cleanup(func() { seanceJSON = old })
+	seanceJSON = true
+	if err := runSeanceTalk("sess-1", ""); err == nil {
+		t.Error("expected --json without -p to be rejected")
+	}
+}
+
+func TestRunSeanceChildSurvivesInterrupt(t *testing.T) {
+	// Ctrl+C in the terminal signals gt as well as claude; gt must outlive
+	// it to clean up. Without the handler this kills the test binary.
+	cmd := exec.Command("sh", "-c", "kill -INT $PPID; sleep 0.2")
+	if err := runSeanceChild(cmd); err != nil {
+		t.Fatalf("runSeanceChild: %v", err)
+	}
+}
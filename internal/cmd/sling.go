@@ -36,6 +36,13 @@ Auto-Convoy:
   gt sling gt-abc gastown              # Creates "Work: <issue-title>" convoy
   gt sling gt-abc gastown --no-convoy  # Skip auto-convoy creation
 
+Convoy Ordering:
+  If the issue's convoy runs after other convoys (gt convoy after) that
+  haven't landed, sling refuses to dispatch it. With --queue the sling is
+  recorded instead, and the daemon dispatches it once the predecessors land.
+
+  gt sling gt-abc gastown --queue      # Dispatch when upstream convoys land
+
 Target Resolution:
   gt sling gt-abc                       # Self (current agent)
   gt sling gt-abc crew                  # Crew worker in current rig
@@ -61,6 +68,10 @@ Formula Slinging:
   gt sling mol-release mayor/           # Cook + wisp + attach + nudge
   gt sling towers-of-hanoi --var disks=3
 
+--var values are checked against the formula's declared types (int, bool,
+enum, bead-id, rig, ...) before anything is slung; bead-id and rig values
+must exist.
+
 Formula-on-Bead (--on flag):
   gt sling mol-review --on gt-abc       # Apply formula to existing work
   gt sling shiny --on gt-abc crew       # Apply formula, sling to crew
@@ -95,6 +106,7 @@ var (
 	slingAccount  string // --account: Claude Code account handle to use
 	slingAgent    string // --agent: override runtime agent for this sling/spawn
 	slingNoConvoy bool   // --no-convoy: skip auto-convoy creation
+	slingQueue    bool   // --queue: queue work gated by convoy ordering
 )
 
 func init() {
@@ -111,6 +123,7 @@ func init() {
 	slingCmd.Flags().StringVar(&slingAccount, "account", "", "Claude Code account handle to use")
 	slingCmd.Flags().StringVar(&slingAgent, "agent", "", "Override agent/runtime for this sling (e.g., claude, gemini, codex, or custom alias)")
 	slingCmd.Flags().BoolVar(&slingNoConvoy, "no-convoy", false, "Skip auto-convoy creation for single-issue sling")
+	slingCmd.Flags().BoolVar(&slingQueue, "queue", false, "Queue work whose convoy waits on unlanded convoys instead of refusing")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -185,6 +198,13 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Convoy ordering: refuse (or queue) work whose convoy waits on
+	// predecessor convoys that haven't landed. Checked before the target is
+	// resolved so no polecat is spawned for gated work.
+	if gated, err := checkSlingGate(townRoot, beadID, formulaName, args[1:]); err != nil || gated {
+		return err
+	}
+
 	// Determine target agent (self or specified)
 	var targetAgent string
 	var targetPane string
@@ -379,10 +399,21 @@ func runSling(cmd *cobra.Command, args []string) error {
 		// rig directory that owns the bead's database.
 		formulaWorkDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 
+		featureVar := fmt.Sprintf("feature=%s", info.Title)
+		issueVar := fmt.Sprintf("issue=%s", beadID)
+
+		// Composition and when/foreach are applied here; bd pours the result.
+		pourName, cleanup, err := expandSlingFormula(townRoot, formulaWorkDir, formulaName, []string{featureVar, issueVar})
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
 		// Step 1: Cook the formula (ensures proto exists)
 		// Cook runs from rig directory to access the correct formula database
-		cookCmd := exec.Command("bd", "--no-daemon", "cook", formulaName)
+		cookCmd := exec.Command("bd", "--no-daemon", "cook", pourName)
 		cookCmd.Dir = formulaWorkDir
+		cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 		cookCmd.Stderr = os.Stderr
 		if err := cookCmd.Run(); err != nil {
 			return fmt.Errorf("cooking formula %s: %w", formulaName, err)
@@ -390,9 +421,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 		// Step 2: Create wisp with feature and issue variables from bead
 		// Run from rig directory so wisp is created in correct database
-		featureVar := fmt.Sprintf("feature=%s", info.Title)
-		issueVar := fmt.Sprintf("issue=%s", beadID)
-		wispArgs := []string{"--no-daemon", "mol", "wisp", formulaName, "--var", featureVar, "--var", issueVar, "--json"}
+		wispArgs := []string{"--no-daemon", "mol", "wisp", pourName, "--var", featureVar, "--var", issueVar, "--json"}
 		wispCmd := exec.Command("bd", wispArgs...)
 		wispCmd.Dir = formulaWorkDir
 		wispCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
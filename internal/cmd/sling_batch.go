@@ -30,6 +30,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 	}
 
 	fmt.Printf("%s Batch slinging %d beads to rig '%s'...\n", style.Bold.Render("🎯"), len(beadIDs), rigName)
+	townRoot := filepath.Dir(townBeadsDir)
 
 	// Track results for summary
 	type slingResult struct {
@@ -58,6 +59,17 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		// Convoy ordering: don't spawn for work that has to wait
+		if gated, err := checkSlingGate(townRoot, beadID, "", []string{rigName}); err != nil || gated {
+			if err != nil {
+				results = append(results, slingResult{beadID: beadID, success: false, errMsg: "waiting on convoy"})
+				fmt.Printf("  %s %v\n", style.Dim.Render("✗"), err)
+			} else {
+				results = append(results, slingResult{beadID: beadID, polecat: "(queued)", success: true})
+			}
+			continue
+		}
+
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
 			Force:    slingForce,
@@ -92,7 +104,6 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 
 		// Hook the bead. See: https://github.com/steveyegge/gastown/issues/148
-		townRoot := filepath.Dir(townBeadsDir)
 		hookCmd := exec.Command("bd", "--no-daemon", "update", beadID, "--status=hooked", "--assignee="+targetAgent)
 		hookCmd.Dir = beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 		hookCmd.Stderr = os.Stderr
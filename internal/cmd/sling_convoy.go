@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
@@ -128,3 +129,47 @@ func formatTrackBeadID(beadID string) string {
 	// Fallback for malformed IDs (single segment)
 	return beadID
 }
+
+// checkSlingGate checks whether a bead's convoy is waiting on predecessor
+// convoys. Gated work is refused, or queued for its target with --queue.
+// formulaName is the formula slung --on the bead, if any.
+// Returns true when the sling must stop here.
+func checkSlingGate(townRoot, beadID, formulaName string, target []string) (bool, error) {
+	convoyID, pending, err := slingGate(filepath.Join(townRoot, ".beads"), beadID)
+	if err != nil || convoyID == "" {
+		// Like isTrackedByConvoy, an unreadable town DB means no ordering
+		return false, nil
+	}
+
+	waiting := formatConvoyRefs(pending)
+	if !slingQueue {
+		return true, fmt.Errorf("%s is tracked by convoy %s, which runs after unlanded convoy(s): %s\nUse --queue to dispatch it when they land", beadID, convoyID, waiting)
+	}
+	if len(target) == 0 {
+		return true, fmt.Errorf("--queue needs an explicit target (e.g. gt sling %s <rig> --queue)", beadID)
+	}
+	if slingDryRun {
+		fmt.Printf("Would queue %s for %s until %s land(s)\n", beadID, target[0], waiting)
+		return true, nil
+	}
+	if err := queueSling(townRoot, queuedSling{
+		Issue:    beadID,
+		Target:   target[0],
+		Convoy:   convoyID,
+		QueuedAt: time.Now(),
+		Formula:  formulaName,
+		Args:     slingArgs,
+		Subject:  slingSubject,
+		Message:  slingMessage,
+		Agent:    slingAgent,
+		Account:  slingAccount,
+		Create:   slingCreate,
+		Force:    slingForce,
+		NoConvoy: slingNoConvoy,
+	}); err != nil {
+		return true, fmt.Errorf("queueing %s: %w", beadID, err)
+	}
+	fmt.Printf("%s Queued %s for %s\n", style.Bold.Render("⏸"), beadID, target[0])
+	fmt.Printf("  Waiting on: %s\n", waiting)
+	return true, nil
+}
@@ -1,15 +1,19 @@
 package cmd
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -69,6 +73,127 @@ func verifyFormulaExists(formulaName string) error {
 	return fmt.Errorf("formula '%s' not found (check 'bd formula list')", formulaName)
 }
 
+// validateSlingVars checks --var values against the types the formula
+// declares, prompting for missing required vars on a terminal, and returns
+// them as key=value arguments. Formulas that aren't in a local search path
+// are left for bd to check.
+func validateSlingVars(formulaName string) ([]string, error) {
+	vars, err := parseFormulaVars(slingVars)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parseSlingFormula(formulaName)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return slingVars, nil
+	}
+	if f.IsComposed() {
+		// Vars may be declared by the formulas it builds on.
+		if f, err = f.Resolve(loadFormula); err != nil {
+			return nil, fmt.Errorf("resolving formula: %w", err)
+		}
+	}
+	if vars, err = resolveFormulaVars(f, vars); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := make([]string, len(names))
+	for i, name := range names {
+		args[i] = name + "=" + vars[name]
+	}
+	return args, nil
+}
+
+// parseSlingFormula finds a formula by name (or with the mol- prefix) in
+// the local search paths and parses it. Returns nil if there's no local
+// TOML formula, leaving the formula to bd.
+func parseSlingFormula(formulaName string) (*formula.Formula, error) {
+	path, err := findFormulaFile(formulaName)
+	if err != nil {
+		if path, err = findFormulaFile("mol-" + formulaName); err != nil {
+			return nil, nil
+		}
+	}
+	if !strings.HasSuffix(path, ".toml") {
+		return nil, nil
+	}
+	f, err := formula.ParseFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing formula: %w", err)
+	}
+	return f, nil
+}
+
+// expandSlingFormula prepares a formula bd can't pour as written: bd
+// doesn't apply gt's composition (extends, compose) or evaluate when and
+// foreach, so the formula is resolved, expanded with vars (key=value) and
+// written to the town formulas directory under a derived name, which is
+// cooked and poured in its place. Other formulas are returned unchanged.
+// Call cleanup once the wisp is poured: it removes the derived formula and
+// the proto bd cooked from it in workDir ("" for the current directory).
+func expandSlingFormula(townRoot, workDir, formulaName string, vars []string) (string, func(), error) {
+	noop := func() {}
+	f, err := parseSlingFormula(formulaName)
+	if err != nil || f == nil || !(f.IsComposed() || f.HasExpansion()) {
+		return formulaName, noop, err
+	}
+
+	expanded := f
+	if f.IsComposed() {
+		if expanded, err = f.Resolve(loadFormula); err != nil {
+			return "", noop, fmt.Errorf("resolving formula %s: %w", formulaName, err)
+		}
+	}
+	if expanded.HasExpansion() {
+		values, err := parseFormulaVars(vars)
+		if err != nil {
+			return "", noop, err
+		}
+		if expanded, err = expanded.Expand(values); err != nil {
+			return "", noop, fmt.Errorf("expanding formula %s: %w", formulaName, err)
+		}
+	}
+
+	// The name is unique per sling, so concurrent slings of the same
+	// formula don't pour each other's expansion.
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%s", os.Getpid(), f.Name, strings.Join(vars, "\x00"))))
+	name := fmt.Sprintf("%s-expanded-%x", f.Name, sum[:4])
+
+	var buf bytes.Buffer
+	if err := expanded.EncodeConcrete(&buf, name); err != nil {
+		return "", noop, fmt.Errorf("writing expanded formula: %w", err)
+	}
+	dir := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", noop, fmt.Errorf("writing expanded formula: %w", err)
+	}
+	path := filepath.Join(dir, name+".formula.toml")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return "", noop, fmt.Errorf("writing expanded formula: %w", err)
+	}
+	return name, func() {
+		_ = os.Remove(path)
+		deleteCookedProto(townRoot, workDir, name)
+	}, nil
+}
+
+// deleteCookedProto removes the proto bd cook made for a derived formula,
+// so each sling doesn't leave one behind. Best-effort: the poured wisp
+// doesn't need it.
+func deleteCookedProto(townRoot, workDir, name string) {
+	cmd := exec.Command("bd", "--no-daemon", "delete", name, "--hard", "--force")
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
+	_ = cmd.Run()
+}
+
 // runSlingFormula handles standalone formula slinging.
 // Flow: cook → wisp → attach to hook → nudge
 func runSlingFormula(args []string) error {
@@ -81,6 +206,13 @@ func runSlingFormula(args []string) error {
 	}
 	townBeadsDir := filepath.Join(townRoot, ".beads")
 
+	// Validate vars before resolving the target, which may dispatch a dog
+	// or spawn a polecat.
+	wispVars, err := validateSlingVars(formulaName)
+	if err != nil {
+		return err
+	}
+
 	// Determine target (self or specified)
 	var target string
 	if len(args) > 1 {
@@ -171,17 +303,25 @@ func runSlingFormula(args []string) error {
 	if slingDryRun {
 		fmt.Printf("Would cook formula: %s\n", formulaName)
 		fmt.Printf("Would create wisp and pin to: %s\n", targetAgent)
-		for _, v := range slingVars {
+		for _, v := range wispVars {
 			fmt.Printf("  --var %s\n", v)
 		}
 		fmt.Printf("Would nudge pane: %s\n", targetPane)
 		return nil
 	}
 
+	// Composition and when/foreach are applied here; bd pours the result.
+	pourName, cleanup, err := expandSlingFormula(townRoot, "", formulaName, wispVars)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
 	// Step 1: Cook the formula (ensures proto exists)
 	fmt.Printf("  Cooking formula...\n")
-	cookArgs := []string{"--no-daemon", "cook", formulaName}
+	cookArgs := []string{"--no-daemon", "cook", pourName}
 	cookCmd := exec.Command("bd", cookArgs...)
+	cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 	cookCmd.Stderr = os.Stderr
 	if err := cookCmd.Run(); err != nil {
 		return fmt.Errorf("cooking formula: %w", err)
@@ -189,13 +329,14 @@ func runSlingFormula(args []string) error {
 
 	// Step 2: Create wisp instance (ephemeral)
 	fmt.Printf("  Creating wisp...\n")
-	wispArgs := []string{"--no-daemon", "mol", "wisp", formulaName}
-	for _, v := range slingVars {
+	wispArgs := []string{"--no-daemon", "mol", "wisp", pourName}
+	for _, v := range wispVars {
 		wispArgs = append(wispArgs, "--var", v)
 	}
 	wispArgs = append(wispArgs, "--json")
 
 	wispCmd := exec.Command("bd", wispArgs...)
+	wispCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 	wispCmd.Stderr = os.Stderr // Show wisp errors to user
 	wispOut, err := wispCmd.Output()
 	if err != nil {
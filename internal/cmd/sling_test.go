@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
 )
 
 func TestParseWispIDFromJSON(t *testing.T) {
@@ -678,20 +681,20 @@ func TestLooksLikeBeadID(t *testing.T) {
 		{"hq-00gyg", true},
 
 		// Short prefixes that match pattern (but may be formulas in practice)
-		{"mol-release", true},    // 3-char prefix matches pattern (formula check runs first in sling)
-		{"mol-abc123", true},     // 3-char prefix matches pattern
+		{"mol-release", true}, // 3-char prefix matches pattern (formula check runs first in sling)
+		{"mol-abc123", true},  // 3-char prefix matches pattern
 
 		// Non-bead strings - should return false
-		{"formula-name", false},  // "formula" is 7 chars (> 5)
-		{"mayor", false},         // no hyphen
-		{"gastown", false},       // no hyphen
-		{"deacon/dogs", false},   // contains slash
-		{"", false},              // empty
-		{"-abc", false},          // starts with hyphen
-		{"GT-abc", false},        // uppercase prefix
-		{"123-abc", false},       // numeric prefix
-		{"a-", false},            // nothing after hyphen
-		{"aaaaaa-b", false},      // prefix too long (6 chars)
+		{"formula-name", false}, // "formula" is 7 chars (> 5)
+		{"mayor", false},        // no hyphen
+		{"gastown", false},      // no hyphen
+		{"deacon/dogs", false},  // contains slash
+		{"", false},             // empty
+		{"-abc", false},         // starts with hyphen
+		{"GT-abc", false},       // uppercase prefix
+		{"123-abc", false},      // numeric prefix
+		{"a-", false},           // nothing after hyphen
+		{"aaaaaa-b", false},     // prefix too long (6 chars)
 	}
 
 	for _, tt := range tests {
@@ -859,3 +862,192 @@ exit 0
 			"Log output:\n%s", string(logBytes))
 	}
 }
+
+func TestExpandSlingFormula(t *testing.T) {
+	townRoot := t.TempDir()
+	formulasDir := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor", "rig"), 0755); err != nil {
+		t.Fatalf("mkdir mayor/rig: %v", err)
+	}
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatalf("mkdir formulas: %v", err)
+	}
+	release := `
+formula = "release"
+type = "workflow"
+
+[vars.platforms]
+default = "linux"
+
+[[steps]]
+id = "build-{{item}}"
+title = "Build {{item}}"
+foreach = "platforms"
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["build-{{item}}"]
+when = "platforms != linux"
+`
+	plain := `
+formula = "plain"
+type = "workflow"
+
+[[steps]]
+id = "only"
+title = "Only step"
+`
+	child := `
+formula = "child"
+type = "workflow"
+extends = ["plain"]
+
+[[steps]]
+id = "extra"
+title = "Extra step"
+needs = ["only"]
+`
+	for name, content := range map[string]string{"release": release, "plain": plain, "child": child} {
+		if err := os.WriteFile(filepath.Join(formulasDir, name+".formula.toml"), []byte(content), 0644); err != nil {
+			t.Fatalf("write formula: %v", err)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(townRoot); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+
+	// Formulas without when/foreach are poured as is.
+	name, cleanup, err := expandSlingFormula(townRoot, "", "plain", nil)
+	if err != nil || name != "plain" {
+		t.Fatalf("expandSlingFormula(plain) = %q, %v", name, err)
+	}
+	cleanup()
+
+	name, cleanup, err = expandSlingFormula(townRoot, "", "release", []string{"platforms=linux,darwin"})
+	if err != nil {
+		t.Fatalf("expandSlingFormula(release): %v", err)
+	}
+	if !strings.HasPrefix(name, "release-expanded-") {
+		t.Fatalf("pour name = %q, want release-expanded-*", name)
+	}
+	path := filepath.Join(formulasDir, name+".formula.toml")
+	f, err := formula.ParseFile(path)
+	if err != nil {
+		t.Fatalf("parse expanded formula: %v", err)
+	}
+	if f.Name != name || f.HasExpansion() {
+		t.Errorf("expanded formula name %q, HasExpansion %v", f.Name, f.HasExpansion())
+	}
+	var ids []string
+	for _, step := range f.Steps {
+		ids = append(ids, step.ID)
+	}
+	if got := strings.Join(ids, ","); got != "build-linux,build-darwin,publish" {
+		t.Errorf("expanded steps = %s", got)
+	}
+
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expanded formula not removed after pouring: %v", err)
+	}
+
+	// Composed formulas are poured resolved, with inherited steps.
+	name, cleanup, err = expandSlingFormula(townRoot, "", "child", nil)
+	if err != nil {
+		t.Fatalf("expandSlingFormula(child): %v", err)
+	}
+	defer cleanup()
+	f, err = formula.ParseFile(filepath.Join(formulasDir, name+".formula.toml"))
+	if err != nil {
+		t.Fatalf("parse resolved formula: %v", err)
+	}
+	if f.IsComposed() || len(f.Steps) != 2 || f.Steps[0].ID != "only" || f.Steps[1].ID != "extra" {
+		t.Errorf("resolved formula = %+v", f)
+	}
+}
+
+// stubProtoBD installs a bd that records cooked protos as files in the
+// returned directory and removes them on delete.
+func stubProtoBD(t *testing.T) string {
+	t.Helper()
+	binDir, protos := t.TempDir(), t.TempDir()
+	writeScript(t, binDir, "bd", `#!/bin/sh
+[ "$1" = "--no-daemon" ] && shift
+case "$1" in
+  cook) touch "`+protos+`/$2" ;;
+  delete) rm -f "`+protos+`/$2" ;;
+esac
+`)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return protos
+}
+
+// setupFormulaTown creates a town with the given formulas and changes into it.
+func setupFormulaTown(t *testing.T, formulas map[string]string) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	formulasDir := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor", "rig"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range formulas {
+		if err := os.WriteFile(filepath.Join(formulasDir, name+".formula.toml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+// assertNoProtoLeft slings formulaName the way gt sling does (expand, cook,
+// clean up) and checks that no cooked proto is left behind.
+func assertNoProtoLeft(t *testing.T, townRoot, formulaName string, vars []string) {
+	t.Helper()
+	protos := stubProtoBD(t)
+	name, cleanup, err := expandSlingFormula(townRoot, "", formulaName, vars)
+	if err != nil {
+		t.Fatalf("expandSlingFormula(%s): %v", formulaName, err)
+	}
+	if name == formulaName {
+		t.Fatalf("%s was not expanded", formulaName)
+	}
+	if out, err := exec.Command("bd", "--no-daemon", "cook", name).CombinedOutput(); err != nil {
+		t.Fatalf("cook: %v\n%s", err, out)
+	}
+	cleanup()
+	if left, _ := os.ReadDir(protos); len(left) != 0 {
+		t.Errorf("protos left after slinging %s: %v", formulaName, left)
+	}
+}
+
+func TestExpandSlingFormulaDeletesProto(t *testing.T) {
+	townRoot := setupFormulaTown(t, map[string]string{
+		"release": "formula = \"release\"\ntype = \"workflow\"\n\n[vars.platforms]\ndefault = \"linux\"\n\n[[steps]]\nid = \"build-{{item}}\"\ntitle = \"Build {{item}}\"\nforeach = \"platforms\"\n",
+	})
+	assertNoProtoLeft(t, townRoot, "release", []string{"platforms=linux,darwin"})
+}
+
+func TestExpandSlingFormulaDeletesComposedProto(t *testing.T) {
+	townRoot := setupFormulaTown(t, map[string]string{
+		"base":  "formula = \"base\"\ntype = \"workflow\"\n\n[[steps]]\nid = \"only\"\ntitle = \"Only\"\n",
+		"child": "formula = \"child\"\ntype = \"workflow\"\nextends = [\"base\"]\n\n[[steps]]\nid = \"extra\"\ntitle = \"Extra\"\nneeds = [\"only\"]\n",
+	})
+	assertNoProtoLeft(t, townRoot, "child", nil)
+}
@@ -33,6 +33,10 @@ var (
 	swarmListStatus string
 	swarmListJSON   bool
 	swarmTarget     string
+
+	swarmLandSkipVerify  bool
+	swarmLandForce       bool
+	swarmLandTestCommand string
 )
 
 var swarmCmd = &cobra.Command{
@@ -103,12 +107,40 @@ var swarmLandCmd = &cobra.Command{
 	Short: "Land a swarm to main",
 	Long: `Manually trigger landing for a completed swarm.
 
-Merges the integration branch to the target branch (usually main).
-Normally this is done automatically by the Refinery.`,
+Before landing, verifies that every swarm task bead is closed and runs the
+rig's test command (merge_queue.test_command) on the integration branch.
+If verification fails, nothing is stopped or merged and the Mayor is told
+why.
+
+The integration branch is then merged to the target branch (usually main)
+as a single merge commit, tagged landed/<swarm-id>. Revert it with
+'gt swarm unland'. Normally this is done automatically by the Refinery.
+
+--skip-verify skips the verification above, but a swarm with ready, active
+or blocked tasks is still refused unless --force is given.
+
+Examples:
+  gt swarm land gt-abc
+  gt swarm land gt-abc --test-command "make check"
+  gt swarm land gt-abc --skip-verify
+  gt swarm land gt-abc --skip-verify --force`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSwarmLand,
 }
 
+var swarmUnlandCmd = &cobra.Command{
+	Use:   "unland <swarm-id>",
+	Short: "Revert a landed swarm",
+	Long: `Revert a swarm's landing merge on its target branch.
+
+Finds the merge tagged landed/<swarm-id>, commits a revert of it on the
+target branch and pushes it, reopens the swarm epic and sends the results
+to the Mayor. A revert that conflicts with later work is aborted and
+nothing is pushed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSwarmUnland,
+}
+
 var swarmCancelCmd = &cobra.Command{
 	Use:   "cancel <swarm-id>",
 	Short: "Cancel a swarm",
@@ -162,6 +194,11 @@ func init() {
 	swarmListCmd.Flags().StringVar(&swarmListStatus, "status", "", "Filter by status (active, landed, canceled, failed)")
 	swarmListCmd.Flags().BoolVar(&swarmListJSON, "json", false, "Output as JSON")
 
+	// Land flags
+	swarmLandCmd.Flags().BoolVar(&swarmLandSkipVerify, "skip-verify", false, "Land without checking task beads or running tests")
+	swarmLandCmd.Flags().BoolVar(&swarmLandForce, "force", false, "Land even if the swarm has incomplete tasks")
+	swarmLandCmd.Flags().StringVar(&swarmLandTestCommand, "test-command", "", "Test command to verify with (default: rig's merge_queue.test_command)")
+
 	// Dispatch flags
 	swarmDispatchCmd.Flags().StringVar(&swarmDispatchRig, "rig", "", "Rig to dispatch in (auto-detected from epic if not specified)")
 
@@ -171,6 +208,7 @@ func init() {
 	swarmCmd.AddCommand(swarmStatusCmd)
 	swarmCmd.AddCommand(swarmListCmd)
 	swarmCmd.AddCommand(swarmLandCmd)
+	swarmCmd.AddCommand(swarmUnlandCmd)
 	swarmCmd.AddCommand(swarmCancelCmd)
 	swarmCmd.AddCommand(swarmDispatchCmd)
 
@@ -744,8 +782,8 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check if all tasks are complete
-	if len(status.Ready) > 0 || len(status.Active) > 0 || len(status.Blocked) > 0 {
-		return fmt.Errorf("swarm has incomplete tasks: %d ready, %d active, %d blocked",
+	if !swarmLandForce && (len(status.Ready) > 0 || len(status.Active) > 0 || len(status.Blocked) > 0) {
+		return fmt.Errorf("swarm has incomplete tasks: %d ready, %d active, %d blocked (use --force to land anyway)",
 			len(status.Ready), len(status.Active), len(status.Blocked))
 	}
 
@@ -760,13 +798,21 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 
 	// Execute full landing protocol
 	config := swarm.LandingConfig{
-		TownRoot: townRoot,
+		TownRoot:    townRoot,
+		SkipVerify:  swarmLandSkipVerify,
+		TestCommand: swarmLandTestCommand,
+	}
+	if config.TestCommand == "" {
+		config.TestCommand = getTestCommand(foundRig.Path)
 	}
 	result, err := mgr.ExecuteLanding(swarmID, config)
 	if err != nil {
 		return fmt.Errorf("landing protocol: %w", err)
 	}
 
+	if v := result.Verify; v != nil {
+		printSwarmVerify(v, config.TestCommand)
+	}
 	if !result.Success {
 		return fmt.Errorf("landing failed: %s", result.Error)
 	}
@@ -782,12 +828,87 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 		style.PrintWarning("couldn't close swarm epic in beads: %v", err)
 	}
 
-	fmt.Printf("%s Swarm %s landed to main\n", style.Bold.Render("✓"), sw.ID)
+	fmt.Printf("%s Swarm %s landed to %s\n", style.Bold.Render("✓"), sw.ID, sw.TargetBranch)
+	fmt.Printf("  Merge commit:     %s (%s)\n", truncate(result.MergeCommit, 8), result.Tag)
 	fmt.Printf("  Sessions stopped: %d\n", result.SessionsStopped)
 	fmt.Printf("  Branches cleaned: %d\n", result.BranchesCleaned)
 	return nil
 }
 
+// printSwarmVerify prints the pre-land verification results.
+func printSwarmVerify(v *swarm.VerifyResult, testCommand string) {
+	switch {
+	case v.TasksError != "":
+		fmt.Printf("  %s Could not load tasks: %s\n", style.Bold.Render("✗"), v.TasksError)
+	case v.NoTasks:
+		fmt.Printf("  %s Swarm has no tasks\n", style.Bold.Render("✗"))
+	case len(v.OpenTasks) > 0:
+		fmt.Printf("  %s Tasks not closed: %s\n", style.Bold.Render("✗"), strings.Join(v.OpenTasks, ", "))
+	default:
+		fmt.Printf("  %s All task beads closed\n", style.Bold.Render("✓"))
+	}
+	switch {
+	case !v.TestsRun:
+		fmt.Printf("  %s\n", style.Dim.Render("(no test command configured)"))
+	case v.TestsPassed:
+		fmt.Printf("  %s Tests passed: %s\n", style.Bold.Render("✓"), testCommand)
+	default:
+		fmt.Printf("  %s Tests failed: %s\n", style.Bold.Render("✗"), testCommand)
+		if v.TestOutput != "" {
+			fmt.Println(style.Dim.Render(v.TestOutput))
+		}
+	}
+}
+
+func runSwarmUnland(cmd *cobra.Command, args []string) error {
+	swarmID := args[0]
+
+	// Find the swarm's rig
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+
+	var foundRig *rig.Rig
+	for _, r := range rigs {
+		// Use BeadsPath() for git-synced beads
+		checkCmd := exec.Command("bd", "show", swarmID, "--json")
+		checkCmd.Dir = r.BeadsPath()
+		if err := checkCmd.Run(); err == nil {
+			foundRig = r
+			break
+		}
+	}
+
+	if foundRig == nil {
+		return fmt.Errorf("swarm '%s' not found", swarmID)
+	}
+
+	mgr := swarm.NewManager(foundRig)
+	result, err := mgr.Unland(swarmID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Reverted landing %s on %s\n", style.Bold.Render("✓"), truncate(result.MergeCommit, 8), result.Target)
+	fmt.Printf("  Revert commit: %s\n", truncate(result.RevertCommit, 8))
+
+	// Reopen the swarm epic in beads
+	reopenCmd := exec.Command("bd", "reopen", swarmID, "--reason", "Swarm unlanded")
+	reopenCmd.Dir = foundRig.BeadsPath()
+	var stderr bytes.Buffer
+	reopenCmd.Stderr = &stderr
+	var reopenErr error
+	if err := reopenCmd.Run(); err != nil {
+		reopenErr = fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+		style.PrintWarning("couldn't reopen swarm epic in beads: %v", reopenErr)
+	} else {
+		fmt.Printf("  Epic %s reopened\n", swarmID)
+	}
+
+	mgr.NotifyMayorUnlanded(result, reopenErr)
+	return nil
+}
+
 func runSwarmCancel(cmd *cobra.Command, args []string) error {
 	swarmID := args[0]
 
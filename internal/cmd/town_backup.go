@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townbackup"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townExportOutput      string
+	townExportCheckpoints bool
+	townImportDryRun      bool
+	townImportForce       bool
+)
+
+var townExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the town to a tarball for backup or migration",
+	Long: `Export the town's state to a versioned tarball.
+
+The export holds what can't be recovered from the rigs' git remotes:
+  - mayor/ configs (town, rigs, mayor, accounts, daemon patrol, overseer)
+  - town settings/, config/ and plugins/
+  - beads JSONL exports, including agent beads, plus formulas
+  - each rig's config.json, settings/ (namepools etc.) and plugins/
+  - the audit-chained logs (.events.jsonl, logs/town.log) with their
+    anchors and public key
+  - polecat checkpoints, with --checkpoints
+
+Beads databases are flushed to JSONL first. Rig repos aren't included;
+gt town import re-clones them. The private audit key lives outside the
+town and is not exported.
+
+Examples:
+  gt town export
+  gt town export -o ~/backups/town.tar.gz --checkpoints`,
+	Args: cobra.NoArgs,
+	RunE: runTownExport,
+}
+
+var townImportCmd = &cobra.Command{
+	Use:   "import <export.tar.gz>",
+	Short: "Restore a town export into this town",
+	Long: `Restore a town export made with gt town export.
+
+Run it in a town created with gt install. Import:
+  1. Checks every config against its schema version
+  2. Re-clones each rig from its git URL (rigs already here are kept)
+  3. Writes the exported configs, beads exports, formulas and plugins,
+     and the checkpoints of polecats that exist in this town
+  4. Rewires routes.jsonl to where each rig's beads live in this town
+  5. Imports the beads exports into the beads databases
+
+Configs are replaced. Replacing beads exports that already hold other
+issues is a conflict, as is a rig directory holding a different repo.
+Use --dry-run to see what would change and any conflicts; --force
+replaces conflicting beads exports.
+
+Examples:
+  gt town import town.tar.gz --dry-run
+  gt town import town.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownImport,
+}
+
+func init() {
+	townExportCmd.Flags().StringVarP(&townExportOutput, "output", "o", "", "Output file (default: <town>-export-<timestamp>.tar.gz)")
+	townExportCmd.Flags().BoolVar(&townExportCheckpoints, "checkpoints", false, "Include polecat checkpoints")
+	townImportCmd.Flags().BoolVar(&townImportDryRun, "dry-run", false, "Show what would change without changing anything")
+	townImportCmd.Flags().BoolVar(&townImportForce, "force", false, "Replace conflicting beads exports")
+
+	townCmd.AddCommand(townExportCmd)
+	townCmd.AddCommand(townImportCmd)
+}
+
+// townBeadsDirs returns the town's beads directories that hold a database.
+func townBeadsDirs(townRoot string, rigs []townbackup.Rig) []string {
+	candidates := []string{filepath.Join(townRoot, constants.DirBeads)}
+	for _, r := range rigs {
+		rigPath := filepath.Join(townRoot, r.Name)
+		candidates = append(candidates,
+			filepath.Join(rigPath, constants.DirBeads),
+			constants.RigBeadsPath(rigPath))
+	}
+	var dirs []string
+	for _, dir := range candidates {
+		if _, err := os.Stat(filepath.Join(dir, "beads.db")); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// runBdSync runs bd sync with args in the directory holding beadsDir.
+func runBdSync(beadsDir string, args ...string) error {
+	cmd := exec.Command("bd", append([]string{"--no-daemon", "sync"}, args...)...) //nolint:gosec // G204: args are fixed flags
+	cmd.Dir = filepath.Dir(beadsDir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+func runTownExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	now := time.Now()
+	m, err := townbackup.Collect(townRoot, townbackup.Options{Checkpoints: townExportCheckpoints, GTVersion: Version}, now)
+	if err != nil {
+		return err
+	}
+
+	// Flush the databases so the JSONL exports are current, then collect
+	// again in case a flush created an export
+	for _, dir := range townBeadsDirs(townRoot, m.Rigs) {
+		if err := runBdSync(dir, "--flush-only"); err != nil {
+			style.PrintWarning("could not flush %s: %v", dir, err)
+		}
+	}
+	m, err = townbackup.Collect(townRoot, townbackup.Options{Checkpoints: townExportCheckpoints, GTVersion: Version}, now)
+	if err != nil {
+		return err
+	}
+
+	output := townExportOutput
+	if output == "" {
+		output = fmt.Sprintf("%s-export-%s.tar.gz", m.Town, now.Format("20060102-150405"))
+	}
+
+	// Write beside the destination and rename, so a failed export never
+	// leaves a truncated tarball under the final name
+	tmp, err := os.CreateTemp(filepath.Dir(output), ".town-export-*")
+	if err != nil {
+		return fmt.Errorf("creating export: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := townbackup.Export(townRoot, m, tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), output); err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+
+	checkpoints := 0
+	for _, f := range m.Files {
+		if f.Kind == townbackup.KindCheckpoint {
+			checkpoints++
+		}
+	}
+	fmt.Printf("%s Exported town %s to %s\n", style.Bold.Render("✓"), m.Town, output)
+	summary := fmt.Sprintf("%d files, %d rig(s)", len(m.Files), len(m.Rigs))
+	if townExportCheckpoints {
+		summary += fmt.Sprintf(", %d checkpoint(s)", checkpoints)
+	}
+	fmt.Printf("  %s\n", style.Dim.Render(summary))
+	return nil
+}
+
+func runTownImport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace (run gt install first): %w", err)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	archive, err := townbackup.Read(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	m := archive.Manifest
+
+	fmt.Printf("Export of town %s from %s", style.Bold.Render(m.Town), m.CreatedAt.Local().Format("2006-01-02 15:04"))
+	if m.GTVersion != "" {
+		fmt.Printf(" (gt %s)", m.GTVersion)
+	}
+	fmt.Println()
+
+	if errs := archive.Validate(); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Printf("  %s %v\n", style.Error.Render("✗"), err)
+		}
+		return fmt.Errorf("export has %d invalid file(s)", len(errs))
+	}
+
+	plan := archive.Plan(townRoot)
+	printImportPlan(plan, townImportDryRun)
+
+	if townImportDryRun {
+		return nil
+	}
+	if plan.RigConflicts() {
+		return errors.New("rig directories conflict with the export; move them aside and re-run")
+	}
+	if n := plan.Conflicts(); n > 0 && !townImportForce {
+		return fmt.Errorf("%d conflict(s); re-run with --force to replace them (--dry-run to review)", n)
+	}
+
+	failed := cloneImportedRigs(townRoot, plan)
+	// Don't recreate the configs of rigs that couldn't be cloned; the rig
+	// would look present without a repo
+	for i, fa := range plan.Files {
+		if rigName, _, ok := strings.Cut(fa.Path, "/"); ok && failed[rigName] {
+			plan.Files[i].Op = townbackup.OpSkip
+			plan.Files[i].Reason = "rig " + rigName + " wasn't cloned"
+		}
+	}
+
+	written, err := archive.Restore(townRoot, plan, townImportForce)
+	if err != nil {
+		return fmt.Errorf("restoring files: %w", err)
+	}
+	fmt.Printf("%s Restored %d file(s)\n", style.Bold.Render("✓"), len(written))
+	skipped := 0
+	for _, fa := range plan.Files {
+		if fa.Kind == townbackup.KindCheckpoint && fa.Op == townbackup.OpSkip {
+			skipped++
+		}
+	}
+	if skipped > 0 {
+		style.PrintWarning("%d checkpoint(s) not restored (skipped above); they remain in %s", skipped, args[0])
+	}
+
+	dropped, err := townbackup.RewireRoutes(townRoot, m)
+	if err != nil {
+		style.PrintWarning("could not rewire routes: %v", err)
+	} else {
+		fmt.Printf("%s Rewired routes\n", style.Bold.Render("✓"))
+		for _, r := range dropped {
+			fmt.Printf("  %s dropped route %s → %s (not in this town)\n", style.Dim.Render("○"), r.Prefix, r.Path)
+		}
+	}
+
+	// Load the restored exports into the beads databases
+	imported := make(map[string]bool)
+	for _, fa := range plan.Files {
+		if fa.Kind != townbackup.KindBeads || fa.Op == townbackup.OpSkip {
+			continue
+		}
+		dir := filepath.Join(townRoot, filepath.FromSlash(path.Dir(fa.Path)))
+		if imported[dir] {
+			continue
+		}
+		imported[dir] = true
+		if err := runBdSync(dir, "--import-only"); err != nil {
+			style.PrintWarning("could not import beads into %s: %v", dir, err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d rig(s) could not be cloned; fix and re-run the import", len(failed))
+	}
+	fmt.Printf("\n%s Imported town %s\n", style.Success.Render("✓"), m.Town)
+	return nil
+}
+
+// cloneImportedRigs clones the rigs the plan needs. Returns the rigs that
+// failed.
+func cloneImportedRigs(townRoot string, plan *townbackup.Plan) map[string]bool {
+	failed := make(map[string]bool)
+	var toClone []townbackup.RigAction
+	for _, r := range plan.Rigs {
+		if r.Op == townbackup.OpClone {
+			toClone = append(toClone, r)
+		}
+	}
+	if len(toClone) == 0 {
+		return failed
+	}
+	if err := deps.EnsureBeads(true); err != nil {
+		style.PrintWarning("beads dependency check failed: %v", err)
+	}
+
+	rigsPath := constants.MayorRigsPath(townRoot)
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Version: config.CurrentRigsVersion, Rigs: make(map[string]config.RigEntry)}
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	for _, r := range toClone {
+		// The plan found no directory, so any entry is left from an earlier
+		// failed attempt and would block AddRig
+		delete(rigsConfig.Rigs, r.Name)
+		fmt.Printf("Cloning rig %s from %s...\n", style.Bold.Render(r.Name), r.GitURL)
+		if _, err := mgr.AddRig(rig.AddRigOptions{
+			Name:          r.Name,
+			GitURL:        r.GitURL,
+			BeadsPrefix:   r.Prefix,
+			DefaultBranch: r.DefaultBranch,
+		}); err != nil {
+			fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), r.Name, err)
+			failed[r.Name] = true
+			continue
+		}
+		if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+			style.PrintWarning("could not save rigs config: %v", err)
+		}
+	}
+	return failed
+}
+
+// printImportPlan shows what an import will do. Unchanged files are only
+// counted; in dry-run mode every other file is listed. Conflicts and
+// skipped files are always listed.
+func printImportPlan(plan *townbackup.Plan, verbose bool) {
+	fmt.Printf("\n%s\n", style.Bold.Render("Rigs"))
+	if len(plan.Rigs) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+	}
+	for _, r := range plan.Rigs {
+		line := fmt.Sprintf("  %-9s %s %s", r.Op, r.Name, style.Dim.Render(r.GitURL))
+		if r.Conflict != "" {
+			line += "  " + style.Error.Render("conflict: "+r.Conflict)
+		}
+		fmt.Println(line)
+	}
+
+	counts := make(map[string]int)
+	for _, f := range plan.Files {
+		counts[f.Op]++
+	}
+	fmt.Printf("\n%s %s\n", style.Bold.Render("Files"), style.Dim.Render(fmt.Sprintf("(%d create, %d replace, %d merge, %d unchanged, %d skip)",
+		counts[townbackup.OpCreate], counts[townbackup.OpReplace], counts[townbackup.OpMerge],
+		counts[townbackup.OpUnchanged], counts[townbackup.OpSkip])))
+	for _, f := range plan.Files {
+		switch {
+		case f.Conflict != "":
+			fmt.Printf("  %-9s %s  %s\n", f.Op, f.Path, style.Error.Render("conflict: "+f.Conflict))
+		case f.Reason != "":
+			fmt.Printf("  %-9s %s  %s\n", f.Op, f.Path, style.Dim.Render(f.Reason))
+		case verbose && f.Op != townbackup.OpUnchanged:
+			fmt.Printf("  %-9s %s\n", f.Op, f.Path)
+		}
+	}
+
+	if n := plan.Conflicts(); n > 0 {
+		fmt.Printf("\n%s %d conflict(s)\n", style.Warning.Render("⚠"), n)
+	}
+	fmt.Println()
+}
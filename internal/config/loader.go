@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +30,68 @@ var (
 	ErrMissingField = errors.New("missing required field")
 )
 
+// Config kinds accepted by Validate.
+const (
+	KindTown         = "town"
+	KindRigs         = "rigs"
+	KindRig          = "rig"
+	KindRigSettings  = "rig-settings"
+	KindMayor        = "mayor"
+	KindDaemonPatrol = "daemon-patrol"
+	KindAccounts     = "accounts"
+	KindMessaging    = "messaging"
+	KindEscalation   = "escalation"
+	KindOverseer     = "overseer"
+)
+
+// Validate parses data as a config of the given kind and validates it the
+// way the matching Load function would. Used for configs that aren't on
+// disk yet, such as those in a town export.
+func Validate(kind string, data []byte) error {
+	var (
+		target   interface{}
+		validate func() error
+	)
+	switch kind {
+	case KindTown:
+		c := &TownConfig{}
+		target, validate = c, func() error { return validateTownConfig(c) }
+	case KindRigs:
+		c := &RigsConfig{}
+		target, validate = c, func() error { return validateRigsConfig(c) }
+	case KindRig:
+		c := &RigConfig{}
+		target, validate = c, func() error { return validateRigConfig(c) }
+	case KindRigSettings:
+		c := &RigSettings{}
+		target, validate = c, func() error { return validateRigSettings(c) }
+	case KindMayor:
+		c := &MayorConfig{}
+		target, validate = c, func() error { return validateMayorConfig(c) }
+	case KindDaemonPatrol:
+		c := &DaemonPatrolConfig{}
+		target, validate = c, func() error { return validateDaemonPatrolConfig(c) }
+	case KindAccounts:
+		c := &AccountsConfig{}
+		target, validate = c, func() error { return validateAccountsConfig(c) }
+	case KindMessaging:
+		c := &MessagingConfig{}
+		target, validate = c, func() error { return validateMessagingConfig(c) }
+	case KindEscalation:
+		c := &EscalationConfig{}
+		target, validate = c, func() error { return validateEscalationConfig(c) }
+	case KindOverseer:
+		c := &OverseerConfig{}
+		target, validate = c, func() error { return validateOverseerConfig(c) }
+	default:
+		return fmt.Errorf("unknown config kind %q", kind)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("parsing config: %w", err)
+	}
+	return validate()
+}
+
 // LoadTownConfig loads and validates a town configuration file.
 func LoadTownConfig(path string) (*TownConfig, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted config location
@@ -212,9 +276,75 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.WarmPool != nil && c.WarmPool.Size < 0 {
+		return fmt.Errorf("warm_pool.size must not be negative, got %d", c.WarmPool.Size)
+	}
+	if c.Limits != nil {
+		if err := validateResourceLimits("limits", c.Limits.ResourceLimits); err != nil {
+			return err
+		}
+		for role, l := range c.Limits.Roles {
+			if l == nil {
+				continue
+			}
+			if err := validateResourceLimits("limits.roles."+role, *l); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// validateResourceLimits validates one set of session resource limits.
+// prefix names the settings key in error messages.
+func validateResourceLimits(prefix string, l ResourceLimits) error {
+	if l.CPUWeight < 0 || l.CPUWeight > 10000 {
+		return fmt.Errorf("%s.cpu_weight must be between 1 and 10000, got %d", prefix, l.CPUWeight)
+	}
+	if l.PidsMax < 0 {
+		return fmt.Errorf("%s.pids_max must not be negative, got %d", prefix, l.PidsMax)
+	}
+	if l.MemoryMax != "" {
+		if _, err := ParseByteSize(l.MemoryMax); err != nil {
+			return fmt.Errorf("%s.memory_max: %w", prefix, err)
+		}
+	}
+	if l.WallClock != "" {
+		if d, err := time.ParseDuration(l.WallClock); err != nil || d <= 0 {
+			return fmt.Errorf("%s.wall_clock: invalid duration %q", prefix, l.WallClock)
+		}
+	}
+	return nil
+}
+
+// ParseByteSize parses a size such as "512M", "4G" or "1048576" into bytes.
+// Suffixes are binary (K = 1024) and may be followed by "B" or "iB".
+func ParseByteSize(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	str = strings.TrimSuffix(strings.TrimSuffix(str, "B"), "I")
+	mult := int64(1)
+	if n := len(str); n > 0 {
+		switch str[n-1] {
+		case 'K':
+			mult = 1 << 10
+		case 'M':
+			mult = 1 << 20
+		case 'G':
+			mult = 1 << 30
+		case 'T':
+			mult = 1 << 40
+		}
+		if mult > 1 {
+			str = str[:n-1]
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+	if err != nil || n <= 0 || n > math.MaxInt64/mult {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
@@ -241,6 +371,47 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
 
+	// Validate merge mode and forge settings
+	switch c.Mode {
+	case "", MergeModeLocal, MergeModePullRequest:
+	default:
+		return fmt.Errorf("%w: got '%s', want '%s' or '%s'",
+			ErrInvalidMergeMode, c.Mode, MergeModeLocal, MergeModePullRequest)
+	}
+	if c.Forge != nil {
+		if err := validateForgeConfig(c.Forge); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ErrInvalidMergeMode indicates an invalid merge_queue mode.
+var ErrInvalidMergeMode = errors.New("invalid merge mode")
+
+// validateForgeConfig validates a ForgeConfig.
+func validateForgeConfig(c *ForgeConfig) error {
+	switch c.Type {
+	case "", "github", "gitea", "gitlab":
+	default:
+		return fmt.Errorf("invalid forge type '%s': want 'github', 'gitea' or 'gitlab'", c.Type)
+	}
+	switch c.MergeMethod {
+	case "", "merge", "squash", "rebase":
+	default:
+		return fmt.Errorf("invalid forge merge_method '%s': want 'merge', 'squash' or 'rebase'", c.MergeMethod)
+	}
+	if c.CheckTimeout != "" {
+		if _, err := time.ParseDuration(c.CheckTimeout); err != nil {
+			return fmt.Errorf("invalid forge check_timeout: %w", err)
+		}
+	}
+	if c.CheckGrace != "" {
+		if _, err := time.ParseDuration(c.CheckGrace); err != nil {
+			return fmt.Errorf("invalid forge check_grace: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -1048,6 +1219,80 @@ func ResolveRoleAgentName(role, townRoot, rigPath string) (agentName string, isR
 	return "claude", false
 }
 
+// ResolveTierAgentName returns the agent mapped to a molecule step tier
+// (e.g. "opus"), checking the rig's TierAgents before the town's. Returns ""
+// if the tier is empty or unmapped, meaning the default agent applies.
+func ResolveTierAgentName(tier, townRoot, rigPath string) string {
+	if tier == "" {
+		return ""
+	}
+
+	// Check rig's TierAgents first
+	if rigPath != "" {
+		if rigSettings, err := LoadRigSettings(RigSettingsPath(rigPath)); err == nil && rigSettings.TierAgents != nil {
+			if name, ok := rigSettings.TierAgents[tier]; ok && name != "" {
+				return name
+			}
+		}
+	}
+
+	// Check town's TierAgents
+	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return ""
+	}
+	return townSettings.TierAgents[tier]
+}
+
+// ResolveTierAgent is ResolveTierAgentName with validation: if the mapped
+// agent is not found or its binary doesn't exist, a warning is printed to
+// stderr and "" is returned so the default agent is used.
+func ResolveTierAgent(tier, townRoot, rigPath string) string {
+	agentName, err := validTierAgent(tier, townRoot, rigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: tier_agents[%s]=%s - %v, falling back to default\n", tier, agentName, err)
+		return ""
+	}
+	return agentName
+}
+
+// EffectiveTierAgent returns the agent that actually runs steps of a tier:
+// the validated mapping, or "" for the default agent. Unlike
+// ResolveTierAgent it prints no warning, so it suits comparing tiers.
+func EffectiveTierAgent(tier, townRoot, rigPath string) string {
+	agentName, err := validTierAgent(tier, townRoot, rigPath)
+	if err != nil {
+		return ""
+	}
+	return agentName
+}
+
+// validTierAgent returns the agent mapped to a tier and an error if that
+// agent is not found or its binary doesn't exist.
+func validTierAgent(tier, townRoot, rigPath string) (string, error) {
+	agentName := ResolveTierAgentName(tier, townRoot, rigPath)
+	if agentName == "" {
+		return "", nil
+	}
+
+	var rigSettings *RigSettings
+	if rigPath != "" {
+		rigSettings, _ = LoadRigSettings(RigSettingsPath(rigPath))
+	}
+	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		townSettings = NewTownSettings()
+	}
+
+	// Load custom agent registries
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	if rigPath != "" {
+		_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
+	}
+
+	return agentName, ValidateAgentConfig(agentName, townSettings, rigSettings)
+}
+
 // lookupAgentConfig looks up an agent by name.
 // Checks rig-level custom agents first, then town's custom agents, then built-in presets from agents.go.
 func lookupAgentConfig(name string, townSettings *TownSettings, rigSettings *RigSettings) *RuntimeConfig {
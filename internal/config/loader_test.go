@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -120,6 +121,38 @@ func TestValidationErrors(t *testing.T) {
 	}
 }
 
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		kind    string
+		data    string
+		wantErr error
+	}{
+		{KindTown, `{"type":"town","version":2,"name":"hq"}`, nil},
+		{KindTown, `{"type":"town","version":99,"name":"hq"}`, ErrInvalidVersion},
+		{KindTown, `{"type":"town","version":1}`, ErrMissingField},
+		{KindRig, `{"type":"mayor","name":"gastown"}`, ErrInvalidType},
+		{KindRigs, `{"version":1,"rigs":{}}`, nil},
+		{KindMayor, `{"type":"mayor-config","version":1}`, nil},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.kind, []byte(tt.data))
+		if tt.wantErr == nil && err != nil {
+			t.Errorf("Validate(%s, %s) = %v, want nil", tt.kind, tt.data, err)
+		}
+		if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("Validate(%s, %s) = %v, want %v", tt.kind, tt.data, err, tt.wantErr)
+		}
+	}
+
+	if err := Validate(KindTown, []byte("not json")); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+	if err := Validate("bogus", []byte("{}")); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
+
 func TestRigConfigRoundTrip(t *testing.T) {
 	t.Parallel()
 	dir := t.TempDir()
@@ -334,6 +367,63 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "pull_request mode",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Mode:  MergeModePullRequest,
+					Forge: &ForgeConfig{Type: "gitea", MergeMethod: "squash", CheckTimeout: "20m"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid mode",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Mode: "carrier-pigeon",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid forge type",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Mode:  MergeModePullRequest,
+					Forge: &ForgeConfig{Type: "sourceforge"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid forge check_timeout",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Forge: &ForgeConfig{CheckTimeout: "soon"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid forge check_grace",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Forge: &ForgeConfig{CheckGrace: "later"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2603,3 +2693,152 @@ func TestBuildStartupCommandWithAgentOverride_IncludesGTRoot(t *testing.T) {
 		t.Errorf("expected GT_ROOT=%s in command, got: %q", townRoot, cmd)
 	}
 }
+
+func TestDoctorSuppressionActive(t *testing.T) {
+	s := DoctorSuppression{Check: "daemon", Reason: "maintenance", Expires: "2026-03-10"}
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+
+	if !s.Active(day.Add(23 * time.Hour)) {
+		t.Error("suppression should apply through its expiry day")
+	}
+	if s.Active(day.AddDate(0, 0, 1)) {
+		t.Error("suppression should not apply after its expiry day")
+	}
+
+	bad := DoctorSuppression{Check: "daemon", Expires: "next week"}
+	if _, err := bad.ExpiresAt(); err == nil {
+		t.Error("ExpiresAt should reject a non-date expiry")
+	}
+	if bad.Active(day) {
+		t.Error("invalid suppression should never apply")
+	}
+}
+
+func TestResolveTierAgentName(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	townSettings := NewTownSettings()
+	townSettings.TierAgents = map[string]string{
+		"haiku": "claude-haiku",
+		"opus":  "claude-opus",
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	rigSettings := NewRigSettings()
+	rigSettings.TierAgents = map[string]string{"haiku": "gemini"}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	tests := []struct {
+		tier    string
+		rigPath string
+		want    string
+	}{
+		{"haiku", rigPath, "gemini"},     // rig overrides town
+		{"opus", rigPath, "claude-opus"}, // falls through to town
+		{"haiku", "", "claude-haiku"},    // town-level role
+		{"sonnet", rigPath, ""},          // unmapped tier
+		{"", rigPath, ""},                // step without tier
+	}
+	for _, tt := range tests {
+		if got := ResolveTierAgentName(tt.tier, townRoot, tt.rigPath); got != tt.want {
+			t.Errorf("ResolveTierAgentName(%q, rig=%q) = %q, want %q", tt.tier, tt.rigPath, got, tt.want)
+		}
+	}
+}
+
+func TestResolveTierAgent_FallsBackOnInvalidAgent(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+
+	townSettings := NewTownSettings()
+	townSettings.TierAgents = map[string]string{"opus": "nonexistent-agent-xyz"}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	if got := ResolveTierAgent("opus", townRoot, ""); got != "" {
+		t.Errorf("ResolveTierAgent with invalid agent = %q, want default (empty)", got)
+	}
+
+	// An invalid mapping runs the default agent, same as an unmapped tier,
+	// so moving between them is not an agent switch.
+	if got := EffectiveTierAgent("opus", townRoot, ""); got != EffectiveTierAgent("sonnet", townRoot, "") {
+		t.Errorf("EffectiveTierAgent(opus) = %q, want the default agent like an unmapped tier", got)
+	}
+}
+
+func TestResourceLimitsForRole(t *testing.T) {
+	c := &ResourceLimitsConfig{
+		ResourceLimits: ResourceLimits{CPUWeight: 100, MemoryMax: "4G", WallClock: "2h"},
+		Roles: map[string]*ResourceLimits{
+			"polecat": {MemoryMax: "2G", PidsMax: 256},
+		},
+	}
+
+	got := c.ForRole("polecat")
+	want := ResourceLimits{CPUWeight: 100, MemoryMax: "2G", PidsMax: 256, WallClock: "2h"}
+	if got != want {
+		t.Errorf("ForRole(polecat) = %+v, want %+v", got, want)
+	}
+	if got := c.ForRole("witness"); got != c.ResourceLimits {
+		t.Errorf("ForRole(witness) = %+v, want rig-wide limits", got)
+	}
+	var none *ResourceLimitsConfig
+	if got := none.ForRole("polecat"); got != (ResourceLimits{}) {
+		t.Errorf("nil config ForRole = %+v", got)
+	}
+}
+
+func TestValidateRigSettings_Limits(t *testing.T) {
+	tests := []struct {
+		limits ResourceLimits
+		want   string // error substring, "" for valid
+	}{
+		{ResourceLimits{CPUWeight: 50, MemoryMax: "512M", PidsMax: 100, WallClock: "90m"}, ""},
+		{ResourceLimits{CPUWeight: 20000}, "cpu_weight"},
+		{ResourceLimits{PidsMax: -1}, "pids_max"},
+		{ResourceLimits{MemoryMax: "lots"}, `invalid size "lots"`},
+		{ResourceLimits{WallClock: "forever"}, "wall_clock"},
+	}
+	for _, tt := range tests {
+		settings := &RigSettings{Limits: &ResourceLimitsConfig{Roles: map[string]*ResourceLimits{"crew": &tt.limits}}}
+		err := validateRigSettings(settings)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error %v", tt.limits, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), "limits.roles.crew") {
+			t.Errorf("%+v: error = %v, want containing %q", tt.limits, err, tt.want)
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{
+		"1048576": 1 << 20,
+		"512K":    512 << 10,
+		"512M":    512 << 20,
+		"4G":      4 << 30,
+		"4GB":     4 << 30,
+		"4GiB":    4 << 30,
+		"1t":      1 << 40,
+	}
+	for in, want := range tests {
+		if got, err := ParseByteSize(in); err != nil || got != want {
+			t.Errorf("ParseByteSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "G", "-1G", "1.5G", "4X"} {
+		if _, err := ParseByteSize(in); err == nil {
+			t.Errorf("ParseByteSize(%q) should fail", in)
+		}
+	}
+}
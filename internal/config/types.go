@@ -2,6 +2,7 @@
 package config
 
 import (
+	"fmt"
 	"path/filepath"
 	"os"
 	"strings"
@@ -57,10 +58,51 @@ type TownSettings struct {
 	// Example: {"mayor": "claude-opus", "witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
 
+	// TierAgents maps molecule step tiers to agent aliases.
+	// Keys are the step "Tier:" hints: "haiku", "sonnet", "opus".
+	// When a molecule advances to a step with a mapped tier, the worker's
+	// session is respawned on that agent. Unmapped tiers use the default agent.
+	// Example: {"haiku": "claude-haiku", "opus": "claude-opus"}
+	TierAgents map[string]string `json:"tier_agents,omitempty"`
+
 	// AgentEmailDomain is the domain used for agent git identity emails.
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// DoctorSuppressions silence known, accepted gt doctor failures until
+	// they expire. Suppressed checks still run and are reported, but don't
+	// count as warnings or errors.
+	DoctorSuppressions []DoctorSuppression `json:"doctor_suppressions,omitempty"`
+}
+
+// DoctorSuppression accepts a failing gt doctor check for a limited time.
+type DoctorSuppression struct {
+	// Check is the doctor check name (e.g. "orphan-sessions").
+	Check string `json:"check"`
+
+	// Reason explains why the failure is accepted.
+	Reason string `json:"reason"`
+
+	// Expires is the last day (YYYY-MM-DD) the suppression applies.
+	Expires string `json:"expires"`
+}
+
+// ExpiresAt returns when the suppression stops applying: the end of its
+// Expires day in local time. Returns an error if Expires isn't a date.
+func (s DoctorSuppression) ExpiresAt() (time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", s.Expires, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry %q for %s: want YYYY-MM-DD", s.Expires, s.Check)
+	}
+	return day.AddDate(0, 0, 1), nil
+}
+
+// Active reports whether the suppression applies at now. Suppressions with
+// an invalid expiry never apply.
+func (s DoctorSuppression) Active(now time.Time) bool {
+	expires, err := s.ExpiresAt()
+	return err == nil && now.Before(expires)
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -202,14 +244,16 @@ type WorkflowConfig struct {
 
 // RigSettings represents per-rig behavioral configuration (settings/config.json).
 type RigSettings struct {
-	Type       string            `json:"type"`                  // "rig-settings"
-	Version    int               `json:"version"`               // schema version
-	MergeQueue *MergeQueueConfig `json:"merge_queue,omitempty"` // merge queue settings
-	Theme      *ThemeConfig      `json:"theme,omitempty"`       // tmux theme settings
-	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
-	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
-	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
-	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
+	Type       string                `json:"type"`                  // "rig-settings"
+	Version    int                   `json:"version"`               // schema version
+	MergeQueue *MergeQueueConfig     `json:"merge_queue,omitempty"` // merge queue settings
+	Theme      *ThemeConfig          `json:"theme,omitempty"`       // tmux theme settings
+	Namepool   *NamepoolConfig       `json:"namepool,omitempty"`    // polecat name pool settings
+	WarmPool   *WarmPoolConfig       `json:"warm_pool,omitempty"`   // pre-provisioned polecat worktrees
+	Limits     *ResourceLimitsConfig `json:"limits,omitempty"`      // session resource limits
+	Crew       *CrewConfig           `json:"crew,omitempty"`        // crew startup settings
+	Workflow   *WorkflowConfig       `json:"workflow,omitempty"`    // workflow settings
+	Runtime    *RuntimeConfig        `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
 	// Agent selects which agent preset to use for this rig.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp")
@@ -229,6 +273,11 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// TierAgents maps molecule step tiers to agent aliases.
+	// Overrides TownSettings.TierAgents for this specific rig.
+	// Example: {"haiku": "claude-haiku"}
+	TierAgents map[string]string `json:"tier_agents,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -661,6 +710,14 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of concurrent merges.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// Mode is how the refinery lands branches: "local" (default) merges and
+	// pushes the target itself, "pull_request" goes through the forge.
+	Mode string `json:"mode,omitempty"`
+
+	// Forge configures the forge for "pull_request" mode. Fields left empty
+	// are derived from the origin remote.
+	Forge *ForgeConfig `json:"forge,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -669,6 +726,40 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// Merge mode constants.
+const (
+	MergeModeLocal       = "local"
+	MergeModePullRequest = "pull_request"
+)
+
+// ForgeConfig configures the code forge used in pull_request mode.
+type ForgeConfig struct {
+	// Type is "github", "gitea" or "gitlab". Detected from the origin host
+	// for github.com, gitlab.com and codeberg.org.
+	Type string `json:"type,omitempty"`
+
+	// APIURL is the REST API base URL (e.g., "https://git.example.com/api/v1").
+	APIURL string `json:"api_url,omitempty"`
+
+	// Repo is the repository path on the forge (e.g., "owner/name").
+	Repo string `json:"repo,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token.
+	// Default: GITHUB_TOKEN/GH_TOKEN, GITEA_TOKEN or GITLAB_TOKEN.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// MergeMethod is "merge" (default), "squash" or "rebase".
+	MergeMethod string `json:"merge_method,omitempty"`
+
+	// CheckTimeout bounds how long an MR stays parked on pending checks
+	// (e.g., "30m").
+	CheckTimeout string `json:"check_timeout,omitempty"`
+
+	// CheckGrace is how long a pull request with no checks reported waits
+	// for CI to register before merging without checks (e.g., "5m").
+	CheckGrace string `json:"check_grace,omitempty"`
+}
+
 // DefaultMergeQueueConfig returns a MergeQueueConfig with sensible defaults.
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
@@ -708,6 +799,66 @@ func DefaultNamepoolConfig() *NamepoolConfig {
 	}
 }
 
+// WarmPoolConfig configures a rig's pool of idle, pre-provisioned polecat
+// worktrees. gt sling claims a warm worktree instead of creating one and
+// running setup hooks, and the daemon refills the pool and keeps it fresh
+// against the default branch.
+type WarmPoolConfig struct {
+	// Size is the number of warm worktrees to keep. 0 disables the pool.
+	Size int `json:"size"`
+}
+
+// ResourceLimitsConfig sets resource limits for the agent sessions of a rig.
+// The top-level limits apply to every role; Roles overrides them field by
+// field for a role ("polecat", "crew", "witness", "refinery").
+type ResourceLimitsConfig struct {
+	ResourceLimits
+	Roles map[string]*ResourceLimits `json:"roles,omitempty"`
+}
+
+// ResourceLimits holds the limits for one session. Zero values mean no limit.
+type ResourceLimits struct {
+	// CPUWeight is the relative CPU share, as cgroup cpu.weight (1-10000,
+	// default 100). Without cgroups it is mapped to a nice value.
+	CPUWeight int `json:"cpu_weight,omitempty"`
+
+	// MemoryMax is the memory ceiling, e.g. "4G" or "512M".
+	MemoryMax string `json:"memory_max,omitempty"`
+
+	// PidsMax caps the number of processes in the session.
+	PidsMax int `json:"pids_max,omitempty"`
+
+	// WallClock is how long a session may run before it is reported to the
+	// witness, e.g. "2h". It is not enforced.
+	WallClock string `json:"wall_clock,omitempty"`
+}
+
+// ForRole returns the limits for a role: the rig-wide limits with any
+// fields set for the role taking precedence.
+func (c *ResourceLimitsConfig) ForRole(role string) ResourceLimits {
+	if c == nil {
+		return ResourceLimits{}
+	}
+	limits := c.ResourceLimits
+	override := c.Roles[role]
+	if override == nil {
+		return limits
+	}
+	if override.CPUWeight != 0 {
+		limits.CPUWeight = override.CPUWeight
+	}
+	if override.MemoryMax != "" {
+		limits.MemoryMax = override.MemoryMax
+	}
+	if override.PidsMax != 0 {
+		limits.PidsMax = override.PidsMax
+	}
+	if override.WallClock != "" {
+		limits.WallClock = override.WallClock
+	}
+	return limits
+}
+
 // AccountsConfig represents Claude Code account configuration (mayor/accounts.json).
 // This enables Gas Town to manage multiple Claude Code accounts with easy switching.
 type AccountsConfig struct {
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/limits"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -520,6 +521,11 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
+	// Apply the rig's resource limits (non-fatal: the session runs unlimited)
+	if _, err := limits.ApplyToSession(t, sessionID, m.rig.Path, "crew/"+name, "crew"); err != nil {
+		fmt.Printf("Warning: could not apply resource limits: %v\n", err)
+	}
+
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	townRoot := filepath.Dir(m.rig.Path)
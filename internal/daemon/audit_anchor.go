@@ -0,0 +1,28 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/auditchain"
+)
+
+// auditAnchorInterval is how often the audit chain heads are anchored in
+// the town git repo.
+const auditAnchorInterval = time.Hour
+
+// anchorAuditChains runs gt audit anchor when the last anchor is older than
+// auditAnchorInterval. gt audit anchor skips chains that haven't moved, so an
+// idle town doesn't accumulate commits.
+func (d *Daemon) anchorAuditChains() {
+	if info, err := os.Stat(auditchain.AnchorsPath(d.config.TownRoot)); err == nil && time.Since(info.ModTime()) < auditAnchorInterval {
+		return
+	}
+	cmd := exec.Command("gt", "audit", "anchor")
+	cmd.Dir = d.config.TownRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Audit anchor failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+}
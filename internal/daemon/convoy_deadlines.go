@@ -0,0 +1,17 @@
+package daemon
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// checkConvoyDeadlines forecasts convoys with a deadline and mails the
+// owners of any that have slipped past it. gt convoy slipping --notify
+// records what it has sent, so each deadline is alerted on once.
+func (d *Daemon) checkConvoyDeadlines() {
+	cmd := exec.Command("gt", "convoy", "slipping", "--notify")
+	cmd.Dir = d.config.TownRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Convoy deadline check failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+}
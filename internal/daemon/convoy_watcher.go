@@ -15,7 +15,9 @@ import (
 
 // ConvoyWatcher monitors bd activity for issue closes and triggers convoy completion checks.
 // When an issue closes, it checks if the issue is tracked by any convoy and runs the
-// completion check if all tracked issues are now closed.
+// completion check if all tracked issues are now closed (which closes the convoy,
+// publishes its release notes and notifies subscribers). When a convoy that other
+// convoys run after closes, it dispatches the work queued behind it.
 type ConvoyWatcher struct {
 	townRoot string
 	ctx      context.Context
@@ -136,6 +138,11 @@ func (w *ConvoyWatcher) processLine(line string) {
 
 	w.logger("convoy watcher: detected close of %s", event.IssueID)
 
+	// A landed convoy may release work queued behind it (gt convoy after)
+	if w.hasSuccessors(event.IssueID) {
+		w.releaseQueued(event.IssueID)
+	}
+
 	// Check if this issue is tracked by any convoy
 	convoyIDs := w.getTrackingConvoys(event.IssueID)
 	if len(convoyIDs) == 0 {
@@ -237,3 +244,52 @@ func (w *ConvoyWatcher) checkConvoyCompletion(convoyID string) {
 		w.logger("convoy watcher: %s", strings.TrimSpace(output))
 	}
 }
+
+// hasSuccessors reports whether any convoy runs after the given issue.
+// Convoy ordering is a blocks dependency between two convoys.
+func (w *ConvoyWatcher) hasSuccessors(issueID string) bool {
+	dbPath := filepath.Join(w.townRoot, ".beads", "beads.db")
+	query := fmt.Sprintf(`
+		SELECT COUNT(*) AS n FROM dependencies d
+		JOIN issues i ON i.id = d.issue_id
+		JOIN issues p ON p.id = d.depends_on_id
+		WHERE d.depends_on_id = '%s' AND d.type = 'blocks'
+		AND i.issue_type = 'convoy' AND p.issue_type = 'convoy'
+	`, strings.ReplaceAll(issueID, "'", "''"))
+
+	queryCmd := exec.Command("sqlite3", "-json", dbPath, query)
+	var stdout bytes.Buffer
+	queryCmd.Stdout = &stdout
+	if err := queryCmd.Run(); err != nil {
+		return false
+	}
+
+	var rows []struct {
+		N int `json:"n"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &rows); err != nil || len(rows) == 0 {
+		return false
+	}
+	return rows[0].N > 0
+}
+
+// releaseQueued runs gt convoy release to dispatch work whose predecessor
+// convoys have all landed.
+func (w *ConvoyWatcher) releaseQueued(convoyID string) {
+	w.logger("convoy watcher: %s landed, releasing queued work", convoyID)
+
+	releaseCmd := exec.Command("gt", "convoy", "release")
+	releaseCmd.Dir = w.townRoot
+	var stdout, stderr bytes.Buffer
+	releaseCmd.Stdout = &stdout
+	releaseCmd.Stderr = &stderr
+
+	if err := releaseCmd.Run(); err != nil {
+		w.logger("convoy watcher: gt convoy release failed: %v: %s", err, stderr.String())
+		return
+	}
+
+	if output := stdout.String(); output != "" && !strings.Contains(output, "No queued work") {
+		w.logger("convoy watcher: %s", strings.TrimSpace(output))
+	}
+}
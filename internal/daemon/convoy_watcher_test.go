@@ -2,6 +2,9 @@ package daemon
 
 import (
 	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 )
 
@@ -87,3 +90,32 @@ func TestIsCloseEvent(t *testing.T) {
 		t.Error("should not detect create as close")
 	}
 }
+
+func TestHasSuccessors(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	townRoot := t.TempDir()
+	townBeads := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(townBeads, 0755); err != nil {
+		t.Fatal(err)
+	}
+	setup := `
+		CREATE TABLE issues (id TEXT PRIMARY KEY, issue_type TEXT);
+		CREATE TABLE dependencies (issue_id TEXT, depends_on_id TEXT, type TEXT);
+		INSERT INTO issues VALUES ('hq-cv-back', 'convoy'), ('hq-cv-front', 'convoy'), ('hq-task', 'task');
+		INSERT INTO dependencies VALUES ('hq-cv-front', 'hq-cv-back', 'blocks');
+		INSERT INTO dependencies VALUES ('hq-task', 'hq-cv-front', 'blocks');
+	`
+	if out, err := exec.Command("sqlite3", filepath.Join(townBeads, "beads.db"), setup).CombinedOutput(); err != nil {
+		t.Fatalf("creating beads.db: %v\n%s", err, out)
+	}
+
+	w := NewConvoyWatcher(townRoot, t.Logf)
+	if !w.hasSuccessors("hq-cv-back") {
+		t.Error("hq-cv-back has a convoy running after it")
+	}
+	if w.hasSuccessors("hq-cv-front") {
+		t.Error("a task blocked on a convoy is not convoy ordering")
+	}
+}
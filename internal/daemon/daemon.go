@@ -274,6 +274,18 @@ func (d *Daemon) heartbeat(state *State) {
 	// This is a safety net - Deacon patrol also does this more frequently.
 	d.cleanupOrphanedProcesses()
 
+	// 13. Keep polecat warm pools fresh and full (rigs with warm_pool.size)
+	d.maintainWarmPools()
+
+	// 14. Report session resource limit breaches to witnesses
+	d.checkResourceLimits()
+
+	// 15. Alert convoy owners when a forecast slips past the convoy's deadline
+	d.checkConvoyDeadlines()
+
+	// 16. Anchor the audit log hash chains in the town git repo (hourly)
+	d.anchorAuditChains()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"os/exec"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/limits"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// checkResourceLimits enforces the resource limits of each rig's sessions
+// and mails the rig's witness a LIMIT_EXCEEDED message for every session
+// that has newly hit one of them. Each breach is reported once per session;
+// later enforcement is only logged.
+func (d *Daemon) checkResourceLimits() {
+	for _, rigName := range d.getKnownRigs() {
+		breaches, err := limits.CheckBreaches(filepath.Join(d.config.TownRoot, rigName))
+		if err != nil {
+			d.logger.Printf("Resource limit check for %s failed: %v", rigName, err)
+		}
+		for _, b := range breaches {
+			d.logger.Printf("%s/%s exceeded its %s limit (%s, max %s)", rigName, b.Agent, b.Kind, b.Usage, b.Max)
+			if b.Enforced != "" {
+				d.logger.Printf("%s/%s: %s", rigName, b.Agent, b.Enforced)
+			}
+			if b.Repeat {
+				continue
+			}
+			subject, body := witness.FormatLimitExceeded(&witness.LimitExceededPayload{
+				Agent:     b.Agent,
+				Role:      b.Role,
+				Limit:     b.Kind,
+				Usage:     b.Usage,
+				Max:       b.Max,
+				Mechanism: string(b.Mechanism),
+			})
+			cmd := exec.Command("gt", "mail", "send", rigName+"/witness", "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+			cmd.Dir = d.config.TownRoot
+			if err := cmd.Run(); err != nil {
+				d.logger.Printf("Warning: failed to report limit breach to witness: %v", err)
+			}
+		}
+	}
+}
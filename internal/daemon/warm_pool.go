@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// maintainWarmPools keeps each operational rig's polecat warm pool fresh
+// and full. Refreshing and refilling both provision worktrees and run setup
+// hooks, which can take minutes, so they run in the background rather than
+// in the heartbeat. The polecat manager's refill lock keeps them from
+// overlapping with each other, with the next heartbeat's round or with the
+// refill gt sling starts after claiming.
+func (d *Daemon) maintainWarmPools() {
+	for _, rigName := range d.getKnownRigs() {
+		r := &rig.Rig{
+			Name: rigName,
+			Path: filepath.Join(d.config.TownRoot, rigName),
+		}
+		mgr := polecat.NewManager(r, git.NewGit(r.Path), nil)
+		if mgr.WarmPoolSize() == 0 {
+			// A disabled pool may still hold slots to drain
+			if slots, _ := mgr.ListWarm(); len(slots) == 0 {
+				continue
+			}
+		}
+		if operational, reason := d.isRigOperational(rigName); !operational {
+			d.logger.Printf("Skipping warm pool for %s: %s", rigName, reason)
+			continue
+		}
+
+		go func() {
+			if refreshed, err := mgr.RefreshWarmPool(); err != nil {
+				d.logger.Printf("Warm pool refresh for %s failed: %v", rigName, err)
+			} else if refreshed > 0 {
+				d.logger.Printf("Replaced %d stale warm worktree(s) in %s", refreshed, rigName)
+			}
+			added, err := mgr.RefillWarmPool()
+			if added > 0 {
+				d.logger.Printf("Provisioned %d warm worktree(s) for %s", added, rigName)
+			}
+			if err != nil {
+				d.logger.Printf("Warm pool refill for %s failed: %v", rigName, err)
+			}
+		}()
+	}
+}
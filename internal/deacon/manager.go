@@ -10,6 +10,7 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/ptyd"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -23,12 +24,15 @@ var (
 // Manager handles deacon lifecycle operations.
 type Manager struct {
 	townRoot string
+	backend  tmux.SessionBackend
 }
 
 // NewManager creates a new deacon manager for a town.
+// Sessions run on the backend selected by GT_SESSION_BACKEND (tmux by default).
 func NewManager(townRoot string) *Manager {
 	return &Manager{
 		townRoot: townRoot,
+		backend:  ptyd.NewBackend(),
 	}
 }
 
@@ -52,14 +56,14 @@ func (m *Manager) deaconDir() string {
 // agentOverride allows specifying an alternate agent alias (e.g., for testing).
 // Restarts are handled by daemon via ensureDeaconRunning on each heartbeat.
 func (m *Manager) Start(agentOverride string) error {
-	t := tmux.NewTmux()
+	t := m.backend
 	sessionID := m.SessionName()
 
 	// Check if session already exists
 	running, _ := t.HasSession(sessionID)
 	if running {
 		// Session exists - check if Claude is actually running (healthy vs zombie)
-		if t.IsClaudeRunning(sessionID) {
+		if tmux.IsAgentAlive(t, sessionID) {
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but Claude dead. Kill and recreate.
@@ -102,9 +106,11 @@ func (m *Manager) Start(agentOverride string) error {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
 
-	// Apply Deacon theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.DeaconTheme()
-	_ = t.ConfigureGasTownSession(sessionID, theme, "", "Deacon", "health-check")
+	if tm, ok := tmux.AsTmux(t); ok {
+		// Apply Deacon theming (non-fatal: theming failure doesn't affect operation)
+		theme := tmux.DeaconTheme()
+		_ = tm.ConfigureGasTownSession(sessionID, theme, "", "Deacon", "health-check")
+	}
 
 	// Wait for Claude to start (non-fatal)
 	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
@@ -134,7 +140,7 @@ func (m *Manager) Start(agentOverride string) error {
 
 // Stop stops the deacon session.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := m.backend
 	sessionID := m.SessionName()
 
 	// Check if session exists
@@ -160,16 +166,15 @@ func (m *Manager) Stop() error {
 
 // IsRunning checks if the deacon session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	return t.HasSession(m.SessionName())
+	return m.backend.HasSession(m.SessionName())
 }
 
 // Status returns information about the deacon session.
+// Non-tmux backends report only the session name.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
 
-	running, err := t.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -177,5 +182,8 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if tm, ok := tmux.AsTmux(m.backend); ok {
+		return tm.GetSessionInfo(sessionID)
+	}
+	return &tmux.SessionInfo{Name: sessionID}, nil
 }
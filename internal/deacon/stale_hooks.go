@@ -9,7 +9,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/ptyd"
 )
 
 // StaleHookConfig holds configurable parameters for stale hook detection.
@@ -78,7 +78,7 @@ func ScanStaleHooks(townRoot string, cfg *StaleHookConfig) (*StaleHookScanResult
 
 	// Filter to stale ones (older than threshold)
 	threshold := time.Now().Add(-cfg.MaxAge)
-	t := tmux.NewTmux()
+	t := ptyd.NewBackend()
 
 	for _, bead := range hookedBeads {
 		// Skip if updated recently (not stale)
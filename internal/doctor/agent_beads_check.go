@@ -19,6 +19,7 @@ import (
 // Each rig uses its configured prefix (e.g., "gt-" for gastown, "bd-" for beads).
 type AgentBeadsCheck struct {
 	FixableCheck
+	missing []string // Cached during Run for PlanFix
 }
 
 // NewAgentBeadsCheck creates a new agent beads check.
@@ -92,6 +93,7 @@ func (c *AgentBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 
 	if len(prefixToRig) == 0 {
 		// No rigs to check, but we still checked global agents
+		c.missing = missing
 		if len(missing) == 0 {
 			return &CheckResult{
 				Name:    c.Name(),
@@ -140,6 +142,7 @@ func (c *AgentBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
+	c.missing = missing
 	if len(missing) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -275,6 +278,15 @@ func (c *AgentBeadsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the agent beads Fix would create.
+func (c *AgentBeadsCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, id := range c.missing {
+		changes = append(changes, fmt.Sprintf("Create agent bead %s", id))
+	}
+	return changes
+}
+
 // listCrewWorkers returns the names of all crew workers in a rig.
 func listCrewWorkers(townRoot, rigName string) []string {
 	crewDir := filepath.Join(townRoot, rigName, "crew")
@@ -0,0 +1,220 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+// FlapThreshold is the number of status changes within the recent history
+// at which a check is reported as flapping.
+const FlapThreshold = 3
+
+// FlapWindow is the number of most recent runs examined for flapping.
+const FlapWindow = 10
+
+// Baseline is a saved snapshot of check statuses. Later runs are compared
+// against it so accepted warnings don't hide new regressions.
+type Baseline struct {
+	SavedAt time.Time              `json:"saved_at"`
+	Checks  map[string]CheckStatus `json:"checks"`
+
+	// Partial is set when the baseline was saved from a run limited by
+	// --only or --skip. Checks it doesn't list weren't run, so they are
+	// not reported as new.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// BaselinePath returns the path to the town's doctor baseline.
+func BaselinePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "doctor", "baseline.json")
+}
+
+// NewBaseline snapshots the statuses in a report. Suppressed checks are
+// recorded with their real status.
+func NewBaseline(report *Report) *Baseline {
+	b := &Baseline{
+		SavedAt: report.Timestamp,
+		Checks:  make(map[string]CheckStatus, len(report.Checks)),
+	}
+	for _, check := range report.Checks {
+		b.Checks[check.Name] = check.Status
+	}
+	return b
+}
+
+// LoadBaseline reads the town's baseline. Returns nil, nil if none was saved.
+func LoadBaseline(townRoot string) (*Baseline, error) {
+	data, err := os.ReadFile(BaselinePath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing doctor baseline: %w", err)
+	}
+	return &b, nil
+}
+
+// SaveBaseline writes the town's baseline.
+func SaveBaseline(townRoot string, b *Baseline) error {
+	path := BaselinePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Drift describes how a report differs from the baseline.
+type Drift struct {
+	BaselineAt time.Time `json:"baseline_at"`
+
+	// New lists checks that got worse than the baseline, including
+	// failing checks a full baseline doesn't know about.
+	New []*CheckResult `json:"new"`
+
+	// Resolved lists checks that failed in the baseline and pass now.
+	Resolved []string `json:"resolved"`
+
+	// Flapping lists checks whose status changed at least FlapThreshold
+	// times in the last FlapWindow runs.
+	Flapping []string `json:"flapping"`
+}
+
+// HasChanges reports whether anything drifted.
+func (d *Drift) HasChanges() bool {
+	return len(d.New) > 0 || len(d.Resolved) > 0 || len(d.Flapping) > 0
+}
+
+// Compare reports the drift of a report from the baseline. Suppressed
+// checks are never reported as new. history is the town's recent runs,
+// oldest first, used to detect flapping.
+func (b *Baseline) Compare(report *Report, history []state.DoctorRun) *Drift {
+	drift := &Drift{
+		BaselineAt: b.SavedAt,
+		New:        []*CheckResult{},
+		Resolved:   []string{},
+		Flapping:   FlappingChecks(history),
+	}
+	for _, check := range report.Checks {
+		before, known := b.Checks[check.Name]
+		switch {
+		case check.Suppressed != "":
+		case check.Status == StatusOK:
+			if known && before != StatusOK {
+				drift.Resolved = append(drift.Resolved, check.Name)
+			}
+		case !known && b.Partial:
+		case !known || check.Status > before:
+			drift.New = append(drift.New, check)
+		}
+	}
+	return drift
+}
+
+// FlappingChecks returns the checks whose status changed at least
+// FlapThreshold times over the last FlapWindow runs, sorted by name.
+func FlappingChecks(history []state.DoctorRun) []string {
+	if len(history) > FlapWindow {
+		history = history[len(history)-FlapWindow:]
+	}
+	changes := make(map[string]int)
+	last := make(map[string]string)
+	for _, run := range history {
+		for name, status := range run.Checks {
+			if prev, ok := last[name]; ok && prev != status {
+				changes[name]++
+			}
+			last[name] = status
+		}
+	}
+	flapping := []string{}
+	for name, n := range changes {
+		if n >= FlapThreshold {
+			flapping = append(flapping, name)
+		}
+	}
+	sort.Strings(flapping)
+	return flapping
+}
+
+// RecordRun appends the report to the run history for townRoot.
+func RecordRun(townRoot string, report *Report) error {
+	run := state.DoctorRun{
+		At:     report.Timestamp,
+		Town:   townRoot,
+		Checks: make(map[string]string, len(report.Checks)),
+	}
+	for _, check := range report.Checks {
+		text, err := check.Status.MarshalText()
+		if err != nil {
+			return err
+		}
+		run.Checks[check.Name] = string(text)
+	}
+	return state.RecordDoctorRun(run)
+}
+
+// ApplySuppressions marks failing checks covered by an active suppression
+// and removes them from the warning and error counts. It returns the
+// suppressions that have expired, so callers can prompt for cleanup.
+func (r *Report) ApplySuppressions(suppressions []config.DoctorSuppression, now time.Time) []config.DoctorSuppression {
+	var expired []config.DoctorSuppression
+	active := make(map[string]config.DoctorSuppression)
+	for _, s := range suppressions {
+		if s.Active(now) {
+			active[s.Check] = s
+		} else {
+			expired = append(expired, s)
+		}
+	}
+
+	for _, check := range r.Checks {
+		s, ok := active[check.Name]
+		if !ok || check.Status == StatusOK || check.Suppressed != "" {
+			continue
+		}
+		check.Suppressed = fmt.Sprintf("%s (until %s)", s.Reason, s.Expires)
+		switch check.Status {
+		case StatusWarning:
+			r.Summary.Warnings--
+		case StatusError:
+			r.Summary.Errors--
+		}
+		r.Summary.Suppressed++
+	}
+	return expired
+}
+
+// Print outputs the drift to the given writer.
+func (d *Drift) Print(w io.Writer) {
+	_, _ = fmt.Fprintln(w, ui.RenderCategory("Changes since baseline ("+d.BaselineAt.Format("2006-01-02 15:04")+")"))
+	if !d.HasChanges() {
+		_, _ = fmt.Fprintf(w, "  %s  %s\n", ui.RenderPassIcon(), ui.RenderMuted("no changes"))
+		return
+	}
+	for _, check := range d.New {
+		_, _ = fmt.Fprintf(w, "  %s  %s %s%s\n", statusIcon(check.Status), ui.RenderBold("new"), check.Name, ui.RenderMuted(" "+check.Message))
+	}
+	for _, name := range d.Resolved {
+		_, _ = fmt.Fprintf(w, "  %s  %s %s\n", ui.RenderPassIcon(), ui.RenderBold("resolved"), name)
+	}
+	for _, name := range d.Flapping {
+		_, _ = fmt.Fprintf(w, "  %s  %s %s\n", ui.RenderWarnIcon(), ui.RenderBold("flapping"), name)
+	}
+}
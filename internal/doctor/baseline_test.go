@@ -0,0 +1,129 @@
+package doctor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/state"
+)
+
+func reportWith(statuses map[string]CheckStatus, order ...string) *Report {
+	r := NewReport()
+	for _, name := range order {
+		r.Add(&CheckResult{Name: name, Status: statuses[name], Message: "mock result"})
+	}
+	return r
+}
+
+func TestBaseline_Compare(t *testing.T) {
+	baseline := NewBaseline(reportWith(map[string]CheckStatus{
+		"accepted": StatusWarning,
+		"fixed":    StatusError,
+		"worse":    StatusWarning,
+		"healthy":  StatusOK,
+	}, "accepted", "fixed", "worse", "healthy"))
+
+	report := reportWith(map[string]CheckStatus{
+		"accepted": StatusWarning,
+		"fixed":    StatusOK,
+		"worse":    StatusError,
+		"healthy":  StatusOK,
+		"brand":    StatusWarning,
+	}, "accepted", "fixed", "worse", "healthy", "brand")
+
+	drift := baseline.Compare(report, nil)
+	var newNames []string
+	for _, c := range drift.New {
+		newNames = append(newNames, c.Name)
+	}
+	if !reflect.DeepEqual(newNames, []string{"worse", "brand"}) {
+		t.Errorf("New = %v, want [worse brand]", newNames)
+	}
+	if !reflect.DeepEqual(drift.Resolved, []string{"fixed"}) {
+		t.Errorf("Resolved = %v, want [fixed]", drift.Resolved)
+	}
+}
+
+func TestBaseline_SaveLoad(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if b, err := LoadBaseline(townRoot); err != nil || b != nil {
+		t.Fatalf("LoadBaseline with no file = %v, %v", b, err)
+	}
+
+	saved := NewBaseline(reportWith(map[string]CheckStatus{"daemon": StatusError}, "daemon"))
+	if err := SaveBaseline(townRoot, saved); err != nil {
+		t.Fatalf("SaveBaseline: %v", err)
+	}
+	loaded, err := LoadBaseline(townRoot)
+	if err != nil {
+		t.Fatalf("LoadBaseline: %v", err)
+	}
+	if loaded.Checks["daemon"] != StatusError {
+		t.Errorf("loaded baseline = %+v", loaded)
+	}
+}
+
+func TestFlappingChecks(t *testing.T) {
+	var history []state.DoctorRun
+	for i, s := range []string{"ok", "error", "ok", "error", "ok"} {
+		history = append(history, state.DoctorRun{
+			At:     time.Unix(int64(i), 0),
+			Checks: map[string]string{"flappy": s, "steady": "warning"},
+		})
+	}
+	if got := FlappingChecks(history); !reflect.DeepEqual(got, []string{"flappy"}) {
+		t.Errorf("FlappingChecks = %v, want [flappy]", got)
+	}
+	if got := FlappingChecks(history[:3]); len(got) != 0 {
+		t.Errorf("FlappingChecks with 2 changes = %v, want none", got)
+	}
+}
+
+func TestReport_ApplySuppressions(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	report := reportWith(map[string]CheckStatus{
+		"daemon": StatusError,
+		"theme":  StatusWarning,
+		"clean":  StatusOK,
+	}, "daemon", "theme", "clean")
+
+	expired := report.ApplySuppressions([]config.DoctorSuppression{
+		{Check: "daemon", Reason: "maintenance", Expires: "2026-03-11"},
+		{Check: "theme", Reason: "old", Expires: "2026-03-01"},
+		{Check: "clean", Reason: "noop", Expires: "2026-03-11"},
+	}, now)
+
+	if len(expired) != 1 || expired[0].Check != "theme" {
+		t.Errorf("expired = %+v, want theme", expired)
+	}
+	if report.Checks[0].Suppressed == "" || report.Checks[1].Suppressed != "" || report.Checks[2].Suppressed != "" {
+		t.Errorf("suppressed flags wrong: %q %q %q", report.Checks[0].Suppressed, report.Checks[1].Suppressed, report.Checks[2].Suppressed)
+	}
+	if report.HasErrors() || report.Summary.Warnings != 1 || report.Summary.Suppressed != 1 {
+		t.Errorf("summary = %+v", report.Summary)
+	}
+
+	// Suppressed failures are not new drift.
+	baseline := NewBaseline(reportWith(map[string]CheckStatus{"daemon": StatusOK}, "daemon"))
+	if drift := baseline.Compare(report, nil); len(drift.New) != 1 || drift.New[0].Name != "theme" {
+		t.Errorf("New = %+v, want only theme", drift.New)
+	}
+}
+
+func TestBaseline_ComparePartial(t *testing.T) {
+	baseline := NewBaseline(reportWith(map[string]CheckStatus{"daemon": StatusWarning}, "daemon"))
+	baseline.Partial = true
+
+	report := reportWith(map[string]CheckStatus{
+		"daemon":  StatusError,
+		"unknown": StatusWarning,
+	}, "daemon", "unknown")
+
+	drift := baseline.Compare(report, nil)
+	if len(drift.New) != 1 || drift.New[0].Name != "daemon" {
+		t.Errorf("New = %+v, want only daemon", drift.New)
+	}
+}
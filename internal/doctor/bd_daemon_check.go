@@ -211,3 +211,11 @@ func (c *BdDaemonCheck) Fix(ctx *CheckContext) error {
 	startCmd.Dir = ctx.TownRoot
 	return startCmd.Run()
 }
+
+// PlanFix describes starting the bd daemon.
+func (c *BdDaemonCheck) PlanFix(ctx *CheckContext) []string {
+	return []string{
+		"Run 'bd daemon --start' in " + ctx.TownRoot,
+		"If bd reports a legacy or mismatched database, run 'bd migrate --update-repo-id --yes' first",
+	}
+}
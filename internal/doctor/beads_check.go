@@ -165,6 +165,25 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the empty databases Fix would rebuild from JSONL.
+func (c *BeadsDatabaseCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	plan := func(beadsDir, workDir string) {
+		dbInfo, dbErr := os.Stat(filepath.Join(beadsDir, "issues.db"))
+		jsonlInfo, jsonlErr := os.Stat(filepath.Join(beadsDir, "issues.jsonl"))
+		if dbErr == nil && dbInfo.Size() == 0 && jsonlErr == nil && jsonlInfo.Size() > 0 {
+			changes = append(changes,
+				fmt.Sprintf("Delete empty %s", filepath.Join(beadsDir, "issues.db")),
+				fmt.Sprintf("Rebuild it from issues.jsonl with 'bd sync --from-main' in %s", workDir))
+		}
+	}
+	plan(filepath.Join(ctx.TownRoot, ".beads"), ctx.TownRoot)
+	if ctx.RigName != "" {
+		plan(beads.ResolveBeadsDir(ctx.RigPath()), ctx.RigPath())
+	}
+	return changes
+}
+
 // PrefixConflictCheck detects duplicate prefixes across rigs in routes.jsonl.
 // Duplicate prefixes break prefix-based routing.
 type PrefixConflictCheck struct {
@@ -231,7 +250,7 @@ func (c *PrefixConflictCheck) Run(ctx *CheckContext) *CheckResult {
 
 // PrefixMismatchCheck detects when rigs.json has a different prefix than what
 // routes.jsonl actually uses for a rig. This can happen when:
-// - deriveBeadsPrefix() generates a different prefix than what's in the beads DB
+// - DeriveBeadsPrefix() generates a different prefix than what's in the beads DB
 // - Someone manually edited rigs.json with the wrong prefix
 // - The beads were initialized before auto-derive existed with a different prefix
 type PrefixMismatchCheck struct {
@@ -395,6 +414,39 @@ func (c *PrefixMismatchCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the rigs.json prefixes Fix would change.
+func (c *PrefixMismatchCheck) PlanFix(ctx *CheckContext) []string {
+	routes, err := beads.LoadRoutes(filepath.Join(ctx.TownRoot, ".beads"))
+	if err != nil || len(routes) == 0 {
+		return nil
+	}
+	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
+	rigsConfig, err := loadRigsConfig(rigsPath)
+	if err != nil {
+		return nil
+	}
+	routePrefixByPath := make(map[string]string)
+	for _, r := range routes {
+		routePrefixByPath[r.Path] = strings.TrimSuffix(r.Prefix, "-")
+	}
+
+	var changes []string
+	for _, rigName := range sortedKeys(rigsConfig.Rigs) {
+		routePrefix, hasRoute := routePrefixByPath[rigName+"/mayor/rig"]
+		if !hasRoute {
+			continue
+		}
+		current := ""
+		if entry := rigsConfig.Rigs[rigName]; entry.BeadsConfig != nil {
+			current = entry.BeadsConfig.Prefix
+		}
+		if current != routePrefix {
+			changes = append(changes, fmt.Sprintf("Set rig '%s' prefix in %s: '%s' -> '%s'", rigName, rigsPath, current, routePrefix))
+		}
+	}
+	return changes
+}
+
 // rigsConfigEntry is a local type for loading rigs.json without importing config package
 // to avoid circular dependencies and keep the check self-contained.
 type rigsConfigEntry struct {
@@ -574,3 +626,12 @@ func (c *RoleLabelCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PlanFix lists the role beads Fix would label.
+func (c *RoleLabelCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, roleID := range c.missingLabel {
+		changes = append(changes, fmt.Sprintf("Add label gt:role to %s", roleID))
+	}
+	return changes
+}
@@ -115,6 +115,15 @@ func (c *BranchCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PlanFix lists the directories Fix would switch to main.
+func (c *BranchCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, dir := range c.offMainDirs {
+		changes = append(changes, fmt.Sprintf("git checkout main && git pull --rebase in %s", c.relativePath(ctx.TownRoot, dir)))
+	}
+	return changes
+}
+
 // findPersistentRoleDirs finds all directories that should be on main:
 // - <rig>/crew/*
 // - <rig>/witness/rig (if exists)
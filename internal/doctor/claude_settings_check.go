@@ -530,6 +530,38 @@ func (c *ClaudeSettingsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the settings files Fix would delete, recreate or skip.
+func (c *ClaudeSettingsCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, sf := range c.staleSettings {
+		if sf.wrongLocation && sf.gitStatus == gitStatusTrackedModified {
+			changes = append(changes, fmt.Sprintf("Skip %s (has local modifications)", sf.path))
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("Delete %s", sf.path))
+
+		claudeDir := filepath.Dir(sf.path)
+		if sf.wrongLocation {
+			if sf.agentType == "mayor" && !strings.Contains(sf.path, "/mayor/") {
+				if strings.HasSuffix(claudeDir, ".claude") {
+					changes = append(changes, fmt.Sprintf("Create mayor settings in %s", filepath.Join(ctx.TownRoot, "mayor", ".claude")))
+				}
+				if strings.HasSuffix(sf.path, "CLAUDE.md") {
+					changes = append(changes, fmt.Sprintf("Create %s", filepath.Join(ctx.TownRoot, "mayor", "CLAUDE.md")))
+				}
+			}
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("Recreate %s settings in %s", sf.agentType, claudeDir))
+		if ctx.RestartSessions && (sf.agentType == "witness" || sf.agentType == "refinery" ||
+			sf.agentType == "deacon" || sf.agentType == "mayor") {
+			changes = append(changes, fmt.Sprintf("Kill tmux session %s if running, so it restarts with the new settings", sf.sessionName))
+		}
+	}
+	return changes
+}
+
 // fileExists checks if a file exists.
 func fileExists(path string) bool {
 	info, err := os.Stat(path)
@@ -74,3 +74,12 @@ func (c *CommandsCheck) Fix(ctx *CheckContext) error {
 
 	return templates.ProvisionCommands(c.townRoot)
 }
+
+// PlanFix lists the slash commands Fix would provision.
+func (c *CommandsCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, name := range c.missingCommands {
+		changes = append(changes, fmt.Sprintf("Provision %s/.claude/commands/%s", ctx.TownRoot, name))
+	}
+	return changes
+}
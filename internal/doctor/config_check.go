@@ -95,6 +95,15 @@ func (c *SettingsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the settings directories Fix would create.
+func (c *SettingsCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, path := range c.missingSettings {
+		changes = append(changes, fmt.Sprintf("Create directory %s", path))
+	}
+	return changes
+}
+
 // RuntimeGitignoreCheck verifies .runtime/ is gitignored at town and rig levels.
 type RuntimeGitignoreCheck struct {
 	BaseCheck
@@ -261,6 +270,15 @@ func (c *LegacyGastownCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the legacy .gastown directories Fix would remove.
+func (c *LegacyGastownCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, dir := range c.legacyDirs {
+		changes = append(changes, fmt.Sprintf("Remove %s", dir))
+	}
+	return changes
+}
+
 // findRigs returns rig directories within the town.
 func (c *LegacyGastownCheck) findRigs(townRoot string) []string {
 	return findAllRigs(townRoot)
@@ -663,3 +681,12 @@ func (c *CustomTypesCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PlanFix lists the bd config change Fix would make.
+func (c *CustomTypesCheck) PlanFix(ctx *CheckContext) []string {
+	if len(c.missingTypes) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("Run 'bd config set types.custom %s' in %s (registers %s)",
+		constants.BeadsCustomTypes, c.townRoot, strings.Join(c.missingTypes, ", "))}
+}
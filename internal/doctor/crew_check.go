@@ -173,6 +173,15 @@ func (c *CrewStateCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PlanFix lists the crew state files Fix would rewrite.
+func (c *CrewStateCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, ic := range c.invalidCrews {
+		changes = append(changes, fmt.Sprintf("Write default state for %s/%s to %s", ic.rigName, ic.crewName, ic.stateFile))
+	}
+	return changes
+}
+
 type crewDir struct {
 	path     string
 	rigName  string
@@ -295,6 +304,15 @@ func (c *CrewWorktreeCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PlanFix lists the stale crew worktrees Fix would remove.
+func (c *CrewWorktreeCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, wt := range c.staleWorktrees {
+		changes = append(changes, fmt.Sprintf("git worktree remove --force %s (in %s/mayor/rig)", wt.path, wt.rigName))
+	}
+	return changes
+}
+
 // findCrewWorktrees finds cross-rig worktrees in crew directories.
 // These are worktrees with hyphenated names (e.g., "beads-dave") that
 // indicate they were created via `gt worktree` for cross-rig work.
@@ -91,6 +91,11 @@ func (c *DaemonCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix describes starting the daemon.
+func (c *DaemonCheck) PlanFix(ctx *CheckContext) []string {
+	return []string{"Start the daemon in the background ('gt daemon run' in " + ctx.TownRoot + ")"}
+}
+
 // itoa is a simple int to string helper
 func itoa(i int) string {
 	if i == 0 {
@@ -1,5 +1,10 @@
 package doctor
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Doctor manages and executes health checks.
 type Doctor struct {
 	checks []Check
@@ -32,21 +37,77 @@ type categoryGetter interface {
 	Category() string
 }
 
+// Select narrows the registered checks. A check is kept if it matches any
+// entry in only (or only is empty) and no entry in skip. Entries match a
+// check name or a category, case-insensitively. Unknown entries are an
+// error so typos don't silently select nothing.
+func (d *Doctor) Select(only, skip []string) error {
+	if len(only) == 0 && len(skip) == 0 {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, check := range d.checks {
+		known[strings.ToLower(check.Name())] = true
+		if cg, ok := check.(categoryGetter); ok && cg.Category() != "" {
+			known[strings.ToLower(cg.Category())] = true
+		}
+	}
+	for _, sel := range append(append([]string(nil), only...), skip...) {
+		if !known[strings.ToLower(sel)] {
+			return fmt.Errorf("unknown check or category %q", sel)
+		}
+	}
+
+	var selected []Check
+	for _, check := range d.checks {
+		if len(only) > 0 && !matchesCheck(check, only) {
+			continue
+		}
+		if matchesCheck(check, skip) {
+			continue
+		}
+		selected = append(selected, check)
+	}
+	d.checks = selected
+	return nil
+}
+
+// matchesCheck reports whether any selector names the check or its category.
+func matchesCheck(check Check, selectors []string) bool {
+	category := ""
+	if cg, ok := check.(categoryGetter); ok {
+		category = cg.Category()
+	}
+	for _, sel := range selectors {
+		if strings.EqualFold(sel, check.Name()) || (category != "" && strings.EqualFold(sel, category)) {
+			return true
+		}
+	}
+	return false
+}
+
+// runCheck runs a single check and fills in the fields the report needs
+// that checks don't set themselves.
+func runCheck(ctx *CheckContext, check Check) *CheckResult {
+	result := check.Run(ctx)
+	// Ensure check name is populated
+	if result.Name == "" {
+		result.Name = check.Name()
+	}
+	// Set category from check if available
+	if cg, ok := check.(categoryGetter); ok && result.Category == "" {
+		result.Category = cg.Category()
+	}
+	result.Fixable = check.CanFix()
+	return result
+}
+
 // Run executes all registered checks and returns a report.
 func (d *Doctor) Run(ctx *CheckContext) *Report {
 	report := NewReport()
 
 	for _, check := range d.checks {
-		result := check.Run(ctx)
-		// Ensure check name is populated
-		if result.Name == "" {
-			result.Name = check.Name()
-		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
-		}
-		report.Add(result)
+		report.Add(runCheck(ctx, check))
 	}
 
 	return report
@@ -58,28 +119,14 @@ func (d *Doctor) Fix(ctx *CheckContext) *Report {
 	report := NewReport()
 
 	for _, check := range d.checks {
-		result := check.Run(ctx)
-		if result.Name == "" {
-			result.Name = check.Name()
-		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
-		}
+		result := runCheck(ctx, check)
 
 		// Attempt fix if check failed and is fixable
 		if result.Status != StatusOK && check.CanFix() {
 			err := check.Fix(ctx)
 			if err == nil {
 				// Re-run check to verify fix worked
-				result = check.Run(ctx)
-				if result.Name == "" {
-					result.Name = check.Name()
-				}
-				// Set category again after re-run
-				if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-					result.Category = cg.Category()
-				}
+				result = runCheck(ctx, check)
 				// Update message to indicate fix was applied
 				if result.Status == StatusOK {
 					result.Message = result.Message + " (fixed)"
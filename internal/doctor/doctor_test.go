@@ -2,6 +2,8 @@ package doctor
 
 import (
 	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -362,3 +364,80 @@ func TestFixableCheck(t *testing.T) {
 		t.Error("FixableCheck.CanFix() should return true")
 	}
 }
+
+func TestReport_WriteJSON(t *testing.T) {
+	d := NewDoctor()
+	fixable := newMockCheck("fixable", StatusError)
+	fixable.fixable = true
+	fixable.CheckCategory = CategoryCleanup
+	d.RegisterAll(newMockCheck("ok-check", StatusOK), fixable)
+	report := d.Run(&CheckContext{TownRoot: "/town"})
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	if len(decoded.Checks) != 2 || decoded.Summary.Errors != 1 {
+		t.Fatalf("decoded report = %+v", decoded)
+	}
+	got := decoded.Checks[1]
+	if got.Name != "fixable" || got.Status != StatusError || !got.Fixable || got.Category != CategoryCleanup {
+		t.Errorf("decoded check = %+v", got)
+	}
+	if !strings.Contains(buf.String(), `"status": "error"`) {
+		t.Errorf("status not encoded as text:\n%s", buf.String())
+	}
+}
+
+func TestDoctor_Select(t *testing.T) {
+	newDoctor := func() *Doctor {
+		d := NewDoctor()
+		a := newMockCheck("orphan-sessions", StatusOK)
+		a.CheckCategory = CategoryCleanup
+		b := newMockCheck("wisp-gc", StatusOK)
+		b.CheckCategory = CategoryCleanup
+		c := newMockCheck("town-git", StatusOK)
+		c.CheckCategory = CategoryCore
+		d.RegisterAll(a, b, c)
+		return d
+	}
+	names := func(d *Doctor) string {
+		var out []string
+		for _, c := range d.Checks() {
+			out = append(out, c.Name())
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name       string
+		only, skip []string
+		want       string
+	}{
+		{"none", nil, nil, "orphan-sessions,wisp-gc,town-git"},
+		{"only category", []string{"cleanup"}, nil, "orphan-sessions,wisp-gc"},
+		{"only name", []string{"town-git"}, nil, "town-git"},
+		{"skip category", nil, []string{"Cleanup"}, "town-git"},
+		{"only and skip", []string{"cleanup"}, []string{"wisp-gc"}, "orphan-sessions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDoctor()
+			if err := d.Select(tt.only, tt.skip); err != nil {
+				t.Fatalf("Select: %v", err)
+			}
+			if got := names(d); got != tt.want {
+				t.Errorf("checks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := newDoctor().Select([]string{"no-such-check"}, nil); err == nil {
+		t.Error("Select with unknown selector should fail")
+	}
+}
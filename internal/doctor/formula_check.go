@@ -128,3 +128,26 @@ func (c *FormulaCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PlanFix lists the formulas Fix would update, reinstall or skip.
+func (c *FormulaCheck) PlanFix(ctx *CheckContext) []string {
+	report, err := formula.CheckFormulaHealth(ctx.TownRoot)
+	if err != nil {
+		return nil
+	}
+
+	var changes []string
+	for _, f := range report.Formulas {
+		switch f.Status {
+		case "outdated", "untracked":
+			changes = append(changes, fmt.Sprintf("Update formula %s", f.Name))
+		case "missing":
+			changes = append(changes, fmt.Sprintf("Reinstall formula %s", f.Name))
+		case "new":
+			changes = append(changes, fmt.Sprintf("Install formula %s", f.Name))
+		case "modified":
+			changes = append(changes, fmt.Sprintf("Skip formula %s (locally modified)", f.Name))
+		}
+	}
+	return changes
+}
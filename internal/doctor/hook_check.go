@@ -187,6 +187,15 @@ func (c *HookAttachmentValidCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the invalid molecule attachments Fix would detach.
+func (c *HookAttachmentValidCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, inv := range c.invalidAttachments {
+		changes = append(changes, fmt.Sprintf("Detach molecule %s from %s (%s)", inv.moleculeID, inv.pinnedBeadID, inv.reason))
+	}
+	return changes
+}
+
 // HookSingletonCheck ensures each agent has at most one handoff bead.
 // Detects when multiple pinned beads exist with the same "{role} Handoff" title,
 // which can cause confusion about which handoff is authoritative.
@@ -327,6 +336,18 @@ func (c *HookSingletonCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the duplicate handoff beads Fix would close.
+func (c *HookSingletonCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, dup := range c.duplicates {
+		if len(dup.beadIDs) > 1 {
+			changes = append(changes, fmt.Sprintf("Close duplicate handoff beads %s for %q (keep %s)",
+				strings.Join(dup.beadIDs[1:], ", "), dup.title, dup.beadIDs[0]))
+		}
+	}
+	return changes
+}
+
 // OrphanedAttachmentsCheck detects handoff beads for agents that no longer exist.
 // This happens when a polecat worktree is deleted but its handoff bead remains,
 // leaving molecules attached to non-existent agents.
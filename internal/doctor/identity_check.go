@@ -11,6 +11,7 @@ import (
 // IdentityCollisionCheck checks for agent identity collisions and stale locks.
 type IdentityCollisionCheck struct {
 	BaseCheck
+	staleLockDirs []string // Cached during Run for use in PlanFix
 }
 
 // NewIdentityCollisionCheck creates a new identity collision check.
@@ -74,6 +75,7 @@ func (c *IdentityCollisionCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
+	c.staleLockDirs = nil
 	var staleLocks []string
 	var orphanedLocks []string
 	var healthyLocks int
@@ -93,6 +95,7 @@ func (c *IdentityCollisionCheck) Run(ctx *CheckContext) *CheckResult {
 				continue
 			}
 			// Both PID dead AND session gone = truly stale
+			c.staleLockDirs = append(c.staleLockDirs, workerDir)
 			staleLocks = append(staleLocks,
 				fmt.Sprintf("%s (dead PID %d)", workerDir, info.PID))
 			continue
@@ -165,3 +168,12 @@ func (c *IdentityCollisionCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PlanFix lists the stale locks Fix would release.
+func (c *IdentityCollisionCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, dir := range c.staleLockDirs {
+		changes = append(changes, fmt.Sprintf("Release stale lock in %s", dir))
+	}
+	return changes
+}
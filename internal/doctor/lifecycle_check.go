@@ -111,3 +111,12 @@ func (c *LifecycleHygieneCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PlanFix lists the stale lifecycle messages Fix would delete.
+func (c *LifecycleHygieneCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, msg := range c.staleMessages {
+		changes = append(changes, fmt.Sprintf("Delete message %s from %s: %s", msg.ID, msg.From, msg.Subject))
+	}
+	return changes
+}
@@ -158,6 +158,24 @@ func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PlanFix lists the orphaned sessions Fix would kill.
+func (c *OrphanSessionCheck) PlanFix(ctx *CheckContext) []string {
+	return planSessionKills(c.orphanSessions)
+}
+
+// planSessionKills describes killing sessions, noting protected crew sessions.
+func planSessionKills(sessions []string) []string {
+	var changes []string
+	for _, sess := range sessions {
+		if isCrewSession(sess) {
+			changes = append(changes, "Skip crew session "+sess+" (protected)")
+			continue
+		}
+		changes = append(changes, "Kill tmux session "+sess)
+	}
+	return changes
+}
+
 // isCrewSession returns true if the session name matches the crew pattern.
 // Crew sessions are gt-<rig>-crew-<name> and are protected from auto-cleanup.
 func isCrewSession(session string) bool {
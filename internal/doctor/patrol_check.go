@@ -131,6 +131,17 @@ func (c *PatrolMoleculesExistCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the patrol molecules Fix would create.
+func (c *PatrolMoleculesExistCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, rigName := range sortedKeys(c.missingMols) {
+		for _, mol := range c.missingMols[rigName] {
+			changes = append(changes, fmt.Sprintf("Create molecule %q in %s", mol, rigName))
+		}
+	}
+	return changes
+}
+
 func getPatrolMoleculeDesc(title string) string {
 	switch title {
 	case "Deacon Patrol":
@@ -215,6 +226,15 @@ func (c *PatrolHooksWiredCheck) Fix(ctx *CheckContext) error {
 	return config.EnsureDaemonPatrolConfig(ctx.TownRoot)
 }
 
+// PlanFix lists the daemon patrol config Fix would create.
+func (c *PatrolHooksWiredCheck) PlanFix(ctx *CheckContext) []string {
+	path := config.DaemonPatrolConfigPath(ctx.TownRoot)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("Create default daemon patrol config at %s", path)}
+}
+
 // PatrolNotStuckCheck detects wisps that have been in_progress too long.
 type PatrolNotStuckCheck struct {
 	BaseCheck
@@ -408,6 +428,15 @@ func (c *PatrolPluginsAccessibleCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the plugin directories Fix would create.
+func (c *PatrolPluginsAccessibleCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, dir := range c.missingDirs {
+		changes = append(changes, fmt.Sprintf("Create directory %s", dir))
+	}
+	return changes
+}
+
 // PatrolRolesHavePromptsCheck verifies that internal/templates/roles/*.md.tmpl exist for each rig.
 // Checks at <town>/<rig>/mayor/rig/internal/templates/roles/*.md.tmpl
 // Fix copies embedded templates to missing locations.
@@ -523,6 +552,18 @@ func (c *PatrolRolesHavePromptsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the role templates Fix would write.
+func (c *PatrolRolesHavePromptsCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, rigName := range sortedKeys(c.missingByRig) {
+		templatesDir := filepath.Join(ctx.TownRoot, rigName, "mayor", "rig", "internal", "templates", "roles")
+		for _, roleFile := range c.missingByRig[rigName] {
+			changes = append(changes, fmt.Sprintf("Write %s", filepath.Join(templatesDir, roleFile)))
+		}
+	}
+	return changes
+}
+
 // discoverRigs finds all registered rigs.
 func discoverRigs(townRoot string) ([]string, error) {
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
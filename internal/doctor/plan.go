@@ -0,0 +1,142 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+// FixPlanner is implemented by fixable checks that can describe what their
+// Fix would change. PlanFix is called after Run, so it can use state cached
+// during Run, and must not modify anything.
+type FixPlanner interface {
+	PlanFix(ctx *CheckContext) []string
+}
+
+// PlannedFix describes what --fix would do for one failing check.
+type PlannedFix struct {
+	Check    string      `json:"check"`
+	Category string      `json:"category,omitempty"`
+	Status   CheckStatus `json:"status"`
+	Message  string      `json:"message"`
+	Changes  []string    `json:"changes"`
+
+	// Detailed is false when the check can't describe its fix and Changes
+	// is a generic summary derived from the check result.
+	Detailed bool `json:"detailed"`
+}
+
+// Plan lists the fixes --fix would apply, plus failing checks that need a
+// human because they can't be fixed automatically.
+type Plan struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Fixes     []*PlannedFix  `json:"fixes"`
+	Manual    []*CheckResult `json:"manual"`
+}
+
+// Plan runs all registered checks and describes what Fix would change,
+// without changing anything.
+func (d *Doctor) Plan(ctx *CheckContext) *Plan {
+	plan := &Plan{
+		Timestamp: time.Now(),
+		Fixes:     []*PlannedFix{},
+		Manual:    []*CheckResult{},
+	}
+
+	for _, check := range d.checks {
+		result := runCheck(ctx, check)
+		if result.Status == StatusOK {
+			continue
+		}
+		if !check.CanFix() {
+			plan.Manual = append(plan.Manual, result)
+			continue
+		}
+
+		fix := &PlannedFix{
+			Check:    result.Name,
+			Category: result.Category,
+			Status:   result.Status,
+			Message:  result.Message,
+		}
+		if planner, ok := check.(FixPlanner); ok {
+			fix.Changes = planner.PlanFix(ctx)
+			fix.Detailed = true
+		} else {
+			fix.Changes = append([]string{check.Description()}, result.Details...)
+		}
+		if fix.Changes == nil {
+			fix.Changes = []string{}
+		}
+		plan.Fixes = append(plan.Fixes, fix)
+	}
+
+	return plan
+}
+
+// WriteJSON writes the plan as indented JSON.
+func (p *Plan) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// Print outputs the plan to the given writer.
+func (p *Plan) Print(w io.Writer) {
+	if len(p.Fixes) == 0 && len(p.Manual) == 0 {
+		_, _ = fmt.Fprintf(w, "%s Nothing to fix\n", ui.RenderPassIcon())
+		return
+	}
+
+	if len(p.Fixes) > 0 {
+		_, _ = fmt.Fprintln(w, ui.RenderCategory("gt doctor --fix would"))
+		for _, fix := range p.Fixes {
+			_, _ = fmt.Fprintf(w, "  %s  %s %s\n", statusIcon(fix.Status), ui.RenderBold(fix.Check), ui.RenderMuted(fix.Message))
+			if len(fix.Changes) == 0 {
+				_, _ = fmt.Fprintf(w, "      %s\n", ui.RenderMuted("no changes (fix must be run manually)"))
+			}
+			for _, change := range fix.Changes {
+				if fix.Detailed {
+					_, _ = fmt.Fprintf(w, "      %s%s\n", ui.MutedStyle.Render(ui.TreeLast), change)
+				} else {
+					_, _ = fmt.Fprintf(w, "      %s%s\n", ui.MutedStyle.Render(ui.TreeLast), ui.RenderMuted(change))
+				}
+			}
+		}
+	}
+
+	if len(p.Manual) > 0 {
+		if len(p.Fixes) > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintln(w, ui.RenderCategory("Needs manual attention"))
+		for _, result := range p.Manual {
+			_, _ = fmt.Fprintf(w, "  %s  %s %s\n", statusIcon(result.Status), ui.RenderBold(result.Name), ui.RenderMuted(result.Message))
+			if result.FixHint != "" {
+				_, _ = fmt.Fprintf(w, "      %s%s\n", ui.MutedStyle.Render(ui.TreeLast), result.FixHint)
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%d fix(es) planned, %d manual\n", len(p.Fixes), len(p.Manual))
+}
+
+func statusIcon(status CheckStatus) string {
+	switch status {
+	case StatusError:
+		return ui.RenderFailIcon()
+	case StatusWarning:
+		return ui.RenderWarnIcon()
+	}
+	return ui.RenderPassIcon()
+}
+
+// sortedKeys returns a map's keys in order, so plans list changes stably.
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
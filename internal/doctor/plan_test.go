@@ -0,0 +1,145 @@
+package doctor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// plannedCheck is a fixable mock that describes its fix.
+type plannedCheck struct {
+	mockCheck
+	changes []string
+}
+
+func (p *plannedCheck) PlanFix(ctx *CheckContext) []string {
+	return p.changes
+}
+
+func TestDoctor_Plan(t *testing.T) {
+	planned := &plannedCheck{
+		mockCheck: *newMockCheck("planned", StatusWarning),
+		changes:   []string{"Kill tmux session gt-old"},
+	}
+	planned.fixable = true
+
+	generic := newMockCheck("generic", StatusError)
+	generic.fixable = true
+
+	manual := newMockCheck("manual", StatusError)
+
+	d := NewDoctor()
+	d.RegisterAll(newMockCheck("healthy", StatusOK), planned, generic, manual)
+	plan := d.Plan(&CheckContext{TownRoot: "/town"})
+
+	if planned.fixCount != 0 || generic.fixCount != 0 {
+		t.Fatal("Plan must not apply fixes")
+	}
+	if len(plan.Fixes) != 2 {
+		t.Fatalf("len(Fixes) = %d, want 2", len(plan.Fixes))
+	}
+	if fix := plan.Fixes[0]; fix.Check != "planned" || !fix.Detailed || len(fix.Changes) != 1 {
+		t.Errorf("planned fix = %+v", fix)
+	}
+	if fix := plan.Fixes[1]; fix.Check != "generic" || fix.Detailed || fix.Changes[0] != "Test check: generic" {
+		t.Errorf("generic fix = %+v", fix)
+	}
+	if len(plan.Manual) != 1 || plan.Manual[0].Name != "manual" {
+		t.Errorf("Manual = %+v", plan.Manual)
+	}
+
+	var buf bytes.Buffer
+	plan.Print(&buf)
+	if !strings.Contains(buf.String(), "Kill tmux session gt-old") {
+		t.Errorf("Print output missing change:\n%s", buf.String())
+	}
+}
+
+func TestPlanSessionKills(t *testing.T) {
+	got := planSessionKills([]string{"gt-gastown-witness", "gt-gastown-crew-joe"})
+	want := []string{"Kill tmux session gt-gastown-witness", "Skip crew session gt-gastown-crew-joe (protected)"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("planSessionKills = %q, want %q", got, want)
+	}
+}
+
+// TestFixableChecksPlanFixes guards --dry-run: every check --fix can run
+// must be able to describe what it would change.
+func TestFixableChecksPlanFixes(t *testing.T) {
+	checks := []Check{
+		NewAgentBeadsCheck(),
+		NewBdDaemonCheck(),
+		NewBeadsDatabaseCheck(),
+		NewPrefixConflictCheck(),
+		NewPrefixMismatchCheck(),
+		NewRoleLabelCheck(),
+		NewBootHealthCheck(),
+		NewBranchCheck(),
+		NewBeadsSyncOrphanCheck(),
+		NewCloneDivergenceCheck(),
+		NewClaudeSettingsCheck(),
+		NewCommandsCheck(),
+		NewSettingsCheck(),
+		NewRuntimeGitignoreCheck(),
+		NewLegacyGastownCheck(),
+		NewSessionHookCheck(),
+		NewCustomTypesCheck(),
+		NewCrashReportCheck(),
+		NewCrewStateCheck(),
+		NewCrewWorktreeCheck(),
+		NewDaemonCheck(),
+		NewEnvVarsCheck(),
+		NewFormulaCheck(),
+		NewGlobalStateCheck(),
+		NewHookAttachmentValidCheck(),
+		NewHookSingletonCheck(),
+		NewOrphanedAttachmentsCheck(),
+		NewIdentityCollisionCheck(),
+		NewLifecycleHygieneCheck(),
+		NewOrphanSessionCheck(),
+		NewOrphanProcessCheck(),
+		NewPatrolMoleculesExistCheck(),
+		NewPatrolHooksWiredCheck(),
+		NewPatrolNotStuckCheck(),
+		NewPatrolPluginsAccessibleCheck(),
+		NewPatrolRolesHavePromptsCheck(),
+		NewPreCheckoutHookCheck(),
+		NewPrimingCheck(),
+		NewRepoFingerprintCheck(),
+		NewRigBeadsCheck(),
+		NewRigIsGitRepoCheck(),
+		NewGitExcludeConfiguredCheck(),
+		NewHooksPathConfiguredCheck(),
+		NewWitnessExistsCheck(),
+		NewRefineryExistsCheck(),
+		NewMayorCloneExistsCheck(),
+		NewPolecatClonesValidCheck(),
+		NewBeadsConfigValidCheck(),
+		NewBeadsRedirectCheck(),
+		NewBareRepoRefspecCheck(),
+		NewRigRoutesJSONLCheck(),
+		NewRoleBeadsCheck(),
+		NewRoutesCheck(),
+		NewSparseCheckoutCheck(),
+		NewStaleBinaryCheck(),
+		NewThemeCheck(),
+		NewLinkedPaneCheck(),
+		NewTownGitCheck(),
+		NewTownRootBranchCheck(),
+		NewWispGCCheck(),
+		NewTownConfigExistsCheck(),
+		NewTownConfigValidCheck(),
+		NewRigsRegistryExistsCheck(),
+		NewRigsRegistryValidCheck(),
+		NewMayorExistsCheck(),
+		NewZombieSessionCheck(),
+	}
+	for _, check := range checks {
+		if !check.CanFix() {
+			continue
+		}
+		if _, ok := check.(FixPlanner); !ok {
+			t.Errorf("%s can fix but does not implement FixPlanner", check.Name())
+		}
+	}
+}
@@ -160,3 +160,11 @@ func (c *PreCheckoutHookCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PlanFix lists the hook Fix would install.
+func (c *PreCheckoutHookCheck) PlanFix(ctx *CheckContext) []string {
+	if !c.hookMissing {
+		return nil
+	}
+	return []string{fmt.Sprintf("Install %s", filepath.Join(ctx.TownRoot, ".git", "hooks", "pre-checkout"))}
+}
@@ -370,3 +370,14 @@ func (c *PrimingCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PlanFix lists the PRIME.md files Fix would provision.
+func (c *PrimingCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, issue := range c.issues {
+		if issue.fixable && issue.issueType == "missing_prime_md" {
+			changes = append(changes, fmt.Sprintf("Provision PRIME.md for %s", issue.location))
+		}
+	}
+	return changes
+}
@@ -198,3 +198,15 @@ func (c *RepoFingerprintCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PlanFix lists the migration Fix would run.
+func (c *RepoFingerprintCheck) PlanFix(ctx *CheckContext) []string {
+	if !c.needsMigration || c.beadsDir == "" {
+		return nil
+	}
+	changes := []string{fmt.Sprintf("Run 'bd migrate --update-repo-id' in %s", filepath.Dir(c.beadsDir))}
+	if running, _, err := daemon.IsRunning(ctx.TownRoot); err == nil && running {
+		changes = append(changes, "Restart the gt daemon")
+	}
+	return changes
+}
@@ -14,6 +14,7 @@ import (
 // They are created by gt rig add (see gt-zmznh) but may be missing for legacy rigs.
 type RigBeadsCheck struct {
 	FixableCheck
+	missing []string // Cached during Run for use in PlanFix
 }
 
 // NewRigBeadsCheck creates a new rig identity beads check.
@@ -31,6 +32,8 @@ func NewRigBeadsCheck() *RigBeadsCheck {
 
 // Run checks if rig identity beads exist for all rigs.
 func (c *RigBeadsCheck) Run(ctx *CheckContext) *CheckResult {
+	c.missing = nil
+
 	// Load routes to get rig info
 	townBeadsDir := filepath.Join(ctx.TownRoot, ".beads")
 	routes, err := beads.LoadRoutes(townBeadsDir)
@@ -89,6 +92,7 @@ func (c *RigBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 		checked++
 	}
 
+	c.missing = missing
 	if len(missing) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -170,3 +174,12 @@ func (c *RigBeadsCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PlanFix lists the rig identity beads Fix would create.
+func (c *RigBeadsCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, id := range c.missing {
+		changes = append(changes, fmt.Sprintf("Create rig identity bead %s", id))
+	}
+	return changes
+}
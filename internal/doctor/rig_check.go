@@ -237,6 +237,15 @@ func (c *GitExcludeConfiguredCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the entries Fix would append.
+func (c *GitExcludeConfiguredCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, entry := range c.missingEntries {
+		changes = append(changes, fmt.Sprintf("Append %s to %s", entry, c.excludePath))
+	}
+	return changes
+}
+
 // HooksPathConfiguredCheck verifies all clones have core.hooksPath set to .githooks.
 // This ensures the pre-push hook blocks pushes to invalid branches (no internal PRs).
 type HooksPathConfiguredCheck struct {
@@ -358,6 +367,15 @@ func (c *HooksPathConfiguredCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the clones Fix would configure.
+func (c *HooksPathConfiguredCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, clonePath := range c.unconfiguredClones {
+		changes = append(changes, "Set core.hooksPath=.githooks in "+clonePath)
+	}
+	return changes
+}
+
 // WitnessExistsCheck verifies the witness directory structure exists.
 type WitnessExistsCheck struct {
 	FixableCheck
@@ -465,6 +483,27 @@ func (c *WitnessExistsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the witness structure Fix would create.
+func (c *WitnessExistsCheck) PlanFix(ctx *CheckContext) []string {
+	return planAgentDirs(filepath.Join(c.rigPath, "witness"), c.needsCreate, c.needsMail, c.needsClone)
+}
+
+// planAgentDirs describes creating an agent's directory and mailbox. The
+// clone can't be created without the repo URL, so it's listed as manual.
+func planAgentDirs(dir string, needsCreate, needsMail, needsClone bool) []string {
+	var changes []string
+	if needsCreate {
+		changes = append(changes, "Create directory "+dir)
+	}
+	if needsMail {
+		changes = append(changes, "Create empty "+filepath.Join(dir, "mail", "inbox.jsonl"))
+	}
+	if needsClone {
+		changes = append(changes, "Cannot create "+filepath.Join(dir, "rig")+" clone (requires repo URL); fix will fail")
+	}
+	return changes
+}
+
 // RefineryExistsCheck verifies the refinery directory structure exists.
 type RefineryExistsCheck struct {
 	FixableCheck
@@ -572,6 +611,11 @@ func (c *RefineryExistsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the refinery structure Fix would create.
+func (c *RefineryExistsCheck) PlanFix(ctx *CheckContext) []string {
+	return planAgentDirs(filepath.Join(c.rigPath, "refinery"), c.needsCreate, c.needsMail, c.needsClone)
+}
+
 // MayorCloneExistsCheck verifies the mayor/rig clone exists.
 type MayorCloneExistsCheck struct {
 	FixableCheck
@@ -659,6 +703,11 @@ func (c *MayorCloneExistsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the mayor structure Fix would create.
+func (c *MayorCloneExistsCheck) PlanFix(ctx *CheckContext) []string {
+	return planAgentDirs(filepath.Join(c.rigPath, "mayor"), c.needsCreate, false, c.needsClone)
+}
+
 // PolecatClonesValidCheck verifies each polecat directory is a valid clone.
 type PolecatClonesValidCheck struct {
 	BaseCheck
@@ -887,6 +936,14 @@ func (c *BeadsConfigValidCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the sync Fix would run.
+func (c *BeadsConfigValidCheck) PlanFix(ctx *CheckContext) []string {
+	if !c.needsSync {
+		return nil
+	}
+	return []string{fmt.Sprintf("Run 'bd sync' in %s", c.rigPath)}
+}
+
 // BeadsRedirectCheck verifies that rig-level beads redirect exists for tracked beads.
 // When a repo has .beads/ tracked in git (at mayor/rig/.beads), the rig root needs
 // a redirect file pointing to that location.
@@ -1087,6 +1144,37 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the beads initialization or redirect Fix would write.
+func (c *BeadsRedirectCheck) PlanFix(ctx *CheckContext) []string {
+	if ctx.RigName == "" {
+		return nil
+	}
+
+	rigPath := ctx.RigPath()
+	mayorRigBeads := filepath.Join(rigPath, "mayor", "rig", ".beads")
+	rigBeadsDir := filepath.Join(rigPath, ".beads")
+
+	_, err := os.Stat(mayorRigBeads)
+	hasTrackedBeads := !os.IsNotExist(err)
+	_, err = os.Stat(rigBeadsDir)
+	hasLocalBeads := !os.IsNotExist(err)
+
+	if !hasTrackedBeads && !hasLocalBeads {
+		prefix := config.GetRigPrefix(ctx.TownRoot, ctx.RigName)
+		return []string{fmt.Sprintf("Run 'bd init --prefix %s' in %s", prefix, rigPath)}
+	}
+	if !hasTrackedBeads {
+		return nil
+	}
+
+	var changes []string
+	if hasLocalBeads && hasBeadsData(rigBeadsDir) {
+		changes = append(changes, fmt.Sprintf("Remove conflicting local beads %s", rigBeadsDir))
+	}
+	changes = append(changes, fmt.Sprintf("Write %s -> mayor/rig/.beads", filepath.Join(rigBeadsDir, "redirect")))
+	return changes
+}
+
 // hasBeadsData checks if a beads directory has actual data (issues.jsonl, issues.db, config.yaml)
 // as opposed to just being a redirect-only directory.
 func hasBeadsData(beadsDir string) bool {
@@ -1198,6 +1286,18 @@ func (c *BareRepoRefspecCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the refspec Fix would configure.
+func (c *BareRepoRefspecCheck) PlanFix(ctx *CheckContext) []string {
+	if ctx.RigName == "" {
+		return nil
+	}
+	bareRepoPath := filepath.Join(ctx.RigPath(), ".repo.git")
+	if _, err := os.Stat(bareRepoPath); os.IsNotExist(err) {
+		return nil
+	}
+	return []string{fmt.Sprintf("Set remote.origin.fetch to +refs/heads/*:refs/remotes/origin/* in %s", bareRepoPath)}
+}
+
 // RigChecks returns all rig-level health checks.
 func RigChecks() []Check {
 	return []Check{
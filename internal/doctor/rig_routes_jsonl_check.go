@@ -120,6 +120,15 @@ func (c *RigRoutesJSONLCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PlanFix lists the rig-level routes.jsonl files Fix would delete.
+func (c *RigRoutesJSONLCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, info := range c.affectedRigs {
+		changes = append(changes, fmt.Sprintf("Delete %s", info.routesPath))
+	}
+	return changes
+}
+
 // findRigDirectories finds all rig directories in the town.
 func (c *RigRoutesJSONLCheck) findRigDirectories(townRoot string) []string {
 	var rigDirs []string
@@ -119,3 +119,12 @@ func (c *RoleBeadsCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PlanFix lists the role beads Fix would create.
+func (c *RoleBeadsCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, id := range c.missing {
+		changes = append(changes, fmt.Sprintf("Create role bead %s", id))
+	}
+	return changes
+}
@@ -244,42 +244,68 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 		routes = []beads.Route{} // Start fresh if can't load
 	}
 
+	missing := missingRoutes(ctx.TownRoot, routes)
+	if len(missing) == 0 {
+		return nil
+	}
+	return beads.WriteRoutes(beadsDir, append(routes, missing...))
+}
+
+// PlanFix lists the routes Fix would add to routes.jsonl.
+func (c *RoutesCheck) PlanFix(ctx *CheckContext) []string {
+	beadsDir := filepath.Join(ctx.TownRoot, ".beads")
+	if _, err := os.Stat(beadsDir); os.IsNotExist(err) {
+		return nil
+	}
+	routes, err := beads.LoadRoutes(beadsDir)
+	if err != nil {
+		routes = []beads.Route{}
+	}
+
+	var changes []string
+	for _, r := range missingRoutes(ctx.TownRoot, routes) {
+		changes = append(changes, fmt.Sprintf("Add route %s -> %s to %s", r.Prefix, r.Path,
+			filepath.Join(beadsDir, "routes.jsonl")))
+	}
+	return changes
+}
+
+// missingRoutes returns the routes Fix adds: the town root and convoy
+// routes, plus one per registered rig whose mayor/rig clone exists.
+func missingRoutes(townRoot string, routes []beads.Route) []beads.Route {
 	// Build map of existing prefixes
 	routeMap := make(map[string]bool)
 	for _, r := range routes {
 		routeMap[r.Prefix] = true
 	}
 
+	var missing []beads.Route
+
 	// Ensure town root route exists (hq- -> .)
 	// This is normally created by gt install but may be missing if routes.jsonl was corrupted
-	modified := false
 	if !routeMap["hq-"] {
-		routes = append(routes, beads.Route{Prefix: "hq-", Path: "."})
+		missing = append(missing, beads.Route{Prefix: "hq-", Path: "."})
 		routeMap["hq-"] = true
-		modified = true
 	}
 
 	// Ensure convoy route exists (hq-cv- -> .)
 	// Convoys use hq-cv-* IDs for visual distinction from other town beads
 	if !routeMap["hq-cv-"] {
-		routes = append(routes, beads.Route{Prefix: "hq-cv-", Path: "."})
+		missing = append(missing, beads.Route{Prefix: "hq-cv-", Path: "."})
 		routeMap["hq-cv-"] = true
-		modified = true
 	}
 
 	// Load rigs registry
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	rigsConfig, err := config.LoadRigsConfig(rigsPath)
 	if err != nil {
-		// No rigs config - just write town root route if we added it
-		if modified {
-			return beads.WriteRoutes(beadsDir, routes)
-		}
-		return nil
+		// No rigs config - only the town root routes
+		return missing
 	}
 
 	// Add missing routes for each rig
-	for rigName, rigEntry := range rigsConfig.Rigs {
+	for _, rigName := range sortedKeys(rigsConfig.Rigs) {
+		rigEntry := rigsConfig.Rigs[rigName]
 		prefix := ""
 		if rigEntry.BeadsConfig != nil && rigEntry.BeadsConfig.Prefix != "" {
 			prefix = rigEntry.BeadsConfig.Prefix + "-"
@@ -287,22 +313,16 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 
 		if prefix != "" && !routeMap[prefix] {
 			// Verify the rig path exists before adding
-			rigPath := filepath.Join(ctx.TownRoot, rigName, "mayor", "rig")
+			rigPath := filepath.Join(townRoot, rigName, "mayor", "rig")
 			if _, err := os.Stat(rigPath); err == nil {
-				route := beads.Route{
+				missing = append(missing, beads.Route{
 					Prefix: prefix,
 					Path:   rigName + "/mayor/rig",
-				}
-				routes = append(routes, route)
+				})
 				routeMap[prefix] = true
-				modified = true
 			}
 		}
 	}
 
-	if modified {
-		return beads.WriteRoutes(beadsDir, routes)
-	}
-
-	return nil
+	return missing
 }
@@ -129,3 +129,13 @@ func (c *SparseCheckoutCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PlanFix lists the repos Fix would configure sparse checkout for.
+func (c *SparseCheckoutCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, repoPath := range c.affectedRepos {
+		relPath, _ := filepath.Rel(c.rigPath, repoPath)
+		changes = append(changes, fmt.Sprintf("Configure sparse checkout in %s (excludes Claude context files)", relPath))
+	}
+	return changes
+}
@@ -78,6 +78,11 @@ func (c *StaleBinaryCheck) Fix(ctx *CheckContext) error {
 	return fmt.Errorf("run 'gt install' manually to rebuild")
 }
 
+// PlanFix returns nothing: Fix never rebuilds the binary.
+func (c *StaleBinaryCheck) PlanFix(ctx *CheckContext) []string {
+	return nil
+}
+
 // CanFix returns false - stale binary should be fixed manually.
 func (c *StaleBinaryCheck) CanFix() bool {
 	return false
@@ -98,6 +98,11 @@ func (c *ThemeCheck) Fix(ctx *CheckContext) error {
 	return cmd.Run()
 }
 
+// PlanFix describes re-applying session themes.
+func (c *ThemeCheck) PlanFix(ctx *CheckContext) []string {
+	return []string{"Run 'gt theme apply --all'"}
+}
+
 // getSessionStatusLeft retrieves the status-left setting for a tmux session.
 func getSessionStatusLeft(session string) (string, error) {
 	cmd := exec.Command("tmux", "show-options", "-t", session, "status-left")
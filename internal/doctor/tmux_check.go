@@ -131,6 +131,15 @@ func (c *LinkedPaneCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PlanFix lists the sessions Fix would kill.
+func (c *LinkedPaneCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, session := range c.linkedSessions {
+		changes = append(changes, fmt.Sprintf("Kill tmux session %s (shares linked panes)", session))
+	}
+	return changes
+}
+
 // getSessionPanes returns all pane IDs for a session.
 func (c *LinkedPaneCheck) getSessionPanes(session string) ([]string, error) {
 	// Get pane IDs using tmux list-panes with format
@@ -116,3 +116,11 @@ func (c *TownRootBranchCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PlanFix describes switching the town root back to main.
+func (c *TownRootBranchCheck) PlanFix(ctx *CheckContext) []string {
+	if c.currentBranch == "main" || c.currentBranch == "master" {
+		return nil
+	}
+	return []string{fmt.Sprintf("Check out main (or master) in %s, currently on %s", ctx.TownRoot, c.currentBranch)}
+}
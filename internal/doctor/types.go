@@ -2,6 +2,7 @@
 package doctor
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"slices"
@@ -12,13 +13,13 @@ import (
 
 // Category constants for grouping checks
 const (
-	CategoryCore          = "Core"
+	CategoryCore           = "Core"
 	CategoryInfrastructure = "Infrastructure"
-	CategoryRig           = "Rig"
-	CategoryPatrol        = "Patrol"
-	CategoryConfig        = "Configuration"
-	CategoryCleanup       = "Cleanup"
-	CategoryHooks         = "Hooks"
+	CategoryRig            = "Rig"
+	CategoryPatrol         = "Patrol"
+	CategoryConfig         = "Configuration"
+	CategoryCleanup        = "Cleanup"
+	CategoryHooks          = "Hooks"
 )
 
 // CategoryOrder defines the display order for categories
@@ -58,6 +59,35 @@ func (s CheckStatus) String() string {
 	}
 }
 
+// MarshalText encodes the status as "ok", "warning" or "error" for
+// machine-readable reports.
+func (s CheckStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case StatusOK:
+		return []byte("ok"), nil
+	case StatusWarning:
+		return []byte("warning"), nil
+	case StatusError:
+		return []byte("error"), nil
+	}
+	return nil, fmt.Errorf("invalid check status %d", int(s))
+}
+
+// UnmarshalText decodes a status written by MarshalText.
+func (s *CheckStatus) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "ok":
+		*s = StatusOK
+	case "warning":
+		*s = StatusWarning
+	case "error":
+		*s = StatusError
+	default:
+		return fmt.Errorf("invalid check status %q", text)
+	}
+	return nil
+}
+
 // CheckContext provides context for running checks.
 type CheckContext struct {
 	TownRoot        string // Root directory of the Gas Town workspace
@@ -77,12 +107,17 @@ func (ctx *CheckContext) RigPath() string {
 
 // CheckResult represents the outcome of a health check.
 type CheckResult struct {
-	Name     string      // Check name
-	Status   CheckStatus // Result status
-	Message  string      // Primary result message
-	Details  []string    // Additional information
-	FixHint  string      // Suggestion if not auto-fixable
-	Category string      // Category for grouping (e.g., CategoryCore)
+	Name     string      `json:"name"`               // Check name
+	Status   CheckStatus `json:"status"`             // Result status
+	Message  string      `json:"message"`            // Primary result message
+	Details  []string    `json:"details,omitempty"`  // Additional information
+	FixHint  string      `json:"fix_hint,omitempty"` // Suggestion if not auto-fixable
+	Category string      `json:"category,omitempty"` // Category for grouping (e.g., CategoryCore)
+	Fixable  bool        `json:"fixable"`            // Check supports --fix
+
+	// Suppressed holds the reason when an active suppression in town
+	// settings covers this failure; it is then not counted in the summary.
+	Suppressed string `json:"suppressed,omitempty"`
 }
 
 // Check defines the interface for a health check.
@@ -106,17 +141,19 @@ type Check interface {
 
 // ReportSummary summarizes the results of all checks.
 type ReportSummary struct {
-	Total    int
-	OK       int
-	Warnings int
-	Errors   int
+	Total      int `json:"total"`
+	OK         int `json:"ok"`
+	Warnings   int `json:"warnings"`
+	Errors     int `json:"errors"`
+	Suppressed int `json:"suppressed"`
 }
 
 // Report contains all check results and a summary.
 type Report struct {
-	Timestamp time.Time
-	Checks    []*CheckResult
-	Summary   ReportSummary
+	Timestamp time.Time      `json:"timestamp"`
+	Checks    []*CheckResult `json:"checks"`
+	Summary   ReportSummary  `json:"summary"`
+	Drift     *Drift         `json:"drift,omitempty"` // Set when compared against a baseline
 }
 
 // NewReport creates an empty report with the current timestamp.
@@ -157,6 +194,13 @@ func (r *Report) IsHealthy() bool {
 	return r.Summary.Errors == 0 && r.Summary.Warnings == 0
 }
 
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
 // Print outputs the report to the given writer.
 // Matches bd doctor UX: grouped by category, semantic icons, warnings section.
 func (r *Report) Print(w io.Writer, verbose bool) {
@@ -189,7 +233,7 @@ func (r *Report) Print(w io.Writer, verbose bool) {
 		// Print each check in this category
 		for _, check := range checks {
 			r.printCheck(w, check, verbose)
-			if check.Status != StatusOK {
+			if check.Status != StatusOK && check.Suppressed == "" {
 				warnings = append(warnings, check)
 			}
 		}
@@ -201,7 +245,7 @@ func (r *Report) Print(w io.Writer, verbose bool) {
 		_, _ = fmt.Fprintln(w, ui.RenderCategory("Other"))
 		for _, check := range otherChecks {
 			r.printCheck(w, check, verbose)
-			if check.Status != StatusOK {
+			if check.Status != StatusOK && check.Suppressed == "" {
 				warnings = append(warnings, check)
 			}
 		}
@@ -227,12 +271,18 @@ func (r *Report) printCheck(w io.Writer, check *CheckResult, verbose bool) {
 	case StatusError:
 		statusIcon = ui.RenderFailIcon()
 	}
+	if check.Suppressed != "" {
+		statusIcon = ui.RenderSkipIcon()
+	}
 
 	// Print check line: icon + name + muted message
 	_, _ = fmt.Fprintf(w, "  %s  %s", statusIcon, check.Name)
 	if check.Message != "" {
 		_, _ = fmt.Fprintf(w, "%s", ui.RenderMuted(" "+check.Message))
 	}
+	if check.Suppressed != "" {
+		_, _ = fmt.Fprintf(w, "%s", ui.RenderMuted(" [suppressed: "+check.Suppressed+"]"))
+	}
 	_, _ = fmt.Fprintln(w)
 
 	// Print details in verbose mode or for non-OK results (with tree connector)
@@ -250,6 +300,9 @@ func (r *Report) printSummary(w io.Writer) {
 		ui.RenderWarnIcon(), r.Summary.Warnings,
 		ui.RenderFailIcon(), r.Summary.Errors,
 	)
+	if r.Summary.Suppressed > 0 {
+		summary += fmt.Sprintf("  %s %d suppressed", ui.RenderSkipIcon(), r.Summary.Suppressed)
+	}
 	_, _ = fmt.Fprintln(w, summary)
 }
 
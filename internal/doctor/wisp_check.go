@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
@@ -145,3 +146,13 @@ func (c *WispGCCheck) Fix(ctx *CheckContext) error {
 
 	return lastErr
 }
+
+// PlanFix lists the rigs Fix would garbage-collect.
+func (c *WispGCCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for rigName, count := range c.abandonedRigs {
+		changes = append(changes, fmt.Sprintf("Run 'bd --no-daemon mol wisp gc' in %s (%d abandoned wisp(s))", rigName, count))
+	}
+	sort.Strings(changes)
+	return changes
+}
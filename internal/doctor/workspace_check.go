@@ -178,6 +178,11 @@ func (c *RigsRegistryExistsCheck) Fix(ctx *CheckContext) error {
 	return os.WriteFile(rigsPath, data, 0644)
 }
 
+// PlanFix describes creating the registry.
+func (c *RigsRegistryExistsCheck) PlanFix(ctx *CheckContext) []string {
+	return []string{"Create " + filepath.Join(ctx.TownRoot, "mayor", "rigs.json") + " with no rigs"}
+}
+
 // RigsRegistryValidCheck verifies mayor/rigs.json is valid and rigs exist.
 type RigsRegistryValidCheck struct {
 	FixableCheck
@@ -313,6 +318,15 @@ func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
 	return os.WriteFile(rigsPath, newData, 0644)
 }
 
+// PlanFix lists the rigs Fix would remove from the registry.
+func (c *RigsRegistryValidCheck) PlanFix(ctx *CheckContext) []string {
+	var changes []string
+	for _, rig := range c.missingRigs {
+		changes = append(changes, "Remove rig "+rig+" from mayor/rigs.json")
+	}
+	return changes
+}
+
 // MayorExistsCheck verifies the mayor/ directory structure.
 type MayorExistsCheck struct {
 	BaseCheck
@@ -135,3 +135,8 @@ func (c *ZombieSessionCheck) Fix(ctx *CheckContext) error {
 
 	return lastErr
 }
+
+// PlanFix lists the zombie sessions Fix would kill.
+func (c *ZombieSessionCheck) PlanFix(ctx *CheckContext) []string {
+	return planSessionKills(c.zombieSessions)
+}
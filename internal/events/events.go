@@ -1,7 +1,8 @@
 // Package events provides event logging for the gt activity feed.
 //
 // Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// curated by the feed daemon into ~/.feed.jsonl (user-facing). Each event
+// is hash-chained to the one before it so gt audit verify can detect edits.
 package events
 
 import (
@@ -12,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/auditchain"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -23,6 +25,11 @@ type Event struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 	Visibility string                 `json:"visibility"`
+
+	// Audit chain link, appended when the event is written (see auditchain)
+	Seq  int64  `json:"seq,omitempty"`
+	Hash string `json:"hash,omitempty"`
+	Sig  string `json:"sig,omitempty"`
 }
 
 // Visibility levels for events.
@@ -112,24 +119,21 @@ func write(event Event) error {
 
 	eventsPath := filepath.Join(townRoot, EventsFile)
 
-	// Marshal event to JSON
+	// Marshal event to JSON (without a link; Append chains it)
+	event.Seq, event.Hash, event.Sig = 0, "", ""
 	data, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("marshaling event: %w", err)
 	}
-	data = append(data, '\n')
+
+	// Sign when the town has an audit key; otherwise just chain
+	key, _ := auditchain.LoadKey(townRoot)
 
 	// Append to file with proper locking
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
-	if err != nil {
-		return fmt.Errorf("opening events file: %w", err)
-	}
-	defer f.Close()
-
-	if _, err := f.Write(data); err != nil {
+	if err := auditchain.Append(townRoot, eventsPath, 0644, auditchain.JSON, data, key); err != nil {
 		return fmt.Errorf("writing event: %w", err)
 	}
 
@@ -0,0 +1,385 @@
+// Package forecast estimates when convoys will land from historical
+// throughput.
+//
+// Each rig's cycle time (sling → merged) is learned from the sling events in
+// the town's .events.jsonl and the close times of the rig's merge requests,
+// falling back to gt done events for work merged outside the queue. A
+// forecast places a convoy's remaining issues on the rig's polecat lanes
+// (max_polecats, less lanes busy with other work), adds the merge-queue
+// backlog, and repeats this at the fast and slow ends of the cycle-time
+// distribution to give a confidence band.
+package forecast
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryWindow is how far back cycle times are learned from.
+const HistoryWindow = 30 * 24 * time.Hour
+
+// Quantiles of the cycle-time distribution used for the forecast band.
+const (
+	bandLow  = 0.2
+	bandMid  = 0.5
+	bandHigh = 0.8
+)
+
+// Confidence levels, by the number of cycle samples behind a forecast.
+const (
+	ConfidenceHigh   = "high"   // 20+ samples from the rigs involved
+	ConfidenceMedium = "medium" // 5+ samples
+	ConfidenceLow    = "low"    // fewer, or borrowed from other rigs
+)
+
+// Deadline status of a forecast.
+const (
+	OnTrack  = "on track"
+	AtRisk   = "at risk"  // the slow end of the band is past the deadline
+	Slipping = "slipping" // the expected ETA is past the deadline
+)
+
+// RigStats is the throughput learned for one rig.
+type RigStats struct {
+	Rig        string
+	Cycles     []time.Duration // sling → merged (or done), sorted
+	MergeGap   time.Duration   // median time between consecutive merges
+	Capacity   int             // polecat lanes (max_polecats)
+	Active     int             // polecats currently working
+	QueueDepth int             // open merge requests
+}
+
+// Model holds the history a forecast is computed from.
+type Model struct {
+	Now  time.Time
+	Rigs map[string]*RigStats
+
+	slungAt   map[string]time.Time // issue -> latest sling
+	slungTo   map[string]string    // issue -> rig of latest sling
+	prefixRig map[string]string    // bead prefix ("gt-") -> rig
+}
+
+// NewModel returns an empty model. Load fills one from a town.
+func NewModel(now time.Time) *Model {
+	return &Model{
+		Now:       now,
+		Rigs:      make(map[string]*RigStats),
+		slungAt:   make(map[string]time.Time),
+		slungTo:   make(map[string]string),
+		prefixRig: make(map[string]string),
+	}
+}
+
+// rig returns the stats for a rig, creating them.
+func (m *Model) rig(name string) *RigStats {
+	s, ok := m.Rigs[name]
+	if !ok {
+		s = &RigStats{Rig: name, Capacity: 1}
+		m.Rigs[name] = s
+	}
+	return s
+}
+
+// Sling is a sling event: an issue dispatched to a rig.
+type Sling struct {
+	Issue string
+	Rig   string
+	At    time.Time
+}
+
+// event is the subset of an .events.jsonl line the forecaster reads.
+type event struct {
+	Timestamp string                 `json:"ts"`
+	Type      string                 `json:"type"`
+	Actor     string                 `json:"actor"`
+	Payload   map[string]interface{} `json:"payload"`
+}
+
+// ReadEvents returns the sling and done events in an events log, keyed by
+// issue. Later events for an issue replace earlier ones. Events older than
+// since are skipped.
+func ReadEvents(r io.Reader, since time.Time) (slings map[string]Sling, done map[string]time.Time, err error) {
+	slings = make(map[string]Sling)
+	done = make(map[string]time.Time)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e event
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		if e.Type != "sling" && e.Type != "done" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || at.Before(since) {
+			continue
+		}
+		issue, _ := e.Payload["bead"].(string)
+		if issue == "" {
+			continue
+		}
+		if e.Type == "sling" {
+			target, _ := e.Payload["target"].(string)
+			slings[issue] = Sling{Issue: issue, Rig: rigOf(target), At: at}
+		} else {
+			done[issue] = at
+		}
+	}
+	return slings, done, scanner.Err()
+}
+
+// rigOf returns the rig of an agent address such as gastown/polecats/toast.
+// Town-level agents (mayor/, deacon/dogs/x) have no rig.
+func rigOf(addr string) string {
+	name, _, _ := strings.Cut(strings.TrimSuffix(addr, "/"), "/")
+	switch name {
+	case "", "mayor", "deacon":
+		return ""
+	}
+	return name
+}
+
+// AddHistory learns cycle times from sling events and the times their issues
+// were merged (or, failing that, reported done).
+func (m *Model) AddHistory(slings map[string]Sling, done, merged map[string]time.Time) {
+	for issue, s := range slings {
+		if s.Rig == "" {
+			continue
+		}
+		m.slungAt[issue] = s.At
+		m.slungTo[issue] = s.Rig
+		end, ok := merged[issue]
+		if !ok {
+			end, ok = done[issue]
+		}
+		if !ok || !end.After(s.At) {
+			continue
+		}
+		st := m.rig(s.Rig)
+		st.Cycles = append(st.Cycles, end.Sub(s.At))
+	}
+	for _, st := range m.Rigs {
+		sort.Slice(st.Cycles, func(i, j int) bool { return st.Cycles[i] < st.Cycles[j] })
+	}
+}
+
+// SetMerges learns a rig's merge rate from the close times of its merge
+// requests.
+func (m *Model) SetMerges(rig string, closes []time.Time) {
+	if len(closes) < 2 {
+		return
+	}
+	sorted := append([]time.Time(nil), closes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	gaps := make([]time.Duration, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		gaps = append(gaps, sorted[i].Sub(sorted[i-1]))
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	m.rig(rig).MergeGap = quantile(gaps, bandMid)
+}
+
+// SetCapacity records a rig's polecat lanes, busy polecats and open merge
+// requests.
+func (m *Model) SetCapacity(rig string, capacity, active, queueDepth int) {
+	st := m.rig(rig)
+	st.Capacity = max(1, capacity)
+	st.Active = active
+	st.QueueDepth = queueDepth
+}
+
+// SetPrefix maps a bead prefix (e.g. "gt-") to its rig, for issues that have
+// never been slung.
+func (m *Model) SetPrefix(prefix, rig string) {
+	m.prefixRig[prefix] = rig
+}
+
+// RigFor returns the rig an issue will be worked in: where it was last slung,
+// else the rig owning its prefix.
+func (m *Model) RigFor(issueID string) string {
+	if rig, ok := m.slungTo[issueID]; ok {
+		return rig
+	}
+	if i := strings.Index(issueID, "-"); i > 0 {
+		return m.prefixRig[issueID[:i+1]]
+	}
+	return ""
+}
+
+// Item is a convoy's tracked issue.
+type Item struct {
+	ID     string
+	Status string
+}
+
+// Forecast is a convoy's estimated landing time.
+type Forecast struct {
+	ETA        time.Time `json:"eta"`
+	Low        time.Time `json:"low"`  // fast end of the band
+	High       time.Time `json:"high"` // slow end of the band
+	Confidence string    `json:"confidence"`
+	Remaining  int       `json:"remaining"`
+	Samples    int       `json:"samples"`
+}
+
+// DeadlineStatus compares the forecast with a deadline.
+func (f *Forecast) DeadlineStatus(deadline time.Time) string {
+	switch {
+	case deadline.IsZero() || !f.High.After(deadline):
+		return OnTrack
+	case f.ETA.After(deadline):
+		return Slipping
+	default:
+		return AtRisk
+	}
+}
+
+// Schedule returns a convoy's deadline status, or "" when it has no
+// deadline or nothing left to land. A deadline that has passed with work
+// remaining is slipping even without a forecast.
+func Schedule(f *Forecast, deadline time.Time, remaining int, now time.Time) string {
+	if deadline.IsZero() || remaining == 0 {
+		return ""
+	}
+	if now.After(deadline) {
+		return Slipping
+	}
+	if f == nil {
+		return ""
+	}
+	return f.DeadlineStatus(deadline)
+}
+
+// Forecast estimates when all items will have landed. It returns nil when
+// nothing remains or there is no history to forecast from.
+func (m *Model) Forecast(items []Item) *Forecast {
+	byRig := make(map[string][]Item)
+	remaining := 0
+	for _, it := range items {
+		if isLanded(it.Status) {
+			continue
+		}
+		remaining++
+		byRig[m.RigFor(it.ID)] = append(byRig[m.RigFor(it.ID)], it)
+	}
+	if remaining == 0 {
+		return nil
+	}
+
+	town := m.townCycles()
+	if len(town) == 0 {
+		return nil
+	}
+
+	f := &Forecast{Remaining: remaining, Confidence: ConfidenceHigh}
+	var low, mid, high time.Duration
+	for rig, rigItems := range byRig {
+		st := m.Rigs[rig]
+		if st == nil {
+			st = &RigStats{Rig: rig, Capacity: 1}
+		}
+		cycles := st.Cycles
+		if len(cycles) < 3 {
+			// Too little history in this rig; borrow the town's
+			cycles = town
+			f.Confidence = ConfidenceLow
+		}
+		f.Samples += len(cycles)
+		low = max(low, m.simulate(st, rigItems, quantile(cycles, bandLow)))
+		mid = max(mid, m.simulate(st, rigItems, quantile(cycles, bandMid)))
+		high = max(high, m.simulate(st, rigItems, quantile(cycles, bandHigh)))
+	}
+	if f.Confidence == ConfidenceHigh && f.Samples < 20 {
+		f.Confidence = ConfidenceMedium
+		if f.Samples < 5 {
+			f.Confidence = ConfidenceLow
+		}
+	}
+	f.Low = m.Now.Add(low)
+	f.ETA = m.Now.Add(mid)
+	f.High = m.Now.Add(high)
+	return f
+}
+
+// simulate returns how long a rig takes to land items when each takes cycle
+// from sling to merge.
+func (m *Model) simulate(st *RigStats, items []Item, cycle time.Duration) time.Duration {
+	lanes := make([]time.Duration, max(1, st.Capacity))
+	// Polecats busy with other work hold a lane for about half a cycle
+	inProgress := 0
+	for _, it := range items {
+		if isStarted(it.Status) {
+			inProgress++
+		}
+	}
+	for i := 0; i < min(st.Active-inProgress, len(lanes)-1); i++ {
+		lanes[i] = cycle / 2
+	}
+
+	// Started items first, with whatever is left of their cycle
+	sorted := append([]Item(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return isStarted(sorted[i].Status) && !isStarted(sorted[j].Status)
+	})
+	var finish time.Duration
+	for _, it := range sorted {
+		need := cycle
+		if isStarted(it.Status) {
+			need = cycle / 2
+			if at, ok := m.slungAt[it.ID]; ok {
+				need = max(cycle-m.Now.Sub(at), cycle/10)
+			}
+		}
+		lane := 0
+		for i := range lanes {
+			if lanes[i] < lanes[lane] {
+				lane = i
+			}
+		}
+		lanes[lane] += need
+		finish = max(finish, lanes[lane])
+	}
+
+	// The refinery works through the existing queue before this work
+	return finish + time.Duration(st.QueueDepth)*st.MergeGap
+}
+
+// townCycles returns every rig's cycle times, sorted.
+func (m *Model) townCycles() []time.Duration {
+	var all []time.Duration
+	for _, st := range m.Rigs {
+		all = append(all, st.Cycles...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	return all
+}
+
+// quantile returns the q-th quantile of sorted durations.
+func quantile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	frac := pos - float64(lo)
+	return sorted[lo] + time.Duration(frac*float64(sorted[hi]-sorted[lo]))
+}
+
+func isLanded(status string) bool {
+	return status == "closed" || status == "tombstone"
+}
+
+func isStarted(status string) bool {
+	switch status {
+	case "in_progress", "hooked", "pinned":
+		return true
+	}
+	return false
+}
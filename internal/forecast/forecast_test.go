@@ -0,0 +1,210 @@
+package forecast
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+// modelWithCycles returns a model whose rig has the given cycle times.
+func modelWithCycles(rig string, capacity int, cycles ...time.Duration) *Model {
+	m := NewModel(now)
+	slings := make(map[string]Sling)
+	merged := make(map[string]time.Time)
+	for i, c := range cycles {
+		id := rig + "-old" + string(rune('a'+i))
+		at := now.Add(-48 * time.Hour)
+		slings[id] = Sling{Issue: id, Rig: rig, At: at}
+		merged[id] = at.Add(c)
+	}
+	m.AddHistory(slings, nil, merged)
+	m.SetPrefix("gt-", rig)
+	m.SetCapacity(rig, capacity, 0, 0)
+	return m
+}
+
+func TestReadEvents(t *testing.T) {
+	log := strings.Join([]string{
+		`{"ts":"2026-10-17T10:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-1","target":"gastown/polecats/toast"}}`,
+		`{"ts":"2026-10-17T14:00:00Z","type":"done","actor":"gastown/polecats/toast","payload":{"bead":"gt-1","branch":"polecat/toast"}}`,
+		`{"ts":"2026-10-17T11:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"hq-2","target":"mayor/"}}`,
+		`{"ts":"2026-08-01T10:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-old","target":"gastown"}}`,
+		`{"ts":"2026-10-17T12:00:00Z","type":"nudge","actor":"mayor","payload":{"target":"gastown/witness"}}`,
+		`not json`,
+	}, "\n")
+
+	slings, done, err := ReadEvents(strings.NewReader(log), now.Add(-HistoryWindow))
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if s := slings["gt-1"]; s.Rig != "gastown" || s.At.Hour() != 10 {
+		t.Errorf("sling gt-1 = %+v, want gastown at 10:00", s)
+	}
+	if s := slings["hq-2"]; s.Rig != "" {
+		t.Errorf("sling to mayor has rig %q, want none", s.Rig)
+	}
+	if _, ok := slings["gt-old"]; ok {
+		t.Error("sling older than the window should be skipped")
+	}
+	if d := done["gt-1"]; d.Hour() != 14 {
+		t.Errorf("done gt-1 = %v, want 14:00", d)
+	}
+}
+
+func TestAddHistory_PrefersMergeTime(t *testing.T) {
+	m := NewModel(now)
+	at := now.Add(-10 * time.Hour)
+	m.AddHistory(
+		map[string]Sling{"gt-1": {Issue: "gt-1", Rig: "gastown", At: at}},
+		map[string]time.Time{"gt-1": at.Add(2 * time.Hour)},
+		map[string]time.Time{"gt-1": at.Add(5 * time.Hour)},
+	)
+	if got := m.Rigs["gastown"].Cycles; len(got) != 1 || got[0] != 5*time.Hour {
+		t.Errorf("cycles = %v, want [5h] (sling → merged)", got)
+	}
+	if rig := m.RigFor("gt-1"); rig != "gastown" {
+		t.Errorf("RigFor(gt-1) = %q, want gastown", rig)
+	}
+}
+
+func TestForecast_Band(t *testing.T) {
+	var cycles []time.Duration
+	for i := 1; i <= 10; i++ {
+		cycles = append(cycles, time.Duration(i)*time.Hour)
+	}
+	m := modelWithCycles("gastown", 2, cycles...)
+
+	f := m.Forecast([]Item{{ID: "gt-a", Status: "open"}, {ID: "gt-b", Status: "open"}, {ID: "gt-c", Status: "closed"}})
+	if f == nil {
+		t.Fatal("Forecast returned nil with history")
+	}
+	if f.Remaining != 2 {
+		t.Errorf("Remaining = %d, want 2", f.Remaining)
+	}
+	if !f.Low.Before(f.ETA) || !f.ETA.Before(f.High) {
+		t.Errorf("band out of order: low %v, eta %v, high %v", f.Low, f.ETA, f.High)
+	}
+	// Two lanes, two items: one median cycle
+	if got := f.ETA.Sub(now); got != quantile(m.Rigs["gastown"].Cycles, bandMid) {
+		t.Errorf("ETA in %v, want one median cycle", got)
+	}
+	if f.Confidence != ConfidenceMedium {
+		t.Errorf("Confidence = %q with %d samples, want medium", f.Confidence, f.Samples)
+	}
+}
+
+func TestForecast_CapacityAndQueue(t *testing.T) {
+	items := []Item{{ID: "gt-a", Status: "open"}, {ID: "gt-b", Status: "open"}}
+
+	wide := modelWithCycles("gastown", 2, 4*time.Hour, 4*time.Hour, 4*time.Hour)
+	narrow := modelWithCycles("gastown", 1, 4*time.Hour, 4*time.Hour, 4*time.Hour)
+	if w, n := wide.Forecast(items).ETA, narrow.Forecast(items).ETA; !n.After(w) {
+		t.Errorf("one lane (%v) should land later than two (%v)", n, w)
+	}
+
+	queued := modelWithCycles("gastown", 2, 4*time.Hour, 4*time.Hour, 4*time.Hour)
+	queued.SetMerges("gastown", []time.Time{now.Add(-3 * time.Hour), now.Add(-2 * time.Hour), now.Add(-time.Hour)})
+	queued.SetCapacity("gastown", 2, 0, 3)
+	if got := queued.Forecast(items).ETA.Sub(wide.Forecast(items).ETA); got != 3*time.Hour {
+		t.Errorf("3 queued merges an hour apart added %v, want 3h", got)
+	}
+}
+
+func TestForecast_NoHistory(t *testing.T) {
+	m := NewModel(now)
+	if f := m.Forecast([]Item{{ID: "gt-a", Status: "open"}}); f != nil {
+		t.Errorf("Forecast without history = %+v, want nil", f)
+	}
+	m = modelWithCycles("gastown", 1, time.Hour, time.Hour, time.Hour)
+	if f := m.Forecast([]Item{{ID: "gt-a", Status: "closed"}}); f != nil {
+		t.Errorf("Forecast of a landed convoy = %+v, want nil", f)
+	}
+	// An unknown rig borrows the town's history at low confidence
+	if f := m.Forecast([]Item{{ID: "bd-a", Status: "open"}}); f == nil || f.Confidence != ConfidenceLow {
+		t.Errorf("Forecast for unknown rig = %+v, want low confidence", f)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	f := &Forecast{Low: now.Add(2 * time.Hour), ETA: now.Add(4 * time.Hour), High: now.Add(8 * time.Hour)}
+	tests := []struct {
+		name      string
+		f         *Forecast
+		deadline  time.Time
+		remaining int
+		want      string
+	}{
+		{"no deadline", f, time.Time{}, 2, ""},
+		{"landed", f, now.Add(time.Hour), 0, ""},
+		{"comfortable", f, now.Add(10 * time.Hour), 2, OnTrack},
+		{"inside band", f, now.Add(6 * time.Hour), 2, AtRisk},
+		{"before ETA", f, now.Add(3 * time.Hour), 2, Slipping},
+		{"passed without forecast", nil, now.Add(-time.Hour), 2, Slipping},
+		{"no forecast", nil, now.Add(time.Hour), 2, ""},
+	}
+	for _, tt := range tests {
+		if got := Schedule(tt.f, tt.deadline, tt.remaining, now); got != tt.want {
+			t.Errorf("%s: Schedule = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseDeadline(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-11-01T09:00:00Z", time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)},
+		{"2026-11-01", time.Date(2026, 11, 1, 23, 59, 59, 0, time.UTC)},
+		{"3d", now.Add(72 * time.Hour)},
+		{"36h", now.Add(36 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDeadline(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseDeadline(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "soon", "-2d", "0h"} {
+		if _, err := ParseDeadline(bad, now); err == nil {
+			t.Errorf("ParseDeadline(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSetDeadline(t *testing.T) {
+	deadline := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+	desc := "Convoy tracking 2 issues\nOwner: mayor/"
+
+	withDeadline := SetDeadline(desc, deadline)
+	if got := DeadlineFromDescription(withDeadline); !got.Equal(deadline) {
+		t.Errorf("DeadlineFromDescription = %v, want %v", got, deadline)
+	}
+	moved := SetDeadline(withDeadline, deadline.Add(24*time.Hour))
+	if strings.Count(moved, "Deadline:") != 1 {
+		t.Errorf("moving the deadline should replace the line:\n%s", moved)
+	}
+	if cleared := SetDeadline(moved, time.Time{}); cleared != desc {
+		t.Errorf("clearing the deadline = %q, want %q", cleared, desc)
+	}
+}
+
+func TestFormatSpan(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{10 * time.Second, "1m"},
+		{45 * time.Minute, "45m"},
+		{6*time.Hour + 20*time.Minute, "6h"},
+		{47 * time.Hour, "47h"},
+		{80 * time.Hour, "3d"},
+	}
+	for _, tt := range tests {
+		if got := formatSpan(tt.d); got != tt.want {
+			t.Errorf("formatSpan(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
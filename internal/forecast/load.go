@@ -0,0 +1,166 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// Load builds a model from a town's event log and the merge queues,
+// capacity and polecats of its rigs. Missing sources just leave gaps in the
+// history.
+func Load(townRoot string) (*Model, error) {
+	m := NewModel(time.Now())
+	since := m.Now.Add(-HistoryWindow)
+
+	slings := map[string]Sling{}
+	done := map[string]time.Time{}
+	if f, err := os.Open(filepath.Join(townRoot, events.EventsFile)); err == nil { //nolint:gosec // G304: path is constructed internally
+		slings, done, err = ReadEvents(f, since)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading events: %w", err)
+		}
+	}
+
+	merged := make(map[string]time.Time)
+	rigs, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err == nil {
+		for name := range rigs.Rigs {
+			loadRig(m, townRoot, name, since, merged)
+		}
+	}
+
+	m.AddHistory(slings, done, merged)
+	return m, nil
+}
+
+// loadRig reads a rig's merge requests and capacity into the model, and
+// records when each source issue was merged.
+func loadRig(m *Model, townRoot, name string, since time.Time, merged map[string]time.Time) {
+	rigPath := filepath.Join(townRoot, name)
+	m.SetPrefix(beads.GetPrefixForRig(townRoot, name)+"-", name)
+
+	var closes []time.Time
+	depth := 0
+	mrs, _ := beads.New(rigPath).List(beads.ListOptions{Type: "merge-request", Status: "all", Priority: -1})
+	for _, mr := range mrs {
+		if mr.Status != "closed" {
+			if mr.Status != "tombstone" {
+				depth++
+			}
+			continue
+		}
+		closedAt, err := time.Parse(time.RFC3339, mr.ClosedAt)
+		if err != nil || closedAt.Before(since) {
+			continue
+		}
+		closes = append(closes, closedAt)
+		if fields := beads.ParseMRFields(mr); fields != nil && fields.SourceIssue != "" {
+			merged[fields.SourceIssue] = closedAt
+		}
+	}
+	m.SetMerges(name, closes)
+
+	r := &rig.Rig{Name: name, Path: rigPath}
+	m.SetCapacity(name, r.GetIntConfig("max_polecats"), countPolecats(rigPath), depth)
+}
+
+// countPolecats returns the number of polecats in a rig (warm pool slots
+// live in a dot directory and are skipped).
+func countPolecats(rigPath string) int {
+	entries, err := os.ReadDir(filepath.Join(rigPath, "polecats"))
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			n++
+		}
+	}
+	return n
+}
+
+// ParseDeadline parses a convoy deadline: an RFC 3339 time, a date
+// (2006-01-02, end of that day in local time), or a duration from now such
+// as 36h or 3d.
+func ParseDeadline(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return now.Add(time.Duration(n) * 24 * time.Hour), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid deadline %q (use RFC 3339, YYYY-MM-DD, or a duration like 36h or 3d)", s)
+}
+
+// DeadlineFromDescription returns the deadline recorded in a convoy's
+// description ("Deadline: <RFC 3339>"), or the zero time.
+func DeadlineFromDescription(desc string) time.Time {
+	for _, line := range strings.Split(desc, "\n") {
+		if v, ok := strings.CutPrefix(line, "Deadline: "); ok {
+			if t, err := time.Parse(time.RFC3339, strings.TrimSpace(v)); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// SetDeadline returns desc with its deadline line replaced by (or set to)
+// deadline. A zero deadline removes the line.
+func SetDeadline(desc string, deadline time.Time) string {
+	var lines []string
+	for _, line := range strings.Split(desc, "\n") {
+		if !strings.HasPrefix(line, "Deadline: ") {
+			lines = append(lines, line)
+		}
+	}
+	if !deadline.IsZero() {
+		lines = append(lines, "Deadline: "+deadline.UTC().Format(time.RFC3339))
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// FormatETA renders a forecast for one line of output, e.g.
+// "~6h (4h–9h, medium confidence)".
+func (f *Forecast) FormatETA(now time.Time) string {
+	return fmt.Sprintf("~%s (%s–%s, %s confidence)",
+		formatSpan(f.ETA.Sub(now)), formatSpan(f.Low.Sub(now)), formatSpan(f.High.Sub(now)), f.Confidence)
+}
+
+// FormatShort renders a forecast compactly, e.g. "~6h".
+func (f *Forecast) FormatShort(now time.Time) string {
+	return "~" + formatSpan(f.ETA.Sub(now))
+}
+
+// formatSpan renders a duration at a single useful unit.
+func formatSpan(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", max(1, int(d.Round(time.Minute).Minutes())))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Round(time.Hour).Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(math.Round(d.Hours()/24)))
+	}
+}
@@ -0,0 +1,308 @@
+// Package forge drives pull requests on a code forge for refineries whose
+// target branch only accepts changes through the forge (protected branches,
+// required reviews or checks).
+//
+// The refinery pushes the polecat branch, opens a pull request carrying the
+// bead context, polls the head commit's checks and merges through the
+// forge's REST API. GitHub, Gitea and GitLab are supported.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Forge types.
+const (
+	TypeGitHub = "github"
+	TypeGitea  = "gitea"
+	TypeGitLab = "gitlab"
+)
+
+// Pull request states.
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+	StateMerged = "merged"
+)
+
+// Check states, combined over every check reported on a commit.
+// ChecksNone means nothing has reported on the commit: either it has no CI,
+// or CI hasn't registered its checks yet (common right after a push).
+const (
+	ChecksPending = "pending"
+	ChecksSuccess = "success"
+	ChecksFailure = "failure"
+	ChecksNone    = "none"
+)
+
+// Merge methods.
+const (
+	MethodMerge  = "merge"
+	MethodSquash = "squash"
+	MethodRebase = "rebase"
+)
+
+// ErrNotMergeable is returned by Merge when the forge refuses the merge:
+// conflicts, failing required checks, missing reviews or a moved head.
+var ErrNotMergeable = errors.New("pull request is not mergeable")
+
+// PullRequest is a pull request (a merge request on GitLab).
+type PullRequest struct {
+	Number      int    `json:"number"`
+	URL         string `json:"url"`
+	State       string `json:"state"`
+	Head        string `json:"head"`
+	Base        string `json:"base"`
+	HeadSHA     string `json:"head_sha"`
+	Conflicted  bool   `json:"conflicted"` // the forge reports merge conflicts
+	MergeCommit string `json:"merge_commit,omitempty"`
+}
+
+// NewPullRequest describes a pull request to open.
+type NewPullRequest struct {
+	Head  string // source branch
+	Base  string // target branch
+	Title string
+	Body  string
+}
+
+// Forge opens, inspects and merges pull requests in one repository.
+type Forge interface {
+	// FindOpen returns the open pull request from head into base, or nil.
+	FindOpen(ctx context.Context, head, base string) (*PullRequest, error)
+	// Open opens a pull request.
+	Open(ctx context.Context, pr NewPullRequest) (*PullRequest, error)
+	// Get returns a pull request by number.
+	Get(ctx context.Context, number int) (*PullRequest, error)
+	// Checks returns the combined state of the checks on the pull
+	// request's head commit, or ChecksNone if no checks are reported.
+	Checks(ctx context.Context, pr *PullRequest) (string, error)
+	// Merge merges the pull request and returns the resulting commit.
+	Merge(ctx context.Context, pr *PullRequest, message string) (string, error)
+}
+
+// Config selects and configures a forge.
+type Config struct {
+	Type        string // TypeGitHub, TypeGitea or TypeGitLab
+	APIURL      string // REST API base URL
+	Repo        string // owner/name (GitLab: namespace/project)
+	Token       string
+	MergeMethod string // MethodMerge (default), MethodSquash or MethodRebase
+}
+
+// New returns the forge for cfg. client may be nil.
+func New(cfg Config, client *http.Client) (Forge, error) {
+	if cfg.Repo == "" {
+		return nil, errors.New("forge repo not set")
+	}
+	if cfg.APIURL == "" {
+		return nil, errors.New("forge API URL not set")
+	}
+	switch cfg.MergeMethod {
+	case "":
+		cfg.MergeMethod = MethodMerge
+	case MethodMerge, MethodSquash, MethodRebase:
+	default:
+		return nil, fmt.Errorf("unknown merge method %q", cfg.MergeMethod)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	api := &apiClient{base: strings.TrimRight(cfg.APIURL, "/"), http: client}
+
+	switch cfg.Type {
+	case TypeGitHub:
+		api.header = func(h http.Header) {
+			h.Set("Accept", "application/vnd.github+json")
+			if cfg.Token != "" {
+				h.Set("Authorization", "Bearer "+cfg.Token)
+			}
+		}
+		return &gitHub{api: api, repo: cfg.Repo, method: cfg.MergeMethod}, nil
+	case TypeGitea:
+		api.header = func(h http.Header) {
+			if cfg.Token != "" {
+				h.Set("Authorization", "token "+cfg.Token)
+			}
+		}
+		return &gitea{api: api, repo: cfg.Repo, method: cfg.MergeMethod}, nil
+	case TypeGitLab:
+		api.header = func(h http.Header) {
+			if cfg.Token != "" {
+				h.Set("PRIVATE-TOKEN", cfg.Token)
+			}
+		}
+		return &gitLab{api: api, project: url.PathEscape(cfg.Repo), method: cfg.MergeMethod}, nil
+	default:
+		return nil, fmt.Errorf("unknown forge type %q (want %s, %s or %s)", cfg.Type, TypeGitHub, TypeGitea, TypeGitLab)
+	}
+}
+
+// ParseRemote splits a git remote URL into its host and repository path.
+// It accepts https://host/owner/repo(.git), ssh://git@host/owner/repo.git
+// and git@host:owner/repo.git.
+func ParseRemote(remote string) (host, repo string, ok bool) {
+	remote = strings.TrimSpace(remote)
+	var p string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, p = u.Hostname(), u.Path
+	} else if at := strings.Index(remote, "@"); at >= 0 && strings.Contains(remote[at:], ":") {
+		// scp-like syntax
+		host, p, _ = strings.Cut(remote[at+1:], ":")
+	} else {
+		return "", "", false
+	}
+	repo = strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+	if host == "" || !strings.Contains(repo, "/") {
+		return "", "", false
+	}
+	return host, repo, true
+}
+
+// Detect guesses the forge type from a remote's host. Self-hosted forges
+// on other hosts need their type configured.
+func Detect(host string) string {
+	switch {
+	case host == "github.com" || strings.HasPrefix(host, "github."):
+		return TypeGitHub
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return TypeGitLab
+	case host == "codeberg.org" || strings.HasPrefix(host, "gitea."):
+		return TypeGitea
+	}
+	return ""
+}
+
+// DefaultAPIURL returns the usual REST API base URL of a forge on host.
+func DefaultAPIURL(forgeType, host string) string {
+	switch forgeType {
+	case TypeGitHub:
+		if host == "github.com" {
+			return "https://api.github.com"
+		}
+		return "https://" + host + "/api/v3"
+	case TypeGitea:
+		return "https://" + host + "/api/v1"
+	case TypeGitLab:
+		return "https://" + host + "/api/v4"
+	}
+	return ""
+}
+
+// TokenFromEnv reads the forge token from tokenEnv, or from the forge's
+// conventional variables when tokenEnv is empty.
+func TokenFromEnv(forgeType, tokenEnv string) string {
+	if tokenEnv != "" {
+		return os.Getenv(tokenEnv)
+	}
+	var names []string
+	switch forgeType {
+	case TypeGitHub:
+		names = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	case TypeGitea:
+		names = []string{"GITEA_TOKEN"}
+	case TypeGitLab:
+		names = []string{"GITLAB_TOKEN"}
+	}
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// APIError is an unexpected response from a forge.
+type APIError struct {
+	Method  string
+	Path    string
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.Status, e.Message)
+	}
+	return fmt.Sprintf("%s %s: %d", e.Method, e.Path, e.Status)
+}
+
+// apiClient makes JSON requests to a forge's REST API.
+type apiClient struct {
+	base   string
+	http   *http.Client
+	header func(http.Header)
+}
+
+// do sends a request with an optional JSON body and decodes a JSON response
+// into out (if non-nil). Non-2xx responses are returned as *APIError.
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.header(req.Header)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{Method: method, Path: path, Status: resp.StatusCode}
+		var msg struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &msg) == nil {
+			apiErr.Message = msg.Message
+		}
+		return apiErr
+	}
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
+
+// statusIs reports whether err is an *APIError with one of the statuses.
+func statusIs(err error, statuses ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, s := range statuses {
+		if apiErr.Status == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/forge/forgetest"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote   string
+		wantHost string
+		wantRepo string
+		wantOK   bool
+	}{
+		{"https://github.com/acme/widgets.git", "github.com", "acme/widgets", true},
+		{"https://github.com/acme/widgets", "github.com", "acme/widgets", true},
+		{"git@github.com:acme/widgets.git", "github.com", "acme/widgets", true},
+		{"ssh://git@gitlab.example.com:2222/group/sub/proj.git", "gitlab.example.com", "group/sub/proj", true},
+		{"https://user:pw@codeberg.org/acme/widgets/", "codeberg.org", "acme/widgets", true},
+		{"/srv/git/widgets.git", "", "", false},
+		{"https://github.com/widgets", "", "", false},
+	}
+	for _, tt := range tests {
+		host, repo, ok := ParseRemote(tt.remote)
+		if host != tt.wantHost || repo != tt.wantRepo || ok != tt.wantOK {
+			t.Errorf("ParseRemote(%q) = %q, %q, %v; want %q, %q, %v",
+				tt.remote, host, repo, ok, tt.wantHost, tt.wantRepo, tt.wantOK)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		"github.com":         TypeGitHub,
+		"github.example.com": TypeGitHub,
+		"gitlab.com":         TypeGitLab,
+		"codeberg.org":       TypeGitea,
+		"git.example.com":    "",
+	}
+	for host, want := range tests {
+		if got := Detect(host); got != want {
+			t.Errorf("Detect(%q) = %q, want %q", host, got, want)
+		}
+	}
+	if got := DefaultAPIURL(TypeGitHub, "github.com"); got != "https://api.github.com" {
+		t.Errorf("DefaultAPIURL(github.com) = %q", got)
+	}
+	if got := DefaultAPIURL(TypeGitHub, "github.example.com"); got != "https://github.example.com/api/v3" {
+		t.Errorf("DefaultAPIURL(enterprise) = %q", got)
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	if _, err := New(Config{Type: "svn", APIURL: "http://x", Repo: "a/b"}, nil); err == nil {
+		t.Error("expected error for unknown type")
+	}
+	if _, err := New(Config{Type: TypeGitHub, APIURL: "http://x", Repo: "a/b", MergeMethod: "octopus"}, nil); err == nil {
+		t.Error("expected error for unknown merge method")
+	}
+	if _, err := New(Config{Type: TypeGitHub, APIURL: "http://x"}, nil); err == nil {
+		t.Error("expected error for missing repo")
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	srv := forgetest.NewServer("acme/widgets")
+	defer srv.Close()
+	f, err := New(Config{Type: TypeGitHub, APIURL: srv.URL, Repo: "acme/widgets"}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Get(context.Background(), 42)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != 404 || apiErr.Message != "Not Found" {
+		t.Fatalf("Get(42) error = %v, want 404 APIError", err)
+	}
+}
@@ -0,0 +1,316 @@
+// Package forgetest provides a fake GitHub-style forge for tests of code
+// that drives pull requests through the forge package.
+package forgetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PR is a pull request held by the fake forge.
+type PR struct {
+	Number      int
+	Head        string
+	Base        string
+	HeadSHA     string
+	Title       string
+	Body        string
+	State       string // "open" or "closed"
+	Merged      bool
+	MergeCommit string
+	Dirty       bool // reported as conflicting with the base
+}
+
+// Server is an in-memory forge speaking the subset of the GitHub REST API
+// used by the forge package. Create one with NewServer and point a
+// forge.Config of type github at URL.
+type Server struct {
+	*httptest.Server
+
+	// Repo is the owner/name the server answers for.
+	Repo string
+
+	// HeadSHA resolves a branch to its head commit when a pull request is
+	// opened. Default: "sha-<branch>" with slashes as dashes.
+	HeadSHA func(branch string) string
+
+	// OnMerge runs when a mergeable pull request is merged and returns the
+	// merge commit. Returning an error answers 405. Default: "merge-<n>".
+	OnMerge func(pr PR) (string, error)
+
+	mu       sync.Mutex
+	prs      []*PR
+	checks   map[string]string // head SHA -> check run conclusion or "pending"
+	defCheck string
+	requests []string
+}
+
+// NewServer starts a fake forge for repo. Close it when done.
+func NewServer(repo string) *Server {
+	s := &Server{
+		Repo:     repo,
+		checks:   make(map[string]string),
+		defCheck: "success",
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// SetChecks sets the check state reported for commit sha: "pending",
+// "success", "failure" or "none" (no check runs at all). An empty sha sets
+// the default for all commits.
+func (s *Server) SetChecks(sha, state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sha == "" {
+		s.defCheck = state
+		return
+	}
+	s.checks[sha] = state
+}
+
+// SetDirty marks pull request number as conflicting (or not) with its base.
+func (s *Server) SetDirty(number int, dirty bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pr := s.find(number); pr != nil {
+		pr.Dirty = dirty
+	}
+}
+
+// PRs returns a copy of every pull request, in creation order.
+func (s *Server) PRs() []PR {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]PR, 0, len(s.prs))
+	for _, pr := range s.prs {
+		out = append(out, *pr)
+	}
+	return out
+}
+
+// Requests returns "METHOD path" for every request served so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) find(number int) *PR {
+	for _, pr := range s.prs {
+		if pr.Number == number {
+			return pr
+		}
+	}
+	return nil
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	s.mu.Unlock()
+
+	prefix := "/repos/" + s.Repo + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "pulls" && r.Method == http.MethodGet:
+		s.listPulls(w, r)
+	case len(parts) == 1 && parts[0] == "pulls" && r.Method == http.MethodPost:
+		s.createPull(w, r)
+	case len(parts) == 2 && parts[0] == "pulls" && r.Method == http.MethodGet:
+		s.withPR(w, parts[1], func(pr *PR) {
+			s.mu.Lock()
+			out := s.render(pr)
+			s.mu.Unlock()
+			writeJSON(w, http.StatusOK, out)
+		})
+	case len(parts) == 3 && parts[0] == "pulls" && parts[2] == "merge" && r.Method == http.MethodPut:
+		s.withPR(w, parts[1], func(pr *PR) { s.merge(w, r, pr) })
+	case len(parts) == 3 && parts[0] == "commits" && parts[2] == "check-runs":
+		s.checkRuns(w, parts[1])
+	case len(parts) == 3 && parts[0] == "commits" && parts[2] == "status":
+		// Everything is reported through check runs
+		writeJSON(w, http.StatusOK, map[string]interface{}{"state": "pending", "total_count": 0})
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) listPulls(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	head := q.Get("head")
+	if _, branch, ok := strings.Cut(head, ":"); ok {
+		head = branch
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []map[string]interface{}{}
+	for _, pr := range s.prs {
+		if state := q.Get("state"); state != "" && state != "all" && state != pr.State {
+			continue
+		}
+		if (head != "" && pr.Head != head) || (q.Get("base") != "" && pr.Base != q.Get("base")) {
+			continue
+		}
+		out = append(out, s.render(pr))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) createPull(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title string `json:"title"`
+		Head  string `json:"head"`
+		Base  string `json:"base"`
+		Body  string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Head == "" || req.Base == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	sha := "sha-" + strings.ReplaceAll(req.Head, "/", "-")
+	if s.HeadSHA != nil {
+		sha = s.HeadSHA(req.Head)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, pr := range s.prs {
+		if pr.State == "open" && pr.Head == req.Head && pr.Base == req.Base {
+			writeError(w, http.StatusUnprocessableEntity, "A pull request already exists for "+req.Head)
+			return
+		}
+	}
+	pr := &PR{
+		Number:  len(s.prs) + 1,
+		Head:    req.Head,
+		Base:    req.Base,
+		HeadSHA: sha,
+		Title:   req.Title,
+		Body:    req.Body,
+		State:   "open",
+	}
+	s.prs = append(s.prs, pr)
+	writeJSON(w, http.StatusCreated, s.render(pr))
+}
+
+func (s *Server) withPR(w http.ResponseWriter, num string, fn func(pr *PR)) {
+	n, err := strconv.Atoi(num)
+	s.mu.Lock()
+	var pr *PR
+	if err == nil {
+		pr = s.find(n)
+	}
+	s.mu.Unlock()
+	if pr == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	fn(pr)
+}
+
+func (s *Server) merge(w http.ResponseWriter, r *http.Request, pr *PR) {
+	var req struct {
+		SHA string `json:"sha"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	s.mu.Lock()
+	snapshot := *pr
+	checks := s.checkState(pr.HeadSHA)
+	s.mu.Unlock()
+
+	switch {
+	case snapshot.State != "open":
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is not open")
+		return
+	case snapshot.Dirty || (checks != "success" && checks != "none"):
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is not mergeable")
+		return
+	case req.SHA != "" && req.SHA != snapshot.HeadSHA:
+		writeError(w, http.StatusConflict, "Head branch was modified")
+		return
+	}
+
+	commit := fmt.Sprintf("merge-%d", snapshot.Number)
+	if s.OnMerge != nil {
+		var err error
+		if commit, err = s.OnMerge(snapshot); err != nil {
+			writeError(w, http.StatusMethodNotAllowed, err.Error())
+			return
+		}
+	}
+
+	s.mu.Lock()
+	pr.State, pr.Merged, pr.MergeCommit = "closed", true, commit
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"sha": commit, "merged": true, "message": "Pull Request successfully merged"})
+}
+
+func (s *Server) checkRuns(w http.ResponseWriter, sha string) {
+	s.mu.Lock()
+	state := s.checkState(sha)
+	s.mu.Unlock()
+
+	if state == "none" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"total_count": 0, "check_runs": []interface{}{}})
+		return
+	}
+
+	run := map[string]interface{}{"name": "ci", "status": "completed", "conclusion": state}
+	if state == "pending" {
+		run = map[string]interface{}{"name": "ci", "status": "in_progress", "conclusion": nil}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"total_count": 1, "check_runs": []interface{}{run}})
+}
+
+func (s *Server) checkState(sha string) string {
+	if state, ok := s.checks[sha]; ok {
+		return state
+	}
+	return s.defCheck
+}
+
+// render returns pr as the GitHub API shows it. Callers hold s.mu.
+func (s *Server) render(pr *PR) map[string]interface{} {
+	mergeable := "clean"
+	if pr.Dirty {
+		mergeable = "dirty"
+	}
+	out := map[string]interface{}{
+		"number":          pr.Number,
+		"html_url":        fmt.Sprintf("%s/%s/pull/%d", s.URL, s.Repo, pr.Number),
+		"state":           pr.State,
+		"title":           pr.Title,
+		"body":            pr.Body,
+		"merged":          pr.Merged,
+		"mergeable_state": mergeable,
+		"head":            map[string]string{"ref": pr.Head, "sha": pr.HeadSHA},
+		"base":            map[string]string{"ref": pr.Base},
+	}
+	if pr.Merged {
+		out["merge_commit_sha"] = pr.MergeCommit
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"message": msg})
+}
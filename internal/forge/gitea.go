@@ -0,0 +1,136 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// gitea talks to the Gitea (and Forgejo) REST API.
+type gitea struct {
+	api    *apiClient
+	repo   string
+	method string
+}
+
+type giteaPR struct {
+	Number         int    `json:"number"`
+	HTMLURL        string `json:"html_url"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	Mergeable      bool   `json:"mergeable"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	Head           struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p *giteaPR) convert() *PullRequest {
+	pr := &PullRequest{
+		Number:  p.Number,
+		URL:     p.HTMLURL,
+		State:   p.State,
+		Head:    p.Head.Ref,
+		Base:    p.Base.Ref,
+		HeadSHA: p.Head.SHA,
+		// Gitea only reports conflicts through mergeable on open PRs
+		Conflicted: p.State == StateOpen && !p.Mergeable,
+	}
+	if p.Merged {
+		pr.State = StateMerged
+		pr.MergeCommit = p.MergeCommitSHA
+	}
+	return pr
+}
+
+// FindOpen lists open pull requests and matches head and base locally;
+// older Gitea releases can't filter by branch.
+func (g *gitea) FindOpen(ctx context.Context, head, base string) (*PullRequest, error) {
+	for page := 1; ; page++ {
+		var prs []giteaPR
+		path := fmt.Sprintf("/repos/%s/pulls?state=open&limit=50&page=%d", g.repo, page)
+		if err := g.api.do(ctx, http.MethodGet, path, nil, &prs); err != nil {
+			return nil, err
+		}
+		for i := range prs {
+			if prs[i].Head.Ref == head && prs[i].Base.Ref == base {
+				return prs[i].convert(), nil
+			}
+		}
+		if len(prs) < 50 {
+			return nil, nil
+		}
+	}
+}
+
+func (g *gitea) Open(ctx context.Context, pr NewPullRequest) (*PullRequest, error) {
+	body := map[string]string{"title": pr.Title, "head": pr.Head, "base": pr.Base, "body": pr.Body}
+	var created giteaPR
+	if err := g.api.do(ctx, http.MethodPost, "/repos/"+g.repo+"/pulls", body, &created); err != nil {
+		return nil, err
+	}
+	return created.convert(), nil
+}
+
+func (g *gitea) Get(ctx context.Context, number int) (*PullRequest, error) {
+	var pr giteaPR
+	if err := g.api.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", g.repo, number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.convert(), nil
+}
+
+func (g *gitea) Checks(ctx context.Context, pr *PullRequest) (string, error) {
+	var status struct {
+		State    string        `json:"state"`
+		Statuses []giteaStatus `json:"statuses"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, "/repos/"+g.repo+"/commits/"+pr.HeadSHA+"/status", nil, &status); err != nil {
+		return "", err
+	}
+	if len(status.Statuses) == 0 {
+		return ChecksNone, nil
+	}
+	switch status.State {
+	case "success", "warning":
+		return ChecksSuccess, nil
+	case "pending", "":
+		return ChecksPending, nil
+	default:
+		return ChecksFailure, nil
+	}
+}
+
+// giteaStatus is one commit status; only whether any exist matters.
+type giteaStatus struct {
+	Status string `json:"status"`
+}
+
+func (g *gitea) Merge(ctx context.Context, pr *PullRequest, message string) (string, error) {
+	title, body, _ := strings.Cut(message, "\n")
+	req := map[string]string{
+		"Do":                g.method,
+		"MergeTitleField":   title,
+		"MergeMessageField": strings.TrimSpace(body),
+		"head_commit_id":    pr.HeadSHA,
+	}
+	err := g.api.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls/%d/merge", g.repo, pr.Number), req, nil)
+	if statusIs(err, http.StatusMethodNotAllowed, http.StatusConflict) {
+		return "", fmt.Errorf("%w: %v", ErrNotMergeable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The merge response is empty; the merge commit is on the PR
+	merged, err := g.Get(ctx, pr.Number)
+	if err != nil {
+		return "", fmt.Errorf("merged, but reading the merge commit: %w", err)
+	}
+	return merged.MergeCommit, nil
+}
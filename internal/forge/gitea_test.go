@@ -0,0 +1,76 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitea(t *testing.T) {
+	var mergeReq map[string]string
+	merged := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/widgets/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"number":3,"state":"open","mergeable":true,"head":{"ref":"other","sha":"s0"},"base":{"ref":"main"}},
+			{"number":7,"state":"open","mergeable":false,"head":{"ref":"polecat/nux","sha":"abc"},"base":{"ref":"main"}}]`))
+	})
+	mux.HandleFunc("/repos/acme/widgets/commits/abc/status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"state":"pending","statuses":[{"status":"pending"}]}`))
+	})
+	mux.HandleFunc("/repos/acme/widgets/commits/new/status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"state":"","statuses":[]}`))
+	})
+	mux.HandleFunc("/repos/acme/widgets/pulls/7/merge", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&mergeReq)
+		if mergeReq["head_commit_id"] != "abc" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		merged = true
+	})
+	mux.HandleFunc("/repos/acme/widgets/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		if merged {
+			_, _ = w.Write([]byte(`{"number":7,"state":"closed","merged":true,"merge_commit_sha":"m7","head":{"ref":"polecat/nux","sha":"abc"},"base":{"ref":"main"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f, err := New(Config{Type: TypeGitea, APIURL: srv.URL + "/", Repo: "acme/widgets", Token: "tok", MergeMethod: MethodSquash}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	pr, err := f.FindOpen(ctx, "polecat/nux", "main")
+	if err != nil || pr == nil || pr.Number != 7 || !pr.Conflicted {
+		t.Fatalf("FindOpen = %+v, %v; want conflicted #7", pr, err)
+	}
+	if state, err := f.Checks(ctx, pr); err != nil || state != ChecksPending {
+		t.Errorf("Checks = %q, %v; want pending", state, err)
+	}
+	if state, err := f.Checks(ctx, &PullRequest{Number: 7, HeadSHA: "new"}); err != nil || state != ChecksNone {
+		t.Errorf("Checks without statuses = %q, %v; want none", state, err)
+	}
+	sha, err := f.Merge(ctx, pr, "Merge polecat/nux\n\nFixes gt-abc")
+	if err != nil || sha != "m7" {
+		t.Fatalf("Merge = %q, %v; want m7", sha, err)
+	}
+	if mergeReq["Do"] != MethodSquash || mergeReq["MergeTitleField"] != "Merge polecat/nux" || mergeReq["MergeMessageField"] != "Fixes gt-abc" {
+		t.Errorf("merge request body = %v", mergeReq)
+	}
+
+	pr.HeadSHA = "moved"
+	if _, err := f.Merge(ctx, pr, "m"); !errors.Is(err, ErrNotMergeable) {
+		t.Errorf("Merge with moved head = %v, want ErrNotMergeable", err)
+	}
+}
@@ -0,0 +1,167 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitHub talks to the GitHub REST API (github.com or Enterprise Server).
+type gitHub struct {
+	api    *apiClient
+	repo   string
+	method string
+}
+
+type gitHubPR struct {
+	Number         int    `json:"number"`
+	HTMLURL        string `json:"html_url"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	MergeableState string `json:"mergeable_state"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	Head           struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p *gitHubPR) convert() *PullRequest {
+	pr := &PullRequest{
+		Number:     p.Number,
+		URL:        p.HTMLURL,
+		State:      p.State,
+		Head:       p.Head.Ref,
+		Base:       p.Base.Ref,
+		HeadSHA:    p.Head.SHA,
+		Conflicted: p.MergeableState == "dirty",
+	}
+	if p.Merged {
+		pr.State = StateMerged
+		pr.MergeCommit = p.MergeCommitSHA
+	}
+	return pr
+}
+
+func (g *gitHub) FindOpen(ctx context.Context, head, base string) (*PullRequest, error) {
+	owner, _, _ := strings.Cut(g.repo, "/")
+	q := url.Values{"state": {"open"}, "head": {owner + ":" + head}, "base": {base}}
+	var prs []gitHubPR
+	if err := g.api.do(ctx, http.MethodGet, "/repos/"+g.repo+"/pulls?"+q.Encode(), nil, &prs); err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return prs[0].convert(), nil
+}
+
+func (g *gitHub) Open(ctx context.Context, pr NewPullRequest) (*PullRequest, error) {
+	body := map[string]string{"title": pr.Title, "head": pr.Head, "base": pr.Base, "body": pr.Body}
+	var created gitHubPR
+	if err := g.api.do(ctx, http.MethodPost, "/repos/"+g.repo+"/pulls", body, &created); err != nil {
+		return nil, err
+	}
+	return created.convert(), nil
+}
+
+func (g *gitHub) Get(ctx context.Context, number int) (*PullRequest, error) {
+	var pr gitHubPR
+	if err := g.api.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", g.repo, number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.convert(), nil
+}
+
+// Checks combines check runs (Actions and other apps) with commit statuses
+// (older CI integrations).
+func (g *gitHub) Checks(ctx context.Context, pr *PullRequest) (string, error) {
+	var runs struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, "/repos/"+g.repo+"/commits/"+pr.HeadSHA+"/check-runs", nil, &runs); err != nil {
+		return "", err
+	}
+	var status struct {
+		State      string `json:"state"`
+		TotalCount int    `json:"total_count"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, "/repos/"+g.repo+"/commits/"+pr.HeadSHA+"/status", nil, &status); err != nil {
+		return "", err
+	}
+
+	states := make([]string, 0, len(runs.CheckRuns)+1)
+	for _, run := range runs.CheckRuns {
+		switch {
+		case run.Status != "completed":
+			states = append(states, ChecksPending)
+		case run.Conclusion == "success" || run.Conclusion == "neutral" || run.Conclusion == "skipped":
+			states = append(states, ChecksSuccess)
+		default:
+			states = append(states, ChecksFailure)
+		}
+	}
+	// The combined status is "pending" when no statuses exist at all
+	if status.TotalCount > 0 {
+		switch status.State {
+		case "success":
+			states = append(states, ChecksSuccess)
+		case "pending":
+			states = append(states, ChecksPending)
+		default:
+			states = append(states, ChecksFailure)
+		}
+	}
+	return combine(states), nil
+}
+
+func (g *gitHub) Merge(ctx context.Context, pr *PullRequest, message string) (string, error) {
+	title, body, _ := strings.Cut(message, "\n")
+	req := map[string]string{
+		"merge_method":   g.method,
+		"commit_title":   title,
+		"commit_message": strings.TrimSpace(body),
+		"sha":            pr.HeadSHA,
+	}
+	var resp struct {
+		SHA    string `json:"sha"`
+		Merged bool   `json:"merged"`
+	}
+	err := g.api.do(ctx, http.MethodPut, fmt.Sprintf("/repos/%s/pulls/%d/merge", g.repo, pr.Number), req, &resp)
+	if statusIs(err, http.StatusMethodNotAllowed, http.StatusConflict) {
+		return "", fmt.Errorf("%w: %v", ErrNotMergeable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !resp.Merged {
+		return "", ErrNotMergeable
+	}
+	return resp.SHA, nil
+}
+
+// combine folds individual check states: any failure fails, then any
+// pending is pending. No checks at all is ChecksNone.
+func combine(states []string) string {
+	if len(states) == 0 {
+		return ChecksNone
+	}
+	result := ChecksSuccess
+	for _, s := range states {
+		switch s {
+		case ChecksFailure:
+			return ChecksFailure
+		case ChecksPending:
+			result = ChecksPending
+		}
+	}
+	return result
+}
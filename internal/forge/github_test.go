@@ -0,0 +1,72 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/forge/forgetest"
+)
+
+func TestGitHubPullRequestLifecycle(t *testing.T) {
+	srv := forgetest.NewServer("acme/widgets")
+	defer srv.Close()
+	f, err := New(Config{Type: TypeGitHub, APIURL: srv.URL, Repo: "acme/widgets", Token: "tok"}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if pr, err := f.FindOpen(ctx, "polecat/nux", "main"); err != nil || pr != nil {
+		t.Fatalf("FindOpen before Open = %v, %v; want nil", pr, err)
+	}
+	pr, err := f.Open(ctx, NewPullRequest{Head: "polecat/nux", Base: "main", Title: "Fix the widget", Body: "gt-abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Number != 1 || pr.State != StateOpen || pr.HeadSHA != "sha-polecat-nux" {
+		t.Errorf("Open = %+v", pr)
+	}
+	found, err := f.FindOpen(ctx, "polecat/nux", "main")
+	if err != nil || found == nil || found.Number != pr.Number {
+		t.Fatalf("FindOpen = %+v, %v; want #%d", found, err, pr.Number)
+	}
+
+	// Failing checks block the merge
+	srv.SetChecks(pr.HeadSHA, ChecksFailure)
+	if state, err := f.Checks(ctx, pr); err != nil || state != ChecksFailure {
+		t.Errorf("Checks = %q, %v; want failure", state, err)
+	}
+	if _, err := f.Merge(ctx, pr, "Merge polecat/nux"); !errors.Is(err, ErrNotMergeable) {
+		t.Errorf("Merge with failing checks = %v, want ErrNotMergeable", err)
+	}
+
+	srv.SetChecks(pr.HeadSHA, ChecksSuccess)
+	sha, err := f.Merge(ctx, pr, "Merge polecat/nux\n\nDetails")
+	if err != nil || sha != "merge-1" {
+		t.Fatalf("Merge = %q, %v; want merge-1", sha, err)
+	}
+	merged, err := f.Get(ctx, pr.Number)
+	if err != nil || merged.State != StateMerged || merged.MergeCommit != "merge-1" {
+		t.Errorf("Get after merge = %+v, %v", merged, err)
+	}
+	if pr, err := f.FindOpen(ctx, "polecat/nux", "main"); err != nil || pr != nil {
+		t.Errorf("FindOpen after merge = %v, %v; want nil", pr, err)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	tests := []struct {
+		states []string
+		want   string
+	}{
+		{nil, ChecksNone},
+		{[]string{ChecksSuccess, ChecksPending}, ChecksPending},
+		{[]string{ChecksPending, ChecksFailure, ChecksSuccess}, ChecksFailure},
+	}
+	for _, tt := range tests {
+		if got := combine(tt.states); got != tt.want {
+			t.Errorf("combine(%v) = %q, want %q", tt.states, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitLab talks to the GitLab REST API. Pull requests are merge requests,
+// numbered by their project-scoped iid, and checks are the head pipeline.
+type gitLab struct {
+	api     *apiClient
+	project string // URL-escaped namespace/project
+	method  string
+}
+
+type gitLabMR struct {
+	IID            int    `json:"iid"`
+	WebURL         string `json:"web_url"`
+	State          string `json:"state"` // opened, closed, locked, merged
+	SourceBranch   string `json:"source_branch"`
+	TargetBranch   string `json:"target_branch"`
+	SHA            string `json:"sha"`
+	HasConflicts   bool   `json:"has_conflicts"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	SquashSHA      string `json:"squash_commit_sha"`
+	HeadPipeline   *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+func (m *gitLabMR) convert() *PullRequest {
+	pr := &PullRequest{
+		Number:     m.IID,
+		URL:        m.WebURL,
+		State:      m.State,
+		Head:       m.SourceBranch,
+		Base:       m.TargetBranch,
+		HeadSHA:    m.SHA,
+		Conflicted: m.HasConflicts,
+	}
+	switch m.State {
+	case "opened":
+		pr.State = StateOpen
+	case "merged":
+		pr.MergeCommit = m.MergeCommitSHA
+		if pr.MergeCommit == "" {
+			pr.MergeCommit = m.SquashSHA
+		}
+	case "locked":
+		pr.State = StateClosed
+	}
+	return pr
+}
+
+func (g *gitLab) FindOpen(ctx context.Context, head, base string) (*PullRequest, error) {
+	q := url.Values{"state": {"opened"}, "source_branch": {head}, "target_branch": {base}}
+	var mrs []gitLabMR
+	if err := g.api.do(ctx, http.MethodGet, "/projects/"+g.project+"/merge_requests?"+q.Encode(), nil, &mrs); err != nil {
+		return nil, err
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return mrs[0].convert(), nil
+}
+
+func (g *gitLab) Open(ctx context.Context, pr NewPullRequest) (*PullRequest, error) {
+	body := map[string]interface{}{
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"squash":        g.method == MethodSquash,
+	}
+	var created gitLabMR
+	if err := g.api.do(ctx, http.MethodPost, "/projects/"+g.project+"/merge_requests", body, &created); err != nil {
+		return nil, err
+	}
+	return created.convert(), nil
+}
+
+func (g *gitLab) get(ctx context.Context, number int) (*gitLabMR, error) {
+	var mr gitLabMR
+	if err := g.api.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%s/merge_requests/%d", g.project, number), nil, &mr); err != nil {
+		return nil, err
+	}
+	return &mr, nil
+}
+
+func (g *gitLab) Get(ctx context.Context, number int) (*PullRequest, error) {
+	mr, err := g.get(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return mr.convert(), nil
+}
+
+func (g *gitLab) Checks(ctx context.Context, pr *PullRequest) (string, error) {
+	mr, err := g.get(ctx, pr.Number)
+	if err != nil {
+		return "", err
+	}
+	if mr.HeadPipeline == nil {
+		return ChecksNone, nil
+	}
+	switch mr.HeadPipeline.Status {
+	case "success", "skipped":
+		return ChecksSuccess, nil
+	case "failed", "canceled":
+		return ChecksFailure, nil
+	default:
+		return ChecksPending, nil
+	}
+}
+
+// Merge merges the merge request. GitLab has no per-merge rebase method;
+// MethodRebase merges with the project's configured method.
+func (g *gitLab) Merge(ctx context.Context, pr *PullRequest, message string) (string, error) {
+	req := map[string]interface{}{
+		"merge_commit_message": message,
+		"sha":                  pr.HeadSHA,
+		"squash":               g.method == MethodSquash,
+	}
+	if g.method == MethodSquash {
+		req["squash_commit_message"] = message
+	}
+	var merged gitLabMR
+	err := g.api.do(ctx, http.MethodPut, fmt.Sprintf("/projects/%s/merge_requests/%d/merge", g.project, pr.Number), req, &merged)
+	if statusIs(err, http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusConflict, http.StatusUnprocessableEntity) {
+		return "", fmt.Errorf("%w: %v", ErrNotMergeable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	result := merged.convert()
+	if result.State != StateMerged {
+		return "", fmt.Errorf("%w: merge request is %s", ErrNotMergeable, strings.TrimSpace(merged.State))
+	}
+	return result.MergeCommit, nil
+}
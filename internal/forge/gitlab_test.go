@@ -0,0 +1,91 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitLab(t *testing.T) {
+	var created, mergeReq map[string]interface{}
+	pipeline := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// The project path is escaped into a single segment
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /projects/group%2Fwidgets/merge_requests":
+			if r.URL.Query().Get("source_branch") != "polecat/nux" || r.URL.Query().Get("state") != "opened" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[]`))
+		case "POST /projects/group%2Fwidgets/merge_requests":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_, _ = w.Write([]byte(`{"iid":4,"web_url":"https://gl/mr/4","state":"opened","source_branch":"polecat/nux","target_branch":"main","sha":"abc"}`))
+		case "GET /projects/group%2Fwidgets/merge_requests/4":
+			if pipeline == "" {
+				_, _ = w.Write([]byte(`{"iid":4,"state":"opened","sha":"abc","head_pipeline":null}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"iid":4,"state":"opened","sha":"abc","head_pipeline":{"status":"` + pipeline + `"}}`))
+		case "PUT /projects/group%2Fwidgets/merge_requests/4/merge":
+			_ = json.NewDecoder(r.Body).Decode(&mergeReq)
+			if pipeline != "success" {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				_, _ = w.Write([]byte(`{"message":"Method Not Allowed"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"iid":4,"state":"merged","sha":"abc","merge_commit_sha":"m4"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	f, err := New(Config{Type: TypeGitLab, APIURL: srv.URL, Repo: "group/widgets", Token: "tok"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if pr, err := f.FindOpen(ctx, "polecat/nux", "main"); err != nil || pr != nil {
+		t.Fatalf("FindOpen = %v, %v; want nil", pr, err)
+	}
+	pr, err := f.Open(ctx, NewPullRequest{Head: "polecat/nux", Base: "main", Title: "Fix", Body: "gt-abc"})
+	if err != nil || pr.Number != 4 || pr.State != StateOpen {
+		t.Fatalf("Open = %+v, %v", pr, err)
+	}
+	if created["source_branch"] != "polecat/nux" || created["description"] != "gt-abc" {
+		t.Errorf("create body = %v", created)
+	}
+
+	// No pipeline yet: nothing has reported
+	if state, err := f.Checks(ctx, pr); err != nil || state != ChecksNone {
+		t.Errorf("Checks = %q, %v; want none", state, err)
+	}
+	pipeline = "running"
+	if state, err := f.Checks(ctx, pr); err != nil || state != ChecksPending {
+		t.Errorf("Checks = %q, %v; want pending", state, err)
+	}
+	if _, err := f.Merge(ctx, pr, "Merge"); !errors.Is(err, ErrNotMergeable) {
+		t.Errorf("Merge with running pipeline = %v, want ErrNotMergeable", err)
+	}
+
+	pipeline = "success"
+	if state, err := f.Checks(ctx, pr); err != nil || state != ChecksSuccess {
+		t.Errorf("Checks = %q, %v; want success", state, err)
+	}
+	sha, err := f.Merge(ctx, pr, "Merge")
+	if err != nil || sha != "m4" {
+		t.Fatalf("Merge = %q, %v; want m4", sha, err)
+	}
+	if mergeReq["sha"] != "abc" || mergeReq["merge_commit_message"] != "Merge" {
+		t.Errorf("merge body = %v", mergeReq)
+	}
+}
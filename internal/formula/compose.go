@@ -0,0 +1,443 @@
+package formula
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Loader loads a formula by name, for resolving composition.
+type Loader func(name string) (*Formula, error)
+
+// IsComposed reports whether the formula extends or composes other
+// formulas and must be resolved before use.
+func (f *Formula) IsComposed() bool {
+	if len(f.Extends) > 0 {
+		return true
+	}
+	c := f.Compose
+	return c != nil && len(c.Aspects)+len(c.Include)+len(c.Expand) > 0
+}
+
+// validateComposition checks what can be checked before resolution.
+func (f *Formula) validateComposition() error {
+	seen := make(map[string]bool)
+	for _, step := range f.Steps {
+		if step.ID == "" {
+			return fmt.Errorf("step missing required id field")
+		}
+		if seen[step.ID] {
+			return fmt.Errorf("duplicate step id: %s", step.ID)
+		}
+		seen[step.ID] = true
+	}
+	if f.Compose == nil {
+		return nil
+	}
+	for _, inc := range f.Compose.Include {
+		if inc.Formula == "" {
+			return fmt.Errorf("compose.include missing required formula field")
+		}
+	}
+	for _, exp := range f.Compose.Expand {
+		if exp.Target == "" || exp.With == "" {
+			return fmt.Errorf("compose.expand requires target and with")
+		}
+	}
+	return nil
+}
+
+// validateAdvice checks an aspect formula's advice and pointcuts.
+func (f *Formula) validateAdvice() error {
+	for _, adv := range f.Advice {
+		if adv.Target == "" {
+			return fmt.Errorf("advice missing required target field")
+		}
+		if _, err := path.Match(adv.Target, ""); err != nil {
+			return fmt.Errorf("advice target %q: %w", adv.Target, err)
+		}
+		for _, step := range adv.steps() {
+			if step.ID == "" {
+				return fmt.Errorf("advice on %q has a step without id", adv.Target)
+			}
+			if len(step.Needs) > 0 {
+				return fmt.Errorf("advice step %q cannot set needs", step.ID)
+			}
+		}
+	}
+	for _, pc := range f.Pointcuts {
+		if _, err := path.Match(pc.Glob, ""); err != nil {
+			return fmt.Errorf("pointcut %q: %w", pc.Glob, err)
+		}
+	}
+	return nil
+}
+
+// before returns the advice's before steps, including around.before.
+func (a Advice) before() []Step {
+	steps := append([]Step(nil), a.Before...)
+	if a.Around != nil {
+		steps = append(steps, a.Around.Before...)
+	}
+	return steps
+}
+
+// after returns the advice's after steps, including around.after.
+func (a Advice) after() []Step {
+	steps := append([]Step(nil), a.After...)
+	if a.Around != nil {
+		steps = append(steps, a.Around.After...)
+	}
+	return steps
+}
+
+func (a Advice) steps() []Step {
+	return append(a.before(), a.after()...)
+}
+
+// source names where the formula came from, for step provenance.
+func (f *Formula) source() string {
+	if f.Path != "" {
+		return f.Path
+	}
+	return f.Name
+}
+
+// Resolve applies extends, compose.include, compose.expand and
+// compose.aspects, returning one validated formula. Every step's Source
+// records the formula file it came from.
+//
+// Extended formulas are merged in order, and the formula's own steps
+// override inherited steps with the same ID. Included steps are prefixed
+// with "<prefix>." and their roots need the include's needs. Aspects are
+// woven last, so their advice can match any step.
+func (f *Formula) Resolve(load Loader) (*Formula, error) {
+	r := &resolver{load: load}
+	return r.resolve(f)
+}
+
+type resolver struct {
+	load  Loader
+	stack []string
+}
+
+func (r *resolver) loadResolved(name string) (*Formula, error) {
+	f, err := r.load(name)
+	if err != nil {
+		return nil, fmt.Errorf("loading formula %q: %w", name, err)
+	}
+	return r.resolve(f)
+}
+
+func (r *resolver) resolve(f *Formula) (*Formula, error) {
+	for _, name := range r.stack {
+		if name == f.Name {
+			return nil, fmt.Errorf("formula composition cycle: %s -> %s", strings.Join(r.stack, " -> "), f.Name)
+		}
+	}
+	r.stack = append(r.stack, f.Name)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	out := *f
+	out.Steps = nil
+	out.Extends = nil
+	out.Compose = nil
+	out.Vars = make(map[string]Var)
+	out.Inputs = make(map[string]Input)
+
+	for _, name := range f.Extends {
+		base, err := r.loadResolved(name)
+		if err != nil {
+			return nil, err
+		}
+		if base.Type != TypeWorkflow {
+			return nil, fmt.Errorf("%s extends %s, which is a %s formula", f.Name, name, base.Type)
+		}
+		for _, step := range base.Steps {
+			out.setStep(step)
+		}
+		out.mergeVars(base, true)
+		if out.Description == "" {
+			out.Description = base.Description
+		}
+	}
+
+	for _, step := range f.Steps {
+		step.Source = f.source()
+		out.setStep(step)
+	}
+	out.mergeVars(f, true)
+
+	if f.Compose != nil {
+		for _, inc := range f.Compose.Include {
+			if err := r.include(&out, inc); err != nil {
+				return nil, err
+			}
+		}
+		for _, exp := range f.Compose.Expand {
+			if err := r.expand(&out, exp); err != nil {
+				return nil, err
+			}
+		}
+		for _, name := range f.Compose.Aspects {
+			if err := r.weave(&out, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if out.Type == "" {
+		out.inferType()
+	}
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("resolved formula %s: %w", f.Name, err)
+	}
+	return &out, nil
+}
+
+// setStep replaces the step with the same ID, or appends it.
+func (f *Formula) setStep(step Step) {
+	for i := range f.Steps {
+		if f.Steps[i].ID == step.ID {
+			f.Steps[i] = step
+			return
+		}
+	}
+	f.Steps = append(f.Steps, step)
+}
+
+// mergeVars copies vars and inputs from other; existing ones are kept
+// unless override is set.
+func (f *Formula) mergeVars(other *Formula, override bool) {
+	for name, v := range other.Vars {
+		if _, ok := f.Vars[name]; override || !ok {
+			f.Vars[name] = v
+		}
+	}
+	for name, in := range other.Inputs {
+		if _, ok := f.Inputs[name]; override || !ok {
+			f.Inputs[name] = in
+		}
+	}
+}
+
+func (r *resolver) include(out *Formula, inc Include) error {
+	src, err := r.loadResolved(inc.Formula)
+	if err != nil {
+		return err
+	}
+	if src.Type != TypeWorkflow {
+		return fmt.Errorf("cannot include %s, which is a %s formula", inc.Formula, src.Type)
+	}
+
+	prefix := inc.Prefix
+	if prefix == "" {
+		prefix = inc.Formula
+	}
+	selected := make(map[string]bool)
+	for _, id := range inc.Steps {
+		if src.GetStep(id) == nil {
+			return fmt.Errorf("include %s: unknown step %s", inc.Formula, id)
+		}
+		selected[id] = true
+	}
+
+	for _, step := range src.Steps {
+		if len(selected) > 0 && !selected[step.ID] {
+			continue
+		}
+		var needs []string
+		for _, need := range step.Needs {
+			if len(selected) > 0 && !selected[need] {
+				return fmt.Errorf("include %s: step %s needs %s, which is not included", inc.Formula, step.ID, need)
+			}
+			needs = append(needs, prefix+"."+need)
+		}
+		if len(needs) == 0 {
+			needs = append(needs, inc.Needs...)
+		}
+		step.ID = prefix + "." + step.ID
+		step.Needs = needs
+		if out.GetStep(step.ID) != nil {
+			return fmt.Errorf("include %s: step id %s already exists", inc.Formula, step.ID)
+		}
+		out.Steps = append(out.Steps, step)
+	}
+	out.mergeVars(src, false)
+	return nil
+}
+
+// expand replaces the target step with an expansion formula's templates.
+// Templates without needs inherit the target's needs, and steps that
+// needed the target need the templates nothing else depends on.
+func (r *resolver) expand(out *Formula, exp ComposeExpand) error {
+	with, err := r.loadResolved(exp.With)
+	if err != nil {
+		return err
+	}
+	if with.Type != TypeExpansion {
+		return fmt.Errorf("cannot expand with %s, which is a %s formula", exp.With, with.Type)
+	}
+	target := out.GetStep(exp.Target)
+	if target == nil {
+		return fmt.Errorf("compose.expand target %q not found", exp.Target)
+	}
+	t := *target
+
+	replacer := strings.NewReplacer(
+		"{target.title}", t.Title,
+		"{target.description}", t.Description,
+		"{target}", t.ID,
+	)
+	needed := make(map[string]bool)
+	var generated []Step
+	for _, tmpl := range with.Template {
+		step := Step{
+			ID:          replacer.Replace(tmpl.ID),
+			Title:       replacer.Replace(tmpl.Title),
+			Description: replacer.Replace(tmpl.Description),
+			When:        t.When,
+			Foreach:     t.Foreach,
+			Source:      with.source(),
+		}
+		for _, need := range tmpl.Needs {
+			need = replacer.Replace(need)
+			step.Needs = append(step.Needs, need)
+			needed[need] = true
+		}
+		if len(step.Needs) == 0 {
+			step.Needs = append([]string(nil), t.Needs...)
+		}
+		generated = append(generated, step)
+	}
+	var sinks []string
+	for _, step := range generated {
+		if !needed[step.ID] {
+			sinks = append(sinks, step.ID)
+		}
+	}
+
+	out.replaceStep(t.ID, generated)
+	out.replaceNeed(t.ID, sinks, nil)
+	return nil
+}
+
+// weave applies an aspect formula's advice to the workflow's steps.
+func (r *resolver) weave(out *Formula, name string) error {
+	asp, err := r.load(name)
+	if err != nil {
+		return fmt.Errorf("loading formula %q: %w", name, err)
+	}
+	if asp.Type != TypeAspect {
+		return fmt.Errorf("cannot weave %s, which is a %s formula", name, asp.Type)
+	}
+
+	for _, adv := range asp.Advice {
+		var targets []string
+		for _, step := range out.Steps {
+			if ok, _ := path.Match(adv.Target, step.ID); ok && asp.inPointcuts(step.ID) {
+				targets = append(targets, step.ID)
+			}
+		}
+
+		for _, id := range targets {
+			// Re-read the target: weaving earlier targets may have
+			// rewritten its needs.
+			t := *out.GetStep(id)
+			replacer := strings.NewReplacer(
+				"{step.id}", t.ID,
+				"{step.title}", t.Title,
+				"{step.description}", t.Description,
+			)
+			advise := func(a Step, needs []string) Step {
+				return Step{
+					ID:          replacer.Replace(a.ID),
+					Title:       replacer.Replace(a.Title),
+					Description: replacer.Replace(a.Description),
+					Needs:       needs,
+					When:        t.When,
+					Foreach:     t.Foreach,
+					Source:      asp.source(),
+				}
+			}
+
+			// Before advice runs in a chain between the target's needs and
+			// the target.
+			var steps []Step
+			needs := t.Needs
+			for _, a := range adv.before() {
+				step := advise(a, needs)
+				steps = append(steps, step)
+				needs = []string{step.ID}
+			}
+			t.Needs = needs
+			steps = append(steps, t)
+
+			// After advice runs in a chain after the target, and whatever
+			// needed the target now needs the end of the chain.
+			last := t.ID
+			var added []string
+			for _, a := range adv.after() {
+				step := advise(a, []string{last})
+				steps = append(steps, step)
+				last = step.ID
+				added = append(added, step.ID)
+			}
+
+			out.replaceStep(t.ID, steps)
+			if last != t.ID {
+				out.replaceNeed(t.ID, []string{last}, added)
+			}
+		}
+	}
+	return nil
+}
+
+// inPointcuts reports whether an aspect may advise the step. Aspects
+// without pointcuts may advise any step.
+func (f *Formula) inPointcuts(id string) bool {
+	if len(f.Pointcuts) == 0 {
+		return true
+	}
+	for _, pc := range f.Pointcuts {
+		if ok, _ := path.Match(pc.Glob, id); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceStep replaces the step with the given ID by steps, in place.
+func (f *Formula) replaceStep(id string, steps []Step) {
+	for i := range f.Steps {
+		if f.Steps[i].ID == id {
+			rest := append(steps, f.Steps[i+1:]...)
+			f.Steps = append(f.Steps[:i:i], rest...)
+			return
+		}
+	}
+}
+
+// replaceNeed rewrites needs on id to needs on with, skipping the steps
+// listed in except.
+func (f *Formula) replaceNeed(id string, with, except []string) {
+	skip := make(map[string]bool, len(except))
+	for _, e := range except {
+		skip[e] = true
+	}
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		if skip[step.ID] {
+			continue
+		}
+		for j, need := range step.Needs {
+			if need != id {
+				continue
+			}
+			needs := append([]string(nil), step.Needs[:j]...)
+			needs = append(needs, with...)
+			step.Needs = append(needs, step.Needs[j+1:]...)
+			break
+		}
+	}
+}
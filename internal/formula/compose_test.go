@@ -0,0 +1,225 @@
+package formula
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// memLoader parses formulas from an in-memory map of name -> TOML.
+func memLoader(t *testing.T, formulas map[string]string) Loader {
+	t.Helper()
+	return func(name string) (*Formula, error) {
+		data, ok := formulas[name]
+		if !ok {
+			return nil, fmt.Errorf("formula %q not found", name)
+		}
+		f, err := Parse([]byte(data))
+		if err != nil {
+			return nil, err
+		}
+		f.Path = name + ".formula.toml"
+		return f, nil
+	}
+}
+
+var composeFormulas = map[string]string{
+	"base": `
+formula = "base"
+type = "workflow"
+[vars.feature]
+required = true
+[[steps]]
+id = "implement"
+title = "Implement {{feature}}"
+[[steps]]
+id = "test"
+title = "Run tests"
+needs = ["implement"]
+`,
+	"tail": `
+formula = "tail"
+type = "workflow"
+[vars.changelog]
+default = "CHANGELOG.md"
+[[steps]]
+id = "changelog"
+title = "Update {{changelog}}"
+[[steps]]
+id = "done"
+title = "gt done"
+needs = ["changelog"]
+`,
+	"audit": `
+formula = "audit"
+type = "aspect"
+[[pointcuts]]
+glob = "implement"
+[[advice]]
+target = "*"
+[[advice.before]]
+id = "{step.id}-prescan"
+title = "Prescan {step.id}"
+[[advice.after]]
+id = "{step.id}-postscan"
+title = "Postscan {step.id}"
+`,
+	"child": `
+formula = "child"
+extends = ["base"]
+[[steps]]
+id = "test"
+title = "Run tests with race detector"
+needs = ["implement"]
+[compose]
+aspects = ["audit"]
+[[compose.include]]
+formula = "tail"
+prefix = "release"
+needs = ["test"]
+`,
+}
+
+func TestResolve_ExtendsIncludeAndAspects(t *testing.T) {
+	load := memLoader(t, composeFormulas)
+	child, err := load("child")
+	if err != nil {
+		t.Fatalf("load child: %v", err)
+	}
+	if !child.IsComposed() {
+		t.Fatal("child should be composed")
+	}
+
+	resolved, err := child.Resolve(load)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if resolved.IsComposed() {
+		t.Error("resolved formula should not be composed")
+	}
+
+	want := map[string][]string{
+		"implement-prescan":  nil,
+		"implement":          {"implement-prescan"},
+		"implement-postscan": {"implement"},
+		"test":               {"implement-postscan"},
+		"release.changelog":  {"test"},
+		"release.done":       {"release.changelog"},
+	}
+	if got := stepNeeds(resolved); !reflect.DeepEqual(got, want) {
+		t.Errorf("needs = %v, want %v", got, want)
+	}
+
+	sources := map[string]string{
+		"implement":         "base.formula.toml",
+		"test":              "child.formula.toml", // overridden by ID
+		"implement-prescan": "audit.formula.toml",
+		"release.done":      "tail.formula.toml",
+	}
+	for id, want := range sources {
+		if got := resolved.GetStep(id).Source; got != want {
+			t.Errorf("%s source = %q, want %q", id, got, want)
+		}
+	}
+	if resolved.GetStep("test").Title != "Run tests with race detector" {
+		t.Errorf("test step not overridden: %+v", resolved.GetStep("test"))
+	}
+	if _, ok := resolved.Vars["changelog"]; !ok {
+		t.Error("vars from included formula should be merged")
+	}
+	if _, ok := resolved.Vars["feature"]; !ok {
+		t.Error("vars from base formula should be inherited")
+	}
+}
+
+func TestResolve_Expand(t *testing.T) {
+	load := memLoader(t, map[string]string{
+		"base": composeFormulas["base"],
+		"twice": `
+formula = "twice"
+type = "expansion"
+[[template]]
+id = "{target}.draft"
+title = "Draft: {target.title}"
+[[template]]
+id = "{target}.polish"
+needs = ["{target}.draft"]
+`,
+		"child": `
+formula = "child"
+extends = ["base"]
+[[compose.expand]]
+target = "implement"
+with = "twice"
+`,
+	})
+	child, _ := load("child")
+	resolved, err := child.Resolve(load)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	want := map[string][]string{
+		"implement.draft":  nil,
+		"implement.polish": {"implement.draft"},
+		"test":             {"implement.polish"},
+	}
+	if got := stepNeeds(resolved); !reflect.DeepEqual(got, want) {
+		t.Errorf("needs = %v, want %v", got, want)
+	}
+	if title := resolved.GetStep("implement.draft").Title; title != "Draft: Implement {{feature}}" {
+		t.Errorf("draft title = %q", title)
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		formulas map[string]string
+		want     string
+	}{
+		{
+			name: "cycle",
+			formulas: map[string]string{
+				"a": "formula = \"a\"\nextends = [\"b\"]\n",
+				"b": "formula = \"b\"\nextends = [\"a\"]\n",
+			},
+			want: "cycle: a -> b -> a",
+		},
+		{
+			name: "missing base",
+			formulas: map[string]string{
+				"a": "formula = \"a\"\nextends = [\"nope\"]\n",
+			},
+			want: `loading formula "nope"`,
+		},
+		{
+			name: "dangling need after resolution",
+			formulas: map[string]string{
+				"base": composeFormulas["base"],
+				"a":    "formula = \"a\"\nextends = [\"base\"]\n[[steps]]\nid = \"ship\"\nneeds = [\"nope\"]\n",
+			},
+			want: "needs unknown step: nope",
+		},
+		{
+			name: "include partial group",
+			formulas: map[string]string{
+				"base": composeFormulas["base"],
+				"tail": composeFormulas["tail"],
+				"a":    "formula = \"a\"\nextends = [\"base\"]\n[[compose.include]]\nformula = \"tail\"\nsteps = [\"done\"]\n",
+			},
+			want: "needs changelog, which is not included",
+		},
+	}
+	for _, tt := range tests {
+		load := memLoader(t, tt.formulas)
+		f, err := load("a")
+		if err != nil {
+			t.Fatalf("%s: load: %v", tt.name, err)
+		}
+		_, err = f.Resolve(load)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want containing %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -77,6 +77,47 @@
 //	title = "Publish"
 //	needs = ["build"]
 //
+// # Conditions and Loops
+//
+// Workflow steps may set when, a condition over vars and inputs, and
+// foreach, the name of a comma-separated list var. Expand resolves vars and
+// returns a formula with concrete steps, which TopologicalSort and
+// ReadySteps then operate on:
+//
+//	[[steps]]
+//	id = "test-{{item}}"
+//	foreach = "platforms"
+//	when = "item != windows"
+//
+//	expanded, err := f.Expand(map[string]string{"platforms": "linux,windows"})
+//	// expanded.Steps: test-linux
+//
+// # Typed Vars
+//
+// Vars and inputs may declare a type (string, int, bool, enum, bead-id,
+// rig, path, list), with enum choices for enum and list. Declarations are
+// checked at parse time; ValidateVars checks a set of values and reports
+// every problem at once, looking up bead IDs and rigs through a VarChecker:
+//
+//	[vars.priority]
+//	type = "enum"
+//	enum = ["low", "high"]
+//
+//	err := f.ValidateVars(map[string]string{"priority": "urgent"}, nil)
+//	// Returns: invalid formula vars: priority: "urgent" is not one of: low | high
+//
+// # Composition
+//
+// Workflows may extend other workflows (own steps override inherited ones by
+// ID), include step groups from other workflows under an ID prefix, expand a
+// step with an expansion formula, and weave aspect formulas' before/after
+// advice into matching steps. Resolve applies all of this given a Loader and
+// records each step's origin in Step.Source:
+//
+//	resolved, err := f.Resolve(func(name string) (*formula.Formula, error) {
+//	    return formula.ParseFile(filepath.Join(dir, name+".formula.toml"))
+//	})
+//
 // # Validation
 //
 // The package performs comprehensive validation:
@@ -85,6 +126,7 @@
 //   - Unique IDs within steps/legs/templates/aspects
 //   - Valid dependency references (needs/depends_on)
 //   - Cycle detection in dependency graphs
+//   - Known var types, enum choices and well-typed defaults
 //
 // # Cycle Detection
 //
@@ -114,6 +156,23 @@
 //	ready := f.ReadySteps(completed)
 //	// Returns: ["build"] (test is done, build can run)
 //
+// # Graphs
+//
+// The Graph method returns the dependency DAG for rendering as a Mermaid
+// flowchart or Graphviz DOT (see package graph):
+//
+//	fmt.Print(f.Graph().DOT())
+//
+// # Simulation
+//
+// Simulate dry-runs a resolved workflow with mock agents driven by a
+// SimScript (step ID or glob -> outcome and duration) and reports the
+// execution order, peak parallelism, critical path and steps that never
+// became ready, so formulas can have regression tests:
+//
+//	result, err := f.Simulate(vars, script, nil)
+//	if !result.Complete() { ... }
+//
 // # Embedded Formulas
 //
 // The package includes embedded formula files that can be provisioned
@@ -0,0 +1,382 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Per-item variables available in foreach steps.
+const (
+	ItemVar  = "item"
+	IndexVar = "index" // 1-based
+)
+
+// varRefRegex matches {{name}} references in templated fields.
+var varRefRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// varNameRegex matches a bare variable name in a when expression.
+var varNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// whenTerm is one comparison in a when expression.
+type whenTerm struct {
+	Var    string
+	Op     string // "", "==" or "!="
+	Value  string
+	Negate bool
+}
+
+// whenExpr is a parsed when expression: an OR of ANDs of terms.
+type whenExpr [][]whenTerm
+
+// parseWhen parses a when expression. The syntax is deliberately small:
+//
+//	var                 var is truthy (set, and not false/0/no/off)
+//	!var                var is not truthy
+//	var == value        var equals value (value may be quoted)
+//	var != value        var differs from value
+//	a && b, a || b      conjunction and disjunction (&& binds tighter)
+//
+// Variables may be written bare or as {{var}}.
+func parseWhen(expr string) (whenExpr, error) {
+	var out whenExpr
+	for _, orPart := range strings.Split(expr, "||") {
+		var and []whenTerm
+		for _, andPart := range strings.Split(orPart, "&&") {
+			term, err := parseWhenTerm(andPart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid when %q: %w", expr, err)
+			}
+			and = append(and, term)
+		}
+		out = append(out, and)
+	}
+	return out, nil
+}
+
+func parseWhenTerm(s string) (whenTerm, error) {
+	var term whenTerm
+	s = strings.TrimSpace(s)
+	for _, op := range []string{"==", "!="} {
+		if i := strings.Index(s, op); i >= 0 {
+			term.Op = op
+			term.Value = unquote(strings.TrimSpace(s[i+len(op):]))
+			s = strings.TrimSpace(s[:i])
+			break
+		}
+	}
+	if term.Op == "" && strings.HasPrefix(s, "!") {
+		term.Negate = true
+		s = strings.TrimSpace(s[1:])
+	}
+
+	name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, "{{"), "}}"))
+	if !varNameRegex.MatchString(name) {
+		if s == "" {
+			return term, fmt.Errorf("empty term")
+		}
+		return term, fmt.Errorf("%q is not a variable name", s)
+	}
+	term.Var = name
+	return term, nil
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// vars returns the variable names referenced by the expression.
+func (e whenExpr) vars() []string {
+	var names []string
+	for _, and := range e {
+		for _, term := range and {
+			names = append(names, term.Var)
+		}
+	}
+	return names
+}
+
+// eval evaluates the expression against resolved variable values.
+func (e whenExpr) eval(values map[string]string) bool {
+	for _, and := range e {
+		all := true
+		for _, term := range and {
+			if !term.eval(values) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+func (t whenTerm) eval(values map[string]string) bool {
+	v := values[t.Var]
+	switch t.Op {
+	case "==":
+		return v == t.Value
+	case "!=":
+		return v != t.Value
+	}
+	return truthy(v) != t.Negate
+}
+
+// truthy reports whether a variable value counts as true in a when expression.
+func truthy(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "false", "0", "no", "off":
+		return false
+	}
+	return true
+}
+
+// splitList splits a list-valued var on commas, dropping empty items.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// substituteVars replaces {{name}} references with known values, leaving
+// unknown references in place.
+func substituteVars(s string, values map[string]string) string {
+	return varRefRegex.ReplaceAllStringFunc(s, func(ref string) string {
+		name := varRefRegex.FindStringSubmatch(ref)[1]
+		if v, ok := values[name]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// declaresVar reports whether name is declared in vars or inputs.
+func (f *Formula) declaresVar(name string) bool {
+	if _, ok := f.Vars[name]; ok {
+		return true
+	}
+	_, ok := f.Inputs[name]
+	return ok
+}
+
+// ResolveVars merges provided values over the defaults of the formula's
+// vars and inputs. It fails with VarErrors if provided values don't
+// validate (see ValidateVars); bead and rig values are not looked up.
+func (f *Formula) ResolveVars(provided map[string]string) (map[string]string, error) {
+	if err := f.ValidateVars(provided, nil); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for _, spec := range f.VarSpecs() {
+		if spec.Default != "" {
+			values[spec.Name] = spec.Default
+		}
+	}
+	for name, v := range provided {
+		values[name] = v
+	}
+	return values, nil
+}
+
+// validateStepExpansion checks the when and foreach fields of a workflow
+// step. Needs are validated against step IDs as written, so a foreach step
+// is referenced by its templated ID (e.g. "test-{{item}}").
+func (f *Formula) validateStepExpansion(step Step) error {
+	templated := strings.Contains(step.ID, "{{")
+	if step.Foreach != "" {
+		if !f.declaresVar(step.Foreach) {
+			return fmt.Errorf("step %q foreach references unknown var: %s", step.ID, step.Foreach)
+		}
+		if !strings.Contains(step.ID, "{{"+ItemVar+"}}") {
+			return fmt.Errorf("foreach step %q must use {{%s}} in its id", step.ID, ItemVar)
+		}
+	} else if templated {
+		return fmt.Errorf("step %q has a templated id but no foreach", step.ID)
+	}
+
+	if step.When != "" {
+		expr, err := parseWhen(step.When)
+		if err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
+		for _, name := range expr.vars() {
+			if step.Foreach != "" && (name == ItemVar || name == IndexVar) {
+				continue
+			}
+			if !f.declaresVar(name) {
+				return fmt.Errorf("step %q when references unknown var: %s", step.ID, name)
+			}
+		}
+	}
+	return nil
+}
+
+// HasExpansion reports whether any step of a workflow uses when or
+// foreach, so the formula must be expanded before bd can pour it.
+func (f *Formula) HasExpansion() bool {
+	for _, step := range f.Steps {
+		if step.When != "" || step.Foreach != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// expander holds the state of one Expand call.
+type expander struct {
+	values    map[string]string
+	templates map[string]Step
+	instances map[string]map[string]string // template ID -> item -> concrete ID
+}
+
+// Expand returns a copy of a workflow formula with when conditions and
+// foreach loops applied, so every step is concrete. vars overrides the
+// defaults of the formula's vars and inputs.
+//
+// Skipped steps are removed. Steps that needed a skipped step inherit its
+// needs, so ordering through it is preserved. A need on a foreach step
+// means all of its instances, except from a step looping over the same
+// var, where it means the instance for the same item.
+//
+// Formulas of other types are returned unchanged.
+func (f *Formula) Expand(vars map[string]string) (*Formula, error) {
+	out := *f
+	if f.Type != TypeWorkflow {
+		return &out, nil
+	}
+
+	values, err := f.ResolveVars(vars)
+	if err != nil {
+		return nil, err
+	}
+	e := &expander{
+		values:    values,
+		templates: make(map[string]Step, len(f.Steps)),
+		instances: make(map[string]map[string]string, len(f.Steps)),
+	}
+
+	type instance struct {
+		step          Step
+		foreach, item string
+	}
+	var expanded []instance
+
+	for _, tmpl := range f.Steps {
+		e.templates[tmpl.ID] = tmpl
+		e.instances[tmpl.ID] = make(map[string]string)
+
+		var when whenExpr
+		if tmpl.When != "" {
+			if when, err = parseWhen(tmpl.When); err != nil {
+				return nil, fmt.Errorf("step %q: %w", tmpl.ID, err)
+			}
+		}
+
+		for i, item := range e.items(tmpl) {
+			ctx := values
+			if tmpl.Foreach != "" {
+				ctx = make(map[string]string, len(values)+2)
+				for k, v := range values {
+					ctx[k] = v
+				}
+				ctx[ItemVar] = item
+				ctx[IndexVar] = strconv.Itoa(i + 1)
+			}
+			if when != nil && !when.eval(ctx) {
+				continue
+			}
+
+			step := Step{
+				ID:          substituteVars(tmpl.ID, ctx),
+				Title:       substituteVars(tmpl.Title, ctx),
+				Description: substituteVars(tmpl.Description, ctx),
+				Needs:       tmpl.Needs, // resolved below
+				Source:      tmpl.Source,
+			}
+			if _, dup := e.instances[tmpl.ID][item]; dup {
+				return nil, fmt.Errorf("step %q: duplicate item %q in %s", tmpl.ID, item, tmpl.Foreach)
+			}
+			e.instances[tmpl.ID][item] = step.ID
+			expanded = append(expanded, instance{step: step, foreach: tmpl.Foreach, item: item})
+		}
+	}
+
+	seen := make(map[string]bool)
+	out.Steps = make([]Step, 0, len(expanded))
+	for _, inst := range expanded {
+		if seen[inst.step.ID] {
+			return nil, fmt.Errorf("expanded step id %q is not unique", inst.step.ID)
+		}
+		seen[inst.step.ID] = true
+		inst.step.Needs = e.resolveAll(inst.step.Needs, inst.foreach, inst.item)
+		out.Steps = append(out.Steps, inst.step)
+	}
+
+	if len(out.Steps) == 0 {
+		return nil, fmt.Errorf("no steps left after expansion")
+	}
+	return &out, nil
+}
+
+// items returns the loop items of a step, or a single empty item for a
+// step without foreach.
+func (e *expander) items(tmpl Step) []string {
+	if tmpl.Foreach == "" {
+		return []string{""}
+	}
+	return splitList(e.values[tmpl.Foreach])
+}
+
+// resolve maps a need, written in a step looping over foreach with the
+// given item (both empty outside a loop), to concrete step IDs.
+func (e *expander) resolve(need, foreach, item string) []string {
+	target := e.templates[need]
+	if item != "" && target.Foreach == foreach {
+		if id, ok := e.instances[need][item]; ok {
+			return []string{id}
+		}
+		// This item's instance was skipped; inherit its needs.
+		return e.resolveAll(target.Needs, foreach, item)
+	}
+
+	items := e.items(target)
+	if len(items) == 0 {
+		// Empty loop; inherit its needs outside any loop.
+		return e.resolveAll(target.Needs, "", "")
+	}
+	// All instances, with skipped ones replaced by their own needs.
+	var ids []string
+	for _, it := range items {
+		if id, ok := e.instances[need][it]; ok {
+			ids = append(ids, id)
+		} else {
+			ids = append(ids, e.resolveAll(target.Needs, target.Foreach, it)...)
+		}
+	}
+	return ids
+}
+
+// resolveAll resolves each need and returns the deduplicated union.
+func (e *expander) resolveAll(needs []string, foreach, item string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, need := range needs {
+		for _, id := range e.resolve(need, foreach, item) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
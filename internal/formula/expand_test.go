@@ -0,0 +1,239 @@
+package formula
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const expandWorkflow = `
+formula = "release"
+type = "workflow"
+
+[vars.platforms]
+description = "Platforms to build"
+default = "linux, darwin"
+
+[vars.publish]
+description = "Publish the release"
+default = "false"
+
+[[steps]]
+id = "prepare"
+title = "Prepare"
+
+[[steps]]
+id = "build-{{item}}"
+title = "Build {{item}} ({{index}})"
+needs = ["prepare"]
+foreach = "platforms"
+
+[[steps]]
+id = "test-{{item}}"
+title = "Test {{item}}"
+needs = ["build-{{item}}"]
+foreach = "platforms"
+when = "item != windows"
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["test-{{item}}"]
+when = "publish"
+
+[[steps]]
+id = "announce"
+title = "Announce"
+needs = ["publish"]
+`
+
+func stepNeeds(f *Formula) map[string][]string {
+	needs := make(map[string][]string)
+	for _, step := range f.Steps {
+		needs[step.ID] = step.Needs
+	}
+	return needs
+}
+
+func TestExpand_ForeachAndWhen(t *testing.T) {
+	f, err := Parse([]byte(expandWorkflow))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	expanded, err := f.Expand(map[string]string{"platforms": "linux,windows", "publish": "yes"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+
+	want := map[string][]string{
+		"prepare":       nil,
+		"build-linux":   {"prepare"},
+		"build-windows": {"prepare"},
+		"test-linux":    {"build-linux"},
+		"publish":       {"test-linux", "build-windows"}, // test-windows skipped
+		"announce":      {"publish"},
+	}
+	if got := stepNeeds(expanded); !reflect.DeepEqual(got, want) {
+		t.Errorf("expanded needs = %v, want %v", got, want)
+	}
+	if step := expanded.GetStep("build-windows"); step == nil || step.Title != "Build windows (2)" {
+		t.Errorf("build-windows = %+v", step)
+	}
+
+	// The original formula is untouched.
+	if len(f.Steps) != 5 {
+		t.Errorf("original steps = %d, want 5", len(f.Steps))
+	}
+}
+
+func TestEncodeConcrete_RoundTrip(t *testing.T) {
+	f, err := Parse([]byte(expandWorkflow))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !f.HasExpansion() {
+		t.Fatal("HasExpansion = false for a formula with when/foreach")
+	}
+
+	var buf bytes.Buffer
+	if err := f.EncodeConcrete(&buf, "release-x"); err == nil {
+		t.Error("EncodeConcrete accepted an unexpanded formula")
+	}
+
+	expanded, err := f.Expand(map[string]string{"platforms": "linux,windows", "publish": "yes"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	buf.Reset()
+	if err := expanded.EncodeConcrete(&buf, "release-x"); err != nil {
+		t.Fatalf("EncodeConcrete: %v", err)
+	}
+
+	got, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Parse encoded: %v\n%s", err, buf.String())
+	}
+	if got.Name != "release-x" || got.HasExpansion() {
+		t.Errorf("encoded formula = %q, HasExpansion %v", got.Name, got.HasExpansion())
+	}
+	if !reflect.DeepEqual(stepNeeds(got), stepNeeds(expanded)) {
+		t.Errorf("encoded needs = %v, want %v", stepNeeds(got), stepNeeds(expanded))
+	}
+	if v, ok := got.Vars["platforms"]; !ok || v.Default != "linux, darwin" {
+		t.Errorf("encoded vars = %+v", got.Vars)
+	}
+}
+
+func TestExpand_SkippedStepPassesNeedsThrough(t *testing.T) {
+	f, err := Parse([]byte(expandWorkflow))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	expanded, err := f.Expand(nil) // defaults: linux, darwin; publish=false
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if expanded.GetStep("publish") != nil {
+		t.Error("publish should be skipped")
+	}
+	announce := expanded.GetStep("announce")
+	if announce == nil || !reflect.DeepEqual(announce.Needs, []string{"test-linux", "test-darwin"}) {
+		t.Errorf("announce = %+v, want needs [test-linux test-darwin]", announce)
+	}
+
+	order, err := expanded.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort: %v", err)
+	}
+	if order[0] != "prepare" || order[len(order)-1] != "announce" {
+		t.Errorf("order = %v", order)
+	}
+
+	ready := expanded.ReadySteps(map[string]bool{"prepare": true, "build-linux": true})
+	if !reflect.DeepEqual(ready, []string{"build-darwin", "test-linux"}) {
+		t.Errorf("ReadySteps = %v", ready)
+	}
+}
+
+func TestResolveVars(t *testing.T) {
+	f := &Formula{
+		Vars: map[string]Var{
+			"feature": {Required: true},
+			"mode":    {Default: "fast"},
+		},
+		Inputs: map[string]Input{
+			"pr":     {Required: true, RequiredUnless: []string{"branch"}},
+			"branch": {},
+		},
+	}
+
+	if _, err := f.ResolveVars(nil); err == nil || !strings.Contains(err.Error(), "feature, pr") {
+		t.Errorf("ResolveVars(nil) error = %v, want missing feature, pr", err)
+	}
+	if _, err := f.ResolveVars(map[string]string{"nope": "x"}); err == nil {
+		t.Error("ResolveVars with undeclared var should fail")
+	}
+
+	values, err := f.ResolveVars(map[string]string{"feature": "auth", "branch": "main"})
+	if err != nil {
+		t.Fatalf("ResolveVars: %v", err)
+	}
+	if values["mode"] != "fast" || values["feature"] != "auth" {
+		t.Errorf("values = %v", values)
+	}
+}
+
+func TestWhenExpressions(t *testing.T) {
+	values := map[string]string{"env": "prod", "dry": "false", "fast": "yes"}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"fast", true},
+		{"dry", false},
+		{"!dry", true},
+		{"{{env}} == prod", true},
+		{`env == "staging"`, false},
+		{"env != staging && fast", true},
+		{"dry || env == prod", true},
+		{"dry || missing", false},
+	}
+	for _, tt := range tests {
+		expr, err := parseWhen(tt.expr)
+		if err != nil {
+			t.Errorf("parseWhen(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := expr.eval(values); got != tt.want {
+			t.Errorf("eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	if _, err := parseWhen("env == prod &&"); err == nil {
+		t.Error("parseWhen with empty term should fail")
+	}
+}
+
+func TestParse_ExpansionValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		step string
+		want string
+	}{
+		{"foreach without item in id", `id = "build"` + "\nforeach = \"platforms\"", "must use {{item}}"},
+		{"foreach unknown var", `id = "b-{{item}}"` + "\nforeach = \"nope\"", "unknown var: nope"},
+		{"templated id without foreach", `id = "b-{{item}}"`, "no foreach"},
+		{"when unknown var", `id = "b"` + "\nwhen = \"nope == 1\"", "unknown var: nope"},
+		{"item outside foreach", `id = "b"` + "\nwhen = \"item\"", "unknown var: item"},
+	}
+	for _, tt := range tests {
+		data := "formula = \"x\"\n[vars.platforms]\ndefault = \"a\"\n[[steps]]\n" + tt.step + "\n"
+		_, err := Parse([]byte(data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want containing %q", tt.name, err, tt.want)
+		}
+	}
+}
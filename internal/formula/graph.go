@@ -0,0 +1,42 @@
+package formula
+
+import "github.com/steveyegge/gastown/internal/graph"
+
+// Graph returns the dependency graph of the formula's steps, legs,
+// templates or aspects, with an edge from each dependency to its
+// dependent. Convoy formulas include the synthesis step, fed by its legs.
+//
+// Composed workflows should be resolved (and expanded, for concrete
+// foreach steps) first; otherwise the graph shows only their own steps.
+func (f *Formula) Graph() *graph.Graph {
+	g := graph.New(f.Name)
+
+	switch f.Type {
+	case TypeWorkflow:
+		for _, step := range f.Steps {
+			g.AddNode(graph.Node{ID: step.ID, Title: step.Title})
+		}
+	case TypeExpansion:
+		for _, tmpl := range f.Template {
+			g.AddNode(graph.Node{ID: tmpl.ID, Title: tmpl.Title})
+		}
+	case TypeConvoy:
+		for _, leg := range f.Legs {
+			g.AddNode(graph.Node{ID: leg.ID, Title: leg.Title})
+		}
+		if f.Synthesis != nil {
+			g.AddNode(graph.Node{ID: "synthesis", Title: f.Synthesis.Title})
+		}
+	case TypeAspect:
+		for _, aspect := range f.Aspects {
+			g.AddNode(graph.Node{ID: aspect.ID, Title: aspect.Title})
+		}
+	}
+
+	for _, n := range g.Nodes {
+		for _, dep := range f.GetDependencies(n.ID) {
+			g.AddEdge(dep, n.ID)
+		}
+	}
+	return g
+}
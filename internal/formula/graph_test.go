@@ -0,0 +1,53 @@
+package formula
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+func TestGraph_Workflow(t *testing.T) {
+	f, err := Parse([]byte(expandWorkflow))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	expanded, err := f.Expand(map[string]string{"platforms": "linux"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+
+	g := expanded.Graph()
+	if g.Title != "release" || len(g.Nodes) != 4 {
+		t.Fatalf("graph = %+v", g)
+	}
+	want := []graph.Edge{
+		{From: "prepare", To: "build-linux"},
+		{From: "build-linux", To: "test-linux"},
+		{From: "test-linux", To: "announce"},
+	}
+	if !reflect.DeepEqual(g.Edges, want) {
+		t.Errorf("edges = %v, want %v", g.Edges, want)
+	}
+}
+
+func TestGraph_ConvoySynthesis(t *testing.T) {
+	f := &Formula{
+		Name: "review",
+		Type: TypeConvoy,
+		Legs: []Leg{{ID: "security", Title: "Security"}, {ID: "perf", Title: "Performance"}},
+		Synthesis: &Synthesis{
+			Title:     "Combine findings",
+			DependsOn: []string{"security", "perf"},
+		},
+	}
+
+	g := f.Graph()
+	if len(g.Nodes) != 3 || g.Nodes[2].ID != "synthesis" {
+		t.Fatalf("nodes = %+v", g.Nodes)
+	}
+	want := []graph.Edge{{From: "security", To: "synthesis"}, {From: "perf", To: "synthesis"}}
+	if !reflect.DeepEqual(g.Edges, want) {
+		t.Errorf("edges = %v, want %v", g.Edges, want)
+	}
+}
@@ -2,6 +2,7 @@ package formula
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/BurntSushi/toml"
@@ -13,7 +14,12 @@ func ParseFile(path string) (*Formula, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading formula file: %w", err)
 	}
-	return Parse(data)
+	f, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	f.Path = path
+	return f, nil
 }
 
 // Parse parses formula.toml content from bytes.
@@ -33,6 +39,71 @@ func Parse(data []byte) (*Formula, error) {
 	return &f, nil
 }
 
+// concreteWorkflow is the TOML form EncodeConcrete writes: a plain
+// workflow with nothing left to expand.
+type concreteWorkflow struct {
+	Name        string                 `toml:"formula"`
+	Description string                 `toml:"description,omitempty"`
+	Type        FormulaType            `toml:"type"`
+	Version     int                    `toml:"version,omitempty"`
+	Vars        map[string]concreteVar `toml:"vars,omitempty"`
+	Steps       []concreteStep         `toml:"steps"`
+}
+
+type concreteVar struct {
+	Description string `toml:"description,omitempty"`
+	Required    bool   `toml:"required,omitempty"`
+	Default     string `toml:"default,omitempty"`
+}
+
+type concreteStep struct {
+	ID          string   `toml:"id"`
+	Title       string   `toml:"title"`
+	Description string   `toml:"description,omitempty"`
+	Needs       []string `toml:"needs,omitempty"`
+}
+
+// EncodeConcrete writes a resolved and expanded workflow formula (see
+// Resolve and Expand) as TOML under the given name, for bd to cook and
+// pour. Vars and inputs are written as vars; their types are checked by gt
+// before pouring.
+func (f *Formula) EncodeConcrete(w io.Writer, name string) error {
+	if f.Type != TypeWorkflow {
+		return fmt.Errorf("formula %s is %s, not a workflow", f.Name, f.Type)
+	}
+	if f.IsComposed() {
+		return fmt.Errorf("formula %s extends or composes other formulas; resolve it first", f.Name)
+	}
+	out := concreteWorkflow{
+		Name:        name,
+		Description: f.Description,
+		Type:        f.Type,
+		Version:     f.Version,
+	}
+	for _, step := range f.Steps {
+		if step.When != "" || step.Foreach != "" {
+			return fmt.Errorf("step %q has when/foreach; expand the formula first", step.ID)
+		}
+		out.Steps = append(out.Steps, concreteStep{
+			ID:          step.ID,
+			Title:       step.Title,
+			Description: step.Description,
+			Needs:       step.Needs,
+		})
+	}
+	for _, spec := range f.VarSpecs() {
+		if out.Vars == nil {
+			out.Vars = make(map[string]concreteVar)
+		}
+		out.Vars[spec.Name] = concreteVar{
+			Description: spec.Description,
+			Required:    spec.Required,
+			Default:     spec.Default,
+		}
+	}
+	return toml.NewEncoder(w).Encode(out)
+}
+
 // inferType sets the formula type based on content when not explicitly set.
 func (f *Formula) inferType() {
 	if f.Type != "" {
@@ -40,13 +111,13 @@ func (f *Formula) inferType() {
 	}
 
 	// Infer from content
-	if len(f.Steps) > 0 {
+	if len(f.Steps) > 0 || len(f.Extends) > 0 {
 		f.Type = TypeWorkflow
 	} else if len(f.Legs) > 0 {
 		f.Type = TypeConvoy
 	} else if len(f.Template) > 0 {
 		f.Type = TypeExpansion
-	} else if len(f.Aspects) > 0 {
+	} else if len(f.Aspects) > 0 || len(f.Advice) > 0 {
 		f.Type = TypeAspect
 	}
 }
@@ -62,6 +133,10 @@ func (f *Formula) Validate() error {
 		return fmt.Errorf("invalid formula type %q (must be convoy, workflow, expansion, or aspect)", f.Type)
 	}
 
+	if err := f.validateVarSpecs(); err != nil {
+		return err
+	}
+
 	// Type-specific validation
 	switch f.Type {
 	case TypeConvoy:
@@ -107,6 +182,11 @@ func (f *Formula) validateConvoy() error {
 }
 
 func (f *Formula) validateWorkflow() error {
+	if f.IsComposed() {
+		// Steps may reference steps from other formulas; the resolved
+		// formula is validated by Resolve.
+		return f.validateComposition()
+	}
 	if len(f.Steps) == 0 {
 		return fmt.Errorf("workflow formula requires at least one step")
 	}
@@ -123,13 +203,16 @@ func (f *Formula) validateWorkflow() error {
 		seen[step.ID] = true
 	}
 
-	// Validate step needs references
+	// Validate step needs references and when/foreach
 	for _, step := range f.Steps {
 		for _, need := range step.Needs {
 			if !seen[need] {
 				return fmt.Errorf("step %q needs unknown step: %s", step.ID, need)
 			}
 		}
+		if err := f.validateStepExpansion(step); err != nil {
+			return err
+		}
 	}
 
 	// Check for cycles
@@ -170,8 +253,11 @@ func (f *Formula) validateExpansion() error {
 }
 
 func (f *Formula) validateAspect() error {
-	if len(f.Aspects) == 0 {
-		return fmt.Errorf("aspect formula requires at least one aspect")
+	if len(f.Aspects) == 0 && len(f.Advice) == 0 {
+		return fmt.Errorf("aspect formula requires at least one aspect or advice")
+	}
+	if err := f.validateAdvice(); err != nil {
+		return err
 	}
 
 	// Check aspect IDs are unique
@@ -189,7 +275,9 @@ func (f *Formula) validateAspect() error {
 	return nil
 }
 
-// checkCycles detects circular dependencies in steps.
+// checkCycles detects circular dependencies in steps. It runs on the
+// unexpanded steps: every edge Expand produces maps back to a needs entry
+// between the templates, so an acyclic template graph expands acyclically.
 func (f *Formula) checkCycles() error {
 	// Build adjacency list
 	deps := make(map[string][]string)
@@ -232,7 +320,8 @@ func (f *Formula) checkCycles() error {
 }
 
 // TopologicalSort returns steps in dependency order (dependencies before dependents).
-// Only applicable to workflow and expansion formulas.
+// Only applicable to workflow and expansion formulas. For workflows with
+// when or foreach steps, call Expand first to sort the concrete steps.
 // Returns an error if there are cycles.
 func (f *Formula) TopologicalSort() ([]string, error) {
 	var items []string
@@ -323,7 +412,8 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 }
 
 // ReadySteps returns steps that have no unmet dependencies.
-// completed is a set of step IDs that have been completed.
+// completed is a set of step IDs that have been completed. For workflows
+// with when or foreach steps, call it on the expanded formula.
 func (f *Formula) ReadySteps(completed map[string]bool) []string {
 	var ready []string
 
@@ -0,0 +1,243 @@
+package formula
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcome is how a mock agent finishes a step in a simulation.
+type Outcome string
+
+// Simulation outcomes.
+const (
+	OutcomeDone  Outcome = "done"  // the step closes after its duration
+	OutcomeFail  Outcome = "fail"  // the step fails; its dependents never start
+	OutcomeStuck Outcome = "stuck" // the agent never finishes and keeps its slot
+)
+
+// DefaultSimDuration is how long a step takes when the script doesn't say.
+const DefaultSimDuration = time.Minute
+
+// StepScript scripts one step: how it ends and how long it takes.
+type StepScript struct {
+	Outcome  Outcome
+	Duration time.Duration
+}
+
+// SimScript drives the mock agents of a simulation.
+//
+// Scripts are a small YAML subset:
+//
+//	agents: 2              # concurrent agents (0 or absent: unlimited)
+//	default:
+//	  duration: 5m
+//	steps:
+//	  implement:
+//	    duration: 30m
+//	  "test-*":            # globs match expanded step IDs
+//	    duration: 10m
+//	  review: {outcome: fail, duration: 2m}
+//
+// Outcomes are done (the default), fail and stuck.
+type SimScript struct {
+	Agents  int
+	Default StepScript
+	Steps   map[string]StepScript
+	order   []string // step keys in file order, for glob precedence
+}
+
+// NewSimScript returns a script where every step succeeds after
+// DefaultSimDuration with unlimited agents.
+func NewSimScript() *SimScript {
+	return &SimScript{
+		Default: StepScript{Outcome: OutcomeDone, Duration: DefaultSimDuration},
+		Steps:   make(map[string]StepScript),
+	}
+}
+
+// SetStep scripts a step, or the steps matching a glob.
+func (s *SimScript) SetStep(pattern string, step StepScript) {
+	if _, ok := s.Steps[pattern]; !ok {
+		s.order = append(s.order, pattern)
+	}
+	s.Steps[pattern] = step
+}
+
+// For returns the script for a step ID: an exact entry, else the first
+// matching glob, else the default. Unset fields fall back to the default.
+func (s *SimScript) For(stepID string) StepScript {
+	step, ok := s.Steps[stepID]
+	if !ok {
+		for _, pattern := range s.order {
+			if matched, _ := path.Match(pattern, stepID); matched {
+				step, ok = s.Steps[pattern], true
+				break
+			}
+		}
+	}
+	if !ok {
+		return s.Default
+	}
+	if step.Outcome == "" {
+		step.Outcome = s.Default.Outcome
+	}
+	if step.Duration == 0 {
+		step.Duration = s.Default.Duration
+	}
+	return step
+}
+
+// ParseSimScriptFile reads a simulation script file.
+func ParseSimScriptFile(path string) (*SimScript, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("reading simulation script: %w", err)
+	}
+	return ParseSimScript(data)
+}
+
+// ParseSimScript parses a simulation script (see SimScript).
+func ParseSimScript(data []byte) (*SimScript, error) {
+	s := NewSimScript()
+	section := ""    // "default" or "steps"
+	current := ""    // step key being filled, under steps
+	stepIndent := -1 // indentation of step keys
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNo := i + 1
+		line := stripYAMLComment(raw)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if strings.HasPrefix(strings.TrimLeft(line, " "), "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNo)
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", lineNo)
+		}
+		key = unquote(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if indent == 0 {
+			section, current, stepIndent = "", "", -1
+			switch key {
+			case "agents":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("line %d: agents must be a non-negative integer", lineNo)
+				}
+				s.Agents = n
+			case "default", "steps":
+				if value != "" {
+					return nil, fmt.Errorf("line %d: %s must be a mapping", lineNo, key)
+				}
+				section = key
+			default:
+				return nil, fmt.Errorf("line %d: unknown key %q", lineNo, key)
+			}
+			continue
+		}
+
+		switch section {
+		case "default":
+			if err := setStepField(&s.Default, key, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+		case "steps":
+			if stepIndent < 0 {
+				stepIndent = indent
+			}
+			if indent == stepIndent {
+				current = key
+				step := StepScript{}
+				if value != "" {
+					if err := parseFlowStep(&step, value); err != nil {
+						return nil, fmt.Errorf("line %d: %w", lineNo, err)
+					}
+				}
+				s.SetStep(current, step)
+				continue
+			}
+			if indent < stepIndent || current == "" {
+				return nil, fmt.Errorf("line %d: unexpected indentation", lineNo)
+			}
+			step := s.Steps[current]
+			if err := setStepField(&step, key, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			s.Steps[current] = step
+		default:
+			return nil, fmt.Errorf("line %d: unexpected indentation", lineNo)
+		}
+	}
+
+	return s, nil
+}
+
+// parseFlowStep parses an inline {outcome: x, duration: y} mapping.
+func parseFlowStep(step *StepScript, value string) error {
+	if !strings.HasPrefix(value, "{") || !strings.HasSuffix(value, "}") {
+		return fmt.Errorf("step value must be a mapping, got %q", value)
+	}
+	for _, field := range strings.Split(value[1:len(value)-1], ",") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		key, v, ok := strings.Cut(field, ":")
+		if !ok {
+			return fmt.Errorf("expected key: value in %q", value)
+		}
+		if err := setStepField(step, strings.TrimSpace(key), strings.TrimSpace(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setStepField sets the outcome or duration of a step script.
+func setStepField(step *StepScript, key, value string) error {
+	value = unquote(value)
+	switch key {
+	case "outcome":
+		switch o := Outcome(strings.ToLower(value)); o {
+		case OutcomeDone, OutcomeFail, OutcomeStuck:
+			step.Outcome = o
+		default:
+			return fmt.Errorf("unknown outcome %q (must be done, fail or stuck)", value)
+		}
+	case "duration":
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		step.Duration = d
+	default:
+		return fmt.Errorf("unknown step field %q (must be outcome or duration)", key)
+	}
+	return nil
+}
+
+// stripYAMLComment removes a trailing # comment outside quotes.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return strings.TrimRight(line, " \t\r")
+}
@@ -0,0 +1,71 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSimScript(t *testing.T) {
+	s, err := ParseSimScript([]byte(`
+# mock agents for the release formula
+agents: 3
+default:
+  duration: 2m   # most steps are quick
+steps:
+  implement:
+    outcome: done
+    duration: 30m
+  "test-*":
+    duration: 10m
+  review: {outcome: fail, duration: "90s"}
+`))
+	if err != nil {
+		t.Fatalf("ParseSimScript: %v", err)
+	}
+	if s.Agents != 3 {
+		t.Errorf("Agents = %d, want 3", s.Agents)
+	}
+
+	tests := []struct {
+		step     string
+		outcome  Outcome
+		duration time.Duration
+	}{
+		{"implement", OutcomeDone, 30 * time.Minute},
+		{"test-linux", OutcomeDone, 10 * time.Minute}, // glob, default outcome
+		{"review", OutcomeFail, 90 * time.Second},
+		{"prepare", OutcomeDone, 2 * time.Minute}, // default
+	}
+	for _, tt := range tests {
+		got := s.For(tt.step)
+		if got.Outcome != tt.outcome || got.Duration != tt.duration {
+			t.Errorf("For(%q) = %+v, want %s/%v", tt.step, got, tt.outcome, tt.duration)
+		}
+	}
+
+	if got := NewSimScript().For("anything"); got.Outcome != OutcomeDone || got.Duration != DefaultSimDuration {
+		t.Errorf("default script = %+v", got)
+	}
+}
+
+func TestParseSimScript_Errors(t *testing.T) {
+	tests := []struct {
+		script string
+		want   string
+	}{
+		{"agents: many\n", "agents must be"},
+		{"timeout: 5m\n", `unknown key "timeout"`},
+		{"steps:\n  build:\n    outcome: maybe\n", "unknown outcome"},
+		{"steps:\n  build:\n    duration: soon\n", "invalid duration"},
+		{"steps:\n  build:\n    retries: 2\n", "unknown step field"},
+		{"steps:\n  build: fast\n", "must be a mapping"},
+		{"  duration: 5m\n", "line 1: unexpected indentation"},
+	}
+	for _, tt := range tests {
+		_, err := ParseSimScript([]byte(tt.script))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseSimScript(%q) error = %v, want containing %q", tt.script, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package formula
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SimTracker records the state of a simulated run, the way beads track a
+// poured molecule. The in-memory tracker is used by default; the CLI backs
+// simulations with a throwaway beads database instead.
+type SimTracker interface {
+	// Pour creates one tracked item per step, with its needs as blocking
+	// dependencies.
+	Pour(f *Formula) error
+	// Start marks a step in progress, assigned to agent.
+	Start(stepID, agent string) error
+	// Finish records the outcome of a step. Only OutcomeDone unblocks
+	// dependents.
+	Finish(stepID string, outcome Outcome) error
+	// Ready returns the steps that are not started and whose needs are done.
+	Ready() ([]string, error)
+}
+
+// SimStep is one step run by a mock agent.
+type SimStep struct {
+	ID      string        `json:"id"`
+	Agent   string        `json:"agent"`
+	Start   time.Duration `json:"start"`
+	End     time.Duration `json:"end,omitempty"` // zero for stuck steps
+	Outcome Outcome       `json:"outcome"`
+}
+
+// SimBlocked is a step that never started. BlockedBy lists the needs
+// that never completed; it is empty for a step that was ready but found
+// every agent stuck.
+type SimBlocked struct {
+	ID        string   `json:"id"`
+	BlockedBy []string `json:"blocked_by"`
+}
+
+// SimResult is the report of a simulation.
+type SimResult struct {
+	Formula string `json:"formula"`
+	Agents  int    `json:"agents"` // 0 means unlimited
+
+	// Steps in the order they started.
+	Steps []SimStep `json:"steps"`
+	// Makespan is when the last finishing step finished.
+	Makespan time.Duration `json:"makespan"`
+	// MaxParallel is the largest number of steps running at once.
+	MaxParallel int `json:"max_parallel"`
+
+	// CriticalPath is the longest chain of scripted durations through the
+	// step graph: the makespan with unlimited agents and no failures.
+	CriticalPath       []string      `json:"critical_path"`
+	CriticalPathLength time.Duration `json:"critical_path_length"`
+
+	Failed     []string     `json:"failed,omitempty"`
+	Stuck      []string     `json:"stuck,omitempty"`
+	NeverReady []SimBlocked `json:"never_ready,omitempty"`
+}
+
+// Complete reports whether every step finished successfully.
+func (r *SimResult) Complete() bool {
+	return len(r.Failed) == 0 && len(r.Stuck) == 0 && len(r.NeverReady) == 0
+}
+
+// Simulate runs a workflow formula with scripted mock agents instead of
+// real agent sessions. The formula must already be
+// resolved (see Resolve); vars are applied with Expand. A nil script runs
+// every step successfully for DefaultSimDuration, and a nil tracker keeps
+// state in memory.
+//
+// Steps are started in formula order as agents free up; a step finishing
+// at the same time another becomes ready hands its agent over at once.
+func (f *Formula) Simulate(vars map[string]string, script *SimScript, tracker SimTracker) (*SimResult, error) {
+	if f.Type != TypeWorkflow {
+		return nil, fmt.Errorf("only workflow formulas can be simulated (%s is %s)", f.Name, f.Type)
+	}
+	if f.IsComposed() {
+		return nil, fmt.Errorf("formula %s uses composition; resolve it first", f.Name)
+	}
+	expanded, err := f.Expand(vars)
+	if err != nil {
+		return nil, err
+	}
+	if script == nil {
+		script = NewSimScript()
+	}
+	if tracker == nil {
+		tracker = NewMemTracker()
+	}
+	if err := tracker.Pour(expanded); err != nil {
+		return nil, fmt.Errorf("pouring simulation: %w", err)
+	}
+
+	result := &SimResult{Formula: f.Name, Agents: script.Agents}
+	result.CriticalPath, result.CriticalPathLength = expanded.criticalPath(script)
+
+	position := make(map[string]int, len(expanded.Steps))
+	for i, step := range expanded.Steps {
+		position[step.ID] = i
+	}
+
+	type running struct {
+		index int // into result.Steps
+		end   time.Duration
+		stuck bool
+	}
+	var active []running
+	var freeAgents []string // a stack, so the lowest-numbered agent goes first
+	for i := script.Agents; i >= 1; i-- {
+		freeAgents = append(freeAgents, fmt.Sprintf("mock-%d", i))
+	}
+	nextAgent := script.Agents + 1
+	started := make(map[string]bool)
+	finished := make(map[string]Outcome)
+	var now time.Duration
+
+	for {
+		ready, err := tracker.Ready()
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(ready, func(i, j int) bool { return position[ready[i]] < position[ready[j]] })
+		for _, id := range ready {
+			if started[id] {
+				continue
+			}
+			var agent string
+			if len(freeAgents) > 0 {
+				agent = freeAgents[len(freeAgents)-1]
+				freeAgents = freeAgents[:len(freeAgents)-1]
+			} else if script.Agents == 0 {
+				agent = fmt.Sprintf("mock-%d", nextAgent)
+				nextAgent++
+			} else {
+				break
+			}
+			if err := tracker.Start(id, agent); err != nil {
+				return nil, err
+			}
+			started[id] = true
+
+			s := script.For(id)
+			run := running{index: len(result.Steps), end: now + s.Duration, stuck: s.Outcome == OutcomeStuck}
+			result.Steps = append(result.Steps, SimStep{ID: id, Agent: agent, Start: now, Outcome: s.Outcome})
+			active = append(active, run)
+		}
+		if len(active) > result.MaxParallel {
+			result.MaxParallel = len(active)
+		}
+
+		// Advance to the next finishing step(s).
+		next := time.Duration(-1)
+		for _, run := range active {
+			if !run.stuck && (next < 0 || run.end < next) {
+				next = run.end
+			}
+		}
+		if next < 0 {
+			break
+		}
+		now = next
+		remaining := active[:0]
+		for _, run := range active {
+			if run.stuck || run.end != now {
+				remaining = append(remaining, run)
+				continue
+			}
+			step := &result.Steps[run.index]
+			step.End = now
+			if err := tracker.Finish(step.ID, step.Outcome); err != nil {
+				return nil, err
+			}
+			finished[step.ID] = step.Outcome
+			if step.Outcome == OutcomeFail {
+				result.Failed = append(result.Failed, step.ID)
+			}
+			freeAgents = append(freeAgents, step.Agent)
+			result.Makespan = now
+		}
+		active = remaining
+	}
+
+	for _, run := range active {
+		result.Stuck = append(result.Stuck, result.Steps[run.index].ID)
+	}
+	for _, step := range expanded.Steps {
+		if started[step.ID] {
+			continue
+		}
+		blocked := SimBlocked{ID: step.ID}
+		for _, need := range step.Needs {
+			if finished[need] != OutcomeDone {
+				blocked.BlockedBy = append(blocked.BlockedBy, need)
+			}
+		}
+		result.NeverReady = append(result.NeverReady, blocked)
+	}
+	return result, nil
+}
+
+// criticalPath returns the longest chain of scripted durations through the
+// steps, and its length.
+func (f *Formula) criticalPath(script *SimScript) ([]string, time.Duration) {
+	order, err := f.TopologicalSort()
+	if err != nil {
+		return nil, 0
+	}
+	finish := make(map[string]time.Duration, len(order))
+	prev := make(map[string]string, len(order))
+	var last string
+	for _, id := range order {
+		var start time.Duration
+		for _, need := range f.GetStep(id).Needs {
+			if prev[id] == "" || finish[need] > start {
+				start = finish[need]
+				prev[id] = need
+			}
+		}
+		finish[id] = start + script.For(id).Duration
+		if last == "" || finish[id] > finish[last] {
+			last = id
+		}
+	}
+
+	var path []string
+	for id := last; id != ""; id = prev[id] {
+		path = append([]string{id}, path...)
+	}
+	return path, finish[last]
+}
+
+// memTracker is the in-memory SimTracker.
+type memTracker struct {
+	needs   map[string][]string
+	order   []string
+	started map[string]bool
+	done    map[string]bool
+}
+
+// NewMemTracker returns a SimTracker that keeps state in memory.
+func NewMemTracker() SimTracker {
+	return &memTracker{
+		needs:   make(map[string][]string),
+		started: make(map[string]bool),
+		done:    make(map[string]bool),
+	}
+}
+
+func (m *memTracker) Pour(f *Formula) error {
+	for _, step := range f.Steps {
+		m.order = append(m.order, step.ID)
+		m.needs[step.ID] = step.Needs
+	}
+	return nil
+}
+
+func (m *memTracker) Start(stepID, agent string) error {
+	m.started[stepID] = true
+	return nil
+}
+
+func (m *memTracker) Finish(stepID string, outcome Outcome) error {
+	m.done[stepID] = outcome == OutcomeDone
+	return nil
+}
+
+func (m *memTracker) Ready() ([]string, error) {
+	var ready []string
+	for _, id := range m.order {
+		if m.started[id] {
+			continue
+		}
+		met := true
+		for _, need := range m.needs[id] {
+			if !m.done[need] {
+				met = false
+				break
+			}
+		}
+		if met {
+			ready = append(ready, id)
+		}
+	}
+	return ready, nil
+}
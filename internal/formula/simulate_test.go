@@ -0,0 +1,123 @@
+package formula
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var releaseVars = map[string]string{"platforms": "linux,darwin", "publish": "yes"}
+
+func simulate(t *testing.T, script string) *SimResult {
+	t.Helper()
+	f, err := Parse([]byte(expandWorkflow))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	s, err := ParseSimScript([]byte(script))
+	if err != nil {
+		t.Fatalf("ParseSimScript: %v", err)
+	}
+	result, err := f.Simulate(releaseVars, s, nil)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	return result
+}
+
+func stepIDs(steps []SimStep) []string {
+	ids := make([]string, len(steps))
+	for i, s := range steps {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+const releaseScript = `
+steps:
+  "build-*":
+    duration: 10m
+  test-darwin: {duration: 5m}
+`
+
+func TestSimulate_UnlimitedAgents(t *testing.T) {
+	r := simulate(t, releaseScript)
+
+	wantOrder := []string{"prepare", "build-linux", "build-darwin", "test-linux", "test-darwin", "publish", "announce"}
+	if got := stepIDs(r.Steps); !reflect.DeepEqual(got, wantOrder) {
+		t.Errorf("order = %v, want %v", got, wantOrder)
+	}
+	if r.MaxParallel != 2 {
+		t.Errorf("MaxParallel = %d, want 2", r.MaxParallel)
+	}
+	if r.Makespan != 18*time.Minute {
+		t.Errorf("Makespan = %v, want 18m", r.Makespan)
+	}
+	wantPath := []string{"prepare", "build-darwin", "test-darwin", "publish", "announce"}
+	if !reflect.DeepEqual(r.CriticalPath, wantPath) || r.CriticalPathLength != 18*time.Minute {
+		t.Errorf("critical path = %v (%v), want %v (18m)", r.CriticalPath, r.CriticalPathLength, wantPath)
+	}
+	if !r.Complete() {
+		t.Errorf("simulation should complete: %+v", r)
+	}
+}
+
+func TestSimulate_LimitedAgents(t *testing.T) {
+	r := simulate(t, "agents: 1\n"+releaseScript)
+
+	if r.MaxParallel != 1 {
+		t.Errorf("MaxParallel = %d, want 1", r.MaxParallel)
+	}
+	if r.Makespan != 29*time.Minute {
+		t.Errorf("Makespan = %v, want 29m", r.Makespan)
+	}
+	for _, s := range r.Steps {
+		if s.Agent != "mock-1" {
+			t.Errorf("%s ran on %s, want mock-1", s.ID, s.Agent)
+		}
+	}
+	if r.CriticalPathLength != 18*time.Minute {
+		t.Errorf("critical path length should not depend on agents: %v", r.CriticalPathLength)
+	}
+}
+
+func TestSimulate_FailureBlocksDependents(t *testing.T) {
+	r := simulate(t, "steps:\n  test-linux:\n    outcome: fail\n")
+
+	if !reflect.DeepEqual(r.Failed, []string{"test-linux"}) {
+		t.Errorf("Failed = %v", r.Failed)
+	}
+	want := []SimBlocked{
+		{ID: "publish", BlockedBy: []string{"test-linux"}},
+		{ID: "announce", BlockedBy: []string{"publish"}},
+	}
+	if !reflect.DeepEqual(r.NeverReady, want) {
+		t.Errorf("NeverReady = %+v, want %+v", r.NeverReady, want)
+	}
+	if r.Complete() {
+		t.Error("simulation with a failure should not be complete")
+	}
+}
+
+func TestSimulate_StuckAgentStarvesReadySteps(t *testing.T) {
+	r := simulate(t, "agents: 1\nsteps:\n  build-linux: {outcome: stuck}\n")
+
+	if !reflect.DeepEqual(r.Stuck, []string{"build-linux"}) {
+		t.Errorf("Stuck = %v", r.Stuck)
+	}
+	if len(r.NeverReady) == 0 || r.NeverReady[0].ID != "build-darwin" || len(r.NeverReady[0].BlockedBy) != 0 {
+		t.Errorf("build-darwin should be ready but starved: %+v", r.NeverReady)
+	}
+}
+
+func TestSimulate_Errors(t *testing.T) {
+	convoy := &Formula{Name: "review", Type: TypeConvoy, Legs: []Leg{{ID: "a"}}}
+	if _, err := convoy.Simulate(nil, nil, nil); err == nil || !strings.Contains(err.Error(), "only workflow") {
+		t.Errorf("convoy Simulate error = %v", err)
+	}
+	composed := &Formula{Name: "child", Type: TypeWorkflow, Extends: []string{"base"}}
+	if _, err := composed.Simulate(nil, nil, nil); err == nil || !strings.Contains(err.Error(), "resolve it first") {
+		t.Errorf("composed Simulate error = %v", err)
+	}
+}
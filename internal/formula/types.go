@@ -31,21 +31,77 @@ type Formula struct {
 	Version     int         `toml:"version"`
 
 	// Convoy-specific
-	Inputs    map[string]Input `toml:"inputs"`
+	Inputs    map[string]Input  `toml:"inputs"`
 	Prompts   map[string]string `toml:"prompts"`
 	Output    *Output           `toml:"output"`
 	Legs      []Leg             `toml:"legs"`
 	Synthesis *Synthesis        `toml:"synthesis"`
 
 	// Workflow-specific
-	Steps []Step           `toml:"steps"`
-	Vars  map[string]Var   `toml:"vars"`
+	Steps []Step         `toml:"steps"`
+	Vars  map[string]Var `toml:"vars"`
 
 	// Expansion-specific
 	Template []Template `toml:"template"`
 
 	// Aspect-specific (similar to convoy but for analysis)
 	Aspects []Aspect `toml:"aspects"`
+
+	// Aspect advice, woven into workflows that list this formula in
+	// compose.aspects
+	Advice    []Advice   `toml:"advice"`
+	Pointcuts []Pointcut `toml:"pointcuts"`
+
+	// Composition, applied by Resolve
+	Extends []string `toml:"extends"`
+	Compose *Compose `toml:"compose"`
+
+	// Path is the file the formula was parsed from, if any.
+	Path string `toml:"-"`
+}
+
+// Compose lists the formulas composed into a workflow.
+type Compose struct {
+	Aspects []string        `toml:"aspects"`
+	Include []Include       `toml:"include"`
+	Expand  []ComposeExpand `toml:"expand"`
+}
+
+// Include copies steps from another workflow formula, prefixing their IDs
+// with Prefix (default: the formula name) and a dot.
+type Include struct {
+	Formula string   `toml:"formula"`
+	Prefix  string   `toml:"prefix"`
+	Steps   []string `toml:"steps"` // subset to include; all if empty
+	Needs   []string `toml:"needs"` // host steps the included roots need
+}
+
+// ComposeExpand replaces the target step with the templates of an
+// expansion formula.
+type ComposeExpand struct {
+	Target string `toml:"target"`
+	With   string `toml:"with"`
+}
+
+// Advice inserts steps before and/or after every workflow step whose ID
+// matches Target (a glob). Advice step fields may use {step.id},
+// {step.title} and {step.description}.
+type Advice struct {
+	Target string        `toml:"target"`
+	Before []Step        `toml:"before"`
+	After  []Step        `toml:"after"`
+	Around *AdviceAround `toml:"around"`
+}
+
+// AdviceAround groups before and after advice.
+type AdviceAround struct {
+	Before []Step `toml:"before"`
+	After  []Step `toml:"after"`
+}
+
+// Pointcut restricts which steps an aspect's advice may apply to.
+type Pointcut struct {
+	Glob string `toml:"glob"`
 }
 
 // Aspect represents a parallel analysis aspect in an aspect formula.
@@ -59,10 +115,11 @@ type Aspect struct {
 // Input represents an input parameter for a formula.
 type Input struct {
 	Description    string   `toml:"description"`
-	Type           string   `toml:"type"`
+	Type           string   `toml:"type"` // see VarType
 	Required       bool     `toml:"required"`
 	RequiredUnless []string `toml:"required_unless"`
 	Default        string   `toml:"default"`
+	Enum           []string `toml:"enum"` // choices for enum and list types
 }
 
 // Output configures where formula outputs are written.
@@ -89,10 +146,23 @@ type Synthesis struct {
 
 // Step represents a sequential step in a workflow formula.
 type Step struct {
-	ID          string   `toml:"id"`
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	Needs       []string `toml:"needs"`
+	ID          string   `toml:"id" json:"id"`
+	Title       string   `toml:"title" json:"title"`
+	Description string   `toml:"description" json:"description,omitempty"`
+	Needs       []string `toml:"needs" json:"needs,omitempty"`
+
+	// When is a condition over vars and inputs; the step is skipped when it
+	// evaluates false. See parseWhen for the syntax.
+	When string `toml:"when" json:"when,omitempty"`
+
+	// Foreach names a list-valued var (comma-separated). The step is
+	// repeated once per item, with {{item}} and {{index}} substituted in
+	// its ID, title and description. The ID must contain {{item}}.
+	Foreach string `toml:"foreach" json:"foreach,omitempty"`
+
+	// Source is the formula file (or name) the step came from, set by
+	// Resolve.
+	Source string `toml:"-" json:"source,omitempty"`
 }
 
 // Template represents a template step in an expansion formula.
@@ -105,9 +175,11 @@ type Template struct {
 
 // Var represents a variable definition for formulas.
 type Var struct {
-	Description string `toml:"description"`
-	Required    bool   `toml:"required"`
-	Default     string `toml:"default"`
+	Description string   `toml:"description"`
+	Type        string   `toml:"type"` // see VarType
+	Required    bool     `toml:"required"`
+	Default     string   `toml:"default"`
+	Enum        []string `toml:"enum"` // choices for enum and list types
 }
 
 // IsValid returns true if the formula type is recognized.
@@ -0,0 +1,298 @@
+package formula
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VarType is the declared type of a formula var or input.
+type VarType string
+
+// Var and input types. Untyped vars are strings.
+const (
+	VarString VarType = "string"
+	VarInt    VarType = "int"     // also "number" and "integer"
+	VarBool   VarType = "bool"    // true/false, yes/no, 1/0, on/off
+	VarEnum   VarType = "enum"    // one of the declared enum choices
+	VarBeadID VarType = "bead-id" // an existing bead, e.g. gt-abc12
+	VarRig    VarType = "rig"     // a rig registered in the town
+	VarPath   VarType = "path"    // a file system path
+	VarList   VarType = "list"    // comma-separated; items checked against enum if set
+)
+
+// ParseVarType parses a declared type, accepting common aliases.
+func ParseVarType(s string) (VarType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "string", "text":
+		return VarString, nil
+	case "int", "integer", "number":
+		return VarInt, nil
+	case "bool", "boolean":
+		return VarBool, nil
+	case "enum", "choice":
+		return VarEnum, nil
+	case "bead-id", "bead":
+		return VarBeadID, nil
+	case "rig":
+		return VarRig, nil
+	case "path", "file", "dir":
+		return VarPath, nil
+	case "list":
+		return VarList, nil
+	}
+	return "", fmt.Errorf("unknown type %q (must be string, int, bool, enum, bead-id, rig, path or list)", s)
+}
+
+// VarSpec is the declaration of a var or input, whichever it came from.
+type VarSpec struct {
+	Name           string
+	Description    string
+	Type           VarType
+	Required       bool
+	RequiredUnless []string
+	Default        string
+	Enum           []string
+}
+
+// Choices renders the enum choices as "a | b | c".
+func (s VarSpec) Choices() string {
+	return strings.Join(s.Enum, " | ")
+}
+
+// VarSpecs returns the declarations of the formula's vars and inputs,
+// sorted by name. Invalid types are reported by Validate; here they read
+// as strings.
+func (f *Formula) VarSpecs() []VarSpec {
+	specs := make([]VarSpec, 0, len(f.Vars)+len(f.Inputs))
+	for name, v := range f.Vars {
+		t := specType(v.Type, v.Enum)
+		specs = append(specs, VarSpec{
+			Name: name, Description: v.Description, Type: t,
+			Required: v.Required, Default: v.Default, Enum: v.Enum,
+		})
+	}
+	for name, in := range f.Inputs {
+		t := specType(in.Type, in.Enum)
+		specs = append(specs, VarSpec{
+			Name: name, Description: in.Description, Type: t,
+			Required: in.Required, RequiredUnless: in.RequiredUnless,
+			Default: in.Default, Enum: in.Enum,
+		})
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
+// specType returns the type of a declaration. An untyped declaration with
+// enum choices is an enum.
+func specType(declared string, enum []string) VarType {
+	if declared == "" && len(enum) > 0 {
+		return VarEnum
+	}
+	t, _ := ParseVarType(declared)
+	return t
+}
+
+// validateVarSpecs checks var and input declarations: known types, enum
+// choices for enums, and defaults that match their type.
+func (f *Formula) validateVarSpecs() error {
+	for name, v := range f.Vars {
+		if _, ok := f.Inputs[name]; ok {
+			return fmt.Errorf("%q is declared as both a var and an input", name)
+		}
+		if _, err := ParseVarType(v.Type); err != nil {
+			return fmt.Errorf("var %q: %w", name, err)
+		}
+	}
+	for name, in := range f.Inputs {
+		if _, err := ParseVarType(in.Type); err != nil {
+			return fmt.Errorf("input %q: %w", name, err)
+		}
+		for _, other := range in.RequiredUnless {
+			if !f.declaresVar(other) {
+				return fmt.Errorf("input %q required_unless references unknown var: %s", name, other)
+			}
+		}
+	}
+	for _, spec := range f.VarSpecs() {
+		if spec.Type == VarEnum && len(spec.Enum) == 0 {
+			return fmt.Errorf("%q is an enum but declares no enum choices", spec.Name)
+		}
+		if spec.Default != "" {
+			if err := spec.checkValue(spec.Default); err != nil {
+				return fmt.Errorf("%q default: %w", spec.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkValue checks a value against the spec's type, without looking
+// anything up outside the formula.
+func (s VarSpec) checkValue(v string) error {
+	switch s.Type {
+	case VarInt:
+		if _, err := strconv.Atoi(strings.TrimSpace(v)); err != nil {
+			return fmt.Errorf("%q is not an integer", v)
+		}
+	case VarBool:
+		if _, ok := parseBool(v); !ok {
+			return fmt.Errorf("%q is not a boolean (true/false, yes/no)", v)
+		}
+	case VarEnum:
+		if !containsChoice(s.Enum, v) {
+			return fmt.Errorf("%q is not one of: %s", v, s.Choices())
+		}
+	case VarBeadID:
+		if !beadIDPattern(v) {
+			return fmt.Errorf("%q is not a bead ID", v)
+		}
+	case VarRig:
+		if v == "" || strings.ContainsAny(v, "/ \t") {
+			return fmt.Errorf("%q is not a rig name", v)
+		}
+	case VarPath:
+		if strings.ContainsAny(v, "\x00\n") {
+			return fmt.Errorf("%q is not a valid path", v)
+		}
+	case VarList:
+		if len(s.Enum) > 0 {
+			for _, item := range splitList(v) {
+				if !containsChoice(s.Enum, item) {
+					return fmt.Errorf("item %q is not one of: %s", item, s.Choices())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseBool parses the boolean spellings accepted for bool vars.
+func parseBool(v string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "true", "yes", "y", "on", "1":
+		return true, true
+	case "false", "no", "n", "off", "0":
+		return false, true
+	}
+	return false, false
+}
+
+func containsChoice(choices []string, v string) bool {
+	for _, c := range choices {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
+
+// beadIDPattern reports whether s looks like a bead ID: a prefix, a dash,
+// and an alphanumeric suffix, optionally with dotted child numbers.
+func beadIDPattern(s string) bool {
+	prefix, rest, ok := strings.Cut(s, "-")
+	if !ok || prefix == "" || rest == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '-' || r == '.' || r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// VarChecker looks up values that refer to things outside the formula.
+type VarChecker interface {
+	// CheckBead returns an error if the bead does not exist.
+	CheckBead(id string) error
+	// CheckRig returns an error if the rig is not registered.
+	CheckRig(name string) error
+}
+
+// VarErrors lists every problem found with a set of var values.
+type VarErrors []string
+
+func (e VarErrors) Error() string {
+	if len(e) == 1 {
+		return "invalid formula vars: " + e[0]
+	}
+	return "invalid formula vars:\n  - " + strings.Join(e, "\n  - ")
+}
+
+// MissingVars returns the required vars and inputs that have no value in
+// values, and no default, sorted by name. An input with required_unless is
+// required unless any of the listed vars has a value.
+func (f *Formula) MissingVars(values map[string]string) []string {
+	var missing []string
+	for _, spec := range f.VarSpecs() {
+		required := spec.Required || len(spec.RequiredUnless) > 0
+		if !required || values[spec.Name] != "" || spec.Default != "" {
+			continue
+		}
+		satisfied := false
+		for _, other := range spec.RequiredUnless {
+			if values[other] != "" {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			missing = append(missing, spec.Name)
+		}
+	}
+	return missing
+}
+
+// ValidateVars checks provided values against the formula's declarations
+// and returns every problem at once as VarErrors: undeclared names,
+// missing required values, and values that don't match their type. With a
+// checker, bead-id and rig values must also exist.
+func (f *Formula) ValidateVars(provided map[string]string, check VarChecker) error {
+	var errs VarErrors
+
+	names := make([]string, 0, len(provided))
+	for name := range provided {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !f.declaresVar(name) {
+			errs = append(errs, fmt.Sprintf("%s: unknown var", name))
+		}
+	}
+	if missing := f.MissingVars(provided); len(missing) > 0 {
+		errs = append(errs, "missing required var(s): "+strings.Join(missing, ", "))
+	}
+
+	for _, spec := range f.VarSpecs() {
+		v, ok := provided[spec.Name]
+		if !ok {
+			continue
+		}
+		if err := spec.checkValue(v); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", spec.Name, err))
+			continue
+		}
+		if check == nil || v == "" {
+			continue
+		}
+		var err error
+		switch spec.Type {
+		case VarBeadID:
+			err = check.CheckBead(v)
+		case VarRig:
+			err = check.CheckRig(v)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", spec.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package formula
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const typedVarsFormula = `
+formula = "typed"
+type = "workflow"
+
+[vars.disks]
+type = "int"
+default = "3"
+
+[vars.publish]
+type = "bool"
+
+[vars.channel]
+enum = ["stable", "beta"]
+default = "stable"
+
+[vars.platforms]
+type = "list"
+enum = ["linux", "darwin", "windows"]
+
+[vars.rig]
+type = "rig"
+
+[inputs.issue]
+type = "bead-id"
+required_unless = ["branch"]
+
+[inputs.branch]
+type = "string"
+
+[[steps]]
+id = "build"
+`
+
+func parseTyped(t *testing.T) *Formula {
+	t.Helper()
+	f, err := Parse([]byte(typedVarsFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return f
+}
+
+func TestVarSpecs(t *testing.T) {
+	f := parseTyped(t)
+
+	types := make(map[string]VarType)
+	var names []string
+	for _, spec := range f.VarSpecs() {
+		types[spec.Name] = spec.Type
+		names = append(names, spec.Name)
+	}
+	wantNames := []string{"branch", "channel", "disks", "issue", "platforms", "publish", "rig"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("names = %v, want %v", names, wantNames)
+	}
+	if types["channel"] != VarEnum {
+		t.Errorf("untyped var with enum choices = %s, want enum", types["channel"])
+	}
+	if types["issue"] != VarBeadID || types["disks"] != VarInt {
+		t.Errorf("types = %v", types)
+	}
+}
+
+func TestValidateVars(t *testing.T) {
+	f := parseTyped(t)
+
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   []string // substrings of the error, nil for valid
+	}{
+		{"valid", map[string]string{"branch": "main", "disks": "5", "publish": "yes", "platforms": "linux,darwin"}, nil},
+		{"required unless satisfied by issue", map[string]string{"issue": "gt-abc12"}, nil},
+		{"required unless", map[string]string{}, []string{"missing required var(s): issue"}},
+		{"int", map[string]string{"branch": "x", "disks": "three"}, []string{`disks: "three" is not an integer`}},
+		{"bool", map[string]string{"branch": "x", "publish": "maybe"}, []string{"publish:", "not a boolean"}},
+		{"enum", map[string]string{"branch": "x", "channel": "nightly"}, []string{`"nightly" is not one of: stable | beta`}},
+		{"list item", map[string]string{"branch": "x", "platforms": "linux,plan9"}, []string{`item "plan9"`}},
+		{"bead id", map[string]string{"issue": "not a bead"}, []string{`issue: "not a bead" is not a bead ID`}},
+		{"unknown", map[string]string{"branch": "x", "colour": "red"}, []string{"colour: unknown var"}},
+	}
+	for _, tt := range tests {
+		err := f.ValidateVars(tt.values, nil)
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected error", tt.name)
+			continue
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(err.Error(), w) {
+				t.Errorf("%s: error %q does not contain %q", tt.name, err, w)
+			}
+		}
+	}
+}
+
+func TestValidateVars_ReportsAllErrors(t *testing.T) {
+	f := parseTyped(t)
+
+	err := f.ValidateVars(map[string]string{"disks": "x", "publish": "x", "colour": "red"}, nil)
+	var errs VarErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want VarErrors", err)
+	}
+	if len(errs) != 4 {
+		t.Errorf("got %d errors, want 4 (unknown, missing, int, bool):\n%v", len(errs), err)
+	}
+	if !strings.HasPrefix(err.Error(), "invalid formula vars:\n  - ") {
+		t.Errorf("multi-error message = %q", err)
+	}
+}
+
+type fakeVarChecker struct{}
+
+func (fakeVarChecker) CheckBead(id string) error {
+	if id != "gt-abc12" {
+		return errors.New("bead not found")
+	}
+	return nil
+}
+
+func (fakeVarChecker) CheckRig(name string) error {
+	if name != "gastown" {
+		return errors.New("rig not found")
+	}
+	return nil
+}
+
+func TestValidateVars_Checker(t *testing.T) {
+	f := parseTyped(t)
+
+	if err := f.ValidateVars(map[string]string{"issue": "gt-abc12", "rig": "gastown"}, fakeVarChecker{}); err != nil {
+		t.Errorf("existing bead and rig: %v", err)
+	}
+	err := f.ValidateVars(map[string]string{"issue": "gt-zzz99", "rig": "nowhere"}, fakeVarChecker{})
+	if err == nil || !strings.Contains(err.Error(), "issue: bead not found") || !strings.Contains(err.Error(), "rig: rig not found") {
+		t.Errorf("error = %v, want both lookups reported", err)
+	}
+}
+
+func TestMissingVars(t *testing.T) {
+	f := parseTyped(t)
+
+	if got := f.MissingVars(map[string]string{}); !reflect.DeepEqual(got, []string{"issue"}) {
+		t.Errorf("MissingVars = %v, want [issue]", got)
+	}
+	if got := f.MissingVars(map[string]string{"branch": "main"}); len(got) != 0 {
+		t.Errorf("MissingVars with branch = %v, want none", got)
+	}
+}
+
+func TestParse_InvalidVarDeclarations(t *testing.T) {
+	tests := []struct {
+		decl string
+		want string
+	}{
+		{"[vars.n]\ntype = \"float\"\n", `var "n": unknown type "float"`},
+		{"[vars.n]\ntype = \"enum\"\n", "declares no enum choices"},
+		{"[vars.n]\ntype = \"int\"\ndefault = \"many\"\n", `"n" default: "many" is not an integer`},
+		{"[vars.n]\nenum = [\"a\"]\ndefault = \"b\"\n", `"n" default`},
+		{"[inputs.n]\nrequired_unless = [\"m\"]\n", "required_unless references unknown var: m"},
+		{"[vars.n]\n[inputs.n]\n", "both a var and an input"},
+	}
+	for _, tt := range tests {
+		data := "formula = \"f\"\ntype = \"workflow\"\n" + tt.decl + "\n[[steps]]\nid = \"a\"\n"
+		_, err := Parse([]byte(data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want containing %q", tt.decl, err, tt.want)
+		}
+	}
+}
@@ -222,6 +222,13 @@ func (g *Git) Checkout(ref string) error {
 	return err
 }
 
+// CheckoutDetached checks out ref with a detached HEAD, leaving branches
+// where they are.
+func (g *Git) CheckoutDetached(ref string) error {
+	_, err := g.run("checkout", "--detach", ref)
+	return err
+}
+
 // Fetch fetches from the remote.
 func (g *Git) Fetch(remote string) error {
 	_, err := g.run("fetch", remote)
@@ -399,6 +406,28 @@ func (g *Git) MergeNoFF(branch, message string) error {
 	return err
 }
 
+// Revert commits a revert of a (non-merge) commit.
+func (g *Git) Revert(commit string) error {
+	_, err := g.run("revert", "--no-edit", commit)
+	return err
+}
+
+// AbortRevert aborts a revert in progress.
+func (g *Git) AbortRevert() error {
+	_, err := g.run("revert", "--abort")
+	return err
+}
+
+// RestoreTree commits the tree of ref on top of HEAD, undoing every change
+// made since ref in a single commit.
+func (g *Git) RestoreTree(ref, message string) error {
+	if _, err := g.run("read-tree", "-u", "--reset", ref); err != nil {
+		return err
+	}
+	_, err := g.run("commit", "-m", message)
+	return err
+}
+
 // DeleteRemoteBranch deletes a branch on the remote.
 func (g *Git) DeleteRemoteBranch(remote, branch string) error {
 	_, err := g.run("push", remote, "--delete", branch)
@@ -578,6 +607,18 @@ func (g *Git) ResetBranch(name, ref string) error {
 	return err
 }
 
+// RenameBranch renames the current branch.
+func (g *Git) RenameBranch(newName string) error {
+	_, err := g.run("branch", "-m", newName)
+	return err
+}
+
+// ResetHard resets the current branch, index and working tree to ref.
+func (g *Git) ResetHard(ref string) error {
+	_, err := g.run("reset", "--hard", ref)
+	return err
+}
+
 // Rev returns the commit hash for the given ref.
 func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
@@ -791,6 +832,13 @@ func (g *Git) WorktreePrune() error {
 	return err
 }
 
+// WorktreeRepair fixes the administrative links of a worktree that was moved
+// to path without git worktree move.
+func (g *Git) WorktreeRepair(path string) error {
+	_, err := g.run("worktree", "repair", path)
+	return err
+}
+
 // Worktree represents a git worktree.
 type Worktree struct {
 	Path   string
@@ -0,0 +1,222 @@
+// Package graph renders dependency graphs, such as formula steps or the
+// beads of a live molecule, as Mermaid or Graphviz DOT.
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status is the state a node is colored by. Formula graphs leave it empty.
+type Status string
+
+// Node statuses, following bead statuses. StatusBlocked covers open beads
+// whose dependencies or WaitsFor conditions are not yet met.
+const (
+	StatusNone       Status = ""
+	StatusOpen       Status = "open"
+	StatusInProgress Status = "in_progress"
+	StatusClosed     Status = "closed"
+	StatusBlocked    Status = "blocked"
+)
+
+// statusOrder fixes the order class definitions are emitted in.
+var statusOrder = []Status{StatusOpen, StatusInProgress, StatusClosed, StatusBlocked}
+
+// statusColors maps each status to its fill and stroke colors.
+var statusColors = map[Status][2]string{
+	StatusOpen:       {"#ffffff", "#6b7280"},
+	StatusInProgress: {"#fef3c7", "#d97706"},
+	StatusClosed:     {"#d1fae5", "#059669"},
+	StatusBlocked:    {"#fee2e2", "#dc2626"},
+}
+
+// Format is an output format for Render.
+type Format string
+
+// Supported output formats.
+const (
+	FormatMermaid Format = "mermaid"
+	FormatDOT     Format = "dot"
+)
+
+// ParseFormat parses an output format name.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatMermaid:
+		return FormatMermaid, nil
+	case FormatDOT, "graphviz":
+		return FormatDOT, nil
+	}
+	return "", fmt.Errorf("unknown graph format %q (must be mermaid or dot)", s)
+}
+
+// Node is a vertex of the graph.
+type Node struct {
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Status   Status `json:"status,omitempty"`
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// Edge says From must finish before To can start.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is a directed graph of nodes in insertion order.
+type Graph struct {
+	Title string `json:"title,omitempty"`
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// New returns an empty graph with the given title.
+func New(title string) *Graph {
+	return &Graph{Title: title}
+}
+
+// AddNode appends a node.
+func (g *Graph) AddNode(n Node) {
+	g.Nodes = append(g.Nodes, n)
+}
+
+// AddEdge appends an edge. Edges to or from unknown nodes are dropped
+// when rendering.
+func (g *Graph) AddEdge(from, to string) {
+	g.Edges = append(g.Edges, Edge{From: from, To: to})
+}
+
+// Render renders the graph in the given format.
+func (g *Graph) Render(format Format) (string, error) {
+	switch format {
+	case FormatMermaid:
+		return g.Mermaid(), nil
+	case FormatDOT:
+		return g.DOT(), nil
+	}
+	return "", fmt.Errorf("unknown graph format %q", format)
+}
+
+// lines returns the label lines of a node: its ID, its title if different,
+// and its assignee.
+func (n Node) lines() []string {
+	lines := []string{n.ID}
+	if n.Title != "" && n.Title != n.ID {
+		lines = append(lines, n.Title)
+	}
+	if n.Assignee != "" {
+		lines = append(lines, "@"+n.Assignee)
+	}
+	return lines
+}
+
+// edges returns the edges whose endpoints are both nodes of the graph,
+// without duplicates.
+func (g *Graph) edges() []Edge {
+	known := make(map[string]bool, len(g.Nodes))
+	for _, n := range g.Nodes {
+		known[n.ID] = true
+	}
+	seen := make(map[Edge]bool, len(g.Edges))
+	var edges []Edge
+	for _, e := range g.Edges {
+		if known[e.From] && known[e.To] && !seen[e] {
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Node IDs are replaced
+// by n0, n1, ... since bead and step IDs are not valid Mermaid identifiers
+// in general.
+func (g *Graph) Mermaid() string {
+	var sb strings.Builder
+	if g.Title != "" {
+		fmt.Fprintf(&sb, "---\ntitle: %q\n---\n", g.Title)
+	}
+	sb.WriteString("flowchart TD\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+	byStatus := make(map[Status][]string)
+	for i, n := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.ID] = id
+		lines := n.lines()
+		for j, line := range lines {
+			lines[j] = mermaidEscape(line)
+		}
+		fmt.Fprintf(&sb, "    %s[\"%s\"]\n", id, strings.Join(lines, "<br/>"))
+		if n.Status != StatusNone {
+			byStatus[n.Status] = append(byStatus[n.Status], id)
+		}
+	}
+	for _, e := range g.edges() {
+		fmt.Fprintf(&sb, "    %s --> %s\n", ids[e.From], ids[e.To])
+	}
+
+	for _, status := range usedStatuses(byStatus) {
+		colors := statusColors[status]
+		fmt.Fprintf(&sb, "    classDef %s fill:%s,stroke:%s\n", status, colors[0], colors[1])
+		fmt.Fprintf(&sb, "    class %s %s\n", strings.Join(byStatus[status], ","), status)
+	}
+	return sb.String()
+}
+
+// usedStatuses returns the statuses in use, in a fixed order.
+func usedStatuses(byStatus map[Status][]string) []Status {
+	var used []Status
+	for _, status := range statusOrder {
+		if len(byStatus[status]) > 0 {
+			used = append(used, status)
+		}
+	}
+	return used
+}
+
+// DOT renders the graph in Graphviz DOT.
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	name := g.Title
+	if name == "" {
+		name = "G"
+	}
+	fmt.Fprintf(&sb, "digraph %s {\n", dotQuote(name))
+	sb.WriteString("    rankdir=TB;\n")
+	sb.WriteString("    node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\"];\n")
+
+	for _, n := range g.Nodes {
+		lines := n.lines()
+		for i, line := range lines {
+			lines[i] = dotEscape(line)
+		}
+		attrs := []string{`label="` + strings.Join(lines, `\n`) + `"`}
+		if colors, ok := statusColors[n.Status]; ok {
+			attrs = append(attrs, fmt.Sprintf("fillcolor=%q", colors[0]), fmt.Sprintf("color=%q", colors[1]))
+		}
+		fmt.Fprintf(&sb, "    %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.edges() {
+		fmt.Fprintf(&sb, "    %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// mermaidEscape replaces characters that would end a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+// dotEscape escapes a string for use inside a quoted DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// dotQuote returns s as a quoted DOT identifier.
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
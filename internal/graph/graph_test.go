@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func sampleGraph() *Graph {
+	g := New("mol-polecat-work")
+	g.AddNode(Node{ID: "gt-a.1", Title: "Load context", Status: StatusClosed})
+	g.AddNode(Node{ID: "gt-a.2", Title: `Implement "auth"`, Status: StatusInProgress, Assignee: "gastown/polecats/nux"})
+	g.AddNode(Node{ID: "gt-a.3", Title: "Submit", Status: StatusBlocked})
+	g.AddEdge("gt-a.1", "gt-a.2")
+	g.AddEdge("gt-a.2", "gt-a.3")
+	g.AddEdge("gt-a.2", "gt-a.3")       // duplicate
+	g.AddEdge("gt-elsewhere", "gt-a.3") // outside the graph
+	return g
+}
+
+func TestMermaid(t *testing.T) {
+	out := sampleGraph().Mermaid()
+
+	for _, want := range []string{
+		`title: "mol-polecat-work"`,
+		"flowchart TD",
+		`n1["gt-a.2<br/>Implement #quot;auth#quot;<br/>@gastown/polecats/nux"]`,
+		"n0 --> n1",
+		"n1 --> n2",
+		"classDef closed fill:#d1fae5",
+		"class n1 in_progress",
+		"class n2 blocked",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "-->") != 2 {
+		t.Errorf("want 2 edges (duplicate and dangling dropped):\n%s", out)
+	}
+	if strings.Contains(out, "classDef open") {
+		t.Errorf("unused status should not get a classDef:\n%s", out)
+	}
+}
+
+func TestDOT(t *testing.T) {
+	out := sampleGraph().DOT()
+
+	for _, want := range []string{
+		`digraph "mol-polecat-work" {`,
+		`"gt-a.2" [label="gt-a.2\nImplement \"auth\"\n@gastown/polecats/nux", fillcolor="#fef3c7", color="#d97706"];`,
+		`"gt-a.1" -> "gt-a.2";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "->") != 2 {
+		t.Errorf("want 2 edges:\n%s", out)
+	}
+}
+
+func TestUncoloredNodes(t *testing.T) {
+	g := New("")
+	g.AddNode(Node{ID: "build", Title: "build"})
+	if out := g.Mermaid(); strings.Contains(out, "classDef") || strings.Contains(out, "title:") {
+		t.Errorf("Mermaid without statuses or title:\n%s", out)
+	}
+	if out := g.DOT(); !strings.Contains(out, `"build" [label="build"];`) {
+		t.Errorf("DOT label should not repeat the title:\n%s", out)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"mermaid": FormatMermaid, "DOT": FormatDOT, "graphviz": FormatDOT} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseFormat("svg"); err == nil {
+		t.Error("ParseFormat(svg) should fail")
+	}
+}
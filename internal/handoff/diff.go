@@ -0,0 +1,194 @@
+package handoff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Delta is what changed between two handoffs: the progress one session made.
+type Delta struct {
+	// StepsDone are molecule steps closed since the previous handoff.
+	StepsDone []string `json:"steps_done,omitempty"`
+
+	// FilesChanged are files touched since the previous handoff.
+	FilesChanged []string `json:"files_changed,omitempty"`
+
+	// NewDecisions are decisions not recorded in the previous handoff.
+	NewDecisions []string `json:"new_decisions,omitempty"`
+
+	// ResolvedQuestions are open questions of the previous handoff that
+	// are no longer open.
+	ResolvedQuestions []string `json:"resolved_questions,omitempty"`
+
+	// NewQuestions are open questions raised since the previous handoff.
+	NewQuestions []string `json:"new_questions,omitempty"`
+
+	// HookChanged is set when the hooked bead differs from the previous one.
+	HookChanged bool `json:"hook_changed,omitempty"`
+
+	// MoleculeChanged is set when work moved to a different molecule.
+	MoleculeChanged bool `json:"molecule_changed,omitempty"`
+}
+
+// Diff compares cur against the handoff before it. prev may be nil (first
+// handoff in the chain), in which case everything in cur counts as new.
+func Diff(prev, cur *Record) Delta {
+	if prev == nil {
+		prev = &Record{}
+	}
+	var d Delta
+
+	var prevDone []string
+	if prev.Molecule != nil && cur.Molecule != nil && prev.Molecule.Root == cur.Molecule.Root && prev.Molecule.ID == cur.Molecule.ID {
+		prevDone = prev.Molecule.StepsDone
+	}
+	if cur.Molecule != nil {
+		d.StepsDone = subtract(cur.Molecule.StepsDone, prevDone)
+	}
+	d.MoleculeChanged = moleculeID(prev) != moleculeID(cur) && moleculeID(prev) != ""
+
+	d.FilesChanged = cur.FilesTouched
+	if len(d.FilesChanged) == 0 && cur.Git != nil {
+		d.FilesChanged = cur.Git.ModifiedFiles
+	}
+
+	d.NewDecisions = subtract(cur.Decisions, prev.Decisions)
+	d.ResolvedQuestions = subtract(prev.OpenQuestions, cur.OpenQuestions)
+	d.NewQuestions = subtract(cur.OpenQuestions, prev.OpenQuestions)
+	d.HookChanged = prev.HookedBead != "" && prev.HookedBead != cur.HookedBead
+	return d
+}
+
+// Summary returns a one-line description, e.g. "2 steps done, 3 files changed".
+func (d Delta) Summary() string {
+	var parts []string
+	if n := len(d.StepsDone); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s done", n, plural(n, "step", "steps")))
+	}
+	if n := len(d.FilesChanged); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s changed", n, plural(n, "file", "files")))
+	}
+	if n := len(d.NewDecisions); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", n, plural(n, "decision", "decisions")))
+	}
+	if n := len(d.ResolvedQuestions); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s resolved", n, plural(n, "question", "questions")))
+	}
+	if n := len(d.NewQuestions); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d new %s", n, plural(n, "question", "questions")))
+	}
+	if d.MoleculeChanged {
+		parts = append(parts, "switched molecule")
+	} else if d.HookChanged {
+		parts = append(parts, "hook changed")
+	}
+	if len(parts) == 0 {
+		return "no recorded progress"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Markdown renders the record for humans and for the handoff mail.
+func (r *Record) Markdown() string {
+	var sb strings.Builder
+	subject := r.Subject
+	if subject == "" {
+		subject = "Session handoff"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", subject)
+	fmt.Fprintf(&sb, "Agent: %s\n", r.Agent)
+	fmt.Fprintf(&sb, "Handed off: %s\n", r.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	if r.Session != "" {
+		fmt.Fprintf(&sb, "Session: %s\n", r.Session)
+	}
+	if r.Previous != "" {
+		fmt.Fprintf(&sb, "Previous: %s\n", r.Previous)
+	}
+
+	if r.HookedBead != "" {
+		fmt.Fprintf(&sb, "\n## Hooked Work\n%s\n", r.HookedBead)
+	}
+	if m := r.Molecule; m != nil {
+		sb.WriteString("\n## Molecule\n")
+		fmt.Fprintf(&sb, "%s", m.ID)
+		if m.Root != "" {
+			fmt.Fprintf(&sb, " (root %s)", m.Root)
+		}
+		sb.WriteString("\n")
+		if m.Step != "" {
+			fmt.Fprintf(&sb, "Current step: %s", m.Step)
+			if m.StepTitle != "" {
+				fmt.Fprintf(&sb, " - %s", m.StepTitle)
+			}
+			sb.WriteString("\n")
+		}
+		if m.StepsTotal > 0 {
+			fmt.Fprintf(&sb, "Progress: %d/%d steps complete\n", len(m.StepsDone), m.StepsTotal)
+		}
+	}
+	if g := r.Git; g != nil && (g.Branch != "" || g.LastCommit != "") {
+		sb.WriteString("\n## Git\n")
+		if g.Branch != "" {
+			fmt.Fprintf(&sb, "Branch: %s\n", g.Branch)
+		}
+		if g.LastCommit != "" {
+			fmt.Fprintf(&sb, "Last commit: %s\n", shortSHA(g.LastCommit))
+		}
+		if len(g.ModifiedFiles) > 0 {
+			fmt.Fprintf(&sb, "Uncommitted: %d files\n", len(g.ModifiedFiles))
+		}
+	}
+	writeList(&sb, "Files Touched", r.FilesTouched)
+	writeList(&sb, "Decisions", r.Decisions)
+	writeList(&sb, "Open Questions", r.OpenQuestions)
+	if msg := strings.TrimSpace(r.Message); msg != "" {
+		fmt.Fprintf(&sb, "\n## Notes\n%s\n", msg)
+	}
+	return sb.String()
+}
+
+func writeList(sb *strings.Builder, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n## %s\n", heading)
+	for _, item := range items {
+		fmt.Fprintf(sb, "- %s\n", item)
+	}
+}
+
+func moleculeID(r *Record) string {
+	if r.Molecule == nil {
+		return ""
+	}
+	return r.Molecule.ID + "/" + r.Molecule.Root
+}
+
+// subtract returns the items of a not in b, in order.
+func subtract(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !in[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
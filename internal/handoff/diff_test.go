@@ -0,0 +1,106 @@
+package handoff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	prev := &Record{
+		HookedBead:    "gt-abc",
+		Molecule:      &Molecule{ID: "mol-polecat-work", Root: "gt-abc", StepsDone: []string{"gt-abc.1"}, StepsTotal: 5},
+		OpenQuestions: []string{"Which API version?", "Keep the v1 shim?"},
+		Decisions:     []string{"Use sqlite"},
+	}
+	cur := &Record{
+		HookedBead:    "gt-abc",
+		Molecule:      &Molecule{ID: "mol-polecat-work", Root: "gt-abc", StepsDone: []string{"gt-abc.1", "gt-abc.2", "gt-abc.3"}, StepsTotal: 5},
+		FilesTouched:  []string{"a.go", "b.go", "c.go"},
+		OpenQuestions: []string{"Keep the v1 shim?", "Who owns deploys?"},
+		Decisions:     []string{"Use sqlite", "Drop v0 endpoints"},
+	}
+
+	d := Diff(prev, cur)
+	if !reflect.DeepEqual(d.StepsDone, []string{"gt-abc.2", "gt-abc.3"}) {
+		t.Errorf("StepsDone = %v", d.StepsDone)
+	}
+	if !reflect.DeepEqual(d.NewDecisions, []string{"Drop v0 endpoints"}) {
+		t.Errorf("NewDecisions = %v", d.NewDecisions)
+	}
+	if !reflect.DeepEqual(d.ResolvedQuestions, []string{"Which API version?"}) || !reflect.DeepEqual(d.NewQuestions, []string{"Who owns deploys?"}) {
+		t.Errorf("questions: resolved %v, new %v", d.ResolvedQuestions, d.NewQuestions)
+	}
+	if d.HookChanged || d.MoleculeChanged {
+		t.Errorf("unexpected change flags: %+v", d)
+	}
+	want := "2 steps done, 3 files changed, 1 decision, 1 question resolved, 1 new question"
+	if got := d.Summary(); got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+}
+
+func TestDiffNewMolecule(t *testing.T) {
+	prev := &Record{
+		HookedBead: "gt-abc",
+		Molecule:   &Molecule{ID: "mol-polecat-work", Root: "gt-abc", StepsDone: []string{"gt-abc.1"}},
+	}
+	cur := &Record{
+		HookedBead: "gt-xyz",
+		Molecule:   &Molecule{ID: "mol-polecat-work", Root: "gt-xyz", StepsDone: []string{"gt-xyz.1"}},
+		Git:        &Git{ModifiedFiles: []string{"x.go"}},
+	}
+	d := Diff(prev, cur)
+	if len(d.StepsDone) != 1 || !d.MoleculeChanged || !d.HookChanged {
+		t.Errorf("Diff = %+v", d)
+	}
+	if got := d.Summary(); got != "1 step done, 1 file changed, switched molecule" {
+		t.Errorf("Summary = %q", got)
+	}
+}
+
+func TestDiffFirstHandoff(t *testing.T) {
+	if got := Diff(nil, &Record{}).Summary(); got != "no recorded progress" {
+		t.Errorf("Summary = %q", got)
+	}
+	d := Diff(nil, &Record{Decisions: []string{"a"}, Molecule: &Molecule{StepsDone: []string{"s1"}}})
+	if len(d.NewDecisions) != 1 || len(d.StepsDone) != 1 || d.HookChanged || d.MoleculeChanged {
+		t.Errorf("Diff(nil, cur) = %+v", d)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	r := &Record{
+		Agent:         "gastown/crew/max",
+		CreatedAt:     time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+		Subject:       "🤝 HANDOFF: auth refactor",
+		Message:       "Tests pass locally.",
+		HookedBead:    "gt-abc",
+		Molecule:      &Molecule{ID: "mol-polecat-work", Step: "gt-abc.3", StepTitle: "Write tests", StepsDone: []string{"gt-abc.1"}, StepsTotal: 4},
+		Git:           &Git{Branch: "main", LastCommit: "0123456789abcdef0123"},
+		FilesTouched:  []string{"auth.go"},
+		OpenQuestions: []string{"Keep the v1 shim?"},
+		Decisions:     []string{"Use sqlite"},
+	}
+	md := r.Markdown()
+	for _, want := range []string{
+		"# 🤝 HANDOFF: auth refactor\n",
+		"Agent: gastown/crew/max\n",
+		"## Hooked Work\ngt-abc\n",
+		"Current step: gt-abc.3 - Write tests\n",
+		"Progress: 1/4 steps complete\n",
+		"Last commit: 0123456789ab\n",
+		"## Files Touched\n- auth.go\n",
+		"## Decisions\n- Use sqlite\n",
+		"## Open Questions\n- Keep the v1 shim?\n",
+		"## Notes\nTests pass locally.\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown lacks %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains((&Record{Agent: "mayor"}).Markdown(), "## ") {
+		t.Error("empty record should render no sections")
+	}
+}
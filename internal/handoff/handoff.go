@@ -0,0 +1,279 @@
+// Package handoff records structured handoff state between agent sessions.
+// Each `gt handoff` writes a Record (JSON, with a Markdown rendering next to
+// it) to <town>/.runtime/handoffs/<agent>/. Records link to the one before,
+// forming a chain the next session can diff against.
+package handoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// DefaultKeep is how many records Save keeps per agent.
+const DefaultKeep = 50
+
+// idFormat is the timestamp layout of record IDs. IDs sort chronologically.
+const idFormat = "20060102T150405Z"
+
+// Record is the structured state one session hands to the next.
+type Record struct {
+	// ID identifies the record within the agent's chain (a UTC timestamp).
+	ID string `json:"id"`
+
+	// Previous is the ID of the record before this one, if any.
+	Previous string `json:"previous,omitempty"`
+
+	// Agent is the agent address (e.g. "mayor", "gastown/crew/max").
+	Agent string `json:"agent"`
+
+	// Session is the tmux session that handed off.
+	Session string `json:"session,omitempty"`
+
+	// CreatedAt is when the handoff happened.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Subject and Message are the handoff mail's subject and body.
+	Subject string `json:"subject,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// HookedBead is the bead on the agent's hook.
+	HookedBead string `json:"hooked_bead,omitempty"`
+
+	// Molecule is the molecule being worked, if any.
+	Molecule *Molecule `json:"molecule,omitempty"`
+
+	// Git is the working tree state at handoff.
+	Git *Git `json:"git,omitempty"`
+
+	// FilesTouched lists files changed since the previous handoff:
+	// committed since its LastCommit, plus uncommitted changes.
+	FilesTouched []string `json:"files_touched,omitempty"`
+
+	// OpenQuestions are questions the session left unresolved.
+	OpenQuestions []string `json:"open_questions,omitempty"`
+
+	// Decisions are decisions the session made.
+	Decisions []string `json:"decisions,omitempty"`
+}
+
+// Molecule is the molecule position at handoff.
+type Molecule struct {
+	ID         string   `json:"id"`                    // molecule (formula) ID
+	Root       string   `json:"root,omitempty"`        // root issue of the instance
+	Step       string   `json:"step,omitempty"`        // step in progress
+	StepTitle  string   `json:"step_title,omitempty"`  // its title
+	StepsDone  []string `json:"steps_done,omitempty"`  // closed step IDs
+	StepsTotal int      `json:"steps_total,omitempty"` // number of steps
+}
+
+// Git is the working tree state at handoff.
+type Git struct {
+	Branch        string   `json:"branch,omitempty"`
+	LastCommit    string   `json:"last_commit,omitempty"`
+	ModifiedFiles []string `json:"modified_files,omitempty"`
+}
+
+// WithCheckpoint copies git, molecule and hook state from a checkpoint
+// (typically from checkpoint.Capture).
+func (r *Record) WithCheckpoint(cp *checkpoint.Checkpoint) *Record {
+	if cp == nil {
+		return r
+	}
+	r.Git = &Git{
+		Branch:        cp.Branch,
+		LastCommit:    cp.LastCommit,
+		ModifiedFiles: cp.ModifiedFiles,
+	}
+	if cp.MoleculeID != "" && r.Molecule == nil {
+		r.Molecule = &Molecule{ID: cp.MoleculeID, Step: cp.CurrentStep, StepTitle: cp.StepTitle}
+	}
+	if cp.HookedBead != "" && r.HookedBead == "" {
+		r.HookedBead = cp.HookedBead
+	}
+	return r
+}
+
+// Age returns how long ago the handoff happened.
+func (r *Record) Age() time.Duration {
+	return time.Since(r.CreatedAt)
+}
+
+// IsStale returns true if the record is at or older than the threshold.
+func (r *Record) IsStale(threshold time.Duration) bool {
+	return r.Age() >= threshold
+}
+
+// Dir returns the directory holding an agent's handoff records.
+func Dir(townRoot, agent string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "handoffs", filepath.FromSlash(normalizeAgent(agent)))
+}
+
+// normalizeAgent strips the trailing slash town-level addresses carry ("mayor/").
+func normalizeAgent(agent string) string {
+	return strings.Trim(agent, "/")
+}
+
+// Save writes r as the newest record in its agent's chain, filling in ID,
+// CreatedAt and Previous when unset, and prunes records beyond DefaultKeep.
+func Save(townRoot string, r *Record) error {
+	r.Agent = normalizeAgent(r.Agent)
+	if r.Agent == "" {
+		return fmt.Errorf("handoff record has no agent")
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	r.CreatedAt = r.CreatedAt.UTC()
+
+	dir := Dir(townRoot, r.Agent)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating handoff dir: %w", err)
+	}
+	if r.Previous == "" {
+		if prev, err := Latest(townRoot, r.Agent); err == nil && prev != nil {
+			r.Previous = prev.ID
+		}
+	}
+	if r.ID == "" {
+		r.ID = r.CreatedAt.Format(idFormat)
+		for n := 2; fileExists(filepath.Join(dir, r.ID+".json")); n++ {
+			r.ID = fmt.Sprintf("%s-%d", r.CreatedAt.Format(idFormat), n)
+		}
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling handoff record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, r.ID+".json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing handoff record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, r.ID+".md"), []byte(r.Markdown()), 0644); err != nil {
+		return fmt.Errorf("writing handoff record: %w", err)
+	}
+	return prune(dir, DefaultKeep)
+}
+
+// Load reads one record from an agent's chain.
+// Returns nil, nil if it doesn't exist.
+func Load(townRoot, agent, id string) (*Record, error) {
+	path := filepath.Join(Dir(townRoot, agent), id+".json")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is built from the town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading handoff record: %w", err)
+	}
+	var r Record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing handoff record %s: %w", id, err)
+	}
+	return &r, nil
+}
+
+// Latest returns the agent's newest record, or nil if there are none.
+func Latest(townRoot, agent string) (*Record, error) {
+	ids, err := listIDs(Dir(townRoot, agent))
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return Load(townRoot, agent, ids[len(ids)-1])
+}
+
+// History returns the agent's records, newest first. A limit of zero or
+// less returns them all. Unreadable records are skipped.
+func History(townRoot, agent string, limit int) ([]*Record, error) {
+	ids, err := listIDs(Dir(townRoot, agent))
+	if err != nil {
+		return nil, err
+	}
+	var out []*Record
+	for i := len(ids) - 1; i >= 0; i-- {
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		r, err := Load(townRoot, agent, ids[i])
+		if err != nil || r == nil {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// listIDs returns the record IDs in dir, oldest first.
+func listIDs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading handoff dir: %w", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && strings.HasSuffix(name, ".json") {
+			ids = append(ids, strings.TrimSuffix(name, ".json"))
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// prune removes all but the newest keep records in dir.
+func prune(dir string, keep int) error {
+	ids, err := listIDs(dir)
+	if err != nil || len(ids) <= keep {
+		return err
+	}
+	for _, id := range ids[:len(ids)-keep] {
+		for _, ext := range []string{".json", ".md"} {
+			if err := os.Remove(filepath.Join(dir, id+ext)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("pruning handoff records: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// TouchedFiles returns the files changed in workDir since the commit base
+// plus any uncommitted changes, sorted. With an empty or unknown base only
+// uncommitted changes are reported.
+func TouchedFiles(workDir, base string, modified []string) []string {
+	seen := make(map[string]bool)
+	for _, f := range modified {
+		seen[f] = true
+	}
+	if base != "" {
+		cmd := exec.Command("git", "diff", "--name-only", base, "HEAD")
+		cmd.Dir = workDir
+		if out, err := cmd.Output(); err == nil {
+			for _, f := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+				if f != "" {
+					seen[f] = true
+				}
+			}
+		}
+	}
+	files := make([]string, 0, len(seen))
+	for f := range seen {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,145 @@
+package handoff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+)
+
+func TestSaveChain(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	first := &Record{Agent: "mayor/", CreatedAt: base, Subject: "first"}
+	if err := Save(town, first); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if first.Agent != "mayor" || first.ID != "20261018T090000Z" || first.Previous != "" {
+		t.Errorf("first = %+v", first)
+	}
+
+	second := &Record{Agent: "mayor", CreatedAt: base, Subject: "second"}
+	if err := Save(town, second); err != nil {
+		t.Fatal(err)
+	}
+	if second.ID != "20261018T090000Z-2" || second.Previous != first.ID {
+		t.Errorf("second ID=%q Previous=%q", second.ID, second.Previous)
+	}
+	if _, err := os.Stat(filepath.Join(Dir(town, "mayor"), second.ID+".md")); err != nil {
+		t.Errorf("markdown not written: %v", err)
+	}
+
+	latest, err := Latest(town, "mayor/")
+	if err != nil || latest == nil || latest.Subject != "second" {
+		t.Fatalf("Latest = %+v, %v", latest, err)
+	}
+	history, err := History(town, "mayor", 0)
+	if err != nil || len(history) != 2 || history[0].Subject != "second" || history[1].Subject != "first" {
+		t.Fatalf("History = %v, %v", history, err)
+	}
+	if history, _ := History(town, "mayor", 1); len(history) != 1 {
+		t.Errorf("History(limit 1) returned %d records", len(history))
+	}
+
+	if r, err := Latest(town, "gastown/crew/max"); r != nil || err != nil {
+		t.Errorf("Latest for an agent without records = %v, %v", r, err)
+	}
+	if r, err := Load(town, "mayor", "nope"); r != nil || err != nil {
+		t.Errorf("Load(missing) = %v, %v", r, err)
+	}
+}
+
+func TestSavePrunes(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < DefaultKeep+3; i++ {
+		if err := Save(town, &Record{Agent: "gastown/witness", CreatedAt: base.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, err := History(town, "gastown/witness", 0)
+	if err != nil || len(history) != DefaultKeep {
+		t.Fatalf("History has %d records, want %d (%v)", len(history), DefaultKeep, err)
+	}
+	if got := history[len(history)-1].CreatedAt; !got.Equal(base.Add(3 * time.Minute)) {
+		t.Errorf("oldest kept record is from %v", got)
+	}
+	entries, _ := os.ReadDir(Dir(town, "gastown/witness"))
+	if len(entries) != 2*DefaultKeep {
+		t.Errorf("handoff dir has %d files, want %d", len(entries), 2*DefaultKeep)
+	}
+}
+
+func TestSaveRequiresAgent(t *testing.T) {
+	if err := Save(t.TempDir(), &Record{Agent: "/"}); err == nil {
+		t.Error("expected Save without an agent to fail")
+	}
+}
+
+func TestWithCheckpoint(t *testing.T) {
+	cp := &checkpoint.Checkpoint{
+		MoleculeID:    "mol-polecat-work",
+		CurrentStep:   "gt-abc.2",
+		StepTitle:     "Write tests",
+		Branch:        "polecat/nux",
+		LastCommit:    "0123456789abcdef",
+		ModifiedFiles: []string{"a.go"},
+		HookedBead:    "gt-abc",
+	}
+	r := (&Record{Agent: "gastown/polecats/nux"}).WithCheckpoint(cp)
+	if r.Git == nil || r.Git.Branch != "polecat/nux" || !reflect.DeepEqual(r.Git.ModifiedFiles, []string{"a.go"}) {
+		t.Errorf("Git = %+v", r.Git)
+	}
+	if r.Molecule == nil || r.Molecule.Step != "gt-abc.2" || r.HookedBead != "gt-abc" {
+		t.Errorf("record = %+v", r)
+	}
+	if (&Record{}).WithCheckpoint(nil).Git != nil {
+		t.Error("nil checkpoint should leave git state empty")
+	}
+}
+
+func TestTouchedFiles(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", name)
+		git("commit", "-m", name)
+	}
+	git("init", "-b", "main")
+	git("config", "user.email", "test@test.com")
+	git("config", "user.name", "Test")
+	commit("a.txt")
+	base := git("rev-parse", "HEAD")
+	commit("b.txt")
+	commit("c.txt")
+
+	got := TouchedFiles(dir, base, []string{"d.txt", "b.txt"})
+	if want := []string{"b.txt", "c.txt", "d.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TouchedFiles = %v, want %v", got, want)
+	}
+	if got := TouchedFiles(dir, "", []string{"d.txt"}); !reflect.DeepEqual(got, []string{"d.txt"}) {
+		t.Errorf("TouchedFiles without base = %v", got)
+	}
+	if got := TouchedFiles(dir, "deadbeef", nil); len(got) != 0 {
+		t.Errorf("TouchedFiles with unknown base = %v", got)
+	}
+}
@@ -0,0 +1,141 @@
+package limits
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cgroupMount is where the cgroup v2 hierarchy is mounted.
+var cgroupMount = "/sys/fs/cgroup"
+
+// cgroupBase returns the parent group for session cgroups. GT_CGROUP_ROOT
+// points it at a delegated group, which lets non-root users use cgroups
+// (e.g. one created with systemd-run --user -p Delegate=yes). The base's
+// parent must be a cgroup v2 group.
+func cgroupBase() (string, bool) {
+	base := os.Getenv("GT_CGROUP_ROOT")
+	if base == "" {
+		base = filepath.Join(cgroupMount, "gastown")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(base), "cgroup.controllers")); err != nil {
+		return "", false
+	}
+	return base, true
+}
+
+// cgroupName returns the session group name for an agent in a rig.
+func cgroupName(rigName, agent string) string {
+	return rigName + "-" + strings.ReplaceAll(agent, "/", "-")
+}
+
+// applyCgroup creates the session group, sets its limits and moves pids
+// into it. The first pid must move; the rest may have exited.
+func applyCgroup(name string, pids []int, l Limits) (string, error) {
+	base, ok := cgroupBase()
+	if !ok {
+		return "", fmt.Errorf("cgroup v2 not available")
+	}
+	if err := os.MkdirAll(base, 0755); err != nil {
+		return "", err
+	}
+	// Delegate the controllers down to session groups. The parent may
+	// already have them enabled, so only the base's write must succeed.
+	_ = writeCgroupFile(filepath.Dir(base), "cgroup.subtree_control", "+cpu +memory +pids")
+	if err := writeCgroupFile(base, "cgroup.subtree_control", "+cpu +memory +pids"); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(base, name)
+	if err := os.Mkdir(dir, 0755); err != nil && !os.IsExist(err) {
+		return "", err
+	}
+	fail := func(err error) (string, error) {
+		_ = os.Remove(dir)
+		return "", err
+	}
+	if l.MemoryMax > 0 {
+		if err := writeCgroupFile(dir, "memory.max", strconv.FormatInt(l.MemoryMax, 10)); err != nil {
+			return fail(err)
+		}
+	}
+	if l.CPUWeight > 0 {
+		if err := writeCgroupFile(dir, "cpu.weight", strconv.Itoa(l.CPUWeight)); err != nil {
+			return fail(err)
+		}
+	}
+	if l.PidsMax > 0 {
+		if err := writeCgroupFile(dir, "pids.max", strconv.Itoa(l.PidsMax)); err != nil {
+			return fail(err)
+		}
+	}
+	for i, pid := range pids {
+		if err := writeCgroupFile(dir, "cgroup.procs", strconv.Itoa(pid)); err != nil && i == 0 {
+			return fail(err)
+		}
+	}
+	return dir, nil
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	// cgroupfs files exist already; O_CREATE only matters for tests
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(value + "\n"); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return f.Close()
+}
+
+// cgroupUsage fills u from the session group's accounting files.
+func cgroupUsage(dir string, u Usage) (Usage, error) {
+	mem, err := readCgroupInt(dir, "memory.current")
+	if err != nil {
+		return u, err
+	}
+	u.MemoryBytes = mem
+	pids, _ := readCgroupInt(dir, "pids.current")
+	u.Pids = int(pids)
+	if usec := readCgroupKeyed(dir, "cpu.stat")["usage_usec"]; usec > 0 {
+		u.CPUTime = time.Duration(usec) * time.Microsecond
+	}
+	u.OOMKills = readCgroupKeyed(dir, "memory.events")["oom_kill"]
+	u.PidsMaxHits = readCgroupKeyed(dir, "pids.events")["max"]
+	return u, nil
+}
+
+func readCgroupInt(dir, name string) (int64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// readCgroupKeyed reads a flat keyed file ("key value" per line).
+func readCgroupKeyed(dir, name string) map[string]int64 {
+	values := make(map[string]int64)
+	f, err := os.Open(filepath.Join(dir, name)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return values
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			values[fields[0]] = n
+		}
+	}
+	return values
+}
@@ -0,0 +1,398 @@
+// Package limits applies per-rig resource limits to agent sessions.
+//
+// Limits come from the "limits" section of a rig's settings/config.json and
+// can be overridden per role. When a session starts, its process tree is
+// moved into a cgroup v2 group with memory.max, cpu.weight and pids.max set.
+// Where cgroup v2 is not available (or not writable), the session falls back
+// to a nice value derived from the CPU weight, and the daemon enforces memory
+// and process counts itself by killing the session's tool processes when the
+// session goes over. Wall-clock time is only monitored.
+//
+// Every limited session has a state file under <rig>/.runtime/limits/, named
+// after the agent's address within the rig (polecats/toast, witness). The
+// daemon reads these to report breaches to the witness, and gt polecat
+// status reads them to show current usage.
+package limits
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Limits is a resolved set of session resource limits. Zero values mean no
+// limit.
+type Limits struct {
+	CPUWeight int           `json:"cpu_weight,omitempty"`
+	MemoryMax int64         `json:"memory_max,omitempty"` // bytes
+	PidsMax   int           `json:"pids_max,omitempty"`
+	WallClock time.Duration `json:"wall_clock,omitempty"`
+}
+
+// FromConfig parses limits from their settings form.
+func FromConfig(c config.ResourceLimits) (Limits, error) {
+	l := Limits{CPUWeight: c.CPUWeight, PidsMax: c.PidsMax}
+	if c.MemoryMax != "" {
+		n, err := config.ParseByteSize(c.MemoryMax)
+		if err != nil {
+			return Limits{}, fmt.Errorf("memory_max: %w", err)
+		}
+		l.MemoryMax = n
+	}
+	if c.WallClock != "" {
+		d, err := time.ParseDuration(c.WallClock)
+		if err != nil {
+			return Limits{}, fmt.Errorf("wall_clock: %w", err)
+		}
+		l.WallClock = d
+	}
+	return l, nil
+}
+
+// IsZero reports whether no limit is set.
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// ForRole loads the limits configured for a role in a rig. A rig without
+// settings has no limits.
+func ForRole(rigPath, role string) (Limits, error) {
+	settings, err := config.LoadRigSettings(filepath.Join(rigPath, "settings", "config.json"))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return Limits{}, nil
+		}
+		return Limits{}, err
+	}
+	return FromConfig(settings.Limits.ForRole(role))
+}
+
+// Mechanism is how a session's limits are enforced.
+type Mechanism string
+
+const (
+	// MechanismCgroup means the session runs in its own cgroup v2 group.
+	MechanismCgroup Mechanism = "cgroup"
+	// MechanismPrlimit means a nice value, with memory and process counts
+	// enforced by the monitor.
+	MechanismPrlimit Mechanism = "prlimit"
+)
+
+// Breach kinds, used in State.Reported and Breach.Kind.
+const (
+	KindMemory    = "memory"
+	KindPids      = "pids"
+	KindWallClock = "wall_clock"
+)
+
+// State records how a session was limited.
+type State struct {
+	Agent     string    `json:"agent"` // address within the rig, e.g. polecats/toast
+	Role      string    `json:"role"`
+	PID       int       `json:"pid"` // session's main process
+	Mechanism Mechanism `json:"mechanism"`
+	Cgroup    string    `json:"cgroup,omitempty"`
+	Limits    Limits    `json:"limits"`
+	StartedAt time.Time `json:"started_at"`
+
+	// Reported lists breach kinds already reported for this session, so
+	// each is reported once.
+	Reported []string `json:"reported,omitempty"`
+}
+
+// statePath returns the state file for an agent in a rig.
+func statePath(rigPath, agent string) string {
+	return filepath.Join(rigPath, ".runtime", "limits", filepath.FromSlash(agent)+".json")
+}
+
+// Load returns the limit state for an agent, or nil if its session is not
+// limited.
+func Load(rigPath, agent string) *State {
+	data, err := os.ReadFile(statePath(rigPath, agent)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+	return &s
+}
+
+func (s *State) save(rigPath string) error {
+	path := statePath(rigPath, s.Agent)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, s)
+}
+
+// Apply places a session's process tree under the limits configured for
+// role in the rig and records the state. agent is the session's address
+// within the rig. It returns nil state when the role has no limits.
+func Apply(rigPath, agent, role string, pid int) (*State, error) {
+	l, err := ForRole(rigPath, role)
+	if err != nil {
+		return nil, err
+	}
+	// Drop the previous session's state and cgroup, if any
+	Release(rigPath, agent)
+	if l.IsZero() {
+		return nil, nil
+	}
+
+	s := &State{
+		Agent:     agent,
+		Role:      role,
+		PID:       pid,
+		Limits:    l,
+		StartedAt: time.Now(),
+	}
+	pids := append([]int{pid}, descendants(pid)...)
+	name := cgroupName(filepath.Base(rigPath), agent)
+	if dir, err := applyCgroup(name, pids, l); err == nil {
+		s.Mechanism = MechanismCgroup
+		s.Cgroup = dir
+	} else {
+		if err := applyRlimits(pids, l); err != nil {
+			return nil, fmt.Errorf("applying limits: %w", err)
+		}
+		s.Mechanism = MechanismPrlimit
+	}
+	if err := s.save(rigPath); err != nil {
+		return nil, fmt.Errorf("saving limit state: %w", err)
+	}
+	return s, nil
+}
+
+// SessionPIDs is the part of a session backend Apply needs.
+type SessionPIDs interface {
+	GetPanePID(session string) (string, error)
+}
+
+// ApplyToSession applies role limits to a running session. See Apply.
+func ApplyToSession(b SessionPIDs, sessionID, rigPath, agent, role string) (*State, error) {
+	out, err := b.GetPanePID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("getting session pid: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return nil, fmt.Errorf("invalid session pid %q", out)
+	}
+	return Apply(rigPath, agent, role, pid)
+}
+
+// Release removes an agent's limit state and cgroup after its session ends.
+func Release(rigPath, agent string) {
+	if s := Load(rigPath, agent); s != nil && s.Cgroup != "" {
+		_ = os.Remove(s.Cgroup)
+	}
+	_ = os.Remove(statePath(rigPath, agent))
+}
+
+// Usage is a session's current resource usage.
+type Usage struct {
+	MemoryBytes int64         `json:"memory_bytes"`
+	Pids        int           `json:"pids"`
+	CPUTime     time.Duration `json:"cpu_time"`
+	Elapsed     time.Duration `json:"elapsed"`
+
+	// Enforcement events since the session started (cgroup only)
+	OOMKills    int64 `json:"oom_kills,omitempty"`
+	PidsMaxHits int64 `json:"pids_max_hits,omitempty"`
+}
+
+// Usage reads the session's current usage.
+func (s *State) Usage() (Usage, error) {
+	u := Usage{Elapsed: time.Since(s.StartedAt)}
+	if s.Mechanism == MechanismCgroup {
+		return cgroupUsage(s.Cgroup, u)
+	}
+	if !processAlive(s.PID) {
+		return u, fmt.Errorf("process %d is not running", s.PID)
+	}
+	for _, pid := range append([]int{s.PID}, descendants(s.PID)...) {
+		mem, cpu, ok := procUsage(pid)
+		if !ok {
+			continue
+		}
+		u.Pids++
+		u.MemoryBytes += mem
+		u.CPUTime += cpu
+	}
+	return u, nil
+}
+
+// Breach is a limit a session has hit.
+type Breach struct {
+	Agent     string
+	Role      string
+	Kind      string // KindMemory, KindPids or KindWallClock
+	Usage     string
+	Max       string
+	Mechanism Mechanism
+	Enforced  string // what the monitor did about it, if anything
+
+	// Repeat is set for a breach already reported, returned again only
+	// because the monitor enforced it.
+	Repeat bool
+}
+
+// breaches returns the limits the session has hit, reported or not.
+func (s *State) breaches(u Usage) []Breach {
+	var out []Breach
+	add := func(kind, usage, max string) {
+		out = append(out, Breach{Agent: s.Agent, Role: s.Role, Kind: kind, Usage: usage, Max: max, Mechanism: s.Mechanism})
+	}
+	l := s.Limits
+	if l.MemoryMax > 0 {
+		if u.OOMKills > 0 {
+			add(KindMemory, fmt.Sprintf("%d OOM kill(s)", u.OOMKills), FormatBytes(l.MemoryMax))
+		} else if u.MemoryBytes >= l.MemoryMax {
+			add(KindMemory, FormatBytes(u.MemoryBytes), FormatBytes(l.MemoryMax))
+		}
+	}
+	if l.PidsMax > 0 {
+		if u.PidsMaxHits > 0 {
+			add(KindPids, fmt.Sprintf("%d fork(s) refused", u.PidsMaxHits), strconv.Itoa(l.PidsMax))
+		} else if u.Pids >= l.PidsMax {
+			add(KindPids, strconv.Itoa(u.Pids), strconv.Itoa(l.PidsMax))
+		}
+	}
+	if l.WallClock > 0 && u.Elapsed > l.WallClock {
+		add(KindWallClock, u.Elapsed.Round(time.Minute).String(), l.WallClock.String())
+	}
+	return out
+}
+
+// enforce brings a prlimit session back under its memory and process
+// limits by killing tool processes, the way the kernel would in a cgroup.
+// The session's main process and its direct children (the shell and the
+// agent) are never killed. It returns a description of what was killed, or
+// "" if nothing was. Cgroup sessions are enforced by the kernel.
+func (s *State) enforce(b Breach, u Usage) string {
+	if s.Mechanism != MechanismPrlimit {
+		return ""
+	}
+	tools := descendantsBelow(s.PID, 2)
+	var killed []string
+	switch b.Kind {
+	case KindMemory:
+		// Like the OOM killer, kill the largest process
+		var victim int
+		var largest int64
+		for _, pid := range tools {
+			if mem, _, ok := procUsage(pid); ok && mem > largest {
+				victim, largest = pid, mem
+			}
+		}
+		if victim != 0 && killProcess(victim) == nil {
+			killed = append(killed, fmt.Sprintf("%d (%s)", victim, FormatBytes(largest)))
+		}
+	case KindPids:
+		// Kill the newest processes, children before parents
+		for i := len(tools) - 1; i >= 0 && u.Pids-len(killed) > s.Limits.PidsMax; i-- {
+			if killProcess(tools[i]) == nil {
+				killed = append(killed, strconv.Itoa(tools[i]))
+			}
+		}
+	}
+	if len(killed) == 0 {
+		return ""
+	}
+	return "killed " + strings.Join(killed, ", ")
+}
+
+// CheckBreaches enforces the limits of every limited session in a rig and
+// returns the breaches that have not been reported yet, marking them
+// reported, along with repeat breaches the monitor enforced. State for
+// sessions whose process has exited is released.
+func CheckBreaches(rigPath string) ([]Breach, error) {
+	root := filepath.Join(rigPath, ".runtime", "limits")
+	var found []Breach
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, strings.TrimSuffix(path, ".json"))
+		s := Load(rigPath, filepath.ToSlash(rel))
+		if s == nil {
+			return nil
+		}
+		if !processAlive(s.PID) {
+			Release(rigPath, s.Agent)
+			return nil
+		}
+		u, err := s.Usage()
+		if err != nil {
+			return nil
+		}
+		var fresh bool
+		for _, b := range s.breaches(u) {
+			b.Enforced = s.enforce(b, u)
+			if s.reported(b.Kind) {
+				if b.Enforced != "" {
+					b.Repeat = true
+					found = append(found, b)
+				}
+				continue
+			}
+			s.Reported = append(s.Reported, b.Kind)
+			found = append(found, b)
+			fresh = true
+		}
+		if fresh {
+			return s.save(rigPath)
+		}
+		return nil
+	})
+	return found, err
+}
+
+func (s *State) reported(kind string) bool {
+	for _, k := range s.Reported {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// niceForWeight maps a cgroup cpu.weight to the nice value with about the
+// same share. The scheduler gives each nice level about 1.25x the weight of
+// the next, and weight 100 is nice 0.
+func niceForWeight(weight int) int {
+	nice := int(math.Round(-math.Log(float64(weight)/100) / math.Log(1.25)))
+	return max(-20, min(19, nice))
+}
+
+// FormatBytes renders a byte count with a binary unit, e.g. "1.5G".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+	s := strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64)
+	return strings.TrimSuffix(s, ".0") + string("KMGT"[exp])
+}
@@ -0,0 +1,313 @@
+package limits
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// setupRig creates a rig with the given limits JSON in its settings.
+func setupRig(t *testing.T, limitsJSON string) string {
+	t.Helper()
+	rigPath := filepath.Join(t.TempDir(), "gastown")
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type": "rig-settings", "version": 1, "limits": ` + limitsJSON + `}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return rigPath
+}
+
+// fakeCgroupRoot points session cgroups at a plain directory tree that
+// looks like a cgroup v2 hierarchy.
+func fakeCgroupRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpu memory pids\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	base := filepath.Join(root, "gastown")
+	t.Setenv("GT_CGROUP_ROOT", base)
+	return base
+}
+
+// noCgroups makes cgroup v2 unavailable.
+func noCgroups(t *testing.T) {
+	t.Setenv("GT_CGROUP_ROOT", filepath.Join(t.TempDir(), "missing", "gastown"))
+}
+
+func startSleep(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd.Process.Pid
+}
+
+func TestForRole(t *testing.T) {
+	rigPath := setupRig(t, `{"memory_max": "4G", "wall_clock": "2h", "roles": {"polecat": {"memory_max": "1G", "cpu_weight": 50}}}`)
+
+	l, err := ForRole(rigPath, "polecat")
+	if err != nil {
+		t.Fatalf("ForRole: %v", err)
+	}
+	want := Limits{CPUWeight: 50, MemoryMax: 1 << 30, WallClock: 2 * time.Hour}
+	if l != want {
+		t.Errorf("ForRole(polecat) = %+v, want %+v", l, want)
+	}
+
+	if l, err := ForRole(t.TempDir(), "polecat"); err != nil || !l.IsZero() {
+		t.Errorf("rig without settings = %+v, %v; want no limits", l, err)
+	}
+}
+
+func TestApply_Cgroup(t *testing.T) {
+	base := fakeCgroupRoot(t)
+	rigPath := setupRig(t, `{"memory_max": "512M", "cpu_weight": 50, "pids_max": 64}`)
+
+	s, err := Apply(rigPath, "polecats/toast", "polecat", os.Getpid())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if s.Mechanism != MechanismCgroup || s.Cgroup != filepath.Join(base, "gastown-polecats-toast") {
+		t.Fatalf("state = %+v, want cgroup gastown-polecats-toast", s)
+	}
+	for file, want := range map[string]string{
+		"memory.max":   strconv.Itoa(512 << 20),
+		"cpu.weight":   "50",
+		"pids.max":     "64",
+		"cgroup.procs": strconv.Itoa(os.Getpid()),
+	} {
+		data, _ := os.ReadFile(filepath.Join(s.Cgroup, file))
+		if strings.TrimSpace(string(data)) != want {
+			t.Errorf("%s = %q, want %q", file, data, want)
+		}
+	}
+
+	loaded := Load(rigPath, "polecats/toast")
+	if loaded == nil || loaded.Cgroup != s.Cgroup || loaded.Limits != s.Limits {
+		t.Errorf("Load = %+v, want %+v", loaded, s)
+	}
+
+	Release(rigPath, "polecats/toast")
+	if Load(rigPath, "polecats/toast") != nil {
+		t.Error("state should be removed after Release")
+	}
+}
+
+func TestApply_NoLimits(t *testing.T) {
+	fakeCgroupRoot(t)
+	rigPath := setupRig(t, `{"roles": {"crew": {"pids_max": 10}}}`)
+
+	s, err := Apply(rigPath, "polecats/toast", "polecat", os.Getpid())
+	if err != nil || s != nil {
+		t.Errorf("Apply without polecat limits = %+v, %v; want nil", s, err)
+	}
+	if Load(rigPath, "polecats/toast") != nil {
+		t.Error("no state should be written without limits")
+	}
+}
+
+func TestApply_PrlimitFallback(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("prlimit is linux-only")
+	}
+	noCgroups(t)
+	rigPath := setupRig(t, `{"memory_max": "8G", "cpu_weight": 50}`)
+	pid := startSleep(t)
+	var before unix.Rlimit
+	if err := unix.Prlimit(pid, unix.RLIMIT_AS, nil, &before); err != nil {
+		t.Fatalf("reading rlimit: %v", err)
+	}
+
+	s, err := Apply(rigPath, "polecats/toast", "polecat", pid)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if s.Mechanism != MechanismPrlimit {
+		t.Fatalf("mechanism = %s, want prlimit", s.Mechanism)
+	}
+
+	// The agent's address space is left alone; memory is enforced by the
+	// monitor
+	var rl unix.Rlimit
+	if err := unix.Prlimit(pid, unix.RLIMIT_AS, nil, &rl); err != nil {
+		t.Fatalf("reading rlimit: %v", err)
+	}
+	if rl != before {
+		t.Errorf("RLIMIT_AS = %+v, want unchanged %+v", rl, before)
+	}
+	if nice, err := unix.Getpriority(unix.PRIO_PROCESS, pid); err != nil || 20-nice != niceForWeight(50) {
+		t.Errorf("nice = %d (%v), want %d", 20-nice, err, niceForWeight(50))
+	}
+
+	u, err := s.Usage()
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	if u.Pids != 1 || u.MemoryBytes <= 0 {
+		t.Errorf("usage = %+v, want one process with resident memory", u)
+	}
+}
+
+func TestCheckBreaches(t *testing.T) {
+	fakeCgroupRoot(t)
+	rigPath := setupRig(t, `{"memory_max": "1G", "pids_max": 100, "wall_clock": "1h"}`)
+
+	s, err := Apply(rigPath, "polecats/toast", "polecat", os.Getpid())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(s.Cgroup, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("memory.current", "1000\n")
+	writeFile("pids.current", "3\n")
+
+	if breaches, err := CheckBreaches(rigPath); err != nil || len(breaches) != 0 {
+		t.Fatalf("CheckBreaches within limits = %+v, %v", breaches, err)
+	}
+
+	writeFile("memory.events", "low 0\nhigh 0\nmax 12\noom 1\noom_kill 1\n")
+	breaches, err := CheckBreaches(rigPath)
+	if err != nil || len(breaches) != 1 {
+		t.Fatalf("CheckBreaches = %+v, %v; want one memory breach", breaches, err)
+	}
+	b := breaches[0]
+	if b.Agent != "polecats/toast" || b.Kind != KindMemory || b.Max != "1G" || b.Mechanism != MechanismCgroup {
+		t.Errorf("breach = %+v", b)
+	}
+
+	// Reported once; a later wall-clock breach is still reported
+	s = Load(rigPath, "polecats/toast")
+	s.StartedAt = time.Now().Add(-2 * time.Hour)
+	if err := s.save(rigPath); err != nil {
+		t.Fatal(err)
+	}
+	breaches, err = CheckBreaches(rigPath)
+	if err != nil || len(breaches) != 1 || breaches[0].Kind != KindWallClock {
+		t.Errorf("CheckBreaches after wall clock = %+v, %v; want only wall_clock", breaches, err)
+	}
+	if breaches, _ := CheckBreaches(rigPath); len(breaches) != 0 {
+		t.Errorf("breaches reported twice: %+v", breaches)
+	}
+}
+
+func TestCheckBreaches_EnforcesPrlimit(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("prlimit is linux-only")
+	}
+	noCgroups(t)
+	rigPath := setupRig(t, `{"pids_max": 4, "memory_max": "1G"}`)
+
+	// session shell -> agent -> three tools
+	cmd := exec.Command("sh", "-c", "sh -c 'sleep 30 & sleep 30 & sleep 30 & wait' & wait")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sh: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, pid := range descendants(cmd.Process.Pid) {
+			_ = killProcess(pid)
+		}
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	pid := cmd.Process.Pid
+	deadline := time.Now().Add(5 * time.Second)
+	for len(descendants(pid)) < 4 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := len(descendants(pid)); n != 4 {
+		t.Skipf("session has %d processes below it, want 4", n)
+	}
+
+	if _, err := Apply(rigPath, "polecats/toast", "polecat", pid); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	breaches, err := CheckBreaches(rigPath)
+	if err != nil || len(breaches) != 1 {
+		t.Fatalf("CheckBreaches = %+v, %v; want one pids breach", breaches, err)
+	}
+	b := breaches[0]
+	if b.Kind != KindPids || b.Usage != "5" || b.Mechanism != MechanismPrlimit || !strings.HasPrefix(b.Enforced, "killed ") {
+		t.Errorf("breach = %+v", b)
+	}
+
+	// One tool was killed; the shell and the agent survive
+	deadline = time.Now().Add(5 * time.Second)
+	for len(descendants(pid)) > 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !processAlive(pid) || len(descendantsBelow(pid, 1))-len(descendantsBelow(pid, 2)) != 1 {
+		t.Error("the session shell and agent should not be killed")
+	}
+	if n := len(descendantsBelow(pid, 2)); n != 2 {
+		t.Errorf("%d tools left, want 2", n)
+	}
+}
+
+func TestCheckBreaches_ReleasesExitedSessions(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("process checks are linux-only")
+	}
+	noCgroups(t)
+	rigPath := setupRig(t, `{"cpu_weight": 100}`)
+
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot run true: %v", err)
+	}
+	s := &State{Agent: "crew/max", Role: "crew", PID: cmd.Process.Pid, Mechanism: MechanismPrlimit, StartedAt: time.Now()}
+	if err := s.save(rigPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CheckBreaches(rigPath); err != nil {
+		t.Fatalf("CheckBreaches: %v", err)
+	}
+	if Load(rigPath, "crew/max") != nil {
+		t.Error("state of an exited session should be released")
+	}
+}
+
+func TestNiceForWeight(t *testing.T) {
+	tests := map[int]int{100: 0, 80: 1, 50: 3, 200: -3, 1: 19, 10000: -20}
+	for weight, want := range tests {
+		if got := niceForWeight(weight); got != want {
+			t.Errorf("niceForWeight(%d) = %d, want %d", weight, got, want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512B",
+		1 << 10:         "1K",
+		1536 << 20:      "1.5G",
+		4 << 30:         "4G",
+		3 << 40:         "3T",
+		100<<20 + 1<<19: "100.5M",
+	}
+	for n, want := range tests {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package limits
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// clockTicks is USER_HZ, the unit of CPU times in /proc/<pid>/stat. It is
+// 100 on every Linux platform Gas Town runs on.
+const clockTicks = 100
+
+// processAlive reports whether pid is a running (non-zombie) process.
+func processAlive(pid int) bool {
+	fields := procStat(pid)
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+// procStat returns the fields of /proc/<pid>/stat after the command name,
+// starting with the state.
+func procStat(pid int) []string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return nil
+	}
+	// The command name may contain spaces; it ends at the last ')'
+	s := string(data)
+	i := strings.LastIndexByte(s, ')')
+	if i < 0 {
+		return nil
+	}
+	return strings.Fields(s[i+1:])
+}
+
+// descendants returns every process below pid, parents before children.
+func descendants(pid int) []int {
+	return descendantsBelow(pid, 1)
+}
+
+// descendantsBelow returns the processes at least depth levels below pid,
+// parents before children.
+func descendantsBelow(pid, depth int) []int {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	children := make(map[int][]int)
+	for _, e := range entries {
+		child, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		fields := procStat(child)
+		if len(fields) < 2 {
+			continue
+		}
+		if ppid, err := strconv.Atoi(fields[1]); err == nil {
+			children[ppid] = append(children[ppid], child)
+		}
+	}
+	var out []int
+	level := children[pid]
+	for d := 1; len(level) > 0; d++ {
+		var next []int
+		for _, p := range level {
+			if d >= depth {
+				out = append(out, p)
+			}
+			next = append(next, children[p]...)
+		}
+		level = next
+	}
+	return out
+}
+
+// procUsage returns a process's resident memory and CPU time.
+func procUsage(pid int) (mem int64, cpu time.Duration, ok bool) {
+	fields := procStat(pid)
+	// utime and stime are fields 14 and 15 of stat; fields[0] is field 3
+	if len(fields) < 13 {
+		return 0, 0, false
+	}
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	cpu = time.Duration(utime+stime) * time.Second / clockTicks
+
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "statm"))
+	if err != nil {
+		return 0, cpu, true
+	}
+	if statm := strings.Fields(string(data)); len(statm) > 1 {
+		pages, _ := strconv.ParseInt(statm[1], 10, 64)
+		mem = pages * int64(os.Getpagesize())
+	}
+	return mem, cpu, true
+}
+
+// applyRlimits renices each process for the CPU weight. Children started
+// later inherit it. Processes cannot be given more CPU than they have without
+// privileges, so a failure to lower the nice value is ignored. Errors for
+// processes other than the first (which may have exited) are ignored.
+//
+// Memory is deliberately not capped with RLIMIT_AS: agent runtimes such as
+// node reserve far more address space than they use and crash under it, and
+// a lowered hard limit can't be raised again. Memory and process counts are
+// enforced by the monitor instead (see State.enforce).
+func applyRlimits(pids []int, l Limits) error {
+	if l.CPUWeight <= 0 {
+		return nil
+	}
+	nice := niceForWeight(l.CPUWeight)
+	for i, pid := range pids {
+		err := unix.Setpriority(unix.PRIO_PROCESS, pid, nice)
+		if err != nil && (nice < 0 && (errors.Is(err, unix.EACCES) || errors.Is(err, unix.EPERM))) {
+			err = nil
+		}
+		if err != nil && i == 0 {
+			return err
+		}
+	}
+	return nil
+}
+
+// killProcess kills a process outright.
+func killProcess(pid int) error {
+	return unix.Kill(pid, unix.SIGKILL)
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package limits
+
+import (
+	"errors"
+	"time"
+)
+
+func processAlive(int) bool { return false }
+
+func descendants(int) []int { return nil }
+
+func descendantsBelow(int, int) []int { return nil }
+
+func procUsage(int) (int64, time.Duration, bool) { return 0, 0, false }
+
+func applyRlimits([]int, Limits) error {
+	return errors.New("resource limits are only supported on linux")
+}
+
+func killProcess(int) error {
+	return errors.New("resource limits are only supported on linux")
+}
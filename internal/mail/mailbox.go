@@ -179,7 +179,24 @@ func (m *Mailbox) identityVariants() []string {
 }
 
 // queryMessages runs a bd list query with the given filter flag and value.
+// A one-off inbox check is faster through bd than a cold native load, so the
+// database is only read natively when this process already has a snapshot
+// (see beads.NativeCached).
 func (m *Mailbox) queryMessages(beadsDir, filterFlag, filterValue, status string) ([]*Message, error) {
+	if snap, ok := beads.NativeCached(beadsDir); ok {
+		opts := beads.ListOptions{IssueType: "message", Status: status, Priority: -1}
+		if filterFlag == "--label" {
+			opts.Label = filterValue
+		} else {
+			opts.Assignee = filterValue
+		}
+		var messages []*Message
+		for _, issue := range snap.List(opts) {
+			messages = append(messages, beadsMessageFromIssue(issue).ToMessage())
+		}
+		return messages, nil
+	}
+
 	args := []string{"list",
 		"--type", "message",
 		filterFlag, filterValue,
@@ -211,6 +228,24 @@ func (m *Mailbox) queryMessages(beadsDir, filterFlag, filterValue, status string
 	return messages, nil
 }
 
+// beadsMessageFromIssue converts a natively read issue to the shape bd list
+// --json produces for messages.
+func beadsMessageFromIssue(issue *beads.Issue) *BeadsMessage {
+	created, _ := time.Parse(time.RFC3339Nano, issue.CreatedAt)
+	return &BeadsMessage{
+		ID:          issue.ID,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Assignee:    issue.Assignee,
+		Priority:    issue.Priority,
+		Status:      issue.Status,
+		CreatedAt:   created,
+		Labels:      issue.Labels,
+		Pinned:      issue.Pinned,
+		Wisp:        issue.Wisp,
+	}
+}
+
 func (m *Mailbox) listLegacy() ([]*Message, error) {
 	file, err := os.Open(m.path)
 	if err != nil {
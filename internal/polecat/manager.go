@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -48,11 +49,11 @@ type Manager struct {
 	git      *git.Git
 	beads    *beads.Beads
 	namePool *NamePool
-	tmux     *tmux.Tmux
+	sessions tmux.SessionBackend
 }
 
 // NewManager creates a new polecat manager.
-func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
+func NewManager(r *rig.Rig, g *git.Git, t tmux.SessionBackend) *Manager {
 	// Use the resolved beads directory to find where bd commands should run.
 	// For tracked beads: rig/.beads/redirect -> mayor/rig/.beads, so use mayor/rig
 	// For local beads: rig/.beads is the database, so use rig root
@@ -84,7 +85,7 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 		git:      g,
 		beads:    beads.NewWithBeadsDir(beadsPath, resolvedBeads),
 		namePool: pool,
-		tmux:     t,
+		sessions: t,
 	}
 }
 
@@ -183,6 +184,26 @@ func (m *Manager) repoBase() (*git.Git, error) {
 	return git.NewGit(mayorPath), nil
 }
 
+// snapshotWork saves uncommitted work in a polecat worktree as a checkpoint
+// snapshot and prunes old snapshots. Non-fatal: returns nil if the tree is
+// clean or the snapshot could not be taken.
+func (m *Manager) snapshotWork(name, clonePath string) *checkpoint.SnapshotInfo {
+	if _, err := os.Stat(filepath.Join(clonePath, ".git")); err != nil {
+		return nil
+	}
+	snap, err := checkpoint.Snapshot(clonePath, name)
+	if err != nil {
+		fmt.Printf("Warning: could not snapshot uncommitted work: %v\n", err)
+		return nil
+	}
+	if snap == nil {
+		return nil
+	}
+	fmt.Printf("Saved uncommitted work to %s (restore with: gt checkpoint restore %s)\n", snap.Ref, snap.Ref)
+	_, _ = checkpoint.PruneSnapshots(clonePath, name, checkpoint.DefaultSnapshotKeep, checkpoint.DefaultSnapshotMaxAge)
+	return snap
+}
+
 // polecatDir returns the parent directory for a polecat.
 // This is polecats/<name>/ - the polecat's home directory.
 func (m *Manager) polecatDir(name string) string {
@@ -222,6 +243,10 @@ func (m *Manager) exists(name string) bool {
 // AddOptions configures polecat creation.
 type AddOptions struct {
 	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
+
+	// RestoreSnapshot reapplies the pre-repair checkpoint snapshot into the
+	// fresh worktree (RepairWorktreeWithOptions only).
+	RestoreSnapshot bool
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -249,28 +274,80 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	polecatDir := m.polecatDir(name)
 	clonePath := filepath.Join(polecatDir, m.rig.Name)
 
-	// Branch naming: include issue ID when available for better traceability.
-	// Format: polecat/<worker>/<issue>@<timestamp> when HookBead is set
-	// The @timestamp suffix ensures uniqueness if the same issue is re-slung.
-	// parseBranchName strips the @suffix to extract the issue ID.
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 36)
-	var branchName string
-	if opts.HookBead != "" {
-		branchName = fmt.Sprintf("polecat/%s/%s@%s", name, opts.HookBead, timestamp)
-	} else {
-		// Fallback to timestamp format when no issue is known at spawn time
-		branchName = fmt.Sprintf("polecat/%s-%s", name, timestamp)
-	}
+	branchName := polecatBranchName(name, opts.HookBead)
 
 	// Create polecat directory (polecats/<name>/)
 	if err := os.MkdirAll(polecatDir, 0755); err != nil {
 		return nil, fmt.Errorf("creating polecat dir: %w", err)
 	}
 
+	if err := m.provisionWorktree(clonePath, branchName); err != nil {
+		return nil, err
+	}
+
+	// NOTE: Slash commands (.claude/commands/) are provisioned at town level by gt install.
+	// All agents inherit them via Claude's directory traversal - no per-workspace copies needed.
+
+	m.createAgentBead(name, opts.HookBead)
+
+	// Return polecat with working state (transient model: polecats are spawned with work)
+	// State is derived from beads, not stored in state.json
+	now := time.Now()
+	polecat := &Polecat{
+		Name:      name,
+		Rig:       m.rig.Name,
+		State:     StateWorking, // Transient model: polecat spawns with work
+		ClonePath: clonePath,
+		Branch:    branchName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return polecat, nil
+}
+
+// createAgentBead creates or reopens the polecat's agent bead for ZFC
+// compliance (self-report state). Non-fatal: logs a warning on failure.
+// State starts as "spawning" - will be updated to "working" when Claude starts.
+// HookBead is set atomically at creation time if provided (avoids cross-beads routing issues).
+// Uses CreateOrReopenAgentBead to handle re-spawning with same name (GH #332).
+func (m *Manager) createAgentBead(name, hookBead string) {
+	agentID := m.agentBeadID(name)
+	_, err := m.beads.CreateOrReopenAgentBead(agentID, agentID, &beads.AgentFields{
+		RoleType:   "polecat",
+		Rig:        m.rig.Name,
+		AgentState: "spawning",
+		RoleBead:   beads.RoleBeadIDTown("polecat"),
+		HookBead:   hookBead, // Set atomically at spawn time
+	})
+	if err != nil {
+		fmt.Printf("Warning: could not create agent bead: %v\n", err)
+	}
+}
+
+// polecatBranchName returns a fresh branch name for a polecat run.
+// Branch naming: include issue ID when available for better traceability.
+// Format: polecat/<worker>/<issue>@<timestamp> when the hook bead is known.
+// The @timestamp suffix ensures uniqueness if the same issue is re-slung.
+// parseBranchName strips the @suffix to extract the issue ID.
+func polecatBranchName(name, hookBead string) string {
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 36)
+	if hookBead != "" {
+		return fmt.Sprintf("polecat/%s/%s@%s", name, hookBead, timestamp)
+	}
+	// Fallback to timestamp format when no issue is known at spawn time
+	return fmt.Sprintf("polecat/%s-%s", name, timestamp)
+}
+
+// provisionWorktree creates a fresh worktree on a new branch from the rig's
+// default branch and prepares it for an agent: AGENTS.md, shared beads,
+// PRIME.md, overlay files and setup hooks. Everything after the worktree
+// itself is best-effort.
+func (m *Manager) provisionWorktree(clonePath, branchName string) error {
 	// Get the repo base (bare repo or mayor/rig)
 	repoGit, err := m.repoBase()
 	if err != nil {
-		return nil, fmt.Errorf("finding repo base: %w", err)
+		return fmt.Errorf("finding repo base: %w", err)
 	}
 
 	// Fetch latest from origin to ensure worktree starts from up-to-date code
@@ -281,19 +358,24 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 
 	// Determine the start point for the new worktree
 	// Use origin/<default-branch> to ensure we start from the rig's configured branch
-	defaultBranch := "main"
-	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
-		defaultBranch = rigCfg.DefaultBranch
-	}
-	startPoint := fmt.Sprintf("origin/%s", defaultBranch)
+	startPoint := fmt.Sprintf("origin/%s", m.defaultBranch())
 
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
 	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
 	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
-		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
+		return fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
 
+	m.prepareWorktree(clonePath)
+	return nil
+}
+
+// prepareWorktree readies a checked-out worktree for an agent: AGENTS.md,
+// shared beads, PRIME.md, overlay files and setup hooks. It is rerun when a
+// warm worktree moves to new code, so every step must be safe to repeat.
+// Failures are logged as warnings.
+func (m *Manager) prepareWorktree(clonePath string) {
 	// Ensure AGENTS.md exists - critical for polecats to "land the plane"
 	// Fall back to copy from mayor/rig if not in git (e.g., stale fetch, local-only file)
 	agentsMDPath := filepath.Join(clonePath, "AGENTS.md")
@@ -340,41 +422,14 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		// Non-fatal - log warning but continue
 		fmt.Printf("Warning: could not run setup hooks: %v\n", err)
 	}
+}
 
-	// NOTE: Slash commands (.claude/commands/) are provisioned at town level by gt install.
-	// All agents inherit them via Claude's directory traversal - no per-workspace copies needed.
-
-	// Create or reopen agent bead for ZFC compliance (self-report state).
-	// State starts as "spawning" - will be updated to "working" when Claude starts.
-	// HookBead is set atomically at creation time if provided (avoids cross-beads routing issues).
-	// Uses CreateOrReopenAgentBead to handle re-spawning with same name (GH #332).
-	agentID := m.agentBeadID(name)
-	_, err = m.beads.CreateOrReopenAgentBead(agentID, agentID, &beads.AgentFields{
-		RoleType:   "polecat",
-		Rig:        m.rig.Name,
-		AgentState: "spawning",
-		RoleBead:   beads.RoleBeadIDTown("polecat"),
-		HookBead:   opts.HookBead, // Set atomically at spawn time
-	})
-	if err != nil {
-		// Non-fatal - log warning but continue
-		fmt.Printf("Warning: could not create agent bead: %v\n", err)
-	}
-
-	// Return polecat with working state (transient model: polecats are spawned with work)
-	// State is derived from beads, not stored in state.json
-	now := time.Now()
-	polecat := &Polecat{
-		Name:      name,
-		Rig:       m.rig.Name,
-		State:     StateWorking, // Transient model: polecat spawns with work
-		ClonePath: clonePath,
-		Branch:    branchName,
-		CreatedAt: now,
-		UpdatedAt: now,
+// defaultBranch returns the rig's configured default branch (main if unset).
+func (m *Manager) defaultBranch() string {
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		return rigCfg.DefaultBranch
 	}
-
-	return polecat, nil
+	return "main"
 }
 
 // Remove deletes a polecat worktree.
@@ -429,6 +484,9 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear bool) error {
 		}
 	}
 
+	// Preserve any uncommitted work that force/nuclear is about to discard
+	m.snapshotWork(name, clonePath)
+
 	// Get repo base to remove the worktree properly
 	repoGit, err := m.repoBase()
 	if err != nil {
@@ -519,8 +577,11 @@ func (m *Manager) ReleaseName(name string) {
 //
 // Branch naming: Each repair gets a unique branch (polecat/<name>-<timestamp>).
 // Old branches are left for garbage collection - they're never pushed to origin.
+//
+// Uncommitted work in the old worktree is saved as a checkpoint snapshot and
+// reapplied to the fresh worktree.
 func (m *Manager) RepairWorktree(name string, force bool) (*Polecat, error) {
-	return m.RepairWorktreeWithOptions(name, force, AddOptions{})
+	return m.RepairWorktreeWithOptions(name, force, AddOptions{RestoreSnapshot: true})
 }
 
 // RepairWorktreeWithOptions repairs a stale polecat and creates a fresh worktree with options.
@@ -554,6 +615,11 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 		}
 	}
 
+	// Preserve any uncommitted work as a checkpoint snapshot before the old
+	// worktree is removed. Snapshot refs live in the shared repo, so they
+	// outlive the worktree.
+	snap := m.snapshotWork(name, oldClonePath)
+
 	// Close old agent bead before recreation (non-fatal)
 	// NOTE: We use CloseAndClearAgentBead instead of DeleteAgentBead because bd delete --hard
 	// creates tombstones that cannot be reopened.
@@ -585,23 +651,12 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 
 	// Determine the start point for the new worktree
 	// Use origin/<default-branch> to ensure we start from latest fetched commits
-	defaultBranch := "main"
-	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
-		defaultBranch = rigCfg.DefaultBranch
-	}
-	startPoint := fmt.Sprintf("origin/%s", defaultBranch)
+	startPoint := fmt.Sprintf("origin/%s", m.defaultBranch())
 
 	// Create fresh worktree with unique branch name, starting from origin's default branch
 	// Old branches are left behind - they're ephemeral (never pushed to origin)
 	// and will be cleaned up by garbage collection
-	// Branch naming: include issue ID when available for better traceability.
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 36)
-	var branchName string
-	if opts.HookBead != "" {
-		branchName = fmt.Sprintf("polecat/%s/%s@%s", name, opts.HookBead, timestamp)
-	} else {
-		branchName = fmt.Sprintf("polecat/%s-%s", name, timestamp)
-	}
+	branchName := polecatBranchName(name, opts.HookBead)
 	if err := repoGit.WorktreeAddFromRef(newClonePath, branchName, startPoint); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
@@ -621,6 +676,14 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	// NOTE: We intentionally do NOT write to CLAUDE.md here.
 	// Gas Town context is injected ephemerally via SessionStart hook (gt prime).
 
+	// Reapply preserved work if requested (non-fatal: the ref remains for manual restore).
+	// Done before beads and overlay setup so stale copies in the snapshot don't win.
+	if snap != nil && opts.RestoreSnapshot {
+		if err := checkpoint.Restore(newClonePath, snap.Ref); err != nil {
+			fmt.Printf("Warning: could not restore snapshot %s: %v\n", snap.Ref, err)
+		}
+	}
+
 	// Set up shared beads
 	if err := m.setupSharedBeads(newClonePath); err != nil {
 		fmt.Printf("Warning: could not set up shared beads: %v\n", err)
@@ -680,11 +743,11 @@ func (m *Manager) ReconcilePool() {
 
 	// Get names with tmux sessions
 	var namesWithSessions []string
-	if m.tmux != nil {
+	if m.sessions != nil {
 		poolNames := m.namePool.getNames()
 		for _, name := range poolNames {
 			sessionName := fmt.Sprintf("gt-%s-%s", m.rig.Name, name)
-			hasSession, _ := m.tmux.HasSession(sessionName)
+			hasSession, _ := m.sessions.HasSession(sessionName)
 			if hasSession {
 				namesWithSessions = append(namesWithSessions, name)
 			}
@@ -714,11 +777,11 @@ func (m *Manager) ReconcilePoolWith(namesWithDirs, namesWithSessions []string) {
 	}
 
 	// Kill orphaned sessions (session exists but no directory)
-	if m.tmux != nil {
+	if m.sessions != nil {
 		for _, name := range namesWithSessions {
 			if !dirSet[name] {
 				sessionName := fmt.Sprintf("gt-%s-%s", m.rig.Name, name)
-				_ = m.tmux.KillSession(sessionName)
+				_ = m.sessions.KillSession(sessionName)
 			}
 		}
 	}
@@ -964,6 +1027,12 @@ func (m *Manager) CleanupStaleBranches() (int, error) {
 	for _, p := range polecats {
 		currentBranches[p.Branch] = true
 	}
+	// Warm pool worktrees are idle, not orphaned
+	if slots, err := m.ListWarm(); err == nil {
+		for _, slot := range slots {
+			currentBranches["polecat/"+slot.Name] = true
+		}
+	}
 
 	// Delete branches not in current set
 	deleted := 0
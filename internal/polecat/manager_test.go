@@ -649,3 +649,67 @@ func TestReconcilePoolWith_OrphanDoesNotBlockAllocation(t *testing.T) {
 		t.Errorf("expected furiosa (orphan freed), got %q", name)
 	}
 }
+
+func TestRepairWorktreeRestoresUncommittedWork(t *testing.T) {
+	m, _ := setupWarmPoolRig(t, 0)
+
+	p, err := m.Add("Toast")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(p.ClonePath, "README.md"), []byte("v1\nwip edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(p.ClonePath, "notes.txt"), []byte("untracked wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repaired, err := m.RepairWorktree("Toast", true)
+	if err != nil {
+		t.Fatalf("RepairWorktree: %v", err)
+	}
+	if repaired.Branch == p.Branch {
+		t.Errorf("repair reused branch %s, want a fresh one", p.Branch)
+	}
+
+	for file, want := range map[string]string{"README.md": "v1\nwip edit\n", "notes.txt": "untracked wip\n"} {
+		got, err := os.ReadFile(filepath.Join(repaired.ClonePath, file))
+		if err != nil || string(got) != want {
+			t.Errorf("%s after repair = %q, %v; want %q", file, got, err, want)
+		}
+	}
+}
+
+func TestRepairWorktreeOverlayWinsOverSnapshot(t *testing.T) {
+	m, _ := setupWarmPoolRig(t, 0)
+	overlayDir := filepath.Join(m.rig.Path, ".runtime", "overlay")
+	if err := os.MkdirAll(overlayDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(overlayDir, ".env"), []byte("TOKEN=old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := m.Add("Toast")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(p.ClonePath, "notes.txt"), []byte("untracked wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The overlay changes while the old worktree still has its copy
+	if err := os.WriteFile(filepath.Join(overlayDir, ".env"), []byte("TOKEN=new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repaired, err := m.RepairWorktree("Toast", true)
+	if err != nil {
+		t.Fatalf("RepairWorktree: %v", err)
+	}
+	for file, want := range map[string]string{".env": "TOKEN=new\n", "notes.txt": "untracked wip\n"} {
+		got, err := os.ReadFile(filepath.Join(repaired.ClonePath, file))
+		if err != nil || string(got) != want {
+			t.Errorf("%s after repair = %q, %v; want %q", file, got, err, want)
+		}
+	}
+}
@@ -70,6 +70,9 @@ var BuiltinThemes = map[string][]string{
 //
 // Names are drawn from a themed pool (mad-max by default).
 // When the pool is exhausted, overflow names use rigname-N format.
+//
+// The warm pool (see warmpool.go) holds pre-provisioned worktrees, not
+// polecats: a warm worktree gets its name from this pool when it is claimed.
 type NamePool struct {
 	mu sync.RWMutex
 
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -190,3 +191,143 @@ func TestPolecatCommandFormat(t *testing.T) {
 		t.Error("GT_ROLE must be 'polecat', not 'mayor' or 'crew'")
 	}
 }
+
+// fakeBackend is an in-memory tmux.SessionBackend for exercising session
+// logic without a tmux server.
+type fakeBackend struct {
+	sessions map[string][]string // session -> keys received
+	killed   []string
+	raw      []string // session:key for each SendKeysRaw
+}
+
+func newFakeBackend(names ...string) *fakeBackend {
+	f := &fakeBackend{sessions: make(map[string][]string)}
+	for _, name := range names {
+		f.sessions[name] = nil
+	}
+	return f
+}
+
+func (f *fakeBackend) NewSessionWithCommand(name, _, command string) error {
+	if _, ok := f.sessions[name]; ok {
+		return tmux.ErrSessionExists
+	}
+	f.sessions[name] = []string{command}
+	return nil
+}
+
+func (f *fakeBackend) KillSession(name string) error {
+	if _, ok := f.sessions[name]; !ok {
+		return tmux.ErrSessionNotFound
+	}
+	delete(f.sessions, name)
+	f.killed = append(f.killed, name)
+	return nil
+}
+
+func (f *fakeBackend) HasSession(name string) (bool, error) {
+	_, ok := f.sessions[name]
+	return ok, nil
+}
+
+func (f *fakeBackend) ListSessions() ([]string, error) {
+	var names []string
+	for name := range f.sessions {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f *fakeBackend) SendKeys(session, keys string) error {
+	if _, ok := f.sessions[session]; !ok {
+		return tmux.ErrSessionNotFound
+	}
+	f.sessions[session] = append(f.sessions[session], keys)
+	return nil
+}
+
+func (f *fakeBackend) SendKeysRaw(session, keys string) error {
+	if _, ok := f.sessions[session]; !ok {
+		return tmux.ErrSessionNotFound
+	}
+	f.raw = append(f.raw, session+":"+keys)
+	return nil
+}
+
+func (f *fakeBackend) NudgeSession(session, message string) error {
+	return f.SendKeys(session, message)
+}
+
+func (f *fakeBackend) CapturePane(session string, _ int) (string, error) {
+	keys, ok := f.sessions[session]
+	if !ok {
+		return "", tmux.ErrSessionNotFound
+	}
+	return strings.Join(keys, "\n"), nil
+}
+
+func (f *fakeBackend) GetPanePID(string) (string, error)           { return "1", nil }
+func (f *fakeBackend) SetEnvironment(string, string, string) error { return nil }
+func (f *fakeBackend) AttachSession(string) error                  { return nil }
+func (f *fakeBackend) AcceptBypassPermissionsWarning(string) error { return nil }
+
+func (f *fakeBackend) WaitForCommand(string, []string, time.Duration) error { return nil }
+
+func TestSessionManagerWithFakeBackend(t *testing.T) {
+	r := &rig.Rig{
+		Name:     "gastown",
+		Path:     t.TempDir(),
+		Polecats: []string{"Toast", "Cheedo"},
+	}
+	fake := newFakeBackend("gt-gastown-Toast", "gt-gastown-Cheedo", "gt-other-Nux")
+	m := NewSessionManager(fake, r)
+
+	infos, err := m.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Errorf("List returned %d sessions, want 2 (other rigs excluded)", len(infos))
+	}
+
+	if err := m.Inject("Toast", "check your hook"); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	out, err := m.Capture("Toast", 10)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if !strings.Contains(out, "check your hook") {
+		t.Errorf("Capture = %q, want injected message", out)
+	}
+
+	if err := m.Stop("Cheedo", true); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if running, _ := m.IsRunning("Cheedo"); running {
+		t.Error("Cheedo still running after Stop")
+	}
+	if len(fake.killed) != 1 || fake.killed[0] != "gt-gastown-Cheedo" {
+		t.Errorf("killed = %v, want [gt-gastown-Cheedo]", fake.killed)
+	}
+	if len(fake.raw) != 0 {
+		t.Errorf("forced Stop sent %v, want no interrupt", fake.raw)
+	}
+
+	// Status degrades gracefully without tmux session metadata
+	info, err := m.Status("Toast")
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !info.Running || info.Windows != 0 {
+		t.Errorf("Status = %+v, want running with no tmux metadata", info)
+	}
+
+	// A graceful stop interrupts the agent before killing the session
+	if err := m.Stop("Toast", false); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if len(fake.raw) != 1 || fake.raw[0] != "gt-gastown-Toast:C-c" {
+		t.Errorf("graceful Stop sent %v, want [gt-gastown-Toast:C-c]", fake.raw)
+	}
+}
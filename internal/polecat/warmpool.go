@@ -0,0 +1,429 @@
+package polecat
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Warm pool: idle, fully provisioned worktrees that gt sling claims instead
+// of creating a worktree and running setup hooks at sling time.
+//
+// Slots live in polecats/.warm/<slot>/<rigname>/, hidden from List by the
+// dot prefix. A slot can be claimed once its .ready marker exists. Claiming
+// renames the slot directory to polecats/<name>/ under the pool lock, so a
+// slot goes to exactly one polecat. Warm slots have no agent bead; it is
+// created with the hook bead when the slot is claimed.
+const (
+	warmDirName    = ".warm"
+	warmReadyFile  = ".ready"
+	warmLockFile   = ".lock"
+	warmRefillLock = ".refill.lock"
+
+	// warmStalePrefix marks a slot replaced by a refresh while it is deleted
+	warmStalePrefix = ".stale-"
+)
+
+// ErrNoWarmPolecat is returned by ClaimWarm when no warm worktree is ready.
+var ErrNoWarmPolecat = errors.New("no warm polecat available")
+
+// WarmSlot is a pre-provisioned worktree in the warm pool.
+type WarmSlot struct {
+	Name      string    `json:"name"`
+	ClonePath string    `json:"clone_path"`
+	Branch    string    `json:"branch,omitempty"`
+	Commit    string    `json:"commit,omitempty"`
+	Ready     bool      `json:"ready"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// warmDir returns the directory holding the warm pool slots.
+func (m *Manager) warmDir() string {
+	return filepath.Join(m.rig.Path, "polecats", warmDirName)
+}
+
+// WarmPoolSize returns the configured warm pool size (0 when disabled).
+func (m *Manager) WarmPoolSize() int {
+	settings, err := config.LoadRigSettings(filepath.Join(m.rig.Path, "settings", "config.json"))
+	if err != nil || settings.WarmPool == nil {
+		return 0
+	}
+	return settings.WarmPool.Size
+}
+
+// ListWarm returns the warm pool slots, oldest first.
+func (m *Manager) ListWarm() ([]WarmSlot, error) {
+	entries, err := os.ReadDir(m.warmDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading warm pool: %w", err)
+	}
+
+	var slots []WarmSlot
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		slotDir := filepath.Join(m.warmDir(), entry.Name())
+		slot := WarmSlot{
+			Name:      entry.Name(),
+			ClonePath: filepath.Join(slotDir, m.rig.Name),
+		}
+		if info, err := os.Stat(filepath.Join(slotDir, warmReadyFile)); err == nil {
+			slot.Ready = true
+			slot.CreatedAt = info.ModTime()
+		} else if info, err := entry.Info(); err == nil {
+			slot.CreatedAt = info.ModTime()
+		}
+		if _, err := os.Stat(slot.ClonePath); err == nil {
+			g := git.NewGit(slot.ClonePath)
+			slot.Branch, _ = g.CurrentBranch()
+			slot.Commit, _ = g.Rev("HEAD")
+		}
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Name < slots[j].Name })
+	return slots, nil
+}
+
+// addWarm provisions one warm worktree: the same worktree, overlay and setup
+// hooks as a new polecat, on a placeholder branch and without an agent bead.
+// Callers hold the refill lock.
+func (m *Manager) addWarm() (*WarmSlot, error) {
+	name := "warm-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	slotDir := filepath.Join(m.warmDir(), name)
+	clonePath := filepath.Join(slotDir, m.rig.Name)
+	branchName := "polecat/" + name
+
+	if err := os.MkdirAll(slotDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating warm slot: %w", err)
+	}
+	if err := m.provisionWorktree(clonePath, branchName); err != nil {
+		m.removeWarmSlot(name)
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(slotDir, warmReadyFile), nil, 0644); err != nil {
+		m.removeWarmSlot(name)
+		return nil, fmt.Errorf("marking warm slot ready: %w", err)
+	}
+
+	return &WarmSlot{Name: name, ClonePath: clonePath, Branch: branchName, Ready: true, CreatedAt: time.Now()}, nil
+}
+
+// lockWarmPool takes the pool lock, which serializes claims with refreshes
+// and trims. Blocks until the lock is free.
+func (m *Manager) lockWarmPool() (*flock.Flock, error) {
+	if err := os.MkdirAll(m.warmDir(), 0755); err != nil {
+		return nil, fmt.Errorf("creating warm pool dir: %w", err)
+	}
+	lock := flock.New(filepath.Join(m.warmDir(), warmLockFile))
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking warm pool: %w", err)
+	}
+	return lock, nil
+}
+
+// ClaimWarm turns a warm worktree into polecat name with a fresh branch and
+// an agent bead carrying opts.HookBead. The slot is taken by renaming it
+// into place under the pool lock, so concurrent slings never share one.
+// Returns ErrNoWarmPolecat if the pool has no ready slot.
+func (m *Manager) ClaimWarm(name string, opts AddOptions) (*Polecat, error) {
+	if m.exists(name) {
+		return nil, ErrPolecatExists
+	}
+	if _, err := os.Stat(m.warmDir()); os.IsNotExist(err) {
+		return nil, ErrNoWarmPolecat
+	}
+
+	lock, err := m.lockWarmPool()
+	if err != nil {
+		return nil, err
+	}
+	slots, err := m.ListWarm()
+	if err != nil {
+		_ = lock.Unlock()
+		return nil, err
+	}
+	claimed := ""
+	for _, slot := range slots {
+		if !slot.Ready {
+			continue
+		}
+		if err := os.Rename(filepath.Join(m.warmDir(), slot.Name), m.polecatDir(name)); err == nil {
+			claimed = slot.Name
+			break
+		}
+	}
+	_ = lock.Unlock()
+	if claimed == "" {
+		return nil, ErrNoWarmPolecat
+	}
+
+	polecatDir := m.polecatDir(name)
+	clonePath := filepath.Join(polecatDir, m.rig.Name)
+	_ = os.Remove(filepath.Join(polecatDir, warmReadyFile))
+
+	branchName := polecatBranchName(name, opts.HookBead)
+	if err := m.adoptWarmWorktree(clonePath, branchName); err != nil {
+		// Don't leave a half-claimed polecat behind; the caller falls back
+		// to creating one from scratch.
+		if repoGit, baseErr := m.repoBase(); baseErr == nil {
+			_ = repoGit.WorktreeRemove(clonePath, true)
+			_ = repoGit.WorktreePrune()
+			_ = repoGit.DeleteBranch("polecat/"+claimed, true)
+		}
+		_ = os.RemoveAll(polecatDir)
+		return nil, fmt.Errorf("claiming warm worktree %s: %w", claimed, err)
+	}
+
+	m.createAgentBead(name, opts.HookBead)
+
+	now := time.Now()
+	return &Polecat{
+		Name:      name,
+		Rig:       m.rig.Name,
+		State:     StateWorking,
+		ClonePath: clonePath,
+		Branch:    branchName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// adoptWarmWorktree fixes up a warm worktree that was moved to clonePath:
+// git's worktree links are repaired, the placeholder branch is renamed and
+// the beads redirect is rewritten. Slots sit one level deeper than polecats
+// (.warm/<slot>/), so the redirect written at provisioning would climb past
+// the rig to the town beads.
+func (m *Manager) adoptWarmWorktree(clonePath, branchName string) error {
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return fmt.Errorf("finding repo base: %w", err)
+	}
+	if err := repoGit.WorktreeRepair(clonePath); err != nil {
+		return fmt.Errorf("repairing worktree: %w", err)
+	}
+	if err := git.NewGit(clonePath).RenameBranch(branchName); err != nil {
+		return fmt.Errorf("renaming branch: %w", err)
+	}
+	if err := m.setupSharedBeads(clonePath); err != nil {
+		// Same as a fresh polecat: work on with local beads, but never
+		// through the stale redirect
+		_ = os.Remove(filepath.Join(clonePath, ".beads", "redirect"))
+		fmt.Printf("Warning: could not set up shared beads: %v\n", err)
+	}
+	return nil
+}
+
+// RefillWarmPool provisions warm worktrees until the pool reaches its
+// configured size, trims ready slots beyond it, and removes slots whose
+// provisioning was interrupted. Only one refill runs at a time per rig; if
+// another is in progress this returns immediately. Returns the number of
+// slots added.
+func (m *Manager) RefillWarmPool() (int, error) {
+	size := m.WarmPoolSize()
+	if _, err := os.Stat(m.warmDir()); size == 0 && os.IsNotExist(err) {
+		return 0, nil // pool disabled and never used
+	}
+	if err := os.MkdirAll(m.warmDir(), 0755); err != nil {
+		return 0, fmt.Errorf("creating warm pool dir: %w", err)
+	}
+	refill := flock.New(filepath.Join(m.warmDir(), warmRefillLock))
+	locked, err := refill.TryLock()
+	if err != nil {
+		return 0, fmt.Errorf("locking warm pool refill: %w", err)
+	}
+	if !locked {
+		return 0, nil // another refill is running
+	}
+	defer func() { _ = refill.Unlock() }()
+
+	slots, err := m.ListWarm()
+	if err != nil {
+		return 0, err
+	}
+	// Only refills and refreshes provision, so with the refill lock held an
+	// unready slot is left over from one that was interrupted. So is a
+	// retired slot that was never deleted.
+	if entries, err := os.ReadDir(m.warmDir()); err == nil {
+		for _, entry := range entries {
+			if name, ok := strings.CutPrefix(entry.Name(), warmStalePrefix); ok && entry.IsDir() {
+				m.removeStaleWarmDir(filepath.Join(m.warmDir(), entry.Name()), name)
+			}
+		}
+	}
+	var ready []WarmSlot
+	for _, slot := range slots {
+		if slot.Ready {
+			ready = append(ready, slot)
+		} else {
+			m.removeWarmSlot(slot.Name)
+		}
+	}
+
+	if len(ready) > size {
+		if err := m.trimWarmPool(size); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	added := 0
+	for i := len(ready); i < size; i++ {
+		if _, err := m.addWarm(); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+// trimWarmPool removes the oldest ready slots until at most size remain.
+func (m *Manager) trimWarmPool(size int) error {
+	lock, err := m.lockWarmPool()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	slots, err := m.ListWarm()
+	if err != nil {
+		return err
+	}
+	var ready []WarmSlot
+	for _, slot := range slots {
+		if slot.Ready {
+			ready = append(ready, slot)
+		}
+	}
+	for i := 0; i < len(ready)-size; i++ {
+		m.removeWarmSlot(ready[i].Name)
+	}
+	return nil
+}
+
+// RefreshWarmPool replaces every ready warm worktree that is behind the
+// rig's default branch with a freshly provisioned one (worktree, beads,
+// PRIME.md, overlay and setup hooks), so claimed polecats start from current
+// code with matching dependencies. Provisioning runs outside the pool lock,
+// which is taken only to swap the stale slot out; slings claim the stale
+// slot meanwhile rather than wait. Shares the refill lock, so it returns
+// immediately while a refill is running. Returns the number of slots
+// refreshed.
+func (m *Manager) RefreshWarmPool() (int, error) {
+	if _, err := os.Stat(m.warmDir()); os.IsNotExist(err) {
+		return 0, nil
+	}
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return 0, fmt.Errorf("finding repo base: %w", err)
+	}
+	if err := repoGit.Fetch("origin"); err != nil {
+		return 0, fmt.Errorf("fetching origin: %w", err)
+	}
+	startPoint := "origin/" + m.defaultBranch()
+	target, err := repoGit.Rev(startPoint)
+	if err != nil {
+		return 0, fmt.Errorf("resolving %s: %w", startPoint, err)
+	}
+
+	refill := flock.New(filepath.Join(m.warmDir(), warmRefillLock))
+	locked, err := refill.TryLock()
+	if err != nil {
+		return 0, fmt.Errorf("locking warm pool refill: %w", err)
+	}
+	if !locked {
+		return 0, nil // a refill is provisioning; refresh next time
+	}
+	defer func() { _ = refill.Unlock() }()
+
+	slots, err := m.ListWarm()
+	if err != nil {
+		return 0, err
+	}
+	refreshed := 0
+	for _, slot := range slots {
+		if !slot.Ready || slot.Commit == target {
+			continue
+		}
+		if _, err := m.addWarm(); err != nil {
+			return refreshed, err
+		}
+		if err := m.removeUnclaimedWarmSlot(slot.Name); err != nil {
+			return refreshed, err
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// removeUnclaimedWarmSlot removes a warm slot unless a sling claimed it
+// first. The pool lock is held only to move the slot out of reach; the
+// worktree is deleted after it is released.
+func (m *Manager) removeUnclaimedWarmSlot(name string) error {
+	lock, err := m.lockWarmPool()
+	if err != nil {
+		return err
+	}
+	stale := filepath.Join(m.warmDir(), warmStalePrefix+name)
+	err = os.Rename(filepath.Join(m.warmDir(), name), stale)
+	_ = lock.Unlock()
+	if os.IsNotExist(err) {
+		return nil // claimed
+	}
+	if err != nil {
+		return fmt.Errorf("retiring warm slot %s: %w", name, err)
+	}
+	m.removeStaleWarmDir(stale, name)
+	return nil
+}
+
+// removeStaleWarmDir deletes a retired slot directory and its branch.
+func (m *Manager) removeStaleWarmDir(dir, name string) {
+	_ = os.RemoveAll(dir)
+	if repoGit, err := m.repoBase(); err == nil {
+		_ = repoGit.WorktreePrune()
+		_ = repoGit.DeleteBranch("polecat/"+name, true)
+	}
+}
+
+// DrainWarmPool removes every warm worktree. Returns the number removed.
+func (m *Manager) DrainWarmPool() (int, error) {
+	lock, err := m.lockWarmPool()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	slots, err := m.ListWarm()
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		m.removeWarmSlot(slot.Name)
+	}
+	return len(slots), nil
+}
+
+// removeWarmSlot deletes a warm slot's worktree, directory and branch.
+// Best-effort: leftovers are pruned by later refills and branch cleanup.
+func (m *Manager) removeWarmSlot(name string) {
+	slotDir := filepath.Join(m.warmDir(), name)
+	if repoGit, err := m.repoBase(); err == nil {
+		_ = repoGit.WorktreeRemove(filepath.Join(slotDir, m.rig.Name), true)
+		_ = repoGit.WorktreePrune()
+		_ = repoGit.DeleteBranch("polecat/"+name, true)
+	}
+	_ = os.RemoveAll(slotDir)
+}
@@ -0,0 +1,275 @@
+package polecat
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// setupWarmPoolRig creates a rig whose mayor/rig repo is its own origin, with
+// a warm pool of the given size.
+func setupWarmPoolRig(t *testing.T, size int) (*Manager, string) {
+	t.Helper()
+	root := t.TempDir()
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatalf("mkdir mayor/rig: %v", err)
+	}
+	runGit(t, mayorRig, "init", "-b", "main")
+	runGit(t, mayorRig, "config", "user.email", "test@test.com")
+	runGit(t, mayorRig, "config", "user.name", "Test")
+	if err := os.WriteFile(filepath.Join(mayorRig, "README.md"), []byte("v1\n"), 0644); err != nil {
+		t.Fatalf("write README: %v", err)
+	}
+	runGit(t, mayorRig, "add", "README.md")
+	runGit(t, mayorRig, "commit", "-m", "v1")
+	runGit(t, mayorRig, "remote", "add", "origin", mayorRig)
+	runGit(t, mayorRig, "update-ref", "refs/remotes/origin/main", "HEAD")
+
+	settings := filepath.Join(root, "settings", "config.json")
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatalf("mkdir settings: %v", err)
+	}
+	data := fmt.Sprintf(`{"type": "rig-settings", "version": 1, "warm_pool": {"size": %d}}`, size)
+	if err := os.WriteFile(settings, []byte(data), 0644); err != nil {
+		t.Fatalf("write settings: %v", err)
+	}
+
+	return NewManager(&rig.Rig{Name: "rig", Path: root}, git.NewGit(root), nil), mayorRig
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func readyWarm(t *testing.T, m *Manager) []WarmSlot {
+	t.Helper()
+	slots, err := m.ListWarm()
+	if err != nil {
+		t.Fatalf("ListWarm: %v", err)
+	}
+	var ready []WarmSlot
+	for _, s := range slots {
+		if s.Ready {
+			ready = append(ready, s)
+		}
+	}
+	return ready
+}
+
+func TestWarmPool_RefillAndClaim(t *testing.T) {
+	m, _ := setupWarmPoolRig(t, 2)
+
+	added, err := m.RefillWarmPool()
+	if err != nil {
+		t.Fatalf("RefillWarmPool: %v", err)
+	}
+	if added != 2 || len(readyWarm(t, m)) != 2 {
+		t.Fatalf("added %d, ready %d, want 2", added, len(readyWarm(t, m)))
+	}
+	if polecats, _ := m.List(); len(polecats) != 0 {
+		t.Errorf("warm slots should not be listed as polecats: %v", polecats)
+	}
+
+	p, err := m.ClaimWarm("toast", AddOptions{HookBead: "gt-abc"})
+	if err != nil {
+		t.Fatalf("ClaimWarm: %v", err)
+	}
+	if p.ClonePath != filepath.Join(m.rig.Path, "polecats", "toast", "rig") {
+		t.Errorf("ClonePath = %s", p.ClonePath)
+	}
+	if _, err := os.Stat(filepath.Join(p.ClonePath, "README.md")); err != nil {
+		t.Errorf("claimed worktree missing checkout: %v", err)
+	}
+	branch, err := git.NewGit(p.ClonePath).CurrentBranch()
+	if err != nil || branch != p.Branch || !strings.HasPrefix(branch, "polecat/toast/gt-abc@") {
+		t.Errorf("branch = %q (%v), want renamed to %s", branch, err, p.Branch)
+	}
+	if _, err := os.Stat(filepath.Join(m.rig.Path, "polecats", "toast", warmReadyFile)); !os.IsNotExist(err) {
+		t.Error("ready marker should be removed from the claimed polecat")
+	}
+	if got := len(readyWarm(t, m)); got != 1 {
+		t.Errorf("ready slots after claim = %d, want 1", got)
+	}
+
+	if _, err := m.ClaimWarm("toast", AddOptions{}); !errors.Is(err, ErrPolecatExists) {
+		t.Errorf("claiming an existing name: %v, want ErrPolecatExists", err)
+	}
+	if _, err := m.ClaimWarm("nux", AddOptions{}); err != nil {
+		t.Fatalf("second ClaimWarm: %v", err)
+	}
+	if _, err := m.ClaimWarm("slit", AddOptions{}); !errors.Is(err, ErrNoWarmPolecat) {
+		t.Errorf("claim from empty pool: %v, want ErrNoWarmPolecat", err)
+	}
+}
+
+func TestWarmPool_RefreshResetsBehindSlots(t *testing.T) {
+	m, mayorRig := setupWarmPoolRig(t, 1)
+
+	// A setup hook standing in for dependency install: it records the
+	// README it set up against, which must follow the refreshed code.
+	hooksDir := filepath.Join(m.rig.Path, ".runtime", "setup-hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatalf("mkdir setup-hooks: %v", err)
+	}
+	hook := "#!/bin/sh\ncp README.md .deps-installed-for\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, "10-deps.sh"), []byte(hook), 0755); err != nil {
+		t.Fatalf("write setup hook: %v", err)
+	}
+
+	if _, err := m.RefillWarmPool(); err != nil {
+		t.Fatalf("RefillWarmPool: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(mayorRig, "README.md"), []byte("v2\n"), 0644); err != nil {
+		t.Fatalf("write README: %v", err)
+	}
+	runGit(t, mayorRig, "commit", "-am", "v2")
+	runGit(t, mayorRig, "update-ref", "refs/remotes/origin/main", "HEAD")
+
+	refreshed, err := m.RefreshWarmPool()
+	if err != nil {
+		t.Fatalf("RefreshWarmPool: %v", err)
+	}
+	if refreshed != 1 {
+		t.Errorf("refreshed = %d, want 1", refreshed)
+	}
+	slot := readyWarm(t, m)[0]
+	data, _ := os.ReadFile(filepath.Join(slot.ClonePath, "README.md"))
+	if string(data) != "v2\n" {
+		t.Errorf("warm worktree README = %q, want v2", data)
+	}
+	if deps, _ := os.ReadFile(filepath.Join(slot.ClonePath, ".deps-installed-for")); string(deps) != "v2\n" {
+		t.Errorf("setup hooks ran against %q, want them rerun after the refresh (v2)", deps)
+	}
+
+	if refreshed, _ := m.RefreshWarmPool(); refreshed != 0 {
+		t.Errorf("second refresh reset %d slots, want 0", refreshed)
+	}
+}
+
+func TestWarmPool_ClaimedPolecatUsesRigBeads(t *testing.T) {
+	m, _ := setupWarmPoolRig(t, 1)
+	rigBeads := filepath.Join(m.rig.Path, ".beads")
+	if err := os.MkdirAll(rigBeads, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.RefillWarmPool(); err != nil {
+		t.Fatalf("RefillWarmPool: %v", err)
+	}
+
+	p, err := m.ClaimWarm("toast", AddOptions{})
+	if err != nil {
+		t.Fatalf("ClaimWarm: %v", err)
+	}
+	if got := beads.ResolveBeadsDir(p.ClonePath); got != rigBeads {
+		t.Errorf("claimed polecat resolves beads to %s, want the rig's %s", got, rigBeads)
+	}
+}
+
+func TestWarmPool_RefreshProvisionsOutsidePoolLock(t *testing.T) {
+	if _, err := exec.LookPath("flock"); err != nil {
+		t.Skip("flock(1) not available")
+	}
+	m, mayorRig := setupWarmPoolRig(t, 1)
+	if _, err := m.RefillWarmPool(); err != nil {
+		t.Fatalf("RefillWarmPool: %v", err)
+	}
+
+	// The setup hook checks whether a sling could take the pool lock while
+	// the refresh provisions the replacement slot
+	hooksDir := filepath.Join(m.rig.Path, ".runtime", "setup-hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatalf("mkdir setup-hooks: %v", err)
+	}
+	probe := filepath.Join(m.rig.Path, "lock-probe")
+	hook := fmt.Sprintf("#!/bin/sh\nflock -n %q true && echo free > %q || echo held > %q\n",
+		filepath.Join(m.warmDir(), warmLockFile), probe, probe)
+	if err := os.WriteFile(filepath.Join(hooksDir, "10-probe.sh"), []byte(hook), 0755); err != nil {
+		t.Fatalf("write setup hook: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(mayorRig, "README.md"), []byte("v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, mayorRig, "commit", "-am", "v2")
+	runGit(t, mayorRig, "update-ref", "refs/remotes/origin/main", "HEAD")
+
+	if refreshed, err := m.RefreshWarmPool(); err != nil || refreshed != 1 {
+		t.Fatalf("RefreshWarmPool = %d, %v; want 1", refreshed, err)
+	}
+	if data, _ := os.ReadFile(probe); string(data) != "free\n" {
+		t.Errorf("pool lock during provisioning: %q, want free", data)
+	}
+	if slots, _ := m.ListWarm(); len(slots) != 1 || !slots[0].Ready {
+		t.Errorf("slots after refresh: %+v", slots)
+	}
+	if entries, _ := os.ReadDir(m.warmDir()); len(entries) != 3 { // slot, .lock, .refill.lock
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("warm dir = %v, want the stale slot gone", names)
+	}
+}
+
+func TestWarmPool_TrimDrainAndLeftovers(t *testing.T) {
+	m, _ := setupWarmPoolRig(t, 2)
+	if _, err := m.RefillWarmPool(); err != nil {
+		t.Fatalf("RefillWarmPool: %v", err)
+	}
+
+	// An interrupted provision leaves a slot without the ready marker
+	if err := os.MkdirAll(filepath.Join(m.warmDir(), "warm-interrupted", "rig"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := filepath.Join(m.rig.Path, "settings", "config.json")
+	if err := os.WriteFile(settings, []byte(`{"type": "rig-settings", "version": 1, "warm_pool": {"size": 1}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.RefillWarmPool(); err != nil {
+		t.Fatalf("RefillWarmPool: %v", err)
+	}
+	slots, _ := m.ListWarm()
+	if len(slots) != 1 || !slots[0].Ready {
+		t.Errorf("after shrinking to 1: %+v", slots)
+	}
+
+	removed, err := m.DrainWarmPool()
+	if err != nil || removed != 1 {
+		t.Errorf("DrainWarmPool = %d, %v; want 1", removed, err)
+	}
+	if slots, _ := m.ListWarm(); len(slots) != 0 {
+		t.Errorf("slots after drain: %+v", slots)
+	}
+}
+
+func TestWarmPool_DisabledIsNoop(t *testing.T) {
+	m, _ := setupWarmPoolRig(t, 0)
+
+	if added, err := m.RefillWarmPool(); err != nil || added != 0 {
+		t.Errorf("RefillWarmPool = %d, %v", added, err)
+	}
+	if _, err := os.Stat(m.warmDir()); !os.IsNotExist(err) {
+		t.Error("a disabled pool should not create the warm dir")
+	}
+	if _, err := m.ClaimWarm("toast", AddOptions{}); !errors.Is(err, ErrNoWarmPolecat) {
+		t.Errorf("ClaimWarm = %v, want ErrNoWarmPolecat", err)
+	}
+	if _, err := os.Stat(m.warmDir()); !os.IsNotExist(err) {
+		t.Error("claiming from a disabled pool should not create the warm dir")
+	}
+}
@@ -0,0 +1,22 @@
+package ptyd
+
+import (
+	"os"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// BackendEnv selects the session backend: "tmux" (default) or "pty" for the
+// headless supervisor.
+const BackendEnv = "GT_SESSION_BACKEND"
+
+// BackendPTY is the BackendEnv value that selects the PTY supervisor.
+const BackendPTY = "pty"
+
+// NewBackend returns the session backend selected by GT_SESSION_BACKEND.
+func NewBackend() tmux.SessionBackend {
+	if os.Getenv(BackendEnv) == BackendPTY {
+		return NewClient(DefaultSocketPath())
+	}
+	return tmux.NewTmux()
+}
@@ -0,0 +1,359 @@
+package ptyd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// SocketEnv overrides the supervisor socket path.
+const SocketEnv = "GT_PTYD_SOCKET"
+
+// DetachKey is the byte that ends an attach session (Ctrl-]).
+const DetachKey = 0x1d
+
+// ErrNoServer is returned when no supervisor is listening on the socket.
+var ErrNoServer = errors.New("ptyd not running (start it with 'gt ptyd start')")
+
+// nudgeLocks serializes nudges to the same session, mirroring tmux.
+var nudgeLocks sync.Map // map[string]*sync.Mutex
+
+// DefaultSocketPath returns the supervisor socket path: $GT_PTYD_SOCKET if
+// set, otherwise a per-user socket in the temp directory (like tmux's
+// default server socket).
+func DefaultSocketPath() string {
+	if p := os.Getenv(SocketEnv); p != "" {
+		return p
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gt-ptyd-%d.sock", os.Getuid()))
+}
+
+// Client talks to a ptyd supervisor and implements tmux.SessionBackend.
+type Client struct {
+	socketPath string
+	timeout    time.Duration
+}
+
+// Compile-time check that Client satisfies tmux.SessionBackend.
+var _ tmux.SessionBackend = (*Client)(nil)
+
+// NewClient creates a client for the supervisor at socketPath.
+func NewClient(socketPath string) *Client {
+	return &Client{
+		socketPath: socketPath,
+		timeout:    10 * time.Second,
+	}
+}
+
+// dial connects to the supervisor socket.
+func (c *Client) dial() (net.Conn, error) {
+	conn, err := net.DialTimeout("unix", c.socketPath, c.timeout)
+	if err != nil {
+		return nil, ErrNoServer
+	}
+	return conn, nil
+}
+
+// call sends a request and decodes the response, mapping error codes back
+// to the tmux sentinel errors.
+func (c *Client) call(req request) (response, error) {
+	conn, err := c.dial()
+	if err != nil {
+		return response{}, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(c.timeout))
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return response{}, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return response{}, fmt.Errorf("ptyd %s: %w", req.Op, err)
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return response{}, fmt.Errorf("ptyd %s: reading response: %w", req.Op, err)
+	}
+	if !resp.OK {
+		return resp, responseError(req.Op, resp)
+	}
+	return resp, nil
+}
+
+func responseError(op string, resp response) error {
+	switch resp.Code {
+	case codeNotFound:
+		return tmux.ErrSessionNotFound
+	case codeExists:
+		return tmux.ErrSessionExists
+	}
+	return fmt.Errorf("ptyd %s: %s", op, resp.Error)
+}
+
+// Ping checks that a supervisor is listening.
+func (c *Client) Ping() error {
+	_, err := c.call(request{Op: opPing})
+	return err
+}
+
+// Shutdown asks the supervisor to kill all sessions and exit.
+func (c *Client) Shutdown() error {
+	_, err := c.call(request{Op: opShutdown})
+	return err
+}
+
+// NewSessionWithCommand creates a session running command in workDir.
+func (c *Client) NewSessionWithCommand(name, workDir, command string) error {
+	_, err := c.call(request{Op: opCreate, Session: name, WorkDir: workDir, Command: command})
+	return err
+}
+
+// KillSession terminates a session and its process group.
+func (c *Client) KillSession(name string) error {
+	_, err := c.call(request{Op: opKill, Session: name})
+	return err
+}
+
+// HasSession reports whether a session exists. A missing supervisor means
+// no sessions, matching tmux's behavior when no server is running.
+func (c *Client) HasSession(name string) (bool, error) {
+	resp, err := c.call(request{Op: opHas, Session: name})
+	if errors.Is(err, ErrNoServer) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return resp.Exists, nil
+}
+
+// ListSessions returns all session names.
+func (c *Client) ListSessions() ([]string, error) {
+	resp, err := c.call(request{Op: opList})
+	if errors.Is(err, ErrNoServer) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Sessions, nil
+}
+
+// write sends raw bytes to a session's terminal input.
+func (c *Client) write(session, data string) error {
+	_, err := c.call(request{Op: opWrite, Session: session, Data: data})
+	return err
+}
+
+// SendKeys sends literal text, then Enter after a short debounce.
+func (c *Client) SendKeys(session, keys string) error {
+	if err := c.write(session, keys); err != nil {
+		return err
+	}
+	time.Sleep(100 * time.Millisecond)
+	return c.write(session, "\r")
+}
+
+// rawKeys maps the tmux key names Gas Town sends to the bytes a terminal
+// would produce.
+var rawKeys = map[string]string{
+	"C-c":    "\x03",
+	"C-d":    "\x04",
+	"C-u":    "\x15",
+	"Enter":  "\r",
+	"Escape": "\x1b",
+	"Up":     "\x1b[A",
+	"Down":   "\x1b[B",
+}
+
+// SendKeysRaw sends a tmux key name ("C-c", "Enter", "Down", ...) without
+// appending Enter. Names without a mapping are sent as literal text.
+func (c *Client) SendKeysRaw(session, keys string) error {
+	if raw, ok := rawKeys[keys]; ok {
+		keys = raw
+	}
+	return c.write(session, keys)
+}
+
+// NudgeSession delivers a message to an agent prompt using the same
+// paste, wait, Escape, Enter sequence as tmux.NudgeSession.
+func (c *Client) NudgeSession(session, message string) error {
+	actual, _ := nudgeLocks.LoadOrStore(session, &sync.Mutex{})
+	lock := actual.(*sync.Mutex)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := c.write(session, message); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+	_ = c.write(session, "\x1b")
+	time.Sleep(100 * time.Millisecond)
+	return c.write(session, "\r")
+}
+
+// CapturePane returns the last lines of session output from the ring buffer.
+func (c *Client) CapturePane(session string, lines int) (string, error) {
+	resp, err := c.call(request{Op: opCapture, Session: session, Lines: lines})
+	if err != nil {
+		return "", err
+	}
+	return resp.Output, nil
+}
+
+// GetPanePID returns the PID of the session's main process.
+func (c *Client) GetPanePID(session string) (string, error) {
+	resp, err := c.call(request{Op: opPID, Session: session})
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(resp.PID), nil
+}
+
+// GetPaneCommand returns the name of the session's foreground command.
+func (c *Client) GetPaneCommand(session string) (string, error) {
+	resp, err := c.call(request{Op: opCommand, Session: session})
+	if err != nil {
+		return "", err
+	}
+	return resp.Output, nil
+}
+
+// WaitForCommand polls until the session's foreground command is not in
+// excludeCommands, mirroring tmux.WaitForCommand.
+func (c *Client) WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		cmd, err := c.GetPaneCommand(session)
+		if err == nil && !slices.Contains(excludeCommands, cmd) {
+			return nil
+		}
+		time.Sleep(constants.PollInterval)
+	}
+	return fmt.Errorf("timeout waiting for command (still running excluded command)")
+}
+
+// AcceptBypassPermissionsWarning dismisses Claude's bypass permissions
+// dialog if it is showing, using the same keys as the tmux backend.
+func (c *Client) AcceptBypassPermissionsWarning(session string) error {
+	// Wait for the dialog to potentially render
+	time.Sleep(1 * time.Second)
+
+	content, err := c.CapturePane(session, 30)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(content, "Bypass Permissions mode") {
+		return nil
+	}
+
+	// Select "Yes, I accept" (option 2) and confirm
+	if err := c.SendKeysRaw(session, "Down"); err != nil {
+		return err
+	}
+	time.Sleep(200 * time.Millisecond)
+	return c.SendKeysRaw(session, "Enter")
+}
+
+// SetEnvironment records an environment variable on the session.
+func (c *Client) SetEnvironment(session, key, value string) error {
+	_, err := c.call(request{Op: opSetEnv, Session: session, Key: key, Value: value})
+	return err
+}
+
+// AttachSession connects the current terminal to a session. The terminal
+// is put in raw mode; press Ctrl-] to detach.
+func (c *Client) AttachSession(session string) error {
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	data, err := json.Marshal(request{Op: opAttach, Session: session})
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("ptyd attach: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("ptyd attach: reading response: %w", err)
+	}
+	var resp response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("ptyd attach: %w", err)
+	}
+	if !resp.OK {
+		return responseError(opAttach, resp)
+	}
+
+	stdin := int(os.Stdin.Fd())
+	if term.IsTerminal(stdin) {
+		state, err := term.MakeRaw(stdin)
+		if err != nil {
+			return fmt.Errorf("setting raw mode: %w", err)
+		}
+		defer func() { _ = term.Restore(stdin, state) }()
+	}
+
+	output := make(chan struct{})
+	go func() {
+		defer close(output)
+		_, _ = io.Copy(os.Stdout, reader)
+	}()
+
+	input := make(chan struct{})
+	go func() {
+		defer close(input)
+		copyUntilDetach(conn, os.Stdin)
+	}()
+
+	select {
+	case <-output:
+	case <-input:
+	}
+	return nil
+}
+
+// copyUntilDetach copies src to dst until EOF or DetachKey is read.
+func copyUntilDetach(dst io.Writer, src io.Reader) {
+	buf := make([]byte, 1024)
+	for {
+		n, err := src.Read(buf)
+		for i := 0; i < n; i++ {
+			if buf[i] == DetachKey {
+				_, _ = dst.Write(buf[:i])
+				return
+			}
+		}
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,127 @@
+//go:build linux
+
+package ptyd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// openPTY allocates a new pseudo-terminal pair and returns the master side
+// along with the opened slave side.
+func openPTY() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening /dev/ptmx: %w", err)
+	}
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("unlocking pty: %w", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("getting pty number: %w", err)
+	}
+
+	slave, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("opening pty slave: %w", err)
+	}
+	return master, slave, nil
+}
+
+// setWinsize sets the terminal dimensions of a pty.
+func setWinsize(f *os.File, cols, rows uint16) error {
+	return unix.IoctlSetWinsize(int(f.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Col: cols, Row: rows})
+}
+
+// sessionSysProcAttr makes the child a session leader with the pty slave
+// (its stdin) as the controlling terminal.
+func sessionSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+}
+
+// killProcessGroup signals the process group led by pid: SIGHUP (like a
+// closing terminal) or SIGKILL when force is set.
+func killProcessGroup(pid int, force bool) error {
+	sig := syscall.SIGHUP
+	if force {
+		sig = syscall.SIGKILL
+	}
+	// Negative PID targets the whole group; the child is a session leader.
+	return syscall.Kill(-pid, sig)
+}
+
+// foregroundCommand returns the name of the command in the foreground of
+// the pty. Sessions run via sh -c, so when the foreground process group
+// leader is a shell, the name of its child in that group is returned
+// instead (as tmux does for a shell running an agent).
+func foregroundCommand(master *os.File) (string, error) {
+	pgrp, err := unix.IoctlGetInt(int(master.Fd()), unix.TIOCGPGRP)
+	if err != nil {
+		return "", fmt.Errorf("getting foreground process group: %w", err)
+	}
+	name, _, _, err := procStat(pgrp)
+	if err != nil {
+		return "", fmt.Errorf("reading foreground command: %w", err)
+	}
+	if !slices.Contains(constants.SupportedShells, name) {
+		return name, nil
+	}
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return name, nil
+	}
+	child := 0
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid <= child {
+			continue
+		}
+		if _, ppid, group, err := procStat(pid); err == nil && ppid == pgrp && group == pgrp {
+			child = pid
+		}
+	}
+	if child == 0 {
+		return name, nil
+	}
+	if childName, _, _, err := procStat(child); err == nil {
+		return childName, nil
+	}
+	return name, nil
+}
+
+// procStat reads a process's command name, parent PID and process group
+// from /proc/<pid>/stat.
+func procStat(pid int) (name string, ppid, pgrp int, err error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return "", 0, 0, err
+	}
+	// Format: pid (comm) state ppid pgrp ...; comm may contain spaces.
+	stat := string(data)
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return "", 0, 0, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 3 {
+		return "", 0, 0, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	ppid, _ = strconv.Atoi(fields[1])
+	pgrp, _ = strconv.Atoi(fields[2])
+	return stat[open+1 : end], ppid, pgrp, nil
+}
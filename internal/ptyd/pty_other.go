@@ -0,0 +1,32 @@
+//go:build !linux
+
+package ptyd
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// errUnsupported is returned on platforms without PTY support in ptyd.
+var errUnsupported = errors.New("ptyd: pseudo-terminals are only supported on linux")
+
+func openPTY() (master, slave *os.File, err error) {
+	return nil, nil, errUnsupported
+}
+
+func setWinsize(_ *os.File, _, _ uint16) error {
+	return errUnsupported
+}
+
+func sessionSysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+func killProcessGroup(_ int, _ bool) error {
+	return errUnsupported
+}
+
+func foregroundCommand(_ *os.File) (string, error) {
+	return "", errUnsupported
+}
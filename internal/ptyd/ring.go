@@ -0,0 +1,81 @@
+package ptyd
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultScrollback is the per-session ring buffer size in bytes.
+const DefaultScrollback = 1 << 20 // 1 MiB
+
+// Ring is a fixed-capacity byte buffer that keeps the most recent output
+// written to it. Older bytes are overwritten once the buffer is full.
+type Ring struct {
+	mu    sync.Mutex
+	buf   []byte
+	start int // index of the oldest byte
+	size  int // number of valid bytes
+}
+
+// NewRing creates a ring buffer holding at most capacity bytes.
+func NewRing(capacity int) *Ring {
+	if capacity <= 0 {
+		capacity = DefaultScrollback
+	}
+	return &Ring{buf: make([]byte, capacity)}
+}
+
+// Write appends p, discarding the oldest bytes when full. It never fails.
+func (r *Ring) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := len(p)
+	capacity := len(r.buf)
+	if n >= capacity {
+		copy(r.buf, p[n-capacity:])
+		r.start = 0
+		r.size = capacity
+		return n, nil
+	}
+
+	for _, b := range p {
+		end := (r.start + r.size) % capacity
+		r.buf[end] = b
+		if r.size < capacity {
+			r.size++
+		} else {
+			r.start = (r.start + 1) % capacity
+		}
+	}
+	return n, nil
+}
+
+// Bytes returns a copy of the buffered output, oldest first.
+func (r *Ring) Bytes() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]byte, r.size)
+	capacity := len(r.buf)
+	first := copy(out, r.buf[r.start:min(r.start+r.size, capacity)])
+	if first < r.size {
+		copy(out[first:], r.buf[:r.size-first])
+	}
+	return out
+}
+
+// Lines returns the last n lines of buffered output, with carriage returns
+// stripped. If n <= 0, all buffered lines are returned.
+func (r *Ring) Lines(n int) string {
+	text := strings.ReplaceAll(string(r.Bytes()), "\r", "")
+	text = strings.TrimRight(text, "\n")
+	if n <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
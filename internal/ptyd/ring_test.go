@@ -0,0 +1,42 @@
+package ptyd
+
+import "testing"
+
+func TestRingKeepsMostRecentBytes(t *testing.T) {
+	r := NewRing(8)
+	_, _ = r.Write([]byte("abcdef"))
+	_, _ = r.Write([]byte("ghij"))
+
+	if got := string(r.Bytes()); got != "cdefghij" {
+		t.Errorf("Bytes() = %q, want %q", got, "cdefghij")
+	}
+}
+
+func TestRingOversizedWrite(t *testing.T) {
+	r := NewRing(4)
+	_, _ = r.Write([]byte("0123456789"))
+
+	if got := string(r.Bytes()); got != "6789" {
+		t.Errorf("Bytes() = %q, want %q", got, "6789")
+	}
+}
+
+func TestRingLines(t *testing.T) {
+	r := NewRing(64)
+	_, _ = r.Write([]byte("one\r\ntwo\r\nthree\r\n"))
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{1, "three"},
+		{2, "two\nthree"},
+		{10, "one\ntwo\nthree"},
+		{0, "one\ntwo\nthree"},
+	}
+	for _, tt := range tests {
+		if got := r.Lines(tt.n); got != tt.want {
+			t.Errorf("Lines(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package ptyd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Request operations understood by the server.
+const (
+	opCreate   = "create"
+	opKill     = "kill"
+	opHas      = "has"
+	opList     = "list"
+	opWrite    = "write"
+	opCapture  = "capture"
+	opPID      = "pid"
+	opCommand  = "command"
+	opSetEnv   = "setenv"
+	opAttach   = "attach"
+	opPing     = "ping"
+	opShutdown = "shutdown"
+)
+
+// Error codes carried in responses so clients can restore sentinel errors.
+const (
+	codeNotFound = "not_found"
+	codeExists   = "exists"
+)
+
+// request is a single newline-delimited JSON command sent by a client.
+type request struct {
+	Op      string            `json:"op"`
+	Session string            `json:"session,omitempty"`
+	WorkDir string            `json:"work_dir,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Data    string            `json:"data,omitempty"`
+	Lines   int               `json:"lines,omitempty"`
+	Key     string            `json:"key,omitempty"`
+	Value   string            `json:"value,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// response is the server's reply to a request.
+type response struct {
+	OK       bool     `json:"ok"`
+	Error    string   `json:"error,omitempty"`
+	Code     string   `json:"code,omitempty"`
+	Exists   bool     `json:"exists,omitempty"`
+	Output   string   `json:"output,omitempty"`
+	Sessions []string `json:"sessions,omitempty"`
+	PID      int      `json:"pid,omitempty"`
+}
+
+func errorResponse(err error) response {
+	resp := response{Error: err.Error()}
+	switch {
+	case errors.Is(err, tmux.ErrSessionNotFound):
+		resp.Code = codeNotFound
+	case errors.Is(err, tmux.ErrSessionExists):
+		resp.Code = codeExists
+	}
+	return resp
+}
+
+// Server exposes a Supervisor on a Unix socket.
+type Server struct {
+	sup        *Supervisor
+	socketPath string
+
+	mu       sync.Mutex
+	ln       net.Listener
+	shutdown chan struct{}
+}
+
+// NewServer creates a server for sup listening at socketPath.
+func NewServer(socketPath string, sup *Supervisor) *Server {
+	return &Server{
+		sup:        sup,
+		socketPath: socketPath,
+		shutdown:   make(chan struct{}),
+	}
+}
+
+// Serve listens on the socket and handles clients until Close is called or
+// a client requests shutdown. A stale socket file from a dead server is
+// replaced; a live one is an error.
+func (s *Server) Serve() error {
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0700); err != nil {
+		return fmt.Errorf("creating socket dir: %w", err)
+	}
+	if NewClient(s.socketPath).Ping() == nil {
+		return fmt.Errorf("ptyd already running on %s", s.socketPath)
+	}
+	_ = os.Remove(s.socketPath)
+
+	ln, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.socketPath, err)
+	}
+	if err := os.Chmod(s.socketPath, 0600); err != nil {
+		_ = ln.Close()
+		return fmt.Errorf("securing socket: %w", err)
+	}
+
+	s.mu.Lock()
+	s.ln = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			select {
+			case <-s.shutdown:
+				return nil
+			default:
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// Close stops accepting clients, kills all sessions and removes the socket.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.shutdown:
+		return nil
+	default:
+		close(s.shutdown)
+	}
+
+	s.sup.KillAll()
+	var err error
+	if s.ln != nil {
+		err = s.ln.Close()
+	}
+	_ = os.Remove(s.socketPath)
+	return err
+}
+
+// handle serves one connection: a single request and its response, or an
+// attach stream that lasts until either side goes away.
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return
+	}
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		writeResponse(conn, response{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+
+	if req.Op == opAttach {
+		s.attach(conn, reader, req.Session)
+		return
+	}
+	writeResponse(conn, s.dispatch(req))
+	if req.Op == opShutdown {
+		go func() { _ = s.Close() }()
+	}
+}
+
+// dispatch executes a non-streaming request.
+func (s *Server) dispatch(req request) response {
+	var err error
+	resp := response{OK: true}
+
+	switch req.Op {
+	case opPing, opShutdown:
+	case opCreate:
+		err = s.sup.Create(req.Session, req.WorkDir, req.Command, req.Env)
+	case opKill:
+		err = s.sup.Kill(req.Session)
+	case opHas:
+		resp.Exists = s.sup.Has(req.Session)
+	case opList:
+		resp.Sessions = s.sup.List()
+	case opWrite:
+		err = s.sup.Write(req.Session, []byte(req.Data))
+	case opCapture:
+		resp.Output, err = s.sup.Capture(req.Session, req.Lines)
+	case opPID:
+		resp.PID, err = s.sup.PID(req.Session)
+	case opCommand:
+		resp.Output, err = s.sup.Command(req.Session)
+	case opSetEnv:
+		err = s.sup.SetEnv(req.Session, req.Key, req.Value)
+	default:
+		err = fmt.Errorf("unknown op %q", req.Op)
+	}
+
+	if err != nil {
+		return errorResponse(err)
+	}
+	return resp
+}
+
+// attach streams session output to conn and conn input to the session.
+func (s *Server) attach(conn net.Conn, reader io.Reader, name string) {
+	// Acknowledge before replaying scrollback so the client knows the
+	// stream that follows is raw terminal output.
+	if !s.sup.Has(name) {
+		writeResponse(conn, errorResponse(tmux.ErrSessionNotFound))
+		return
+	}
+	writeResponse(conn, response{OK: true})
+
+	detach, done, err := s.sup.Attach(name, conn)
+	if err != nil {
+		return
+	}
+	defer detach()
+
+	input := make(chan struct{})
+	go func() {
+		defer close(input)
+		buf := make([]byte, 4096)
+		for {
+			n, err := reader.Read(buf)
+			if n > 0 {
+				if werr := s.sup.Write(name, buf[:n]); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-input:
+	case <-s.shutdown:
+	}
+}
+
+func writeResponse(w io.Writer, resp response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	_, _ = w.Write(append(data, '\n'))
+}
@@ -0,0 +1,232 @@
+package ptyd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// startTestServer runs a supervisor on a short socket path (Unix socket
+// paths are length-limited, so t.TempDir() can be too long).
+func startTestServer(t *testing.T) *Client {
+	t.Helper()
+	master, slave, err := openPTY()
+	if err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	_ = master.Close()
+	_ = slave.Close()
+
+	dir, err := os.MkdirTemp("", "ptyd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "s.sock")
+	server := NewServer(socket, NewSupervisor(4096))
+	go func() { _ = server.Serve() }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	client := NewClient(socket)
+	deadline := time.Now().Add(2 * time.Second)
+	for client.Ping() != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("server did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return client
+}
+
+func waitForOutput(t *testing.T, c *Client, session, want string) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		out, err := c.CapturePane(session, 50)
+		if err == nil && strings.Contains(out, want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("output of %s never contained %q; last: %q (err %v)", session, want, out, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestClientSessionLifecycle(t *testing.T) {
+	c := startTestServer(t)
+
+	if err := c.NewSessionWithCommand("gt-test-one", os.TempDir(), "echo ready; cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := c.NewSessionWithCommand("gt-test-one", os.TempDir(), "cat"); !errors.Is(err, tmux.ErrSessionExists) {
+		t.Errorf("duplicate create err = %v, want ErrSessionExists", err)
+	}
+
+	has, err := c.HasSession("gt-test-one")
+	if err != nil || !has {
+		t.Fatalf("HasSession = %v, %v; want true", has, err)
+	}
+	sessions, err := c.ListSessions()
+	if err != nil || len(sessions) != 1 || sessions[0] != "gt-test-one" {
+		t.Errorf("ListSessions = %v, %v", sessions, err)
+	}
+	if pid, err := c.GetPanePID("gt-test-one"); err != nil || pid == "0" {
+		t.Errorf("GetPanePID = %q, %v", pid, err)
+	}
+
+	waitForOutput(t, c, "gt-test-one", "ready")
+
+	// cat echoes input back through the terminal
+	if err := c.SendKeys("gt-test-one", "ping-from-test"); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitForOutput(t, c, "gt-test-one", "ping-from-test")
+
+	if err := c.KillSession("gt-test-one"); err != nil {
+		t.Fatalf("KillSession: %v", err)
+	}
+	if has, _ := c.HasSession("gt-test-one"); has {
+		t.Error("session still present after kill")
+	}
+	if _, err := c.CapturePane("gt-test-one", 10); !errors.Is(err, tmux.ErrSessionNotFound) {
+		t.Errorf("capture after kill err = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSessionEndsWhenCommandExits(t *testing.T) {
+	c := startTestServer(t)
+
+	if err := c.NewSessionWithCommand("gt-test-exit", os.TempDir(), "true"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		has, _ := c.HasSession("gt-test-exit")
+		if !has {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("session did not end after command exited")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestClientNoServer(t *testing.T) {
+	c := NewClient(filepath.Join(os.TempDir(), "gt-ptyd-missing.sock"))
+
+	has, err := c.HasSession("anything")
+	if err != nil || has {
+		t.Errorf("HasSession without server = %v, %v; want false, nil", has, err)
+	}
+	if err := c.NewSessionWithCommand("x", "", "true"); !errors.Is(err, ErrNoServer) {
+		t.Errorf("create without server err = %v, want ErrNoServer", err)
+	}
+}
+
+func TestClientStartupHandshake(t *testing.T) {
+	c := startTestServer(t)
+
+	// The shell runs a foreground child, like a startup command running an agent
+	script := `echo "Bypass Permissions mode"; sleep 0.2; exec cat`
+	if err := c.NewSessionWithCommand("gt-test-agent", os.TempDir(), "export X=1 && sh -c '"+script+"'"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := c.WaitForCommand("gt-test-agent", []string{"bash", "zsh", "sh"}, 3*time.Second); err != nil {
+		t.Fatalf("WaitForCommand: %v", err)
+	}
+	if cmd, err := c.GetPaneCommand("gt-test-agent"); err != nil || cmd != "cat" {
+		t.Errorf("GetPaneCommand = %q, %v; want cat", cmd, err)
+	}
+
+	// The dialog is accepted with Down, Enter; cat echoes the escape sequence
+	if err := c.AcceptBypassPermissionsWarning("gt-test-agent"); err != nil {
+		t.Fatalf("AcceptBypassPermissionsWarning: %v", err)
+	}
+	waitForOutput(t, c, "gt-test-agent", "^[[B")
+
+	// C-c interrupts cat, ending the session
+	if err := c.SendKeysRaw("gt-test-agent", "C-c"); err != nil {
+		t.Fatalf("SendKeysRaw: %v", err)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for has, _ := c.HasSession("gt-test-agent"); has; has, _ = c.HasSession("gt-test-agent") {
+		if time.Now().After(deadline) {
+			t.Fatal("session survived C-c")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// blockedWriter is an attach client that never reads: writes block until
+// it is closed.
+type blockedWriter struct {
+	closed chan struct{}
+}
+
+func (w *blockedWriter) Write(p []byte) (int, error) {
+	<-w.closed
+	return 0, io.ErrClosedPipe
+}
+
+func (w *blockedWriter) Close() error {
+	close(w.closed)
+	return nil
+}
+
+func TestSlowAttachClientIsDisconnected(t *testing.T) {
+	master, slave, err := openPTY()
+	if err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	_ = master.Close()
+	_ = slave.Close()
+
+	sup := NewSupervisor(4096)
+	t.Cleanup(sup.KillAll)
+	if err := sup.Create("gt-test-flood", os.TempDir(), "cat", nil); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	slow := &blockedWriter{closed: make(chan struct{})}
+	_, done, err := sup.Attach("gt-test-flood", slow)
+	if err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+
+	// Flood the session in separate chunks; the pump must keep recording
+	// while the client lags
+	for i := 0; i <= clientBuffer*2; i++ {
+		if err := sup.Write("gt-test-flood", []byte(fmt.Sprintf("line-%d\r", i))); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	want := fmt.Sprintf("line-%d", clientBuffer*2)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		out, _ := sup.Capture("gt-test-flood", 5)
+		if strings.Contains(out, want) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pump stalled behind a slow client; last output %q", out)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("slow client was not disconnected")
+	}
+}
@@ -0,0 +1,360 @@
+// Package ptyd is a headless session backend: a pure-Go PTY supervisor that
+// runs agent commands in pseudo-terminals, keeps their scrollback in ring
+// buffers, and serves create/kill/send/capture/attach over a Unix socket.
+//
+// It is a drop-in replacement for tmux (see tmux.SessionBackend) in CI and
+// containers where tmux isn't installed.
+package ptyd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Default terminal size for supervised sessions. Agents render TUIs, so
+// give them a roomy virtual screen.
+const (
+	defaultCols = 200
+	defaultRows = 50
+)
+
+// session is a single supervised command running in a pseudo-terminal.
+type session struct {
+	name    string
+	workDir string
+	command string
+	created time.Time
+
+	cmd  *exec.Cmd
+	pty  *os.File
+	ring *Ring
+	done chan struct{}
+
+	mu      sync.Mutex
+	env     map[string]string
+	clients map[*attachClient]struct{}
+	exited  bool // Output has ended; no new clients
+}
+
+// clientBuffer is how many output chunks an attached client may fall
+// behind before it is disconnected.
+const clientBuffer = 256
+
+// attachClient is an attached terminal. Output is queued on out and written
+// by the client's own goroutine, so a slow client never stalls the pump.
+type attachClient struct {
+	w    io.Writer
+	out  chan []byte
+	done chan struct{} // closed when the writer goroutine exits
+	once sync.Once
+}
+
+func newAttachClient(w io.Writer) *attachClient {
+	return &attachClient{
+		w:    w,
+		out:  make(chan []byte, clientBuffer),
+		done: make(chan struct{}),
+	}
+}
+
+// run writes queued output until the queue is closed or a write fails.
+func (c *attachClient) run(sess *session) {
+	defer close(c.done)
+	for p := range c.out {
+		if _, err := c.w.Write(p); err != nil {
+			sess.removeClient(c)
+			return
+		}
+	}
+}
+
+// stop closes the queue; the writer drains what is left and exits.
+// Callers hold the session lock.
+func (c *attachClient) stop() {
+	c.once.Do(func() { close(c.out) })
+}
+
+// removeClient detaches a client.
+func (s *session) removeClient(c *attachClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, c)
+	c.stop()
+}
+
+// record appends output to the scrollback and queues it for every attached
+// client. A client whose queue is full is disconnected rather than allowed
+// to block the session.
+func (s *session) record(p []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.ring.Write(p)
+	if len(s.clients) == 0 {
+		return
+	}
+	chunk := append([]byte(nil), p...)
+	for c := range s.clients {
+		select {
+		case c.out <- chunk:
+		default:
+			delete(s.clients, c)
+			c.stop()
+			if closer, ok := c.w.(io.Closer); ok {
+				_ = closer.Close() // Unblock a writer stuck on the lagging client
+			}
+		}
+	}
+}
+
+// Supervisor owns the set of running PTY sessions.
+type Supervisor struct {
+	mu         sync.Mutex
+	sessions   map[string]*session
+	scrollback int
+}
+
+// NewSupervisor creates a supervisor whose sessions keep scrollback bytes
+// of output each.
+func NewSupervisor(scrollback int) *Supervisor {
+	if scrollback <= 0 {
+		scrollback = DefaultScrollback
+	}
+	return &Supervisor{
+		sessions:   make(map[string]*session),
+		scrollback: scrollback,
+	}
+}
+
+// Create starts command via sh -c in a new pseudo-terminal.
+// Returns tmux.ErrSessionExists if a session with that name is running.
+func (s *Supervisor) Create(name, workDir, command string, env map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[name]; ok {
+		return tmux.ErrSessionExists
+	}
+
+	master, slave, err := openPTY()
+	if err != nil {
+		return err
+	}
+	defer slave.Close()
+	_ = setWinsize(master, defaultCols, defaultRows)
+
+	if command == "" {
+		command = os.Getenv("SHELL")
+		if command == "" {
+			command = "/bin/sh"
+		}
+	}
+
+	cmd := exec.Command("sh", "-c", command) //nolint:gosec // G204: command is supplied by the local gt client
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdin = slave
+	cmd.Stdout = slave
+	cmd.Stderr = slave
+	cmd.SysProcAttr = sessionSysProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		_ = master.Close()
+		return fmt.Errorf("starting command: %w", err)
+	}
+
+	sess := &session{
+		name:    name,
+		workDir: workDir,
+		command: command,
+		created: time.Now(),
+		cmd:     cmd,
+		pty:     master,
+		ring:    NewRing(s.scrollback),
+		done:    make(chan struct{}),
+		env:     make(map[string]string),
+		clients: make(map[*attachClient]struct{}),
+	}
+	for k, v := range env {
+		sess.env[k] = v
+	}
+	s.sessions[name] = sess
+
+	go s.pump(sess)
+	return nil
+}
+
+// pump copies PTY output into the session's ring buffer and attached
+// clients until the command exits, then removes the session.
+func (s *Supervisor) pump(sess *session) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := sess.pty.Read(buf)
+		if n > 0 {
+			sess.record(buf[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+	_ = sess.cmd.Wait()
+	_ = sess.pty.Close()
+
+	sess.mu.Lock()
+	sess.exited = true
+	for c := range sess.clients {
+		delete(sess.clients, c)
+		c.stop()
+	}
+	sess.mu.Unlock()
+
+	s.mu.Lock()
+	if s.sessions[sess.name] == sess {
+		delete(s.sessions, sess.name)
+	}
+	s.mu.Unlock()
+	close(sess.done)
+}
+
+// get returns a running session or tmux.ErrSessionNotFound.
+func (s *Supervisor) get(name string) (*session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[name]
+	if !ok {
+		return nil, tmux.ErrSessionNotFound
+	}
+	return sess, nil
+}
+
+// Kill terminates a session's process group and waits briefly for it to exit.
+func (s *Supervisor) Kill(name string) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+
+	pid := sess.cmd.Process.Pid
+	_ = killProcessGroup(pid, false)
+	select {
+	case <-sess.done:
+		return nil
+	case <-time.After(2 * time.Second):
+	}
+	_ = killProcessGroup(pid, true)
+	select {
+	case <-sess.done:
+	case <-time.After(2 * time.Second):
+		return fmt.Errorf("session %s did not exit after SIGKILL", name)
+	}
+	return nil
+}
+
+// Has reports whether a session is running.
+func (s *Supervisor) Has(name string) bool {
+	_, err := s.get(name)
+	return err == nil
+}
+
+// List returns the names of all running sessions, sorted.
+func (s *Supervisor) List() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.sessions))
+	for name := range s.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write sends raw bytes to a session's terminal input.
+func (s *Supervisor) Write(name string, data []byte) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	_, err = sess.pty.Write(data)
+	return err
+}
+
+// Capture returns the last lines of a session's scrollback.
+func (s *Supervisor) Capture(name string, lines int) (string, error) {
+	sess, err := s.get(name)
+	if err != nil {
+		return "", err
+	}
+	return sess.ring.Lines(lines), nil
+}
+
+// PID returns the PID of a session's main process.
+func (s *Supervisor) PID(name string) (int, error) {
+	sess, err := s.get(name)
+	if err != nil {
+		return 0, err
+	}
+	return sess.cmd.Process.Pid, nil
+}
+
+// Command returns the name of the session's foreground process, like
+// tmux's pane_current_command.
+func (s *Supervisor) Command(name string) (string, error) {
+	sess, err := s.get(name)
+	if err != nil {
+		return "", err
+	}
+	return foregroundCommand(sess.pty)
+}
+
+// SetEnv records an environment variable on a session. Like tmux, this
+// does not affect the already-running process.
+func (s *Supervisor) SetEnv(name, key, value string) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	sess.mu.Lock()
+	sess.env[key] = value
+	sess.mu.Unlock()
+	return nil
+}
+
+// Attach replays a session's scrollback to w and then streams live output
+// until detach is called or the session exits. The returned channel is
+// closed once output to w has stopped: after detach, when the session
+// exits, or when w fell too far behind and was disconnected.
+func (s *Supervisor) Attach(name string, w io.Writer) (detach func(), done <-chan struct{}, err error) {
+	sess, err := s.get(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := newAttachClient(w)
+	sess.mu.Lock()
+	c.out <- sess.ring.Bytes()
+	if sess.exited {
+		c.stop()
+	} else {
+		sess.clients[c] = struct{}{}
+	}
+	sess.mu.Unlock()
+	go c.run(sess)
+
+	return func() { sess.removeClient(c) }, c.done, nil
+}
+
+// KillAll terminates every session. Used on supervisor shutdown.
+func (s *Supervisor) KillAll() {
+	for _, name := range s.List() {
+		_ = s.Kill(name)
+	}
+}
@@ -0,0 +1,200 @@
+package refinery
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// ChangesetLanding is one member of a change set being landed, with the
+// working directory of its rig's landing worktree (see ChangesetWorkDir).
+type ChangesetLanding struct {
+	Member  *beads.ChangesetMember
+	WorkDir string
+
+	git      *git.Git
+	preMerge string // Target SHA before the merge, for rolling back
+	pushed   bool
+}
+
+// ChangesetWorkDir returns the worktree change sets land through in a rig,
+// creating it on first use. It shares the refinery clone's repository but
+// has its own detached HEAD, so a landing never touches the branch or
+// working tree the rig's refinery is merging in.
+func ChangesetWorkDir(r *rig.Rig) (string, error) {
+	dir := filepath.Join(r.Path, "refinery", "changeset")
+	if _, err := os.Stat(dir); err == nil {
+		return dir, nil
+	}
+	if err := git.NewGit(WorkDir(r)).WorktreeAddDetached(dir, "HEAD"); err != nil {
+		return "", fmt.Errorf("creating change set worktree for %s: %w", r.Name, err)
+	}
+	return dir, nil
+}
+
+// LandChangeset merges every member of a change set so that either all of
+// them land or none stay merged.
+//
+// Phase 1 merges each member onto a detached copy of its target in its
+// landing worktree, without pushing. If any merge fails, every worktree is
+// reset and nothing leaves the town. Phase 2 pushes the merges one rig at a
+// time. If a push fails, rigs already pushed get a rollback commit pushed
+// on top, and the rest are reset.
+//
+// A member whose earlier landing was pushed and rolled back is already an
+// ancestor of its target; its rollback commit is reverted before merging
+// any commits added since.
+//
+// Member states and merge commits are updated in place. The returned error
+// describes the first failure.
+func LandChangeset(changesetID string, landings []*ChangesetLanding, out io.Writer) error {
+	for _, l := range landings {
+		l.git = git.NewGit(l.WorkDir)
+	}
+
+	// Phase 1: merge locally
+	for i, l := range landings {
+		_, _ = fmt.Fprintf(out, "[Changeset] Merging %s into %s in %s...\n", l.Member.Branch, l.Member.Target, l.Member.Rig)
+		if err := l.merge(changesetID); err != nil {
+			l.fail(err)
+			_, _ = fmt.Fprintf(out, "[Changeset] ✗ %s: %v\n", l.Member.Rig, err)
+			rollBack(changesetID, landings[:i], out)
+			return fmt.Errorf("%s (%s): %w", l.Member.MR, l.Member.Rig, err)
+		}
+	}
+
+	// Phase 2: push
+	for i, l := range landings {
+		_, _ = fmt.Fprintf(out, "[Changeset] Pushing %s in %s...\n", l.Member.Target, l.Member.Rig)
+		if err := l.git.Push("origin", "HEAD:refs/heads/"+l.Member.Target, false); err != nil {
+			err = fmt.Errorf("push failed: %w", err)
+			l.fail(err)
+			_ = l.git.ResetHard(l.preMerge)
+			_, _ = fmt.Fprintf(out, "[Changeset] ✗ %s: %v\n", l.Member.Rig, err)
+			rollBack(changesetID, landings[:i], out)
+			rollBack(changesetID, landings[i+1:], out)
+			return fmt.Errorf("%s (%s): %w", l.Member.MR, l.Member.Rig, err)
+		}
+		l.pushed = true
+		l.Member.State = beads.MemberMerged
+		l.Member.Reverted = ""
+		l.Member.Error = ""
+	}
+
+	_, _ = fmt.Fprintf(out, "[Changeset] ✓ Landed %d MRs\n", len(landings))
+	return nil
+}
+
+// merge merges the member's branch into a fresh, detached copy of its
+// target.
+func (l *ChangesetLanding) merge(changesetID string) error {
+	m := l.Member
+	if err := l.git.Fetch("origin"); err != nil {
+		return fmt.Errorf("fetching origin: %w", err)
+	}
+	if err := l.git.CheckoutDetached("origin/" + m.Target); err != nil {
+		return fmt.Errorf("checking out %s: %w", m.Target, err)
+	}
+	pre, err := l.git.Rev("HEAD")
+	if err != nil {
+		return err
+	}
+	l.preMerge = pre
+
+	// Prefer the local branch (shared .repo.git with polecats)
+	source := m.Branch
+	if exists, _ := l.git.BranchExists(source); !exists {
+		source = "origin/" + m.Branch
+	}
+	head, err := l.git.Rev(source)
+	if err != nil {
+		return fmt.Errorf("branch %s not found", m.Branch)
+	}
+	if m.Tested != "" && !strings.HasPrefix(head, m.Tested) {
+		return fmt.Errorf("branch %s moved since its tests passed (tested %s, now %s)", m.Branch, short(m.Tested), short(head))
+	}
+
+	// An earlier landing of this member was pushed and rolled back: the
+	// target already has the branch's commits, minus the rollback. Merging
+	// again would change nothing, so bring the changes back by reverting
+	// the rollback.
+	restored := false
+	if m.Reverted != "" {
+		if ok, _ := l.git.IsAncestor(m.Reverted, "HEAD"); ok {
+			if err := l.git.Revert(m.Reverted); err != nil {
+				_ = l.git.AbortRevert()
+				return fmt.Errorf("undoing rollback %s failed (rebase %s onto %s): %w", short(m.Reverted), m.Branch, m.Target, err)
+			}
+			restored = true
+		}
+	}
+	if merged, _ := l.git.IsAncestor(head, "HEAD"); merged {
+		if !restored {
+			return fmt.Errorf("branch %s is already in %s; rebase it onto %s to land it again", m.Branch, m.Target, m.Target)
+		}
+	} else {
+		msg := fmt.Sprintf("Merge %s into %s (%s, change set %s)", m.Branch, m.Target, m.MR, changesetID)
+		if err := l.git.MergeNoFF(source, msg); err != nil {
+			_ = l.git.AbortMerge()
+			return fmt.Errorf("merge failed: %w", err)
+		}
+	}
+	m.MergeCommit, err = l.git.Rev("HEAD")
+	return err
+}
+
+func (l *ChangesetLanding) fail(err error) {
+	l.Member.State = beads.MemberFailed
+	l.Member.Error = err.Error()
+	l.Member.MergeCommit = ""
+}
+
+// rollBack undoes the merges of members that got one. Unpushed merges are
+// reset away; pushed ones are undone by a rollback commit restoring the
+// target as it was before the landing, which is pushed and recorded on the
+// member so a later landing can revert it.
+func rollBack(changesetID string, landings []*ChangesetLanding, out io.Writer) {
+	for _, l := range landings {
+		if l.preMerge == "" || l.Member.MergeCommit == "" {
+			continue // Never merged
+		}
+		m := l.Member
+		if !l.pushed {
+			if err := l.git.ResetHard(l.preMerge); err != nil {
+				m.Error = fmt.Sprintf("rollback failed: %v", err)
+				_, _ = fmt.Fprintf(out, "[Changeset] ✗ Could not reset %s in %s: %v\n", m.Target, m.Rig, err)
+				continue
+			}
+		} else {
+			msg := fmt.Sprintf("Revert %s in %s (%s, change set %s rolled back)", m.Branch, m.Target, m.MR, changesetID)
+			if err := l.git.RestoreTree(l.preMerge, msg); err != nil {
+				_ = l.git.ResetHard(m.MergeCommit)
+				m.Error = fmt.Sprintf("rollback failed: revert %s: %v", short(m.MergeCommit), err)
+				_, _ = fmt.Fprintf(out, "[Changeset] ✗ Could not revert %s in %s: %v\n", short(m.MergeCommit), m.Rig, err)
+				continue
+			}
+			if err := l.git.Push("origin", "HEAD:refs/heads/"+m.Target, false); err != nil {
+				m.Error = fmt.Sprintf("rollback failed: push revert of %s: %v", short(m.MergeCommit), err)
+				_, _ = fmt.Fprintf(out, "[Changeset] ✗ Could not push revert in %s: %v\n", m.Rig, err)
+				continue
+			}
+			m.Reverted, _ = l.git.Rev("HEAD")
+		}
+		_, _ = fmt.Fprintf(out, "[Changeset] Rolled back %s in %s\n", m.MR, m.Rig)
+		m.State = beads.MemberRolledBack
+		m.Error = ""
+	}
+}
+
+func short(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,236 @@
+package refinery
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// setupChangesetRig creates a rig's origin and refinery clone, with a
+// polecat branch adding <rig>.txt pushed to origin. Returns the landing and
+// the origin path.
+func setupChangesetRig(t *testing.T, root, rigName string) (*ChangesetLanding, string) {
+	t.Helper()
+	origin := filepath.Join(root, rigName+".git")
+	clone := filepath.Join(root, rigName, "refinery", "rig")
+	runGit(t, root, "init", "--bare", "-b", "main", origin)
+	runGit(t, root, "clone", origin, clone)
+	runGit(t, clone, "config", "user.email", "test@test.com")
+	runGit(t, clone, "config", "user.name", "Test")
+	writeAndCommit(t, clone, "README.md", "base\n")
+	runGit(t, clone, "push", "origin", "main")
+	runGit(t, clone, "checkout", "-b", "polecat/nux")
+	writeAndCommit(t, clone, rigName+".txt", "change\n")
+	runGit(t, clone, "push", "origin", "polecat/nux")
+	tested := runGit(t, clone, "rev-parse", "HEAD")
+	runGit(t, clone, "checkout", "main")
+
+	return &ChangesetLanding{
+		Member: &beads.ChangesetMember{
+			MR:     rigName + "-mr",
+			Rig:    rigName,
+			Branch: "polecat/nux",
+			Target: "main",
+			State:  beads.MemberPassed,
+			Tested: tested,
+		},
+		WorkDir: clone,
+	}, origin
+}
+
+func originHasFile(t *testing.T, origin, name string) bool {
+	t.Helper()
+	files := runGit(t, origin, "ls-tree", "--name-only", "main")
+	return strings.Contains("\n"+files+"\n", "\n"+name+"\n")
+}
+
+func TestLandChangeset(t *testing.T) {
+	root := t.TempDir()
+	api, apiOrigin := setupChangesetRig(t, root, "api")
+	client, clientOrigin := setupChangesetRig(t, root, "client")
+
+	if err := LandChangeset("hq-cs1", []*ChangesetLanding{api, client}, io.Discard); err != nil {
+		t.Fatalf("LandChangeset: %v", err)
+	}
+	for _, l := range []*ChangesetLanding{api, client} {
+		if l.Member.State != beads.MemberMerged || l.Member.MergeCommit == "" {
+			t.Errorf("%s: member = %+v, want merged", l.Member.Rig, l.Member)
+		}
+	}
+	if !originHasFile(t, apiOrigin, "api.txt") || !originHasFile(t, clientOrigin, "client.txt") {
+		t.Error("change not pushed to both origins")
+	}
+	if msg := runGit(t, apiOrigin, "log", "-1", "--format=%s", "main"); !strings.Contains(msg, "change set hq-cs1") {
+		t.Errorf("merge message = %q", msg)
+	}
+}
+
+func TestLandChangesetMergeFailure(t *testing.T) {
+	root := t.TempDir()
+	api, apiOrigin := setupChangesetRig(t, root, "api")
+	client, clientOrigin := setupChangesetRig(t, root, "client")
+	apiBefore := runGit(t, apiOrigin, "rev-parse", "main")
+
+	// The client branch changed after its tests passed
+	client.Member.Tested = strings.Repeat("0", 40)
+
+	err := LandChangeset("hq-cs1", []*ChangesetLanding{api, client}, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "moved since its tests passed") {
+		t.Fatalf("LandChangeset = %v, want a moved-branch failure", err)
+	}
+	if api.Member.State != beads.MemberRolledBack || client.Member.State != beads.MemberFailed {
+		t.Errorf("states = %s, %s; want rolled_back, failed", api.Member.State, client.Member.State)
+	}
+	if client.Member.Error == "" {
+		t.Error("failed member has no error")
+	}
+	if got := runGit(t, apiOrigin, "rev-parse", "main"); got != apiBefore {
+		t.Error("api origin changed although nothing should have been pushed")
+	}
+	if got := runGit(t, api.WorkDir, "rev-parse", "HEAD"); got != apiBefore {
+		t.Error("api landing worktree kept its local merge")
+	}
+	if originHasFile(t, clientOrigin, "client.txt") {
+		t.Error("client change was pushed")
+	}
+}
+
+func TestLandChangesetPushFailure(t *testing.T) {
+	root := t.TempDir()
+	api, apiOrigin := setupChangesetRig(t, root, "api")
+	client, clientOrigin := setupChangesetRig(t, root, "client")
+	clientBefore := runGit(t, clientOrigin, "rev-parse", "main")
+
+	// The client origin rejects pushes to main
+	hook := filepath.Join(clientOrigin, "hooks", "pre-receive")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho protected >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := LandChangeset("hq-cs1", []*ChangesetLanding{api, client}, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "push failed") {
+		t.Fatalf("LandChangeset = %v, want a push failure", err)
+	}
+	if api.Member.State != beads.MemberRolledBack || client.Member.State != beads.MemberFailed {
+		t.Errorf("states = %s, %s; want rolled_back, failed", api.Member.State, client.Member.State)
+	}
+
+	// The api merge was pushed, then reverted on origin
+	if originHasFile(t, apiOrigin, "api.txt") {
+		t.Error("api change still on origin after rollback")
+	}
+	if msg := runGit(t, apiOrigin, "log", "-1", "--format=%s", "main"); !strings.HasPrefix(msg, "Revert") {
+		t.Errorf("api origin head = %q, want a revert", msg)
+	}
+	if got := runGit(t, clientOrigin, "rev-parse", "main"); got != clientBefore {
+		t.Error("client origin changed")
+	}
+	if got := runGit(t, client.WorkDir, "rev-parse", "HEAD"); got != clientBefore {
+		t.Error("client landing worktree kept its local merge")
+	}
+	if api.Member.Reverted == "" {
+		t.Error("pushed rollback not recorded on the api member")
+	}
+}
+
+func TestLandChangesetAfterPushedRollback(t *testing.T) {
+	root := t.TempDir()
+	api, apiOrigin := setupChangesetRig(t, root, "api")
+	client, clientOrigin := setupChangesetRig(t, root, "client")
+
+	// First landing: api is pushed, client is rejected, api is rolled back
+	hook := filepath.Join(clientOrigin, "hooks", "pre-receive")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := LandChangeset("hq-cs1", []*ChangesetLanding{api, client}, io.Discard); err == nil {
+		t.Fatal("first landing succeeded, want a push failure")
+	}
+	if originHasFile(t, apiOrigin, "api.txt") {
+		t.Fatal("api change still on origin after rollback")
+	}
+
+	// The api branch gains a commit and passes again; the client is fixed
+	runGit(t, api.WorkDir, "checkout", "polecat/nux")
+	writeAndCommit(t, api.WorkDir, "api2.txt", "more\n")
+	runGit(t, api.WorkDir, "push", "origin", "polecat/nux")
+	api.Member.Tested = runGit(t, api.WorkDir, "rev-parse", "HEAD")
+	runGit(t, api.WorkDir, "checkout", "--detach")
+	if err := os.Remove(hook); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LandChangeset("hq-cs1", []*ChangesetLanding{api, client}, io.Discard); err != nil {
+		t.Fatalf("re-landing: %v", err)
+	}
+	for _, l := range []*ChangesetLanding{api, client} {
+		if l.Member.State != beads.MemberMerged || l.Member.Reverted != "" {
+			t.Errorf("%s: member = %+v, want merged", l.Member.Rig, l.Member)
+		}
+	}
+	// Both the rolled-back change and the new commit are back on origin
+	for _, name := range []string{"api.txt", "api2.txt"} {
+		if !originHasFile(t, apiOrigin, name) {
+			t.Errorf("api origin lacks %s after re-landing", name)
+		}
+	}
+	if !originHasFile(t, clientOrigin, "client.txt") {
+		t.Error("client change not pushed")
+	}
+}
+
+func TestLandChangesetAlreadyMerged(t *testing.T) {
+	root := t.TempDir()
+	api, apiOrigin := setupChangesetRig(t, root, "api")
+
+	// The branch reached main outside the change set
+	runGit(t, api.WorkDir, "push", "origin", "polecat/nux:main")
+	before := runGit(t, apiOrigin, "rev-parse", "main")
+
+	err := LandChangeset("hq-cs1", []*ChangesetLanding{api}, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "already in main") {
+		t.Fatalf("LandChangeset = %v, want an already-merged failure", err)
+	}
+	if api.Member.State != beads.MemberFailed {
+		t.Errorf("state = %s, want failed", api.Member.State)
+	}
+	if got := runGit(t, apiOrigin, "rev-parse", "main"); got != before {
+		t.Error("api origin changed")
+	}
+}
+
+func TestChangesetWorkDir(t *testing.T) {
+	root := t.TempDir()
+	api, _ := setupChangesetRig(t, root, "api")
+	r := &rig.Rig{Name: "api", Path: filepath.Join(root, "api")}
+
+	dir, err := ChangesetWorkDir(r)
+	if err != nil {
+		t.Fatalf("ChangesetWorkDir: %v", err)
+	}
+	if dir != filepath.Join(r.Path, "refinery", "changeset") {
+		t.Errorf("dir = %s", dir)
+	}
+	if again, err := ChangesetWorkDir(r); err != nil || again != dir {
+		t.Errorf("second call = %s, %v", again, err)
+	}
+
+	// Landing through the worktree leaves the refinery's checkout alone
+	refineryHead := runGit(t, api.WorkDir, "rev-parse", "HEAD")
+	api.WorkDir = dir
+	if err := LandChangeset("hq-cs1", []*ChangesetLanding{api}, io.Discard); err != nil {
+		t.Fatalf("LandChangeset: %v", err)
+	}
+	clone := filepath.Join(r.Path, "refinery", "rig")
+	if got := runGit(t, clone, "rev-parse", "HEAD"); got != refineryHead {
+		t.Error("landing moved the refinery clone's HEAD")
+	}
+	if got := runGit(t, clone, "symbolic-ref", "--short", "HEAD"); got != "main" {
+		t.Errorf("refinery clone on %q, want main", got)
+	}
+}
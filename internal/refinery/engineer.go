@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
@@ -51,6 +53,14 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of MRs to process concurrently.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// Mode is "local" (merge and push the target branch) or "pull_request"
+	// (push the branch and land it through the forge).
+	Mode string `json:"mode"`
+
+	// Forge configures pull_request mode. Unset fields are derived from
+	// the origin remote.
+	Forge *config.ForgeConfig `json:"forge,omitempty"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -66,6 +76,7 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		RetryFlakyTests:      1,
 		PollInterval:         30 * time.Second,
 		MaxConcurrent:        1,
+		Mode:                 config.MergeModeLocal,
 	}
 }
 
@@ -86,6 +97,9 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	ChangesetID     string     // Cross-rig change set this MR lands with
+	PullRequest     string     // Pull request the MR is parked on (pull_request mode)
+	ChecksSince     *time.Time // When the MR was parked on pending forge checks
 }
 
 // Engineer is the merge queue processor that polls for ready merge-requests
@@ -98,24 +112,30 @@ type Engineer struct {
 	workDir string
 	output  io.Writer    // Output destination for user-facing messages
 	router  *mail.Router // Mail router for sending protocol messages
+	forge   forge.Forge  // Forge for pull_request mode, created on first use
 
 	// stopCh is used for graceful shutdown
 	stopCh chan struct{}
 }
 
+// WorkDir returns the git working directory for refinery operations.
+// Prefer refinery/rig worktree, fall back to mayor/rig (legacy architecture).
+// Using rig.Path directly would find town's .git with rig-named remotes instead of "origin".
+func WorkDir(r *rig.Rig) string {
+	gitDir := filepath.Join(r.Path, "refinery", "rig")
+	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
+		gitDir = filepath.Join(r.Path, "mayor", "rig")
+	}
+	return gitDir
+}
+
 // NewEngineer creates a new Engineer for the given rig.
 func NewEngineer(r *rig.Rig) *Engineer {
 	cfg := DefaultMergeQueueConfig()
 	// Override target branch with rig's configured default branch
 	cfg.TargetBranch = r.DefaultBranch()
 
-	// Determine the git working directory for refinery operations.
-	// Prefer refinery/rig worktree, fall back to mayor/rig (legacy architecture).
-	// Using rig.Path directly would find town's .git with rig-named remotes instead of "origin".
-	gitDir := filepath.Join(r.Path, "refinery", "rig")
-	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-		gitDir = filepath.Join(r.Path, "mayor", "rig")
-	}
+	gitDir := WorkDir(r)
 
 	return &Engineer{
 		rig:     r,
@@ -135,6 +155,12 @@ func (e *Engineer) SetOutput(w io.Writer) {
 	e.output = w
 }
 
+// SetForge sets the forge used in pull_request mode instead of one built
+// from the configuration. This is useful for testing.
+func (e *Engineer) SetForge(f forge.Forge) {
+	e.forge = f
+}
+
 // LoadConfig loads merge queue configuration from the rig's config.json.
 func (e *Engineer) LoadConfig() error {
 	configPath := filepath.Join(e.rig.Path, "config.json")
@@ -163,16 +189,18 @@ func (e *Engineer) LoadConfig() error {
 	// Parse merge_queue section into our config struct
 	// We need special handling for poll_interval (string -> Duration)
 	var mqRaw struct {
-		Enabled              *bool   `json:"enabled"`
-		TargetBranch         *string `json:"target_branch"`
-		IntegrationBranches  *bool   `json:"integration_branches"`
-		OnConflict           *string `json:"on_conflict"`
-		RunTests             *bool   `json:"run_tests"`
-		TestCommand          *string `json:"test_command"`
-		DeleteMergedBranches *bool   `json:"delete_merged_branches"`
-		RetryFlakyTests      *int    `json:"retry_flaky_tests"`
-		PollInterval         *string `json:"poll_interval"`
-		MaxConcurrent        *int    `json:"max_concurrent"`
+		Enabled              *bool               `json:"enabled"`
+		TargetBranch         *string             `json:"target_branch"`
+		IntegrationBranches  *bool               `json:"integration_branches"`
+		OnConflict           *string             `json:"on_conflict"`
+		RunTests             *bool               `json:"run_tests"`
+		TestCommand          *string             `json:"test_command"`
+		DeleteMergedBranches *bool               `json:"delete_merged_branches"`
+		RetryFlakyTests      *int                `json:"retry_flaky_tests"`
+		PollInterval         *string             `json:"poll_interval"`
+		MaxConcurrent        *int                `json:"max_concurrent"`
+		Mode                 *string             `json:"mode"`
+		Forge                *config.ForgeConfig `json:"forge"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -214,6 +242,23 @@ func (e *Engineer) LoadConfig() error {
 		}
 		e.config.PollInterval = dur
 	}
+	if mqRaw.Mode != nil {
+		switch *mqRaw.Mode {
+		case config.MergeModeLocal, config.MergeModePullRequest:
+			e.config.Mode = *mqRaw.Mode
+		default:
+			return fmt.Errorf("invalid mode %q: want %q or %q", *mqRaw.Mode, config.MergeModeLocal, config.MergeModePullRequest)
+		}
+	}
+	if mqRaw.Forge != nil {
+		e.config.Forge = mqRaw.Forge
+		if _, err := e.checkTimeout(); err != nil {
+			return err
+		}
+		if _, err := e.checkGrace(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -230,6 +275,11 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
+
+	// Parked means the MR's pull request is waiting on checks. The MR stays
+	// in the queue and is polled again on the next patrol (pull_request mode).
+	Parked      bool
+	PullRequest string // URL of the pull request (pull_request mode)
 }
 
 // ProcessMR processes a single merge request from a beads issue.
@@ -242,6 +292,9 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 			Error:   "no MR fields found in description",
 		}
 	}
+	if mrFields.ChangesetID != "" {
+		return changesetMemberResult(mr.ID, mrFields.ChangesetID)
+	}
 
 	// Log what we're processing
 	_, _ = fmt.Fprintln(e.output, "[Engineer] Processing MR:")
@@ -249,12 +302,19 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mrFields.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mrFields.Worker)
 
+	if e.config.Mode == config.MergeModePullRequest {
+		return e.doPullRequestMerge(ctx, mrFields.Branch, mrFields.Target, mrFields.SourceIssue, mrFields.PullRequest, ParseChecksSince(mrFields.ChecksSince))
+	}
 	return e.doMerge(ctx, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
 }
 
 // doMerge performs the actual git merge operation.
 // This is the core merge logic shared by ProcessMR and ProcessMRFromQueue.
 func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+	if e.config.Mode == config.MergeModePullRequest {
+		return e.doPullRequestMerge(ctx, branch, target, sourceIssue, "", nil)
+	}
+
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
 	exists, err := e.git.BranchExists(branch)
@@ -495,6 +555,10 @@ func (e *Engineer) handleFailure(mr *beads.Issue, result ProcessResult) {
 
 // ProcessMRInfo processes a merge request from MRInfo.
 func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult {
+	if mr.ChangesetID != "" {
+		return changesetMemberResult(mr.ID, mr.ChangesetID)
+	}
+
 	// MR fields are directly on the struct
 	_, _ = fmt.Fprintln(e.output, "[Engineer] Processing MR:")
 	_, _ = fmt.Fprintf(e.output, "  Branch: %s\n", mr.Branch)
@@ -502,10 +566,22 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
+	if e.config.Mode == config.MergeModePullRequest {
+		return e.doPullRequestMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue, mr.PullRequest, mr.ChecksSince)
+	}
 	// Use the shared merge logic
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
 }
 
+// changesetMemberResult refuses to merge a change set member on its own.
+// Members are held with gt mq changeset pass and land together.
+func changesetMemberResult(mrID, changesetID string) ProcessResult {
+	return ProcessResult{
+		Success: false,
+		Error:   fmt.Sprintf("MR %s is in change set %s; hold it with gt mq changeset pass and land the change set instead", mrID, changesetID),
+	}
+}
+
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
 func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	// Release merge slot if this was a conflict resolution
@@ -616,6 +692,13 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	// A failed MR comes back after rework with a fresh check timeout
+	if mr.PullRequest != "" || mr.ChecksSince != nil {
+		if err := e.setParked(mr.ID, "", ""); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to unpark MR %s: %v\n", mr.ID, err)
+		}
+	}
+
 	// Log the failure - MR stays in queue but may be blocked
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
 	if mr.BlockedBy != "" {
@@ -625,6 +708,40 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// ParkMR records that an MR is waiting on its pull request's checks
+// (pull_request mode). The MR stays in the queue and the next patrol polls
+// it again; the check timeout runs from the first time it was parked.
+func (e *Engineer) ParkMR(mr *MRInfo, result ProcessResult) error {
+	since := time.Now().UTC()
+	if mr.ChecksSince != nil {
+		since = *mr.ChecksSince
+	}
+	if err := e.setParked(mr.ID, result.PullRequest, since.Format(time.RFC3339)); err != nil {
+		return err
+	}
+	mr.PullRequest = result.PullRequest
+	mr.ChecksSince = &since
+	_, _ = fmt.Fprintf(e.output, "[Engineer] ⏸ Parked: %s - %s\n", mr.ID, result.Error)
+	return nil
+}
+
+// setParked writes the pull_request and checks_since fields of an MR bead.
+// Empty values clear them.
+func (e *Engineer) setParked(mrID, pullRequest, checksSince string) error {
+	mrBead, err := e.beads.Show(mrID)
+	if err != nil {
+		return err
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	mrFields.PullRequest = pullRequest
+	mrFields.ChecksSince = checksSince
+	newDesc := beads.SetMRFields(mrBead, mrFields)
+	return e.beads.Update(mrID, beads.UpdateOptions{Description: &newDesc})
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -785,6 +902,11 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			continue
 		}
 
+		// Skip MRs held for their change set; they land together later
+		if beads.HasLabel(issue, beads.LabelChangesetHeld) {
+			continue
+		}
+
 		// Parse convoy created_at if present
 		var convoyCreatedAt *time.Time
 		if fields.ConvoyCreatedAt != "" {
@@ -802,6 +924,8 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 		}
 
 		mr := &MRInfo{
+			PullRequest:     fields.PullRequest,
+			ChecksSince:     ParseChecksSince(fields.ChecksSince),
 			ID:              issue.ID,
 			Branch:          fields.Branch,
 			Target:          fields.Target,
@@ -815,6 +939,7 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			ConvoyID:        fields.ConvoyID,
 			ConvoyCreatedAt: convoyCreatedAt,
 			CreatedAt:       createdAt,
+			ChangesetID:     fields.ChangesetID,
 		}
 		mrs = append(mrs, mr)
 	}
@@ -890,6 +1015,8 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 		}
 
 		mr := &MRInfo{
+			PullRequest:     fields.PullRequest,
+			ChecksSince:     ParseChecksSince(fields.ChecksSince),
 			ID:              issue.ID,
 			Branch:          fields.Branch,
 			Target:          fields.Target,
@@ -904,6 +1031,7 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 			ConvoyCreatedAt: convoyCreatedAt,
 			CreatedAt:       createdAt,
 			BlockedBy:       blockedBy,
+			ChangesetID:     fields.ChangesetID,
 		}
 		mrs = append(mrs, mr)
 	}
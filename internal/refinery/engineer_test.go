@@ -1,9 +1,12 @@
 package refinery
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -186,6 +189,49 @@ func TestEngineer_LoadConfig_InvalidPollInterval(t *testing.T) {
 	}
 }
 
+func TestEngineer_LoadConfig_PullRequestMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := map[string]interface{}{
+		"merge_queue": map[string]interface{}{
+			"mode": "pull_request",
+			"forge": map[string]interface{}{
+				"type":          "gitea",
+				"api_url":       "https://git.example.com/api/v1",
+				"merge_method":  "squash",
+				"check_timeout": "10m",
+			},
+		},
+	}
+	data, _ := json.MarshalIndent(config, "", "  ")
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if e.config.Mode != "pull_request" {
+		t.Errorf("Mode = %q, want pull_request", e.config.Mode)
+	}
+	if e.config.Forge == nil || e.config.Forge.Type != "gitea" || e.config.Forge.MergeMethod != "squash" {
+		t.Errorf("Forge = %+v", e.config.Forge)
+	}
+	if timeout, err := e.checkTimeout(); err != nil || timeout != 10*time.Minute {
+		t.Errorf("checkTimeout = %v, %v", timeout, err)
+	}
+
+	// Unknown modes are rejected rather than silently merging locally
+	config["merge_queue"] = map[string]interface{}{"mode": "pr"}
+	data, _ = json.MarshalIndent(config, "", "  ")
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
+
 func TestNewEngineer(t *testing.T) {
 	r := &rig.Rig{
 		Name: "test-rig",
@@ -208,6 +254,21 @@ func TestNewEngineer(t *testing.T) {
 	}
 }
 
+func TestEngineer_ProcessMRInfoRefusesChangesetMember(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.output = &bytes.Buffer{}
+
+	result := e.ProcessMRInfo(context.Background(), &MRInfo{
+		ID:          "gt-mr1",
+		Branch:      "polecat/nux/gt-1",
+		Target:      "main",
+		ChangesetID: "hq-cs1",
+	})
+	if result.Success || !strings.Contains(result.Error, "hq-cs1") {
+		t.Errorf("ProcessMRInfo = %+v, want refusal naming the change set", result)
+	}
+}
+
 func TestEngineer_DeleteMergedBranchesConfig(t *testing.T) {
 	// Test that DeleteMergedBranches is true by default
 	cfg := DefaultMergeQueueConfig()
@@ -0,0 +1,275 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// Defaults for waiting on forge checks in pull_request mode.
+const (
+	// DefaultCheckTimeout bounds how long an MR stays parked on pending checks.
+	DefaultCheckTimeout = 30 * time.Minute
+	// DefaultCheckGrace is how long a pull request without any checks waits
+	// for CI to register them before it merges without checks.
+	DefaultCheckGrace = 5 * time.Minute
+)
+
+// checkTimeout returns how long an MR may wait on forge checks.
+func (e *Engineer) checkTimeout() (time.Duration, error) {
+	fc := e.config.Forge
+	if fc == nil || fc.CheckTimeout == "" {
+		return DefaultCheckTimeout, nil
+	}
+	timeout, err := time.ParseDuration(fc.CheckTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid forge check_timeout %q: %w", fc.CheckTimeout, err)
+	}
+	return timeout, nil
+}
+
+// checkGrace returns how long a pull request without checks waits for them.
+func (e *Engineer) checkGrace() (time.Duration, error) {
+	fc := e.config.Forge
+	if fc == nil || fc.CheckGrace == "" {
+		return DefaultCheckGrace, nil
+	}
+	grace, err := time.ParseDuration(fc.CheckGrace)
+	if err != nil {
+		return 0, fmt.Errorf("invalid forge check_grace %q: %w", fc.CheckGrace, err)
+	}
+	return grace, nil
+}
+
+// pullRequestNumber returns the number at the end of a pull request URL
+// (GitHub /pull/N, Gitea /pulls/N, GitLab /merge_requests/N), or 0.
+func pullRequestNumber(url string) int {
+	n, err := strconv.Atoi(path.Base(strings.TrimRight(url, "/")))
+	if err != nil || url == "" {
+		return 0
+	}
+	return n
+}
+
+// ParseChecksSince parses the checks_since MR field, returning nil when the
+// MR isn't parked.
+func ParseChecksSince(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// resolveForge returns the forge for pull_request mode, building it from the
+// merge_queue.forge config on first use. Missing type, repo and API URL are
+// derived from the origin remote.
+func (e *Engineer) resolveForge() (forge.Forge, error) {
+	if e.forge != nil {
+		return e.forge, nil
+	}
+
+	fc := e.config.Forge
+	if fc == nil {
+		fc = &config.ForgeConfig{}
+	}
+	cfg := forge.Config{
+		Type:        fc.Type,
+		APIURL:      fc.APIURL,
+		Repo:        fc.Repo,
+		MergeMethod: fc.MergeMethod,
+	}
+	if cfg.Type == "" || cfg.Repo == "" || cfg.APIURL == "" {
+		remote, err := e.git.RemoteURL("origin")
+		if err != nil {
+			return nil, fmt.Errorf("reading origin remote: %w", err)
+		}
+		host, repo, ok := forge.ParseRemote(remote)
+		if !ok {
+			return nil, fmt.Errorf("can't derive forge from origin %q: set merge_queue.forge", remote)
+		}
+		if cfg.Repo == "" {
+			cfg.Repo = repo
+		}
+		if cfg.Type == "" {
+			if cfg.Type = forge.Detect(host); cfg.Type == "" {
+				return nil, fmt.Errorf("unknown forge at %s: set merge_queue.forge.type", host)
+			}
+		}
+		if cfg.APIURL == "" {
+			cfg.APIURL = forge.DefaultAPIURL(cfg.Type, host)
+		}
+	}
+	cfg.Token = forge.TokenFromEnv(cfg.Type, fc.TokenEnv)
+
+	f, err := forge.New(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	e.forge = f
+	return f, nil
+}
+
+// doPullRequestMerge lands branch through the forge: push it, open (or reuse)
+// a pull request carrying the bead context, check the required checks once
+// and merge through the API. The target branch is never pushed directly, so
+// protected branches work; the forge's checks replace the local test run.
+//
+// Pending checks park the MR instead of blocking the patrol: the result has
+// Parked set and the caller re-polls on a later cycle. pullRequest and
+// checksSince come from the parked MR (empty and nil if never parked); once
+// check_timeout has passed since checksSince, pending checks fail the MR.
+func (e *Engineer) doPullRequestMerge(ctx context.Context, branch, target, sourceIssue, pullRequest string, checksSince *time.Time) ProcessResult {
+	f, err := e.resolveForge()
+	if err != nil {
+		return ProcessResult{Success: false, Error: fmt.Sprintf("forge: %v", err)}
+	}
+	timeout, err := e.checkTimeout()
+	if err != nil {
+		return ProcessResult{Success: false, Error: err.Error()}
+	}
+	grace, err := e.checkGrace()
+	if err != nil {
+		return ProcessResult{Success: false, Error: err.Error()}
+	}
+
+	// Step 1: Verify source branch exists locally and publish it
+	exists, err := e.git.BranchExists(branch)
+	if err != nil {
+		return ProcessResult{Success: false, Error: fmt.Sprintf("failed to check branch %s: %v", branch, err)}
+	}
+	if !exists {
+		return ProcessResult{Success: false, Error: fmt.Sprintf("branch %s not found locally", branch)}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing %s to origin...\n", branch)
+	if err := e.git.Push("origin", branch, false); err != nil {
+		return ProcessResult{Success: false, Error: fmt.Sprintf("failed to push %s to origin: %v", branch, err)}
+	}
+
+	// Step 2: Reuse the pull request from an earlier attempt, or open one
+	var pr *forge.PullRequest
+	if number := pullRequestNumber(pullRequest); number > 0 {
+		parked, err := f.Get(ctx, number)
+		if err != nil {
+			return ProcessResult{Success: false, Error: fmt.Sprintf("reading pull request #%d: %v", number, err)}
+		}
+		if parked.State == forge.StateMerged {
+			// Merged by someone else while the MR was parked
+			return e.pullRequestMerged(parked, parked.MergeCommit)
+		}
+		if parked.State == forge.StateOpen {
+			pr = parked
+		}
+	}
+	if pr == nil {
+		pr, err = f.FindOpen(ctx, branch, target)
+		if err != nil {
+			return ProcessResult{Success: false, Error: fmt.Sprintf("finding pull request: %v", err)}
+		}
+	}
+	if pr == nil {
+		title, body := e.pullRequestText(branch, target, sourceIssue)
+		pr, err = f.Open(ctx, forge.NewPullRequest{Head: branch, Base: target, Title: title, Body: body})
+		if err != nil {
+			return ProcessResult{Success: false, Error: fmt.Sprintf("opening pull request: %v", err)}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Opened pull request #%d: %s\n", pr.Number, pr.URL)
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Using open pull request #%d: %s\n", pr.Number, pr.URL)
+	}
+
+	// Step 3: Check the required checks, parking the MR while they run
+	if !pr.Conflicted {
+		state, err := f.Checks(ctx, pr)
+		if err != nil {
+			return ProcessResult{Success: false, Error: fmt.Sprintf("checking pull request #%d: %v", pr.Number, err)}
+		}
+		switch state {
+		case forge.ChecksFailure:
+			return ProcessResult{Success: false, TestsFailed: true, Error: fmt.Sprintf("checks failed on pull request #%d: %s", pr.Number, pr.URL)}
+		case forge.ChecksNone:
+			// Right after a push CI may not have registered its checks yet;
+			// only merge without checks once the grace period has passed
+			if checksSince == nil || time.Since(*checksSince) <= grace {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] No checks reported on #%d yet; parking MR\n", pr.Number)
+				return ProcessResult{Success: false, Parked: true, PullRequest: pr.URL, Error: fmt.Sprintf("no checks reported yet on pull request #%d", pr.Number)}
+			}
+			_, _ = fmt.Fprintf(e.output, "[Engineer] No checks reported on #%d after %s; merging without checks\n", pr.Number, grace)
+		case forge.ChecksPending:
+			if checksSince != nil && time.Since(*checksSince) > timeout {
+				return ProcessResult{Success: false, Error: fmt.Sprintf("checks on pull request #%d still pending after %s", pr.Number, timeout)}
+			}
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Checks pending on #%d; parking MR\n", pr.Number)
+			return ProcessResult{Success: false, Parked: true, PullRequest: pr.URL, Error: fmt.Sprintf("checks pending on pull request #%d", pr.Number)}
+		}
+	}
+	if pr.Conflicted {
+		return ProcessResult{Success: false, Conflict: true, Error: fmt.Sprintf("pull request #%d conflicts with %s", pr.Number, target)}
+	}
+
+	// Step 4: Merge through the forge
+	mergeMsg := fmt.Sprintf("Merge %s into %s", branch, target)
+	if sourceIssue != "" {
+		mergeMsg = fmt.Sprintf("Merge %s into %s (%s)", branch, target, sourceIssue)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merging pull request #%d...\n", pr.Number)
+	mergeCommit, err := f.Merge(ctx, pr, mergeMsg)
+	if errors.Is(err, forge.ErrNotMergeable) {
+		// Tell conflicts (back to the polecat) from blocked merges (retry later)
+		if latest, getErr := f.Get(ctx, pr.Number); getErr == nil && latest.Conflicted {
+			return ProcessResult{Success: false, Conflict: true, Error: fmt.Sprintf("pull request #%d conflicts with %s", pr.Number, target)}
+		}
+	}
+	if err != nil {
+		return ProcessResult{Success: false, Error: fmt.Sprintf("merging pull request #%d: %v", pr.Number, err)}
+	}
+	return e.pullRequestMerged(pr, mergeCommit)
+}
+
+// pullRequestMerged refreshes origin after a forge merge and reports success.
+func (e *Engineer) pullRequestMerged(pr *forge.PullRequest, mergeCommit string) ProcessResult {
+	if err := e.git.Fetch("origin"); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin: %v (continuing)\n", err)
+	}
+	short := mergeCommit
+	if len(short) > 8 {
+		short = short[:8]
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged pull request #%d: %s\n", pr.Number, short)
+	return ProcessResult{Success: true, MergeCommit: mergeCommit}
+}
+
+// pullRequestText builds the pull request title and body from the source
+// bead, falling back to the branch names when the bead can't be read.
+func (e *Engineer) pullRequestText(branch, target, sourceIssue string) (title, body string) {
+	title = fmt.Sprintf("Merge %s into %s", branch, target)
+	var sb strings.Builder
+	if sourceIssue != "" {
+		if issue, err := e.beads.Show(sourceIssue); err == nil && issue != nil {
+			title = fmt.Sprintf("%s: %s", sourceIssue, issue.Title)
+			if desc := strings.TrimSpace(issue.Description); desc != "" {
+				sb.WriteString(desc)
+				sb.WriteString("\n\n---\n\n")
+			}
+		} else {
+			title = fmt.Sprintf("%s (%s)", title, sourceIssue)
+		}
+		fmt.Fprintf(&sb, "Bead: `%s`\n", sourceIssue)
+	}
+	fmt.Fprintf(&sb, "Branch: `%s`\n", branch)
+	if e.rig != nil && e.rig.Name != "" {
+		fmt.Fprintf(&sb, "Rig: `%s`\n", e.rig.Name)
+	}
+	sb.WriteString("\nOpened by the Gas Town refinery; it merges once the required checks pass.\n")
+	return title, sb.String()
+}
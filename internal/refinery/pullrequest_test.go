@@ -0,0 +1,228 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/forge/forgetest"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// setupPullRequestEngineer creates a refinery clone with a bare origin and a
+// polecat branch, wired to a fake forge in pull_request mode.
+func setupPullRequestEngineer(t *testing.T) (*Engineer, *forgetest.Server, string) {
+	t.Helper()
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	clone := filepath.Join(root, "refinery", "rig")
+	runGit(t, root, "init", "--bare", "-b", "main", origin)
+	runGit(t, root, "clone", origin, clone)
+	runGit(t, clone, "config", "user.email", "test@test.com")
+	runGit(t, clone, "config", "user.name", "Test")
+	writeAndCommit(t, clone, "README.md", "base\n")
+	runGit(t, clone, "push", "origin", "main")
+	runGit(t, clone, "checkout", "-b", "polecat/nux")
+	writeAndCommit(t, clone, "fix.txt", "fixed\n")
+	runGit(t, clone, "checkout", "main")
+
+	srv := forgetest.NewServer("acme/widgets")
+	t.Cleanup(srv.Close)
+	srv.HeadSHA = func(branch string) string {
+		return runGit(t, origin, "rev-parse", branch)
+	}
+	f, err := forge.New(forge.Config{Type: forge.TypeGitHub, APIURL: srv.URL, Repo: "acme/widgets"}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "widgets", Path: root})
+	e.git = git.NewGit(clone)
+	e.workDir = clone
+	e.SetOutput(&bytes.Buffer{})
+	e.SetForge(f)
+	e.config.Mode = config.MergeModePullRequest
+	e.config.Forge = &config.ForgeConfig{CheckTimeout: "2s"}
+	return e, srv, origin
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeAndCommit(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", "add "+name)
+}
+
+func TestPullRequestMerge(t *testing.T) {
+	e, srv, origin := setupPullRequestEngineer(t)
+
+	result := e.doMerge(context.Background(), "polecat/nux", "main", "gt-abc")
+	if !result.Success || result.MergeCommit != "merge-1" {
+		t.Fatalf("doMerge = %+v, want success with merge-1", result)
+	}
+
+	prs := srv.PRs()
+	if len(prs) != 1 {
+		t.Fatalf("forge has %d pull requests, want 1", len(prs))
+	}
+	pr := prs[0]
+	if !pr.Merged || pr.Head != "polecat/nux" || pr.Base != "main" {
+		t.Errorf("pull request = %+v", pr)
+	}
+	if !strings.Contains(pr.Title, "gt-abc") || !strings.Contains(pr.Body, "Bead: `gt-abc`") {
+		t.Errorf("pull request lacks bead context: %q / %q", pr.Title, pr.Body)
+	}
+	// The branch was published, but the target was never pushed directly
+	if got := runGit(t, origin, "rev-parse", "polecat/nux"); got != pr.HeadSHA {
+		t.Errorf("origin polecat/nux = %s, want %s", got, pr.HeadSHA)
+	}
+	if strings.Contains(runGit(t, origin, "log", "--format=%s", "main"), "fix.txt") {
+		t.Error("refinery pushed to main directly")
+	}
+}
+
+func TestPullRequestMergeChecksFail(t *testing.T) {
+	e, srv, _ := setupPullRequestEngineer(t)
+	srv.SetChecks("", forge.ChecksFailure)
+
+	result := e.doMerge(context.Background(), "polecat/nux", "main", "")
+	if result.Success || !result.TestsFailed {
+		t.Fatalf("doMerge = %+v, want TestsFailed", result)
+	}
+
+	// A retry after a fix reuses the open pull request
+	srv.SetChecks("", forge.ChecksSuccess)
+	if result := e.doMerge(context.Background(), "polecat/nux", "main", ""); !result.Success {
+		t.Fatalf("retry = %+v, want success", result)
+	}
+	if n := len(srv.PRs()); n != 1 {
+		t.Errorf("forge has %d pull requests after retry, want 1", n)
+	}
+}
+
+func TestPullRequestMergeConflict(t *testing.T) {
+	e, srv, _ := setupPullRequestEngineer(t)
+	ctx := context.Background()
+
+	// An earlier attempt left a pull request the forge says conflicts
+	runGit(t, e.workDir, "push", "origin", "polecat/nux")
+	pr, err := e.forge.Open(ctx, forge.NewPullRequest{Head: "polecat/nux", Base: "main", Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.SetDirty(pr.Number, true)
+
+	result := e.doMerge(ctx, "polecat/nux", "main", "")
+	if result.Success || !result.Conflict {
+		t.Fatalf("doMerge = %+v, want Conflict", result)
+	}
+}
+
+func TestPullRequestMergeParksOnPendingChecks(t *testing.T) {
+	e, srv, _ := setupPullRequestEngineer(t)
+	ctx := context.Background()
+	srv.SetChecks("", forge.ChecksPending)
+
+	result := e.doPullRequestMerge(ctx, "polecat/nux", "main", "", "", nil)
+	if result.Success || !result.Parked || result.TestsFailed || result.Conflict {
+		t.Fatalf("doPullRequestMerge = %+v, want Parked", result)
+	}
+	if pullRequestNumber(result.PullRequest) != 1 {
+		t.Errorf("PullRequest = %q, want the URL of #1", result.PullRequest)
+	}
+
+	// The next patrol polls the parked pull request again and merges it
+	since := time.Now()
+	srv.SetChecks("", forge.ChecksSuccess)
+	result = e.doPullRequestMerge(ctx, "polecat/nux", "main", "", result.PullRequest, &since)
+	if !result.Success {
+		t.Fatalf("re-poll = %+v, want success", result)
+	}
+	if n := len(srv.PRs()); n != 1 {
+		t.Errorf("forge has %d pull requests after re-poll, want 1", n)
+	}
+
+	// A parked pull request someone else merged counts as merged
+	result = e.doPullRequestMerge(ctx, "polecat/nux", "main", "", srv.URL+"/acme/widgets/pull/1", &since)
+	if !result.Success || result.MergeCommit != "merge-1" {
+		t.Fatalf("already merged = %+v, want success with merge-1", result)
+	}
+}
+
+func TestPullRequestMergeChecksTimeout(t *testing.T) {
+	e, srv, _ := setupPullRequestEngineer(t)
+	srv.SetChecks("", forge.ChecksPending)
+	e.config.Forge.CheckTimeout = "50ms"
+
+	// Parked longer than check_timeout ago
+	since := time.Now().Add(-time.Minute)
+	result := e.doPullRequestMerge(context.Background(), "polecat/nux", "main", "", "", &since)
+	if result.Success || result.Parked || result.TestsFailed || result.Conflict || !strings.Contains(result.Error, "still pending") {
+		t.Fatalf("doPullRequestMerge = %+v, want pending timeout", result)
+	}
+}
+
+func TestPullRequestMergeWaitsForChecksToRegister(t *testing.T) {
+	e, srv, _ := setupPullRequestEngineer(t)
+	ctx := context.Background()
+	srv.SetChecks("", forge.ChecksNone)
+	e.config.Forge.CheckGrace = "1m"
+
+	// No checks yet right after the push: park instead of merging
+	result := e.doPullRequestMerge(ctx, "polecat/nux", "main", "", "", nil)
+	if result.Success || !result.Parked || !strings.Contains(result.Error, "no checks reported") {
+		t.Fatalf("doPullRequestMerge = %+v, want Parked", result)
+	}
+	since := time.Now()
+	result = e.doPullRequestMerge(ctx, "polecat/nux", "main", "", result.PullRequest, &since)
+	if result.Success || !result.Parked {
+		t.Fatalf("within grace = %+v, want Parked", result)
+	}
+
+	// Still no checks after check_grace: the repo has no CI, merge
+	since = time.Now().Add(-2 * time.Minute)
+	result = e.doPullRequestMerge(ctx, "polecat/nux", "main", "", result.PullRequest, &since)
+	if !result.Success {
+		t.Fatalf("after grace = %+v, want success", result)
+	}
+}
+
+func TestResolveForgeFromOrigin(t *testing.T) {
+	e, _, _ := setupPullRequestEngineer(t)
+	e.forge = nil
+	runGit(t, e.workDir, "remote", "set-url", "origin", "git@github.com:acme/widgets.git")
+	if _, err := e.resolveForge(); err != nil {
+		t.Fatalf("resolveForge: %v", err)
+	}
+
+	e.forge = nil
+	runGit(t, e.workDir, "remote", "set-url", "origin", "https://git.example.com/acme/widgets.git")
+	if _, err := e.resolveForge(); err == nil {
+		t.Error("expected error for an unknown forge host without merge_queue.forge.type")
+	}
+	e.config.Forge.Type = forge.TypeGitea
+	if _, err := e.resolveForge(); err != nil {
+		t.Fatalf("resolveForge with type: %v", err)
+	}
+}
@@ -283,7 +283,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 
 	// Derive defaults
 	if opts.BeadsPrefix == "" {
-		opts.BeadsPrefix = deriveBeadsPrefix(opts.Name)
+		opts.BeadsPrefix = DeriveBeadsPrefix(opts.Name)
 	}
 
 	localRepo, warn := resolveLocalRepo(opts.LocalRepo, opts.GitURL)
@@ -802,9 +802,9 @@ func (m *Manager) ensureGitignoreEntry(gitignorePath, entry string) error {
 	return err
 }
 
-// deriveBeadsPrefix generates a beads prefix from a rig name.
+// DeriveBeadsPrefix generates a beads prefix from a rig name.
 // Examples: "gastown" -> "gt", "my-project" -> "mp", "foo" -> "foo"
-func deriveBeadsPrefix(name string) string {
+func DeriveBeadsPrefix(name string) string {
 	// Remove common suffixes
 	name = strings.TrimSuffix(name, "-py")
 	name = strings.TrimSuffix(name, "-go")
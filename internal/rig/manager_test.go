@@ -640,9 +640,9 @@ func TestDeriveBeadsPrefix(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := deriveBeadsPrefix(tt.name)
+			got := DeriveBeadsPrefix(tt.name)
 			if got != tt.want {
-				t.Errorf("deriveBeadsPrefix(%q) = %q, want %q", tt.name, got, tt.want)
+				t.Errorf("DeriveBeadsPrefix(%q) = %q, want %q", tt.name, got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,307 @@
+package rig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// TemplateManifest is the file that marks a directory as a rig template.
+const TemplateManifest = "template.json"
+
+// Template variables, replaced in every text file when a template is applied.
+const (
+	VarRigName   = "{{rig.name}}"
+	VarRigPrefix = "{{rig.prefix}}"
+)
+
+// ErrTemplateNotFound is returned when a named template doesn't exist.
+var ErrTemplateNotFound = errors.New("rig template not found")
+
+// templateParts maps the parts of a template to where they land in a rig.
+// A template is laid out as:
+//
+//	<template>/
+//	  template.json     Name and description
+//	  settings/         -> <rig>/settings/ (config.json is RigSettings)
+//	  overlay/          -> <rig>/.runtime/overlay/
+//	  setup-hooks/      -> <rig>/.runtime/setup-hooks/
+//	  plugins/          -> <rig>/plugins/
+//	  formulas/         -> <rig>/.beads/formulas/ (following a beads redirect)
+var templateParts = []templatePart{
+	{dir: constants.DirSettings, dest: constants.DirSettings},
+	{dir: "overlay", dest: filepath.Join(constants.DirRuntime, "overlay")},
+	{dir: "setup-hooks", dest: filepath.Join(constants.DirRuntime, "setup-hooks")},
+	{dir: "plugins", dest: "plugins"},
+	{dir: "formulas", dest: "formulas", inBeads: true},
+}
+
+type templatePart struct {
+	dir     string
+	dest    string
+	inBeads bool // dest is in the rig's beads directory
+}
+
+// path returns where the part lives in the rig at rigPath. For tracked
+// beads <rig>/.beads is only a redirect, so beads parts follow it.
+func (p templatePart) path(rigPath string) string {
+	if p.inBeads {
+		return filepath.Join(beads.ResolveBeadsDir(rigPath), p.dest)
+	}
+	return filepath.Join(rigPath, p.dest)
+}
+
+// Template is a reusable rig setup: settings, overlay files, setup hooks,
+// plugins and formulas.
+type Template struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedFrom string    `json:"created_from,omitempty"` // rig the template was saved from
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+
+	// Dir is the template directory.
+	Dir string `json:"-"`
+}
+
+// TemplateVars are the values substituted into a template.
+type TemplateVars struct {
+	Name   string // rig name
+	Prefix string // beads prefix, without the trailing hyphen
+}
+
+func (v TemplateVars) replacer() *strings.Replacer {
+	return strings.NewReplacer(VarRigName, v.Name, VarRigPrefix, v.Prefix)
+}
+
+// TemplatesDir returns the directory holding the town's named rig templates.
+func TemplatesDir(townRoot string) string {
+	return filepath.Join(townRoot, "templates", "rigs")
+}
+
+// ResolveTemplate finds a template by name (under TemplatesDir) or by path.
+func ResolveTemplate(townRoot, ref string) (*Template, error) {
+	if !strings.ContainsRune(ref, filepath.Separator) && ref != "." && ref != ".." {
+		dir := filepath.Join(TemplatesDir(townRoot), ref)
+		if _, err := os.Stat(filepath.Join(dir, TemplateManifest)); err == nil {
+			return LoadTemplate(dir)
+		}
+		if _, err := os.Stat(ref); err != nil {
+			return nil, fmt.Errorf("%w: %s (looked in %s)", ErrTemplateNotFound, ref, TemplatesDir(townRoot))
+		}
+	}
+	return LoadTemplate(ref)
+}
+
+// LoadTemplate reads the template in dir.
+func LoadTemplate(dir string) (*Template, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(abs, TemplateManifest)) //nolint:gosec // G304: template path is chosen by the user
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: no %s in %s", ErrTemplateNotFound, TemplateManifest, dir)
+		}
+		return nil, err
+	}
+	var t Template
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(dir, TemplateManifest), err)
+	}
+	if t.Name == "" {
+		t.Name = filepath.Base(abs)
+	}
+	t.Dir = abs
+	return &t, nil
+}
+
+// ListTemplates returns the town's named templates, sorted by name.
+func ListTemplates(townRoot string) ([]*Template, error) {
+	entries, err := os.ReadDir(TemplatesDir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*Template
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		t, err := LoadTemplate(filepath.Join(TemplatesDir(townRoot), e.Name()))
+		if err != nil {
+			continue // not a template
+		}
+		t.Name = e.Name()
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Validate checks that the template's rig settings are valid once vars are
+// substituted, so a bad template fails before a rig is created.
+func (t *Template) Validate(vars TemplateVars) error {
+	path := filepath.Join(t.Dir, constants.DirSettings, constants.FileConfigJSON)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: template path is chosen by the user
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := config.Validate(config.KindRigSettings, []byte(vars.replacer().Replace(string(data)))); err != nil {
+		return fmt.Errorf("template %s: settings/config.json: %w", t.Name, err)
+	}
+	return nil
+}
+
+// Apply copies the template into the rig at rigPath, substituting vars in
+// text files and preserving file modes. Existing files are overwritten. It
+// returns the rig-relative paths written.
+func (t *Template) Apply(rigPath string, vars TemplateVars) ([]string, error) {
+	if err := t.Validate(vars); err != nil {
+		return nil, err
+	}
+	r := vars.replacer()
+	var written []string
+	for _, part := range templateParts {
+		src := filepath.Join(t.Dir, part.dir)
+		destDir := part.path(rigPath)
+		err := walkFiles(src, func(rel string, info fs.FileInfo, data []byte) error {
+			if isText(data) {
+				data = []byte(r.Replace(string(data)))
+			}
+			dest := filepath.Join(destDir, rel)
+			if err := writeTemplateFile(dest, data, info.Mode().Perm()); err != nil {
+				return err
+			}
+			if relDest, err := filepath.Rel(rigPath, dest); err == nil {
+				dest = relDest
+			}
+			written = append(written, dest)
+			return nil
+		})
+		if err != nil {
+			return written, fmt.Errorf("applying %s: %w", part.dir, err)
+		}
+	}
+	return written, nil
+}
+
+// SaveTemplate captures the rig at rigPath as a template in dest. With
+// parameterize, whole-word occurrences of the rig name and of bead IDs with
+// its prefix are replaced by template variables.
+func SaveTemplate(rigPath, dest string, t Template, vars TemplateVars, parameterize bool) (*Template, error) {
+	if _, err := os.Stat(dest); err == nil {
+		return nil, fmt.Errorf("template directory already exists: %s", dest)
+	}
+
+	var rewrite func(string) string
+	if parameterize {
+		rewrite = parameterizer(vars)
+	}
+	for _, part := range templateParts {
+		src := part.path(rigPath)
+		err := walkFiles(src, func(rel string, info fs.FileInfo, data []byte) error {
+			if rewrite != nil && isText(data) {
+				data = []byte(rewrite(string(data)))
+			}
+			return writeTemplateFile(filepath.Join(dest, part.dir, rel), data, info.Mode().Perm())
+		})
+		if err != nil {
+			_ = os.RemoveAll(dest)
+			return nil, fmt.Errorf("saving %s: %w", part.dir, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err == nil {
+		err = writeTemplateFile(filepath.Join(dest, TemplateManifest), append(data, '\n'), 0644)
+	}
+	if err != nil {
+		_ = os.RemoveAll(dest)
+		return nil, err
+	}
+	return LoadTemplate(dest)
+}
+
+// parameterizer returns a function replacing the rig's name and bead ID
+// prefix with template variables.
+func parameterizer(vars TemplateVars) func(string) string {
+	var patterns []*regexp.Regexp
+	var repls []string
+	if vars.Prefix != "" {
+		// Bead IDs (gt-abc) and prefix settings ("gt-")
+		patterns = append(patterns, regexp.MustCompile(`\b`+regexp.QuoteMeta(vars.Prefix)+`-`))
+		repls = append(repls, VarRigPrefix+"-")
+	}
+	if vars.Name != "" {
+		patterns = append(patterns, regexp.MustCompile(`\b`+regexp.QuoteMeta(vars.Name)+`\b`))
+		repls = append(repls, VarRigName)
+	}
+	return func(s string) string {
+		for i, re := range patterns {
+			s = re.ReplaceAllLiteralString(s, repls[i])
+		}
+		return s
+	}
+}
+
+// walkFiles calls fn for each regular file under root with its path relative
+// to root. A missing root is not an error. Lock files are skipped.
+func walkFiles(root string, fn func(rel string, info fs.FileInfo, data []byte) error) error {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.Walk(root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || strings.HasSuffix(info.Name(), ".lock") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // G304: walking a known directory
+		if err != nil {
+			return err
+		}
+		return fn(rel, info, data)
+	})
+}
+
+func writeTemplateFile(path string, data []byte, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, perm); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file; hooks must stay executable
+	return os.Chmod(path, perm)
+}
+
+// isText reports whether data looks like text (no NUL in the first 8KB).
+func isText(data []byte) bool {
+	if len(data) > 8192 {
+		data = data[:8192]
+	}
+	return !bytes.Contains(data, []byte{0})
+}
@@ -0,0 +1,194 @@
+package rig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestTemplateApply(t *testing.T) {
+	town := t.TempDir()
+	dir := filepath.Join(TemplatesDir(town), "go-service")
+	writeFile(t, filepath.Join(dir, TemplateManifest), `{"description": "Go service"}`, 0644)
+	writeFile(t, filepath.Join(dir, "settings", "config.json"),
+		`{"type": "rig-settings", "version": 1, "merge_queue": {"test_command": "make test RIG={{rig.name}}"}}`, 0644)
+	writeFile(t, filepath.Join(dir, "overlay", ".env"), "DB={{rig.name}}_dev\n", 0600)
+	writeFile(t, filepath.Join(dir, "setup-hooks", "01-deps.sh"), "#!/bin/sh\necho {{rig.prefix}}\n", 0755)
+	writeFile(t, filepath.Join(dir, "plugins", "lint", "plugin.md"), "lint {{rig.name}}\n", 0644)
+	writeFile(t, filepath.Join(dir, "formulas", "mol-deploy.formula.toml"), "title = \"{{feature}} on {{rig.name}}\"\n", 0644)
+	writeFile(t, filepath.Join(dir, "overlay", "blob.bin"), "a\x00{{rig.name}}", 0644)
+
+	tmpl, err := ResolveTemplate(town, "go-service")
+	if err != nil {
+		t.Fatalf("ResolveTemplate: %v", err)
+	}
+	if tmpl.Name != "go-service" || tmpl.Description != "Go service" {
+		t.Errorf("template = %+v", tmpl)
+	}
+
+	rigPath := filepath.Join(town, "api")
+	written, err := tmpl.Apply(rigPath, TemplateVars{Name: "api", Prefix: "ap"})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(written) != 6 {
+		t.Errorf("Apply wrote %d files, want 6: %v", len(written), written)
+	}
+
+	if got := readFile(t, filepath.Join(rigPath, "settings", "config.json")); !strings.Contains(got, "RIG=api") {
+		t.Errorf("settings not substituted: %s", got)
+	}
+	if got := readFile(t, filepath.Join(rigPath, ".runtime", "overlay", ".env")); got != "DB=api_dev\n" {
+		t.Errorf("overlay .env = %q", got)
+	}
+	hook := filepath.Join(rigPath, ".runtime", "setup-hooks", "01-deps.sh")
+	if got := readFile(t, hook); !strings.Contains(got, "echo ap") {
+		t.Errorf("hook = %q", got)
+	}
+	if info, err := os.Stat(hook); err != nil || info.Mode().Perm()&0111 == 0 {
+		t.Errorf("hook lost its executable bit: %v", info.Mode())
+	}
+	if info, _ := os.Stat(filepath.Join(rigPath, ".runtime", "overlay", ".env")); info.Mode().Perm() != 0600 {
+		t.Errorf(".env mode = %v, want 0600", info.Mode().Perm())
+	}
+	if got := readFile(t, filepath.Join(rigPath, "plugins", "lint", "plugin.md")); got != "lint api\n" {
+		t.Errorf("plugin = %q", got)
+	}
+	// Formula variables are left alone
+	if got := readFile(t, filepath.Join(rigPath, ".beads", "formulas", "mol-deploy.formula.toml")); got != "title = \"{{feature}} on api\"\n" {
+		t.Errorf("formula = %q", got)
+	}
+	if got := readFile(t, filepath.Join(rigPath, ".runtime", "overlay", "blob.bin")); got != "a\x00{{rig.name}}" {
+		t.Errorf("binary file was rewritten: %q", got)
+	}
+}
+
+func TestTemplateFormulasFollowBeadsRedirect(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, TemplateManifest), `{"name": "deploy"}`, 0644)
+	writeFile(t, filepath.Join(dir, "formulas", "mol-deploy.formula.toml"), "title = \"deploy\"\n", 0644)
+	tmpl, err := LoadTemplate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tracked beads: <rig>/.beads only redirects to the clone's .beads
+	rigPath := filepath.Join(t.TempDir(), "api")
+	writeFile(t, filepath.Join(rigPath, ".beads", "redirect"), "mayor/rig/.beads\n", 0644)
+	if err := os.MkdirAll(filepath.Join(rigPath, "mayor", "rig", ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := tmpl.Apply(rigPath, TemplateVars{Name: "api", Prefix: "ap"})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	want := filepath.Join("mayor", "rig", ".beads", "formulas", "mol-deploy.formula.toml")
+	if len(written) != 1 || written[0] != want {
+		t.Errorf("Apply wrote %v, want %s", written, want)
+	}
+	if got := readFile(t, filepath.Join(rigPath, want)); got != "title = \"deploy\"\n" {
+		t.Errorf("formula = %q", got)
+	}
+
+	saved, err := SaveTemplate(rigPath, filepath.Join(t.TempDir(), "svc"), Template{Name: "svc"}, TemplateVars{}, false)
+	if err != nil {
+		t.Fatalf("SaveTemplate: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(saved.Dir, "formulas", "mol-deploy.formula.toml")); err != nil {
+		t.Errorf("saved template lacks the redirected formula: %v", err)
+	}
+}
+
+func TestTemplateValidate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, TemplateManifest), `{"name": "broken"}`, 0644)
+	writeFile(t, filepath.Join(dir, "settings", "config.json"),
+		`{"type": "rig-settings", "version": 1, "merge_queue": {"on_conflict": "panic"}}`, 0644)
+
+	tmpl, err := LoadTemplate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpl.Validate(TemplateVars{Name: "api"}); err == nil {
+		t.Error("expected invalid settings to fail validation")
+	}
+	rigPath := filepath.Join(t.TempDir(), "api")
+	if _, err := tmpl.Apply(rigPath, TemplateVars{Name: "api"}); err == nil {
+		t.Error("expected Apply to refuse an invalid template")
+	}
+	if _, err := os.Stat(rigPath); !os.IsNotExist(err) {
+		t.Error("Apply wrote files for an invalid template")
+	}
+}
+
+func TestSaveTemplateRoundTrip(t *testing.T) {
+	town := t.TempDir()
+	src := filepath.Join(town, "gastown")
+	writeFile(t, filepath.Join(src, "settings", "config.json"),
+		`{"type": "rig-settings", "version": 1, "merge_queue": {"test_command": "make test-gastown"}}`, 0644)
+	writeFile(t, filepath.Join(src, ".runtime", "setup-hooks", "01.sh"), "#!/bin/sh\nbd show gt-abc # gastowner\n", 0755)
+	writeFile(t, filepath.Join(src, ".beads", "formulas", "mol-x.formula.toml"), "x = 1\n", 0644)
+	writeFile(t, filepath.Join(src, ".beads", "issues.jsonl"), "{}\n", 0644) // not part of a template
+
+	dest := filepath.Join(TemplatesDir(town), "svc")
+	tmpl, err := SaveTemplate(src, dest, Template{Name: "svc", CreatedFrom: "gastown"}, TemplateVars{Name: "gastown", Prefix: "gt"}, true)
+	if err != nil {
+		t.Fatalf("SaveTemplate: %v", err)
+	}
+	if got := readFile(t, filepath.Join(dest, "setup-hooks", "01.sh")); got != "#!/bin/sh\nbd show {{rig.prefix}}-abc # gastowner\n" {
+		t.Errorf("saved hook = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "issues.jsonl")); !os.IsNotExist(err) {
+		t.Error("beads data leaked into the template")
+	}
+	if _, err := SaveTemplate(src, dest, Template{Name: "svc"}, TemplateVars{}, false); err == nil {
+		t.Error("expected saving over an existing template to fail")
+	}
+
+	list, err := ListTemplates(town)
+	if err != nil || len(list) != 1 || list[0].Name != "svc" || list[0].CreatedFrom != "gastown" {
+		t.Fatalf("ListTemplates = %v, %v", list, err)
+	}
+
+	dst := filepath.Join(town, "wyvern")
+	if _, err := tmpl.Apply(dst, TemplateVars{Name: "wyvern", Prefix: "wy"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dst, "settings", "config.json")); !strings.Contains(got, "make test-wyvern") {
+		t.Errorf("applied settings = %s", got)
+	}
+	if got := readFile(t, filepath.Join(dst, ".runtime", "setup-hooks", "01.sh")); !strings.Contains(got, "bd show wy-abc") {
+		t.Errorf("applied hook = %q", got)
+	}
+}
+
+func TestResolveTemplateNotFound(t *testing.T) {
+	if _, err := ResolveTemplate(t.TempDir(), "nope"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("ResolveTemplate(nope) = %v, want ErrTemplateNotFound", err)
+	}
+	if _, err := ResolveTemplate(t.TempDir(), t.TempDir()); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("ResolveTemplate(dir without manifest) = %v, want ErrTemplateNotFound", err)
+	}
+}
@@ -0,0 +1,245 @@
+// Package seance supports `gt seance`: running a fork of a predecessor's
+// Claude session in a disposable, read-only snapshot of its worktree, and
+// extracting the questions and answers from the fork's transcript.
+package seance
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofrs/flock"
+)
+
+// Sandbox is a disposable, read-only git worktree holding a snapshot of
+// another worktree: its HEAD plus uncommitted changes. Nothing written in
+// the sandbox reaches the source worktree or its branch.
+type Sandbox struct {
+	// Dir is the sandbox worktree.
+	Dir string
+
+	// Source is the worktree the snapshot was taken from.
+	Source string
+
+	// Commit is the commit the sandbox is checked out at (detached).
+	Commit string
+
+	// lock is held while the sandbox is in use (<dir>.lock), so
+	// PruneSandboxes can tell live sandboxes from ones left by a crash.
+	lock *flock.Flock
+}
+
+// NewSandbox snapshots the git worktree containing workDir into a new
+// detached worktree at dir. Uncommitted and untracked (non-ignored) files
+// are copied over, the .claude directory is removed so the source's
+// project settings and hooks don't apply, and all files are made read-only.
+func NewSandbox(workDir, dir string) (*Sandbox, error) {
+	source, err := gitOutput(workDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not a git worktree: %w", err)
+	}
+	commit, err := gitOutput(source, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return nil, fmt.Errorf("creating sandbox parent: %w", err)
+	}
+	lock := flock.New(dir + ".lock")
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking sandbox: %w", err)
+	}
+	if _, err := gitOutput(source, "worktree", "add", "--detach", dir, commit); err != nil {
+		_ = lock.Unlock()
+		_ = os.Remove(lock.Path())
+		return nil, fmt.Errorf("creating sandbox worktree: %w", err)
+	}
+	s := &Sandbox{Dir: dir, Source: source, Commit: commit, lock: lock}
+
+	if err := s.copyUncommitted(); err != nil {
+		_ = s.Remove()
+		return nil, err
+	}
+	if err := os.RemoveAll(filepath.Join(dir, ".claude")); err != nil {
+		_ = s.Remove()
+		return nil, err
+	}
+	if err := setWritable(dir, false); err != nil {
+		_ = s.Remove()
+		return nil, fmt.Errorf("making sandbox read-only: %w", err)
+	}
+	return s, nil
+}
+
+// copyUncommitted brings modified, added, deleted and untracked files from
+// the source worktree into the sandbox.
+func (s *Sandbox) copyUncommitted() error {
+	out, err := gitRaw(s.Source, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return err
+	}
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		code, path := entry[:2], entry[3:]
+		if code[0] == 'R' || code[0] == 'C' {
+			// Renames and copies are followed by the original path
+			if code[0] == 'R' && i+1 < len(entries) {
+				_ = os.Remove(filepath.Join(s.Dir, entries[i+1]))
+			}
+			i++
+		}
+		src := filepath.Join(s.Source, path)
+		dst := filepath.Join(s.Dir, path)
+		info, err := os.Lstat(src)
+		if os.IsNotExist(err) {
+			_ = os.Remove(dst)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		if err := copyFile(src, dst, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("copying %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Remove deletes the sandbox worktree.
+func (s *Sandbox) Remove() error {
+	_ = setWritable(s.Dir, true)
+	_, err := gitOutput(s.Source, "worktree", "remove", "--force", s.Dir)
+	if rmErr := os.RemoveAll(s.Dir); err == nil {
+		err = rmErr
+	}
+	_, _ = gitOutput(s.Source, "worktree", "prune")
+	if s.lock != nil {
+		_ = os.Remove(s.lock.Path())
+		_ = s.lock.Unlock()
+	}
+	return err
+}
+
+// PruneSandboxes removes sandboxes under parent that no running seance
+// holds, such as those left behind when gt was killed mid-seance. It
+// returns the directories it removed.
+func PruneSandboxes(parent string) []string {
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return nil
+	}
+	var removed []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(parent, e.Name())
+		lock := flock.New(dir + ".lock")
+		if ok, err := lock.TryLock(); err != nil || !ok {
+			continue // in use
+		}
+		// Git commands run from the source's git dir; the worktree itself
+		// may be half gone.
+		s := &Sandbox{Dir: dir, lock: lock}
+		common, err := gitOutput(dir, "rev-parse", "--path-format=absolute", "--git-common-dir")
+		if err == nil {
+			s.Source = common
+		}
+		if s.Source == "" {
+			_ = setWritable(dir, true)
+			_ = os.RemoveAll(dir)
+			_ = os.Remove(lock.Path())
+			_ = lock.Unlock()
+		} else {
+			_ = s.Remove()
+		}
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			removed = append(removed, dir)
+		}
+	}
+	return removed
+}
+
+// setWritable adds or removes write permission on everything under dir,
+// except git's metadata link.
+func setWritable(dir string, writable bool) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 || (path != dir && d.Name() == ".git") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		mode := info.Mode().Perm()
+		if writable {
+			mode |= 0200
+		} else {
+			mode &^= 0222
+		}
+		if d.IsDir() && !writable {
+			// Walk enters directories after visiting them; keep them
+			// traversable but not writable.
+			mode |= 0500
+		}
+		return os.Chmod(path, mode)
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src) //nolint:gosec // G304: path comes from git status
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm) //nolint:gosec // G304: path comes from git status
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// gitOutput runs git in dir and returns trimmed stdout.
+func gitOutput(dir string, args ...string) (string, error) {
+	out, err := gitRaw(dir, args...)
+	return strings.TrimSpace(out), err
+}
+
+// gitRaw runs git in dir and returns stdout as is.
+func gitRaw(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,149 @@
+package seance
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "rig")
+	runGit(t, t.TempDir(), "init", "-b", "main", repo)
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	runGit(t, repo, "config", "user.name", "Test")
+	writeFile(t, filepath.Join(repo, "committed.txt"), "v1\n")
+	writeFile(t, filepath.Join(repo, "doomed.txt"), "bye\n")
+	writeFile(t, filepath.Join(repo, "old-name.txt"), "moved\n")
+	writeFile(t, filepath.Join(repo, ".claude", "settings.json"), "{}\n")
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "-m", "init")
+
+	// Uncommitted work the predecessor left behind
+	writeFile(t, filepath.Join(repo, "committed.txt"), "v2\n")
+	writeFile(t, filepath.Join(repo, "src", "new.go"), "package src\n")
+	if err := os.Remove(filepath.Join(repo, "doomed.txt")); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "mv", "old-name.txt", "new-name.txt")
+	statusBefore := runGit(t, repo, "status", "--porcelain")
+
+	dir := filepath.Join(t.TempDir(), "seance", "abc")
+	s, err := NewSandbox(filepath.Join(repo, "src"), dir)
+	if err != nil {
+		t.Fatalf("NewSandbox: %v", err)
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return "<missing>"
+		}
+		return string(data)
+	}
+	if got := read("committed.txt"); got != "v2\n" {
+		t.Errorf("committed.txt = %q, want the uncommitted v2", got)
+	}
+	if got := read("src/new.go"); got != "package src\n" {
+		t.Errorf("untracked file = %q", got)
+	}
+	if got := read("doomed.txt"); got != "<missing>" {
+		t.Errorf("deleted file still in sandbox: %q", got)
+	}
+	if read("new-name.txt") != "moved\n" || read("old-name.txt") != "<missing>" {
+		t.Error("rename not carried into sandbox")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".claude")); !os.IsNotExist(err) {
+		t.Error("sandbox kept the source's .claude settings")
+	}
+	for _, name := range []string{"committed.txt", "src", "src/new.go"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm()&0222 != 0 {
+			t.Errorf("%s is writable: %v", name, info.Mode().Perm())
+		}
+	}
+	if got := runGit(t, dir, "rev-parse", "HEAD"); got != s.Commit {
+		t.Errorf("sandbox HEAD = %s, want %s", got, s.Commit)
+	}
+	if got := runGit(t, repo, "status", "--porcelain"); got != statusBefore {
+		t.Errorf("source worktree changed:\n%s\nwant:\n%s", got, statusBefore)
+	}
+
+	if err := s.Remove(); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("sandbox dir still exists")
+	}
+	if list := runGit(t, repo, "worktree", "list"); strings.Contains(list, dir) {
+		t.Errorf("sandbox worktree still registered:\n%s", list)
+	}
+}
+
+func TestSandboxNotGit(t *testing.T) {
+	if _, err := NewSandbox(t.TempDir(), filepath.Join(t.TempDir(), "s")); err == nil {
+		t.Error("expected an error outside a git worktree")
+	}
+}
+
+func TestPruneSandboxes(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "rig")
+	runGit(t, t.TempDir(), "init", "-b", "main", repo)
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	runGit(t, repo, "config", "user.name", "Test")
+	writeFile(t, filepath.Join(repo, "committed.txt"), "v1\n")
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "-m", "init")
+
+	parent := filepath.Join(t.TempDir(), "seance")
+	live, err := NewSandbox(repo, filepath.Join(parent, "live"))
+	if err != nil {
+		t.Fatalf("NewSandbox: %v", err)
+	}
+	defer func() { _ = live.Remove() }()
+	stale, err := NewSandbox(repo, filepath.Join(parent, "stale"))
+	if err != nil {
+		t.Fatalf("NewSandbox: %v", err)
+	}
+	// A killed gt releases its lock without removing the sandbox
+	_ = stale.lock.Unlock()
+
+	removed := PruneSandboxes(parent)
+	if len(removed) != 1 || removed[0] != stale.Dir {
+		t.Errorf("PruneSandboxes removed %v, want only %s", removed, stale.Dir)
+	}
+	if _, err := os.Stat(live.Dir); err != nil {
+		t.Errorf("live sandbox pruned: %v", err)
+	}
+	if list := runGit(t, repo, "worktree", "list"); strings.Contains(list, stale.Dir) {
+		t.Errorf("stale sandbox still registered:\n%s", list)
+	}
+	if _, err := os.Stat(stale.Dir + ".lock"); !os.IsNotExist(err) {
+		t.Error("stale sandbox lock file left behind")
+	}
+}
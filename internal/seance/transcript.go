@@ -0,0 +1,242 @@
+package seance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrSessionNotFound is returned when no transcript exists for a session ID.
+var ErrSessionNotFound = errors.New("session transcript not found")
+
+// MaxTranscript caps the size of a Q&A transcript saved to a handoff bead.
+const MaxTranscript = 8 * 1024
+
+// ConfigDir returns Claude's configuration directory: $CLAUDE_CONFIG_DIR,
+// or ~/.claude.
+func ConfigDir() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".claude")
+}
+
+// ProjectDir returns the directory where Claude keeps the transcripts of
+// sessions started in workDir. `claude --resume <id>` only finds sessions
+// in the current directory's project.
+func ProjectDir(workDir string) string {
+	abs, err := filepath.Abs(workDir)
+	if err != nil {
+		abs = workDir
+	}
+	slug := []byte(abs)
+	for i, c := range slug {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			slug[i] = '-'
+		}
+	}
+	return filepath.Join(ConfigDir(), "projects", string(slug))
+}
+
+// FindSession returns the transcript path of a session, searching every
+// project.
+func FindSession(sessionID string) (string, error) {
+	if sessionID == "" || strings.ContainsAny(sessionID, `/\*?[`) {
+		return "", fmt.Errorf("%w: invalid session ID %q", ErrSessionNotFound, sessionID)
+	}
+	matches, _ := filepath.Glob(filepath.Join(ConfigDir(), "projects", "*", sessionID+".jsonl"))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+	return matches[0], nil
+}
+
+// CopySession copies a session transcript into workDir's project so the
+// session can be resumed from there. Returns the new transcript path.
+func CopySession(transcript, workDir string) (string, error) {
+	dest := filepath.Join(ProjectDir(workDir), filepath.Base(transcript))
+	info, err := os.Stat(transcript)
+	if err != nil {
+		return "", err
+	}
+	if err := copyFile(transcript, dest, info.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("copying session transcript: %w", err)
+	}
+	return dest, nil
+}
+
+// Transcripts returns the session transcripts in workDir's project.
+func Transcripts(workDir string) map[string]bool {
+	matches, _ := filepath.Glob(filepath.Join(ProjectDir(workDir), "*.jsonl"))
+	out := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		out[m] = true
+	}
+	return out
+}
+
+// Exchange is one message of a seance conversation.
+type Exchange struct {
+	Role string `json:"role"` // "user" or "assistant"
+	Text string `json:"text"`
+}
+
+// transcriptEntry is the part of a Claude transcript line we read.
+type transcriptEntry struct {
+	Type    string `json:"type"`
+	UUID    string `json:"uuid"`
+	IsMeta  bool   `json:"isMeta"`
+	Message struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// text returns the entry's plain text, ignoring tool calls and results.
+func (e *transcriptEntry) text() string {
+	var s string
+	if err := json.Unmarshal(e.Message.Content, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(e.Message.Content, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" && strings.TrimSpace(b.Text) != "" {
+			parts = append(parts, strings.TrimSpace(b.Text))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func readEntries(path string) ([]transcriptEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: transcript path is found under Claude's config dir
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []transcriptEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e transcriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if e.Type == "user" || e.Type == "assistant" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// NewExchanges returns the messages in a forked session's transcript that
+// aren't in the original's: the seance conversation itself.
+func NewExchanges(original, fork string) ([]Exchange, error) {
+	orig, err := readEntries(original)
+	if err != nil {
+		return nil, err
+	}
+	forked, err := readEntries(fork)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(orig))
+	for _, e := range orig {
+		if e.UUID != "" {
+			seen[e.UUID] = true
+		}
+	}
+	shared := 0
+	for _, e := range forked {
+		if seen[e.UUID] {
+			shared++
+		}
+	}
+	if shared == 0 && len(forked) >= len(orig) {
+		// The fork didn't keep message IDs; it starts with a copy of the
+		// original conversation.
+		forked = forked[len(orig):]
+	}
+
+	var out []Exchange
+	for i := range forked {
+		e := &forked[i]
+		if seen[e.UUID] || e.IsMeta {
+			continue
+		}
+		if text := e.text(); text != "" {
+			out = append(out, Exchange{Role: e.Type, Text: text})
+		}
+	}
+	return out, nil
+}
+
+// FormatTranscript renders a seance conversation as Markdown for a handoff
+// bead, truncated to MaxTranscript bytes.
+func FormatTranscript(sessionID string, at time.Time, exchanges []Exchange) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## 🔮 Seance with %s (%s)\n", sessionID, at.Format("2006-01-02 15:04"))
+	for _, e := range exchanges {
+		label := "A"
+		if e.Role == "user" {
+			label = "Q"
+		}
+		fmt.Fprintf(&sb, "\n**%s:** %s\n", label, e.Text)
+	}
+	s := sb.String()
+	if len(s) > MaxTranscript {
+		cut := MaxTranscript
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + "\n\n… (transcript truncated)\n"
+	}
+	return s
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// Result is the outcome of a one-shot (`claude --print`) seance.
+type Result struct {
+	Answer        string  `json:"answer"`
+	ForkSessionID string  `json:"fork_session_id,omitempty"`
+	IsError       bool    `json:"is_error,omitempty"`
+	CostUSD       float64 `json:"cost_usd,omitempty"`
+}
+
+// ParseResult reads the output of `claude --print --output-format json`.
+// Output that isn't a JSON result is taken as the plain-text answer.
+func ParseResult(out []byte) Result {
+	var raw struct {
+		Type      string  `json:"type"`
+		Result    string  `json:"result"`
+		SessionID string  `json:"session_id"`
+		IsError   bool    `json:"is_error"`
+		CostUSD   float64 `json:"total_cost_usd"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil || raw.Type != "result" {
+		return Result{Answer: strings.TrimSpace(string(out))}
+	}
+	return Result{
+		Answer:        strings.TrimSpace(raw.Result),
+		ForkSessionID: raw.SessionID,
+		IsError:       raw.IsError,
+		CostUSD:       raw.CostUSD,
+	}
+}
@@ -0,0 +1,111 @@
+package seance
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProjectDir(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", "/cfg")
+	if got, want := ProjectDir("/home/me/gt/gastown/crew/max_2.x"), "/cfg/projects/-home-me-gt-gastown-crew-max-2-x"; got != want {
+		t.Errorf("ProjectDir = %q, want %q", got, want)
+	}
+}
+
+func TestFindAndCopySession(t *testing.T) {
+	cfg := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", cfg)
+	src := filepath.Join(ProjectDir("/town/gastown/crew/max"), "sess-1.jsonl")
+	writeFile(t, src, `{"type":"user"}`+"\n")
+
+	found, err := FindSession("sess-1")
+	if err != nil || found != src {
+		t.Fatalf("FindSession = %q, %v", found, err)
+	}
+	if _, err := FindSession("nope"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("FindSession(nope) = %v", err)
+	}
+	if _, err := FindSession("../*"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("FindSession with a pattern = %v", err)
+	}
+
+	copied, err := CopySession(found, "/town/.runtime/seance/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(ProjectDir("/town/.runtime/seance/x"), "sess-1.jsonl"); copied != want {
+		t.Errorf("CopySession = %q, want %q", copied, want)
+	}
+	if got := Transcripts("/town/.runtime/seance/x"); !got[copied] || len(got) != 1 {
+		t.Errorf("Transcripts = %v", got)
+	}
+}
+
+const originalTranscript = `{"type":"summary","summary":"auth work"}
+{"type":"user","uuid":"u1","message":{"role":"user","content":"Fix the auth bug"}}
+{"type":"assistant","uuid":"a1","message":{"role":"assistant","content":[{"type":"text","text":"Fixed in auth.go"},{"type":"tool_use","name":"Edit"}]}}
+`
+
+func TestNewExchanges(t *testing.T) {
+	dir := t.TempDir()
+	orig := filepath.Join(dir, "orig.jsonl")
+	fork := filepath.Join(dir, "fork.jsonl")
+	writeFile(t, orig, originalTranscript)
+	writeFile(t, fork, originalTranscript+
+		`{"type":"user","uuid":"u2","message":{"role":"user","content":"Where is the fix?"}}
+{"type":"assistant","uuid":"a2","message":{"role":"assistant","content":[{"type":"tool_use","name":"Grep"}]}}
+{"type":"user","uuid":"u3","message":{"role":"user","content":[{"type":"tool_result","content":"auth.go:12"}]}}
+{"type":"user","uuid":"m1","isMeta":true,"message":{"role":"user","content":"caveat"}}
+{"type":"assistant","uuid":"a3","message":{"role":"assistant","content":[{"type":"text","text":"auth.go line 12"}]}}
+not json
+`)
+
+	got, err := NewExchanges(orig, fork)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Exchange{{Role: "user", Text: "Where is the fix?"}, {Role: "assistant", Text: "auth.go line 12"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewExchanges = %+v, want %+v", got, want)
+	}
+
+	// Forks that renumber messages are matched by position
+	writeFile(t, fork, strings.NewReplacer(`"u1"`, `"x1"`, `"a1"`, `"x2"`).Replace(originalTranscript)+
+		`{"type":"user","uuid":"x3","message":{"role":"user","content":"Why?"}}`+"\n")
+	got, err = NewExchanges(orig, fork)
+	if err != nil || !reflect.DeepEqual(got, []Exchange{{Role: "user", Text: "Why?"}}) {
+		t.Errorf("NewExchanges (renumbered) = %+v, %v", got, err)
+	}
+}
+
+func TestFormatTranscript(t *testing.T) {
+	at := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	got := FormatTranscript("sess-1", at, []Exchange{{Role: "user", Text: "Where?"}, {Role: "assistant", Text: "Here."}})
+	want := "## 🔮 Seance with sess-1 (2026-10-18 09:30)\n\n**Q:** Where?\n\n**A:** Here.\n"
+	if got != want {
+		t.Errorf("FormatTranscript = %q, want %q", got, want)
+	}
+
+	long := FormatTranscript("s", at, []Exchange{{Role: "assistant", Text: strings.Repeat("é", MaxTranscript)}})
+	if len(long) > MaxTranscript+64 || !strings.HasSuffix(long, "(transcript truncated)\n") {
+		t.Errorf("long transcript not truncated: %d bytes", len(long))
+	}
+	if !strings.HasPrefix(long, "## ") || strings.ContainsRune(long, '�') {
+		t.Error("truncation split a character")
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	got := ParseResult([]byte(`{"type":"result","subtype":"success","is_error":false,"result":" On branch fix/auth \n","session_id":"fork-1","total_cost_usd":0.12}`))
+	want := Result{Answer: "On branch fix/auth", ForkSessionID: "fork-1", CostUSD: 0.12}
+	if got != want {
+		t.Errorf("ParseResult = %+v, want %+v", got, want)
+	}
+	if got := ParseResult([]byte("plain answer\n")); got != (Result{Answer: "plain answer"}) {
+		t.Errorf("ParseResult(text) = %+v", got)
+	}
+}
@@ -177,12 +177,106 @@ func SetShellIntegration(shell string) error {
 	return Save(s)
 }
 
-// RecordDoctorRun records when doctor was last run.
-func RecordDoctorRun() error {
+// MaxDoctorHistory is the number of doctor runs kept per town.
+const MaxDoctorHistory = 50
+
+// DoctorRun records the outcome of one gt doctor run.
+type DoctorRun struct {
+	At     time.Time         `json:"at"`
+	Town   string            `json:"town"`
+	Checks map[string]string `json:"checks"` // check name -> "ok", "warning" or "error"
+}
+
+// DoctorHistoryPath returns the path to the doctor run history.
+func DoctorHistoryPath() string {
+	return filepath.Join(StateDir(), "doctor-history.json")
+}
+
+// RecordDoctorRun records when doctor was last run and appends the run to
+// the history, keeping the most recent MaxDoctorHistory runs per town.
+func RecordDoctorRun(run DoctorRun) error {
+	if run.At.IsZero() {
+		run.At = time.Now()
+	}
+
+	runs, err := loadDoctorHistory()
+	if err != nil {
+		return err
+	}
+	runs = append(runs, run)
+
+	// Trim per town, oldest first.
+	count := make(map[string]int)
+	for _, r := range runs {
+		count[r.Town]++
+	}
+	kept := runs[:0]
+	for _, r := range runs {
+		if count[r.Town] > MaxDoctorHistory {
+			count[r.Town]--
+			continue
+		}
+		kept = append(kept, r)
+	}
+	if err := saveDoctorHistory(kept); err != nil {
+		return err
+	}
+
 	s, err := Load()
 	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
 		return err
 	}
-	s.LastDoctorRun = time.Now()
+	s.LastDoctorRun = run.At
 	return Save(s)
 }
+
+// DoctorHistory returns the recorded doctor runs for a town, oldest first.
+func DoctorHistory(town string) ([]DoctorRun, error) {
+	runs, err := loadDoctorHistory()
+	if err != nil {
+		return nil, err
+	}
+	var result []DoctorRun
+	for _, r := range runs {
+		if r.Town == town {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func loadDoctorHistory() ([]DoctorRun, error) {
+	data, err := os.ReadFile(DoctorHistoryPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var runs []DoctorRun
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func saveDoctorHistory(runs []DoctorRun) error {
+	if err := os.MkdirAll(StateDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Atomic write via temp file
+	path := DoctorHistoryPath()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -129,3 +129,29 @@ func TestGenerateMachineID(t *testing.T) {
 		t.Error("generateMachineID() should generate unique IDs")
 	}
 }
+
+func TestRecordDoctorRun(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	for i := 0; i < MaxDoctorHistory+5; i++ {
+		run := DoctorRun{Town: "/town-a", Checks: map[string]string{"daemon": "ok"}}
+		if err := RecordDoctorRun(run); err != nil {
+			t.Fatalf("RecordDoctorRun: %v", err)
+		}
+	}
+	if err := RecordDoctorRun(DoctorRun{Town: "/town-b", Checks: map[string]string{"daemon": "error"}}); err != nil {
+		t.Fatalf("RecordDoctorRun: %v", err)
+	}
+
+	a, err := DoctorHistory("/town-a")
+	if err != nil {
+		t.Fatalf("DoctorHistory: %v", err)
+	}
+	if len(a) != MaxDoctorHistory {
+		t.Errorf("town-a history = %d runs, want %d", len(a), MaxDoctorHistory)
+	}
+	b, _ := DoctorHistory("/town-b")
+	if len(b) != 1 || b[0].Checks["daemon"] != "error" || b[0].At.IsZero() {
+		t.Errorf("town-b history = %+v", b)
+	}
+}
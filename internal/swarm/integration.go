@@ -101,41 +101,150 @@ func (m *Manager) AbortMerge() error {
 	return m.gitRun("merge", "--abort")
 }
 
-// LandToMain merges the integration branch to the target branch (usually main).
-func (m *Manager) LandToMain(swarmID string) error {
+// LandToMain merges the integration branch to the target branch (usually main)
+// as a single --no-ff merge commit, tags it (see LandedTag) and pushes both.
+// Returns the merge commit.
+func (m *Manager) LandToMain(swarmID string) (string, error) {
 	swarm, err := m.LoadSwarm(swarmID)
 	if err != nil {
-		return err
+		return "", err
 	}
+	return m.landSwarm(swarm)
+}
+
+// LandedTag returns the name of the tag marking a swarm's landing merge.
+func LandedTag(swarmID string) string {
+	return "landed/" + swarmID
+}
 
+func (m *Manager) landSwarm(swarm *Swarm) (string, error) {
 	// Checkout target branch
 	if err := m.gitRun("checkout", swarm.TargetBranch); err != nil {
-		return fmt.Errorf("checking out %s: %w", swarm.TargetBranch, err)
+		return "", fmt.Errorf("checking out %s: %w", swarm.TargetBranch, err)
 	}
 
 	// Pull latest (non-fatal: may fail if remote unreachable)
 	_ = m.gitRun("pull", "origin", swarm.TargetBranch)
 
 	// Merge integration branch
-	err = m.gitRun("merge", "--no-ff", "-m",
-		fmt.Sprintf("Land swarm %s", swarmID),
+	err := m.gitRun("merge", "--no-ff", "-m",
+		fmt.Sprintf("Land swarm %s\n\nSwarm: %s\nIntegration: %s\nTasks: %d",
+			swarm.ID, swarm.ID, swarm.Integration, len(swarm.Tasks)),
 		swarm.Integration)
 	if err != nil {
 		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
 		conflicts, conflictErr := m.getConflictingFiles()
 		if conflictErr == nil && len(conflicts) > 0 {
 			// Return the original error with raw output for observation
-			return err
+			return "", err
 		}
-		return fmt.Errorf("merging to %s: %w", swarm.TargetBranch, err)
+		return "", fmt.Errorf("merging to %s: %w", swarm.TargetBranch, err)
+	}
+
+	mergeCommit, err := m.getGitHead()
+	if err != nil {
+		return "", fmt.Errorf("reading merge commit: %w", err)
 	}
 
-	// Push
+	// Tag the merge so it can be found (and reverted) later
+	tag := LandedTag(swarm.ID)
+	if err := m.gitRun("tag", "-f", "-a", tag, "-m",
+		fmt.Sprintf("Swarm %s landed on %s\n\nTarget: %s", swarm.ID, swarm.TargetBranch, swarm.TargetBranch),
+		mergeCommit); err != nil {
+		_ = m.gitRun("reset", "--hard", "HEAD~1")
+		return "", fmt.Errorf("tagging landing: %w", err)
+	}
+
+	// Push; on failure drop the local merge so the target matches origin
 	if err := m.gitRun("push", "origin", swarm.TargetBranch); err != nil {
-		return fmt.Errorf("pushing: %w", err)
+		_ = m.gitRun("reset", "--hard", "HEAD~1")
+		_ = m.gitRun("tag", "-d", tag)
+		return "", fmt.Errorf("pushing: %w", err)
 	}
+	// The landing is on origin now; the tag push is best-effort (Unland
+	// also finds the local tag)
+	_ = m.gitRun("push", "-f", "origin", "refs/tags/"+tag)
 
-	return nil
+	return mergeCommit, nil
+}
+
+// UnlandResult contains the result of reverting a swarm landing.
+type UnlandResult struct {
+	SwarmID      string
+	Target       string
+	MergeCommit  string
+	RevertCommit string
+}
+
+// Unland reverts a swarm's landing merge on its target branch and pushes
+// the revert. The landed tag is deleted, so a swarm is unlanded only once.
+// A conflicting revert is aborted and the target is left untouched.
+func (m *Manager) Unland(swarmID string) (*UnlandResult, error) {
+	tag := LandedTag(swarmID)
+
+	// The landing may have been pushed from another clone
+	_ = m.gitRun("fetch", "origin", "refs/tags/"+tag+":refs/tags/"+tag)
+
+	mergeCommit, err := m.gitRunOutput(m.gitDir, "rev-list", "-n", "1", "refs/tags/"+tag)
+	if err != nil || strings.TrimSpace(mergeCommit) == "" {
+		return nil, fmt.Errorf("swarm %s has no landing to revert (tag %s not found)", swarmID, tag)
+	}
+	mergeCommit = strings.TrimSpace(mergeCommit)
+
+	target := ""
+	annotation, _ := m.gitRunOutput(m.gitDir, "for-each-ref", "--format=%(contents)", "refs/tags/"+tag)
+	for _, line := range strings.Split(annotation, "\n") {
+		if strings.HasPrefix(line, "Target:") {
+			target = strings.TrimSpace(strings.TrimPrefix(line, "Target:"))
+		}
+	}
+	if target == "" {
+		target = m.rig.DefaultBranch()
+	}
+
+	if err := m.gitRun("checkout", target); err != nil {
+		return nil, fmt.Errorf("checking out %s: %w", target, err)
+	}
+	_ = m.gitRun("pull", "origin", target)
+
+	if err := m.gitRun("revert", "--no-commit", "-m", "1", mergeCommit); err != nil {
+		_ = m.gitRun("revert", "--abort")
+		return nil, fmt.Errorf("reverting %s: %w", ShortSHA(mergeCommit), err)
+	}
+	if err := m.gitRun("commit", "-m",
+		fmt.Sprintf("Unland swarm %s\n\nThis reverts landing merge %s.\n\nSwarm: %s", swarmID, mergeCommit, swarmID)); err != nil {
+		_ = m.gitRun("revert", "--abort")
+		return nil, fmt.Errorf("committing revert: %w", err)
+	}
+	revertCommit, err := m.getGitHead()
+	if err != nil {
+		return nil, fmt.Errorf("reading revert commit: %w", err)
+	}
+
+	// Push; on failure drop the local revert so the target matches origin
+	if err := m.gitRun("push", "origin", target); err != nil {
+		_ = m.gitRun("reset", "--hard", "HEAD~1")
+		return nil, fmt.Errorf("pushing revert: %w", err)
+	}
+
+	// Drop the landed tag (best-effort: the revert is what matters)
+	_ = m.gitRun("tag", "-d", tag)
+	_ = m.gitRun("push", "origin", ":refs/tags/"+tag)
+
+	return &UnlandResult{
+		SwarmID:      swarmID,
+		Target:       target,
+		MergeCommit:  mergeCommit,
+		RevertCommit: revertCommit,
+	}, nil
+}
+
+// ShortSHA abbreviates a commit hash for messages.
+func ShortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
 }
 
 // CleanupBranches removes all branches associated with a swarm.
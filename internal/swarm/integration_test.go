@@ -1,6 +1,10 @@
 package swarm
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/rig"
@@ -20,5 +24,138 @@ func TestGetWorkerBranch(t *testing.T) {
 	}
 }
 
-// Note: Integration tests that require git operations and beads
-// are covered by the E2E test (gt-kc7yj.4).
+// Note: Integration tests that require beads are covered by the E2E test
+// (gt-kc7yj.4).
+
+// setupLandingRepo creates a rig whose git root has a bare origin, a main
+// branch and a swarm integration branch with one commit.
+func setupLandingRepo(t *testing.T) (*Manager, *Swarm) {
+	t.Helper()
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	rigPath := filepath.Join(root, "rig")
+	runGit(t, root, "init", "--bare", "-b", "main", origin)
+	runGit(t, root, "clone", origin, rigPath)
+	runGit(t, rigPath, "config", "user.email", "test@test.com")
+	runGit(t, rigPath, "config", "user.name", "Test")
+	writeAndCommit(t, rigPath, "README.md", "base\n")
+	runGit(t, rigPath, "push", "origin", "main")
+
+	sw := &Swarm{
+		ID:           "gt-sw1",
+		Integration:  "swarm/gt-sw1",
+		TargetBranch: "main",
+		Tasks:        []SwarmTask{{IssueID: "gt-t1", State: TaskMerged}},
+	}
+	runGit(t, rigPath, "checkout", "-b", sw.Integration)
+	writeAndCommit(t, rigPath, "feature.txt", "swarm work\n")
+	runGit(t, rigPath, "checkout", "main")
+
+	return NewManager(&rig.Rig{Name: "rig", Path: rigPath}), sw
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeAndCommit(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", "add "+name)
+}
+
+func TestLandAndUnland(t *testing.T) {
+	m, sw := setupLandingRepo(t)
+
+	mergeCommit, err := m.landSwarm(sw)
+	if err != nil {
+		t.Fatalf("landSwarm: %v", err)
+	}
+	origin := filepath.Join(filepath.Dir(m.gitDir), "origin.git")
+	if got := runGit(t, origin, "rev-parse", "main"); got != mergeCommit {
+		t.Errorf("origin main = %s, want merge commit %s", got, mergeCommit)
+	}
+	if parents := strings.Fields(runGit(t, m.gitDir, "rev-list", "--parents", "-n", "1", mergeCommit)); len(parents) != 3 {
+		t.Errorf("landing should be a single merge commit, parents: %v", parents)
+	}
+	if got := runGit(t, origin, "rev-list", "-n", "1", "refs/tags/"+LandedTag(sw.ID)); got != mergeCommit {
+		t.Errorf("landed tag on origin = %s, want %s", got, mergeCommit)
+	}
+
+	result, err := m.Unland(sw.ID)
+	if err != nil {
+		t.Fatalf("Unland: %v", err)
+	}
+	if result.Target != "main" || result.MergeCommit != mergeCommit {
+		t.Errorf("result = %+v", result)
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != result.RevertCommit {
+		t.Errorf("origin main = %s, want revert commit %s", got, result.RevertCommit)
+	}
+	if _, err := os.Stat(filepath.Join(m.gitDir, "feature.txt")); !os.IsNotExist(err) {
+		t.Error("swarm work should be reverted on main")
+	}
+	if msg := runGit(t, m.gitDir, "log", "-1", "--format=%s"); msg != "Unland swarm gt-sw1" {
+		t.Errorf("revert subject = %q", msg)
+	}
+
+	if _, err := m.Unland(sw.ID); err == nil || !strings.Contains(err.Error(), "no landing to revert") {
+		t.Errorf("second Unland = %v, want no landing error", err)
+	}
+}
+
+func TestUnland_ConflictLeavesTargetUntouched(t *testing.T) {
+	m, sw := setupLandingRepo(t)
+	if _, err := m.landSwarm(sw); err != nil {
+		t.Fatalf("landSwarm: %v", err)
+	}
+	// Later work on main edits the landed file
+	writeAndCommit(t, m.gitDir, "feature.txt", "changed after landing\n")
+	runGit(t, m.gitDir, "push", "origin", "main")
+	head := runGit(t, m.gitDir, "rev-parse", "HEAD")
+
+	if _, err := m.Unland(sw.ID); err == nil {
+		t.Fatal("Unland should fail on a conflicting revert")
+	}
+	if got := runGit(t, m.gitDir, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD moved to %s after failed unland, want %s", got, head)
+	}
+	if status := runGit(t, m.gitDir, "status", "--porcelain"); status != "" {
+		t.Errorf("worktree not clean after failed unland:\n%s", status)
+	}
+}
+
+func TestUnland_PushFailureResetsTarget(t *testing.T) {
+	m, sw := setupLandingRepo(t)
+	if _, err := m.landSwarm(sw); err != nil {
+		t.Fatalf("landSwarm: %v", err)
+	}
+	head := runGit(t, m.gitDir, "rev-parse", "HEAD")
+
+	// Reject every push to origin
+	origin := filepath.Join(filepath.Dir(m.gitDir), "origin.git")
+	hook := filepath.Join(origin, "hooks", "pre-receive")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Unland(sw.ID); err == nil || !strings.Contains(err.Error(), "pushing revert") {
+		t.Fatalf("Unland = %v, want push failure", err)
+	}
+	if got := runGit(t, m.gitDir, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD = %s after failed push, want landing %s", got, head)
+	}
+	if _, err := os.Stat(filepath.Join(m.gitDir, "feature.txt")); err != nil {
+		t.Error("landed work should still be on the target after a failed unland")
+	}
+}
@@ -3,7 +3,9 @@ package swarm
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -22,6 +24,29 @@ type LandingConfig struct {
 
 	// SkipGitAudit skips the git safety audit.
 	SkipGitAudit bool
+
+	// SkipVerify skips pre-land verification (task closure and tests).
+	SkipVerify bool
+
+	// TestCommand is run with sh -c on the integration branch during
+	// verification, usually the rig's merge_queue.test_command. Empty
+	// skips the test run.
+	TestCommand string
+}
+
+// VerifyResult contains the result of pre-land verification.
+type VerifyResult struct {
+	TasksError  string   // why the swarm's tasks could not be loaded
+	NoTasks     bool     // the swarm has no tasks to land
+	OpenTasks   []string // swarm tasks whose beads are not closed
+	TestsRun    bool
+	TestsPassed bool
+	TestOutput  string // tail of the test command's output
+}
+
+// Passed reports whether verification allows landing.
+func (v *VerifyResult) Passed() bool {
+	return v.TasksError == "" && !v.NoTasks && len(v.OpenTasks) == 0 && (!v.TestsRun || v.TestsPassed)
 }
 
 // LandingResult contains the result of a landing operation.
@@ -32,6 +57,9 @@ type LandingResult struct {
 	SessionsStopped int
 	BranchesCleaned int
 	PolecatsAtRisk  []string
+	Verify          *VerifyResult // nil if verification was skipped
+	MergeCommit     string
+	Tag             string
 }
 
 // GitAuditResult contains the result of a git safety audit.
@@ -57,7 +85,20 @@ func (m *Manager) ExecuteLanding(swarmID string, config LandingConfig) (*Landing
 		SwarmID: swarmID,
 	}
 
-	// Phase 1: Stop all polecat sessions
+	// Phase 1: Verify before touching sessions, so workers can still fix
+	// a failing integration branch
+	if !config.SkipVerify {
+		result.Verify = m.verifyLanding(swarm, config.TestCommand)
+		if !result.Verify.Passed() {
+			result.Error = verifyFailure(result.Verify)
+			if config.TownRoot != "" {
+				m.notifyMayorVerifyFailed(swarm, result)
+			}
+			return result, nil
+		}
+	}
+
+	// Phase 2: Stop all polecat sessions
 	t := tmux.NewTmux()
 	polecatMgr := polecat.NewSessionManager(t, m.rig)
 
@@ -76,7 +117,7 @@ func (m *Manager) ExecuteLanding(swarmID string, config LandingConfig) (*Landing
 	// Wait for graceful shutdown
 	time.Sleep(2 * time.Second)
 
-	// Phase 2: Git audit (check for code at risk)
+	// Phase 3: Git audit (check for code at risk)
 	if !config.SkipGitAudit {
 		for _, worker := range swarm.Workers {
 			audit := m.auditWorkerGit(worker)
@@ -99,13 +140,23 @@ func (m *Manager) ExecuteLanding(swarmID string, config LandingConfig) (*Landing
 		}
 	}
 
-	// Phase 3: Cleanup branches
+	// Phase 4: Land as a single tagged merge commit
+	mergeCommit, err := m.landSwarm(swarm)
+	result.MergeCommit = mergeCommit
+	if err != nil {
+		_ = m.AbortMerge() // best-effort: leave the target branch clean
+		result.Error = fmt.Sprintf("landing to %s: %v", swarm.TargetBranch, err)
+		return result, nil
+	}
+	result.Tag = LandedTag(swarmID)
+
+	// Phase 5: Cleanup branches
 	if err := m.CleanupBranches(swarmID); err != nil {
 		// Log but continue
 	}
 	result.BranchesCleaned = len(swarm.Tasks) + 1 // tasks + integration
 
-	// Phase 4: Update swarm state
+	// Phase 6: Update swarm state
 	swarm.State = SwarmLanded
 	swarm.UpdatedAt = time.Now()
 
@@ -118,6 +169,76 @@ func (m *Manager) ExecuteLanding(swarmID string, config LandingConfig) (*Landing
 	return result, nil
 }
 
+// verifyLanding checks that the swarm has tasks and every task bead is
+// closed, and runs the test command on the integration branch in a
+// temporary worktree, leaving the rig's checkout alone. A swarm whose tasks
+// failed to load never passes.
+func (m *Manager) verifyLanding(swarm *Swarm, testCommand string) *VerifyResult {
+	result := &VerifyResult{}
+	if swarm.tasksErr != nil {
+		result.TasksError = swarm.tasksErr.Error()
+	} else if len(swarm.Tasks) == 0 {
+		result.NoTasks = true
+	}
+	for _, task := range swarm.Tasks {
+		if task.State != TaskMerged {
+			result.OpenTasks = append(result.OpenTasks, task.IssueID)
+		}
+	}
+
+	if testCommand == "" {
+		return result
+	}
+	result.TestsRun = true
+	tmp, err := os.MkdirTemp("", "gt-swarm-verify-")
+	if err != nil {
+		result.TestOutput = fmt.Sprintf("creating verify worktree: %v", err)
+		return result
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	workDir := filepath.Join(tmp, "integration")
+	if err := m.gitRun("worktree", "add", "--detach", workDir, swarm.Integration); err != nil {
+		result.TestOutput = fmt.Sprintf("checking out %s: %v", swarm.Integration, err)
+		return result
+	}
+	defer func() { _ = m.gitRun("worktree", "remove", "--force", workDir) }()
+
+	// Note: TestCommand comes from rig's config.json (trusted infrastructure config).
+	cmd := exec.Command("sh", "-c", testCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
+	cmd.Dir = workDir
+	out, err := cmd.CombinedOutput()
+	result.TestsPassed = err == nil
+	result.TestOutput = tailLines(string(out), 20)
+	return result
+}
+
+// verifyFailure describes why verification blocked a landing.
+func verifyFailure(v *VerifyResult) string {
+	var reasons []string
+	if v.TasksError != "" {
+		reasons = append(reasons, fmt.Sprintf("loading tasks: %s", v.TasksError))
+	}
+	if v.NoTasks {
+		reasons = append(reasons, "swarm has no tasks")
+	}
+	if len(v.OpenTasks) > 0 {
+		reasons = append(reasons, fmt.Sprintf("tasks not closed: %s", strings.Join(v.OpenTasks, ", ")))
+	}
+	if v.TestsRun && !v.TestsPassed {
+		reasons = append(reasons, "tests failed on the integration branch")
+	}
+	return "verification failed: " + strings.Join(reasons, "; ")
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
 // auditWorkerGit checks a worker's git state for uncommitted/unpushed work.
 func (m *Manager) auditWorkerGit(worker string) GitAuditResult {
 	result := GitAuditResult{
@@ -224,14 +345,67 @@ func (m *Manager) notifyMayorLanded(_ string, swarm *Swarm, result *LandingResul
 
 Swarm: %s
 Target: %s
+Merge commit: %s (tag %s)
 Sessions stopped: %d
 Branches cleaned: %d
-Tasks merged: %d`,
+Tasks merged: %d
+
+Revert with: gt swarm unland %s`,
 			swarm.ID,
 			swarm.TargetBranch,
+			result.MergeCommit,
+			result.Tag,
 			result.SessionsStopped,
 			result.BranchesCleaned,
-			len(swarm.Tasks)),
+			len(swarm.Tasks),
+			swarm.ID),
+	}
+	_ = router.Send(msg) // best-effort notification
+}
+
+// notifyMayorVerifyFailed tells Mayor that verification blocked a landing.
+func (m *Manager) notifyMayorVerifyFailed(swarm *Swarm, result *LandingResult) {
+	router := mail.NewRouter(m.gitDir)
+	body := fmt.Sprintf("Landing blocked for swarm %s.\n\n%s", swarm.ID, result.Error)
+	if v := result.Verify; v.TestsRun && !v.TestsPassed && v.TestOutput != "" {
+		body += fmt.Sprintf("\n\nTest output (%s):\n%s", swarm.Integration, v.TestOutput)
+	}
+	msg := &mail.Message{
+		From:     fmt.Sprintf("%s/refinery", m.rig.Name),
+		To:       "mayor/",
+		Subject:  fmt.Sprintf("Swarm %s failed verification", swarm.ID),
+		Body:     body,
+		Priority: mail.PriorityHigh,
+	}
+	_ = router.Send(msg) // best-effort notification
+}
+
+// NotifyMayorUnlanded sends Mayor the result of reverting a landing.
+// reopenErr is the error from reopening the swarm epic, if any.
+func (m *Manager) NotifyMayorUnlanded(result *UnlandResult, reopenErr error) {
+	epic := "Epic reopened."
+	if reopenErr != nil {
+		epic = fmt.Sprintf("Could not reopen epic: %v", reopenErr)
+	}
+	router := mail.NewRouter(m.gitDir)
+	msg := &mail.Message{
+		From:    fmt.Sprintf("%s/refinery", m.rig.Name),
+		To:      "mayor/",
+		Subject: fmt.Sprintf("Swarm %s unlanded", result.SwarmID),
+		Body: fmt.Sprintf(`Swarm landing reverted.
+
+Swarm: %s
+Target: %s
+Landing merge: %s
+Revert commit: %s
+
+%s`,
+			result.SwarmID,
+			result.Target,
+			result.MergeCommit,
+			result.RevertCommit,
+			epic),
+		Priority: mail.PriorityHigh,
 	}
 	_ = router.Send(msg) // best-effort notification
 }
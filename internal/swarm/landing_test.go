@@ -0,0 +1,72 @@
+package swarm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerifyLanding(t *testing.T) {
+	m, sw := setupLandingRepo(t)
+
+	// The test command runs on the integration branch
+	v := m.verifyLanding(sw, "test -f feature.txt")
+	if !v.TestsRun || !v.TestsPassed || !v.Passed() {
+		t.Errorf("passing verification = %+v", v)
+	}
+	// ...without moving the rig's checkout off its branch
+	if head := runGit(t, m.gitDir, "rev-parse", "--abbrev-ref", "HEAD"); head != "main" {
+		t.Errorf("rig HEAD after verify = %s, want main", head)
+	}
+	if list := runGit(t, m.gitDir, "worktree", "list"); strings.Count(list, "\n") != 0 {
+		t.Errorf("verify worktree left registered:\n%s", list)
+	}
+
+	v = m.verifyLanding(sw, "echo boom; exit 1")
+	if v.Passed() || v.TestOutput != "boom" {
+		t.Errorf("failing tests = %+v, want failure with output", v)
+	}
+	if msg := verifyFailure(v); msg != "verification failed: tests failed on the integration branch" {
+		t.Errorf("verifyFailure = %q", msg)
+	}
+
+	sw.Tasks = append(sw.Tasks, SwarmTask{IssueID: "gt-t2", State: TaskInProgress})
+	v = m.verifyLanding(sw, "")
+	if v.TestsRun || v.Passed() || len(v.OpenTasks) != 1 || v.OpenTasks[0] != "gt-t2" {
+		t.Errorf("open task verification = %+v", v)
+	}
+	if msg := verifyFailure(v); !strings.Contains(msg, "tasks not closed: gt-t2") {
+		t.Errorf("verifyFailure = %q", msg)
+	}
+}
+
+func TestVerifyLanding_NoTasks(t *testing.T) {
+	m, sw := setupLandingRepo(t)
+
+	sw.Tasks = nil
+	v := m.verifyLanding(sw, "true")
+	if v.Passed() || !v.NoTasks {
+		t.Errorf("verification without tasks = %+v, want failure", v)
+	}
+	if msg := verifyFailure(v); msg != "verification failed: swarm has no tasks" {
+		t.Errorf("verifyFailure = %q", msg)
+	}
+
+	sw.tasksErr = errors.New("bd list: connection refused")
+	v = m.verifyLanding(sw, "true")
+	if v.Passed() || v.NoTasks || v.TasksError != "bd list: connection refused" {
+		t.Errorf("verification with unloadable tasks = %+v, want failure", v)
+	}
+	if msg := verifyFailure(v); !strings.Contains(msg, "loading tasks: bd list: connection refused") {
+		t.Errorf("verifyFailure = %q", msg)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	if got := tailLines("a\nb\nc\n", 2); got != "b\nc" {
+		t.Errorf("tailLines = %q, want b\\nc", got)
+	}
+	if got := tailLines("a", 5); got != "a" {
+		t.Errorf("tailLines = %q, want a", got)
+	}
+}
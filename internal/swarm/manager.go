@@ -52,8 +52,9 @@ func (m *Manager) LoadSwarm(epicID string) (*Swarm, error) {
 		return nil, fmt.Errorf("bd show: %s", strings.TrimSpace(stderr.String()))
 	}
 
-	// Parse the epic
-	var epic struct {
+	// Parse the epic (bd show --json returns an array with one element;
+	// older versions returned the object itself)
+	type epicJSON struct {
 		ID        string `json:"id"`
 		Title     string `json:"title"`
 		Status    string `json:"status"`
@@ -61,7 +62,14 @@ func (m *Manager) LoadSwarm(epicID string) (*Swarm, error) {
 		CreatedAt string `json:"created_at"`
 		UpdatedAt string `json:"updated_at"`
 	}
-	if err := json.Unmarshal(stdout.Bytes(), &epic); err != nil {
+	var epic epicJSON
+	var epics []epicJSON
+	if err := json.Unmarshal(stdout.Bytes(), &epics); err == nil {
+		if len(epics) == 0 {
+			return nil, ErrSwarmNotFound
+		}
+		epic = epics[0]
+	} else if err := json.Unmarshal(stdout.Bytes(), &epic); err != nil {
 		return nil, fmt.Errorf("parsing epic: %w", err)
 	}
 
@@ -95,8 +103,11 @@ func (m *Manager) LoadSwarm(epicID string) (*Swarm, error) {
 	}
 
 	// Load tasks from beads (children of the epic)
+	// (a failure is kept on the swarm so landing verification can refuse it)
 	tasks, err := m.loadTasksFromBeads(epicID)
-	if err == nil {
+	if err != nil {
+		swarm.tasksErr = err
+	} else {
 		swarm.Tasks = tasks
 		// Discover workers from assigned tasks
 		for _, task := range tasks {
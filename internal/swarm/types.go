@@ -74,6 +74,9 @@ type Swarm struct {
 
 	// Error contains error details if State is SwarmFailed.
 	Error string `json:"error,omitempty"`
+
+	// tasksErr is why Tasks could not be loaded, if they couldn't.
+	tasksErr error
 }
 
 // SwarmTask represents a single task in the swarm.
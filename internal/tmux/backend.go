@@ -0,0 +1,80 @@
+package tmux
+
+import "time"
+
+// SessionBackend is the set of session operations Gas Town relies on to run
+// agents: create, kill, send keys, capture, has/list, pane PID and the
+// startup handshake (waiting for the agent, accepting its permissions dialog).
+//
+// *Tmux is the default implementation. Other backends (for example the
+// headless PTY supervisor in internal/ptyd) implement the same contract so
+// lifecycle code can run where tmux isn't installed, and tests can use an
+// in-memory fake.
+//
+// Backends should return ErrSessionExists and ErrSessionNotFound where tmux
+// would, so callers can match errors with errors.Is regardless of backend.
+type SessionBackend interface {
+	// NewSessionWithCommand creates a detached session running command in workDir.
+	NewSessionWithCommand(name, workDir, command string) error
+
+	// KillSession terminates a session and the processes running in it.
+	KillSession(name string) error
+
+	// HasSession reports whether a session exists.
+	HasSession(name string) (bool, error)
+
+	// ListSessions returns the names of all sessions.
+	ListSessions() ([]string, error)
+
+	// SendKeys sends literal text followed by Enter.
+	SendKeys(session, keys string) error
+
+	// SendKeysRaw sends a tmux key name such as "C-c", "Enter" or "Down"
+	// without appending Enter.
+	SendKeysRaw(session, keys string) error
+
+	// NudgeSession reliably delivers a message to an agent's prompt.
+	NudgeSession(session, message string) error
+
+	// CapturePane returns the last N lines of session output.
+	CapturePane(session string, lines int) (string, error)
+
+	// GetPanePID returns the PID of the session's main process.
+	GetPanePID(session string) (string, error)
+
+	// SetEnvironment records an environment variable on the session.
+	SetEnvironment(session, key, value string) error
+
+	// AttachSession connects the current terminal to a session.
+	AttachSession(session string) error
+
+	// WaitForCommand polls until the session's foreground command is not one
+	// of excludeCommands (typically shells), or timeout elapses.
+	WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error
+
+	// AcceptBypassPermissionsWarning dismisses Claude's bypass permissions
+	// dialog if it is showing.
+	AcceptBypassPermissionsWarning(session string) error
+}
+
+// Compile-time check that Tmux satisfies SessionBackend.
+var _ SessionBackend = (*Tmux)(nil)
+
+// AsTmux returns the concrete *Tmux behind a backend, if any.
+// Callers use it for tmux-only decoration (themes, status lines, hooks)
+// that has no equivalent in other backends.
+func AsTmux(b SessionBackend) (*Tmux, bool) {
+	t, ok := b.(*Tmux)
+	return t, ok && t != nil
+}
+
+// IsAgentAlive reports whether an agent is running in a session.
+// For tmux this inspects the pane process tree; other backends end the
+// session when the agent exits, so session existence is sufficient.
+func IsAgentAlive(b SessionBackend, session string) bool {
+	if t, ok := AsTmux(b); ok {
+		return t.IsClaudeRunning(session)
+	}
+	has, err := b.HasSession(session)
+	return err == nil && has
+}
@@ -0,0 +1,341 @@
+// Package townbackup exports a town's state to a versioned tarball and
+// restores it elsewhere.
+//
+// An export holds what can't be recovered from the rigs' git remotes: town
+// and rig configs, beads JSONL exports (including agent beads), formulas,
+// plugins, the daemon patrol config, the audit-chained town logs with their
+// anchors and public key and, optionally, polecat checkpoints.
+// Rig repos themselves are re-cloned from their git URLs on import.
+package townbackup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/townlog"
+)
+
+// FormatVersion is the current export format version. Imports reject
+// archives from a newer version.
+const FormatVersion = 1
+
+// ManifestName is the manifest's name inside the archive. It is always the
+// first entry.
+const ManifestName = "manifest.json"
+
+// File kinds beyond the config kinds accepted by config.Validate.
+const (
+	KindBeads      = "beads"      // beads JSONL export
+	KindRoutes     = "routes"     // town routes.jsonl, rewired on import
+	KindCheckpoint = "checkpoint" // polecat checkpoint
+	KindAuditLog   = "audit-log"  // audit-chained log, verified against the anchors
+)
+
+// Manifest describes an export.
+type Manifest struct {
+	Version     int           `json:"version"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Town        string        `json:"town"`
+	GTVersion   string        `json:"gt_version,omitempty"`
+	Checkpoints bool          `json:"checkpoints"`
+	Rigs        []Rig         `json:"rigs"`
+	Routes      []beads.Route `json:"routes,omitempty"`
+	Files       []File        `json:"files"`
+}
+
+// Rig is a rig to re-clone on import.
+type Rig struct {
+	Name          string `json:"name"`
+	GitURL        string `json:"git_url"`
+	Prefix        string `json:"prefix,omitempty"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+}
+
+// File is a file in the export.
+type File struct {
+	Path string      `json:"path"`           // slash-separated, relative to the town root
+	Kind string      `json:"kind,omitempty"` // config kind, or one of the Kind constants
+	Mode fs.FileMode `json:"mode"`
+}
+
+// Options controls what an export includes.
+type Options struct {
+	Checkpoints bool   // include polecat checkpoints
+	GTVersion   string // recorded in the manifest
+}
+
+// townFiles are the town-level files in mayor/, by kind.
+var townFiles = map[string]string{
+	constants.FileTownJSON:            config.KindTown,
+	constants.FileRigsJSON:            config.KindRigs,
+	constants.FileConfigJSON:          config.KindMayor,
+	config.DaemonPatrolConfigFileName: config.KindDaemonPatrol,
+	constants.FileAccountsJSON:        config.KindAccounts,
+	"overseer.json":                   config.KindOverseer,
+	"audit-key.pub":                   "",
+	"audit-anchors.jsonl":             "",
+}
+
+// Collect builds the manifest of a town's export.
+func Collect(townRoot string, opts Options, now time.Time) (*Manifest, error) {
+	townConfig, err := config.LoadTownConfig(constants.MayorTownPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town config: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+	routes, err := beads.LoadRoutes(filepath.Join(townRoot, constants.DirBeads))
+	if err != nil {
+		return nil, fmt.Errorf("loading routes: %w", err)
+	}
+
+	m := &Manifest{
+		Version:     FormatVersion,
+		CreatedAt:   now.UTC(),
+		Town:        townConfig.Name,
+		GTVersion:   opts.GTVersion,
+		Checkpoints: opts.Checkpoints,
+		Routes:      routes,
+	}
+	c := &collector{root: townRoot}
+
+	for name, kind := range townFiles {
+		c.file(path.Join(constants.DirMayor, name), kind)
+	}
+	// The chained logs go with their anchors, so the restored town verifies
+	c.file(events.EventsFile, KindAuditLog)
+	if rel, err := filepath.Rel(townRoot, townlog.LogPath(townRoot)); err == nil {
+		c.file(filepath.ToSlash(rel), KindAuditLog)
+	}
+	c.tree(constants.DirSettings, settingsKind)
+	c.tree("config", func(rel string) string {
+		if rel == "config/messaging.json" {
+			return config.KindMessaging
+		}
+		return ""
+	})
+	c.tree("plugins", nil)
+	c.tree("templates", nil)
+	c.beads(constants.DirBeads)
+
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry := rigsConfig.Rigs[name]
+		r := Rig{Name: name, GitURL: entry.GitURL}
+		if entry.BeadsConfig != nil {
+			r.Prefix = entry.BeadsConfig.Prefix
+		}
+		if rc, err := rig.LoadRigConfig(filepath.Join(townRoot, name)); err == nil {
+			r.DefaultBranch = rc.DefaultBranch
+		}
+		m.Rigs = append(m.Rigs, r)
+
+		c.file(path.Join(name, constants.FileConfigJSON), config.KindRig)
+		c.tree(path.Join(name, constants.DirSettings), settingsKind)
+		c.tree(path.Join(name, "plugins"), nil)
+		c.beads(path.Join(name, constants.DirBeads))
+		c.beads(path.Join(name, constants.DirMayor, constants.DirRig, constants.DirBeads))
+		if opts.Checkpoints {
+			c.checkpoints(path.Join(name, constants.DirPolecats))
+		}
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	sort.Slice(c.files, func(i, j int) bool { return c.files[i].Path < c.files[j].Path })
+	m.Files = c.files
+	return m, nil
+}
+
+// settingsKind returns the kind of a file in a town or rig settings/ dir.
+func settingsKind(rel string) string {
+	switch path.Base(rel) {
+	case "escalation.json":
+		return config.KindEscalation
+	case constants.FileConfigJSON:
+		// Town settings have no validator; rig settings do
+		if strings.Count(rel, "/") > 1 {
+			return config.KindRigSettings
+		}
+	}
+	return ""
+}
+
+// collector gathers the files of an export.
+type collector struct {
+	root  string
+	files []File
+	err   error
+}
+
+// file adds a single file if it exists.
+func (c *collector) file(rel, kind string) {
+	info, err := os.Stat(filepath.Join(c.root, filepath.FromSlash(rel)))
+	if err != nil {
+		if !os.IsNotExist(err) && c.err == nil {
+			c.err = err
+		}
+		return
+	}
+	if info.Mode().IsRegular() {
+		c.files = append(c.files, File{Path: rel, Kind: kind, Mode: info.Mode().Perm()})
+	}
+}
+
+// tree adds every regular file under dir. kind may be nil.
+func (c *collector) tree(dir string, kind func(rel string) string) {
+	c.walk(dir, func(rel string) (bool, string) {
+		if kind == nil {
+			return true, ""
+		}
+		return true, kind(rel)
+	})
+}
+
+// beads adds a beads directory's JSONL exports, config and formulas. The
+// database is left out; bd rebuilds it from the JSONL.
+func (c *collector) beads(dir string) {
+	c.walk(dir, func(rel string) (bool, string) {
+		sub := strings.TrimPrefix(rel, dir+"/")
+		switch {
+		case sub == beads.RoutesFileName:
+			if dir == constants.DirBeads {
+				return true, KindRoutes
+			}
+			return false, ""
+		case strings.HasPrefix(sub, "formulas/"):
+			return true, ""
+		case sub == "config.yaml":
+			return true, ""
+		case !strings.Contains(sub, "/") && strings.HasSuffix(sub, ".jsonl"):
+			return true, KindBeads
+		}
+		return false, ""
+	})
+}
+
+// checkpoints adds the checkpoint files of a rig's polecats. A polecat's
+// checkpoint sits in its directory or in the worktree inside it.
+func (c *collector) checkpoints(dir string) {
+	for _, pattern := range []string{"*", "*/*"} {
+		matches, err := filepath.Glob(filepath.Join(c.root, filepath.FromSlash(dir), pattern, checkpoint.Filename))
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			if rel, err := filepath.Rel(c.root, match); err == nil {
+				c.file(filepath.ToSlash(rel), KindCheckpoint)
+			}
+		}
+	}
+}
+
+// walk adds the regular files under dir that include accepts. Lock files
+// are always skipped.
+func (c *collector) walk(dir string, include func(rel string) (bool, string)) {
+	if c.err != nil {
+		return
+	}
+	base := filepath.Join(c.root, filepath.FromSlash(dir))
+	err := filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasSuffix(d.Name(), ".lock") {
+			return nil
+		}
+		rel, err := filepath.Rel(c.root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		ok, kind := include(rel)
+		if !ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		c.files = append(c.files, File{Path: rel, Kind: kind, Mode: info.Mode().Perm()})
+		return nil
+	})
+	if err != nil {
+		c.err = fmt.Errorf("collecting %s: %w", dir, err)
+	}
+}
+
+// Export writes the files listed in m, from townRoot, to w as a gzipped
+// tarball with the manifest first.
+func Export(townRoot string, m *Manifest, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := writeEntry(tw, ManifestName, 0644, m.CreatedAt, data); err != nil {
+		return err
+	}
+
+	for _, f := range m.Files {
+		data, err := os.ReadFile(filepath.Join(townRoot, filepath.FromSlash(f.Path))) //nolint:gosec // G304: path comes from the town walk
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", f.Path, err)
+		}
+		if err := writeEntry(tw, f.Path, f.Mode, m.CreatedAt, data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, mode fs.FileMode, modTime time.Time, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    int64(mode),
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
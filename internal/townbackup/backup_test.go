@@ -0,0 +1,258 @@
+package townbackup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// writeFiles creates files under root from a path → content map.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for p, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// sourceTown is a town with one rig and a polecat checkpoint.
+var sourceTown = map[string]string{
+	"mayor/town.json":   `{"type":"town","version":2,"name":"hq"}`,
+	"mayor/rigs.json":   `{"version":1,"rigs":{"gastown":{"git_url":"https://example.com/gastown.git","beads":{"repo":"","prefix":"gt"}}}}`,
+	"mayor/daemon.json": `{"type":"daemon-patrol-config","version":1}`,
+	"mayor/CLAUDE.md":   "not exported",
+
+	"settings/config.json":     `{"type":"town-settings","version":1}`,
+	"plugins/digest/plugin.md": "# digest",
+
+	".beads/issues.jsonl":         `{"id":"hq-1","title":"mayor agent"}` + "\n",
+	".beads/routes.jsonl":         `{"prefix":"hq-","path":"."}` + "\n" + `{"prefix":"gt-","path":"gastown/mayor/rig"}` + "\n" + `{"prefix":"old-","path":"gone"}` + "\n",
+	".beads/beads.db":             "sqlite",
+	".beads/issues.jsonl.lock":    "",
+	".beads/formulas/patrol.toml": "formula = 'patrol'",
+
+	"mayor/audit-key.pub":       "cHVibGljIGtleQ==\n",
+	"mayor/audit-anchors.jsonl": `{"log":"events","seq":1,"hash":"ab"}` + "\n",
+	".events.jsonl":             `{"type":"sling","_seq":1,"_hash":"ab"}` + "\n",
+	"logs/town.log":             "2026-10-18 12:00:00 [spawn] gastown/polecats/toast spawned #1 ab\n",
+
+	"gastown/config.json":          `{"type":"rig","version":1,"name":"gastown","git_url":"https://example.com/gastown.git","default_branch":"main"}`,
+	"gastown/settings/config.json": `{"type":"rig-settings","version":1,"namepool":{"style":"mad-max"}}`,
+	"gastown/.beads/issues.jsonl":  `{"id":"gt-1"}` + "\n",
+
+	"gastown/polecats/toast/.polecat-checkpoint.json":           `{"polecat":"toast"}`,
+	"gastown/polecats/nux/gastown/.polecat-checkpoint.json":     `{"polecat":"nux"}`,
+	"gastown/polecats/nux/gastown/src/.polecat-checkpoint.json": `{"deep":true}`,
+}
+
+func exportTown(t *testing.T, opts Options) (*Manifest, []byte) {
+	t.Helper()
+	root := t.TempDir()
+	writeFiles(t, root, sourceTown)
+	m, err := Collect(root, opts, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Export(root, m, &buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	return m, buf.Bytes()
+}
+
+func filePaths(m *Manifest) string {
+	var paths []string
+	for _, f := range m.Files {
+		paths = append(paths, f.Path)
+	}
+	return strings.Join(paths, ",")
+}
+
+func TestCollect(t *testing.T) {
+	m, _ := exportTown(t, Options{})
+	want := strings.Join([]string{
+		".beads/formulas/patrol.toml",
+		".beads/issues.jsonl",
+		".beads/routes.jsonl",
+		".events.jsonl",
+		"gastown/.beads/issues.jsonl",
+		"gastown/config.json",
+		"gastown/settings/config.json",
+		"logs/town.log",
+		"mayor/audit-anchors.jsonl",
+		"mayor/audit-key.pub",
+		"mayor/daemon.json",
+		"mayor/rigs.json",
+		"mayor/town.json",
+		"plugins/digest/plugin.md",
+		"settings/config.json",
+	}, ",")
+	if got := filePaths(m); got != want {
+		t.Errorf("files =\n  %s\nwant\n  %s", got, want)
+	}
+	if m.Town != "hq" || len(m.Rigs) != 1 || m.Rigs[0].DefaultBranch != "main" || m.Rigs[0].Prefix != "gt" {
+		t.Errorf("manifest = %+v", m)
+	}
+	if len(m.Routes) != 3 {
+		t.Errorf("routes = %+v", m.Routes)
+	}
+
+	m, _ = exportTown(t, Options{Checkpoints: true})
+	var checkpoints []string
+	for _, f := range m.Files {
+		if f.Kind == KindCheckpoint {
+			checkpoints = append(checkpoints, f.Path)
+		}
+	}
+	if got := strings.Join(checkpoints, ","); got != "gastown/polecats/nux/gastown/.polecat-checkpoint.json,gastown/polecats/toast/.polecat-checkpoint.json" {
+		t.Errorf("checkpoints = %s", got)
+	}
+}
+
+func TestReadValidate(t *testing.T) {
+	_, data := exportTown(t, Options{})
+	a, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if errs := a.Validate(); len(errs) != 0 {
+		t.Errorf("Validate = %v", errs)
+	}
+	if got := string(a.Data("mayor/town.json")); got != sourceTown["mayor/town.json"] {
+		t.Errorf("town.json = %q", got)
+	}
+
+	// A config from a newer gt fails its schema check
+	a.data["mayor/town.json"] = []byte(`{"type":"town","version":99,"name":"hq"}`)
+	a.data["gastown/.beads/issues.jsonl"] = []byte("{\"id\":\"gt-1\"}\n{oops\n")
+	errs := a.Validate()
+	if len(errs) != 2 {
+		t.Fatalf("Validate = %v, want 2 errors", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "line 2") || !strings.Contains(errs[1].Error(), "unsupported config version") {
+		t.Errorf("Validate = %v", errs)
+	}
+
+	if _, err := Read(strings.NewReader("not a tarball")); err == nil {
+		t.Error("Read accepted garbage")
+	}
+}
+
+func TestPlanRestore(t *testing.T) {
+	m, data := exportTown(t, Options{Checkpoints: true})
+	a, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	// A freshly installed town with its own issues
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"mayor/town.json":      `{"type":"town","version":2,"name":"new"}`,
+		"mayor/rigs.json":      `{"version":1,"rigs":{}}`,
+		"settings/config.json": sourceTown["settings/config.json"],
+		".beads/issues.jsonl":  `{"id":"hq-9"}` + "\n",
+		".beads/routes.jsonl":  `{"prefix":"hq-","path":"."}` + "\n",
+		".events.jsonl":        `{"type":"boot"}` + "\n",
+	})
+
+	p := a.Plan(root)
+	ops := make(map[string]FileAction)
+	for _, f := range p.Files {
+		ops[f.Path] = f
+	}
+	for path, want := range map[string]string{
+		"mayor/town.json":      OpReplace,
+		"mayor/rigs.json":      OpMerge,
+		"settings/config.json": OpUnchanged,
+		".beads/issues.jsonl":  OpReplace,
+		".events.jsonl":        OpReplace,
+		"logs/town.log":        OpCreate,
+		"gastown/config.json":  OpCreate,
+		"gastown/polecats/toast/.polecat-checkpoint.json": OpSkip,
+	} {
+		if got := ops[path].Op; got != want {
+			t.Errorf("%s: op %q, want %q", path, got, want)
+		}
+	}
+	if ops["gastown/polecats/toast/.polecat-checkpoint.json"].Reason == "" {
+		t.Error("skipped checkpoint has no reason")
+	}
+	if p.Conflicts() != 2 || ops[".beads/issues.jsonl"].Conflict == "" || ops[".events.jsonl"].Conflict == "" {
+		t.Errorf("conflicts = %d, plan %+v", p.Conflicts(), p.Files)
+	}
+	if len(p.Rigs) != 1 || p.Rigs[0].Op != OpClone || p.RigConflicts() {
+		t.Errorf("rigs = %+v", p.Rigs)
+	}
+
+	// Without force the conflicting beads export is left alone
+	if _, err := a.Restore(root, p, false); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, ".beads/issues.jsonl")); string(got) != `{"id":"hq-9"}`+"\n" {
+		t.Errorf("issues.jsonl overwritten without force: %q", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, "gastown/settings/config.json")); string(got) != sourceTown["gastown/settings/config.json"] {
+		t.Errorf("rig settings = %q", got)
+	}
+	rigs, err := config.LoadRigsConfig(filepath.Join(root, "mayor/rigs.json"))
+	if err != nil || rigs.Rigs["gastown"].GitURL != "https://example.com/gastown.git" {
+		t.Errorf("merged rigs = %+v, %v", rigs, err)
+	}
+	if _, err := a.Restore(root, p, true); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, ".beads/issues.jsonl")); string(got) != sourceTown[".beads/issues.jsonl"] {
+		t.Errorf("issues.jsonl not replaced with force: %q", got)
+	}
+
+	// Once restored, the rig is there with the same repo
+	if p := a.Plan(root); p.Rigs[0].Op != OpExists || p.RigConflicts() {
+		t.Errorf("replanned rigs = %+v", p.Rigs)
+	}
+
+	// The rig's beads aren't tracked in this clone, so its route moves to
+	// the rig root; the route to a missing rig is dropped
+	dropped, err := RewireRoutes(root, m)
+	if err != nil {
+		t.Fatalf("RewireRoutes: %v", err)
+	}
+	if len(dropped) != 1 || dropped[0].Prefix != "old-" {
+		t.Errorf("dropped = %+v", dropped)
+	}
+	routes, _ := beads.LoadRoutes(filepath.Join(root, ".beads"))
+	var got []string
+	for _, r := range routes {
+		got = append(got, r.Prefix+r.Path)
+	}
+	if strings.Join(got, ",") != "hq-.,gt-gastown" {
+		t.Errorf("routes = %v", got)
+	}
+}
+
+func TestPlanRigConflict(t *testing.T) {
+	_, data := exportTown(t, Options{})
+	a, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"gastown/config.json": `{"type":"rig","version":1,"name":"gastown","git_url":"https://example.com/fork.git"}`,
+	})
+	p := a.Plan(root)
+	if !p.RigConflicts() || !strings.Contains(p.Rigs[0].Conflict, "fork.git") {
+		t.Errorf("rigs = %+v", p.Rigs)
+	}
+}
@@ -0,0 +1,392 @@
+package townbackup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// Archive is an export read back into memory.
+type Archive struct {
+	Manifest *Manifest
+	data     map[string][]byte
+}
+
+// Read reads an export written by Export.
+func Read(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a town export: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	a := &Archive{data: make(map[string][]byte)}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading export: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", hdr.Name, err)
+		}
+
+		if a.Manifest == nil {
+			if hdr.Name != ManifestName {
+				return nil, fmt.Errorf("not a town export: first entry is %s, not %s", hdr.Name, ManifestName)
+			}
+			var m Manifest
+			if err := json.Unmarshal(data, &m); err != nil {
+				return nil, fmt.Errorf("parsing manifest: %w", err)
+			}
+			if m.Version < 1 || m.Version > FormatVersion {
+				return nil, fmt.Errorf("unsupported export version %d (max supported %d)", m.Version, FormatVersion)
+			}
+			a.Manifest = &m
+			continue
+		}
+		if !safePath(hdr.Name) {
+			return nil, fmt.Errorf("export contains unsafe path %q", hdr.Name)
+		}
+		a.data[hdr.Name] = data
+	}
+	if a.Manifest == nil {
+		return nil, errors.New("not a town export: empty archive")
+	}
+	for _, f := range a.Manifest.Files {
+		if !safePath(f.Path) {
+			return nil, fmt.Errorf("manifest lists unsafe path %q", f.Path)
+		}
+		if _, ok := a.data[f.Path]; !ok {
+			return nil, fmt.Errorf("export is missing %s", f.Path)
+		}
+	}
+	return a, nil
+}
+
+// safePath reports whether p stays inside the town root.
+func safePath(p string) bool {
+	return p != "" && !path.IsAbs(p) && path.Clean(p) == p && p != ".." && !strings.HasPrefix(p, "../")
+}
+
+// Data returns the contents of a file in the export.
+func (a *Archive) Data(p string) []byte {
+	return a.data[p]
+}
+
+// Validate checks every config in the export with config.Validate and every
+// beads export for malformed lines.
+func (a *Archive) Validate() []error {
+	var errs []error
+	for _, f := range a.Manifest.Files {
+		data := a.data[f.Path]
+		switch f.Kind {
+		case "", KindCheckpoint, KindRoutes, KindAuditLog:
+		case KindBeads:
+			if line := badJSONLine(data); line > 0 {
+				errs = append(errs, fmt.Errorf("%s: line %d is not valid JSON", f.Path, line))
+			}
+		default:
+			if err := config.Validate(f.Kind, data); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.Path, err))
+			}
+		}
+	}
+	return errs
+}
+
+// badJSONLine returns the 1-based number of the first malformed line of a
+// JSONL file, or 0.
+func badJSONLine(data []byte) int {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	n := 0
+	for scanner.Scan() {
+		n++
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 && !json.Valid(line) {
+			return n
+		}
+	}
+	return 0
+}
+
+// Import operations.
+const (
+	OpCreate    = "create"    // file doesn't exist yet
+	OpReplace   = "replace"   // file exists with other content
+	OpUnchanged = "unchanged" // file exists with the same content
+	OpMerge     = "merge"     // rigs.json and routes.jsonl are merged
+	OpSkip      = "skip"      // checkpoint for a polecat that isn't here
+	OpClone     = "clone"     // rig will be cloned
+	OpExists    = "exists"    // rig is already here
+)
+
+// FileAction is what an import does with one file.
+type FileAction struct {
+	Path     string `json:"path"`
+	Kind     string `json:"kind,omitempty"`
+	Op       string `json:"op"`
+	Conflict string `json:"conflict,omitempty"`
+	Reason   string `json:"reason,omitempty"` // why the file is skipped
+}
+
+// RigAction is what an import does with one rig.
+type RigAction struct {
+	Rig
+	Op       string `json:"op"`
+	Conflict string `json:"conflict,omitempty"`
+}
+
+// Plan is what importing an export into a town would do.
+type Plan struct {
+	Files []FileAction `json:"files"`
+	Rigs  []RigAction  `json:"rigs"`
+}
+
+// Conflicts returns the number of conflicts in the plan.
+func (p *Plan) Conflicts() int {
+	n := 0
+	for _, f := range p.Files {
+		if f.Conflict != "" {
+			n++
+		}
+	}
+	for _, r := range p.Rigs {
+		if r.Conflict != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// RigConflicts reports whether any rig conflicts. Rig conflicts can't be
+// forced: the existing directory has to be moved first.
+func (p *Plan) RigConflicts() bool {
+	for _, r := range p.Rigs {
+		if r.Conflict != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Plan works out what importing the archive into townRoot would do.
+//
+// Configs are replaced. Beads exports that would replace existing, different
+// issues are conflicts, as are rigs whose directory holds another repo.
+func (a *Archive) Plan(townRoot string) *Plan {
+	p := &Plan{}
+	for _, r := range a.Manifest.Rigs {
+		p.Rigs = append(p.Rigs, planRig(townRoot, r))
+	}
+
+	for _, f := range a.Manifest.Files {
+		fa := FileAction{Path: f.Path, Kind: f.Kind}
+		target := filepath.Join(townRoot, filepath.FromSlash(f.Path))
+		existing, err := os.ReadFile(target) //nolint:gosec // G304: path checked by safePath
+		switch {
+		case f.Kind == KindRoutes:
+			fa.Op = OpMerge
+		case f.Kind == config.KindRigs:
+			fa.Op = OpMerge
+		case f.Kind == KindCheckpoint && !dirExists(filepath.Dir(target)):
+			// Creating the directory would leave a polecat without a
+			// worktree, so the checkpoint stays in the export
+			fa.Op = OpSkip
+			fa.Reason = path.Dir(f.Path) + " isn't in this town"
+		case err != nil:
+			fa.Op = OpCreate
+		case bytes.Equal(existing, a.data[f.Path]):
+			fa.Op = OpUnchanged
+		default:
+			fa.Op = OpReplace
+			if f.Kind == KindBeads && len(bytes.TrimSpace(existing)) > 0 {
+				fa.Conflict = "existing beads would be replaced"
+			}
+			if f.Kind == KindAuditLog && len(bytes.TrimSpace(existing)) > 0 {
+				fa.Conflict = "existing audit log would be replaced"
+			}
+		}
+		p.Files = append(p.Files, fa)
+	}
+	return p
+}
+
+// planRig decides whether a rig needs cloning.
+func planRig(townRoot string, r Rig) RigAction {
+	ra := RigAction{Rig: r, Op: OpClone}
+	rigPath := filepath.Join(townRoot, r.Name)
+	if !dirExists(rigPath) {
+		if r.GitURL == "" {
+			ra.Conflict = "no git URL to clone from"
+		}
+		return ra
+	}
+	ra.Op = OpExists
+	rc, err := rig.LoadRigConfig(rigPath)
+	switch {
+	case err != nil:
+		ra.Conflict = "directory exists but isn't a rig"
+	case rc.GitURL != r.GitURL:
+		ra.Conflict = fmt.Sprintf("directory holds %s", rc.GitURL)
+	}
+	return ra
+}
+
+func dirExists(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && info.IsDir()
+}
+
+// Restore writes the archive's files into townRoot as planned. Replacements
+// that conflict are written only with force. Returns the paths written.
+func (a *Archive) Restore(townRoot string, p *Plan, force bool) ([]string, error) {
+	modes := make(map[string]fs.FileMode, len(a.Manifest.Files))
+	for _, f := range a.Manifest.Files {
+		modes[f.Path] = f.Mode
+	}
+
+	var written []string
+	for _, fa := range p.Files {
+		target := filepath.Join(townRoot, filepath.FromSlash(fa.Path))
+		switch {
+		case fa.Op == OpUnchanged || fa.Op == OpSkip || fa.Kind == KindRoutes:
+			continue
+		case fa.Conflict != "" && !force:
+			continue
+		case fa.Kind == config.KindRigs:
+			if err := mergeRigs(townRoot, target, a.data[fa.Path]); err != nil {
+				return written, err
+			}
+		default:
+			mode := modes[fa.Path]
+			if mode == 0 {
+				mode = 0644
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return written, err
+			}
+			if err := os.WriteFile(target, a.data[fa.Path], mode); err != nil {
+				return written, fmt.Errorf("writing %s: %w", fa.Path, err)
+			}
+		}
+		written = append(written, fa.Path)
+	}
+	return written, nil
+}
+
+// mergeRigs adds the exported entries of rigs present in the town to its
+// rigs.json, keeping the town's own entries for rigs it already registered.
+// Rigs that weren't cloned stay unregistered so a re-run can clone them.
+func mergeRigs(townRoot, target string, data []byte) error {
+	var archived config.RigsConfig
+	if err := json.Unmarshal(data, &archived); err != nil {
+		return fmt.Errorf("parsing exported rigs config: %w", err)
+	}
+	current, err := config.LoadRigsConfig(target)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			return err
+		}
+		current = &config.RigsConfig{Version: config.CurrentRigsVersion, Rigs: make(map[string]config.RigEntry)}
+	}
+	for name, entry := range archived.Rigs {
+		if _, ok := current.Rigs[name]; ok || !dirExists(filepath.Join(townRoot, name)) {
+			continue
+		}
+		// A reference repo from the old machine is rarely at the same path
+		if entry.LocalRepo != "" && !dirExists(entry.LocalRepo) {
+			entry.LocalRepo = ""
+		}
+		current.Rigs[name] = entry
+	}
+	return config.SaveRigsConfig(target, current)
+}
+
+// RigRoutePath returns the route path for a rig's beads: mayor/rig when the
+// rig's repo tracks its beads, otherwise the rig root.
+func RigRoutePath(townRoot, name string) string {
+	if dirExists(filepath.Join(townRoot, name, constants.DirMayor, constants.DirRig, constants.DirBeads)) {
+		return name + "/" + constants.DirMayor + "/" + constants.DirRig
+	}
+	return name
+}
+
+// RewireRoutes merges the export's routes into the town's routes.jsonl.
+// Each rig's prefix is pointed at where its beads live in this town; routes
+// to rigs that aren't here, or to beads directories that don't exist, are
+// dropped and returned.
+func RewireRoutes(townRoot string, m *Manifest) ([]beads.Route, error) {
+	beadsDir := filepath.Join(townRoot, constants.DirBeads)
+	routes, err := beads.LoadRoutes(beadsDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading routes: %w", err)
+	}
+	index := make(map[string]int, len(routes))
+	for i, r := range routes {
+		index[r.Prefix] = i
+	}
+	set := func(r beads.Route) {
+		if i, ok := index[r.Prefix]; ok {
+			routes[i] = r
+			return
+		}
+		index[r.Prefix] = len(routes)
+		routes = append(routes, r)
+	}
+
+	rigPrefixes := make(map[string]string)
+	for _, r := range m.Rigs {
+		if r.Prefix != "" {
+			rigPrefixes[r.Prefix+"-"] = r.Name
+		}
+	}
+
+	var dropped []beads.Route
+	for _, r := range m.Routes {
+		if name, ok := rigPrefixes[r.Prefix]; ok {
+			delete(rigPrefixes, r.Prefix)
+			if !dirExists(filepath.Join(townRoot, name)) {
+				dropped = append(dropped, r)
+				continue
+			}
+			set(beads.Route{Prefix: r.Prefix, Path: RigRoutePath(townRoot, name)})
+			continue
+		}
+		if _, ok := index[r.Prefix]; ok {
+			continue // the town's own route wins
+		}
+		if !dirExists(filepath.Join(townRoot, filepath.FromSlash(r.Path), constants.DirBeads)) {
+			dropped = append(dropped, r)
+			continue
+		}
+		set(r)
+	}
+	// Rigs the old town had no route for
+	for _, r := range m.Rigs {
+		if _, ok := rigPrefixes[r.Prefix+"-"]; ok && dirExists(filepath.Join(townRoot, r.Name)) {
+			set(beads.Route{Prefix: r.Prefix + "-", Path: RigRoutePath(townRoot, r.Name)})
+		}
+	}
+
+	return dropped, beads.WriteRoutes(beadsDir, routes)
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/auditchain"
 )
 
 // EventType represents the type of agent lifecycle event.
@@ -66,6 +68,11 @@ func logPath(townRoot string) string {
 	return filepath.Join(logDir(townRoot), "town.log")
 }
 
+// LogPath returns the path to a town's log file.
+func LogPath(townRoot string) string {
+	return logPath(townRoot)
+}
+
 // NewLogger creates a new Logger for the given town root.
 func NewLogger(townRoot string) *Logger {
 	return &Logger{
@@ -83,16 +90,12 @@ func (l *Logger) LogEvent(event Event) error {
 		return fmt.Errorf("creating log directory: %w", err)
 	}
 
-	// Open file for appending
-	f, err := os.OpenFile(l.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("opening log file: %w", err)
-	}
-	defer f.Close()
-
-	// Write human-readable log line
+	// Write human-readable log line, chained to the previous one (and
+	// signed when the town has an audit key)
+	townRoot := filepath.Dir(filepath.Dir(l.logPath))
+	key, _ := auditchain.LoadKey(townRoot)
 	line := formatLogLine(event)
-	if _, err := f.WriteString(line + "\n"); err != nil {
+	if err := auditchain.Append(townRoot, l.logPath, 0600, auditchain.Text, []byte(line), key); err != nil {
 		return fmt.Errorf("writing log line: %w", err)
 	}
 
@@ -248,7 +251,7 @@ func ParseLogLines(content string) ([]Event, error) {
 		if line == "" {
 			continue
 		}
-		event, err := parseLogLine(line)
+		event, err := parseLogLine(auditchain.StripText(line))
 		if err != nil {
 			continue // Skip malformed lines
 		}
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/auditchain"
 )
 
 func TestFormatLogLine(t *testing.T) {
@@ -164,6 +166,34 @@ func TestLoggerLogEvent(t *testing.T) {
 	}
 }
 
+func TestLoggerChainsLines(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := NewLogger(tmpDir)
+
+	for _, agent := range []string{"gastown/crew/max", "gastown/polecats/toast"} {
+		if err := logger.Log(EventSpawn, agent, "gt-xyz"); err != nil {
+			t.Fatalf("Log() error: %v", err)
+		}
+	}
+
+	f, err := os.Open(LogPath(tmpDir))
+	if err != nil {
+		t.Fatalf("opening log: %v", err)
+	}
+	defer f.Close()
+	if rep := auditchain.Verify("townlog", f, auditchain.Text, nil); !rep.OK() || rep.Entries != 2 {
+		t.Errorf("chain: entries %d, problems %+v", rep.Entries, rep.Problems)
+	}
+
+	events, err := ReadEvents(tmpDir)
+	if err != nil || len(events) != 2 {
+		t.Fatalf("ReadEvents = %d events, %v", len(events), err)
+	}
+	if events[1].Agent != "gastown/polecats/toast" {
+		t.Errorf("agent = %q, want gastown/polecats/toast", events[1].Agent)
+	}
+}
+
 func TestFilterEvents(t *testing.T) {
 	now := time.Now()
 	events := []Event{
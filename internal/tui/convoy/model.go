@@ -15,6 +15,7 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/forecast"
 )
 
 // convoyIDPattern validates convoy IDs to prevent SQL injection.
@@ -37,6 +38,7 @@ type ConvoyItem struct {
 	Status   string
 	Issues   []IssueItem
 	Progress string // e.g., "2/5"
+	ETA      string // e.g., "~6h", empty without a forecast
 	Expanded bool
 }
 
@@ -107,6 +109,9 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 		return nil, fmt.Errorf("parsing convoy list: %w", err)
 	}
 
+	// Forecasts are best-effort; without history convoys just have no ETA
+	model, _ := forecast.Load(filepath.Dir(townBeads))
+
 	convoys := make([]ConvoyItem, 0, len(rawConvoys))
 	for _, rc := range rawConvoys {
 		issues, completed, total := loadTrackedIssues(townBeads, rc.ID)
@@ -116,6 +121,7 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 			Status:   rc.Status,
 			Issues:   issues,
 			Progress: fmt.Sprintf("%d/%d", completed, total),
+			ETA:      forecastETA(model, rc.Status, issues),
 			Expanded: false,
 		})
 	}
@@ -123,6 +129,21 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 	return convoys, nil
 }
 
+// forecastETA returns the short ETA of an open convoy, or "".
+func forecastETA(model *forecast.Model, status string, issues []IssueItem) string {
+	if model == nil || status == "closed" {
+		return ""
+	}
+	items := make([]forecast.Item, 0, len(issues))
+	for _, issue := range issues {
+		items = append(items, forecast.Item{ID: issue.ID, Status: issue.Status})
+	}
+	if f := model.Forecast(items); f != nil {
+		return f.FormatShort(model.Now)
+	}
+	return ""
+}
+
 // loadTrackedIssues loads issues tracked by a convoy.
 func loadTrackedIssues(townBeads, convoyID string) ([]IssueItem, int, int) {
 	// Validate convoy ID to prevent SQL injection
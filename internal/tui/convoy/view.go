@@ -69,13 +69,17 @@ func (m Model) renderView() string {
 		}
 
 		statusIcon := statusToIcon(c.Status)
+		progress := c.Progress
+		if c.ETA != "" {
+			progress += ", ETA " + c.ETA
+		}
 		line := fmt.Sprintf("%s %d. %s %s: %s %s",
 			expandIcon,
 			ci+1,
 			statusIcon,
 			c.ID,
 			c.Title,
-			progressStyle.Render(fmt.Sprintf("(%s)", c.Progress)),
+			progressStyle.Render(fmt.Sprintf("(%s)", progress)),
 		)
 
 		if isSelected {
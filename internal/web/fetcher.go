@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/forecast"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -33,27 +35,13 @@ func NewLiveConvoyFetcher() (*LiveConvoyFetcher, error) {
 
 // FetchConvoys fetches all open convoys with their activity data.
 func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
-	// List all open convoy-type issues
-	listArgs := []string{"list", "--type=convoy", "--status=open", "--json"}
-	listCmd := exec.Command("bd", listArgs...)
-	listCmd.Dir = f.townBeads
-
-	var stdout bytes.Buffer
-	listCmd.Stdout = &stdout
-
-	if err := listCmd.Run(); err != nil {
-		return nil, fmt.Errorf("listing convoys: %w", err)
+	convoys, err := f.listOpenConvoys()
+	if err != nil {
+		return nil, err
 	}
 
-	var convoys []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		CreatedAt string `json:"created_at"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return nil, fmt.Errorf("parsing convoy list: %w", err)
-	}
+	// Throughput history for ETAs; forecasts are best-effort
+	model, _ := forecast.Load(filepath.Dir(f.townBeads))
 
 	// Build convoy rows with activity data
 	rows := make([]ConvoyRow, 0, len(convoys))
@@ -123,6 +111,19 @@ func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 		// Calculate work status based on progress and activity
 		row.WorkStatus = calculateWorkStatus(row.Completed, row.Total, row.LastActivity.ColorClass)
 
+		if model != nil {
+			items := make([]forecast.Item, len(tracked))
+			for i, t := range tracked {
+				items[i] = forecast.Item{ID: t.ID, Status: t.Status}
+			}
+			eta := model.Forecast(items)
+			if eta != nil {
+				row.ETA = eta.FormatShort(model.Now)
+			}
+			deadline := forecast.DeadlineFromDescription(c.Description)
+			row.Schedule = forecast.Schedule(eta, deadline, row.Total-row.Completed, model.Now)
+		}
+
 		// Get tracked issues for expandable view
 		row.TrackedIssues = make([]TrackedIssue, len(tracked))
 		for i, t := range tracked {
@@ -140,6 +141,51 @@ func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 	return rows, nil
 }
 
+// convoySummary is the subset of a convoy issue the dashboard needs.
+type convoySummary struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// listOpenConvoys lists open convoy-type issues, reading the town database
+// natively when possible.
+func (f *LiveConvoyFetcher) listOpenConvoys() ([]convoySummary, error) {
+	if snap, ok := beads.Native(f.townBeads); ok {
+		issues := snap.List(beads.ListOptions{IssueType: "convoy", Status: "open", Priority: -1})
+		convoys := make([]convoySummary, 0, len(issues))
+		for _, issue := range issues {
+			convoys = append(convoys, convoySummary{
+				ID:          issue.ID,
+				Title:       issue.Title,
+				Status:      issue.Status,
+				Description: issue.Description,
+				CreatedAt:   issue.CreatedAt,
+			})
+		}
+		return convoys, nil
+	}
+
+	listArgs := []string{"list", "--type=convoy", "--status=open", "--json"}
+	listCmd := exec.Command("bd", listArgs...)
+	listCmd.Dir = f.townBeads
+
+	var stdout bytes.Buffer
+	listCmd.Stdout = &stdout
+
+	if err := listCmd.Run(); err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
+	}
+
+	var convoys []convoySummary
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
+		return nil, fmt.Errorf("parsing convoy list: %w", err)
+	}
+	return convoys, nil
+}
+
 // trackedIssueInfo holds info about an issue being tracked by a convoy.
 type trackedIssueInfo struct {
 	ID           string
@@ -152,32 +198,11 @@ type trackedIssueInfo struct {
 
 // getTrackedIssues fetches tracked issues for a convoy.
 func (f *LiveConvoyFetcher) getTrackedIssues(convoyID string) []trackedIssueInfo {
-	dbPath := filepath.Join(f.townBeads, "beads.db")
-
-	// Query tracked dependencies from SQLite
-	safeConvoyID := strings.ReplaceAll(convoyID, "'", "''")
-	// #nosec G204 -- sqlite3 path is from trusted config, convoyID is escaped
-	queryCmd := exec.Command("sqlite3", "-json", dbPath,
-		fmt.Sprintf(`SELECT depends_on_id, type FROM dependencies WHERE issue_id = '%s' AND type = 'tracks'`, safeConvoyID))
-
-	var stdout bytes.Buffer
-	queryCmd.Stdout = &stdout
-	if err := queryCmd.Run(); err != nil {
-		return nil
-	}
-
-	var deps []struct {
-		DependsOnID string `json:"depends_on_id"`
-		Type        string `json:"type"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &deps); err != nil {
-		return nil
-	}
+	deps := f.getTrackedIDs(convoyID)
 
 	// Collect issue IDs (normalize external refs)
 	issueIDs := make([]string, 0, len(deps))
-	for _, dep := range deps {
-		issueID := dep.DependsOnID
+	for _, issueID := range deps {
 		if strings.HasPrefix(issueID, "external:") {
 			parts := strings.SplitN(issueID, ":", 3)
 			if len(parts) == 3 {
@@ -218,6 +243,42 @@ func (f *LiveConvoyFetcher) getTrackedIssues(convoyID string) []trackedIssueInfo
 	return result
 }
 
+// getTrackedIDs returns the raw depends_on_id of a convoy's "tracks"
+// dependencies, which may be external references.
+func (f *LiveConvoyFetcher) getTrackedIDs(convoyID string) []string {
+	if snap, ok := beads.Native(f.townBeads); ok {
+		return snap.DependsOn(convoyID, "tracks")
+	}
+
+	dbPath := filepath.Join(f.townBeads, "beads.db")
+
+	// Query tracked dependencies from SQLite
+	safeConvoyID := strings.ReplaceAll(convoyID, "'", "''")
+	// #nosec G204 -- sqlite3 path is from trusted config, convoyID is escaped
+	queryCmd := exec.Command("sqlite3", "-json", dbPath,
+		fmt.Sprintf(`SELECT depends_on_id, type FROM dependencies WHERE issue_id = '%s' AND type = 'tracks'`, safeConvoyID))
+
+	var stdout bytes.Buffer
+	queryCmd.Stdout = &stdout
+	if err := queryCmd.Run(); err != nil {
+		return nil
+	}
+
+	var deps []struct {
+		DependsOnID string `json:"depends_on_id"`
+		Type        string `json:"type"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &deps); err != nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		ids = append(ids, dep.DependsOnID)
+	}
+	return ids
+}
+
 // issueDetail holds basic issue info.
 type issueDetail struct {
 	ID        string
@@ -234,6 +295,33 @@ func (f *LiveConvoyFetcher) getIssueDetailsBatch(issueIDs []string) map[string]*
 		return result
 	}
 
+	// Tracked issues usually live in rig databases, so the natively read
+	// town database only answers for town-level beads; bd routes the rest.
+	if snap, ok := beads.Native(f.townBeads); ok {
+		var remote []string
+		for _, id := range issueIDs {
+			issue, ok := snap.Get(id)
+			if !ok {
+				remote = append(remote, id)
+				continue
+			}
+			detail := &issueDetail{
+				ID:       issue.ID,
+				Title:    issue.Title,
+				Status:   issue.Status,
+				Assignee: issue.Assignee,
+			}
+			if t, err := time.Parse(time.RFC3339, issue.UpdatedAt); err == nil {
+				detail.UpdatedAt = t
+			}
+			result[id] = detail
+		}
+		if len(remote) == 0 {
+			return result
+		}
+		issueIDs = remote
+	}
+
 	args := append([]string{"show"}, issueIDs...)
 	args = append(args, "--json")
 
@@ -49,6 +49,8 @@ type ConvoyRow struct {
 	Completed     int
 	Total         int
 	LastActivity  activity.Info
+	ETA           string // Forecast landing, e.g. "~6h"; empty without history
+	Schedule      string // Deadline status: "on track", "at risk", "slipping"
 	TrackedIssues []TrackedIssue
 }
 
@@ -67,6 +69,7 @@ func LoadTemplates() (*template.Template, error) {
 		"activityClass":   activityClass,
 		"statusClass":     statusClass,
 		"workStatusClass": workStatusClass,
+		"scheduleClass":   scheduleClass,
 		"progressPercent": progressPercent,
 	}
 
@@ -129,6 +132,20 @@ func workStatusClass(workStatus string) string {
 	}
 }
 
+// scheduleClass returns the CSS class for a convoy's deadline status.
+func scheduleClass(schedule string) string {
+	switch schedule {
+	case "on track":
+		return "schedule-on-track"
+	case "at risk":
+		return "schedule-at-risk"
+	case "slipping":
+		return "schedule-slipping"
+	default:
+		return ""
+	}
+}
+
 // progressPercent calculates percentage as an integer for progress bars.
 func progressPercent(completed, total int) int {
 	if total == 0 {
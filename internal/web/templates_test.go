@@ -175,6 +175,38 @@ func TestConvoyTemplate_ProgressDisplay(t *testing.T) {
 	}
 }
 
+func TestConvoyTemplate_ETADisplay(t *testing.T) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	data := ConvoyData{
+		Convoys: []ConvoyRow{
+			{ID: "hq-cv-late", Title: "Late", Status: "open", ETA: "~3d", Schedule: "slipping"},
+			{ID: "hq-cv-new", Title: "No history", Status: "open"},
+		},
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, "convoy.html", data)
+	if err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "~3d") {
+		t.Error("Template should display ETA '~3d'")
+	}
+	if !strings.Contains(output, `class="schedule-slipping"`) {
+		t.Error("Template should flag a slipping convoy")
+	}
+	if !strings.Contains(output, "—") {
+		t.Error("Template should show a placeholder for convoys without an ETA")
+	}
+}
+
 func TestConvoyTemplate_StatusIndicators(t *testing.T) {
 	tmpl, err := LoadTemplates()
 	if err != nil {
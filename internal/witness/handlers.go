@@ -12,7 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
-	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/ptyd"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -355,6 +355,61 @@ Please fix the issue and resubmit with 'gt done'.`,
 	return result
 }
 
+// HandleLimitExceeded processes a LIMIT_EXCEEDED message from the daemon.
+// Tells the worker that hit the limit what happened and how to respond;
+// breaches by the rig's own agents (witness, refinery) are only recorded.
+func HandleLimitExceeded(workDir, rigName string, msg *mail.Message, router *mail.Router) *HandlerResult {
+	result := &HandlerResult{
+		MessageID:    msg.ID,
+		ProtocolType: ProtoLimitExceeded,
+	}
+
+	payload, err := ParseLimitExceeded(msg.Subject, msg.Body)
+	if err != nil {
+		result.Error = fmt.Errorf("parsing LIMIT_EXCEEDED: %w", err)
+		return result
+	}
+
+	summary := fmt.Sprintf("%s limit (%s): %s", payload.Limit, payload.Max, payload.Usage)
+	if !strings.HasPrefix(payload.Agent, "polecats/") && !strings.HasPrefix(payload.Agent, "crew/") {
+		result.Handled = true
+		result.Action = fmt.Sprintf("recorded %s breach of %s", payload.Agent, summary)
+		return result
+	}
+
+	var advice string
+	switch payload.Limit {
+	case "memory":
+		advice = "Processes in your session were killed for running out of memory.\nRun fewer builds or tests in parallel, and check that your last command finished."
+	case "pids":
+		advice = "Your session hit its process limit, so new processes fail to start or are killed.\nStop background processes you no longer need."
+	case "wall_clock":
+		advice = "Your session has run longer than its time budget.\nWrap up: commit your work and run 'gt done', or 'gt handoff' if it needs more time."
+	default:
+		advice = "Check your session's resource usage."
+	}
+
+	notification := &mail.Message{
+		From:     fmt.Sprintf("%s/witness", rigName),
+		To:       fmt.Sprintf("%s/%s", rigName, payload.Agent),
+		Subject:  fmt.Sprintf("Resource limit exceeded: %s", payload.Limit),
+		Priority: mail.PriorityHigh,
+		Type:     mail.TypeTask,
+		Body:     fmt.Sprintf("Your session exceeded its %s.\n\n%s", summary, advice),
+	}
+
+	if err := router.Send(notification); err != nil {
+		result.Error = fmt.Errorf("sending limit notification: %w", err)
+		return result
+	}
+
+	result.Handled = true
+	result.MailSent = notification.ID
+	result.Action = fmt.Sprintf("notified %s of %s", payload.Agent, summary)
+
+	return result
+}
+
 // HandleSwarmStart processes a SWARM_START message from the Mayor.
 // Creates a swarm tracking wisp to monitor batch polecat work.
 func HandleSwarmStart(workDir string, msg *mail.Message) *HandlerResult {
@@ -653,7 +708,7 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	// session due to rig loading issues or race conditions with IsRunning checks.
 	// See: gt-g9ft5 - sessions were piling up because nuke wasn't killing them.
 	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
-	t := tmux.NewTmux()
+	t := ptyd.NewBackend()
 
 	// Check if session exists and kill it
 	if running, _ := t.HasSession(sessionName); running {
@@ -13,6 +13,8 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/limits"
+	"github.com/steveyegge/gastown/internal/ptyd"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -29,14 +31,17 @@ var (
 type Manager struct {
 	rig          *rig.Rig
 	workDir      string
+	backend      tmux.SessionBackend
 	stateManager *agent.StateManager[Witness]
 }
 
 // NewManager creates a new witness manager for a rig.
+// Sessions run on the backend selected by GT_SESSION_BACKEND (tmux by default).
 func NewManager(r *rig.Rig) *Manager {
 	return &Manager{
 		rig:     r,
 		workDir: r.Path,
+		backend: ptyd.NewBackend(),
 		stateManager: agent.NewStateManager[Witness](r.Path, "witness.json", func() *Witness {
 			return &Witness{
 				RigName: r.Name,
@@ -108,13 +113,13 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		return err
 	}
 
-	t := tmux.NewTmux()
+	t := m.backend
 	sessionID := m.SessionName()
 
 	if foreground {
 		// Foreground mode is deprecated - patrol logic moved to mol-witness-patrol
 		// Just check tmux session (no PID inference per ZFC)
-		if running, _ := t.HasSession(sessionID); running && t.IsClaudeRunning(sessionID) {
+		if running, _ := t.HasSession(sessionID); running && tmux.IsAgentAlive(t, sessionID) {
 			return ErrAlreadyRunning
 		}
 
@@ -131,7 +136,7 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	running, _ := t.HasSession(sessionID)
 	if running {
 		// Session exists - check if Claude is actually running (healthy vs zombie)
-		if t.IsClaudeRunning(sessionID) {
+		if tmux.IsAgentAlive(t, sessionID) {
 			// Healthy - Claude is running
 			return ErrAlreadyRunning
 		}
@@ -175,6 +180,11 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
+	// Apply the rig's resource limits (non-fatal: the session runs unlimited)
+	if _, err := limits.ApplyToSession(t, sessionID, m.rig.Path, "witness", "witness"); err != nil {
+		fmt.Printf("Warning: could not apply resource limits: %v\n", err)
+	}
+
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	envVars := config.AgentEnv(config.AgentEnvConfig{
@@ -197,8 +207,10 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	}
 
 	// Apply Gas Town theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "witness", "witness")
+	if tm, ok := tmux.AsTmux(t); ok {
+		theme := tmux.AssignTheme(m.rig.Name)
+		_ = tm.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "witness", "witness")
+	}
 
 	// Update state to running
 	now := time.Now()
@@ -290,7 +302,7 @@ func (m *Manager) Stop() error {
 	}
 
 	// Check if tmux session exists
-	t := tmux.NewTmux()
+	t := m.backend
 	sessionID := m.SessionName()
 	sessionRunning, _ := t.HasSession(sessionID)
 
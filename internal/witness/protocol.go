@@ -30,6 +30,9 @@ var (
 
 	// SWARM_START - mayor initiating batch work
 	PatternSwarmStart = regexp.MustCompile(`^SWARM_START`)
+
+	// LIMIT_EXCEEDED <agent> - daemon reporting a resource limit breach
+	PatternLimitExceeded = regexp.MustCompile(`^LIMIT_EXCEEDED\s+(\S+)`)
 )
 
 // ProtocolType identifies the type of protocol message.
@@ -43,6 +46,7 @@ const (
 	ProtoMergeFailed       ProtocolType = "merge_failed"
 	ProtoHandoff           ProtocolType = "handoff"
 	ProtoSwarmStart        ProtocolType = "swarm_start"
+	ProtoLimitExceeded     ProtocolType = "limit_exceeded"
 	ProtoUnknown           ProtocolType = "unknown"
 )
 
@@ -92,6 +96,16 @@ type SwarmStartPayload struct {
 	StartedAt time.Time
 }
 
+// LimitExceededPayload contains parsed data from a LIMIT_EXCEEDED message.
+type LimitExceededPayload struct {
+	Agent     string // address within the rig, e.g. polecats/toast
+	Role      string
+	Limit     string // "memory", "pids" or "wall_clock"
+	Usage     string
+	Max       string
+	Mechanism string // "cgroup" or "prlimit"
+}
+
 // ClassifyMessage determines the protocol type from a message subject.
 func ClassifyMessage(subject string) ProtocolType {
 	switch {
@@ -109,6 +123,8 @@ func ClassifyMessage(subject string) ProtocolType {
 		return ProtoHandoff
 	case PatternSwarmStart.MatchString(subject):
 		return ProtoSwarmStart
+	case PatternLimitExceeded.MatchString(subject):
+		return ProtoLimitExceeded
 	default:
 		return ProtoUnknown
 	}
@@ -281,6 +297,54 @@ func ParseSwarmStart(body string) (*SwarmStartPayload, error) {
 	return payload, nil
 }
 
+// ParseLimitExceeded extracts payload from a LIMIT_EXCEEDED message.
+// Subject format: LIMIT_EXCEEDED <agent>
+// Body format:
+//
+//	Role: <role>
+//	Limit: memory|pids|wall_clock
+//	Usage: <current usage>
+//	Max: <configured limit>
+//	Mechanism: cgroup|prlimit
+func ParseLimitExceeded(subject, body string) (*LimitExceededPayload, error) {
+	matches := PatternLimitExceeded.FindStringSubmatch(subject)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("invalid LIMIT_EXCEEDED subject: %s", subject)
+	}
+
+	payload := &LimitExceededPayload{
+		Agent: matches[1],
+	}
+
+	// Parse body for structured fields
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Role:"):
+			payload.Role = strings.TrimSpace(strings.TrimPrefix(line, "Role:"))
+		case strings.HasPrefix(line, "Limit:"):
+			payload.Limit = strings.TrimSpace(strings.TrimPrefix(line, "Limit:"))
+		case strings.HasPrefix(line, "Usage:"):
+			payload.Usage = strings.TrimSpace(strings.TrimPrefix(line, "Usage:"))
+		case strings.HasPrefix(line, "Max:"):
+			payload.Max = strings.TrimSpace(strings.TrimPrefix(line, "Max:"))
+		case strings.HasPrefix(line, "Mechanism:"):
+			payload.Mechanism = strings.TrimSpace(strings.TrimPrefix(line, "Mechanism:"))
+		}
+	}
+
+	return payload, nil
+}
+
+// FormatLimitExceeded builds the subject and body of a LIMIT_EXCEEDED
+// message, in the format ParseLimitExceeded reads.
+func FormatLimitExceeded(p *LimitExceededPayload) (subject, body string) {
+	subject = fmt.Sprintf("LIMIT_EXCEEDED %s", p.Agent)
+	body = fmt.Sprintf("Role: %s\nLimit: %s\nUsage: %s\nMax: %s\nMechanism: %s\n",
+		p.Role, p.Limit, p.Usage, p.Max, p.Mechanism)
+	return subject, body
+}
+
 // CleanupWispLabels generates labels for a cleanup wisp.
 func CleanupWispLabels(polecatName, state string) []string {
 	return []string{
@@ -21,6 +21,7 @@ func TestClassifyMessage(t *testing.T) {
 		{"🤝 HANDOFF: Patrol context", ProtoHandoff},
 		{"🤝HANDOFF: No space", ProtoHandoff},
 		{"SWARM_START", ProtoSwarmStart},
+		{"LIMIT_EXCEEDED polecats/nux", ProtoLimitExceeded},
 		{"Unknown message", ProtoUnknown},
 		{"", ProtoUnknown},
 	}
@@ -309,3 +310,30 @@ func TestAssessHelpRequest_BuildIssues(t *testing.T) {
 		t.Error("Should be able to help with build issues")
 	}
 }
+
+func TestFormatAndParseLimitExceeded(t *testing.T) {
+	want := &LimitExceededPayload{
+		Agent:     "polecats/nux",
+		Role:      "polecat",
+		Limit:     "memory",
+		Usage:     "2 OOM kill(s)",
+		Max:       "4G",
+		Mechanism: "cgroup",
+	}
+	subject, body := FormatLimitExceeded(want)
+	if subject != "LIMIT_EXCEEDED polecats/nux" {
+		t.Errorf("subject = %q", subject)
+	}
+
+	got, err := ParseLimitExceeded(subject, body)
+	if err != nil {
+		t.Fatalf("ParseLimitExceeded() error = %v", err)
+	}
+	if *got != *want {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+
+	if _, err := ParseLimitExceeded("MERGED nux", body); err == nil {
+		t.Error("expected error for non-LIMIT_EXCEEDED subject")
+	}
+}